@@ -0,0 +1,222 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package devnet
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"text/template"
+)
+
+const (
+	chainmakerYmlName = "chainmaker.yml"
+	logYmlName        = "log.yml"
+	chainConfYmlName  = "bc1.yml"
+)
+
+// nodeConfData values rendered into the local config of the node
+type nodeConfData struct {
+	Dir      string
+	ChainId  string
+	Node     *Org
+	RpcPort  int
+	LogLevel string
+}
+
+// chainConfData values rendered into the genesis chain config
+type chainConfData struct {
+	ChainId       string
+	ConsensusType int32
+	BlockInterval int64
+	TxCapacity    int
+	Node          *Org
+	Orgs          []*Org
+}
+
+const chainmakerYmlTpl = `auth_type: "permissionedWithCert"
+log:
+  config_file: {{.Dir}}/log.yml
+crypto_engine: tjfoc
+blockchain:
+  - chainId: {{.ChainId}}
+    genesis: {{.Dir}}/bc1.yml
+node:
+  type: full
+  org_id: {{.Node.OrgId}}
+  priv_key_file: {{.Node.Consensus.SignKeyFile}}
+  cert_file: {{.Node.Consensus.SignCrtFile}}
+  cert_cache_size: 1000
+  cert_key_usage_check: true
+net:
+  provider: LibP2P
+  listen_addr: /ip4/127.0.0.1/tcp/0
+  tls:
+    enabled: true
+    priv_key_file: {{.Node.Consensus.TLSKeyFile}}
+    cert_file: {{.Node.Consensus.TLSCrtFile}}
+txpool:
+  pool_type: "normal"
+  max_txpool_size: 50000
+  max_config_txpool_size: 10
+  is_dump_txs_in_queue: false
+  common_queue_num: 8
+  batch_max_size: 100
+  batch_create_timeout: 50
+rpc:
+  provider: grpc
+  host: 127.0.0.1
+  port: {{.RpcPort}}
+  check_chain_conf_trust_roots_change_interval: 60
+  ratelimit:
+    enabled: false
+  subscriber:
+    ratelimit:
+      token_per_second: 100
+      token_bucket_size: 100
+  tls:
+    mode: disable
+    priv_key_file: {{.Node.Consensus.TLSKeyFile}}
+    cert_file: {{.Node.Consensus.TLSCrtFile}}
+monitor:
+  enabled: false
+pprof:
+  enabled: false
+storage:
+  store_path: {{.Dir}}/data/ledgerData
+  blockdb_config:
+    provider: leveldb
+    leveldb_config:
+      store_path: {{.Dir}}/data/blocks
+  statedb_config:
+    provider: leveldb
+    leveldb_config:
+      store_path: {{.Dir}}/data/statedb
+  historydb_config:
+    provider: leveldb
+    leveldb_config:
+      store_path: {{.Dir}}/data/history
+  resultdb_config:
+    provider: leveldb
+    leveldb_config:
+      store_path: {{.Dir}}/data/result
+  disable_contract_eventdb: true
+debug:
+  is_cli_open: false
+  is_http_open: false
+vm:
+  go:
+    enable: false
+`
+
+const logYmlTpl = `log:
+  system:
+    log_level_default: {{.LogLevel}}
+    file_path: {{.Dir}}/log/system.log
+    log_by_chain: false
+    max_age: 1
+    rotation_time: 1
+    rotation_size: 100
+    log_in_console: false
+    show_color: false
+  brief:
+    log_level_default: {{.LogLevel}}
+    file_path: {{.Dir}}/log/brief.log
+    max_age: 1
+    rotation_time: 1
+    rotation_size: 100
+    log_in_console: false
+    show_color: false
+  event:
+    log_level_default: {{.LogLevel}}
+    file_path: {{.Dir}}/log/event.log
+    max_age: 1
+    rotation_time: 1
+    rotation_size: 100
+    log_in_console: false
+    show_color: false
+`
+
+const chainConfYmlTpl = `chain_id: {{.ChainId}}
+version: "2030500"
+sequence: 0
+auth_type: "permissionedWithCert"
+crypto:
+  hash: SHA256
+contract:
+  enable_sql_support: false
+vm:
+  addr_type: 2
+  support_list:
+    - "wasmer"
+    - "gasm"
+    - "evm"
+block:
+  tx_timestamp_verify: true
+  tx_timeout: 600
+  block_tx_capacity: {{.TxCapacity}}
+  block_size: 10
+  block_interval: {{.BlockInterval}}
+core:
+  tx_scheduler_timeout: 10
+  tx_scheduler_validate_timeout: 10
+  enable_sender_group: false
+  enable_conflicts_bit_window: true
+account_config:
+  enable_gas: false
+  gas_count: 0
+  default_gas: 100
+consensus:
+  type: {{.ConsensusType}}
+  nodes:
+    - org_id: "{{.Node.OrgId}}"
+      node_id:
+        - "{{.Node.NodeId}}"
+trust_roots:
+{{- range .Orgs}}
+  - org_id: "{{.OrgId}}"
+    root:
+      - "{{.CACrtFile}}"
+{{- end}}
+resource_policies:
+  - resource_name: CHAIN_CONFIG-NODE_ID_UPDATE
+    policy:
+      rule: SELF
+      org_list:
+      role_list:
+        - admin
+  - resource_name: CHAIN_CONFIG-TRUST_ROOT_ADD
+    policy:
+      rule: MAJORITY
+      org_list:
+      role_list:
+        - admin
+  - resource_name: CHAIN_CONFIG-CERTS_FREEZE
+    policy:
+      rule: ANY
+      org_list:
+      role_list:
+        - admin
+`
+
+// renderFile render the template into the file named name under dir
+func renderFile(dir, name, tpl string, data interface{}) (string, error) {
+	t, err := template.New(name).Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	if err = ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
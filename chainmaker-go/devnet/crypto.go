@@ -0,0 +1,171 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package devnet
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"chainmaker.org/chainmaker/common/v2/cert"
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	"chainmaker.org/chainmaker/common/v2/helper"
+)
+
+const (
+	certExpireYear = 1
+
+	roleConsensus = "consensus"
+	roleAdmin     = "admin"
+	roleClient    = "client"
+)
+
+var (
+	signKeyUsages = []x509.KeyUsage{x509.KeyUsageDigitalSignature, x509.KeyUsageContentCommitment}
+	tlsKeyUsages  = []x509.KeyUsage{
+		x509.KeyUsageKeyEncipherment,
+		x509.KeyUsageDataEncipherment,
+		x509.KeyUsageKeyAgreement,
+		x509.KeyUsageDigitalSignature,
+		x509.KeyUsageContentCommitment,
+	}
+	tlsSans = []string{"chainmaker.org", "localhost", "127.0.0.1"}
+)
+
+// Identity key and cert files of one member, laid out as chainmaker-cryptogen does
+type Identity struct {
+	SignKeyFile string
+	SignCrtFile string
+	TLSKeyFile  string
+	TLSCrtFile  string
+}
+
+// Org crypto material of one organization
+type Org struct {
+	OrgId     string
+	CACrtFile string
+	// Consensus node of the org, only the node of the first org is started
+	Consensus *Identity
+	// NodeId libp2p peer id of the consensus node
+	NodeId string
+	Admin  *Identity
+	Client *Identity
+}
+
+// generateOrg generate ca, consensus node, admin and client of org under dir
+func generateOrg(dir, orgId string) (*Org, error) {
+	orgDir := filepath.Join(dir, orgId)
+	caDir := filepath.Join(orgDir, "ca")
+	org := &Org{
+		OrgId:     orgId,
+		CACrtFile: filepath.Join(caDir, "ca.crt"),
+	}
+
+	caKey, err := cert.CreatePrivKey(crypto.ECC_NISTP256, caDir, "ca.key", false)
+	if err != nil {
+		return nil, fmt.Errorf("create ca key of %s failed, %s", orgId, err)
+	}
+	if err = cert.CreateCACertificate(&cert.CACertificateConfig{
+		PrivKey:            caKey,
+		HashType:           crypto.HASH_TYPE_SHA256,
+		CertPath:           caDir,
+		CertFileName:       "ca.crt",
+		Country:            "CN",
+		Locality:           "Beijing",
+		Province:           "Beijing",
+		OrganizationalUnit: "root-cert",
+		Organization:       orgId,
+		CommonName:         "ca." + orgId,
+		ExpireYear:         certExpireYear,
+		Sans:               []string{"ca." + orgId},
+	}); err != nil {
+		return nil, fmt.Errorf("create ca cert of %s failed, %s", orgId, err)
+	}
+
+	if org.Consensus, err = generateIdentity(orgDir, "node", roleConsensus+"1", orgId, roleConsensus); err != nil {
+		return nil, err
+	}
+	if org.Admin, err = generateIdentity(orgDir, "user", roleAdmin+"1", orgId, roleAdmin); err != nil {
+		return nil, err
+	}
+	if org.Client, err = generateIdentity(orgDir, "user", roleClient+"1", orgId, roleClient); err != nil {
+		return nil, err
+	}
+
+	tlsCrt, err := ioutil.ReadFile(org.Consensus.TLSCrtFile)
+	if err != nil {
+		return nil, err
+	}
+	if org.NodeId, err = helper.GetLibp2pPeerIdFromCert(tlsCrt); err != nil {
+		return nil, fmt.Errorf("get node id of %s failed, %s", orgId, err)
+	}
+	return org, nil
+}
+
+// generateIdentity issue the sign and tls pairs of member by the ca of org
+func generateIdentity(orgDir, kind, name, orgId, role string) (*Identity, error) {
+	dir := filepath.Join(orgDir, kind, name)
+	id := &Identity{
+		SignKeyFile: filepath.Join(dir, name+".sign.key"),
+		SignCrtFile: filepath.Join(dir, name+".sign.crt"),
+		TLSKeyFile:  filepath.Join(dir, name+".tls.key"),
+		TLSCrtFile:  filepath.Join(dir, name+".tls.crt"),
+	}
+	if err := issuePair(orgDir, dir, name+".sign", fmt.Sprintf("%s.sign.%s", name, orgId),
+		orgId, role, nil, signKeyUsages, nil, false); err != nil {
+		return nil, fmt.Errorf("issue sign cert of %s.%s failed, %s", name, orgId, err)
+	}
+	extKeyUsages := []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	if role == roleConsensus {
+		extKeyUsages = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	// don't set ou for tls certificate
+	if err := issuePair(orgDir, dir, name+".tls", fmt.Sprintf("%s.tls.%s", name, orgId),
+		orgId, "", tlsSans, tlsKeyUsages, extKeyUsages, true); err != nil {
+		return nil, fmt.Errorf("issue tls cert of %s.%s failed, %s", name, orgId, err)
+	}
+	return id, nil
+}
+
+func issuePair(orgDir, dir, name, cn, org, ou string, sans []string,
+	keyUsages []x509.KeyUsage, extKeyUsages []x509.ExtKeyUsage, isTls bool) error {
+	csrName := name + ".csr"
+	defer os.Remove(filepath.Join(dir, csrName))
+
+	privKey, err := cert.CreatePrivKey(crypto.ECC_NISTP256, dir, name+".key", isTls)
+	if err != nil {
+		return err
+	}
+	if err = cert.CreateCSR(&cert.CSRConfig{
+		PrivKey:            privKey,
+		CsrPath:            dir,
+		CsrFileName:        csrName,
+		Country:            "CN",
+		Locality:           "Beijing",
+		Province:           "Beijing",
+		OrganizationalUnit: ou,
+		Organization:       org,
+		CommonName:         cn,
+	}); err != nil {
+		return err
+	}
+	return cert.IssueCertificate(&cert.IssueCertificateConfig{
+		HashType:              crypto.HASH_TYPE_SHA256,
+		IssuerPrivKeyFilePath: filepath.Join(orgDir, "ca", "ca.key"),
+		IssuerCertFilePath:    filepath.Join(orgDir, "ca", "ca.crt"),
+		CsrFilePath:           filepath.Join(dir, csrName),
+		CertPath:              dir,
+		CertFileName:          name + ".crt",
+		ExpireYear:            certExpireYear,
+		Sans:                  sans,
+		KeyUsages:             keyUsages,
+		ExtKeyUsages:          extKeyUsages,
+	})
+}
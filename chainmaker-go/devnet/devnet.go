@@ -0,0 +1,447 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package devnet boots a single node chain inside the current process for integration tests,
+// with generated crypto material, a net without sockets, temp dir storage and fast blocks.
+// The returned sdk-go clients talk to the node over a loopback grpc port, nothing leaves the
+// machine.
+//
+// Several consensus nodes in one process are not supported: localconf, the rpc dispatcher and
+// the module registries are process wide, and the consensus, net and store modules outside
+// this repo read node settings such as the node key and the store path from localconf, so two
+// nodes in a process would share them. A process runs one ChainMakerServer
+// at a time and the chain has one consensus node. The node belongs to the first org, the
+// other orgs join the chain as trust roots with their own admins and clients, which is enough
+// to test multi-org endorsement policies, but not consensus among nodes, use the scripts
+// starting several node processes for that.
+//
+//	n, err := devnet.Start(devnet.WithOrgCount(2), devnet.WithConsensus(consensus.ConsensusType_TBFT))
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer n.Stop()
+//	client, err := n.Client(0)
+package devnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker-go/module/blockchain"
+	"chainmaker.org/chainmaker-go/module/consensus"
+	"chainmaker.org/chainmaker-go/module/rpcserver"
+	"chainmaker.org/chainmaker-go/module/txpool"
+	"chainmaker.org/chainmaker-go/module/vm"
+	solo "chainmaker.org/chainmaker/consensus-solo/v2"
+	tbft "chainmaker.org/chainmaker/consensus-tbft/v2"
+	consensusUtils "chainmaker.org/chainmaker/consensus-utils/v2"
+	"chainmaker.org/chainmaker/localconf/v2"
+	"chainmaker.org/chainmaker/logger/v2"
+	consensusPb "chainmaker.org/chainmaker/pb-go/v2/consensus"
+	"chainmaker.org/chainmaker/protocol/v2"
+	sdk "chainmaker.org/chainmaker/sdk-go/v2"
+	normal "chainmaker.org/chainmaker/txpool-normal/v2"
+	evm "chainmaker.org/chainmaker/vm-evm/v2"
+	gasm "chainmaker.org/chainmaker/vm-gasm/v2"
+	wasmer "chainmaker.org/chainmaker/vm-wasmer/v2"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultChainId chain id of devnet
+	DefaultChainId = "chain1"
+	// DefaultOrgCount orgs created by default
+	DefaultOrgCount = 4
+	// DefaultBlockInterval block interval in milliseconds
+	DefaultBlockInterval = 10
+	// DefaultReadyTimeout how long Start waits for the chain to answer
+	DefaultReadyTimeout = 30 * time.Second
+
+	orgIdTemplate = "wx-org%d.chainmaker.org"
+)
+
+var (
+	log = logger.GetLogger("[DevNet]")
+
+	// running guards the process wide state, only one devnet runs at a time
+	running     bool
+	runningLock sync.Mutex
+	registry    sync.Once
+)
+
+// Config of devnet
+type Config struct {
+	ChainId       string
+	OrgCount      int
+	Consensus     consensusPb.ConsensusType
+	BlockInterval int64
+	TxCapacity    int
+	ReadyTimeout  time.Duration
+	// Dir root of crypto material, configs, ledger and logs, a temp dir removed on Stop when empty
+	Dir      string
+	LogLevel string
+}
+
+// Option apply option to config
+type Option func(*Config)
+
+// WithChainId set chain id
+func WithChainId(chainId string) Option {
+	return func(c *Config) {
+		c.ChainId = chainId
+	}
+}
+
+// WithOrgCount set how many orgs are trusted by the chain, the node belongs to the first one
+func WithOrgCount(count int) Option {
+	return func(c *Config) {
+		c.OrgCount = count
+	}
+}
+
+// WithConsensus set consensus type, SOLO and TBFT are supported
+func WithConsensus(consensusType consensusPb.ConsensusType) Option {
+	return func(c *Config) {
+		c.Consensus = consensusType
+	}
+}
+
+// WithBlockInterval set block interval in milliseconds
+func WithBlockInterval(interval int64) Option {
+	return func(c *Config) {
+		c.BlockInterval = interval
+	}
+}
+
+// WithTxCapacity set max txs in a block
+func WithTxCapacity(capacity int) Option {
+	return func(c *Config) {
+		c.TxCapacity = capacity
+	}
+}
+
+// WithReadyTimeout set how long Start waits for the chain to answer
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.ReadyTimeout = timeout
+	}
+}
+
+// WithDir keep crypto material, configs, ledger and logs in dir, it is not removed on Stop
+func WithDir(dir string) Option {
+	return func(c *Config) {
+		c.Dir = dir
+	}
+}
+
+// WithLogLevel set level of the node logs written under dir/log
+func WithLogLevel(level string) Option {
+	return func(c *Config) {
+		c.LogLevel = level
+	}
+}
+
+// Network a running devnet
+type Network struct {
+	config  *Config
+	dir     string
+	tempDir bool
+	orgs    []*Org
+	rpcPort int
+	// listener of rpc port, handed over to the rpc server when it starts
+	listener net.Listener
+
+	server    *blockchain.ChainMakerServer
+	rpcServer *rpcserver.RPCServer
+
+	lock    sync.Mutex
+	clients []*sdk.ChainClient
+	stopped bool
+}
+
+// Start boot the devnet and wait until the chain answers, it fails if another devnet is running
+func Start(opts ...Option) (*Network, error) {
+	config := &Config{
+		ChainId:       DefaultChainId,
+		OrgCount:      DefaultOrgCount,
+		Consensus:     consensusPb.ConsensusType_SOLO,
+		BlockInterval: DefaultBlockInterval,
+		TxCapacity:    1000,
+		ReadyTimeout:  DefaultReadyTimeout,
+		LogLevel:      "ERROR",
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.OrgCount < 1 {
+		return nil, errors.New("devnet needs at least one org")
+	}
+	if config.Consensus != consensusPb.ConsensusType_SOLO && config.Consensus != consensusPb.ConsensusType_TBFT {
+		return nil, fmt.Errorf("consensus %s is not supported by devnet", config.Consensus)
+	}
+
+	runningLock.Lock()
+	defer runningLock.Unlock()
+	if running {
+		return nil, errors.New("another devnet is running in this process")
+	}
+	registry.Do(registerProviders)
+
+	n := &Network{config: config, dir: config.Dir}
+	if err := n.prepare(); err != nil {
+		n.shutdown()
+		return nil, err
+	}
+	if err := n.boot(); err != nil {
+		n.shutdown()
+		return nil, err
+	}
+	running = true
+	log.Infof("devnet of chain[%s] is ready, rpc port %d", config.ChainId, n.rpcPort)
+	return n, nil
+}
+
+// prepare generate crypto material and render configs
+func (n *Network) prepare() error {
+	var err error
+	if n.dir == "" {
+		if n.dir, err = ioutil.TempDir("", "devnet"); err != nil {
+			return err
+		}
+		n.tempDir = true
+	}
+
+	for i := 1; i <= n.config.OrgCount; i++ {
+		org, err2 := generateOrg(n.dir, fmt.Sprintf(orgIdTemplate, i))
+		if err2 != nil {
+			return err2
+		}
+		n.orgs = append(n.orgs, org)
+	}
+
+	// the port is kept bound until the rpc server takes the listener, so nobody else can take it
+	if n.listener, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		return err
+	}
+	n.rpcPort = n.listener.Addr().(*net.TCPAddr).Port
+	nodeData := &nodeConfData{
+		Dir:      n.dir,
+		ChainId:  n.config.ChainId,
+		Node:     n.orgs[0],
+		RpcPort:  n.rpcPort,
+		LogLevel: n.config.LogLevel,
+	}
+	if _, err = renderFile(n.dir, logYmlName, logYmlTpl, nodeData); err != nil {
+		return err
+	}
+	if _, err = renderFile(n.dir, chainConfYmlName, chainConfYmlTpl, &chainConfData{
+		ChainId:       n.config.ChainId,
+		ConsensusType: int32(n.config.Consensus),
+		BlockInterval: n.config.BlockInterval,
+		TxCapacity:    n.config.TxCapacity,
+		Node:          n.orgs[0],
+		Orgs:          n.orgs,
+	}); err != nil {
+		return err
+	}
+	confPath, err := renderFile(n.dir, chainmakerYmlName, chainmakerYmlTpl, nodeData)
+	if err != nil {
+		return err
+	}
+
+	localconf.ConfigFilepath = confPath
+	return localconf.InitLocalConfig(&cobra.Command{})
+}
+
+// boot start the node and the rpc server, then wait for the chain
+func (n *Network) boot() error {
+	server := blockchain.NewChainMakerServerWithNet(newLocalNet(n.orgs[0].NodeId))
+	if err := server.Init(); err != nil {
+		return fmt.Errorf("init chainmaker server failed, %s", err)
+	}
+	rpcServer, err := rpcserver.NewRPCServer(server)
+	if err != nil {
+		return fmt.Errorf("init rpc server failed, %s", err)
+	}
+	if err = server.Start(); err != nil {
+		return fmt.Errorf("start chainmaker server failed, %s", err)
+	}
+	// only started servers are stopped on failure
+	n.server = server
+	if err = rpcServer.StartWithListener(n.listener); err != nil {
+		return fmt.Errorf("start rpc server failed, %s", err)
+	}
+	n.listener = nil
+	n.rpcServer = rpcServer
+
+	client, err := n.Client(0)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), n.config.ReadyTimeout)
+	defer cancel()
+	for {
+		if _, err = client.GetCurrentBlockHeight(); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("chain is not ready in %s, %s", n.config.ReadyTimeout, err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// ChainId of devnet
+func (n *Network) ChainId() string {
+	return n.config.ChainId
+}
+
+// Dir root of crypto material, configs, ledger and logs
+func (n *Network) Dir() string {
+	return n.dir
+}
+
+// RpcAddr grpc address of the node
+func (n *Network) RpcAddr() string {
+	return fmt.Sprintf("127.0.0.1:%d", n.rpcPort)
+}
+
+// Orgs crypto material of the orgs, the node belongs to the first one
+func (n *Network) Orgs() []*Org {
+	return n.orgs
+}
+
+// Client return a client of the i-th org signing as its client member, stopped on Stop
+func (n *Network) Client(i int) (*sdk.ChainClient, error) {
+	if i < 0 || i >= len(n.orgs) {
+		return nil, fmt.Errorf("org index %d out of range", i)
+	}
+	return n.NewClient(n.orgs[i].OrgId, n.orgs[i].Client)
+}
+
+// Admin return a client of the i-th org signing as its admin member, stopped on Stop
+func (n *Network) Admin(i int) (*sdk.ChainClient, error) {
+	if i < 0 || i >= len(n.orgs) {
+		return nil, fmt.Errorf("org index %d out of range", i)
+	}
+	return n.NewClient(n.orgs[i].OrgId, n.orgs[i].Admin)
+}
+
+// NewClient create a client of org signing as the given identity, stopped on Stop
+func (n *Network) NewClient(orgId string, identity *Identity, opts ...sdk.ChainClientOption) (*sdk.ChainClient, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.stopped {
+		return nil, errors.New("devnet is stopped")
+	}
+	nodeConfig := sdk.NewNodeConfig(
+		sdk.WithNodeAddr(n.RpcAddr()),
+		sdk.WithNodeConnCnt(1),
+		sdk.WithNodeUseTLS(false),
+	)
+	opts = append([]sdk.ChainClientOption{
+		sdk.WithChainClientOrgId(orgId),
+		sdk.WithChainClientChainId(n.config.ChainId),
+		sdk.WithUserKeyFilePath(identity.TLSKeyFile),
+		sdk.WithUserCrtFilePath(identity.TLSCrtFile),
+		sdk.WithUserSignKeyFilePath(identity.SignKeyFile),
+		sdk.WithUserSignCrtFilePath(identity.SignCrtFile),
+		sdk.AddChainClientNodeConfig(nodeConfig),
+	}, opts...)
+	client, err := sdk.NewChainClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("new chain client of %s failed, %s", orgId, err)
+	}
+	n.clients = append(n.clients, client)
+	return client, nil
+}
+
+// Stop the clients, the rpc server and the node, the temp dir is removed
+func (n *Network) Stop() {
+	n.lock.Lock()
+	if n.stopped {
+		n.lock.Unlock()
+		return
+	}
+	n.stopped = true
+	n.lock.Unlock()
+
+	runningLock.Lock()
+	defer runningLock.Unlock()
+	n.shutdown()
+	running = false
+}
+
+func (n *Network) shutdown() {
+	for _, client := range n.clients {
+		if err := client.Stop(); err != nil {
+			log.Warnf("stop chain client failed, %s", err)
+		}
+	}
+	n.clients = nil
+	if n.listener != nil {
+		if err := n.listener.Close(); err != nil {
+			log.Warnf("close rpc listener failed, %s", err)
+		}
+	}
+	if n.rpcServer != nil {
+		n.rpcServer.Stop()
+	}
+	if n.server != nil {
+		n.server.Stop()
+	}
+	n.removeDir()
+}
+
+func (n *Network) removeDir() {
+	if n.tempDir {
+		if err := os.RemoveAll(n.dir); err != nil {
+			log.Warnf("remove dir %s failed, %s", n.dir, err)
+		}
+	}
+}
+
+// registerProviders register the components the chainmaker binary registers in main
+func registerProviders() {
+	txpool.RegisterTxPoolProvider(normal.TxPoolType, normal.NewNormalPool)
+
+	vm.RegisterVmProvider(
+		"GASM",
+		func(chainId string, configs map[string]interface{}) (protocol.VmInstancesManager, error) {
+			return &gasm.InstancesManager{}, nil
+		})
+	vm.RegisterVmProvider(
+		"WASMER",
+		func(chainId string, configs map[string]interface{}) (protocol.VmInstancesManager, error) {
+			return wasmer.NewInstancesManager(chainId), nil
+		})
+	vm.RegisterVmProvider(
+		"EVM",
+		func(chainId string, configs map[string]interface{}) (protocol.VmInstancesManager, error) {
+			return &evm.InstancesManager{}, nil
+		})
+
+	consensus.RegisterConsensusProvider(
+		consensusPb.ConsensusType_SOLO,
+		func(config *consensusUtils.ConsensusImplConfig) (protocol.ConsensusEngine, error) {
+			return solo.New(config)
+		},
+	)
+	consensus.RegisterConsensusProvider(
+		consensusPb.ConsensusType_TBFT,
+		func(config *consensusUtils.ConsensusImplConfig) (protocol.ConsensusEngine, error) {
+			return tbft.New(config)
+		},
+	)
+}
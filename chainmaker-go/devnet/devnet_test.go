@@ -0,0 +1,76 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package devnet
+
+import (
+	"testing"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	consensusPb "chainmaker.org/chainmaker/pb-go/v2/consensus"
+	sdk "chainmaker.org/chainmaker/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStart(t *testing.T) {
+	if testing.Short() {
+		t.Skip("devnet boots a whole node")
+	}
+	n, err := Start(WithOrgCount(2), WithConsensus(consensusPb.ConsensusType_SOLO))
+	require.Nil(t, err)
+	defer n.Stop()
+
+	_, err = Start()
+	require.NotNil(t, err)
+
+	client, err := n.Client(1)
+	require.Nil(t, err)
+	chainConfig, err := client.GetChainConfig()
+	require.Nil(t, err)
+	require.Equal(t, DefaultChainId, chainConfig.ChainId)
+	require.Len(t, chainConfig.TrustRoots, 2)
+	require.Equal(t, n.Orgs()[0].NodeId, chainConfig.Consensus.Nodes[0].NodeId[0])
+
+	height, err := client.GetCurrentBlockHeight()
+	require.Nil(t, err)
+
+	// block update needs the endorsements of the majority admins, both orgs here
+	admin1, err := n.Admin(0)
+	require.Nil(t, err)
+	admin2, err := n.Admin(1)
+	require.Nil(t, err)
+	updateBlock := func(admins ...*sdk.ChainClient) (*common.TxResponse, error) {
+		payload, err2 := admin1.CreateChainConfigBlockUpdatePayload(true, true, 600, 10, 100, 10, 20, 0)
+		require.Nil(t, err2)
+		endorsements := make([]*common.EndorsementEntry, 0, len(admins))
+		for _, admin := range admins {
+			endorsement, err3 := admin.SignChainConfigPayload(payload)
+			require.Nil(t, err3)
+			endorsements = append(endorsements, endorsement)
+		}
+		return admin1.SendChainConfigUpdateRequest(payload, endorsements, -1, true)
+	}
+	resp, err := updateBlock(admin1)
+	require.True(t, err != nil || resp.Code != common.TxStatusCode_SUCCESS)
+	resp, err = updateBlock(admin1, admin2)
+	require.Nil(t, err)
+	require.Equal(t, common.TxStatusCode_SUCCESS, resp.Code, resp.Message)
+
+	newHeight, err := client.GetCurrentBlockHeight()
+	require.Nil(t, err)
+	require.Greater(t, newHeight, height)
+
+	_, err = n.Client(2)
+	require.NotNil(t, err)
+}
+
+func TestStartOptions(t *testing.T) {
+	_, err := Start(WithOrgCount(0))
+	require.NotNil(t, err)
+	_, err = Start(WithConsensus(consensusPb.ConsensusType_RAFT))
+	require.NotNil(t, err)
+}
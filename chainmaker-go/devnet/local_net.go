@@ -0,0 +1,141 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package devnet
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"chainmaker.org/chainmaker/protocol/v2"
+)
+
+var errNetNotRunning = errors.New("local net is not running")
+
+// localNet implements protocol.Net for the only node of devnet without opening sockets. The node has
+// no peers, so broadcasts reach nobody, direct messages fail and the registered handlers are never
+// called, which is what consensus and sync expect of a node alone in its chain.
+type localNet struct {
+	nodeUid string
+
+	lock    sync.RWMutex
+	running bool
+}
+
+// newLocalNet create the net of the node with the given uid, the uid must be the libp2p peer id
+// derived from the tls key of the node, because ChainMakerServer computes its node id that way
+func newLocalNet(nodeUid string) *localNet {
+	return &localNet{nodeUid: nodeUid}
+}
+
+// GetNodeUid is the unique id of node.
+func (n *localNet) GetNodeUid() string {
+	return n.nodeUid
+}
+
+// InitPubSub is no-op, there is nobody to publish to.
+func (n *localNet) InitPubSub(_ string, _ int) error {
+	return nil
+}
+
+// StopPubSub is no-op, there is nobody to publish to.
+func (n *localNet) StopPubSub(_ string) {}
+
+// BroadcastWithChainId drop the msg, the node has no peers.
+func (n *localNet) BroadcastWithChainId(_ string, _ string, _ []byte) error {
+	if !n.IsRunning() {
+		return errNetNotRunning
+	}
+	return nil
+}
+
+// SubscribeWithChainId is no-op, no peer publishes to the node.
+func (n *localNet) SubscribeWithChainId(_ string, _ string, _ protocol.PubSubMsgHandler) error {
+	return nil
+}
+
+// CancelSubscribeWithChainId is no-op, no peer publishes to the node.
+func (n *localNet) CancelSubscribeWithChainId(_ string, _ string) error {
+	return nil
+}
+
+// SendMsg always fails, the node has no peers.
+func (n *localNet) SendMsg(_ string, node string, _ string, _ []byte) error {
+	if !n.IsRunning() {
+		return errNetNotRunning
+	}
+	return fmt.Errorf("node [%s] is not connected", node)
+}
+
+// DirectMsgHandle is no-op, no peer sends to the node.
+func (n *localNet) DirectMsgHandle(_ string, _ string, _ protocol.DirectMsgHandler) error {
+	return nil
+}
+
+// CancelDirectMsgHandle is no-op, no peer sends to the node.
+func (n *localNet) CancelDirectMsgHandle(_ string, _ string) error {
+	return nil
+}
+
+// AddSeed is no-op, the node has no peers.
+func (n *localNet) AddSeed(_ string) error {
+	return nil
+}
+
+// RefreshSeeds is no-op, the node has no peers.
+func (n *localNet) RefreshSeeds(_ []string) error {
+	return nil
+}
+
+// SetChainCustomTrustRoots is no-op, there are no peers to verify.
+func (n *localNet) SetChainCustomTrustRoots(_ string, _ [][]byte) {}
+
+// ReVerifyPeers is no-op, there are no peers to verify.
+func (n *localNet) ReVerifyPeers(_ string) {}
+
+// AddAC is no-op, there are no peers to verify.
+func (n *localNet) AddAC(_ string, _ protocol.AccessControlProvider) {}
+
+// DeleteAC is no-op, there are no peers to verify.
+func (n *localNet) DeleteAC(_ string) {}
+
+// SetMsgPriority is no-op, no messages are sent.
+func (n *localNet) SetMsgPriority(_ string, _ uint8) {}
+
+// IsRunning return true when the net is started.
+func (n *localNet) IsRunning() bool {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.running
+}
+
+// ChainNodesInfo return no nodes, the node has no peers.
+func (n *localNet) ChainNodesInfo(_ string) ([]*protocol.ChainNodeInfo, error) {
+	return []*protocol.ChainNodeInfo{}, nil
+}
+
+// GetNodeUidByCertId always fails, no tls handshake maps cert ids to nodes.
+func (n *localNet) GetNodeUidByCertId(certId string) (string, error) {
+	return "", fmt.Errorf("cert id [%s] is not mapped by local net", certId)
+}
+
+// Start the net.
+func (n *localNet) Start() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.running = true
+	return nil
+}
+
+// Stop the net.
+func (n *localNet) Stop() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.running = false
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package devnet
+
+import (
+	"testing"
+
+	"chainmaker.org/chainmaker/protocol/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var _ protocol.Net = (*localNet)(nil)
+
+func TestLocalNet(t *testing.T) {
+	n := newLocalNet("QmA")
+	require.Equal(t, "QmA", n.GetNodeUid())
+	require.Equal(t, errNetNotRunning, n.BroadcastWithChainId(DefaultChainId, "topic", []byte("hello")))
+	require.Equal(t, errNetNotRunning, n.SendMsg(DefaultChainId, "QmB", "flag", []byte("direct")))
+
+	require.Nil(t, n.Start())
+	require.True(t, n.IsRunning())
+	require.Nil(t, n.InitPubSub(DefaultChainId, 0))
+	handler := func(from string, msg []byte) error {
+		t.Fatalf("msg from %s is delivered", from)
+		return nil
+	}
+	require.Nil(t, n.SubscribeWithChainId(DefaultChainId, "topic", handler))
+	require.Nil(t, n.DirectMsgHandle(DefaultChainId, "flag", handler))
+
+	// the node is alone, broadcasts reach nobody and direct messages fail
+	require.Nil(t, n.BroadcastWithChainId(DefaultChainId, "topic", []byte("hello")))
+	require.NotNil(t, n.SendMsg(DefaultChainId, "QmA", "flag", []byte("direct")))
+	require.NotNil(t, n.SendMsg(DefaultChainId, "QmB", "flag", []byte("direct")))
+	infos, err := n.ChainNodesInfo(DefaultChainId)
+	require.Nil(t, err)
+	require.Len(t, infos, 0)
+
+	require.Nil(t, n.Stop())
+	require.False(t, n.IsRunning())
+}
@@ -35,6 +35,7 @@ require (
 	github.com/gogo/protobuf v1.3.2
 	github.com/golang/mock v1.6.0
 	github.com/google/shlex v0.0.0-20181106134648-c34317bd91bf
+	github.com/gorilla/websocket v1.4.3-0.20220104015952-9111bb834a68
 	github.com/gosuri/uiprogress v0.0.1
 	github.com/grpc-ecosystem/go-grpc-middleware v1.3.0
 	github.com/grpc-ecosystem/grpc-gateway v1.16.0
@@ -79,6 +80,7 @@ require (
 )
 
 replace (
+	chainmaker.org/chainmaker/sdk-go/v2 => ../sdk-go
 	github.com/RedisBloom/redisbloom-go => chainmaker.org/third_party/redisbloom-go v1.0.0
 	github.com/dgraph-io/badger/v3 => chainmaker.org/third_party/badger/v3 v3.0.0
 	github.com/libp2p/go-conn-security-multistream v0.2.0 => chainmaker.org/third_party/go-conn-security-multistream v1.0.5
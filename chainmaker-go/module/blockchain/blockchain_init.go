@@ -563,7 +563,7 @@ func (bc *Blockchain) initTxPool() (err error) {
 		return err
 	}
 
-	bc.txPool = currentTxPool
+	bc.txPool = txpool.NewSequenceTxPool(currentTxPool, bc.chainConf, bc.store, bc.ac, txPoolLogger)
 	bc.initModules[moduleNameTxPool] = struct{}{}
 	return nil
 }
@@ -58,6 +58,12 @@ func NewChainMakerServer() *ChainMakerServer {
 	return &ChainMakerServer{}
 }
 
+// NewChainMakerServerWithNet create a new ChainMakerServer instance which uses the given net
+// instead of the one built from the net config, the node id is still loaded from the tls key.
+func NewChainMakerServerWithNet(localNet protocol.Net) *ChainMakerServer {
+	return &ChainMakerServer{net: localNet}
+}
+
 // Init ChainMakerServer.
 func (server *ChainMakerServer) Init() error {
 	var err error
@@ -142,43 +148,45 @@ func (server *ChainMakerServer) initNet() error {
 	encKeyPath, _ := filepath.Abs(localconf.ChainMakerConfig.NetConfig.TLSConfig.PrivEncKeyFile)
 	encCertPath, _ := filepath.Abs(localconf.ChainMakerConfig.NetConfig.TLSConfig.CertEncFile)
 
-	// new net
-	var netFactory net.NetFactory
-	server.net, err = netFactory.NewNet(
-		netType,
-		net.WithReadySignalC(server.readyC),
-		net.WithListenAddr(localconf.ChainMakerConfig.NetConfig.ListenAddr),
-		net.WithCrypto(pubKeyMode, keyPath, certPath, encKeyPath, encCertPath),
-		net.WithPeerStreamPoolSize(localconf.ChainMakerConfig.NetConfig.PeerStreamPoolSize),
-		net.WithMaxPeerCountAllowed(localconf.ChainMakerConfig.NetConfig.MaxPeerCountAllow),
-		net.WithPeerEliminationStrategy(localconf.ChainMakerConfig.NetConfig.PeerEliminationStrategy),
-		net.WithSeeds(localconf.ChainMakerConfig.NetConfig.Seeds...),
-		net.WithBlackAddresses(localconf.ChainMakerConfig.NetConfig.BlackList.Addresses...),
-		net.WithBlackNodeIds(localconf.ChainMakerConfig.NetConfig.BlackList.NodeIds...),
-		net.WithMsgCompression(localconf.ChainMakerConfig.DebugConfig.UseNetMsgCompression),
-		net.WithInsecurity(localconf.ChainMakerConfig.DebugConfig.IsNetInsecurity),
-		net.WithTlsEnabled(localconf.ChainMakerConfig.NetConfig.TLSConfig.Enabled),
-		net.WithPeerHttpTunnelTargetAddressList(localconf.ChainMakerConfig.NetConfig.SeedTargetAddressList...),
-		net.WithStunClient(localconf.ChainMakerConfig.NetConfig.StunClient.ListenAddr,
-			localconf.ChainMakerConfig.NetConfig.StunClient.StunServerAddr,
-			localconf.ChainMakerConfig.NetConfig.StunClient.NetworkType,
-			localconf.ChainMakerConfig.NetConfig.StunClient.Enabled),
-		net.WithStunServer(localconf.ChainMakerConfig.NetConfig.StunServer.Enabled,
-			localconf.ChainMakerConfig.NetConfig.StunServer.TwoPublicAddress,
-			localconf.ChainMakerConfig.NetConfig.StunServer.OtherStunServerAddr,
-			localconf.ChainMakerConfig.NetConfig.StunServer.LocalNotifyAddr,
-			localconf.ChainMakerConfig.NetConfig.StunServer.OtherNotifyAddr,
-			localconf.ChainMakerConfig.NetConfig.StunServer.ListenAddr1,
-			localconf.ChainMakerConfig.NetConfig.StunServer.ListenAddr2,
-			localconf.ChainMakerConfig.NetConfig.StunServer.ListenAddr3,
-			localconf.ChainMakerConfig.NetConfig.StunServer.ListenAddr4,
-			localconf.ChainMakerConfig.NetConfig.StunServer.NetworkType),
-		net.WithHolePunch(localconf.ChainMakerConfig.NetConfig.EnablePunch),
-	)
-	if err != nil {
-		errMsg := fmt.Sprintf("new net failed, %s", err.Error())
-		log.Error(errMsg)
-		return errors.New(errMsg)
+	// new net, unless the net was given on creation
+	if server.net == nil {
+		var netFactory net.NetFactory
+		server.net, err = netFactory.NewNet(
+			netType,
+			net.WithReadySignalC(server.readyC),
+			net.WithListenAddr(localconf.ChainMakerConfig.NetConfig.ListenAddr),
+			net.WithCrypto(pubKeyMode, keyPath, certPath, encKeyPath, encCertPath),
+			net.WithPeerStreamPoolSize(localconf.ChainMakerConfig.NetConfig.PeerStreamPoolSize),
+			net.WithMaxPeerCountAllowed(localconf.ChainMakerConfig.NetConfig.MaxPeerCountAllow),
+			net.WithPeerEliminationStrategy(localconf.ChainMakerConfig.NetConfig.PeerEliminationStrategy),
+			net.WithSeeds(localconf.ChainMakerConfig.NetConfig.Seeds...),
+			net.WithBlackAddresses(localconf.ChainMakerConfig.NetConfig.BlackList.Addresses...),
+			net.WithBlackNodeIds(localconf.ChainMakerConfig.NetConfig.BlackList.NodeIds...),
+			net.WithMsgCompression(localconf.ChainMakerConfig.DebugConfig.UseNetMsgCompression),
+			net.WithInsecurity(localconf.ChainMakerConfig.DebugConfig.IsNetInsecurity),
+			net.WithTlsEnabled(localconf.ChainMakerConfig.NetConfig.TLSConfig.Enabled),
+			net.WithPeerHttpTunnelTargetAddressList(localconf.ChainMakerConfig.NetConfig.SeedTargetAddressList...),
+			net.WithStunClient(localconf.ChainMakerConfig.NetConfig.StunClient.ListenAddr,
+				localconf.ChainMakerConfig.NetConfig.StunClient.StunServerAddr,
+				localconf.ChainMakerConfig.NetConfig.StunClient.NetworkType,
+				localconf.ChainMakerConfig.NetConfig.StunClient.Enabled),
+			net.WithStunServer(localconf.ChainMakerConfig.NetConfig.StunServer.Enabled,
+				localconf.ChainMakerConfig.NetConfig.StunServer.TwoPublicAddress,
+				localconf.ChainMakerConfig.NetConfig.StunServer.OtherStunServerAddr,
+				localconf.ChainMakerConfig.NetConfig.StunServer.LocalNotifyAddr,
+				localconf.ChainMakerConfig.NetConfig.StunServer.OtherNotifyAddr,
+				localconf.ChainMakerConfig.NetConfig.StunServer.ListenAddr1,
+				localconf.ChainMakerConfig.NetConfig.StunServer.ListenAddr2,
+				localconf.ChainMakerConfig.NetConfig.StunServer.ListenAddr3,
+				localconf.ChainMakerConfig.NetConfig.StunServer.ListenAddr4,
+				localconf.ChainMakerConfig.NetConfig.StunServer.NetworkType),
+			net.WithHolePunch(localconf.ChainMakerConfig.NetConfig.EnablePunch),
+		)
+		if err != nil {
+			errMsg := fmt.Sprintf("new net failed, %s", err.Error())
+			log.Error(errMsg)
+			return errors.New(errMsg)
+		}
 	}
 
 	// read key file, then set the NodeId of local config
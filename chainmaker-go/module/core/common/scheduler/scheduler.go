@@ -116,10 +116,16 @@ func (ts *TxScheduler) Schedule(block *commonPb.Block, txBatch []*commonPb.Trans
 	timeoutC := time.After(ScheduleTimeout * time.Second)
 	startTime := time.Now()
 
+	blockVersion := block.Header.BlockVersion
+	var sequencer *senderSequencer
+	if blockVersion >= blockVersion2360 {
+		txBatch, sequencer = ts.newSenderSequencer(txBatch, snapshot, blockVersion)
+		txBatchSize = len(txBatch)
+	}
+
 	runningTxC := make(chan *commonPb.Transaction, txBatchSize)
 	finishC := make(chan bool)
 
-	blockVersion := block.Header.BlockVersion
 	enableOptimizeChargeGas := coinbasemgr.IsOptimizeChargeGasEnabled(ts.chainConf)
 	enableSenderGroup := ts.chainConf.ChainConfig().Core.EnableSenderGroup
 	enableConflictsBitWindow, conflictsBitWindow := ts.initOptimizeTools(txBatch)
@@ -128,6 +134,7 @@ func (ts *TxScheduler) Schedule(block *commonPb.Block, txBatch []*commonPb.Trans
 	if enableOptimizeChargeGas {
 		ts.log.Debugf("before prepare `SenderCollection` ")
 		senderCollection = ts.NewSenderCollection(txBatch, snapshot, ts.ac, blockVersion, ts.log)
+		sequencer.retain(senderCollection)
 		ts.log.Debugf("end prepare `SenderCollection` ")
 	} else if enableSenderGroup {
 		ts.log.Debugf("before prepare `SenderGroup` ")
@@ -171,8 +178,8 @@ func (ts *TxScheduler) Schedule(block *commonPb.Block, txBatch []*commonPb.Trans
 	// Put the pending transaction into the running queue
 	if parallelTxsNum > 0 {
 		go ts.startTxHandler(runningTxC, block, snapshot, finishC, goRoutinePool, enableConflictsBitWindow,
-			conflictsBitWindow, enableSenderGroup, senderGroup, senderCollection, timeoutC, enableOptimizeChargeGas,
-			parallelTxsNum)
+			conflictsBitWindow, enableSenderGroup, senderGroup, senderCollection, sequencer, timeoutC,
+			enableOptimizeChargeGas, parallelTxsNum)
 		// Wait for schedule finish signal
 		<-ts.scheduleFinishC
 	}
@@ -204,7 +211,7 @@ func (ts *TxScheduler) startTxHandler(runningTxC chan *commonPb.Transaction,
 	block *commonPb.Block, snapshot protocol.Snapshot, finishC chan bool,
 	goRoutinePool *ants.Pool, enableConflictsBitWindow bool, conflictsBitWindow *ConflictsBitWindow,
 	enableSenderGroup bool, senderGroup *SenderGroup, senderCollection *SenderCollection,
-	timeoutC <-chan time.Time, enableOptimizeChargeGas bool, parallelTxsNum int) {
+	sequencer *senderSequencer, timeoutC <-chan time.Time, enableOptimizeChargeGas bool, parallelTxsNum int) {
 	counter := 0
 	for {
 		select {
@@ -213,7 +220,8 @@ func (ts *TxScheduler) startTxHandler(runningTxC chan *commonPb.Transaction,
 
 			err := goRoutinePool.Submit(func() {
 				handleTx(block, snapshot, ts, tx, runningTxC, finishC, goRoutinePool, parallelTxsNum,
-					enableConflictsBitWindow, conflictsBitWindow, enableSenderGroup, senderGroup, senderCollection)
+					enableConflictsBitWindow, conflictsBitWindow, enableSenderGroup, senderGroup, senderCollection,
+					sequencer)
 			})
 			if err != nil {
 				ts.log.Warnf("failed to submit running task, tx id:%s during schedule, %+v",
@@ -292,7 +300,8 @@ func handleTx(block *commonPb.Block, snapshot protocol.Snapshot,
 	runningTxC chan *commonPb.Transaction, finishC chan bool,
 	goRoutinePool *ants.Pool, txBatchSize int,
 	enableConflictsBitWindow bool, conflictsBitWindow *ConflictsBitWindow,
-	enableSenderGroup bool, senderGroup *SenderGroup, senderCollection *SenderCollection) {
+	enableSenderGroup bool, senderGroup *SenderGroup, senderCollection *SenderCollection,
+	sequencer *senderSequencer) {
 
 	// If snapshot is sealed, no more transaction will be added into snapshot
 	if snapshot.IsSealed() {
@@ -301,6 +310,10 @@ func handleTx(block *commonPb.Block, snapshot protocol.Snapshot,
 		})
 		return
 	}
+	// the txs of the same sender with smaller sequences apply first, the tx is parked until they are applied
+	if sequencer.park(tx) {
+		return
+	}
 	var start time.Time
 	if localconf.ChainMakerConfig.MonitorConfig.Enabled {
 		start = time.Now()
@@ -337,6 +350,9 @@ func handleTx(block *commonPb.Block, snapshot protocol.Snapshot,
 		})
 
 	} else {
+		for _, released := range sequencer.done(tx) {
+			runningTxC <- released
+		}
 		ts.handleApplyResult(enableConflictsBitWindow, enableSenderGroup,
 			conflictsBitWindow, senderGroup, goRoutinePool, tx, start)
 
@@ -700,6 +716,10 @@ func (ts *TxScheduler) executeTx(
 		}
 	}
 
+	if IsSequencedTx(tx, blockVersion) && !ts.checkSenderSequence(tx, txSimContext) {
+		return txSimContext, protocol.ExecOrderTxTypeNormal, false
+	}
+
 	ts.log.Debugf("run vm start for tx:%s", tx.Payload.GetTxId())
 	if blockVersion >= 2300 {
 		if txResult, specialTxType, err = ts.runVM2300(tx, txSimContext, enableOptimizeChargeGas); err != nil {
@@ -0,0 +1,363 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scheduler
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"chainmaker.org/chainmaker/protocol/v2"
+)
+
+// Sender sequence is an optional per-account sequence number carried by `Payload.Sequence` of invoke txs.
+// A tx with sequence 0 is not ordered, as before. A tx with sequence > 0 executes only when its sequence
+// is the next expected one of its sender, which starts from 1 and increases by 1 with every successful tx.
+// A failed tx does not consume its sequence, the sender may send another tx with the same sequence.
+// Chain config txs keep using `Payload.Sequence` as the chain config sequence and are not ordered.
+// Txs are ordered from block version 2.3.6, blocks of older versions execute as before.
+const (
+	blockVersion2360 = uint32(2030600)
+
+	// SenderSequencePrefix key prefix of the next expected sequence of an account, under the account manager
+	SenderSequencePrefix = "__sender_sequence__"
+	// QuerySenderSequenceMethod query method of the next expected sequence of an account
+	// under the account manager, served by the rpc server
+	QuerySenderSequenceMethod = "GET_SENDER_SEQUENCE"
+	// QuerySenderSequenceAddressParam optional param of the query, the sender of the query by default
+	QuerySenderSequenceAddressParam = "address"
+)
+
+var senderSequenceContract = syscontract.SystemContract_ACCOUNT_MANAGER.String()
+
+// IsSequencedTx return whether the tx is ordered by the sequence of its sender
+func IsSequencedTx(tx *commonPb.Transaction, blockVersion uint32) bool {
+	payload := tx.GetPayload()
+	return blockVersion >= blockVersion2360 &&
+		payload.GetSequence() > 0 &&
+		payload.GetTxType() == commonPb.TxType_INVOKE_CONTRACT &&
+		payload.GetContractName() != syscontract.SystemContract_CHAIN_CONFIG.String()
+}
+
+// GetSenderAddress get the address of the tx sender, which owns the sequence
+func GetSenderAddress(tx *commonPb.Transaction, snapshot protocol.Snapshot,
+	ac protocol.AccessControlProvider) (string, error) {
+	member := getTxSenderSigner(tx)
+	if member == nil {
+		return "", errors.New("tx has no sender")
+	}
+	pkBytes, err := publicKeyPEMFromMember(member, snapshot)
+	if err != nil {
+		return "", err
+	}
+	address, _, err := ac.GetAddressFromCache(pkBytes)
+	if err != nil {
+		return "", fmt.Errorf("GetAddressFromCache failed: err = %v", err)
+	}
+	return address, nil
+}
+
+// GetCommittedSenderSequence get the sender address of tx and its next expected sequence in the committed
+// state, for the checks before the tx enters the tx pool
+func GetCommittedSenderSequence(tx *commonPb.Transaction, store protocol.BlockchainStore,
+	ac protocol.AccessControlProvider) (string, uint64, error) {
+	member := getTxSenderSigner(tx)
+	if member == nil {
+		return "", 0, errors.New("tx has no sender")
+	}
+	var (
+		pkBytes []byte
+		err     error
+	)
+	if member.MemberType == accesscontrol.MemberType_CERT_HASH {
+		var cert []byte
+		if cert, err = store.ReadObject(syscontract.SystemContract_CERT_MANAGE.String(),
+			[]byte(hex.EncodeToString(member.MemberInfo))); err != nil {
+			return "", 0, err
+		}
+		pkBytes, err = publicKeyFromCert(cert)
+	} else {
+		pkBytes, err = publicKeyPEMFromMember(member, nil)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	address, _, err := ac.GetAddressFromCache(pkBytes)
+	if err != nil {
+		return "", 0, fmt.Errorf("GetAddressFromCache failed: err = %v", err)
+	}
+	value, err := store.ReadObject(senderSequenceContract, []byte(SenderSequencePrefix+address))
+	if err != nil {
+		return "", 0, err
+	}
+	sequence, err := parseSenderSequence(value)
+	return address, sequence, err
+}
+
+// GetSenderSequence get the next expected sequence of the address
+func GetSenderSequence(snapshot protocol.Snapshot, address string) (uint64, error) {
+	value, err := snapshot.GetKey(-1, senderSequenceContract, []byte(SenderSequencePrefix+address))
+	if err != nil {
+		return 0, err
+	}
+	return parseSenderSequence(value)
+}
+
+func parseSenderSequence(value []byte) (uint64, error) {
+	if len(value) == 0 {
+		return 1, nil
+	}
+	return strconv.ParseUint(string(value), 10, 64)
+}
+
+// checkSenderSequence check the sequence of tx against the next expected one of its sender
+// and consume it, the rwset of the sequence orders the txs of one sender in the dag
+func (ts *TxScheduler) checkSenderSequence(tx *commonPb.Transaction, txSimContext protocol.TxSimContext) bool {
+	setFailed := func(code commonPb.TxStatusCode, errMsg string) bool {
+		txSimContext.SetTxResult(&commonPb.Result{
+			Code: code,
+			ContractResult: &commonPb.ContractResult{
+				Code:    uint32(1),
+				Message: errMsg,
+			},
+			Message: errMsg,
+		})
+		return false
+	}
+
+	address, err := GetSenderAddress(tx, txSimContext.GetSnapshot(), ts.ac)
+	if err != nil {
+		return setFailed(commonPb.TxStatusCode_INTERNAL_ERROR, fmt.Sprintf("get sender address failed, %s", err))
+	}
+	key := []byte(SenderSequencePrefix + address)
+	value, err := txSimContext.Get(senderSequenceContract, key)
+	if err != nil {
+		return setFailed(commonPb.TxStatusCode_INTERNAL_ERROR, fmt.Sprintf("get sender sequence failed, %s", err))
+	}
+	expected, err := parseSenderSequence(value)
+	if err != nil {
+		return setFailed(commonPb.TxStatusCode_INTERNAL_ERROR, fmt.Sprintf("parse sender sequence failed, %s", err))
+	}
+	if tx.Payload.Sequence != expected {
+		return setFailed(commonPb.TxStatusCode_INVALID_PARAMETER, fmt.Sprintf(
+			"sequence %d of tx `%s` is not the expected %d of `%s`",
+			tx.Payload.Sequence, tx.Payload.TxId, expected, address))
+	}
+	if err = txSimContext.Put(senderSequenceContract, key,
+		[]byte(strconv.FormatUint(expected+1, 10))); err != nil {
+		return setFailed(commonPb.TxStatusCode_INTERNAL_ERROR, fmt.Sprintf("put sender sequence failed, %s", err))
+	}
+	return true
+}
+
+// senderSequencer holds back the sequenced txs of the proposer until the txs of the same sender
+// with smaller sequences are applied, so that they apply in the order of their sequences
+type senderSequencer struct {
+	lock sync.Mutex
+	// tx id => sender of the tx
+	txs map[string]sequencedSender
+	// sender address => sequences of the txs not applied yet
+	pending map[string]map[uint64]struct{}
+	// sender address => txs held back, released by done
+	parked map[string][]*commonPb.Transaction
+}
+
+type sequencedSender struct {
+	address  string
+	sequence uint64
+}
+
+// newSenderSequencer select the sequenced txs which can execute in the block:
+//  1. the txs with consecutive sequences from the next expected one of the sender, when several txs
+//     have the same sequence, the one with the highest gas limit is selected, the others wait
+//  2. the txs with sequences lower than the expected one, they fail and leave the tx pool
+//
+// the txs after a missing sequence are left out of the block and go back to the tx pool.
+// the returned batch lists the txs of every sender in the order of their sequences
+func (ts *TxScheduler) newSenderSequencer(txBatch []*commonPb.Transaction, snapshot protocol.Snapshot,
+	blockVersion uint32) ([]*commonPb.Transaction, *senderSequencer) {
+
+	sequencer := &senderSequencer{
+		txs:     make(map[string]sequencedSender),
+		pending: make(map[string]map[uint64]struct{}),
+		parked:  make(map[string][]*commonPb.Transaction),
+	}
+
+	senderIndexes := make(map[string][]int)
+	var senders []string
+	for i, tx := range txBatch {
+		if !IsSequencedTx(tx, blockVersion) {
+			continue
+		}
+		address, err := GetSenderAddress(tx, snapshot, ts.ac)
+		if err != nil {
+			// executes and fails in checkSenderSequence
+			ts.log.Warnf("get sender address of tx `%s` failed, %s", tx.Payload.TxId, err)
+			continue
+		}
+		if _, ok := senderIndexes[address]; !ok {
+			senders = append(senders, address)
+		}
+		senderIndexes[address] = append(senderIndexes[address], i)
+	}
+	if len(senders) == 0 {
+		return txBatch, nil
+	}
+
+	selected := make([]*commonPb.Transaction, len(txBatch))
+	copy(selected, txBatch)
+	for _, address := range senders {
+		indexes := senderIndexes[address]
+		txs := make([]*commonPb.Transaction, 0, len(indexes))
+		for _, i := range indexes {
+			txs = append(txs, txBatch[i])
+			selected[i] = nil
+		}
+		expected, err := GetSenderSequence(snapshot, address)
+		if err != nil {
+			// the txs of the sender go back to the tx pool
+			ts.log.Warnf("get sender sequence of `%s` failed, %s", address, err)
+			continue
+		}
+		sort.SliceStable(txs, func(i, j int) bool {
+			if txs[i].Payload.Sequence != txs[j].Payload.Sequence {
+				return txs[i].Payload.Sequence < txs[j].Payload.Sequence
+			}
+			return txs[i].Payload.GetLimit().GetGasLimit() > txs[j].Payload.GetLimit().GetGasLimit()
+		})
+
+		pending := make(map[uint64]struct{})
+		next := expected
+		slot := 0
+		for _, tx := range txs {
+			sequence := tx.Payload.Sequence
+			if sequence >= expected {
+				if sequence != next {
+					// duplicated or after a missing sequence
+					continue
+				}
+				next++
+				pending[sequence] = struct{}{}
+				sequencer.txs[tx.Payload.TxId] = sequencedSender{address: address, sequence: sequence}
+			}
+			selected[indexes[slot]] = tx
+			slot++
+		}
+		if len(pending) > 0 {
+			sequencer.pending[address] = pending
+		}
+		if left := len(txs) - slot; left > 0 {
+			ts.log.Infof("sender `%s` expects sequence %d, %d txs wait for the next blocks",
+				address, expected, left)
+		}
+	}
+
+	batch := make([]*commonPb.Transaction, 0, len(txBatch))
+	for _, tx := range selected {
+		if tx != nil {
+			batch = append(batch, tx)
+		}
+	}
+	return batch, sequencer
+}
+
+// ready return whether the txs of the same sender with smaller sequences are all applied
+func (s *senderSequencer) ready(tx *commonPb.Transaction) bool {
+	if s == nil {
+		return true
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sender, ok := s.txs[tx.Payload.TxId]
+	return !ok || s.readyLocked(sender)
+}
+
+func (s *senderSequencer) readyLocked(sender sequencedSender) bool {
+	for sequence := range s.pending[sender.address] {
+		if sequence < sender.sequence {
+			return false
+		}
+	}
+	return true
+}
+
+// park hold back the tx until it is ready, it returns false and keeps nothing if the tx is ready now,
+// the parked tx is returned by the done of the tx it waits for
+func (s *senderSequencer) park(tx *commonPb.Transaction) bool {
+	if s == nil {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sender, ok := s.txs[tx.Payload.TxId]
+	if !ok || s.readyLocked(sender) {
+		return false
+	}
+	s.parked[sender.address] = append(s.parked[sender.address], tx)
+	return true
+}
+
+// done mark the tx applied, or never dispatched to the parallel execution,
+// it returns the parked txs of the same sender which are ready now
+func (s *senderSequencer) done(tx *commonPb.Transaction) []*commonPb.Transaction {
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sender, ok := s.txs[tx.Payload.TxId]
+	if !ok {
+		return nil
+	}
+	delete(s.pending[sender.address], sender.sequence)
+	return s.releaseLocked(sender.address)
+}
+
+func (s *senderSequencer) releaseLocked(address string) []*commonPb.Transaction {
+	parked := s.parked[address]
+	if len(parked) == 0 {
+		return nil
+	}
+	var released, waiting []*commonPb.Transaction
+	for _, tx := range parked {
+		if s.readyLocked(s.txs[tx.Payload.TxId]) {
+			released = append(released, tx)
+		} else {
+			waiting = append(waiting, tx)
+		}
+	}
+	s.parked[address] = waiting
+	return released
+}
+
+// retain mark the txs not dispatched to the parallel execution done, such as the txs of the senders
+// without enough balance, which execute after the parallel ones and must not hold back the others
+func (s *senderSequencer) retain(collection *SenderCollection) {
+	if s == nil || collection == nil {
+		return
+	}
+	dispatched := make(map[string]struct{}, len(s.txs))
+	for _, txCollection := range collection.txsMap {
+		for _, tx := range txCollection.txs {
+			dispatched[tx.Payload.TxId] = struct{}{}
+		}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for txId, sender := range s.txs {
+		if _, ok := dispatched[txId]; !ok {
+			delete(s.pending[sender.address], sender.sequence)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	"chainmaker.org/chainmaker/logger/v2"
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"chainmaker.org/chainmaker/protocol/v2/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSequencedTx(txId string, sequence, gasLimit uint64) *commonPb.Transaction {
+	tx := newTxWithPubKeyAndGasLimit(txId, &commonPb.Contract{Name: "contract1"}, nil, gasLimit)
+	tx.Payload.Sequence = sequence
+	return tx
+}
+
+func TestNewSenderSequencer(t *testing.T) {
+	ctl := gomock.NewController(t)
+	ac := mock.NewMockAccessControlProvider(ctl)
+	ac.EXPECT().GetAddressFromCache(gomock.Any()).Return("sender1", nil, nil).AnyTimes()
+	snapshot := mock.NewMockSnapshot(ctl)
+	snapshot.EXPECT().GetKey(-1, syscontract.SystemContract_ACCOUNT_MANAGER.String(),
+		[]byte(SenderSequencePrefix+"sender1")).Return([]byte("2"), nil).AnyTimes()
+	ts := &TxScheduler{ac: ac, log: logger.GetLogger("test")}
+
+	tx3 := newSequencedTx("tx3", 3, 10)
+	tx2a := newSequencedTx("tx2a", 2, 10)
+	tx2b := newSequencedTx("tx2b", 2, 20)
+	tx5 := newSequencedTx("tx5", 5, 10)
+	tx1 := newSequencedTx("tx1", 1, 10)
+	txN := newSequencedTx("txN", 0, 10)
+
+	// old block version, nothing changes
+	batch, sequencer := ts.newSenderSequencer([]*commonPb.Transaction{tx3, tx2a, txN}, snapshot, blockVersion2340)
+	assert.Equal(t, []*commonPb.Transaction{tx3, tx2a, txN}, batch)
+	assert.Nil(t, sequencer)
+	assert.True(t, sequencer.ready(tx3))
+
+	batch, sequencer = ts.newSenderSequencer(
+		[]*commonPb.Transaction{tx3, tx2a, tx2b, tx5, tx1, txN}, snapshot, blockVersion2360)
+	// the stale tx stays to fail, the cheaper duplicated one and the one after the gap wait
+	assert.Equal(t, []*commonPb.Transaction{tx1, tx2b, tx3, txN}, batch)
+
+	assert.True(t, sequencer.ready(tx1))
+	assert.True(t, sequencer.ready(tx2b))
+	assert.False(t, sequencer.ready(tx3))
+	assert.True(t, sequencer.ready(txN))
+
+	// the tx waiting for smaller sequences is parked and released once they are all applied
+	assert.False(t, sequencer.park(tx2b))
+	assert.False(t, sequencer.park(txN))
+	assert.True(t, sequencer.park(tx3))
+	assert.Empty(t, sequencer.done(tx1))
+	assert.Equal(t, []*commonPb.Transaction{tx3}, sequencer.done(tx2b))
+	assert.True(t, sequencer.ready(tx3))
+	assert.False(t, sequencer.park(tx3))
+	assert.Empty(t, sequencer.done(tx3))
+	assert.Empty(t, sequencer.done(txN))
+
+	// txs not dispatched in parallel do not hold back the others
+	_, sequencer = ts.newSenderSequencer([]*commonPb.Transaction{tx3, tx2a}, snapshot, blockVersion2360)
+	assert.False(t, sequencer.ready(tx3))
+	sequencer.retain(&SenderCollection{txsMap: map[string]*TxCollection{
+		"sender1": {txs: []*commonPb.Transaction{tx3}},
+	}})
+	assert.True(t, sequencer.ready(tx3))
+}
+
+func TestCheckSenderSequence(t *testing.T) {
+	ctl := gomock.NewController(t)
+	ac := mock.NewMockAccessControlProvider(ctl)
+	ac.EXPECT().GetAddressFromCache(gomock.Any()).Return("sender1", nil, nil).AnyTimes()
+	ts := &TxScheduler{ac: ac, log: logger.GetLogger("test")}
+	key := []byte(SenderSequencePrefix + "sender1")
+
+	txSimContext := mock.NewMockTxSimContext(ctl)
+	txSimContext.EXPECT().GetSnapshot().Return(nil).AnyTimes()
+	txSimContext.EXPECT().Get(syscontract.SystemContract_ACCOUNT_MANAGER.String(), key).
+		Return(nil, nil).AnyTimes()
+
+	// the first expected sequence is 1
+	txSimContext.EXPECT().Put(syscontract.SystemContract_ACCOUNT_MANAGER.String(), key, []byte("2")).
+		Return(nil).Times(1)
+	assert.True(t, ts.checkSenderSequence(newSequencedTx("tx1", 1, 10), txSimContext))
+
+	var result *commonPb.Result
+	txSimContext.EXPECT().SetTxResult(gomock.Any()).Do(func(r *commonPb.Result) { result = r }).Times(1)
+	assert.False(t, ts.checkSenderSequence(newSequencedTx("tx2", 2, 10), txSimContext))
+	assert.Equal(t, commonPb.TxStatusCode_INVALID_PARAMETER, result.Code)
+}
+
+func TestIsSequencedTx(t *testing.T) {
+	assert.True(t, IsSequencedTx(newSequencedTx("tx1", 1, 10), blockVersion2360))
+	assert.False(t, IsSequencedTx(newSequencedTx("tx1", 1, 10), blockVersion2340))
+	assert.False(t, IsSequencedTx(newSequencedTx("tx1", 1, 10), uint32(2030500)))
+	assert.False(t, IsSequencedTx(newSequencedTx("tx1", 0, 10), blockVersion2360))
+
+	tx := newSequencedTx("tx1", 1, 10)
+	tx.Payload.ContractName = syscontract.SystemContract_CHAIN_CONFIG.String()
+	assert.False(t, IsSequencedTx(tx, blockVersion2360))
+	tx = newSequencedTx("tx1", 1, 10)
+	tx.Payload.TxType = commonPb.TxType_QUERY_CONTRACT
+	assert.False(t, IsSequencedTx(tx, blockVersion2360))
+}
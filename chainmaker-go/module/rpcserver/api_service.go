@@ -12,6 +12,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"chainmaker.org/chainmaker-go/module/blockchain"
+	"chainmaker.org/chainmaker-go/module/core/common/scheduler"
 	"chainmaker.org/chainmaker-go/module/snapshot"
 	commonErr "chainmaker.org/chainmaker/common/v2/errors"
 	"chainmaker.org/chainmaker/common/v2/monitor"
@@ -28,6 +30,7 @@ import (
 	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
 	configPb "chainmaker.org/chainmaker/pb-go/v2/config"
 	syncPb "chainmaker.org/chainmaker/pb-go/v2/sync"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
 	txpoolPb "chainmaker.org/chainmaker/pb-go/v2/txpool"
 	"chainmaker.org/chainmaker/protocol/v2"
 	tbf "chainmaker.org/chainmaker/store/v2/types/blockfile"
@@ -41,6 +44,10 @@ import (
 const (
 	//SYSTEM_CHAIN the system chain name
 	SYSTEM_CHAIN = "system_chain"
+
+	// callContextParam the arg key reserved by vm-engine to pass the call stack of cross contract calls
+	callContextParam = "__call_context__"
+	blockVersion2360 = uint32(2030600)
 )
 
 var _ apiPb.RpcNodeServer = (*ApiService)(nil)
@@ -224,9 +231,30 @@ func (s *ApiService) validate(tx *commonPb.Transaction) (errCode commonErr.ErrCo
 		return
 	}
 
+	if err = checkReservedParameters(tx, blockVersion); err != nil {
+		errCode = commonErr.ERR_CODE_TX_VERIFY_FAILED
+		errMsg = s.getErrMsg(errCode, err)
+		s.log.Error(errMsg)
+		return
+	}
+
 	return commonErr.ERR_CODE_OK, ""
 }
 
+// checkReservedParameters the args reserved by the vm are set by the engine, a tx giving them is rejected
+// instead of having them dropped silently
+func checkReservedParameters(tx *commonPb.Transaction, blockVersion uint32) error {
+	if blockVersion < blockVersion2360 {
+		return nil
+	}
+	for _, param := range tx.Payload.Parameters {
+		if param.Key == callContextParam {
+			return fmt.Errorf("parameter %s is reserved, txId:%s", callContextParam, tx.Payload.TxId)
+		}
+	}
+	return nil
+}
+
 func (s *ApiService) getErrMsg(errCode commonErr.ErrCode, err error) string {
 	return fmt.Sprintf("%s, %s", errCode.String(), err.Error())
 }
@@ -313,6 +341,11 @@ func (s *ApiService) dealQuery(tx *commonPb.Transaction, source protocol.TxSourc
 		return resp
 	}
 
+	if tx.Payload.ContractName == syscontract.SystemContract_ACCOUNT_MANAGER.String() &&
+		tx.Payload.Method == scheduler.QuerySenderSequenceMethod {
+		return s.dealSenderSequenceQuery(tx, snap)
+	}
+
 	blockVersion := protocol.DefaultBlockVersion
 
 	if cc, err1 := s.chainMakerServer.GetChainConf(tx.Payload.ChainId); err1 == nil {
@@ -533,6 +566,38 @@ func (s *ApiService) dealSystemChainQuery(tx *commonPb.Transaction, vmMgr protoc
 	return resp
 }
 
+// dealSenderSequenceQuery - query the next expected sequence of the given address, or the query sender
+func (s *ApiService) dealSenderSequenceQuery(tx *commonPb.Transaction, snap protocol.Snapshot) *commonPb.TxResponse {
+	resp := &commonPb.TxResponse{TxId: tx.Payload.TxId}
+	setErr := func(err error) *commonPb.TxResponse {
+		s.log.Warnf("query sender sequence failed, %s", err)
+		resp.Code = commonPb.TxStatusCode_CONTRACT_FAIL
+		resp.Message = err.Error()
+		resp.ContractResult = &commonPb.ContractResult{Code: uint32(1), Message: err.Error()}
+		return resp
+	}
+
+	address := string(s.kvPair2Map(tx.Payload.Parameters)[scheduler.QuerySenderSequenceAddressParam])
+	if address == "" {
+		chain, err := s.chainMakerServer.GetBlockchain(tx.Payload.ChainId)
+		if err != nil {
+			return setErr(err)
+		}
+		if address, err = scheduler.GetSenderAddress(tx, snap, chain.GetAccessControl()); err != nil {
+			return setErr(err)
+		}
+	}
+	sequence, err := scheduler.GetSenderSequence(snap, address)
+	if err != nil {
+		return setErr(err)
+	}
+
+	resp.Code = commonPb.TxStatusCode_SUCCESS
+	resp.Message = commonPb.TxStatusCode_SUCCESS.String()
+	resp.ContractResult = &commonPb.ContractResult{Result: []byte(strconv.FormatUint(sequence, 10))}
+	return resp
+}
+
 // kvPair2Map - change []*commonPb.KeyValuePair to map[string]string
 func (s *ApiService) kvPair2Map(kvPair []*commonPb.KeyValuePair) map[string][]byte {
 	kvMap := make(map[string][]byte)
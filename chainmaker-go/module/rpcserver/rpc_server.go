@@ -48,6 +48,7 @@ type RPCServer struct {
 	curChainConfTrustRootsHash string
 	isShutdown                 bool
 	mixServer                  *http.Server
+	subscribeStreams           *subscribeStreamHandler
 }
 
 // prom monitor define
@@ -80,7 +81,7 @@ func NewRPCServer(chainMakerServer *blockchain.ChainMakerServer) (*RPCServer, er
 		return nil, fmt.Errorf("new grpc server failed, %s", err.Error())
 	}
 
-	mixServer, err := newMixServer(grpcServer, chainMakerServer)
+	mixServer, subscribeStreams, err := newMixServer(grpcServer, chainMakerServer)
 	if err != nil {
 		return nil, fmt.Errorf("new http grpc server failed, %s", err.Error())
 	}
@@ -104,6 +105,7 @@ func NewRPCServer(chainMakerServer *blockchain.ChainMakerServer) (*RPCServer, er
 	return &RPCServer{
 		grpcServer:       grpcServer,
 		mixServer:        mixServer,
+		subscribeStreams: subscribeStreams,
 		chainMakerServer: chainMakerServer,
 		log:              logger.GetLogger(logger.MODULE_RPC),
 	}, nil
@@ -111,6 +113,12 @@ func NewRPCServer(chainMakerServer *blockchain.ChainMakerServer) (*RPCServer, er
 
 // Start - start RPCServer
 func (s *RPCServer) Start() error {
+	return s.StartWithListener(nil)
+}
+
+// StartWithListener - start RPCServer on the given listener, which is opened on the configured rpc port if nil.
+// The listener must be on the configured rpc port, because the http gateway dials it.
+func (s *RPCServer) StartWithListener(conn net.Listener) error {
 	var (
 		err       error
 		tlsConfig *cmtls.Config
@@ -135,16 +143,18 @@ func (s *RPCServer) Start() error {
 		return err
 	}
 
-	endPoint := fmt.Sprintf("%s:%d", localconf.ChainMakerConfig.RpcConfig.Host,
-		localconf.ChainMakerConfig.RpcConfig.Port)
-	conn, err := net.Listen("tcp", endPoint)
-	if err != nil {
-		return fmt.Errorf("TCP listen failed, %s", err.Error())
+	if conn == nil {
+		endPoint := fmt.Sprintf("%s:%d", localconf.ChainMakerConfig.RpcConfig.Host,
+			localconf.ChainMakerConfig.RpcConfig.Port)
+		conn, err = net.Listen("tcp", endPoint)
+		if err != nil {
+			return fmt.Errorf("TCP listen failed, %s", err.Error())
+		}
 	}
 
 	go s.startServer(conn, tlsConfig)
 
-	s.log.Infof("gRPC server listen on %s", endPoint)
+	s.log.Infof("gRPC server listen on %s", conn.Addr())
 
 	return nil
 }
@@ -290,6 +300,9 @@ func (s *RPCServer) startServer(conn net.Listener, tlsConfig *cmtls.Config) {
 func (s *RPCServer) RegisterHandler() error {
 	apiService := NewApiService(s.ctx, s.chainMakerServer)
 	apiPb.RegisterRpcNodeServer(s.grpcServer, apiService)
+	if s.subscribeStreams != nil {
+		s.subscribeStreams.setApiService(apiService)
+	}
 	return nil
 }
 
@@ -321,7 +334,7 @@ func (s *RPCServer) Restart(reason string) error {
 		return errors.New(errMsg)
 	}
 
-	s.mixServer, err = newMixServer(s.grpcServer, s.chainMakerServer)
+	s.mixServer, s.subscribeStreams, err = newMixServer(s.grpcServer, s.chainMakerServer)
 	if err != nil {
 		errMsg := fmt.Sprintf("new http grpc server failed, %s", err.Error())
 		s.log.Errorf(errMsg)
@@ -418,6 +431,14 @@ func (s *RPCServer) checkAndRestart() error {
 	return nil
 }
 
+// streamInterceptors - interceptors of stream methods, shared by the grpc server and the subscribe endpoints
+func streamInterceptors() []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		BlackListStreamInterceptor(),
+		StreamRecoveryInterceptor(),
+	}
+}
+
 // newGrpc - new GRPC object
 func newGrpc(chainMakerServer *blockchain.ChainMakerServer) (*grpc.Server, error) {
 	var opts []grpc.ServerOption
@@ -430,10 +451,7 @@ func newGrpc(chainMakerServer *blockchain.ChainMakerServer) (*grpc.Server, error
 				BlackListInterceptor(),
 				RateLimitInterceptor(),
 			),
-			grpc_middleware.WithStreamServerChain(
-				BlackListStreamInterceptor(),
-				StreamRecoveryInterceptor(),
-			),
+			grpc_middleware.WithStreamServerChain(streamInterceptors()...),
 		}
 	} else {
 		opts = []grpc.ServerOption{
@@ -443,10 +461,7 @@ func newGrpc(chainMakerServer *blockchain.ChainMakerServer) (*grpc.Server, error
 				BlackListInterceptor(),
 				RateLimitInterceptor(),
 			),
-			grpc_middleware.WithStreamServerChain(
-				BlackListStreamInterceptor(),
-				StreamRecoveryInterceptor(),
-			),
+			grpc_middleware.WithStreamServerChain(streamInterceptors()...),
 		}
 	}
 
@@ -556,11 +571,15 @@ func newGrpc(chainMakerServer *blockchain.ChainMakerServer) (*grpc.Server, error
 	return server, nil
 }
 
-func newMixServer(grpcServer *grpc.Server, chainMakerServer *blockchain.ChainMakerServer) (*http.Server, error) {
+// newMixServer create the http server serving grpc, and the gateway with the json subscribe endpoints
+// if the gateway is enabled, the subscribe endpoints handler is nil otherwise
+func newMixServer(grpcServer *grpc.Server, chainMakerServer *blockchain.ChainMakerServer) (*http.Server,
+	*subscribeStreamHandler, error) {
 
 	var (
-		mux        *http.ServeMux
-		httpServer *http.Server
+		mux              *http.ServeMux
+		httpServer       *http.Server
+		subscribeStreams *subscribeStreamHandler
 	)
 
 	if localconf.ChainMakerConfig.RpcConfig.GatewayConfig.Enabled {
@@ -568,7 +587,7 @@ func newMixServer(grpcServer *grpc.Server, chainMakerServer *blockchain.ChainMak
 		gwmux, err := newGateway(chainMakerServer)
 		if err != nil {
 			log.Error(err)
-			return nil, err
+			return nil, nil, err
 		}
 
 		mux.Handle("/", gwmux)
@@ -577,14 +596,16 @@ func newMixServer(grpcServer *grpc.Server, chainMakerServer *blockchain.ChainMak
 	handler := GrpcHandlerFunc(grpcServer, mux)
 
 	if localconf.ChainMakerConfig.RpcConfig.GatewayConfig.Enabled {
-		httpServer = &http.Server{
-			Handler: wsproxy.WebsocketProxy(handler, wsproxy.WithMaxRespBodyBufferSize(
-				localconf.ChainMakerConfig.RpcConfig.GatewayConfig.MaxRespBodySize*1024*1024))}
+		// the subscribe endpoints upgrade websocket themselves, so they go before the websocket proxy
+		subscribeStreams = newSubscribeStreamHandler(wsproxy.WebsocketProxy(handler,
+			wsproxy.WithMaxRespBodyBufferSize(
+				localconf.ChainMakerConfig.RpcConfig.GatewayConfig.MaxRespBodySize*1024*1024)))
+		httpServer = &http.Server{Handler: subscribeStreams}
 	} else {
 		httpServer = &http.Server{Handler: handler}
 	}
 
-	return httpServer, nil
+	return httpServer, subscribeStreams, nil
 }
 
 func newGateway(chainMakerServer *blockchain.ChainMakerServer) (http.Handler, error) {
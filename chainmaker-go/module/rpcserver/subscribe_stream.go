@@ -0,0 +1,325 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	apiPb "chainmaker.org/chainmaker/pb-go/v2/api"
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/websocket"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Subscribe endpoints of the mix server, they accept the same signed subscribe request as Subscribe,
+// passed as the base64 of the protobuf encoded TxRequest in query param rawTx, and stream
+// SubscribeMessage encoded by json
+const (
+	SubscribeWSPath  = "/v1/subscribe/ws"
+	SubscribeSSEPath = "/v1/subscribe/sse"
+
+	subscribeRawTxParam = "rawTx"
+	// subscribeFullMethod the grpc method the stream interceptors see for the subscribe endpoints
+	subscribeFullMethod = "/api.RpcNode/Subscribe"
+)
+
+// SubscribeMessage types
+const (
+	SubscribeMessageBlock         = "block"
+	SubscribeMessageBlockHeader   = "block_header"
+	SubscribeMessageTx            = "tx"
+	SubscribeMessageContractEvent = "contract_event"
+	// SubscribeMessageEnd is sent when the end block is reached, no message follows
+	SubscribeMessageEnd = "end"
+	// SubscribeMessageError is sent when the subscription fails, no message follows
+	SubscribeMessageError = "error"
+)
+
+// SubscribeMessage one json message of the websocket and sse subscribe endpoints, data is the
+// protobuf json (original field names, enums as ints) of BlockInfo, BlockHeader, Transaction
+// or ContractEventInfoList, according to the type
+type SubscribeMessage struct {
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error *SubscribeError `json:"error,omitempty"`
+}
+
+// SubscribeError error of SubscribeMessage, code is the grpc status code
+type SubscribeError struct {
+	Code    uint32 `json:"code"`
+	Message string `json:"message"`
+}
+
+var subscribeJSONMarshaler = &jsonpb.Marshaler{OrigName: true, EnumsAsInts: true}
+
+// subscribeStreamHandler serves the subscribe endpoints and passes the other requests to next,
+// it must run before the websocket proxy of the gateway, which takes every upgrade request
+type subscribeStreamHandler struct {
+	next       http.Handler
+	apiService atomic.Value // *ApiService, set when the rpc server registers handlers
+	upgrader   websocket.Upgrader
+	// interceptor the stream interceptors of grpc server, the black list and panic recovery apply here too
+	interceptor grpc.StreamServerInterceptor
+}
+
+func newSubscribeStreamHandler(next http.Handler) *subscribeStreamHandler {
+	return &subscribeStreamHandler{
+		next:        next,
+		interceptor: grpc_middleware.ChainStreamServer(streamInterceptors()...),
+		upgrader: websocket.Upgrader{
+			// dApps are served from other origins, the signed request is checked instead
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+func (h *subscribeStreamHandler) setApiService(apiService *ApiService) {
+	h.apiService.Store(apiService)
+}
+
+// ServeHTTP implements http.Handler
+func (h *subscribeStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != SubscribeWSPath && r.URL.Path != SubscribeSSEPath {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	apiService, ok := h.apiService.Load().(*ApiService)
+	if !ok {
+		http.Error(w, "rpc server is not started", http.StatusServiceUnavailable)
+		return
+	}
+	req, err := parseSubscribeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Path == SubscribeWSPath {
+		h.serveWS(apiService, req, w, r)
+	} else {
+		h.serveSSE(apiService, req, w, r)
+	}
+}
+
+func (h *subscribeStreamHandler) serveWS(apiService *ApiService, req *commonPb.TxRequest,
+	w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader has replied the error
+		apiService.log.Warnf("upgrade subscribe websocket failed, %s", err)
+		return
+	}
+	defer conn.Close()
+
+	// the client sends nothing, reading detects that it is gone
+	ctx, cancel := context.WithCancel(subscribePeerContext(r))
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	stream := newSubscribeJSONStream(ctx, req, func(_ string, msg []byte) error {
+		return conn.WriteMessage(websocket.TextMessage, msg)
+	})
+	stream.run(apiService, h.interceptor)
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+func (h *subscribeStreamHandler) serveSSE(apiService *ApiService, req *commonPb.TxRequest,
+	w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stream := newSubscribeJSONStream(subscribePeerContext(r), req, func(messageType string, msg []byte) error {
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", messageType, msg); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	stream.run(apiService, h.interceptor)
+}
+
+// subscribePeerContext returns the context of request with its client as the grpc peer,
+// which the black list and the logs of api service read
+func subscribePeerContext(r *http.Request) context.Context {
+	addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+	if err != nil {
+		return r.Context()
+	}
+	return peer.NewContext(r.Context(), &peer.Peer{Addr: addr})
+}
+
+// parseSubscribeRequest decode the rawTx query param, the same as the websocket gateway of SubscribeWS
+func parseSubscribeRequest(r *http.Request) (*commonPb.TxRequest, error) {
+	rawTx := r.URL.Query().Get(subscribeRawTxParam)
+	if rawTx == "" {
+		return nil, fmt.Errorf("query param %s is required", subscribeRawTxParam)
+	}
+	txBytes, err := base64.StdEncoding.DecodeString(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s failed, %s", subscribeRawTxParam, err)
+	}
+	req := &commonPb.TxRequest{}
+	if err = proto.Unmarshal(txBytes, req); err != nil {
+		return nil, fmt.Errorf("unmarshal subscribe tx request failed, %s", err)
+	}
+	if req.Payload == nil || req.Sender == nil {
+		return nil, errors.New("subscribe tx request has no payload or sender")
+	}
+	return req, nil
+}
+
+// subscribeJSONStream implements apiPb.RpcNode_SubscribeServer, it encodes every result
+// as SubscribeMessage and passes it to write with its type
+type subscribeJSONStream struct {
+	ctx         context.Context
+	req         *commonPb.TxRequest
+	messageType string
+	write       func(messageType string, msg []byte) error
+}
+
+func newSubscribeJSONStream(ctx context.Context, req *commonPb.TxRequest,
+	write func(messageType string, msg []byte) error) *subscribeJSONStream {
+	messageType := ""
+	switch req.Payload.Method {
+	case syscontract.SubscribeFunction_SUBSCRIBE_BLOCK.String():
+		messageType = SubscribeMessageBlock
+		for _, kv := range req.Payload.Parameters {
+			if kv.Key == syscontract.SubscribeBlock_ONLY_HEADER.String() && string(kv.Value) == TRUE {
+				messageType = SubscribeMessageBlockHeader
+			}
+		}
+	case syscontract.SubscribeFunction_SUBSCRIBE_TX.String():
+		messageType = SubscribeMessageTx
+	case syscontract.SubscribeFunction_SUBSCRIBE_CONTRACT_EVENT.String():
+		messageType = SubscribeMessageContractEvent
+	}
+	return &subscribeJSONStream{ctx: ctx, req: req, messageType: messageType, write: write}
+}
+
+// run subscribe through the stream interceptors with the checks of Subscribe, then send the end or error message
+func (s *subscribeJSONStream) run(apiService *ApiService, interceptor grpc.StreamServerInterceptor) {
+	if s.messageType == "" {
+		s.sendError(codes.InvalidArgument, fmt.Sprintf("unknown subscribe method %s", s.req.Payload.Method))
+		return
+	}
+	info := &grpc.StreamServerInfo{FullMethod: subscribeFullMethod, IsServerStream: true}
+	err := interceptor(apiService, s, info, func(_ interface{}, stream grpc.ServerStream) error {
+		return apiService.Subscribe(s.req, stream.(apiPb.RpcNode_SubscribeServer))
+	})
+	if s.ctx.Err() != nil {
+		return
+	}
+	if st, _ := status.FromError(err); st.Code() != codes.OK {
+		s.sendError(st.Code(), st.Message())
+		return
+	}
+	if msg, err2 := json.Marshal(&SubscribeMessage{Type: SubscribeMessageEnd}); err2 == nil {
+		_ = s.write(SubscribeMessageEnd, msg)
+	}
+}
+
+func (s *subscribeJSONStream) sendError(code codes.Code, message string) {
+	msg, err := json.Marshal(&SubscribeMessage{
+		Type:  SubscribeMessageError,
+		Error: &SubscribeError{Code: uint32(code), Message: message},
+	})
+	if err == nil {
+		_ = s.write(SubscribeMessageError, msg)
+	}
+}
+
+// Send implements apiPb.RpcNode_SubscribeServer
+func (s *subscribeJSONStream) Send(result *commonPb.SubscribeResult) error {
+	var data proto.Message
+	switch s.messageType {
+	case SubscribeMessageBlock:
+		data = &commonPb.BlockInfo{}
+	case SubscribeMessageBlockHeader:
+		data = &commonPb.BlockHeader{}
+	case SubscribeMessageTx:
+		data = &commonPb.Transaction{}
+	default:
+		data = &commonPb.ContractEventInfoList{}
+	}
+	if err := proto.Unmarshal(result.Data, data); err != nil {
+		return fmt.Errorf("unmarshal subscribe result failed, %s", err)
+	}
+	var buf bytes.Buffer
+	if err := subscribeJSONMarshaler.Marshal(&buf, data); err != nil {
+		return fmt.Errorf("marshal subscribe result to json failed, %s", err)
+	}
+	msg, err := json.Marshal(&SubscribeMessage{Type: s.messageType, Data: buf.Bytes()})
+	if err != nil {
+		return err
+	}
+	return s.write(s.messageType, msg)
+}
+
+// Context implements grpc.ServerStream, it is done when the client is gone
+func (s *subscribeJSONStream) Context() context.Context {
+	return s.ctx
+}
+
+// SetHeader implements grpc.ServerStream
+func (s *subscribeJSONStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+// SendHeader implements grpc.ServerStream
+func (s *subscribeJSONStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+// SetTrailer implements grpc.ServerStream
+func (s *subscribeJSONStream) SetTrailer(metadata.MD) {}
+
+// SendMsg implements grpc.ServerStream
+func (s *subscribeJSONStream) SendMsg(m interface{}) error {
+	result, ok := m.(*commonPb.SubscribeResult)
+	if !ok {
+		return fmt.Errorf("unexpected subscribe message %T", m)
+	}
+	return s.Send(result)
+}
+
+// RecvMsg implements grpc.ServerStream, the subscribe request is the only message of client
+func (s *subscribeJSONStream) RecvMsg(interface{}) error {
+	return errors.New("subscribe stream receives no message")
+}
@@ -0,0 +1,215 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txpool
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker-go/module/core/common/scheduler"
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/protocol/v2"
+)
+
+const (
+	// ReplaceGasLimitBumpPercent the gas limit of the tx replacing the tx with the same sender
+	// and sequence in the pool must be at least this percent higher
+	ReplaceGasLimitBumpPercent = 10
+	// MaxSequenceGap a tx whose sequence is this far or farther above the next expected sequence
+	// of its sender is rejected, it could only wait in the pool for the missing ones
+	MaxSequenceGap = 64
+	// SequenceTxExpiry a tx waiting in the pool this long, usually behind a missing sequence, is removed
+	SequenceTxExpiry = 10 * time.Minute
+
+	minSequencePruneSize    = 1024
+	sequenceTxCheckInterval = time.Minute
+)
+
+// sequenceTxPool wraps the tx pool to replace the txs with sender sequence, a tx added through it
+// replaces the tx with the same sender and sequence in the pool if its gas limit is high enough.
+// the txs received from other nodes do not pass it, the proposer keeps the one with the highest gas
+// limit of the same sequence, and the others fail when the sequence is consumed.
+// a tx with a used sequence or too far ahead of the expected one is rejected, and the txs added
+// through it are removed from the pool after SequenceTxExpiry
+type sequenceTxPool struct {
+	protocol.TxPool
+	chainConf protocol.ChainConf
+	store     protocol.BlockchainStore
+	ac        protocol.AccessControlProvider
+	log       protocol.Logger
+
+	lock sync.Mutex
+	// sender and sequence => tx
+	txs     map[string]*sequencedTx
+	pruneAt int
+
+	stopC chan struct{}
+	wg    sync.WaitGroup
+}
+
+type sequencedTx struct {
+	tx      *commonPb.Transaction
+	addedAt time.Time
+}
+
+// NewSequenceTxPool wrap the tx pool to replace the txs with sender sequence
+func NewSequenceTxPool(pool protocol.TxPool, chainConf protocol.ChainConf, store protocol.BlockchainStore,
+	ac protocol.AccessControlProvider, log protocol.Logger) protocol.TxPool {
+	return &sequenceTxPool{
+		TxPool:    pool,
+		chainConf: chainConf,
+		store:     store,
+		ac:        ac,
+		log:       log,
+		txs:       make(map[string]*sequencedTx),
+		pruneAt:   minSequencePruneSize,
+	}
+}
+
+// Start the pool and the expiry of sequenced txs
+func (pool *sequenceTxPool) Start() error {
+	if err := pool.TxPool.Start(); err != nil {
+		return err
+	}
+	pool.stopC = make(chan struct{})
+	pool.wg.Add(1)
+	go pool.expireLoop(pool.stopC)
+	return nil
+}
+
+// Stop the expiry of sequenced txs and the pool
+func (pool *sequenceTxPool) Stop() error {
+	if pool.stopC != nil {
+		close(pool.stopC)
+		pool.wg.Wait()
+		pool.stopC = nil
+	}
+	return pool.TxPool.Stop()
+}
+
+// AddTx add the tx into the pool, or replace the tx with the same sender and sequence
+func (pool *sequenceTxPool) AddTx(tx *commonPb.Transaction, source protocol.TxSource) error {
+	if source != protocol.RPC ||
+		!scheduler.IsSequencedTx(tx, pool.chainConf.ChainConfig().GetBlockVersion()) {
+		return pool.TxPool.AddTx(tx, source)
+	}
+
+	_, expected, err := scheduler.GetCommittedSenderSequence(tx, pool.store, pool.ac)
+	if err != nil {
+		return fmt.Errorf("get sender sequence of tx `%s` failed, %s", tx.Payload.TxId, err)
+	}
+	if tx.Payload.Sequence < expected {
+		return fmt.Errorf("sequence %d of tx `%s` is used, the next expected is %d", tx.Payload.Sequence,
+			tx.Payload.TxId, expected)
+	}
+	if tx.Payload.Sequence-expected >= MaxSequenceGap {
+		return fmt.Errorf("sequence %d of tx `%s` is %d or more ahead of the next expected %d",
+			tx.Payload.Sequence, tx.Payload.TxId, MaxSequenceGap, expected)
+	}
+
+	key := senderSequenceKey(tx)
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	entry, ok := pool.txs[key]
+	if ok && entry.tx.Payload.TxId != tx.Payload.TxId && pool.inPool(entry.tx) {
+		old := entry.tx
+		oldGasLimit := old.Payload.GetLimit().GetGasLimit()
+		gasLimit := tx.Payload.GetLimit().GetGasLimit()
+		if gasLimit <= oldGasLimit || gasLimit-oldGasLimit < oldGasLimit/100*ReplaceGasLimitBumpPercent {
+			return fmt.Errorf("tx `%s` with sequence %d exists, the gas limit of replacement must be "+
+				"%d%% higher than %d", old.Payload.TxId, tx.Payload.Sequence, ReplaceGasLimitBumpPercent, oldGasLimit)
+		}
+		if err = pool.TxPool.AddTx(tx, source); err != nil {
+			return err
+		}
+		pool.TxPool.RetryAndRemoveTxs(nil, []*commonPb.Transaction{old})
+		pool.txs[key] = &sequencedTx{tx: tx, addedAt: time.Now()}
+		pool.log.Infof("tx [%s] replaces tx [%s] with sequence %d", tx.Payload.TxId, old.Payload.TxId,
+			tx.Payload.Sequence)
+		return nil
+	}
+
+	if err = pool.TxPool.AddTx(tx, source); err != nil {
+		return err
+	}
+	pool.txs[key] = &sequencedTx{tx: tx, addedAt: time.Now()}
+	if len(pool.txs) >= pool.pruneAt {
+		pool.prune()
+	}
+	return nil
+}
+
+// inPool return whether the tx is still in the pool, not committed or removed
+func (pool *sequenceTxPool) inPool(tx *commonPb.Transaction) bool {
+	_, missing, err := pool.TxPool.GetTxsInPoolByTxIds([]string{tx.Payload.TxId})
+	return err == nil && len(missing) == 0
+}
+
+// prune forget the txs no longer in the pool
+func (pool *sequenceTxPool) prune() {
+	txIds := make([]string, 0, len(pool.txs))
+	keys := make(map[string]string, len(pool.txs))
+	for key, entry := range pool.txs {
+		txIds = append(txIds, entry.tx.Payload.TxId)
+		keys[entry.tx.Payload.TxId] = key
+	}
+	_, missing, err := pool.TxPool.GetTxsInPoolByTxIds(txIds)
+	if err != nil {
+		pool.log.Warnf("prune sequenced txs failed, %s", err)
+	}
+	for _, txId := range missing {
+		delete(pool.txs, keys[txId])
+	}
+	pool.pruneAt = 2 * len(pool.txs)
+	if pool.pruneAt < minSequencePruneSize {
+		pool.pruneAt = minSequencePruneSize
+	}
+}
+
+func (pool *sequenceTxPool) expireLoop(stopC chan struct{}) {
+	defer pool.wg.Done()
+	ticker := time.NewTicker(sequenceTxCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			pool.lock.Lock()
+			pool.expire(now)
+			pool.lock.Unlock()
+		case <-stopC:
+			return
+		}
+	}
+}
+
+// expire remove the txs added before now - SequenceTxExpiry from the pool and forget them
+func (pool *sequenceTxPool) expire(now time.Time) {
+	var expired []*commonPb.Transaction
+	for key, entry := range pool.txs {
+		if now.Sub(entry.addedAt) >= SequenceTxExpiry {
+			expired = append(expired, entry.tx)
+			delete(pool.txs, key)
+		}
+	}
+	if len(expired) > 0 {
+		pool.TxPool.RetryAndRemoveTxs(nil, expired)
+		pool.log.Infof("%d sequenced txs expire and are removed from tx pool", len(expired))
+	}
+}
+
+// senderSequenceKey the sender of the tx is identified by its member, the same sender signing by
+// cert and by cert hash is not matched
+func senderSequenceKey(tx *commonPb.Transaction) string {
+	signer := tx.GetSender().GetSigner()
+	return signer.GetOrgId() + "#" + signer.GetMemberType().String() + "#" +
+		hex.EncodeToString(signer.GetMemberInfo()) + "#" + strconv.FormatUint(tx.Payload.Sequence, 10)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txpool
+
+import (
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker-go/module/core/common/scheduler"
+	"chainmaker.org/chainmaker/logger/v2"
+	acPb "chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	configPb "chainmaker.org/chainmaker/pb-go/v2/config"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"chainmaker.org/chainmaker/protocol/v2"
+	"chainmaker.org/chainmaker/protocol/v2/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const blockVersion2360 = uint32(2030600)
+
+func newSequencedTx(txId string, sequence, gasLimit uint64) *commonPb.Transaction {
+	return &commonPb.Transaction{
+		Payload: &commonPb.Payload{
+			ChainId:      "chain1",
+			TxType:       commonPb.TxType_INVOKE_CONTRACT,
+			TxId:         txId,
+			ContractName: "contract1",
+			Method:       "method",
+			Sequence:     sequence,
+			Limit:        &commonPb.Limit{GasLimit: gasLimit},
+		},
+		Sender: &commonPb.EndorsementEntry{
+			Signer: &acPb.Member{
+				OrgId:      "org1",
+				MemberType: acPb.MemberType_PUBLIC_KEY,
+				MemberInfo: []byte("pk1"),
+			},
+		},
+	}
+}
+
+// newTestSequenceTxPool wraps a mock pool holding the added txs, the next expected sequence of the sender is 3
+func newTestSequenceTxPool(t *testing.T, blockVersion uint32) (*sequenceTxPool, map[string]*commonPb.Transaction,
+	*[]*commonPb.Transaction) {
+	ctrl := gomock.NewController(t)
+
+	inner := make(map[string]*commonPb.Transaction)
+	var removed []*commonPb.Transaction
+	txPool := mock.NewMockTxPool(ctrl)
+	txPool.EXPECT().AddTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(tx *commonPb.Transaction, source protocol.TxSource) error {
+			inner[tx.Payload.TxId] = tx
+			return nil
+		}).AnyTimes()
+	txPool.EXPECT().GetTxsInPoolByTxIds(gomock.Any()).DoAndReturn(
+		func(txIds []string) ([]*commonPb.Transaction, []string, error) {
+			var txs []*commonPb.Transaction
+			var missing []string
+			for _, txId := range txIds {
+				if tx, ok := inner[txId]; ok {
+					txs = append(txs, tx)
+				} else {
+					missing = append(missing, txId)
+				}
+			}
+			return txs, missing, nil
+		}).AnyTimes()
+	txPool.EXPECT().RetryAndRemoveTxs(gomock.Any(), gomock.Any()).Do(
+		func(retryTxs, removeTxs []*commonPb.Transaction) {
+			for _, tx := range removeTxs {
+				delete(inner, tx.Payload.TxId)
+				removed = append(removed, tx)
+			}
+		}).AnyTimes()
+
+	chainConf := mock.NewMockChainConf(ctrl)
+	chainConf.EXPECT().ChainConfig().Return(&configPb.ChainConfig{BlockVersion: blockVersion}).AnyTimes()
+	ac := mock.NewMockAccessControlProvider(ctrl)
+	ac.EXPECT().GetAddressFromCache(gomock.Any()).Return("sender1", nil, nil).AnyTimes()
+	store := mock.NewMockBlockchainStore(ctrl)
+	store.EXPECT().ReadObject(syscontract.SystemContract_ACCOUNT_MANAGER.String(),
+		[]byte(scheduler.SenderSequencePrefix+"sender1")).Return([]byte("3"), nil).AnyTimes()
+
+	pool := NewSequenceTxPool(txPool, chainConf, store, ac, logger.GetLogger("test")).(*sequenceTxPool)
+	return pool, inner, &removed
+}
+
+func TestSequenceTxPool_AddTx(t *testing.T) {
+	tests := []struct {
+		name         string
+		blockVersion uint32
+		source       protocol.TxSource
+		sequence     uint64
+		wantErr      bool
+		wantTracked  bool
+	}{
+		{name: "next expected", blockVersion: blockVersion2360, source: protocol.RPC, sequence: 3,
+			wantTracked: true},
+		{name: "within gap", blockVersion: blockVersion2360, source: protocol.RPC,
+			sequence: 3 + MaxSequenceGap - 1, wantTracked: true},
+		{name: "used sequence", blockVersion: blockVersion2360, source: protocol.RPC, sequence: 2, wantErr: true},
+		{name: "too far ahead", blockVersion: blockVersion2360, source: protocol.RPC, sequence: 3 + MaxSequenceGap,
+			wantErr: true},
+		{name: "not sequenced", blockVersion: blockVersion2360, source: protocol.RPC, sequence: 0},
+		{name: "from other nodes", blockVersion: blockVersion2360, source: protocol.P2P, sequence: 2},
+		{name: "old block version", blockVersion: uint32(2030500), source: protocol.RPC, sequence: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, inner, _ := newTestSequenceTxPool(t, tt.blockVersion)
+			err := pool.AddTx(newSequencedTx("tx1", tt.sequence, 100), tt.source)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			_, added := inner["tx1"]
+			assert.Equal(t, !tt.wantErr, added)
+			assert.Equal(t, tt.wantTracked, len(pool.txs) == 1)
+		})
+	}
+}
+
+func TestSequenceTxPool_Replace(t *testing.T) {
+	pool, inner, removed := newTestSequenceTxPool(t, blockVersion2360)
+
+	tx1 := newSequencedTx("tx1", 3, 100)
+	assert.Nil(t, pool.AddTx(tx1, protocol.RPC))
+
+	tests := []struct {
+		name    string
+		tx      *commonPb.Transaction
+		wantErr bool
+	}{
+		{name: "same gas limit", tx: newSequencedTx("tx2", 3, 100), wantErr: true},
+		{name: "bump too small", tx: newSequencedTx("tx2", 3, 109), wantErr: true},
+		{name: "other sequence", tx: newSequencedTx("tx3", 4, 10)},
+		{name: "bump enough", tx: newSequencedTx("tx4", 3, 110)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pool.AddTx(tt.tx, protocol.RPC)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+	// the replaced tx leaves the pool
+	assert.Equal(t, []*commonPb.Transaction{tx1}, *removed)
+	assert.NotContains(t, inner, "tx1")
+	assert.Contains(t, inner, "tx4")
+
+	// the tx of the sequence left the pool, the next one needs no bump
+	delete(inner, "tx4")
+	assert.Nil(t, pool.AddTx(newSequencedTx("tx5", 3, 10), protocol.RPC))
+	assert.Contains(t, inner, "tx5")
+}
+
+func TestSequenceTxPool_Expire(t *testing.T) {
+	pool, inner, removed := newTestSequenceTxPool(t, blockVersion2360)
+	tx1 := newSequencedTx("tx1", 3, 100)
+	tx2 := newSequencedTx("tx2", 4, 100)
+	assert.Nil(t, pool.AddTx(tx1, protocol.RPC))
+	assert.Nil(t, pool.AddTx(tx2, protocol.RPC))
+	pool.txs[senderSequenceKey(tx1)].addedAt = time.Now().Add(-SequenceTxExpiry)
+
+	pool.expire(time.Now())
+	assert.Equal(t, []*commonPb.Transaction{tx1}, *removed)
+	assert.NotContains(t, inner, "tx1")
+	assert.Len(t, pool.txs, 1)
+
+	// the txs which left the pool are forgotten by prune
+	delete(inner, "tx2")
+	pool.prune()
+	assert.Empty(t, pool.txs)
+	assert.Equal(t, minSequencePruneSize, pool.pruneAt)
+}
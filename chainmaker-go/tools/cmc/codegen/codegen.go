@@ -0,0 +1,151 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"chainmaker.org/chainmaker-go/tools/cmc/util"
+	sdkutils "chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// contractSchemaMethod query method answered by contract-sdk-go sandbox with the contract schema
+const contractSchemaMethod = "__contract_schema__"
+
+var (
+	abiFilePath    string
+	schemaFilePath string
+	contractName   string
+	sdkConfPath    string
+	packageName    string
+	typeName       string
+	outputPath     string
+)
+
+const (
+	flagAbiFilePath    = "abi-file-path"
+	flagSchemaFilePath = "schema-file-path"
+	flagContractName   = "contract-name"
+	flagSdkConfPath    = "sdk-conf-path"
+	flagPackage        = "package"
+	flagType           = "type"
+	flagOutput         = "output"
+)
+
+// NewCodegenCMD new codegen command
+func NewCodegenCMD() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "codegen",
+		Short: "generate typed go client of contract",
+		Long: `generate typed go client of contract from the abi of evm contract, or from the method and event
+schemas of contract-sdk-go contract, the schemas are read from --schema-file-path or queried from the contract
+named --contract-name on chain`,
+		Example: `cmc codegen --abi-file-path=./token.abi --package=token --type=Token --output=./token/token.go
+cmc codegen --schema-file-path=./fact.json --package=fact --type=Fact --output=./fact/fact.go
+cmc codegen --contract-name=fact --sdk-conf-path=./sdk_config.yml --package=fact --output=./fact/fact.go`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runCodegen()
+		},
+	}
+
+	util.AttachFlags(cmd, flags, []string{
+		flagAbiFilePath, flagSchemaFilePath, flagContractName, flagSdkConfPath, flagPackage, flagType, flagOutput,
+	})
+	return cmd
+}
+
+var flags *pflag.FlagSet
+
+func init() {
+	flags = &pflag.FlagSet{}
+
+	flags.StringVar(&abiFilePath, flagAbiFilePath, "", "specify EVM contract abi file path, eg: ./token.abi")
+	flags.StringVar(&schemaFilePath, flagSchemaFilePath, "",
+		"specify contract schema file path, the json answered by method "+contractSchemaMethod)
+	flags.StringVar(&contractName, flagContractName, "",
+		"specify contract name to query contract schema from chain if no file path is specified")
+	flags.StringVar(&sdkConfPath, flagSdkConfPath, "", "specify sdk config path")
+	flags.StringVar(&packageName, flagPackage, "contract", "specify package name of generated file")
+	flags.StringVar(&typeName, flagType, "", "specify client type name, default to the camel case of contract name")
+	flags.StringVar(&outputPath, flagOutput, "", "specify output file path, print to stdout if not specified")
+
+	if sdkConfPath == "" {
+		sdkConfPath = util.EnvSdkConfPath
+	}
+}
+
+func runCodegen() error {
+	opts := Options{Package: packageName, Type: typeName}
+	if opts.Type == "" {
+		opts.Type = exportedName(contractName, exportedName(packageName, "Contract"))
+	}
+
+	var (
+		input    []byte
+		err      error
+		generate = GenerateNative
+	)
+	switch {
+	case abiFilePath != "":
+		input, err = ioutil.ReadFile(abiFilePath)
+		opts.Source = filepath.Base(abiFilePath)
+		generate = GenerateEVM
+	case schemaFilePath != "":
+		input, err = ioutil.ReadFile(schemaFilePath)
+		opts.Source = filepath.Base(schemaFilePath)
+	case contractName != "":
+		input, err = queryContractSchema()
+		opts.Source = "contract " + contractName
+	default:
+		return errors.New("one of abi-file-path, schema-file-path and contract-name must be set")
+	}
+	if err != nil {
+		return err
+	}
+	code, err := generate(input, opts)
+	if err != nil {
+		return err
+	}
+
+	if outputPath == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(outputPath), 0750); err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(outputPath, code, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("generated %s\n", outputPath)
+	return nil
+}
+
+// queryContractSchema query the schema of contract-sdk-go contract from chain
+func queryContractSchema() ([]byte, error) {
+	cc, err := util.CreateChainClientWithConfPath(sdkConfPath, false)
+	if err != nil {
+		return nil, err
+	}
+	defer cc.Stop()
+
+	resp, err := cc.QueryContract(contractName, contractSchemaMethod, nil, -1)
+	if err != nil {
+		return nil, err
+	}
+	if err = sdkutils.CheckProposalRequestResp(resp, true); err != nil {
+		return nil, fmt.Errorf("query schema of contract %s failed, %s", contractName, err)
+	}
+	return resp.ContractResult.Result, nil
+}
@@ -0,0 +1,587 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package codegen
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"chainmaker.org/chainmaker/common/v2/evmutils"
+)
+
+// contract kinds of generated client
+const (
+	kindEVM    = "evm"
+	kindNative = "native"
+)
+
+// field types of contract-sdk-go method params, results and events
+const (
+	typeString  = "string"
+	typeAddress = "address"
+	typeBool    = "bool"
+	typeInt     = "int"
+	typeUint    = "uint"
+	typeUint256 = "uint256"
+	typeBytes   = "bytes"
+	typeJSON    = "json"
+)
+
+// Options options of generator
+type Options struct {
+	// Package package name of generated file
+	Package string
+	// Type name of generated client type
+	Type string
+	// Source the input file name, written in the header of generated file
+	Source string
+}
+
+// ContractSchema method and event schemas of a contract-sdk-go contract, the json is answered by
+// the sandbox to the query method __contract_schema__, it can also be written by hand for native contracts
+// taking key value params
+type ContractSchema struct {
+	Methods []*MethodSchema `json:"methods"`
+	Events  []*EventSchema  `json:"events"`
+}
+
+// MethodSchema method of contract
+type MethodSchema struct {
+	Name    string         `json:"name"`
+	Kind    string         `json:"kind"`
+	Params  []*FieldSchema `json:"params"`
+	Returns string         `json:"returns,omitempty"`
+}
+
+// EventSchema event of contract
+type EventSchema struct {
+	Name   string         `json:"name"`
+	Fields []*FieldSchema `json:"fields"`
+}
+
+// FieldSchema method param or event field
+type FieldSchema struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed,omitempty"`
+}
+
+// abiEntry an entry of evm abi json
+type abiEntry struct {
+	Type            string         `json:"type"`
+	Name            string         `json:"name"`
+	Inputs          []*abiArgument `json:"inputs"`
+	Outputs         []*abiArgument `json:"outputs"`
+	StateMutability string         `json:"stateMutability"`
+	Constant        bool           `json:"constant"`
+	Anonymous       bool           `json:"anonymous"`
+}
+
+type abiArgument struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Indexed    bool           `json:"indexed"`
+	Components []*abiArgument `json:"components"`
+}
+
+// contractModel is rendered by the templates
+type contractModel struct {
+	Options
+	Kind    string
+	ABI     string
+	Methods []*methodModel
+	Events  []*eventModel
+	// NeedJSON whether encoding/json is imported by native client
+	NeedJSON bool
+}
+
+type methodModel struct {
+	// Name method name of contract
+	Name string
+	// PackName name of method in parsed abi, overloaded evm functions are renamed
+	PackName string
+	GoName   string
+	Query    bool
+	Params   []*fieldModel
+	// Results evm outputs, or the single native result
+	Results []*fieldModel
+	// ResultType go type of result, empty if the method has no decoded result
+	ResultType string
+	// ResultStruct name of struct holding multiple evm outputs
+	ResultStruct string
+}
+
+type eventModel struct {
+	Name       string
+	StructName string
+	Topic      string
+	Fields     []*fieldModel
+	Indexed    int
+	NonIndexed int
+}
+
+type fieldModel struct {
+	Name    string
+	GoName  string
+	GoType  string
+	Type    string
+	Indexed bool
+	// Index position of the value in unpacked evm values or topics
+	Index int
+}
+
+// names used by generated code, params are renamed when conflicting
+var reservedNames = map[string]bool{
+	"c": true, "kvs": true, "resp": true, "err": true, "result": true, "values": true, "data": true,
+	"event": true, "e": true, "ok": true, "topics": true,
+}
+
+// GenerateEVM generate typed client of evm contract from its abi json
+// @param abiJSON
+// @param opts
+// @return []byte
+// @return error
+func GenerateEVM(abiJSON []byte, opts Options) ([]byte, error) {
+	var entries []*abiEntry
+	if err := json.Unmarshal(abiJSON, &entries); err != nil {
+		return nil, fmt.Errorf("unmarshal abi failed, %s", err)
+	}
+	compacted := new(bytes.Buffer)
+	if err := json.Compact(compacted, abiJSON); err != nil {
+		return nil, err
+	}
+
+	model := &contractModel{Options: opts, Kind: kindEVM, ABI: compacted.String()}
+	methodNames := make(map[string]bool)
+	eventNames := make(map[string]bool)
+	for _, entry := range entries {
+		switch entry.Type {
+		case "function", "":
+			method := &methodModel{
+				Name:     entry.Name,
+				PackName: resolveNameConflict(entry.Name, methodNames),
+				Query: entry.Constant || entry.StateMutability == "view" ||
+					entry.StateMutability == "pure",
+			}
+			method.Params = evmFields(entry.Inputs, "arg")
+			method.Results = evmFields(entry.Outputs, "out")
+			model.Methods = append(model.Methods, method)
+		case "event":
+			if entry.Anonymous {
+				// anonymous events have no topic to be recognized by
+				continue
+			}
+			event := &eventModel{
+				Name:   resolveNameConflict(entry.Name, eventNames),
+				Topic:  hex.EncodeToString(evmutils.Keccak256([]byte(evmSignature(entry.Name, entry.Inputs)))),
+				Fields: evmFields(entry.Inputs, "field"),
+			}
+			for _, field := range event.Fields {
+				if field.Indexed {
+					field.Index = event.Indexed
+					event.Indexed++
+					if !isStaticTopicType(field.Type) {
+						// dynamic values are indexed by their hashes
+						field.GoType = "[32]byte"
+					}
+				} else {
+					field.Index = event.NonIndexed
+					event.NonIndexed++
+				}
+			}
+			model.Events = append(model.Events, event)
+		}
+	}
+	return render(model)
+}
+
+// GenerateNative generate typed client of contract-sdk-go or native contract from its schema json
+// @param schemaJSON
+// @param opts
+// @return []byte
+// @return error
+func GenerateNative(schemaJSON []byte, opts Options) ([]byte, error) {
+	var schema ContractSchema
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, fmt.Errorf("unmarshal contract schema failed, %s", err)
+	}
+
+	model := &contractModel{Options: opts, Kind: kindNative}
+	for _, method := range schema.Methods {
+		if method.Kind != "invoke" && method.Kind != "query" {
+			return nil, fmt.Errorf("method [%s] has unknown kind [%s]", method.Name, method.Kind)
+		}
+		m := &methodModel{Name: method.Name, PackName: method.Name, Query: method.Kind == "query"}
+		for i, param := range method.Params {
+			field, err := nativeField(param, "arg", i)
+			if err != nil {
+				return nil, fmt.Errorf("method [%s]: %s", method.Name, err)
+			}
+			m.Params = append(m.Params, field)
+		}
+		uniqueGoNames(m.Params)
+		if method.Returns == "" && m.Query {
+			// queries without declared result return the raw payload
+			method.Returns = typeBytes
+		}
+		if method.Returns != "" {
+			result, err := nativeField(&FieldSchema{Name: "result", Type: method.Returns}, "result", 0)
+			if err != nil {
+				return nil, fmt.Errorf("method [%s]: %s", method.Name, err)
+			}
+			m.Results = []*fieldModel{result}
+		}
+		model.Methods = append(model.Methods, m)
+	}
+	for _, event := range schema.Events {
+		e := &eventModel{Name: event.Name}
+		for i, field := range event.Fields {
+			f, err := nativeField(field, "field", i)
+			if err != nil {
+				return nil, fmt.Errorf("event [%s]: %s", event.Name, err)
+			}
+			e.Fields = append(e.Fields, f)
+		}
+		model.Events = append(model.Events, e)
+	}
+	for _, method := range model.Methods {
+		for _, field := range append(method.Params, method.Results...) {
+			model.NeedJSON = model.NeedJSON || field.Type == typeJSON
+		}
+	}
+	for _, event := range model.Events {
+		for _, field := range event.Fields {
+			model.NeedJSON = model.NeedJSON || field.Type == typeJSON
+		}
+	}
+	return render(model)
+}
+
+func render(model *contractModel) ([]byte, error) {
+	if !token.IsIdentifier(model.Package) {
+		return nil, fmt.Errorf("invalid package name [%s]", model.Package)
+	}
+	model.Type = exportedName(model.Type, "Contract")
+
+	// methods of the generated client type
+	goNames := map[string]bool{"SetTimeout": true, "SetSyncResult": true, "ContractName": true}
+	for _, method := range model.Methods {
+		method.GoName = uniqueName(exportedName(method.PackName, "Method"), goNames)
+		if method.PackName == "" {
+			method.PackName = method.Name
+		}
+		switch {
+		case len(method.Results) == 1:
+			method.ResultType = method.Results[0].GoType
+		case len(method.Results) > 1:
+			method.ResultStruct = model.Type + method.GoName + "Result"
+			method.ResultType = "*" + method.ResultStruct
+			for i, result := range method.Results {
+				result.GoName = exportedName(result.Name, fmt.Sprintf("Out%d", i))
+			}
+			uniqueGoNames(method.Results)
+		}
+	}
+	for _, event := range model.Events {
+		event.StructName = model.Type + exportedName(event.Name, "Event") + "Event"
+		for i, field := range event.Fields {
+			field.GoName = exportedName(field.Name, fmt.Sprintf("Field%d", i))
+		}
+		uniqueGoNames(event.Fields)
+	}
+	sort.SliceStable(model.Events, func(i, j int) bool { return model.Events[i].Name < model.Events[j].Name })
+
+	tmpl := nativeTemplate
+	if model.Kind == kindEVM {
+		tmpl = evmTemplate
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, model); err != nil {
+		return nil, err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code failed, %s", err)
+	}
+	return code, nil
+}
+
+func evmFields(args []*abiArgument, prefix string) []*fieldModel {
+	fields := make([]*fieldModel, 0, len(args))
+	for i, arg := range args {
+		fields = append(fields, &fieldModel{
+			Name:    arg.Name,
+			GoName:  paramName(arg.Name, fmt.Sprintf("%s%d", prefix, i)),
+			GoType:  evmGoType(arg.Type),
+			Type:    arg.Type,
+			Indexed: arg.Indexed,
+			Index:   i,
+		})
+	}
+	uniqueGoNames(fields)
+	return fields
+}
+
+var (
+	evmArrayPattern = regexp.MustCompile(`^(.+)\[(\d*)\]$`)
+	evmIntPattern   = regexp.MustCompile(`^u?int(\d*)$`)
+	evmBytesPattern = regexp.MustCompile(`^bytes(\d+)$`)
+)
+
+// evmGoType the go type of abi type packed and unpacked by abi, tuples are left as interface{}
+func evmGoType(abiType string) string {
+	if matches := evmArrayPattern.FindStringSubmatch(abiType); matches != nil {
+		elem := evmGoType(matches[1])
+		if elem == "interface{}" {
+			return elem
+		}
+		return "[" + matches[2] + "]" + elem
+	}
+	switch abiType {
+	case "address":
+		return "evmutils.Address"
+	case "bool", "string":
+		return abiType
+	case "bytes":
+		return "[]byte"
+	}
+	if matches := evmIntPattern.FindStringSubmatch(abiType); matches != nil {
+		switch matches[1] {
+		case "8", "16", "32", "64":
+			return abiType
+		}
+		return "*big.Int"
+	}
+	if matches := evmBytesPattern.FindStringSubmatch(abiType); matches != nil {
+		return "[" + matches[1] + "]byte"
+	}
+	return "interface{}"
+}
+
+// isStaticTopicType whether the indexed value is stored in topic as is instead of its hash
+func isStaticTopicType(abiType string) bool {
+	return abiType == "address" || abiType == "bool" || evmIntPattern.MatchString(abiType) ||
+		evmBytesPattern.MatchString(abiType)
+}
+
+// evmSignature the canonical signature hashed into topic of event
+func evmSignature(name string, args []*abiArgument) string {
+	types := make([]string, 0, len(args))
+	for _, arg := range args {
+		types = append(types, canonicalABIType(arg))
+	}
+	return name + "(" + strings.Join(types, ",") + ")"
+}
+
+func canonicalABIType(arg *abiArgument) string {
+	if strings.HasPrefix(arg.Type, "tuple") {
+		types := make([]string, 0, len(arg.Components))
+		for _, component := range arg.Components {
+			types = append(types, canonicalABIType(component))
+		}
+		return "(" + strings.Join(types, ",") + ")" + strings.TrimPrefix(arg.Type, "tuple")
+	}
+	switch {
+	case arg.Type == "uint" || strings.HasPrefix(arg.Type, "uint["):
+		return "uint256" + strings.TrimPrefix(arg.Type, "uint")
+	case arg.Type == "int" || strings.HasPrefix(arg.Type, "int["):
+		return "int256" + strings.TrimPrefix(arg.Type, "int")
+	}
+	return arg.Type
+}
+
+// resolveNameConflict renames overloaded names the same way as abi does
+func resolveNameConflict(name string, used map[string]bool) string {
+	resolved := name
+	for i := 0; used[resolved]; i++ {
+		resolved = name + strconv.Itoa(i)
+	}
+	used[resolved] = true
+	return resolved
+}
+
+func nativeField(field *FieldSchema, prefix string, index int) (*fieldModel, error) {
+	goType, ok := nativeGoTypes[field.Type]
+	if !ok {
+		return nil, fmt.Errorf("field [%s] has unknown type [%s]", field.Name, field.Type)
+	}
+	return &fieldModel{
+		Name:    field.Name,
+		GoName:  paramName(field.Name, fmt.Sprintf("%s%d", prefix, index)),
+		GoType:  goType,
+		Type:    field.Type,
+		Indexed: field.Indexed,
+		Index:   index,
+	}, nil
+}
+
+// nativeGoTypes go types of contract-sdk-go field types
+var nativeGoTypes = map[string]string{
+	typeString:  "string",
+	typeAddress: "string",
+	typeBool:    "bool",
+	typeInt:     "int64",
+	typeUint:    "uint64",
+	typeUint256: "*big.Int",
+	typeBytes:   "[]byte",
+	typeJSON:    "json.RawMessage",
+}
+
+// exportedName converts name like "file_hash" or "_value" into "FileHash" and "Value"
+func exportedName(name, fallback string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var builder strings.Builder
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	exported := builder.String()
+	if exported == "" {
+		return fallback
+	}
+	if unicode.IsDigit([]rune(exported)[0]) {
+		exported = fallback + exported
+	}
+	return exported
+}
+
+// paramName converts name into an unexported identifier not used by generated code
+func paramName(name, fallback string) string {
+	exported := exportedName(name, "")
+	if exported == "" {
+		return fallback
+	}
+	runes := []rune(exported)
+	runes[0] = unicode.ToLower(runes[0])
+	unexported := string(runes)
+	if !unicode.IsLetter(runes[0]) || token.IsKeyword(unexported) || reservedNames[unexported] ||
+		isPredeclared(unexported) {
+		return unexported + "Param"
+	}
+	return unexported
+}
+
+func isPredeclared(name string) bool {
+	switch name {
+	case "bool", "byte", "error", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16",
+		"uint32", "uint64", "string", "rune", "len", "cap", "make", "new", "append", "copy", "nil", "true",
+		"false", "big", "json", "hex", "fmt", "strings", "strconv", "common", "sdk", "sdkutils", "abi",
+		"evmutils":
+		return true
+	}
+	return false
+}
+
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 1; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+func uniqueGoNames(fields []*fieldModel) {
+	used := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		field.GoName = uniqueName(field.GoName, used)
+	}
+}
+
+var templateFuncs = template.FuncMap{
+	"quote": strconv.Quote,
+	// encode returns the expression encoding a native param into bytes
+	"encode": func(field *fieldModel) string {
+		switch field.Type {
+		case typeBool:
+			return "[]byte(strconv.FormatBool(" + field.GoName + "))"
+		case typeInt:
+			return "[]byte(strconv.FormatInt(" + field.GoName + ", 10))"
+		case typeUint:
+			return "[]byte(strconv.FormatUint(" + field.GoName + ", 10))"
+		case typeUint256:
+			return "[]byte(" + field.GoName + ".String())"
+		case typeBytes, typeJSON:
+			return field.GoName
+		default:
+			return "[]byte(" + field.GoName + ")"
+		}
+	},
+	// decode returns the function decoding a native result
+	"decode": func(field *fieldModel) string {
+		switch field.Type {
+		case typeBool:
+			return "decodeBool"
+		case typeInt:
+			return "decodeInt"
+		case typeUint:
+			return "decodeUint"
+		case typeUint256:
+			return "decodeUint256"
+		case typeBytes, typeJSON:
+			return "decodeBytes"
+		default:
+			return "decodeString"
+		}
+	},
+	// topic returns the expression decoding an indexed evm field from its topic
+	"topic": func(field *fieldModel) string {
+		expr := fmt.Sprintf("topics[%d]", field.Index)
+		switch {
+		case field.GoType == "[32]byte":
+			return expr
+		case field.Type == "address":
+			return "evmTopicAddress(" + expr + ")"
+		case field.Type == "bool":
+			return expr + "[31] == 1"
+		case field.GoType == "*big.Int":
+			if strings.HasPrefix(field.Type, "u") {
+				return "new(big.Int).SetBytes(" + expr + "[:])"
+			}
+			return "evmTopicInt(" + expr + ")"
+		case evmIntPattern.MatchString(field.Type):
+			if strings.HasPrefix(field.Type, "u") {
+				return field.GoType + "(new(big.Int).SetBytes(" + expr + "[:]).Uint64())"
+			}
+			return field.GoType + "(evmTopicInt(" + expr + ").Int64())"
+		default:
+			// bytesN is left aligned in topic
+			return "evmTopicBytes" + strings.TrimPrefix(field.Type, "bytes") + "(" + expr + ")"
+		}
+	},
+	// bytesSizes returns the sizes of bytesN fields indexed by events
+	"bytesSizes": func(events []*eventModel) []string {
+		sizes := make(map[string]bool)
+		for _, event := range events {
+			for _, field := range event.Fields {
+				if field.Indexed && evmBytesPattern.MatchString(field.Type) && field.Type != "bytes32" {
+					sizes[strings.TrimPrefix(field.Type, "bytes")] = true
+				}
+			}
+		}
+		sorted := make([]string, 0, len(sizes))
+		for size := range sizes {
+			sorted = append(sorted, size)
+		}
+		sort.Strings(sorted)
+		return sorted
+	},
+}
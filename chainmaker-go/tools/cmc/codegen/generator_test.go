@@ -0,0 +1,103 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package codegen
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const factSchema = `{
+  "methods": [
+    {"name": "save", "kind": "invoke", "returns": "string", "params": [
+      {"name": "file_hash", "type": "string"}, {"name": "time", "type": "int"}, {"name": "type", "type": "uint256"}
+    ]},
+    {"name": "findByFileHash", "kind": "query", "returns": "json", "params": [{"name": "file_hash", "type": "string"}]},
+    {"name": "raw", "kind": "query", "params": []}
+  ],
+  "events": [
+    {"name": "topic_vx", "fields": [{"name": "file_hash", "type": "string", "indexed": true},
+      {"name": "amount", "type": "uint256"}]}
+  ]
+}`
+
+func TestGenerateEVM(t *testing.T) {
+	abiJSON, err := ioutil.ReadFile("../testdata/token-evm-demo/token.abi")
+	require.Nil(t, err)
+
+	code, err := GenerateEVM(abiJSON, Options{Package: "token", Type: "token", Source: "token.abi"})
+	require.Nil(t, err)
+	for _, expected := range []string{
+		"// source: token.abi",
+		"package token",
+		"func NewToken(cc *sdk.ChainClient, contractName string) (*Token, error) {",
+		"func (c *Token) Transfer(to evmutils.Address, value *big.Int) (result bool, resp *common.TxResponse, err error) {",
+		"func (c *Token) BalanceOf(arg0 evmutils.Address) (result *big.Int, err error) {",
+		`resp, err := c.cc.QueryContract(c.contractName, "balanceOf", kvs, c.timeout)`,
+		`const TokenTransferEventTopic = "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"`,
+		"e.From = evmTopicAddress(topics[0])",
+		"if e.Value, ok = values[0].(*big.Int); !ok {",
+	} {
+		require.Contains(t, string(code), expected)
+	}
+}
+
+func TestGenerateEVMOverloads(t *testing.T) {
+	abiJSON := `[
+	  {"type": "function", "name": "get", "stateMutability": "view", "inputs": [{"name": "id", "type": "uint8"}],
+	   "outputs": [{"name": "owner", "type": "address"}, {"name": "", "type": "bytes32[2]"}]},
+	  {"type": "function", "name": "get", "inputs": [{"name": "type", "type": "string"}], "outputs": []},
+	  {"type": "function", "name": "contractName", "constant": true, "inputs": [],
+	   "outputs": [{"name": "", "type": "string"}]},
+	  {"type": "event", "name": "Hashed", "inputs": [{"name": "s", "type": "string", "indexed": true},
+	   {"name": "n", "type": "int32", "indexed": true}]}
+	]`
+	code, err := GenerateEVM([]byte(abiJSON), Options{Package: "rich", Type: "Rich"})
+	require.Nil(t, err)
+	for _, expected := range []string{
+		"func (c *Rich) Get(id uint8) (result *RichGetResult, err error) {",
+		"\tOwner evmutils.Address\n",
+		"\tOut1  [2][32]byte\n",
+		"func (c *Rich) Get0(typeParam string) (resp *common.TxResponse, err error) {",
+		`data, err := c.abi.Pack("get0", typeParam)`,
+		"func (c *Rich) ContractName1() (result string, err error) {",
+		"e.S = topics[0]",
+		"e.N = int32(evmTopicInt(topics[1]).Int64())",
+	} {
+		require.Contains(t, string(code), expected)
+	}
+
+	_, err = GenerateEVM([]byte(abiJSON), Options{Package: "1rich"})
+	require.NotNil(t, err)
+}
+
+func TestGenerateNative(t *testing.T) {
+	code, err := GenerateNative([]byte(factSchema), Options{Package: "fact", Type: "Fact"})
+	require.Nil(t, err)
+	for _, expected := range []string{
+		"func NewFact(cc *sdk.ChainClient, contractName string) *Fact {",
+		"func (c *Fact) Save(fileHash string, time int64, typeParam *big.Int) " +
+			"(result string, resp *common.TxResponse, err error) {",
+		`{Key: "time", Value: []byte(strconv.FormatInt(time, 10))},`,
+		`{Key: "type", Value: []byte(typeParam.String())},`,
+		"func (c *Fact) FindByFileHash(fileHash string) (result json.RawMessage, err error) {",
+		"func (c *Fact) Raw() (result []byte, err error) {",
+		"FileHash string   `event:\"file_hash\"`",
+		"func (c *Fact) DecodeFactTopicVxEvent(event *common.ContractEventInfo) (*FactTopicVxEvent, error) {",
+	} {
+		require.Contains(t, string(code), expected)
+	}
+
+	_, err = GenerateNative([]byte(`{"methods": [{"name": "save", "kind": "invoke",
+		"params": [{"name": "a", "type": "float"}]}]}`), Options{Package: "fact"})
+	require.NotNil(t, err)
+	_, err = GenerateNative([]byte(`{"methods": [{"name": "save", "kind": "call"}]}`), Options{Package: "fact"})
+	require.NotNil(t, err)
+}
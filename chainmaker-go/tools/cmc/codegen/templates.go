@@ -0,0 +1,345 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package codegen
+
+import "text/template"
+
+const headerTemplate = `// Code generated by cmc codegen. DO NOT EDIT.
+{{- if .Source}}
+// source: {{.Source}}
+{{- end}}
+
+package {{.Package}}
+`
+
+const clientTemplate = `
+// {{.Type}} typed client of contract
+type {{.Type}} struct {
+	cc             *sdk.ChainClient
+	contractName   string
+	timeout        int64
+	withSyncResult bool
+{{- if eq .Kind "evm"}}
+	abi            *abi.ABI
+{{- end}}
+}
+
+// SetTimeout set the timeout of sending tx and querying, -1 for the default timeout of sdk
+func (c *{{.Type}}) SetTimeout(timeout int64) {
+	c.timeout = timeout
+}
+
+// SetSyncResult set whether invoking waits for the tx result, results are decoded only when it is set
+func (c *{{.Type}}) SetSyncResult(withSyncResult bool) {
+	c.withSyncResult = withSyncResult
+}
+
+// ContractName the name of contract called by client
+func (c *{{.Type}}) ContractName() string {
+	return c.contractName
+}
+`
+
+var nativeTemplate = template.Must(template.New("native").Funcs(templateFuncs).Parse(headerTemplate + `
+import (
+{{- if .NeedJSON}}
+	"encoding/json"
+{{- end}}
+	"fmt"
+	"math/big"
+	"strconv"
+{{if or .Methods .Events}}
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+{{- end}}
+	sdk "chainmaker.org/chainmaker/sdk-go/v2"
+{{- if .Methods}}
+	sdkutils "chainmaker.org/chainmaker/sdk-go/v2/utils"
+{{- end}}
+)
+` + clientTemplate + `
+// New{{.Type}} create {{.Type}} client calling the contract named contractName
+func New{{.Type}}(cc *sdk.ChainClient, contractName string) *{{.Type}} {
+	return &{{.Type}}{
+		cc:             cc,
+		contractName:   contractName,
+		timeout:        -1,
+		withSyncResult: true,
+	}
+}
+{{range $method := .Methods}}
+{{- $result := ""}}{{if .Results}}{{$result = index .Results 0}}{{end}}
+// {{.GoName}} {{if .Query}}query{{else}}invoke{{end}} method {{.Name}}
+func (c *{{$.Type}}) {{.GoName}}(
+	{{- range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.GoName}} {{$p.GoType}}{{end -}}
+) ({{if $result}}result {{.ResultType}}, {{end}}{{if not .Query}}resp *common.TxResponse, {{end}}err error) {
+	kvs := []*common.KeyValuePair{
+{{- range .Params}}
+		{Key: {{quote .Name}}, Value: {{encode .}}},
+{{- end}}
+	}
+{{- if .Query}}
+	resp, err := c.cc.QueryContract(c.contractName, {{quote .Name}}, kvs, c.timeout)
+	if err != nil {
+		return
+	}
+	if err = sdkutils.CheckProposalRequestResp(resp, true); err != nil {
+		return
+	}
+{{- else}}
+	resp, err = c.cc.InvokeContract(c.contractName, {{quote .Name}}, "", kvs, c.timeout, c.withSyncResult)
+	if err != nil {
+		return
+	}
+	if err = sdkutils.CheckProposalRequestResp(resp, c.withSyncResult); err != nil || !c.withSyncResult {
+		return
+	}
+{{- end}}
+{{- if $result}}
+	result, err = {{decode $result}}(resp.ContractResult.Result)
+{{- end}}
+	return
+}
+{{end}}
+{{- if .Events}}
+// {{.Type}}EventSchemas event schemas of contract
+var {{.Type}}EventSchemas = sdk.EventSchemas{
+{{- range .Events}}
+	{{quote .Name}}: {Name: {{quote .Name}}, Fields: []*sdk.EventField{
+	{{- range .Fields}}
+		{Name: {{quote .Name}}, Type: {{quote .Type}}, Indexed: {{.Indexed}}},
+	{{- end}}
+	}},
+{{- end}}
+}
+{{end}}
+{{- range .Events}}
+// {{.StructName}} event {{.Name}}
+type {{.StructName}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`event:{{quote .Name}}`" + `
+{{- end}}
+}
+
+// Decode{{.StructName}} decode event {{.Name}}
+func (c *{{$.Type}}) Decode{{.StructName}}(event *common.ContractEventInfo) (*{{.StructName}}, error) {
+	if event == nil || event.Topic != {{quote .Name}} {
+		return nil, fmt.Errorf("event is not {{.Name}}")
+	}
+	e := &{{.StructName}}{}
+	if err := {{$.Type}}EventSchemas.Unmarshal(event, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+{{end}}
+func decodeString(data []byte) (string, error) {
+	return string(data), nil
+}
+
+func decodeBytes(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+func decodeBool(data []byte) (bool, error) {
+	return strconv.ParseBool(string(data))
+}
+
+func decodeInt(data []byte) (int64, error) {
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+func decodeUint(data []byte) (uint64, error) {
+	return strconv.ParseUint(string(data), 10, 64)
+}
+
+func decodeUint256(data []byte) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(string(data), 10)
+	if !ok {
+		return nil, fmt.Errorf("[%s] is not a valid uint256", data)
+	}
+	return n, nil
+}
+`))
+
+var evmTemplate = template.Must(template.New("evm").Funcs(templateFuncs).Parse(headerTemplate + `
+import (
+{{- if or .Methods .Events}}
+	"encoding/hex"
+{{- end}}
+	"fmt"
+	"math/big"
+	"strings"
+{{if or .Methods .Events}}
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+{{- end}}
+	"chainmaker.org/chainmaker/common/v2/evmutils"
+	"chainmaker.org/chainmaker/common/v2/evmutils/abi"
+	sdk "chainmaker.org/chainmaker/sdk-go/v2"
+{{- if .Methods}}
+	sdkutils "chainmaker.org/chainmaker/sdk-go/v2/utils"
+{{- end}}
+)
+
+// {{.Type}}ABI abi of contract
+const {{.Type}}ABI = {{quote .ABI}}
+` + clientTemplate + `
+// New{{.Type}} create {{.Type}} client calling the evm contract named contractName
+func New{{.Type}}(cc *sdk.ChainClient, contractName string) (*{{.Type}}, error) {
+	contractABI, err := abi.JSON(strings.NewReader({{.Type}}ABI))
+	if err != nil {
+		return nil, err
+	}
+	return &{{.Type}}{
+		cc:             cc,
+		contractName:   contractName,
+		timeout:        -1,
+		withSyncResult: true,
+		abi:            &contractABI,
+	}, nil
+}
+{{range .Methods}}
+{{- if .ResultStruct}}
+// {{.ResultStruct}} outputs of method {{.Name}}
+type {{.ResultStruct}} struct {
+{{- range .Results}}
+	{{.GoName}} {{.GoType}}
+{{- end}}
+}
+{{end}}
+{{- $method := .}}
+// {{.GoName}} {{if .Query}}query{{else}}invoke{{end}} method {{.Name}}
+func (c *{{$.Type}}) {{.GoName}}(
+	{{- range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.GoName}} {{$p.GoType}}{{end -}}
+) ({{if .ResultType}}result {{.ResultType}}, {{end}}{{if not .Query}}resp *common.TxResponse, {{end}}err error) {
+	data, err := c.abi.Pack({{quote .PackName}}{{range .Params}}, {{.GoName}}{{end}})
+	if err != nil {
+		return
+	}
+	kvs := []*common.KeyValuePair{{"{{"}}Key: "data", Value: []byte(hex.EncodeToString(data)){{"}}"}}
+{{- if .Query}}
+	resp, err := c.cc.QueryContract(c.contractName, {{quote .Name}}, kvs, c.timeout)
+	if err != nil {
+		return
+	}
+	if err = sdkutils.CheckProposalRequestResp(resp, true); err != nil {
+		return
+	}
+{{- else}}
+	resp, err = c.cc.InvokeContract(c.contractName, {{quote .Name}}, "", kvs, c.timeout, c.withSyncResult)
+	if err != nil {
+		return
+	}
+	if err = sdkutils.CheckProposalRequestResp(resp, c.withSyncResult); err != nil || !c.withSyncResult {
+		return
+	}
+{{- end}}
+{{- if .ResultType}}
+	values, err := c.abi.Unpack({{quote .PackName}}, resp.ContractResult.Result)
+	if err != nil {
+		return
+	}
+	if len(values) != {{len .Results}} {
+		err = fmt.Errorf("method {{.Name}} expects {{len .Results}} outputs, got %d", len(values))
+		return
+	}
+{{- if .ResultStruct}}
+	result = &{{.ResultStruct}}{}
+{{- end}}
+	var ok bool
+{{- range .Results}}
+	if {{if $method.ResultStruct}}result.{{.GoName}}{{else}}result{{end}}, ok = values[{{.Index}}].({{.GoType}}); !ok {
+		err = fmt.Errorf("output {{.Index}} of method {{$method.Name}} is %T, not {{.GoType}}", values[{{.Index}}])
+		return
+	}
+{{- end}}
+{{- end}}
+	return
+}
+{{end}}
+{{- range .Events}}
+// {{.StructName}}Topic topic of event {{.Name}}
+const {{.StructName}}Topic = {{quote .Topic}}
+
+// {{.StructName}} event {{.Name}}, indexed dynamic values are their keccak256 hashes
+type {{.StructName}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}}
+{{- end}}
+}
+
+// Decode{{.StructName}} decode event {{.Name}}, the event data are the hex of indexed topics followed by
+// the hex of abi encoded non indexed values
+func (c *{{$.Type}}) Decode{{.StructName}}(event *common.ContractEventInfo) (*{{.StructName}}, error) {
+	if event == nil || event.Topic != {{.StructName}}Topic {
+		return nil, fmt.Errorf("event is not {{.Name}}")
+	}
+	if len(event.EventData) < {{.Indexed}} {
+		return nil, fmt.Errorf("event {{.Name}} expects {{.Indexed}} topics, got %d", len(event.EventData))
+	}
+{{- if .Indexed}}
+	topics := make([][32]byte, {{.Indexed}})
+	for i := range topics {
+		topic, err := hex.DecodeString(event.EventData[i])
+		if err != nil || len(topic) != 32 {
+			return nil, fmt.Errorf("invalid topic %s of event {{.Name}}", event.EventData[i])
+		}
+		copy(topics[i][:], topic)
+	}
+{{- end}}
+	e := &{{.StructName}}{}
+{{- if .NonIndexed}}
+	if len(event.EventData) != {{.Indexed}}+1 {
+		return nil, fmt.Errorf("event {{.Name}} has no data")
+	}
+	data, err := hex.DecodeString(event.EventData[{{.Indexed}}])
+	if err != nil {
+		return nil, err
+	}
+	values, err := c.abi.Events[{{quote .Name}}].Inputs.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != {{.NonIndexed}} {
+		return nil, fmt.Errorf("event {{.Name}} expects {{.NonIndexed}} values, got %d", len(values))
+	}
+	var ok bool
+{{- end}}
+{{- $event := .}}
+{{- range .Fields}}
+{{- if .Indexed}}
+	e.{{.GoName}} = {{topic .}}
+{{- else}}
+	if e.{{.GoName}}, ok = values[{{.Index}}].({{.GoType}}); !ok {
+		return nil, fmt.Errorf("value {{.Index}} of event {{$event.Name}} is %T, not {{.GoType}}", values[{{.Index}}])
+	}
+{{- end}}
+{{- end}}
+	return e, nil
+}
+{{end}}
+func evmTopicAddress(topic [32]byte) evmutils.Address {
+	var address evmutils.Address
+	copy(address[:], topic[12:])
+	return address
+}
+
+// evmTopicInt decode the two's complement signed integer
+func evmTopicInt(topic [32]byte) *big.Int {
+	n := new(big.Int).SetBytes(topic[:])
+	if topic[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	return n
+}
+{{range bytesSizes .Events}}
+func evmTopicBytes{{.}}(topic [32]byte) [{{.}}]byte {
+	var value [{{.}}]byte
+	copy(value[:], topic[:])
+	return value
+}
+{{end}}`))
@@ -15,6 +15,7 @@ import (
 	"chainmaker.org/chainmaker-go/tools/cmc/bulletproofs"
 	"chainmaker.org/chainmaker-go/tools/cmc/cert"
 	"chainmaker.org/chainmaker-go/tools/cmc/client"
+	"chainmaker.org/chainmaker-go/tools/cmc/codegen"
 	commandutil "chainmaker.org/chainmaker-go/tools/cmc/command_util"
 	"chainmaker.org/chainmaker-go/tools/cmc/consensus"
 	"chainmaker.org/chainmaker-go/tools/cmc/console"
@@ -62,6 +63,7 @@ For detailed logs, please see ./sdk.log
 	mainCmd.AddCommand(commandutil.NewUtilCMD())
 	mainCmd.AddCommand(consensus.NewConsensusCMD())
 	mainCmd.AddCommand(node.NewNodeCMD())
+	mainCmd.AddCommand(codegen.NewCodegenCMD())
 
 	// 后续改成go-sdk
 	//mainCmd.AddCommand(payload.PayloadCMD())
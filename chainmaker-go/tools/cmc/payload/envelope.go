@@ -0,0 +1,324 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package payload
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"chainmaker.org/chainmaker-go/tools/cmc/util"
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	sdkPbAc "chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	sdkPbCommon "chainmaker.org/chainmaker/pb-go/v2/common"
+	sdk "chainmaker.org/chainmaker/sdk-go/v2"
+	sdkutils "chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"github.com/gogo/protobuf/proto"
+	"github.com/spf13/cobra"
+)
+
+// commands sharing a flag variable share its default too
+var (
+	envelopePayloadInput string
+	envelopeInput        string
+	envelopeInputs       []string
+	envelopeOutput       string
+	envelopeSignedOutput string
+	envelopeMergedOutput string
+	envelopeOrgId        string
+	envelopeKeyPath      string
+	envelopeCrtPath      string
+	envelopeHashType     string
+	envelopeTimeout      int64
+	envelopeSyncResult   bool
+	envelopeCheckPolicy  bool
+)
+
+// envelopeCMD partially signed tx envelope command
+// @return *cobra.Command
+func envelopeCMD() *cobra.Command {
+	envelopeCmd := &cobra.Command{
+		Use:   "envelope",
+		Short: "Partially signed tx envelope command",
+		Long: "Partially signed tx envelope command, collect endorsements of several orgs offline " +
+			"and submit the tx once the endorsement policy is satisfied",
+	}
+
+	envelopeCmd.AddCommand(createEnvelopeCMD())
+	envelopeCmd.AddCommand(inspectEnvelopeCMD())
+	envelopeCmd.AddCommand(signEnvelopeCMD())
+	envelopeCmd.AddCommand(mergeEnvelopeCMD())
+	envelopeCmd.AddCommand(submitEnvelopeCMD())
+
+	return envelopeCmd
+}
+
+// createEnvelopeCMD create envelope from pb file command
+// @return *cobra.Command
+func createEnvelopeCMD() *cobra.Command {
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create envelope from pb file command",
+		Long:  "Create envelope from payload pb file created by `payload create`",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return createEnvelope()
+		},
+	}
+
+	flags := createCmd.Flags()
+	flags.StringVarP(&envelopePayloadInput, "input", "i", "./collect.pb", "specify input payload pb file")
+	flags.StringVarP(&envelopeOutput, "output", "o", "./envelope.json", "specify output envelope file")
+
+	return createCmd
+}
+
+// inspectEnvelopeCMD print envelope command
+// @return *cobra.Command
+func inspectEnvelopeCMD() *cobra.Command {
+	inspectCmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect envelope command",
+		Long:  "Print the payload summary and endorsements of envelope, check endorsements with --check",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return inspectEnvelope()
+		},
+	}
+
+	flags := inspectCmd.Flags()
+	flags.StringVarP(&envelopeInput, "input", "i", "./envelope.json", "specify input envelope file")
+	flags.BoolVar(&envelopeCheckPolicy, "check", false,
+		"check endorsements against the resource policy of chain, requires --sdk-conf-path")
+	attachFlags(inspectCmd, []string{"sdk-conf-path"})
+
+	return inspectCmd
+}
+
+// signEnvelopeCMD sign envelope command
+// @return *cobra.Command
+func signEnvelopeCMD() *cobra.Command {
+	signCmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign envelope command",
+		Long:  "Sign envelope with admin key, the cert is not set in PermissionedWithKey and Public mode",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return signEnvelope()
+		},
+	}
+
+	flags := signCmd.Flags()
+	flags.StringVarP(&envelopeInput, "input", "i", "./envelope.json", "specify input envelope file")
+	flags.StringVarP(&envelopeSignedOutput, "output", "o", "./envelope-signed.json", "specify output envelope file")
+	flags.StringVarP(&envelopeOrgId, "org-id", "O", "",
+		"specify organization identity in PermissionedWithKey mode, the cert org is used in cert mode")
+	flags.StringVarP(&envelopeKeyPath, "admin-key-path", "k", "./admin1.sign.key", "specify admin key path")
+	flags.StringVarP(&envelopeCrtPath, "admin-crt-path", "c", "", "specify admin certificate path")
+	flags.StringVar(&envelopeHashType, "hash-type", crypto.CRYPTO_ALGO_SHA256,
+		"specify hash type in PermissionedWithKey and Public mode")
+
+	return signCmd
+}
+
+// mergeEnvelopeCMD merge envelopes command
+// @return *cobra.Command
+func mergeEnvelopeCMD() *cobra.Command {
+	mergeCmd := &cobra.Command{
+		Use:   "merge",
+		Short: "Merge envelopes command",
+		Long:  "Merge the endorsements of envelopes of the same payload",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return mergeEnvelopes()
+		},
+	}
+
+	flags := mergeCmd.Flags()
+	flags.StringSliceVarP(&envelopeInputs, "inputs", "i", nil, "specify input envelope files, separated by comma")
+	flags.StringVarP(&envelopeMergedOutput, "output", "o", "./envelope-merged.json", "specify output envelope file")
+
+	return mergeCmd
+}
+
+// submitEnvelopeCMD submit envelope command
+// @return *cobra.Command
+func submitEnvelopeCMD() *cobra.Command {
+	submitCmd := &cobra.Command{
+		Use:   "submit",
+		Short: "Submit envelope command",
+		Long:  "Check endorsements against the resource policy of chain and send the tx of envelope",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return submitEnvelope()
+		},
+	}
+
+	flags := submitCmd.Flags()
+	flags.StringVarP(&envelopeInput, "input", "i", "./envelope.json", "specify input envelope file")
+	flags.Int64Var(&envelopeTimeout, "timeout", -1, "specify timeout of sending tx in seconds")
+	flags.BoolVar(&envelopeSyncResult, "sync-result", true, "whether wait the result of the tx")
+	attachFlags(submitCmd, []string{"sdk-conf-path"})
+
+	return submitCmd
+}
+
+func createEnvelope() error {
+	raw, err := ioutil.ReadFile(envelopePayloadInput)
+	if err != nil {
+		return fmt.Errorf(LOAD_FILE_ERROR_FORMAT, envelopePayloadInput, err)
+	}
+	payload := &sdkPbCommon.Payload{}
+	if err = proto.Unmarshal(raw, payload); err != nil {
+		return fmt.Errorf("Payload unmarshal error: %s", err)
+	}
+
+	envelope, err := sdk.NewTxEnvelope(payload)
+	if err != nil {
+		return err
+	}
+	return writeEnvelope(envelope, envelopeOutput)
+}
+
+func inspectEnvelope() error {
+	envelope, err := readEnvelope(envelopeInput)
+	if err != nil {
+		return err
+	}
+
+	inspection := map[string]interface{}{
+		"summary":      envelope.Summary,
+		"endorsements": describeEndorsements(envelope),
+	}
+	if envelopeCheckPolicy {
+		cc, err := util.CreateChainClientWithConfPath(sdkConfPath, false)
+		if err != nil {
+			return err
+		}
+		defer cc.Stop()
+
+		result, err := cc.CheckTxEnvelope(envelope)
+		if err != nil {
+			return err
+		}
+		inspection["check"] = result
+	}
+	util.PrintPrettyJson(inspection)
+	return nil
+}
+
+func signEnvelope() error {
+	envelope, err := readEnvelope(envelopeInput)
+	if err != nil {
+		return err
+	}
+	payload, err := envelope.GetPayload()
+	if err != nil {
+		return err
+	}
+
+	var entry *sdkPbCommon.EndorsementEntry
+	if envelopeCrtPath != "" {
+		entry, err = sdkutils.MakeEndorserWithPath(envelopeKeyPath, envelopeCrtPath, payload)
+	} else {
+		hashType, ok := crypto.HashAlgoMap[envelopeHashType]
+		if !ok {
+			return fmt.Errorf("invalid hash type: %s", envelopeHashType)
+		}
+		entry, err = sdkutils.MakePkEndorserWithPath(envelopeKeyPath, hashType, envelopeOrgId, payload)
+	}
+	if err != nil {
+		return fmt.Errorf("sign envelope failed, %s", err)
+	}
+	if err = envelope.AddEndorsement(entry); err != nil {
+		return err
+	}
+	return writeEnvelope(envelope, envelopeSignedOutput)
+}
+
+func mergeEnvelopes() error {
+	if len(envelopeInputs) == 0 {
+		return errors.New("no input envelope file")
+	}
+	var envelopes []*sdk.TxEnvelope
+	for _, input := range envelopeInputs {
+		envelope, err := readEnvelope(input)
+		if err != nil {
+			return err
+		}
+		envelopes = append(envelopes, envelope)
+	}
+
+	merged, err := sdk.MergeTxEnvelopes(envelopes...)
+	if err != nil {
+		return err
+	}
+	return writeEnvelope(merged, envelopeMergedOutput)
+}
+
+func submitEnvelope() error {
+	envelope, err := readEnvelope(envelopeInput)
+	if err != nil {
+		return err
+	}
+
+	cc, err := util.CreateChainClientWithConfPath(sdkConfPath, false)
+	if err != nil {
+		return err
+	}
+	defer cc.Stop()
+
+	resp, err := cc.SubmitTxEnvelope(envelope, envelopeTimeout, envelopeSyncResult)
+	if err != nil {
+		return err
+	}
+	err = util.CheckProposalRequestResp(resp, false)
+	if err != nil {
+		return err
+	}
+	util.PrintPrettyJson(resp)
+	return nil
+}
+
+// describeEndorsements describe the signers of envelope endorsements
+func describeEndorsements(envelope *sdk.TxEnvelope) []map[string]string {
+	var descriptions []map[string]string
+	for _, endorsement := range envelope.Endorsements {
+		description := map[string]string{
+			"org_id":      endorsement.OrgId,
+			"member_type": endorsement.MemberType,
+		}
+		switch endorsement.MemberType {
+		case sdkPbAc.MemberType_CERT.String():
+			if cert, err := sdkutils.ParseCert(endorsement.MemberInfo); err == nil {
+				description["subject"] = cert.Subject.String()
+			}
+		case sdkPbAc.MemberType_PUBLIC_KEY.String():
+			description["public_key"] = string(endorsement.MemberInfo)
+		default:
+			description["member_info"] = hex.EncodeToString(endorsement.MemberInfo)
+		}
+		descriptions = append(descriptions, description)
+	}
+	return descriptions
+}
+
+func readEnvelope(path string) (*sdk.TxEnvelope, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(LOAD_FILE_ERROR_FORMAT, path, err)
+	}
+	return sdk.UnmarshalTxEnvelope(raw)
+}
+
+func writeEnvelope(envelope *sdk.TxEnvelope, path string) error {
+	data, err := envelope.Marshal()
+	if err != nil {
+		return fmt.Errorf("Envelope marshal error: %s", err)
+	}
+	if err = ioutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("Write to file %s error: %s", path, err)
+	}
+	return nil
+}
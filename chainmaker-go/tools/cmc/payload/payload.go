@@ -39,6 +39,7 @@ func NewPayloadCMD() *cobra.Command {
 	payloadCmd.AddCommand(jsonCMD())
 	payloadCmd.AddCommand(createCMD())
 	payloadCmd.AddCommand(signCMD())
+	payloadCmd.AddCommand(envelopeCMD())
 	//payloadCmd.AddCommand(mergeCMD())
 
 	return payloadCmd
@@ -20,7 +20,24 @@ fi
 
 echo "[CMD] ./build.sh $contractName $targetARCH"
 
-GOOS=linux GOARCH=$targetARCH go build $crypto -ldflags="-s -w" -o $contractName
+# CEI_LINT=on stops the build when the contract writes state after a cross contract call, see cmd/ceilint
+if [ "$CEI_LINT" == "on" ]; then
+  go run chainmaker.org/chainmaker/contract-sdk-go/v2/cmd/ceilint . || exit 1
+  echo "[OK] Checked contract with checks-effects-interactions lint."
+fi
+
+# GAS_METER=on builds a copy of the contract instrumented by gasmeter, so that the compute it does is charged as gas
+if [ "$GAS_METER" == "on" ]; then
+  meterDir=$(mktemp -d)
+  cp -r ./* $meterDir
+  go run chainmaker.org/chainmaker/contract-sdk-go/v2/cmd/gasmeter $meterDir || exit 1
+  (cd $meterDir && GOOS=linux GOARCH=$targetARCH go build $crypto -ldflags="-s -w" -o $contractName) || exit 1
+  mv $meterDir/$contractName ./$contractName
+  rm -rf $meterDir
+  echo "[OK] Instrumented contract with gas meter."
+else
+  GOOS=linux GOARCH=$targetARCH go build $crypto -ldflags="-s -w" -o $contractName
+fi
 
 echo "[OK] Compiled project to contract bin $contractName."
 
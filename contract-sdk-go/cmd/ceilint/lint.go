@@ -0,0 +1,344 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// sdk methods writing the state of the contract
+var stateWrites = map[string]bool{
+	"PutState":            true,
+	"PutStateByte":        true,
+	"PutStateFromKey":     true,
+	"PutStateFromKeyByte": true,
+	"DelState":            true,
+	"DelStateFromKey":     true,
+}
+
+// sdk functions calling other contracts
+var interactions = map[string]bool{
+	"CallContract":    true,
+	"CallEVMContract": true,
+}
+
+const guardFunc = "NonReentrant"
+
+// call is a call expression in a function body
+type call struct {
+	pos token.Pos
+	// name of the called function, methods are prefixed with a dot
+	name     string
+	writes   bool
+	interact bool
+}
+
+// function is a function or method declared by the contract
+type function struct {
+	name     string
+	decl     *ast.FuncDecl
+	calls    map[*ast.CallExpr]*call
+	writes   bool
+	interact bool
+	guarded  bool
+}
+
+// finding is a state write which may run after a cross contract call
+type finding struct {
+	pos      token.Position
+	callLine int
+	funcName string
+	write    string
+}
+
+func (f finding) String() string {
+	return fmt.Sprintf("%s: %s after cross contract call at line %d in %s, "+
+		"move it before the call or guard %s with sdk.NonReentrant", f.pos, f.write, f.callLine, f.funcName, f.funcName)
+}
+
+// lintDir reports the state writes after cross contract calls in the go files of dir
+func lintDir(dir string) ([]finding, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var funcs []*function
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				funcs = append(funcs, newFunction(fn))
+			}
+		}
+	}
+
+	resolve(funcs)
+
+	var findings []finding
+	for _, fn := range funcs {
+		if !fn.guarded {
+			l := &linter{fset: fset, fn: fn, reported: make(map[token.Pos]bool)}
+			l.block(fn.decl.Body.List, nil)
+			findings = append(findings, l.findings...)
+		}
+	}
+	return findings, nil
+}
+
+// newFunction collects the calls of fn
+func newFunction(fn *ast.FuncDecl) *function {
+	f := &function{name: fn.Name.Name, decl: fn, calls: make(map[*ast.CallExpr]*call)}
+	if fn.Recv != nil {
+		f.name = "." + f.name
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if expr, ok := n.(*ast.CallExpr); ok {
+			if c := newCall(expr); c != nil {
+				f.guarded = f.guarded || strings.TrimPrefix(c.name, ".") == guardFunc
+				f.calls[expr] = c
+			}
+		}
+		return true
+	})
+	return f
+}
+
+func newCall(expr *ast.CallExpr) *call {
+	c := &call{pos: expr.Pos()}
+	switch fun := expr.Fun.(type) {
+	case *ast.Ident:
+		c.name = fun.Name
+	case *ast.SelectorExpr:
+		c.name = "." + fun.Sel.Name
+	default:
+		return nil
+	}
+	// sdk.Instance.PutState, sdk.CallEVMContract or the same names imported with dot
+	name := strings.TrimPrefix(c.name, ".")
+	c.writes = stateWrites[name]
+	c.interact = interactions[name]
+	return c
+}
+
+// resolve marks the functions writing state or calling other contracts, directly or through other functions
+// of the contract. Methods are matched by name, whatever their receiver. Functions only called by guarded
+// functions are guarded as well.
+func resolve(funcs []*function) {
+	byName := make(map[string][]*function, len(funcs))
+	for _, fn := range funcs {
+		byName[fn.name] = append(byName[fn.name], fn)
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range funcs {
+			for _, c := range fn.calls {
+				for _, callee := range byName[c.name] {
+					if callee.writes && !c.writes {
+						c.writes, changed = true, true
+					}
+					if callee.interact && !c.interact {
+						c.interact, changed = true, true
+					}
+				}
+				if c.writes && !fn.writes {
+					fn.writes, changed = true, true
+				}
+				if c.interact && !fn.interact {
+					fn.interact, changed = true, true
+				}
+			}
+		}
+	}
+
+	callers := make(map[*function][]*function)
+	for _, fn := range funcs {
+		for _, c := range fn.calls {
+			for _, callee := range byName[c.name] {
+				callers[callee] = append(callers[callee], fn)
+			}
+		}
+	}
+	// start from every called function guarded and drop the ones with an unguarded caller
+	inherited := make(map[*function]bool)
+	for _, fn := range funcs {
+		inherited[fn] = !fn.guarded && len(callers[fn]) > 0
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, fn := range funcs {
+			if !inherited[fn] {
+				continue
+			}
+			for _, caller := range callers[fn] {
+				if caller != fn && !caller.guarded && !inherited[caller] {
+					inherited[fn], changed = false, true
+					break
+				}
+			}
+		}
+	}
+	for fn, ok := range inherited {
+		fn.guarded = fn.guarded || ok
+	}
+}
+
+// linter follows the statements of a function, remembering the first cross contract call on the way
+type linter struct {
+	fset     *token.FileSet
+	fn       *function
+	reported map[token.Pos]bool
+	findings []finding
+}
+
+// block lints stmts run after interaction, it returns the first cross contract call made once stmts are run,
+// and whether they never fall through
+func (l *linter) block(stmts []ast.Stmt, interaction *call) (*call, bool) {
+	for _, stmt := range stmts {
+		var done bool
+		if interaction, done = l.stmt(stmt, interaction); done {
+			return interaction, true
+		}
+	}
+	return interaction, false
+}
+
+func (l *linter) stmt(stmt ast.Stmt, interaction *call) (*call, bool) {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		return l.block(s.List, interaction)
+	case *ast.LabeledStmt:
+		return l.stmt(s.Stmt, interaction)
+	case *ast.IfStmt:
+		interaction = l.node(s.Init, interaction)
+		interaction = l.node(s.Cond, interaction)
+		thenCall, thenDone := l.block(s.Body.List, interaction)
+		elseCall, elseDone := interaction, false
+		if s.Else != nil {
+			elseCall, elseDone = l.stmt(s.Else, interaction)
+		}
+		return merge(thenCall, thenDone, elseCall, elseDone), thenDone && elseDone
+	case *ast.SwitchStmt:
+		interaction = l.node(s.Init, interaction)
+		interaction = l.node(s.Tag, interaction)
+		return l.clauses(s.Body, interaction)
+	case *ast.TypeSwitchStmt:
+		interaction = l.node(s.Init, interaction)
+		interaction = l.node(s.Assign, interaction)
+		return l.clauses(s.Body, interaction)
+	case *ast.SelectStmt:
+		return l.clauses(s.Body, interaction)
+	case *ast.ForStmt:
+		interaction = l.node(s.Init, interaction)
+		interaction = l.node(s.Cond, interaction)
+		return l.loop(s.Body, s.Post, interaction), false
+	case *ast.RangeStmt:
+		interaction = l.node(s.X, interaction)
+		return l.loop(s.Body, nil, interaction), false
+	case *ast.ReturnStmt:
+		return l.node(s, interaction), true
+	case *ast.BranchStmt:
+		return interaction, true
+	default:
+		return l.node(s, interaction), false
+	}
+}
+
+// clauses lints the cases of a switch or select, one of them runs
+func (l *linter) clauses(body *ast.BlockStmt, interaction *call) (*call, bool) {
+	result, allDone, hasDefault := interaction, true, false
+	for _, clause := range body.List {
+		var stmts []ast.Stmt
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			hasDefault = hasDefault || c.List == nil
+			stmts = c.Body
+		case *ast.CommClause:
+			hasDefault = hasDefault || c.Comm == nil
+			stmts = c.Body
+		}
+		caseCall, caseDone := l.block(stmts, interaction)
+		result = merge(result, false, caseCall, caseDone)
+		allDone = allDone && caseDone
+	}
+	return result, allDone && hasDefault
+}
+
+// loop lints the body of a loop twice, the second time as if run again after the calls of the first time
+func (l *linter) loop(body *ast.BlockStmt, post ast.Stmt, interaction *call) *call {
+	for i := 0; i < 2; i++ {
+		bodyCall, _ := l.block(body.List, interaction)
+		interaction = merge(interaction, false, l.node(post, bodyCall), false)
+	}
+	return interaction
+}
+
+// node lints the calls of n in the order they return, arguments are evaluated before the call they are passed to
+func (l *linter) node(n ast.Node, interaction *call) *call {
+	if n == nil {
+		return interaction
+	}
+	var exprs []*ast.CallExpr
+	ast.Inspect(n, func(node ast.Node) bool {
+		if expr, ok := node.(*ast.CallExpr); ok && l.fn.calls[expr] != nil {
+			exprs = append(exprs, expr)
+		}
+		return true
+	})
+	sort.Slice(exprs, func(i, j int) bool {
+		return exprs[i].Rparen < exprs[j].Rparen
+	})
+
+	for _, expr := range exprs {
+		c := l.fn.calls[expr]
+		if interaction != nil && c.writes && !l.reported[c.pos] {
+			l.reported[c.pos] = true
+			write := "state write"
+			if name := strings.TrimPrefix(c.name, "."); !stateWrites[name] {
+				write += " by " + name
+			}
+			l.findings = append(l.findings, finding{
+				pos:      l.fset.Position(c.pos),
+				callLine: l.fset.Position(interaction.pos).Line,
+				funcName: strings.TrimPrefix(l.fn.name, "."),
+				write:    write,
+			})
+		}
+		if interaction == nil && c.interact {
+			interaction = c
+		}
+	}
+	return interaction
+}
+
+// merge returns the cross contract call made on either of two paths, paths never falling through are ignored
+func merge(a *call, aDone bool, b *call, bDone bool) *call {
+	if a != nil && !aDone {
+		return a
+	}
+	if b != nil && !bDone {
+		return b
+	}
+	return nil
+}
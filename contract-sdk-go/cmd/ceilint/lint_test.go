@@ -0,0 +1,42 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLintDir(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+		want []string
+	}{
+		{name: "write after call", dir: "unguarded",
+			want: []string{"vault.go:24: state write by clear after call at line 20 in withdraw"}},
+		{name: "guarded by NonReentrant", dir: "guarded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := lintDir(filepath.Join("testdata", tt.dir))
+			if err != nil {
+				t.Fatalf("lintDir() error = %v", err)
+			}
+			var got []string
+			for _, f := range findings {
+				got = append(got, fmt.Sprintf("%s:%d: %s after call at line %d in %s",
+					filepath.Base(f.pos.Filename), f.pos.Line, f.write, f.callLine, f.funcName))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lintDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// ceilint checks that a go contract follows the checks-effects-interactions pattern.
+//
+// A contract called by CallContract may call back into its caller before the call returns, so a caller
+// writing state after the call works on state the callback could have changed. ceilint reports every
+// state write which may run after a cross contract call in the same function, directly or through the
+// functions of the contract it calls. Functions guarded by sdk.NonReentrant are not reported.
+//
+// Usage:
+//
+//	ceilint [contractDir]
+//
+// The exit status is 1 when a write is reported, build.sh runs it before building when CEI_LINT=on is set.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	dir := "."
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+
+	findings, err := lintDir(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ceilint: %s\n", err)
+		os.Exit(1)
+	}
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
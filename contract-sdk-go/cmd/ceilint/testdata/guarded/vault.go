@@ -0,0 +1,29 @@
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+type Vault struct{}
+
+// withdraw writes after the call, guarded by NonReentrant
+func (v *Vault) withdraw(sender string, amount []byte) protogo.Response {
+	release, err := sdk.NonReentrant("withdraw")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	defer release()
+
+	resp := sdk.Instance.CallContract("token", "Transfer", map[string][]byte{"to": []byte(sender), "amount": amount})
+	if resp.Status != sdk.OK {
+		return resp
+	}
+	v.clear(sender)
+	return sdk.Success(nil)
+}
+
+// clear is only called by withdraw, it is guarded as well
+func (v *Vault) clear(sender string) {
+	_ = sdk.Instance.DelState("balance", sender)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+type Vault struct{}
+
+// deposit writes before the call
+func (v *Vault) deposit(sender string, amount []byte) protogo.Response {
+	if err := sdk.Instance.PutStateByte("balance", sender, amount); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Instance.CallContract("token", "TransferFrom", map[string][]byte{"amount": amount})
+}
+
+// withdraw writes after the call
+func (v *Vault) withdraw(sender string, amount []byte) protogo.Response {
+	resp := sdk.Instance.CallContract("token", "Transfer", map[string][]byte{"to": []byte(sender), "amount": amount})
+	if resp.Status != sdk.OK {
+		return resp
+	}
+	v.clear(sender)
+	return sdk.Success(nil)
+}
+
+func (v *Vault) clear(sender string) {
+	_ = sdk.Instance.DelState("balance", sender)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	sdkImportPath = "chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	// import name used when the file does not import sdk package yet
+	meterImportName = "gasmetersdk"
+)
+
+// insertion is a piece of source inserted at offset of the original file
+type insertion struct {
+	offset int
+	text   string
+}
+
+// instrument inserts a Meter call at the start of every basic block of every function in src.
+// The cost of a block is the number of statements it holds, so the units charged for a tx only
+// depend on the executed path and are the same on every node.
+func instrument(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	meterFunc, importText, importOffset := meterFuncName(fset, file)
+
+	// blocks whose statement list may only hold case clauses
+	clauseBodies := make(map[*ast.BlockStmt]bool)
+	var inserts []insertion
+	addMeter := func(pos token.Pos, stmts []ast.Stmt) {
+		cost := len(stmts)
+		if cost == 0 {
+			cost = 1
+		}
+		inserts = append(inserts, insertion{
+			offset: fset.Position(pos).Offset,
+			text:   fmt.Sprintf("\n%s(%d);", meterFunc, cost),
+		})
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SwitchStmt:
+			clauseBodies[node.Body] = true
+		case *ast.TypeSwitchStmt:
+			clauseBodies[node.Body] = true
+		case *ast.SelectStmt:
+			clauseBodies[node.Body] = true
+		case *ast.BlockStmt:
+			if !clauseBodies[node] {
+				addMeter(node.Lbrace+1, node.List)
+			}
+		case *ast.CaseClause:
+			addMeter(node.Colon+1, node.Body)
+		case *ast.CommClause:
+			addMeter(node.Colon+1, node.Body)
+		}
+		return true
+	})
+
+	if len(inserts) == 0 {
+		return src, nil
+	}
+	if importText != "" {
+		inserts = append(inserts, insertion{offset: importOffset, text: importText})
+	}
+
+	sort.SliceStable(inserts, func(i, j int) bool {
+		return inserts[i].offset < inserts[j].offset
+	})
+
+	var b strings.Builder
+	last := 0
+	for _, in := range inserts {
+		b.Write(src[last:in.offset])
+		b.WriteString(in.text)
+		last = in.offset
+	}
+	b.Write(src[last:])
+
+	return format.Source([]byte(b.String()))
+}
+
+// meterFuncName returns how the Meter function is referred to in file,
+// and the import to insert at offset when the sdk package is not imported yet
+func meterFuncName(fset *token.FileSet, file *ast.File) (string, string, int) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != sdkImportPath {
+			continue
+		}
+		if spec.Name == nil {
+			return "sdk.Meter", "", 0
+		}
+		switch spec.Name.Name {
+		case "_":
+			continue
+		case ".":
+			return "Meter", "", 0
+		default:
+			return spec.Name.Name + ".Meter", "", 0
+		}
+	}
+
+	importText := fmt.Sprintf("\nimport %s %q\n", meterImportName, sdkImportPath)
+	// imports must follow the package clause and come before any other declaration
+	return meterImportName + ".Meter", importText, fset.Position(file.Name.End()).Offset
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// sdkSrc is the part of the sdk package used by the testdata
+const sdkSrc = `package sdk
+
+func Meter(units uint64) {}
+
+var Instance interface {
+	PutState(key, field string, value string) error
+}
+`
+
+// sdkImporter imports the sdk package from sdkSrc and the others from the standard library
+type sdkImporter struct {
+	sdk *types.Package
+	std types.Importer
+}
+
+func (i *sdkImporter) Import(path string) (*types.Package, error) {
+	if path == sdkImportPath {
+		return i.sdk, nil
+	}
+	return i.std.Import(path)
+}
+
+func newSdkImporter(t *testing.T) *sdkImporter {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "sdk.go", sdkSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := new(types.Config).Check(sdkImportPath, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &sdkImporter{sdk: pkg, std: importer.Default()}
+}
+
+func TestInstrument(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.go")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no testdata, %v", err)
+	}
+
+	fset := token.NewFileSet()
+	var instrumented []*ast.File
+	for _, name := range files {
+		src, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := instrument(filepath.Base(name), src)
+		if err != nil {
+			t.Fatalf("instrument(%s) error = %v", name, err)
+		}
+
+		golden := name + ".golden"
+		if *update {
+			if err = ioutil.WriteFile(golden, got, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("instrument(%s) =\n%s\nwant\n%s", name, got, want)
+		}
+
+		file, err := parser.ParseFile(fset, name, got, 0)
+		if err != nil {
+			t.Fatalf("instrumented %s does not parse, %v", name, err)
+		}
+		instrumented = append(instrumented, file)
+	}
+
+	// the instrumented contract must still compile
+	conf := types.Config{Importer: newSdkImporter(t)}
+	if _, err = conf.Check("main", fset, instrumented, nil); err != nil {
+		t.Errorf("instrumented testdata does not compile, %v", err)
+	}
+}
+
+func TestInstrumentCost(t *testing.T) {
+	src := []byte(`package main
+
+func f(n int) {
+	for i := 0; i < n; i++ {
+		n--
+		n++
+	}
+}
+`)
+	got, err := instrument("f.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the function body holds one statement, the loop body two
+	for _, want := range []string{"gasmetersdk.Meter(1)", "gasmetersdk.Meter(2)"} {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("instrument() =\n%s\nwant %s", got, want)
+		}
+	}
+}
+
+func TestInstrumentDir(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "gasmeter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srcDir)
+
+	src := []byte("package p\n\nfunc f() int {\n\treturn 1\n}\n")
+	files := []string{"main.go", "main_test.go", "util/util.go", "util/deep/deep.go", "testdata/data.go",
+		"vendor/v/v.go", ".git/g.go"}
+	for _, name := range files {
+		path := filepath.Join(srcDir, name)
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	outDir := srcDir + "-out"
+	defer os.RemoveAll(outDir)
+	if err = instrumentDir(srcDir, outDir); err != nil {
+		t.Fatalf("instrumentDir() error = %v", err)
+	}
+	tests := []struct {
+		name         string
+		instrumented bool
+	}{
+		{name: "main.go", instrumented: true},
+		{name: "util/util.go", instrumented: true},
+		{name: "util/deep/deep.go", instrumented: true},
+		{name: "main_test.go"},
+		{name: "testdata/data.go"},
+		{name: "vendor/v/v.go"},
+		{name: ".git/g.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ioutil.ReadFile(filepath.Join(outDir, tt.name))
+			if instrumented := err == nil && bytes.Contains(got, []byte("gasmetersdk.Meter(1)")); instrumented !=
+				tt.instrumented {
+				t.Errorf("%s instrumented = %v, want %v", tt.name, instrumented, tt.instrumented)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// gasmeter instruments the source of a go contract so that the compute it does is charged as gas.
+//
+// Every basic block of the contract is prefixed with a call to sdk.Meter, the sandbox reports the
+// consumed compute units in the tx response and the chain charges them through its gas config.
+// A contract running out of gas fails the tx with out of gas instead of waiting for the tx timeout.
+//
+// Usage:
+//
+//	gasmeter [-o outDir] [contractDir]
+//
+// Instrumented files overwrite the sources unless outDir is given, build.sh uses it on a copy of
+// the contract when GAS_METER=on is set. The packages in the subdirectories of contractDir are
+// instrumented as well, except testdata, vendor and the directories the go tool ignores.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	outDir := flag.String("o", "", "directory to write instrumented files to, default overwrite the sources")
+	flag.Parse()
+
+	srcDir := "."
+	if flag.NArg() > 0 {
+		srcDir = flag.Arg(0)
+	}
+	if *outDir == "" {
+		*outDir = srcDir
+	}
+
+	if err := instrumentDir(srcDir, *outDir); err != nil {
+		fmt.Fprintf(os.Stderr, "gasmeter: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func instrumentDir(srcDir, outDir string) error {
+	files, err := ioutil.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() {
+			if skipDir(name) {
+				continue
+			}
+			if err = instrumentDir(filepath.Join(srcDir, name), filepath.Join(outDir, name)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		src, err := ioutil.ReadFile(filepath.Join(srcDir, name))
+		if err != nil {
+			return err
+		}
+		dst, err := instrument(name, src)
+		if err != nil {
+			return fmt.Errorf("failed to instrument %s, %v", filepath.Join(srcDir, name), err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(outDir, name), dst, f.Mode()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// skipDir returns whether the directory holds no package of the contract
+func skipDir(name string) bool {
+	return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
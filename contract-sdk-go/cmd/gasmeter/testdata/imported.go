@@ -0,0 +1,17 @@
+package main
+
+import (
+	"strconv"
+
+	contractsdk "chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+// put writes n states, the sdk is already imported under another name
+func put(n int) error {
+	for i := 0; i < n; i++ {
+		if err := contractsdk.Instance.PutState("key", strconv.Itoa(i), "value"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
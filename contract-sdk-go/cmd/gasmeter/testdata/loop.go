@@ -0,0 +1,29 @@
+package main
+
+import "strconv"
+
+// sum runs a loop, every iteration is charged
+func sum(n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			total += i
+		} else {
+			total -= i
+		}
+	}
+	return total
+}
+
+// describe calls other functions, the callee charges its own blocks
+func describe(n int) string {
+	switch {
+	case n < 0:
+		return "negative"
+	case n == 0:
+	default:
+		return strconv.Itoa(sum(n))
+	}
+	f := func() string { return "zero" }
+	return f()
+}
@@ -8,13 +8,10 @@ SPDX-License-Identifier: Apache-2.0
 package demo
 
 import (
-	"encoding/hex"
 	"fmt"
 	"log"
 	"strconv"
-	"strings"
 
-	"chainmaker.org/chainmaker/common/v2/evmutils/abi"
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
@@ -47,13 +44,6 @@ func (c *CrossEvmContract) CrossEvmStorageSet() protogo.Response {
 	// 获取所有参数
 	args := sdk.Instance.GetArgs()
 
-	// 取出 storage abi
-	storateABIJson := string(args["storage_abi"])
-	storageAbi, err := abi.JSON(strings.NewReader(string(storateABIJson)))
-	if err != nil {
-		return sdk.Error(err.Error())
-	}
-
 	// 取出 data的10进制字符串表示
 	dataStr := string(args["storage_set_data"])
 	// dataStr 转换成 int64
@@ -62,70 +52,29 @@ func (c *CrossEvmContract) CrossEvmStorageSet() protogo.Response {
 		return sdk.Error(err.Error())
 	}
 
-	// 取出跨合约调用的方法
-	evmStorageFuncName := string(args["storage_set_func_name"])
-	dataByte, err := storageAbi.Pack(evmStorageFuncName, data)
+	// 跨合约调用 evm storage 合约，参数按 abi 编码
+	_, err = sdk.CallEVMContract(string(args["storage_contract_name"]), string(args["storage_abi"]),
+		string(args["storage_set_func_name"]), data)
 	if err != nil {
 		return sdk.Error(err.Error())
 	}
 
-	dataString := hex.EncodeToString(dataByte)
-	method := dataString[0:8]
-
-	// 取出 evm storage 合约名
-	storageContractName := string(args["storage_contract_name"])
-
-	crossContractArgs := make(map[string][]byte)
-	crossContractArgs["data"] = []byte(dataString)
-
-	// response could be correct or error
-	response := sdk.Instance.CallContract(storageContractName, method, crossContractArgs)
 	sdk.Instance.EmitEvent("cross contract set", []string{"success"})
-	return response
+	return sdk.Success([]byte("success"))
 }
 
 func (c *CrossEvmContract) CrossEvmStorageGet() protogo.Response {
 	// 获取所有参数
 	args := sdk.Instance.GetArgs()
 
-	// 取出 storage abi
-	storateABIJson := string(args["storage_abi"])
-	storageAbi, err := abi.JSON(strings.NewReader(string(storateABIJson)))
-	if err != nil {
-		return sdk.Error(err.Error())
-	}
-
-	// 取出跨合约调用的方法
-	evmStorageFuncName := string(args["storage_get_func_name"])
-
-	dataByte, err := storageAbi.Pack(evmStorageFuncName)
-	// dataByte, err := storageAbi.Pack("get")
-	if err != nil {
-		if err.Error() != "contract does not have a constructor" {
-			return sdk.Error(err.Error())
-		}
-	}
-
-	dataString := hex.EncodeToString(dataByte)
-
-	method := dataString[0:8]
-
-	// 取出 evm storage 合约名
-	storageContractName := string(args["storage_contract_name"])
-
-	crossContractArgs := make(map[string][]byte)
-	crossContractArgs["data"] = []byte(dataString)
-
-	// response could be correct or error
-	response := sdk.Instance.CallContract(storageContractName, method, crossContractArgs)
-
-	val, err := storageAbi.Unpack(evmStorageFuncName, response.Payload)
+	// 跨合约调用 evm storage 合约，返回值按 abi 解码
+	val, err := sdk.CallEVMContract(string(args["storage_contract_name"]), string(args["storage_abi"]),
+		string(args["storage_get_func_name"]))
 	if err != nil {
 		return sdk.Error(err.Error())
 	}
 
 	sdk.Instance.EmitEvent("cross contract get", []string{fmt.Sprintf("%s", val)})
-	// return response
 	return sdk.Success([]byte(fmt.Sprintf("get value from evm: %s", val)))
 }
 
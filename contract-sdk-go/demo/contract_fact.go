@@ -40,6 +40,20 @@ func (f *FactContract) InvokeContract(method string) protogo.Response {
 	}
 }
 
+// MethodSchemas 声明合约方法，供cmc codegen生成客户端
+func (f *FactContract) MethodSchemas() []*sdk.MethodSchema {
+	return []*sdk.MethodSchema{
+		sdk.NewMethodSchema("save", sdk.MethodInvoke, sdk.EventFieldString,
+			sdk.NewMethodParam("file_hash", sdk.EventFieldString),
+			sdk.NewMethodParam("file_name", sdk.EventFieldString),
+			sdk.NewMethodParam("time", sdk.EventFieldInt),
+		),
+		sdk.NewMethodSchema("findByFileHash", sdk.MethodQuery, sdk.EventFieldJSON,
+			sdk.NewMethodParam("file_hash", sdk.EventFieldString),
+		),
+	}
+}
+
 // 存证对象
 type Fact struct {
 	FileHash string `json:"FileHash"`
@@ -0,0 +1,85 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package demo
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const migrationBalanceKey = "balance"
+
+// Account balance saved since state schema version 1, version 0 saved the amount as a decimal string
+type Account struct {
+	Amount uint64 `json:"amount"`
+	Frozen bool   `json:"frozen"`
+}
+
+// MigrationContract shows state migrations, the sandbox runs its migrations when it is upgraded
+// and a migration can be tested before upgrading with sdk.DryRunMigrations
+type MigrationContract struct {
+}
+
+var _ sdk.MigrationDeclarer = (*MigrationContract)(nil)
+
+func (c *MigrationContract) InitContract() protogo.Response {
+	return sdk.Success([]byte("Init success"))
+}
+
+func (c *MigrationContract) UpgradeContract() protogo.Response {
+	return sdk.Success([]byte("Upgrade success"))
+}
+
+// Migrations returns the state migrations in version order
+func (c *MigrationContract) Migrations() []*sdk.Migration {
+	return []*sdk.Migration{
+		sdk.NewMigration(1, "save balance as json", c.balanceToJSON),
+	}
+}
+
+func (c *MigrationContract) balanceToJSON() error {
+	_, err := sdk.RewriteStatePrefix(migrationBalanceKey, sdk.DefaultMigrationBatchSize,
+		func(key, field string, value []byte) ([]byte, error) {
+			amount, err := strconv.ParseUint(string(value), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(&Account{Amount: amount})
+		})
+	return err
+}
+
+func (c *MigrationContract) InvokeContract(method string) protogo.Response {
+	switch method {
+	case "balanceOf":
+		return c.balanceOf()
+	default:
+		return sdk.Error("invalid method")
+	}
+}
+
+func (c *MigrationContract) balanceOf() protogo.Response {
+	owner := string(sdk.Instance.GetArgs()["owner"])
+	account, err := sdk.Instance.GetStateByte(migrationBalanceKey, owner)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(account)
+}
+
+func main() {
+	err := sandbox.Start(new(MigrationContract))
+	if err != nil {
+		log.Fatal(err)
+	}
+}
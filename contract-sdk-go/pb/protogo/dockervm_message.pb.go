@@ -242,7 +242,7 @@ func (DockerVMCode) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_23619f598d968e93, []int{2}
 }
 
-//DockerVMMessage means message between chainmaker and docker vm
+// DockerVMMessage means message between chainmaker and docker vm
 type DockerVMMessage struct {
 	TxId         string        `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
 	Type         DockerVMType  `protobuf:"varint,2,opt,name=type,proto3,enum=proto.DockerVMType" json:"type,omitempty"`
@@ -559,6 +559,10 @@ type TxRequest struct {
 	// cross contract in use
 	TxContext *TxContext `protobuf:"bytes,5,opt,name=tx_context,json=txContext,proto3" json:"tx_context,omitempty"`
 	ChainId   string     `protobuf:"bytes,6,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	// compute units the metered contract may consume, 0 means unlimited
+	ComputeLimit uint64 `protobuf:"varint,9,opt,name=compute_limit,json=computeLimit,proto3" json:"compute_limit,omitempty"`
+	// the engine put the call stack under __call_context__ in parameters
+	HasCallContext bool `protobuf:"varint,10,opt,name=has_call_context,json=hasCallContext,proto3" json:"has_call_context,omitempty"`
 }
 
 func (m *TxRequest) Reset()         { *m = TxRequest{} }
@@ -636,6 +640,20 @@ func (m *TxRequest) GetChainId() string {
 	return ""
 }
 
+func (m *TxRequest) GetComputeLimit() uint64 {
+	if m != nil {
+		return m.ComputeLimit
+	}
+	return 0
+}
+
+func (m *TxRequest) GetHasCallContext() bool {
+	if m != nil {
+		return m.HasCallContext
+	}
+	return false
+}
+
 type TxContext struct {
 	WriteMap map[string][]byte `protobuf:"bytes,1,rep,name=write_map,json=writeMap,proto3" json:"write_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	ReadMap  map[string][]byte `protobuf:"bytes,2,rep,name=read_map,json=readMap,proto3" json:"read_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -700,6 +718,8 @@ type TxResponse struct {
 	ContractName    string                 `protobuf:"bytes,8,opt,name=contract_name,json=contractName,proto3" json:"contract_name,omitempty"`
 	ContractVersion string                 `protobuf:"bytes,9,opt,name=contract_version,json=contractVersion,proto3" json:"contract_version,omitempty"`
 	ChainId         string                 `protobuf:"bytes,10,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	// compute units consumed by a metered contract
+	ComputeUnits uint64 `protobuf:"varint,12,opt,name=compute_units,json=computeUnits,proto3" json:"compute_units,omitempty"`
 }
 
 func (m *TxResponse) Reset()         { *m = TxResponse{} }
@@ -805,6 +825,13 @@ func (m *TxResponse) GetChainId() string {
 	return ""
 }
 
+func (m *TxResponse) GetComputeUnits() uint64 {
+	if m != nil {
+		return m.ComputeUnits
+	}
+	return 0
+}
+
 type DockerContractEvent struct {
 	// Event topic
 	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
@@ -1161,111 +1188,113 @@ func init() {
 func init() { proto.RegisterFile("dockervm_message.proto", fileDescriptor_23619f598d968e93) }
 
 var fileDescriptor_23619f598d968e93 = []byte{
-	// 1650 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0x4f, 0x6f, 0xdb, 0xc8,
-	0x15, 0x37, 0xf5, 0x5f, 0xcf, 0x92, 0x4c, 0x8f, 0x63, 0x85, 0x51, 0x12, 0x45, 0x91, 0x93, 0xc6,
-	0x75, 0x11, 0xbb, 0x48, 0x0b, 0xd4, 0x4d, 0x5a, 0xa4, 0x32, 0x35, 0xb1, 0x09, 0xdb, 0x94, 0x3a,
-	0xa2, 0xdd, 0x38, 0x17, 0x82, 0x91, 0x18, 0x47, 0xb0, 0x24, 0xaa, 0x24, 0xe5, 0xd8, 0x1f, 0xa2,
-	0x40, 0x6f, 0xfd, 0x1a, 0xfd, 0x08, 0x7b, 0xdc, 0xcb, 0x02, 0x39, 0x05, 0x7b, 0x5c, 0xc4, 0x1f,
-	0x63, 0xf7, 0xb0, 0x98, 0xe1, 0x90, 0x26, 0x29, 0x29, 0xbb, 0x3e, 0x2c, 0x72, 0xb2, 0xde, 0x7b,
-	0xbf, 0xf7, 0x67, 0x7e, 0xf3, 0xde, 0xe3, 0x18, 0xca, 0x3d, 0xab, 0x7b, 0x66, 0xda, 0xe7, 0x43,
-	0x7d, 0x68, 0x3a, 0x8e, 0x71, 0x6a, 0x6e, 0x8e, 0x6d, 0xcb, 0xb5, 0x50, 0x9a, 0xfd, 0xa9, 0xff,
-	0x94, 0x80, 0xa5, 0x26, 0x43, 0x1c, 0x1f, 0x1e, 0x7a, 0x00, 0xb4, 0x02, 0x69, 0xf7, 0x42, 0xef,
-	0xf7, 0x24, 0xa1, 0x26, 0xac, 0xe7, 0x49, 0xca, 0xbd, 0x50, 0x7a, 0xe8, 0x09, 0xa4, 0xdc, 0xcb,
-	0xb1, 0x29, 0x25, 0x6a, 0xc2, 0x7a, 0xe9, 0xd9, 0x8a, 0x17, 0x65, 0xd3, 0x77, 0xd5, 0x2e, 0xc7,
-	0x26, 0x61, 0x00, 0xb4, 0x0d, 0xc5, 0xae, 0x6d, 0x39, 0x8e, 0xde, 0xb5, 0x46, 0xae, 0x79, 0xe1,
-	0x4a, 0xc9, 0x9a, 0xb0, 0xbe, 0x18, 0x78, 0xc8, 0xd4, 0x26, 0x7b, 0x26, 0x52, 0xe8, 0x86, 0x24,
-	0xf4, 0x12, 0x44, 0xe7, 0xd2, 0xd1, 0xbb, 0xc6, 0x60, 0xe0, 0x17, 0x2b, 0xa5, 0x98, 0xf3, 0x2a,
-	0x77, 0xee, 0x5c, 0x3a, 0xb2, 0x31, 0x18, 0xf0, 0x42, 0x49, 0xc9, 0x89, 0xc8, 0x68, 0x03, 0xb2,
-	0xb6, 0xf9, 0xef, 0x89, 0xe9, 0xb8, 0x52, 0x9a, 0xf9, 0x89, 0xdc, 0x4f, 0xbb, 0x20, 0x9e, 0x9e,
-	0xf8, 0x00, 0xf4, 0x14, 0x72, 0xb6, 0xe9, 0x8c, 0xad, 0x91, 0x63, 0x4a, 0x19, 0x06, 0x5e, 0x0e,
-	0x81, 0x3d, 0x03, 0x09, 0x20, 0xe8, 0x0e, 0xe4, 0xba, 0xef, 0x8d, 0xfe, 0x88, 0xd2, 0x92, 0x65,
-	0xb4, 0x64, 0x99, 0xac, 0xf4, 0xd0, 0x73, 0x28, 0x39, 0xae, 0x39, 0xd6, 0x7b, 0x13, 0xdb, 0x70,
-	0xfb, 0xd6, 0xc8, 0x91, 0x72, 0xb5, 0x64, 0xe8, 0xc4, 0x1d, 0xd7, 0x1c, 0x37, 0xb9, 0x8d, 0x14,
-	0x9d, 0x90, 0xe4, 0xd4, 0xbf, 0x13, 0xa0, 0x14, 0x3d, 0x14, 0x25, 0xba, 0x6b, 0xf5, 0x4c, 0x46,
-	0xfe, 0x34, 0xd1, 0xb2, 0xd5, 0x33, 0x09, 0x03, 0x20, 0x09, 0xb2, 0x3e, 0x4b, 0x09, 0xaf, 0x22,
-	0x2e, 0xa2, 0xbf, 0x41, 0x76, 0x6c, 0x5c, 0x0e, 0x2c, 0xa3, 0x27, 0x25, 0x59, 0x29, 0xf5, 0x99,
-	0xfc, 0x6d, 0xb6, 0x3d, 0x10, 0x1e, 0xb9, 0xf6, 0x25, 0xf1, 0x5d, 0x2a, 0xcf, 0xa1, 0x10, 0x36,
-	0x20, 0x11, 0x92, 0x67, 0xe6, 0x25, 0x6f, 0x06, 0xfa, 0x13, 0xdd, 0x82, 0xf4, 0xb9, 0x31, 0x98,
-	0x78, 0x79, 0x0b, 0xc4, 0x13, 0x9e, 0x27, 0xb6, 0x85, 0xfa, 0x04, 0x0a, 0xe1, 0x0b, 0x46, 0x6b,
-	0x50, 0xec, 0x4e, 0x6c, 0xdb, 0x1c, 0xb9, 0x7a, 0xcf, 0x1c, 0xbb, 0xef, 0x59, 0x94, 0x22, 0x29,
-	0x70, 0x65, 0x93, 0xea, 0xd0, 0x43, 0x28, 0x8c, 0x6d, 0xab, 0x6b, 0x3a, 0x8e, 0x3e, 0x32, 0x86,
-	0xfe, 0x69, 0x16, 0xb9, 0x4e, 0x35, 0x86, 0x26, 0xba, 0x0f, 0xe0, 0x35, 0x55, 0x7f, 0xf4, 0xce,
-	0x62, 0x1d, 0x95, 0x22, 0x79, 0xa6, 0x51, 0x46, 0xef, 0xac, 0xfa, 0xff, 0x05, 0x28, 0x84, 0x69,
-	0x46, 0x6b, 0xbc, 0x5b, 0x3d, 0x12, 0x97, 0x42, 0x37, 0x11, 0xea, 0xd4, 0xfb, 0x00, 0x8e, 0x6b,
-	0xd8, 0xae, 0xee, 0xf6, 0x79, 0xd6, 0x24, 0xc9, 0x33, 0x8d, 0xd6, 0x1f, 0x9a, 0xb4, 0xf6, 0xc8,
-	0xbd, 0xb2, 0xb4, 0x49, 0x52, 0x08, 0xdf, 0x20, 0x7a, 0x0c, 0xa5, 0xc9, 0xc8, 0xed, 0x0f, 0xae,
-	0x51, 0x29, 0x86, 0x2a, 0x32, 0x6d, 0x00, 0x13, 0x21, 0x39, 0x74, 0x4e, 0x59, 0x57, 0xe6, 0x09,
-	0xfd, 0x59, 0xff, 0x26, 0x01, 0xf9, 0xa0, 0x2d, 0x19, 0x4f, 0xd6, 0xc8, 0xb5, 0x8d, 0xae, 0xeb,
-	0x71, 0xe0, 0xb1, 0x5d, 0xf0, 0x95, 0x8c, 0x84, 0xdf, 0x83, 0x18, 0x80, 0xce, 0x4d, 0xdb, 0xa1,
-	0xd9, 0x3c, 0xae, 0x96, 0x7c, 0xfd, 0xb1, 0xa7, 0x46, 0x65, 0xc8, 0x0c, 0x4d, 0xf7, 0xbd, 0xd5,
-	0x63, 0x45, 0xe7, 0x09, 0x97, 0xd0, 0x3f, 0x00, 0xc6, 0x86, 0x6d, 0x0c, 0x4d, 0xd7, 0xb4, 0x1d,
-	0x29, 0xc5, 0x9a, 0xa3, 0x16, 0x1f, 0x92, 0xcd, 0x76, 0x00, 0xf1, 0x5a, 0x23, 0xe4, 0x83, 0xb6,
-	0x00, 0xdc, 0x8b, 0x60, 0xb6, 0xe3, 0x63, 0xe6, 0x0f, 0x76, 0xde, 0xf5, 0x7f, 0x46, 0x26, 0x27,
-	0x13, 0x99, 0x9c, 0xca, 0xdf, 0x61, 0x29, 0x96, 0xea, 0x46, 0xcd, 0xf6, 0xa3, 0x40, 0x29, 0xf4,
-	0xf3, 0xbc, 0x80, 0xfc, 0x07, 0xbb, 0xef, 0x9a, 0xfa, 0xd0, 0x18, 0x4b, 0x02, 0x3b, 0x59, 0x35,
-	0x5e, 0xd7, 0xe6, 0xbf, 0x28, 0xe2, 0xd0, 0x18, 0x7b, 0xe7, 0xca, 0x7d, 0xe0, 0x22, 0xda, 0xa6,
-	0xdb, 0xc0, 0xe8, 0x31, 0xdf, 0x04, 0xf3, 0xbd, 0x3f, 0xe5, 0x4b, 0x4c, 0xa3, 0x17, 0xb8, 0x66,
-	0x6d, 0x4f, 0xaa, 0xbc, 0x80, 0x62, 0x24, 0xe8, 0x4d, 0x4e, 0x40, 0x47, 0x2d, 0x1c, 0xf5, 0x46,
-	0xa7, 0xff, 0x4f, 0x0a, 0xe0, 0x7a, 0x55, 0xcd, 0x5d, 0xda, 0x6c, 0x97, 0x24, 0x7e, 0x69, 0x97,
-	0x94, 0x21, 0x63, 0x9b, 0xce, 0x64, 0xe0, 0x6d, 0xeb, 0x02, 0xe1, 0x52, 0x78, 0xc7, 0xa4, 0xe2,
-	0x3b, 0x26, 0x44, 0x77, 0x9a, 0x51, 0xf6, 0x60, 0x6a, 0x81, 0xce, 0xe5, 0xfb, 0xaf, 0x21, 0xbe,
-	0x33, 0xb1, 0xbb, 0x0a, 0x9c, 0x67, 0x12, 0x8e, 0x9e, 0x41, 0xc6, 0x3c, 0x37, 0x47, 0xae, 0x23,
-	0x65, 0x99, 0x63, 0x25, 0x72, 0x2a, 0x99, 0x0f, 0x02, 0xa6, 0x10, 0xc2, 0x91, 0xd3, 0xe3, 0x95,
-	0xfb, 0x95, 0xe3, 0x95, 0x9f, 0x3d, 0x5e, 0xe1, 0x9e, 0x86, 0x68, 0x4f, 0x7f, 0xb5, 0x7e, 0xe8,
-	0xc1, 0xca, 0x0c, 0x0a, 0xa8, 0x83, 0x6b, 0x8d, 0xfb, 0x5d, 0x1e, 0xc4, 0x13, 0xa6, 0x09, 0x49,
-	0xcc, 0x20, 0x04, 0x41, 0xaa, 0x67, 0xb8, 0x06, 0xfb, 0x86, 0xe4, 0x09, 0xfb, 0x5d, 0xff, 0x24,
-	0xc0, 0x0a, 0xfd, 0x84, 0xf8, 0x49, 0x6e, 0xb4, 0xc0, 0x9e, 0x40, 0xc0, 0xa4, 0xce, 0xd7, 0x93,
-	0x97, 0xb7, 0xe4, 0xab, 0x0f, 0xbd, 0x35, 0xb5, 0x0d, 0x29, 0xc3, 0x3e, 0x75, 0xf8, 0xd7, 0xeb,
-	0x91, 0xff, 0x74, 0x98, 0xce, 0xbb, 0xd9, 0xb0, 0x4f, 0xf9, 0x92, 0x62, 0x1e, 0x95, 0xbf, 0x40,
-	0x3e, 0x50, 0xdd, 0x88, 0xbe, 0x63, 0xc8, 0x05, 0xb3, 0x54, 0x86, 0x8c, 0xe3, 0x1a, 0xee, 0xc4,
-	0x61, 0xae, 0x69, 0xc2, 0xa5, 0x2f, 0x7c, 0x71, 0xa5, 0xf0, 0x17, 0x97, 0x46, 0xf6, 0xc5, 0xfa,
-	0x55, 0x02, 0xc4, 0xeb, 0xa2, 0x79, 0x82, 0x3f, 0x84, 0x1e, 0x1f, 0x02, 0x5b, 0xa1, 0xfe, 0x27,
-	0x6a, 0xc6, 0xd3, 0x63, 0x27, 0x3c, 0x69, 0xde, 0x72, 0x7a, 0xec, 0x33, 0x12, 0x0b, 0x3c, 0x77,
-	0xde, 0x5e, 0x86, 0xe6, 0x2d, 0x46, 0x6a, 0x3c, 0xc4, 0xec, 0xa9, 0x7b, 0x14, 0x4c, 0x9d, 0xf7,
-	0xd1, 0x28, 0x70, 0xf7, 0xc8, 0x9c, 0x7d, 0xbd, 0xe6, 0x3f, 0x86, 0xf4, 0x6f, 0xd1, 0xee, 0x1b,
-	0x9f, 0xd2, 0x50, 0x08, 0xbf, 0x71, 0x51, 0x11, 0xf2, 0x47, 0x6a, 0x13, 0xbf, 0x52, 0x54, 0xdc,
-	0x14, 0x17, 0x50, 0x01, 0x72, 0x04, 0xef, 0x2a, 0x1d, 0x0d, 0x13, 0x51, 0x40, 0x25, 0x00, 0x5f,
-	0xc2, 0x4d, 0x31, 0x81, 0x16, 0x21, 0xdb, 0x26, 0xb8, 0xdd, 0x20, 0x58, 0x4c, 0xa2, 0x3c, 0xa4,
-	0x09, 0x6e, 0x34, 0x4f, 0xc4, 0x14, 0xca, 0x41, 0x4a, 0x51, 0x15, 0x4d, 0x4c, 0x23, 0x80, 0x8c,
-	0xa2, 0x1e, 0xb7, 0xf6, 0xb1, 0x98, 0xa1, 0xde, 0xda, 0x6b, 0x9d, 0xe0, 0x7f, 0x1e, 0xe1, 0x8e,
-	0x26, 0x66, 0xd1, 0x12, 0x2c, 0x32, 0xb9, 0xd3, 0x6e, 0xa9, 0x1d, 0x2c, 0xe6, 0xd0, 0x2a, 0x2c,
-	0xef, 0x62, 0x4d, 0xef, 0x68, 0x0d, 0x0d, 0x07, 0xb8, 0x3c, 0x2a, 0x03, 0x0a, 0xab, 0x39, 0x1c,
-	0x90, 0x04, 0xb7, 0xa8, 0x7e, 0xe7, 0x44, 0xc3, 0x72, 0xab, 0x79, 0xed, 0xb1, 0x88, 0xee, 0xc0,
-	0x6a, 0xcc, 0xc2, 0x9d, 0x0a, 0xd4, 0x24, 0x37, 0x0e, 0x0e, 0x74, 0xb9, 0xa5, 0x6a, 0xa4, 0x21,
-	0x6b, 0x81, 0x57, 0x11, 0x55, 0xa0, 0x1c, 0x37, 0x71, 0xb7, 0x12, 0xa5, 0x45, 0x6e, 0x1d, 0xb6,
-	0x0f, 0xb0, 0x86, 0x9b, 0xe2, 0x12, 0x3d, 0x2b, 0x26, 0xa4, 0x45, 0x44, 0x11, 0x55, 0xa1, 0x22,
-	0x13, 0x4c, 0x4b, 0xdb, 0x3f, 0xd6, 0x15, 0x0d, 0x93, 0x86, 0xd6, 0x22, 0x41, 0xd4, 0x65, 0xf4,
-	0x00, 0xee, 0xce, 0xb4, 0xf3, 0xd0, 0x88, 0x01, 0x5a, 0x6a, 0xe7, 0xe8, 0x70, 0x76, 0x84, 0x15,
-	0x54, 0x83, 0x7b, 0xb3, 0x01, 0x3c, 0xc4, 0x2d, 0xb4, 0x06, 0x0f, 0xfc, 0x1c, 0xf8, 0x44, 0xdf,
-	0x53, 0x3a, 0x5a, 0x8b, 0x9c, 0x30, 0x64, 0x10, 0x66, 0x75, 0x0e, 0xc8, 0xc3, 0xf0, 0x48, 0x65,
-	0xf4, 0x08, 0x6a, 0x41, 0xae, 0x79, 0xa1, 0x6e, 0xa3, 0xc7, 0xf0, 0xf0, 0x0b, 0x28, 0x1e, 0x4c,
-	0xa2, 0xd4, 0xb0, 0x8b, 0xc3, 0x6a, 0x13, 0x13, 0xbd, 0xd1, 0x6c, 0x12, 0xdc, 0xe9, 0x04, 0x61,
-	0xee, 0xd0, 0x93, 0xcf, 0xb4, 0xf3, 0x00, 0x15, 0x74, 0x17, 0x6e, 0xb3, 0x7b, 0x6c, 0x68, 0xf2,
-	0x5e, 0xac, 0x2d, 0xee, 0xa2, 0x7b, 0x20, 0x4d, 0x1b, 0xb9, 0xeb, 0xbd, 0x8d, 0xff, 0x65, 0x20,
-	0xe7, 0x3f, 0x87, 0x69, 0x21, 0xe4, 0x48, 0xd5, 0x94, 0x43, 0xac, 0xf3, 0x7e, 0xd5, 0x43, 0x9d,
-	0xb8, 0x40, 0x19, 0xf6, 0xed, 0xbb, 0xa4, 0x2d, 0xb3, 0x8a, 0xc2, 0x08, 0x01, 0xd5, 0xa1, 0x8a,
-	0xd5, 0x5d, 0x45, 0xe5, 0x00, 0x82, 0x65, 0xac, 0x1c, 0x47, 0xa2, 0x24, 0xd0, 0x13, 0x58, 0xe3,
-	0x98, 0x8e, 0xbc, 0x87, 0x9b, 0x47, 0x07, 0x8c, 0x8d, 0x29, 0x60, 0x92, 0x92, 0x3c, 0x05, 0x8c,
-	0xa7, 0x4c, 0xd1, 0xfb, 0x0a, 0x52, 0xb6, 0x8e, 0xda, 0xb3, 0x42, 0xa5, 0x69, 0xe5, 0x11, 0x50,
-	0x3c, 0x4c, 0x86, 0xde, 0x15, 0x47, 0xb4, 0x49, 0x4b, 0xf6, 0x08, 0x9e, 0x0a, 0x94, 0x0d, 0x1d,
-	0xd0, 0x87, 0xc5, 0x43, 0xe5, 0xd0, 0xef, 0xa0, 0xfe, 0xa5, 0x50, 0x9c, 0xfb, 0x3c, 0xad, 0xbc,
-	0xd3, 0x50, 0x9b, 0x3b, 0xad, 0xd7, 0x73, 0xd9, 0x02, 0x5a, 0x79, 0x04, 0x14, 0x4f, 0xb7, 0x18,
-	0x46, 0xc8, 0x7b, 0x0d, 0x75, 0x0a, 0x51, 0xa0, 0x05, 0xf9, 0x88, 0xbd, 0x86, 0xda, 0x9c, 0x43,
-	0x78, 0x91, 0xf6, 0x51, 0x1c, 0x87, 0x5f, 0x63, 0xf9, 0x48, 0xa3, 0xa3, 0x1d, 0x32, 0xb2, 0x0c,
-	0xf2, 0x5e, 0x43, 0x51, 0xd9, 0x71, 0xc4, 0xa5, 0xd9, 0x55, 0x86, 0x10, 0x22, 0x6d, 0xc3, 0x88,
-	0x3b, 0x67, 0x88, 0x59, 0x97, 0xe9, 0x55, 0x47, 0x3a, 0x6b, 0x16, 0x61, 0x88, 0x92, 0x1f, 0xa0,
-	0xb0, 0xa6, 0xab, 0x2d, 0x4d, 0x79, 0x75, 0x12, 0xc1, 0xac, 0xd0, 0xee, 0xf2, 0x31, 0x33, 0xcf,
-	0x1a, 0x2c, 0x83, 0x50, 0xb3, 0x7b, 0xc0, 0x88, 0x7d, 0x75, 0xa3, 0x76, 0xbd, 0xf1, 0xe9, 0x03,
-	0x19, 0x65, 0x20, 0xd1, 0xda, 0x17, 0x17, 0xe8, 0xd2, 0x7e, 0xd5, 0x50, 0x0e, 0x44, 0xe1, 0xd9,
-	0x1b, 0x58, 0xf4, 0x11, 0x64, 0xdc, 0x45, 0xfb, 0xfe, 0xc3, 0x8b, 0x3a, 0x0c, 0x87, 0x93, 0x51,
-	0xbf, 0x6b, 0xb8, 0x26, 0x2a, 0xc7, 0x5e, 0xdb, 0xfc, 0x9f, 0xee, 0xca, 0x1c, 0x7d, 0x7d, 0x61,
-	0x5d, 0xf8, 0xa3, 0xb0, 0xa3, 0x7e, 0xfb, 0xb9, 0x2a, 0x7c, 0xfc, 0x5c, 0x15, 0x7e, 0xf8, 0x5c,
-	0x15, 0xfe, 0x7b, 0x55, 0x5d, 0xf8, 0x78, 0x55, 0x5d, 0xf8, 0xfe, 0xaa, 0xba, 0xf0, 0xe6, 0xcf,
-	0xec, 0x85, 0x39, 0x34, 0xce, 0x4c, 0x7b, 0xd3, 0xb2, 0x4f, 0xb7, 0xae, 0xc5, 0x2d, 0xff, 0x33,
-	0xf6, 0xd4, 0xe9, 0x9d, 0x3d, 0x3d, 0xb5, 0xb6, 0xc6, 0x6f, 0xb7, 0x58, 0x8e, 0x53, 0xeb, 0x6d,
-	0x86, 0xfd, 0xf8, 0xd3, 0xcf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x18, 0xd1, 0x4f, 0x7e, 0x07, 0x12,
-	0x00, 0x00,
+	// 1693 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcd, 0x72, 0xdb, 0xc8,
+	0x11, 0x16, 0xf8, 0xcf, 0x16, 0x48, 0xc1, 0x23, 0x4b, 0x0b, 0xd3, 0x36, 0xcd, 0xa5, 0xed, 0x58,
+	0x71, 0xca, 0x52, 0xca, 0x49, 0x55, 0x1c, 0x6f, 0x52, 0x1b, 0x1a, 0x1c, 0x5b, 0x2c, 0x4b, 0x20,
+	0x33, 0x04, 0x15, 0x6b, 0x2f, 0x28, 0x2c, 0x39, 0x2b, 0xb1, 0x4c, 0x12, 0x0c, 0x30, 0xf4, 0x4a,
+	0x4f, 0x91, 0xdc, 0xf2, 0x1a, 0xa9, 0x3c, 0x45, 0x2e, 0xa9, 0xda, 0xd3, 0x56, 0x8e, 0x29, 0xeb,
+	0x31, 0x92, 0x43, 0x6a, 0x06, 0x03, 0x08, 0x04, 0x41, 0x27, 0x3a, 0x6c, 0xed, 0x49, 0xec, 0xee,
+	0xaf, 0x7f, 0xe6, 0x9b, 0xee, 0xc6, 0x08, 0x76, 0x47, 0xee, 0xf0, 0x3d, 0xf5, 0x3e, 0x4c, 0xed,
+	0x29, 0xf5, 0x7d, 0xe7, 0x8c, 0xee, 0xcf, 0x3d, 0x97, 0xb9, 0x28, 0x2f, 0xfe, 0x34, 0xff, 0x93,
+	0x81, 0xad, 0xb6, 0x40, 0x9c, 0x1c, 0x1f, 0x07, 0x00, 0xb4, 0x0d, 0x79, 0x76, 0x61, 0x8f, 0x47,
+	0xba, 0xd2, 0x50, 0xf6, 0xca, 0x24, 0xc7, 0x2e, 0x3a, 0x23, 0xf4, 0x04, 0x72, 0xec, 0x72, 0x4e,
+	0xf5, 0x4c, 0x43, 0xd9, 0xab, 0x3e, 0xdf, 0x0e, 0xa2, 0xec, 0x87, 0xae, 0xd6, 0xe5, 0x9c, 0x12,
+	0x01, 0x40, 0x2f, 0xa0, 0x32, 0xf4, 0x5c, 0xdf, 0xb7, 0x87, 0xee, 0x8c, 0xd1, 0x0b, 0xa6, 0x67,
+	0x1b, 0xca, 0xde, 0x66, 0xe4, 0x61, 0x70, 0x9b, 0x11, 0x98, 0x88, 0x3a, 0x8c, 0x49, 0xe8, 0x4b,
+	0xd0, 0xfc, 0x4b, 0xdf, 0x1e, 0x3a, 0x93, 0x49, 0x58, 0xac, 0x9e, 0x13, 0xce, 0x3b, 0xd2, 0xb9,
+	0x7f, 0xe9, 0x1b, 0xce, 0x64, 0x22, 0x0b, 0x25, 0x55, 0x7f, 0x49, 0x46, 0x4f, 0xa1, 0xe8, 0xd1,
+	0x3f, 0x2e, 0xa8, 0xcf, 0xf4, 0xbc, 0xf0, 0xd3, 0xa4, 0x9f, 0x75, 0x41, 0x02, 0x3d, 0x09, 0x01,
+	0xe8, 0x19, 0x94, 0x3c, 0xea, 0xcf, 0xdd, 0x99, 0x4f, 0xf5, 0x82, 0x00, 0xdf, 0x8a, 0x81, 0x03,
+	0x03, 0x89, 0x20, 0xe8, 0x0e, 0x94, 0x86, 0xe7, 0xce, 0x78, 0xc6, 0x69, 0x29, 0x0a, 0x5a, 0x8a,
+	0x42, 0xee, 0x8c, 0xd0, 0x4b, 0xa8, 0xfa, 0x8c, 0xce, 0xed, 0xd1, 0xc2, 0x73, 0xd8, 0xd8, 0x9d,
+	0xf9, 0x7a, 0xa9, 0x91, 0x8d, 0x9d, 0xb8, 0xcf, 0xe8, 0xbc, 0x2d, 0x6d, 0xa4, 0xe2, 0xc7, 0x24,
+	0xbf, 0xf9, 0x0f, 0x05, 0xaa, 0xcb, 0x87, 0xe2, 0x44, 0x0f, 0xdd, 0x11, 0xd5, 0x95, 0x54, 0xa2,
+	0x0d, 0x77, 0x44, 0x89, 0x00, 0x20, 0x1d, 0x8a, 0x21, 0x4b, 0x99, 0xa0, 0x22, 0x29, 0xa2, 0xdf,
+	0x40, 0x71, 0xee, 0x5c, 0x4e, 0x5c, 0x67, 0xa4, 0x67, 0x45, 0x29, 0xcd, 0x54, 0xfe, 0xf6, 0x7b,
+	0x01, 0x08, 0xcf, 0x98, 0x77, 0x49, 0x42, 0x97, 0xda, 0x4b, 0x50, 0xe3, 0x06, 0xa4, 0x41, 0xf6,
+	0x3d, 0xbd, 0x94, 0xcd, 0xc0, 0x7f, 0xa2, 0xdb, 0x90, 0xff, 0xe0, 0x4c, 0x16, 0x41, 0x5e, 0x95,
+	0x04, 0xc2, 0xcb, 0xcc, 0x0b, 0xa5, 0xb9, 0x00, 0x35, 0x7e, 0xc1, 0xe8, 0x21, 0x54, 0x86, 0x0b,
+	0xcf, 0xa3, 0x33, 0x66, 0x8f, 0xe8, 0x9c, 0x9d, 0x8b, 0x28, 0x15, 0xa2, 0x4a, 0x65, 0x9b, 0xeb,
+	0xd0, 0xe7, 0xa0, 0xce, 0x3d, 0x77, 0x48, 0x7d, 0xdf, 0x9e, 0x39, 0xd3, 0xf0, 0x34, 0x9b, 0x52,
+	0x67, 0x3a, 0x53, 0x8a, 0xee, 0x03, 0x04, 0x4d, 0x35, 0x9e, 0x7d, 0xe3, 0x8a, 0x8e, 0xca, 0x91,
+	0xb2, 0xd0, 0x74, 0x66, 0xdf, 0xb8, 0xcd, 0xbf, 0x2a, 0xa0, 0xc6, 0x69, 0x46, 0x0f, 0x65, 0xb7,
+	0x06, 0x24, 0x6e, 0xc5, 0x6e, 0x22, 0xd6, 0xa9, 0xf7, 0x01, 0x7c, 0xe6, 0x78, 0xcc, 0x66, 0x63,
+	0x99, 0x35, 0x4b, 0xca, 0x42, 0x63, 0x8d, 0xa7, 0x94, 0xd7, 0xbe, 0x74, 0xaf, 0x22, 0x6d, 0x96,
+	0xa8, 0xf1, 0x1b, 0x44, 0x8f, 0xa1, 0xba, 0x98, 0xb1, 0xf1, 0xe4, 0x1a, 0x95, 0x13, 0xa8, 0x8a,
+	0xd0, 0x46, 0x30, 0x0d, 0xb2, 0x53, 0xff, 0x4c, 0x74, 0x65, 0x99, 0xf0, 0x9f, 0xcd, 0x3f, 0x65,
+	0xa1, 0x1c, 0xb5, 0xa5, 0xe0, 0xc9, 0x9d, 0x31, 0xcf, 0x19, 0xb2, 0x80, 0x83, 0x80, 0x6d, 0x35,
+	0x54, 0x0a, 0x12, 0x7e, 0x0a, 0x5a, 0x04, 0xfa, 0x40, 0x3d, 0x9f, 0x67, 0x0b, 0xb8, 0xda, 0x0a,
+	0xf5, 0x27, 0x81, 0x1a, 0xed, 0x42, 0x61, 0x4a, 0xd9, 0xb9, 0x3b, 0x12, 0x45, 0x97, 0x89, 0x94,
+	0xd0, 0xef, 0x00, 0xe6, 0x8e, 0xe7, 0x4c, 0x29, 0xa3, 0x9e, 0xaf, 0xe7, 0x44, 0x73, 0x34, 0x92,
+	0x43, 0xb2, 0xdf, 0x8b, 0x20, 0x41, 0x6b, 0xc4, 0x7c, 0xd0, 0x01, 0x00, 0xbb, 0x88, 0x66, 0x3b,
+	0x39, 0x66, 0xe1, 0x60, 0x97, 0x59, 0xf8, 0x73, 0x69, 0x72, 0x0a, 0xcb, 0x93, 0x23, 0x4e, 0x3d,
+	0x9d, 0x2f, 0x18, 0xb5, 0x27, 0xe3, 0xe9, 0x98, 0xe9, 0x65, 0x71, 0xb1, 0xaa, 0x54, 0x1e, 0x71,
+	0x1d, 0xda, 0x03, 0xed, 0xdc, 0x91, 0x5b, 0x21, 0x4c, 0x0b, 0x0d, 0x65, 0xaf, 0x44, 0xaa, 0xe7,
+	0x8e, 0x68, 0x67, 0x99, 0xa9, 0xf6, 0x5b, 0xd8, 0x4a, 0x54, 0x7e, 0xa3, 0xde, 0xfd, 0xb7, 0xc2,
+	0x6f, 0x24, 0x2c, 0xfb, 0x0b, 0x28, 0x7f, 0xeb, 0x8d, 0x19, 0xb5, 0xa7, 0xce, 0x5c, 0x57, 0x04,
+	0x51, 0xf5, 0xe4, 0x31, 0xf7, 0xff, 0xc0, 0x11, 0xc7, 0xce, 0x3c, 0xa0, 0xa9, 0xf4, 0xad, 0x14,
+	0xd1, 0x0b, 0xbe, 0x5c, 0x9c, 0x91, 0xf0, 0xcd, 0x08, 0xdf, 0xfb, 0x2b, 0xbe, 0x84, 0x3a, 0xa3,
+	0xc8, 0xb5, 0xe8, 0x05, 0x52, 0xed, 0x0b, 0xa8, 0x2c, 0x05, 0xbd, 0xc9, 0x09, 0xf8, 0xe4, 0xc6,
+	0xa3, 0xde, 0xe8, 0xf4, 0x7f, 0xcb, 0x01, 0x5c, 0x6f, 0xbe, 0xb5, 0xdf, 0x00, 0xb1, 0x9a, 0x32,
+	0xff, 0x6b, 0x35, 0xed, 0x42, 0xc1, 0xa3, 0xfe, 0x62, 0x12, 0x2c, 0x7f, 0x95, 0x48, 0x29, 0xbe,
+	0xb2, 0x72, 0xc9, 0x95, 0x15, 0xa3, 0x3b, 0x2f, 0x28, 0x7b, 0xb0, 0xb2, 0x8f, 0xd7, 0xf2, 0xfd,
+	0xeb, 0x18, 0xdf, 0x85, 0xc4, 0x5d, 0x45, 0xce, 0xa9, 0x84, 0xa3, 0xe7, 0x50, 0xa0, 0x1f, 0xe8,
+	0x8c, 0xf9, 0x7a, 0x51, 0x38, 0xd6, 0x96, 0x4e, 0x65, 0xc8, 0xb9, 0xc2, 0x1c, 0x42, 0x24, 0x72,
+	0x75, 0x5a, 0x4b, 0xff, 0xe7, 0xb4, 0x96, 0xd3, 0xa7, 0x35, 0x3e, 0x22, 0xb0, 0x76, 0x44, 0x16,
+	0xb3, 0x31, 0xf3, 0x75, 0x75, 0x69, 0x44, 0x06, 0x5c, 0xf7, 0xe3, 0x35, 0xcd, 0x08, 0xb6, 0x53,
+	0x78, 0xe2, 0x0e, 0xcc, 0x9d, 0x8f, 0x87, 0x32, 0x48, 0x20, 0xac, 0xb2, 0x96, 0x49, 0x61, 0x0d,
+	0x41, 0x6e, 0xe4, 0x30, 0x47, 0x7c, 0xb7, 0xca, 0x44, 0xfc, 0x6e, 0x7e, 0xaf, 0xc0, 0x76, 0x38,
+	0xe7, 0x1c, 0x78, 0xa3, 0xa5, 0xf9, 0x04, 0x22, 0xba, 0x6d, 0xb9, 0x12, 0x83, 0xbc, 0xd5, 0x50,
+	0x7d, 0x2c, 0xb4, 0xe8, 0x05, 0xe4, 0x1c, 0xef, 0xcc, 0x97, 0x5f, 0xcc, 0x47, 0xe1, 0x73, 0x65,
+	0x35, 0xef, 0x7e, 0xcb, 0x3b, 0x93, 0x8b, 0x51, 0x78, 0xd4, 0x7e, 0x05, 0xe5, 0x48, 0x75, 0x23,
+	0xfa, 0x4e, 0xa0, 0x14, 0x0d, 0xdc, 0x2e, 0x14, 0x7c, 0xe6, 0xb0, 0x85, 0x2f, 0x5c, 0xf3, 0x44,
+	0x4a, 0x9f, 0xf8, 0xca, 0xeb, 0xf1, 0xaf, 0x3c, 0x8f, 0x1c, 0x8a, 0xcd, 0xab, 0x0c, 0x68, 0xd7,
+	0x45, 0xcb, 0x04, 0x3f, 0x8b, 0x3d, 0x78, 0x14, 0xb1, 0xb6, 0xc3, 0xcf, 0x62, 0xca, 0x73, 0xe7,
+	0x55, 0x7c, 0x1c, 0x83, 0x0d, 0xf6, 0x38, 0x64, 0x24, 0x11, 0x78, 0xed, 0x50, 0x7e, 0x19, 0x1b,
+	0xca, 0x04, 0xa9, 0xc9, 0x10, 0xe9, 0xa3, 0xf9, 0x28, 0x1a, 0xcd, 0xe0, 0x43, 0xa5, 0x4a, 0xf7,
+	0xa5, 0x61, 0xfc, 0xf1, 0x9a, 0xff, 0x04, 0xf2, 0x3f, 0x44, 0xbb, 0x3f, 0xfd, 0x3e, 0x0f, 0x6a,
+	0xfc, 0x5d, 0x8d, 0x2a, 0x50, 0x1e, 0x98, 0x6d, 0xfc, 0xba, 0x63, 0xe2, 0xb6, 0xb6, 0x81, 0x54,
+	0x28, 0x11, 0xfc, 0xa6, 0xd3, 0xb7, 0x30, 0xd1, 0x14, 0x54, 0x05, 0x08, 0x25, 0xdc, 0xd6, 0x32,
+	0x68, 0x13, 0x8a, 0x3d, 0x82, 0x7b, 0x2d, 0x82, 0xb5, 0x2c, 0x2a, 0x43, 0x9e, 0xe0, 0x56, 0xfb,
+	0x54, 0xcb, 0xa1, 0x12, 0xe4, 0x3a, 0x66, 0xc7, 0xd2, 0xf2, 0x08, 0xa0, 0xd0, 0x31, 0x4f, 0xba,
+	0x6f, 0xb1, 0x56, 0xe0, 0xde, 0xd6, 0x3b, 0x9b, 0xe0, 0xdf, 0x0f, 0x70, 0xdf, 0xd2, 0x8a, 0x68,
+	0x0b, 0x36, 0x85, 0xdc, 0xef, 0x75, 0xcd, 0x3e, 0xd6, 0x4a, 0x68, 0x07, 0x6e, 0xbd, 0xc1, 0x96,
+	0xdd, 0xb7, 0x5a, 0x16, 0x8e, 0x70, 0x65, 0xb4, 0x0b, 0x28, 0xae, 0x96, 0x70, 0x40, 0x3a, 0xdc,
+	0xe6, 0xfa, 0x57, 0xa7, 0x16, 0x36, 0xba, 0xed, 0x6b, 0x8f, 0x4d, 0x74, 0x07, 0x76, 0x12, 0x16,
+	0xe9, 0xa4, 0x72, 0x93, 0xd1, 0x3a, 0x3a, 0xb2, 0x8d, 0xae, 0x69, 0x91, 0x96, 0x61, 0x45, 0x5e,
+	0x15, 0x54, 0x83, 0xdd, 0xa4, 0x49, 0xba, 0x55, 0x39, 0x2d, 0x46, 0xf7, 0xb8, 0x77, 0x84, 0x2d,
+	0xdc, 0xd6, 0xb6, 0xf8, 0x59, 0x31, 0x21, 0x5d, 0xa2, 0x69, 0xa8, 0x0e, 0x35, 0x83, 0x60, 0x5e,
+	0xda, 0xdb, 0x13, 0xbb, 0x63, 0x61, 0xd2, 0xb2, 0xba, 0x24, 0x8a, 0x7a, 0x0b, 0x3d, 0x80, 0xbb,
+	0xa9, 0x76, 0x19, 0x1a, 0x09, 0x40, 0xd7, 0xec, 0x0f, 0x8e, 0xd3, 0x23, 0x6c, 0xa3, 0x06, 0xdc,
+	0x4b, 0x07, 0xc8, 0x10, 0xb7, 0xd1, 0x43, 0x78, 0x10, 0xe6, 0xc0, 0xa7, 0xf6, 0x61, 0xa7, 0x6f,
+	0x75, 0xc9, 0xa9, 0x40, 0x46, 0x61, 0x76, 0xd6, 0x80, 0x2c, 0x1c, 0x8b, 0xb4, 0x8b, 0x1e, 0x41,
+	0x23, 0xca, 0xb5, 0x2e, 0xd4, 0x67, 0xe8, 0x31, 0x7c, 0xfe, 0x09, 0x94, 0x0c, 0xa6, 0x73, 0x6a,
+	0xc4, 0xc5, 0x61, 0xb3, 0x8d, 0x89, 0xdd, 0x6a, 0xb7, 0x09, 0xee, 0xf7, 0xa3, 0x30, 0x77, 0xf8,
+	0xc9, 0x53, 0xed, 0x32, 0x40, 0x0d, 0xdd, 0x85, 0xcf, 0xc4, 0x3d, 0xb6, 0x2c, 0xe3, 0x30, 0xd1,
+	0x16, 0x77, 0xd1, 0x3d, 0xd0, 0x57, 0x8d, 0xd2, 0xf5, 0xde, 0xd3, 0xbf, 0x14, 0xa0, 0x14, 0x3e,
+	0xc1, 0x79, 0x21, 0x64, 0x60, 0x5a, 0x9d, 0x63, 0x6c, 0xcb, 0x7e, 0xb5, 0x63, 0x9d, 0xb8, 0xc1,
+	0x19, 0x0e, 0xed, 0x6f, 0x48, 0xcf, 0x10, 0x15, 0xc5, 0x11, 0x0a, 0x6a, 0x42, 0x1d, 0x9b, 0x6f,
+	0x3a, 0xa6, 0x04, 0x10, 0x6c, 0xe0, 0xce, 0xc9, 0x52, 0x94, 0x0c, 0x7a, 0x02, 0x0f, 0x25, 0xa6,
+	0x6f, 0x1c, 0xe2, 0xf6, 0xe0, 0x08, 0x93, 0x34, 0x60, 0x96, 0x93, 0xbc, 0x02, 0x4c, 0xa6, 0xcc,
+	0xf1, 0xfb, 0x8a, 0x52, 0x76, 0x07, 0xbd, 0xb4, 0x50, 0x79, 0x5e, 0xf9, 0x12, 0x28, 0x19, 0xa6,
+	0xc0, 0xef, 0x4a, 0x22, 0x7a, 0xa4, 0x6b, 0x04, 0x04, 0xaf, 0x04, 0x2a, 0xc6, 0x0e, 0x18, 0xc2,
+	0x92, 0xa1, 0x4a, 0xe8, 0x27, 0xd0, 0xfc, 0x54, 0x28, 0xc9, 0x7d, 0x99, 0x57, 0xde, 0x6f, 0x99,
+	0xed, 0x57, 0xdd, 0x77, 0x6b, 0xd9, 0x02, 0x5e, 0xf9, 0x12, 0x28, 0x99, 0x6e, 0x33, 0x8e, 0x30,
+	0x0e, 0x5b, 0xe6, 0x0a, 0x42, 0xe5, 0x05, 0x85, 0x88, 0xc3, 0x96, 0xd9, 0x5e, 0x43, 0x78, 0x85,
+	0xf7, 0x51, 0x12, 0x87, 0xdf, 0x61, 0x63, 0x60, 0xf1, 0xd1, 0x8e, 0x19, 0x45, 0x06, 0xe3, 0xb0,
+	0xd5, 0x31, 0xc5, 0x71, 0xb4, 0xad, 0xf4, 0x2a, 0x63, 0x08, 0x8d, 0xb7, 0xe1, 0x92, 0xbb, 0x64,
+	0x48, 0x58, 0x6f, 0xf1, 0xab, 0x5e, 0xea, 0xac, 0x34, 0xc2, 0x10, 0x27, 0x3f, 0x42, 0x61, 0xcb,
+	0x36, 0xbb, 0x56, 0xe7, 0xf5, 0xe9, 0x12, 0x66, 0x9b, 0x77, 0x57, 0x88, 0x49, 0x3d, 0x6b, 0xb4,
+	0x0c, 0x62, 0xcd, 0x1e, 0x00, 0x97, 0xec, 0x3b, 0x4f, 0x1b, 0xa0, 0xc6, 0x5f, 0xd1, 0xa8, 0x00,
+	0x99, 0xee, 0x5b, 0x6d, 0x83, 0x2f, 0xed, 0xd7, 0xad, 0xce, 0x91, 0xa6, 0x3c, 0xff, 0x0a, 0x36,
+	0x43, 0x04, 0x99, 0x0f, 0xd1, 0x5b, 0xd8, 0xbe, 0x76, 0x98, 0x4e, 0x17, 0xb3, 0xf1, 0xd0, 0x61,
+	0x14, 0xed, 0x26, 0x9e, 0xe4, 0xf2, 0x1f, 0xfd, 0xda, 0x1a, 0x7d, 0x73, 0x63, 0x4f, 0xf9, 0xb9,
+	0xf2, 0xca, 0xfc, 0xfb, 0xc7, 0xba, 0xf2, 0xdd, 0xc7, 0xba, 0xf2, 0xaf, 0x8f, 0x75, 0xe5, 0xcf,
+	0x57, 0xf5, 0x8d, 0xef, 0xae, 0xea, 0x1b, 0xff, 0xbc, 0xaa, 0x6f, 0x7c, 0xf5, 0x4b, 0xf1, 0x0c,
+	0x9d, 0x3a, 0xef, 0xa9, 0xb7, 0xef, 0x7a, 0x67, 0x07, 0xd7, 0xe2, 0x41, 0xf8, 0x19, 0x7b, 0xe6,
+	0x8f, 0xde, 0x3f, 0x3b, 0x73, 0x0f, 0xe6, 0x5f, 0x1f, 0x88, 0x1c, 0x67, 0xee, 0xd7, 0x05, 0xf1,
+	0xe3, 0x17, 0xff, 0x1d, 0x00, 0x65, 0x9d, 0xfa, 0x3f, 0x7b, 0x12, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1650,6 +1679,21 @@ func (m *TxRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.HasCallContext {
+		i--
+		if m.HasCallContext {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.ComputeLimit != 0 {
+		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ComputeLimit))
+		i--
+		dAtA[i] = 0x48
+	}
 	if len(m.ChainId) > 0 {
 		i -= len(m.ChainId)
 		copy(dAtA[i:], m.ChainId)
@@ -1799,6 +1843,11 @@ func (m *TxResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ComputeUnits != 0 {
+		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ComputeUnits))
+		i--
+		dAtA[i] = 0x60
+	}
 	if len(m.ChainId) > 0 {
 		i -= len(m.ChainId)
 		copy(dAtA[i:], m.ChainId)
@@ -2351,6 +2400,12 @@ func (m *TxRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovDockervmMessage(uint64(l))
 	}
+	if m.ComputeLimit != 0 {
+		n += 1 + sovDockervmMessage(uint64(m.ComputeLimit))
+	}
+	if m.HasCallContext {
+		n += 2
+	}
 	return n
 }
 
@@ -2450,6 +2505,9 @@ func (m *TxResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovDockervmMessage(uint64(l))
 	}
+	if m.ComputeUnits != 0 {
+		n += 1 + sovDockervmMessage(uint64(m.ComputeUnits))
+	}
 	return n
 }
 
@@ -3736,6 +3794,45 @@ func (m *TxRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.ChainId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ComputeLimit", wireType)
+			}
+			m.ComputeLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ComputeLimit |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HasCallContext", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HasCallContext = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDockervmMessage(dAtA[iNdEx:])
@@ -4595,6 +4692,25 @@ func (m *TxResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.ChainId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ComputeUnits", wireType)
+			}
+			m.ComputeUnits = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ComputeUnits |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDockervmMessage(dAtA[iNdEx:])
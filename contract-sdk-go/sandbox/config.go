@@ -32,6 +32,7 @@ const (
 type Conf struct {
 	ProcessName          string
 	ContractName         string
+	ContractVersion      string
 	ContractAddr         string
 	LogLevel             string
 	ContractEngineClient *ContractEngineClientConf
@@ -67,10 +68,11 @@ func initConfig() error {
 	*/
 
 	config = &Conf{
-		ProcessName:  os.Args[1],
-		ContractName: os.Args[2],
-		ContractAddr: os.Getenv("CONTRACT_ADDRESS"),
-		LogLevel:     os.Args[4],
+		ProcessName:     os.Args[1],
+		ContractName:    os.Args[2],
+		ContractVersion: os.Args[3],
+		ContractAddr:    os.Getenv("CONTRACT_ADDRESS"),
+		LogLevel:        os.Args[4],
 		ContractEngineClient: &ContractEngineClientConf{
 			EngineUDSSockPath: os.Args[0],
 			MaxSendMsgSize:    maxSendMessageSize,
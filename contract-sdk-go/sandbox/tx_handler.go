@@ -11,6 +11,7 @@ SPDX-License-Identifier: Apache-2.0
 package sandbox
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -26,16 +27,17 @@ const (
 )
 
 type TxHandler struct {
-	sandboxLogger  *zap.SugaredLogger
-	contractLogger *zap.SugaredLogger
-	contract       sdk.Contract
-	contractName   string
-	contractAddr   string
-	processName    string
-	txId           string
-	originalTxId   string
-	crossCtx       *protogo.CrossContext
-	chainId        string
+	sandboxLogger   *zap.SugaredLogger
+	contractLogger  *zap.SugaredLogger
+	contract        sdk.Contract
+	contractName    string
+	contractAddr    string
+	contractVersion string
+	processName     string
+	txId            string
+	originalTxId    string
+	crossCtx        *protogo.CrossContext
+	chainId         string
 
 	sendSyscallMsg        func(msg *protogo.DockerVMMessage, responseNotify func(msg *protogo.DockerVMMessage))
 	txFinishMsgNotifyFunc func(signal *protogo.DockerVMMessage)
@@ -44,13 +46,14 @@ type TxHandler struct {
 
 func newTxHandler(contract sdk.Contract, processName, contractName, contractAddr string, logger *zap.SugaredLogger) *TxHandler {
 	return &TxHandler{
-		sandboxLogger:  logger,
-		contractLogger: newDockerLogger(contractLoggerModule, config.LogLevel),
-		contract:       contract,
-		contractName:   contractName,
-		contractAddr:   contractAddr,
-		processName:    processName,
-		pendingQueue:   make(chan *protogo.DockerVMMessage, 200),
+		sandboxLogger:   logger,
+		contractLogger:  newDockerLogger(contractLoggerModule, config.LogLevel),
+		contract:        contract,
+		contractName:    contractName,
+		contractAddr:    contractAddr,
+		contractVersion: config.ContractVersion,
+		processName:     processName,
+		pendingQueue:    make(chan *protogo.DockerVMMessage, 200),
 	}
 }
 
@@ -153,15 +156,9 @@ func (h *TxHandler) handleTxRequest(msg *protogo.DockerVMMessage) error {
 	}
 
 	currentStatus = Executing
-	var response protogo.Response
-	switch method {
-	case _initContract:
-		response = h.contract.InitContract()
-	case _upgradeContract:
-		response = h.contract.UpgradeContract()
-	default:
-		response = h.contract.InvokeContract(method)
-	}
+	sdk.ResetComputeMeter(msg.GetRequest().GetComputeLimit())
+	sdk.ResetCallContext(args, msg.GetRequest().GetHasCallContext(), h.crossCtx.GetCurrentDepth(), h.contractName)
+	response := h.invokeContract(method)
 
 	// construct complete message
 	writeMap := s.GetWriteMap()
@@ -169,8 +166,9 @@ func (h *TxHandler) handleTxRequest(msg *protogo.DockerVMMessage) error {
 	events := s.GetEvents()
 
 	txResponse := &protogo.TxResponse{
-		TxId:    h.txId,
-		ChainId: h.chainId,
+		TxId:         h.txId,
+		ChainId:      h.chainId,
+		ComputeUnits: sdk.ComputeUnits(),
 	}
 
 	signal := &protogo.DockerVMMessage{
@@ -245,3 +243,117 @@ func (h *TxHandler) handleTxRequest(msg *protogo.DockerVMMessage) error {
 
 	return nil
 }
+
+// invokeContract runs the contract method, a metered contract running out of compute units fails the tx
+func (h *TxHandler) invokeContract(method string) (response protogo.Response) {
+	defer func() {
+		if r := recover(); r != nil {
+			if r != sdk.ErrOutOfComputeGas {
+				panic(r)
+			}
+			h.sandboxLogger.Warnf("[%s] %s, consumed: [%d]", h.txId, sdk.ErrOutOfComputeGas, sdk.ComputeUnits())
+			response = sdk.Error(sdk.ErrOutOfComputeGas.Error())
+		}
+	}()
+
+	switch method {
+	case _initContract:
+		return h.saveStateVersion(h.saveEventSchemas(h.contract.InitContract()), true)
+	case _upgradeContract:
+		return h.upgradeContract()
+	case sdk.EventSchemasMethod:
+		return h.getEventSchemas()
+	case sdk.ContractSchemaMethod:
+		return h.getContractSchema()
+	case sdk.StateVersionMethod:
+		return h.getStateVersion()
+	default:
+		return h.contract.InvokeContract(method)
+	}
+}
+
+// upgradeContract runs the pending state migrations of contract before its UpgradeContract
+func (h *TxHandler) upgradeContract() protogo.Response {
+	if declarer, ok := h.contract.(sdk.MigrationDeclarer); ok {
+		result, err := sdk.RunMigrations(declarer.Migrations())
+		if err != nil {
+			h.sandboxLogger.Errorf("[%s] failed to migrate state, err: %s", h.txId, err)
+			return sdk.Error("failed to migrate state: " + err.Error())
+		}
+		h.sandboxLogger.Infof("[%s] state migrated from version %d to %d",
+			h.txId, result.FromVersion, result.ToVersion)
+	}
+
+	return h.saveStateVersion(h.saveEventSchemas(h.contract.UpgradeContract()), false)
+}
+
+// saveStateVersion save the contract version after it is installed or upgraded,
+// the state of a newly installed contract is created with the latest state schema
+func (h *TxHandler) saveStateVersion(response protogo.Response, install bool) protogo.Response {
+	if response.Status != sdk.OK {
+		return response
+	}
+
+	if err := sdk.PutContractVersion(h.contractVersion); err != nil {
+		return sdk.Error("failed to save contract version: " + err.Error())
+	}
+	if declarer, ok := h.contract.(sdk.MigrationDeclarer); ok && install {
+		latest := sdk.LatestStateSchemaVersion(declarer.Migrations())
+		if err := sdk.PutStateSchemaVersion(latest); err != nil {
+			return sdk.Error("failed to save state schema version: " + err.Error())
+		}
+	}
+	return response
+}
+
+// getStateVersion returns the contract version and state schema version saved on chain, in json
+func (h *TxHandler) getStateVersion() protogo.Response {
+	version, err := sdk.GetStateVersion()
+	if err != nil {
+		return sdk.Error("failed to get state version: " + err.Error())
+	}
+	versionBytes, err := json.Marshal(version)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(versionBytes)
+}
+
+// saveEventSchemas save the event schemas declared by contract after it is installed or upgraded
+func (h *TxHandler) saveEventSchemas(response protogo.Response) protogo.Response {
+	declarer, ok := h.contract.(sdk.EventSchemaDeclarer)
+	if !ok || response.Status != sdk.OK {
+		return response
+	}
+
+	if err := sdk.PutEventSchemas(declarer.EventSchemas()); err != nil {
+		h.sandboxLogger.Errorf("[%s] failed to save event schemas, err: %s", h.txId, err)
+		return sdk.Error("failed to save event schemas: " + err.Error())
+	}
+	return response
+}
+
+// getEventSchemas returns the event schemas saved on chain, in json
+func (h *TxHandler) getEventSchemas() protogo.Response {
+	schemasBytes, err := sdk.Instance.GetStateFromKeyByte(sdk.EventSchemasKey)
+	if err != nil {
+		return sdk.Error("failed to get event schemas: " + err.Error())
+	}
+	if len(schemasBytes) == 0 {
+		return sdk.Success([]byte("[]"))
+	}
+	return sdk.Success(schemasBytes)
+}
+
+// getContractSchema returns the method and event schemas declared by contract, in json
+func (h *TxHandler) getContractSchema() protogo.Response {
+	schema, err := sdk.NewContractSchema(h.contract)
+	if err != nil {
+		return sdk.Error("failed to get contract schema: " + err.Error())
+	}
+	schemaBytes, err := json.Marshal(schema)
+	if err != nil {
+		return sdk.Error("failed to marshal contract schema: " + err.Error())
+	}
+	return sdk.Success(schemaBytes)
+}
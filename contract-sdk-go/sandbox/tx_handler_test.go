@@ -0,0 +1,72 @@
+//go:build !crypto
+// +build !crypto
+
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sandbox
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"go.uber.org/zap"
+)
+
+// meteredContract meters units in every method call, as the code inserted by gasmeter does
+type meteredContract struct {
+	units uint64
+}
+
+func (c *meteredContract) InitContract() protogo.Response {
+	return sdk.Success(nil)
+}
+
+func (c *meteredContract) UpgradeContract() protogo.Response {
+	return sdk.Success(nil)
+}
+
+func (c *meteredContract) InvokeContract(method string) protogo.Response {
+	if method == "panic" {
+		panic(errors.New("contract panic"))
+	}
+	sdk.Meter(c.units)
+	return sdk.Success([]byte(method))
+}
+
+func TestTxHandler_invokeContract(t *testing.T) {
+	h := &TxHandler{sandboxLogger: zap.NewNop().Sugar(), contract: &meteredContract{units: 100}, txId: "tx1"}
+
+	tests := []struct {
+		name  string
+		limit uint64
+		want  protogo.Response
+	}{
+		{name: "unlimited", limit: 0, want: sdk.Success([]byte("transfer"))},
+		{name: "within limit", limit: 100, want: sdk.Success([]byte("transfer"))},
+		{name: "out of gas", limit: 99, want: sdk.Error(sdk.ErrOutOfComputeGas.Error())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk.ResetComputeMeter(tt.limit)
+			if got := h.invokeContract("transfer"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("invokeContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// other panics are not turned into an out of gas error
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("invokeContract() should panic")
+		}
+	}()
+	sdk.ResetComputeMeter(0)
+	h.invokeContract("panic")
+}
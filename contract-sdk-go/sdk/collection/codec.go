@@ -0,0 +1,161 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package collection
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Codec encodes the values of a collection into state values
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// KeyCodec encodes the keys of a collection into state fields. The encoding must keep the order of keys,
+// so that range iteration returns keys in order, and only use characters allowed in state fields
+type KeyCodec[K any] interface {
+	EncodeKey(key K) (string, error)
+	DecodeKey(field string) (K, error)
+}
+
+// JSON codec encoding values in json
+func JSON[T any]() Codec[T] {
+	return jsonCodec[T]{}
+}
+
+// String codec storing strings as is
+func String() Codec[string] {
+	return stringCodec{}
+}
+
+// Bytes codec storing bytes as is
+func Bytes() Codec[[]byte] {
+	return bytesCodec{}
+}
+
+// Uint64 codec storing uint64 in decimal
+func Uint64() Codec[uint64] {
+	return uint64Codec{}
+}
+
+// StringKey key codec using strings as fields, strings must be valid state fields
+func StringKey() KeyCodec[string] {
+	return stringKeyCodec{}
+}
+
+// HexKey key codec encoding bytes in hex
+func HexKey() KeyCodec[[]byte] {
+	return hexKeyCodec{}
+}
+
+// Uint64Key key codec encoding uint64 in zero padded decimal
+func Uint64Key() KeyCodec[uint64] {
+	return uint64KeyCodec{}
+}
+
+// Int64Key key codec encoding int64 in zero padded decimal, offset so that negative keys come first
+func Int64Key() KeyCodec[int64] {
+	return int64KeyCodec{}
+}
+
+type jsonCodec[T any] struct{}
+
+func (jsonCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+type stringCodec struct{}
+
+func (stringCodec) Encode(value string) ([]byte, error) {
+	return []byte(value), nil
+}
+
+func (stringCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
+
+type bytesCodec struct{}
+
+func (bytesCodec) Encode(value []byte) ([]byte, error) {
+	return value, nil
+}
+
+func (bytesCodec) Decode(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+type uint64Codec struct{}
+
+func (uint64Codec) Encode(value uint64) ([]byte, error) {
+	return []byte(strconv.FormatUint(value, 10)), nil
+}
+
+func (uint64Codec) Decode(data []byte) (uint64, error) {
+	return strconv.ParseUint(string(data), 10, 64)
+}
+
+type stringKeyCodec struct{}
+
+func (stringKeyCodec) EncodeKey(key string) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("key is empty")
+	}
+	return key, nil
+}
+
+func (stringKeyCodec) DecodeKey(field string) (string, error) {
+	return field, nil
+}
+
+type hexKeyCodec struct{}
+
+func (hexKeyCodec) EncodeKey(key []byte) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("key is empty")
+	}
+	return hex.EncodeToString(key), nil
+}
+
+func (hexKeyCodec) DecodeKey(field string) ([]byte, error) {
+	return hex.DecodeString(field)
+}
+
+type uint64KeyCodec struct{}
+
+func (uint64KeyCodec) EncodeKey(key uint64) (string, error) {
+	return fmt.Sprintf("%020d", key), nil
+}
+
+func (uint64KeyCodec) DecodeKey(field string) (uint64, error) {
+	return strconv.ParseUint(field, 10, 64)
+}
+
+type int64KeyCodec struct{}
+
+func (int64KeyCodec) EncodeKey(key int64) (string, error) {
+	return fmt.Sprintf("%020d", uint64(key)+math.MaxInt64+1), nil
+}
+
+func (int64KeyCodec) DecodeKey(field string) (int64, error) {
+	v, err := strconv.ParseUint(field, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(v - math.MaxInt64 - 1), nil
+}
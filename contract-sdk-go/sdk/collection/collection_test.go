@@ -0,0 +1,166 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package collection
+
+import (
+	"reflect"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+type order struct {
+	Owner  string `json:"owner"`
+	Price  uint64 `json:"price"`
+	Serial string `json:"serial"`
+}
+
+func TestMapIndex(t *testing.T) {
+	sdk.Instance = sdk.NewMemorySDK(nil, nil)
+
+	orders := NewMap[uint64, *order]("orders", Uint64Key(), JSON[*order]())
+	byOwner := NewIndex(orders, "owner", StringKey(), func(o *order) (string, bool) {
+		return o.Owner, true
+	})
+	bySerial := NewUniqueIndex(orders, "serial", StringKey(), func(o *order) (string, bool) {
+		return o.Serial, o.Serial != ""
+	})
+
+	for i, o := range []*order{
+		{Owner: "alice", Price: 10, Serial: "s1"},
+		{Owner: "bob", Price: 20},
+		{Owner: "alice", Price: 30, Serial: "s3"},
+	} {
+		if err := orders.Set(uint64(i+1), o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := byOwner.Keys("alice")
+	if err != nil || !reflect.DeepEqual(keys, []uint64{1, 3}) {
+		t.Fatalf("byOwner.Keys(alice) = %v, %v", keys, err)
+	}
+
+	// moving an order to another owner updates the index
+	if err = orders.Set(3, &order{Owner: "bob", Price: 30, Serial: "s3"}); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ = byOwner.Keys("alice")
+	if !reflect.DeepEqual(keys, []uint64{1}) {
+		t.Errorf("byOwner.Keys(alice) after move = %v", keys)
+	}
+	keys, _ = byOwner.Keys("bob")
+	if !reflect.DeepEqual(keys, []uint64{2, 3}) {
+		t.Errorf("byOwner.Keys(bob) after move = %v", keys)
+	}
+
+	if err = orders.Set(4, &order{Owner: "carol", Serial: "s1"}); err == nil {
+		t.Errorf("duplicate unique index key should fail")
+	}
+	if keys, _ = byOwner.Keys("carol"); len(keys) != 0 {
+		t.Errorf("byOwner.Keys(carol) after failed set = %v", keys)
+	}
+	// a failed update keeps the index entry of the old value
+	if err = orders.Set(3, &order{Owner: "bob", Price: 30, Serial: "s1"}); err == nil {
+		t.Errorf("moving to a held unique index key should fail")
+	}
+	key, o, ok, err := bySerial.Get("s3")
+	if err != nil || !ok || key != 3 || o.Price != 30 {
+		t.Errorf("bySerial.Get(s3) = %v, %v, %v, %v", key, o, ok, err)
+	}
+
+	if err = orders.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok, _ = bySerial.Get("s1"); ok {
+		t.Errorf("index entry of deleted order still exists")
+	}
+
+	var owners []string
+	err = byOwner.Range("a", "c", func(owner string, _ uint64, _ *order) (bool, error) {
+		owners = append(owners, owner)
+		return false, nil
+	})
+	if err != nil || !reflect.DeepEqual(owners, []string{"bob", "bob"}) {
+		t.Errorf("byOwner.Range(a, c) = %v, %v", owners, err)
+	}
+}
+
+func TestMapPage(t *testing.T) {
+	sdk.Instance = sdk.NewMemorySDK(nil, nil)
+
+	m := NewMap[int64, string]("m", Int64Key(), String())
+	for _, k := range []int64{5, -3, 0, 12, -40} {
+		if err := m.Set(k, "v"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []int64
+	cursor := ""
+	for {
+		page, err := m.Page(cursor, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range page.Entries {
+			got = append(got, e.Key)
+		}
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+	}
+	if !reflect.DeepEqual(got, []int64{-40, -3, 0, 5, 12}) {
+		t.Errorf("pages = %v", got)
+	}
+
+	if _, err := m.Page("n", 2); err == nil {
+		t.Errorf("cursor outside entries should fail")
+	}
+}
+
+func TestListSetCounter(t *testing.T) {
+	sdk.Instance = sdk.NewMemorySDK(nil, nil)
+
+	l := NewList[string]("list", String())
+	for _, v := range []string{"a", "b", "c"} {
+		if _, err := l.Push(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, err := l.Pop(); err != nil || v != "c" {
+		t.Errorf("Pop() = %v, %v", v, err)
+	}
+	if _, err := l.Get(2); err == nil {
+		t.Errorf("Get out of range should fail")
+	}
+	if values, err := l.Page(1, 10); err != nil || !reflect.DeepEqual(values, []string{"b"}) {
+		t.Errorf("Page(1, 10) = %v, %v", values, err)
+	}
+
+	s := NewSet[string]("set", StringKey())
+	_ = s.Add("x")
+	_ = s.Add("y")
+	_ = s.Remove("x")
+	if ok, _ := s.Has("x"); ok {
+		t.Errorf("removed key is still in set")
+	}
+	if keys, next, err := s.Page("", 10); err != nil || next != "" || !reflect.DeepEqual(keys, []string{"y"}) {
+		t.Errorf("Page() = %v, %v, %v", keys, next, err)
+	}
+
+	c := NewCounter("counter")
+	if v, err := c.Incr(); err != nil || v != 1 {
+		t.Errorf("Incr() = %v, %v", v, err)
+	}
+	_ = c.Set(1<<63 - 1)
+	if _, err := c.Incr(); err == nil {
+		t.Errorf("overflow should fail")
+	}
+}
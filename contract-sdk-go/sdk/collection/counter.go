@@ -0,0 +1,63 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package collection
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+// Counter int64 counter saved under a state key, it is 0 until first changed
+type Counter struct {
+	name string
+}
+
+// NewCounter create counter saved under state key name
+func NewCounter(name string) *Counter {
+	return &Counter{name: name}
+}
+
+// Get returns the value of counter
+func (c *Counter) Get() (int64, error) {
+	data, err := sdk.Instance.GetStateFromKeyByte(c.name)
+	if err != nil || len(data) == 0 {
+		return 0, err
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+// Set set the value of counter
+func (c *Counter) Set(value int64) error {
+	return sdk.Instance.PutStateFromKeyByte(c.name, []byte(strconv.FormatInt(value, 10)))
+}
+
+// Add add delta to counter and returns the new value, overflow fails
+func (c *Counter) Add(delta int64) (int64, error) {
+	value, err := c.Get()
+	if err != nil {
+		return 0, err
+	}
+	if (delta > 0 && value > math.MaxInt64-delta) || (delta < 0 && value < math.MinInt64-delta) {
+		return 0, fmt.Errorf("counter %s overflows adding %d to %d", c.name, delta, value)
+	}
+	value += delta
+	return value, c.Set(value)
+}
+
+// Incr add 1 to counter and returns the new value
+func (c *Counter) Incr() (int64, error) {
+	return c.Add(1)
+}
+
+// Decr subtract 1 from counter and returns the new value
+func (c *Counter) Decr() (int64, error) {
+	return c.Add(-1)
+}
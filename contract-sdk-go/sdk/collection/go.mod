@@ -0,0 +1,22 @@
+module chainmaker.org/chainmaker/contract-sdk-go/sdk/collection
+
+go 1.18
+
+require chainmaker.org/chainmaker/contract-sdk-go/v2 v2.3.3
+
+require (
+	chainmaker.org/chainmaker/common/v2 v2.3.5 // indirect
+	chainmaker.org/chainmaker/pb-go/v2 v2.3.6 // indirect
+	chainmaker.org/chainmaker/protocol/v2 v2.3.6 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/golang/mock v1.6.0 // indirect
+	github.com/tjfoc/gmsm v1.4.1 // indirect
+	go.uber.org/zap v1.18.1 // indirect
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519 // indirect
+	golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a // indirect
+	golang.org/x/tools v0.1.10 // indirect
+	golang.org/x/xerrors v0.0.0-20220517211312-f3a8303e98df // indirect
+	google.golang.org/grpc v1.41.1 // indirect
+)
+
+replace chainmaker.org/chainmaker/contract-sdk-go/v2 => ../..
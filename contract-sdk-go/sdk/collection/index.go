@@ -0,0 +1,240 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package collection
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+// indexSeparator separates the hex encoded index key from the map key, it never occurs in hex
+const indexSeparator = "."
+
+// Index secondary index of Map, it maps the index keys extracted from map values to the map keys
+// and is updated by the map on every Set and Delete.
+// Index keys are hex encoded in fields, so iterating an index returns index keys in byte order.
+type Index[K, V, I any] struct {
+	name    string
+	m       *Map[K, V]
+	keys    KeyCodec[I]
+	extract func(value V) (I, bool)
+	unique  bool
+}
+
+// NewIndex create index of m saved under state key m.Name()+"."+name, extract returns the index key
+// of a map value and false when the value is not indexed
+func NewIndex[K, V, I any](m *Map[K, V], name string, keys KeyCodec[I],
+	extract func(value V) (I, bool)) *Index[K, V, I] {
+	return newIndex(m, name, keys, extract, false)
+}
+
+// NewUniqueIndex create index whose index keys map to at most one map key,
+// setting a value whose index key is held by another map key fails
+func NewUniqueIndex[K, V, I any](m *Map[K, V], name string, keys KeyCodec[I],
+	extract func(value V) (I, bool)) *Index[K, V, I] {
+	return newIndex(m, name, keys, extract, true)
+}
+
+func newIndex[K, V, I any](m *Map[K, V], name string, keys KeyCodec[I],
+	extract func(value V) (I, bool), unique bool) *Index[K, V, I] {
+	index := &Index[K, V, I]{
+		name:    m.name + "." + name,
+		m:       m,
+		keys:    keys,
+		extract: extract,
+		unique:  unique,
+	}
+	m.indexes = append(m.indexes, index)
+	return index
+}
+
+// Get get the first map entry of index key, the bool result reports whether one exists
+func (idx *Index[K, V, I]) Get(indexKey I) (K, V, bool, error) {
+	var (
+		key   K
+		value V
+		found bool
+	)
+	err := idx.Lookup(indexKey, func(k K, v V) (bool, error) {
+		key, value, found = k, v, true
+		return true, nil
+	})
+	return key, value, found, err
+}
+
+// Keys returns the map keys of index key
+func (idx *Index[K, V, I]) Keys(indexKey I) ([]K, error) {
+	prefix, err := idx.prefix(indexKey)
+	if err != nil {
+		return nil, err
+	}
+	var keys []K
+	err = scanPrefix(idx.name, prefix, func(field string, primary []byte) (bool, error) {
+		key, err := idx.m.keys.DecodeKey(string(primary))
+		if err != nil {
+			return true, err
+		}
+		keys = append(keys, key)
+		return false, nil
+	})
+	return keys, err
+}
+
+// Lookup iterates the map entries of index key in map key order
+func (idx *Index[K, V, I]) Lookup(indexKey I, fn func(key K, value V) (bool, error)) error {
+	prefix, err := idx.prefix(indexKey)
+	if err != nil {
+		return err
+	}
+	return scanPrefix(idx.name, prefix, func(field string, primary []byte) (bool, error) {
+		_, key, value, err := idx.entry(field, primary)
+		if err != nil {
+			return true, err
+		}
+		return fn(key, value)
+	})
+}
+
+// Range iterates the map entries whose index key is in [start, end), in index key order
+func (idx *Index[K, V, I]) Range(start, end I, fn func(indexKey I, key K, value V) (bool, error)) error {
+	startField, err := idx.prefix(start)
+	if err != nil {
+		return err
+	}
+	endField, err := idx.prefix(end)
+	if err != nil {
+		return err
+	}
+	return scanRange(idx.name, startField, endField, func(field string, primary []byte) (bool, error) {
+		indexKey, key, value, err := idx.entry(field, primary)
+		if err != nil {
+			return true, err
+		}
+		return fn(indexKey, key, value)
+	})
+}
+
+// Page returns at most limit map entries in index key order from cursor,
+// an empty cursor starts from the first entry
+func (idx *Index[K, V, I]) Page(cursor string, limit int) (*Page[K, V], error) {
+	page := &Page[K, V]{}
+	next, err := scanPage(idx.name, cursor, limit, func(field string, primary []byte) error {
+		_, key, value, err := idx.entry(field, primary)
+		if err != nil {
+			return err
+		}
+		page.Entries = append(page.Entries, &Entry[K, V]{Key: key, Value: value})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	page.Next = next
+	return page, nil
+}
+
+// check fails if the index key of current value is held by another map key of a unique index
+func (idx *Index[K, V, I]) check(primary string, current *V) error {
+	if !idx.unique {
+		return nil
+	}
+	field, indexed, err := idx.field(current, primary)
+	if err != nil || !indexed {
+		return err
+	}
+	holder, err := sdk.Instance.GetStateByte(idx.name, field)
+	if err != nil {
+		return err
+	}
+	if len(holder) != 0 && string(holder) != primary {
+		return fmt.Errorf("index %s: key is already held by [%s]", idx.name, holder)
+	}
+	return nil
+}
+
+// update moves the index entry of map key primary from the index key of old value to that of current value,
+// the entry is checked before the old one is deleted, so a failed update leaves the index as it was
+func (idx *Index[K, V, I]) update(primary string, old, current *V) error {
+	if err := idx.check(primary, current); err != nil {
+		return err
+	}
+	oldField, oldIndexed, err := idx.field(old, primary)
+	if err != nil {
+		return err
+	}
+	newField, newIndexed, err := idx.field(current, primary)
+	if err != nil {
+		return err
+	}
+	if oldIndexed && newIndexed && oldField == newField {
+		return nil
+	}
+
+	if oldIndexed {
+		if err = sdk.Instance.DelState(idx.name, oldField); err != nil {
+			return err
+		}
+	}
+	if !newIndexed {
+		return nil
+	}
+	return sdk.Instance.PutStateByte(idx.name, newField, []byte(primary))
+}
+
+// prefix returns the field prefix of the entries of index key
+func (idx *Index[K, V, I]) prefix(indexKey I) (string, error) {
+	encoded, err := idx.keys.EncodeKey(indexKey)
+	if err != nil {
+		return "", err
+	}
+	return entryPrefix + hex.EncodeToString([]byte(encoded)) + indexSeparator, nil
+}
+
+// field returns the field of the index entry of value, unique indexes hold one map key per index key
+func (idx *Index[K, V, I]) field(value *V, primary string) (string, bool, error) {
+	if value == nil {
+		return "", false, nil
+	}
+	indexKey, ok := idx.extract(*value)
+	if !ok {
+		return "", false, nil
+	}
+	prefix, err := idx.prefix(indexKey)
+	if err != nil {
+		return "", false, err
+	}
+	if idx.unique {
+		return prefix, true, nil
+	}
+	return prefix + primary, true, nil
+}
+
+// entry decodes the index key of field and reads the map entry it points to
+func (idx *Index[K, V, I]) entry(field string, primary []byte) (I, K, V, error) {
+	var (
+		indexKey I
+		key      K
+		value    V
+	)
+	encoded := strings.SplitN(strings.TrimPrefix(field, entryPrefix), indexSeparator, 2)[0]
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil {
+		return indexKey, key, value, err
+	}
+	if indexKey, err = idx.keys.DecodeKey(string(decoded)); err != nil {
+		return indexKey, key, value, err
+	}
+	if key, err = idx.m.keys.DecodeKey(string(primary)); err != nil {
+		return indexKey, key, value, err
+	}
+	value, _, err = idx.m.get(entryPrefix + string(primary))
+	return indexKey, key, value, err
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package collection
+
+import (
+	"fmt"
+	"strings"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	// entries of collections are saved in fields starting with entryPrefix,
+	// entryLimit is greater than all of them and bounds open ranges
+	entryPrefix = "k"
+	entryLimit  = "l"
+	// metadata of collections are saved in fields outside the entry range
+	lengthField = "n"
+
+	// DefaultPageSize page size used when a page is requested with limit <= 0
+	DefaultPageSize = 50
+)
+
+// Entry key value pair of collection
+type Entry[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// Page entries of a collection, Next is the cursor of the next page and empty on the last page
+type Page[K, V any] struct {
+	Entries []*Entry[K, V]
+	Next    string
+}
+
+// scanFunc receives the fields of a collection in order, returning true stops the scan
+type scanFunc func(field string, value []byte) (stop bool, err error)
+
+// scanRange scans the fields of state key name in [start, limit)
+func scanRange(name, start, limit string, fn scanFunc) error {
+	iter, err := sdk.Instance.NewIteratorWithField(name, start, limit)
+	if err != nil {
+		return err
+	}
+	return scan(iter, fn)
+}
+
+// scanPrefix scans the fields of state key name starting with prefix
+func scanPrefix(name, prefix string, fn scanFunc) error {
+	iter, err := sdk.Instance.NewIteratorPrefixWithKeyField(name, prefix)
+	if err != nil {
+		return err
+	}
+	return scan(iter, fn)
+}
+
+func scan(iter sdk.ResultSetKV, fn scanFunc) error {
+	defer iter.Close()
+
+	for iter.HasNext() {
+		_, field, value, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		// deleted states may be returned with empty value
+		if len(value) == 0 {
+			continue
+		}
+		stop, err := fn(field, value)
+		if err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanPage scans at most limit fields of state key name from cursor, the cursor of next page is returned
+func scanPage(name, cursor string, limit int, fn func(field string, value []byte) error) (string, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if len(cursor) == 0 {
+		cursor = entryPrefix
+	}
+	if !strings.HasPrefix(cursor, entryPrefix) {
+		return "", fmt.Errorf("invalid cursor [%s]", cursor)
+	}
+
+	count := 0
+	next := ""
+	err := scanRange(name, cursor, entryLimit, func(field string, value []byte) (bool, error) {
+		if count == limit {
+			next = field
+			return true, nil
+		}
+		count++
+		return false, fn(field, value)
+	})
+	return next, err
+}
@@ -0,0 +1,161 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+// List typed list collection with index access, its length is saved in a field of its own
+type List[V any] struct {
+	name   string
+	values Codec[V]
+	index  KeyCodec[uint64]
+}
+
+// NewList create list saved under state key name
+func NewList[V any](name string, values Codec[V]) *List[V] {
+	return &List[V]{
+		name:   name,
+		values: values,
+		index:  Uint64Key(),
+	}
+}
+
+// Len returns the length of list
+func (l *List[V]) Len() (uint64, error) {
+	data, err := sdk.Instance.GetStateByte(l.name, lengthField)
+	if err != nil || len(data) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(string(data), 10, 64)
+}
+
+// Get get the value at index i
+func (l *List[V]) Get(i uint64) (V, error) {
+	var value V
+	if err := l.checkIndex(i); err != nil {
+		return value, err
+	}
+	data, err := sdk.Instance.GetStateByte(l.name, l.field(i))
+	if err != nil {
+		return value, err
+	}
+	return l.values.Decode(data)
+}
+
+// Set set the value at index i
+func (l *List[V]) Set(i uint64, value V) error {
+	if err := l.checkIndex(i); err != nil {
+		return err
+	}
+	return l.put(i, value)
+}
+
+// Push append value to the end of list and returns its index
+func (l *List[V]) Push(value V) (uint64, error) {
+	length, err := l.Len()
+	if err != nil {
+		return 0, err
+	}
+	if err = l.put(length, value); err != nil {
+		return 0, err
+	}
+	return length, l.setLen(length + 1)
+}
+
+// Pop remove the last value of list and returns it
+func (l *List[V]) Pop() (V, error) {
+	var value V
+	length, err := l.Len()
+	if err != nil {
+		return value, err
+	}
+	if length == 0 {
+		return value, errors.New("list is empty")
+	}
+	if value, err = l.Get(length - 1); err != nil {
+		return value, err
+	}
+	if err = sdk.Instance.DelState(l.name, l.field(length-1)); err != nil {
+		return value, err
+	}
+	return value, l.setLen(length - 1)
+}
+
+// Iterate iterates the values in index order, returning true from fn stops the iteration
+func (l *List[V]) Iterate(fn func(i uint64, value V) (bool, error)) error {
+	return scanRange(l.name, entryPrefix, entryLimit, l.decodeValue(fn))
+}
+
+// Range iterates the values with index in [start, end)
+func (l *List[V]) Range(start, end uint64, fn func(i uint64, value V) (bool, error)) error {
+	return scanRange(l.name, l.field(start), l.field(end), l.decodeValue(fn))
+}
+
+// Page returns at most limit values from index offset
+func (l *List[V]) Page(offset uint64, limit int) ([]V, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	values := make([]V, 0, limit)
+	err := l.Range(offset, offset+uint64(limit), func(_ uint64, value V) (bool, error) {
+		values = append(values, value)
+		return false, nil
+	})
+	return values, err
+}
+
+func (l *List[V]) field(i uint64) string {
+	encoded, _ := l.index.EncodeKey(i)
+	return entryPrefix + encoded
+}
+
+func (l *List[V]) put(i uint64, value V) error {
+	data, err := l.values.Encode(value)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("value encodes to empty bytes, which means deleted in state")
+	}
+	return sdk.Instance.PutStateByte(l.name, l.field(i), data)
+}
+
+func (l *List[V]) setLen(length uint64) error {
+	return sdk.Instance.PutStateByte(l.name, lengthField, []byte(strconv.FormatUint(length, 10)))
+}
+
+func (l *List[V]) checkIndex(i uint64) error {
+	length, err := l.Len()
+	if err != nil {
+		return err
+	}
+	if i >= length {
+		return fmt.Errorf("index %d out of range [0, %d)", i, length)
+	}
+	return nil
+}
+
+func (l *List[V]) decodeValue(fn func(i uint64, value V) (bool, error)) scanFunc {
+	return func(field string, data []byte) (bool, error) {
+		i, err := l.index.DecodeKey(field[len(entryPrefix):])
+		if err != nil {
+			return true, err
+		}
+		value, err := l.values.Decode(data)
+		if err != nil {
+			return true, err
+		}
+		return fn(i, value)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package collection provides typed state collections for go contracts.
+//
+// A collection is saved under the state key of its name, every entry in a field of that key, so entries
+// are read and written with single GetStateByte/PutStateByte calls and iterated in key order with the
+// field iterators of sdk. Map secondary indexes are maintained on every write of the map.
+//
+// The collections are generic, so the package is a module of its own which needs go 1.18, contracts
+// not using it keep building with the go version the sdk supports.
+//
+//	go get chainmaker.org/chainmaker/contract-sdk-go/sdk/collection
+package collection
+
+import (
+	"errors"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+// Map typed key value collection
+type Map[K, V any] struct {
+	name    string
+	keys    KeyCodec[K]
+	values  Codec[V]
+	indexes []mapIndex[V]
+}
+
+// mapIndex is notified by Map on every write, to keep its entries up to date
+type mapIndex[V any] interface {
+	// check fails if the write would break the index, it is called on every index before any is updated
+	check(primary string, current *V) error
+	update(primary string, old, current *V) error
+}
+
+// NewMap create map saved under state key name
+func NewMap[K, V any](name string, keys KeyCodec[K], values Codec[V]) *Map[K, V] {
+	return &Map[K, V]{
+		name:   name,
+		keys:   keys,
+		values: values,
+	}
+}
+
+// Name returns the state key of map
+func (m *Map[K, V]) Name() string {
+	return m.name
+}
+
+// Get get value of key, the bool result reports whether key exists
+func (m *Map[K, V]) Get(key K) (V, bool, error) {
+	var value V
+	field, err := m.field(key)
+	if err != nil {
+		return value, false, err
+	}
+	return m.get(field)
+}
+
+// Has reports whether key exists
+func (m *Map[K, V]) Has(key K) (bool, error) {
+	field, err := m.field(key)
+	if err != nil {
+		return false, err
+	}
+	data, err := sdk.Instance.GetStateByte(m.name, field)
+	if err != nil {
+		return false, err
+	}
+	return len(data) != 0, nil
+}
+
+// Set set value of key and update the indexes of map
+func (m *Map[K, V]) Set(key K, value V) error {
+	field, err := m.field(key)
+	if err != nil {
+		return err
+	}
+	data, err := m.values.Encode(value)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("value encodes to empty bytes, which means deleted in state")
+	}
+
+	if len(m.indexes) > 0 {
+		if err = m.updateIndexes(field, &value); err != nil {
+			return err
+		}
+	}
+	return sdk.Instance.PutStateByte(m.name, field, data)
+}
+
+// Delete delete key and its index entries
+func (m *Map[K, V]) Delete(key K) error {
+	field, err := m.field(key)
+	if err != nil {
+		return err
+	}
+
+	if len(m.indexes) > 0 {
+		if err = m.updateIndexes(field, nil); err != nil {
+			return err
+		}
+	}
+	return sdk.Instance.DelState(m.name, field)
+}
+
+// Iterate iterates all entries in key order, returning true from fn stops the iteration
+func (m *Map[K, V]) Iterate(fn func(key K, value V) (bool, error)) error {
+	return scanRange(m.name, entryPrefix, entryLimit, m.decodeEntry(fn))
+}
+
+// Range iterates the entries in [start, end) in key order
+func (m *Map[K, V]) Range(start, end K, fn func(key K, value V) (bool, error)) error {
+	startField, err := m.field(start)
+	if err != nil {
+		return err
+	}
+	endField, err := m.field(end)
+	if err != nil {
+		return err
+	}
+	return scanRange(m.name, startField, endField, m.decodeEntry(fn))
+}
+
+// Prefix iterates the entries whose encoded key starts with prefix
+func (m *Map[K, V]) Prefix(prefix string, fn func(key K, value V) (bool, error)) error {
+	return scanPrefix(m.name, entryPrefix+prefix, m.decodeEntry(fn))
+}
+
+// Page returns at most limit entries from cursor, an empty cursor starts from the first entry
+func (m *Map[K, V]) Page(cursor string, limit int) (*Page[K, V], error) {
+	page := &Page[K, V]{}
+	next, err := scanPage(m.name, cursor, limit, func(field string, data []byte) error {
+		entry, err := m.entry(field, data)
+		if err != nil {
+			return err
+		}
+		page.Entries = append(page.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	page.Next = next
+	return page, nil
+}
+
+func (m *Map[K, V]) field(key K) (string, error) {
+	encoded, err := m.keys.EncodeKey(key)
+	if err != nil {
+		return "", err
+	}
+	return entryPrefix + encoded, nil
+}
+
+func (m *Map[K, V]) get(field string) (V, bool, error) {
+	var value V
+	data, err := sdk.Instance.GetStateByte(m.name, field)
+	if err != nil || len(data) == 0 {
+		return value, false, err
+	}
+	value, err = m.values.Decode(data)
+	if err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
+
+func (m *Map[K, V]) entry(field string, data []byte) (*Entry[K, V], error) {
+	key, err := m.keys.DecodeKey(field[len(entryPrefix):])
+	if err != nil {
+		return nil, err
+	}
+	value, err := m.values.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Entry[K, V]{Key: key, Value: value}, nil
+}
+
+func (m *Map[K, V]) decodeEntry(fn func(key K, value V) (bool, error)) scanFunc {
+	return func(field string, data []byte) (bool, error) {
+		entry, err := m.entry(field, data)
+		if err != nil {
+			return true, err
+		}
+		return fn(entry.Key, entry.Value)
+	}
+}
+
+func (m *Map[K, V]) updateIndexes(field string, value *V) error {
+	old, exists, err := m.get(field)
+	if err != nil {
+		return err
+	}
+	var oldValue *V
+	if exists {
+		oldValue = &old
+	}
+	primary := field[len(entryPrefix):]
+	for _, index := range m.indexes {
+		if err = index.check(primary, value); err != nil {
+			return err
+		}
+	}
+	for _, index := range m.indexes {
+		if err = index.update(primary, oldValue, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
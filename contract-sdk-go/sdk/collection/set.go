@@ -0,0 +1,119 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package collection
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+// setMember value saved for the members of set, an empty value would mean deleted
+var setMember = []byte{1}
+
+// Set typed set collection
+type Set[K any] struct {
+	name string
+	keys KeyCodec[K]
+}
+
+// NewSet create set saved under state key name
+func NewSet[K any](name string, keys KeyCodec[K]) *Set[K] {
+	return &Set[K]{
+		name: name,
+		keys: keys,
+	}
+}
+
+// Add add key to set
+func (s *Set[K]) Add(key K) error {
+	field, err := s.field(key)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(s.name, field, setMember)
+}
+
+// Remove remove key from set
+func (s *Set[K]) Remove(key K) error {
+	field, err := s.field(key)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.DelState(s.name, field)
+}
+
+// Has reports whether key is in set
+func (s *Set[K]) Has(key K) (bool, error) {
+	field, err := s.field(key)
+	if err != nil {
+		return false, err
+	}
+	data, err := sdk.Instance.GetStateByte(s.name, field)
+	if err != nil {
+		return false, err
+	}
+	return len(data) != 0, nil
+}
+
+// Iterate iterates all keys in order, returning true from fn stops the iteration
+func (s *Set[K]) Iterate(fn func(key K) (bool, error)) error {
+	return scanRange(s.name, entryPrefix, entryLimit, s.decodeKey(fn))
+}
+
+// Range iterates the keys in [start, end) in order
+func (s *Set[K]) Range(start, end K, fn func(key K) (bool, error)) error {
+	startField, err := s.field(start)
+	if err != nil {
+		return err
+	}
+	endField, err := s.field(end)
+	if err != nil {
+		return err
+	}
+	return scanRange(s.name, startField, endField, s.decodeKey(fn))
+}
+
+// Prefix iterates the keys whose encoding starts with prefix
+func (s *Set[K]) Prefix(prefix string, fn func(key K) (bool, error)) error {
+	return scanPrefix(s.name, entryPrefix+prefix, s.decodeKey(fn))
+}
+
+// Page returns at most limit keys from cursor and the cursor of next page,
+// an empty cursor starts from the first key
+func (s *Set[K]) Page(cursor string, limit int) ([]K, string, error) {
+	var keys []K
+	next, err := scanPage(s.name, cursor, limit, func(field string, _ []byte) error {
+		key, err := s.keys.DecodeKey(field[len(entryPrefix):])
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return keys, next, nil
+}
+
+func (s *Set[K]) field(key K) (string, error) {
+	encoded, err := s.keys.EncodeKey(key)
+	if err != nil {
+		return "", err
+	}
+	return entryPrefix + encoded, nil
+}
+
+func (s *Set[K]) decodeKey(fn func(key K) (bool, error)) scanFunc {
+	return func(field string, _ []byte) (bool, error) {
+		key, err := s.keys.DecodeKey(field[len(entryPrefix):])
+		if err != nil {
+			return true, err
+		}
+		return fn(key)
+	}
+}
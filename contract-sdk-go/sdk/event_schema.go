@@ -0,0 +1,258 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+)
+
+const (
+	// EventSchemasKey is the state key the event schemas of contract are saved under
+	EventSchemasKey = "__event_schemas__"
+	// EventSchemasMethod is the query method answered by sandbox with the saved event schemas
+	EventSchemasMethod = "__event_schemas__"
+)
+
+// EventFieldType type of event field, decides the canonical encoding of the field value
+type EventFieldType string
+
+const (
+	// EventFieldString string value, encoded as is
+	EventFieldString EventFieldType = "string"
+	// EventFieldAddress address string, encoded as is
+	EventFieldAddress EventFieldType = "address"
+	// EventFieldBool bool value, encoded as "true" or "false"
+	EventFieldBool EventFieldType = "bool"
+	// EventFieldInt signed integer, encoded in decimal
+	EventFieldInt EventFieldType = "int"
+	// EventFieldUint unsigned integer, encoded in decimal
+	EventFieldUint EventFieldType = "uint"
+	// EventFieldUint256 unsigned big integer, encoded in decimal
+	EventFieldUint256 EventFieldType = "uint256"
+	// EventFieldBytes byte slice, encoded in hex
+	EventFieldBytes EventFieldType = "bytes"
+	// EventFieldJSON any value, encoded in json
+	EventFieldJSON EventFieldType = "json"
+)
+
+// EventField one field of event
+type EventField struct {
+	Name    string         `json:"name"`
+	Type    EventFieldType `json:"type"`
+	Indexed bool           `json:"indexed,omitempty"`
+}
+
+// EventSchema describes an event of contract, the event topic is the schema name
+// and the event data are the fields encoded in declared order
+type EventSchema struct {
+	Name   string        `json:"name"`
+	Fields []*EventField `json:"fields"`
+}
+
+// EventSchemaDeclarer is implemented by contracts which declare their event schemas,
+// the sandbox saves the schemas on chain when the contract is installed or upgraded.
+// The schemas used by EmitTypedEvent are registered by the contract itself with RegisterEventSchemas, in init
+type EventSchemaDeclarer interface {
+	EventSchemas() []*EventSchema
+}
+
+var (
+	eventSchemasLock sync.RWMutex
+	eventSchemas     = make(map[string]*EventSchema)
+)
+
+// NewEventSchema create event schema
+func NewEventSchema(name string, fields ...*EventField) *EventSchema {
+	return &EventSchema{
+		Name:   name,
+		Fields: fields,
+	}
+}
+
+// NewEventField create event field
+func NewEventField(name string, fieldType EventFieldType, indexed bool) *EventField {
+	return &EventField{
+		Name:    name,
+		Type:    fieldType,
+		Indexed: indexed,
+	}
+}
+
+// RegisterEventSchemas register event schemas used by EmitTypedEvent
+func RegisterEventSchemas(schemas ...*EventSchema) error {
+	eventSchemasLock.Lock()
+	defer eventSchemasLock.Unlock()
+
+	for _, schema := range schemas {
+		if err := schema.check(); err != nil {
+			return err
+		}
+		eventSchemas[schema.Name] = schema
+	}
+	return nil
+}
+
+// RegisteredEventSchemas returns the registered event schemas
+func RegisteredEventSchemas() []*EventSchema {
+	eventSchemasLock.RLock()
+	defer eventSchemasLock.RUnlock()
+
+	schemas := make([]*EventSchema, 0, len(eventSchemas))
+	for _, schema := range eventSchemas {
+		schemas = append(schemas, schema)
+	}
+	return schemas
+}
+
+// PutEventSchemas save event schemas on chain, they are read by clients to decode events
+func PutEventSchemas(schemas []*EventSchema) error {
+	schemasBytes, err := json.Marshal(schemas)
+	if err != nil {
+		return err
+	}
+	return Instance.PutStateFromKeyByte(EventSchemasKey, schemasBytes)
+}
+
+// GetEventSchemas get event schemas saved on chain
+func GetEventSchemas() ([]*EventSchema, error) {
+	schemasBytes, err := Instance.GetStateFromKeyByte(EventSchemasKey)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []*EventSchema
+	if len(schemasBytes) == 0 {
+		return schemas, nil
+	}
+	if err = json.Unmarshal(schemasBytes, &schemas); err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
+
+// EmitTypedEvent emit event described by the registered schema of name,
+// values are checked against the schema fields and encoded with the canonical codec
+// @param1 name: 事件名称，即合约事件的主题
+// @param2 values: 事件字段值，顺序与事件定义一致
+func EmitTypedEvent(name string, values ...interface{}) error {
+	eventSchemasLock.RLock()
+	schema, ok := eventSchemas[name]
+	eventSchemasLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("event schema [%s] not registered", name)
+	}
+
+	data, err := schema.Encode(values...)
+	if err != nil {
+		return err
+	}
+	Instance.EmitEvent(schema.Name, data)
+	return nil
+}
+
+// Encode encode values of event with the canonical codec
+func (e *EventSchema) Encode(values ...interface{}) ([]string, error) {
+	if len(values) != len(e.Fields) {
+		return nil, fmt.Errorf("event [%s] expects %d fields, got %d", e.Name, len(e.Fields), len(values))
+	}
+
+	data := make([]string, 0, len(values))
+	for i, field := range e.Fields {
+		value, err := encodeEventField(field.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("event [%s] field [%s]: %s", e.Name, field.Name, err)
+		}
+		data = append(data, value)
+	}
+	return data, nil
+}
+
+func (e *EventSchema) check() error {
+	if e == nil || len(e.Name) == 0 {
+		return errors.New("event schema name is empty")
+	}
+	// event data can not hold more than 16 items
+	if len(e.Fields) > 16 {
+		return fmt.Errorf("event [%s] has more than 16 fields", e.Name)
+	}
+	for _, field := range e.Fields {
+		if !isEventFieldType(field.Type) {
+			return fmt.Errorf("event [%s] field [%s] has unknown type [%s]", e.Name, field.Name, field.Type)
+		}
+	}
+	return nil
+}
+
+// nolint: gocyclo
+func encodeEventField(fieldType EventFieldType, value interface{}) (string, error) {
+	switch fieldType {
+	case EventFieldString, EventFieldAddress:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case EventFieldBool:
+		if v, ok := value.(bool); ok {
+			return strconv.FormatBool(v), nil
+		}
+	case EventFieldInt:
+		switch v := value.(type) {
+		case int:
+			return strconv.FormatInt(int64(v), 10), nil
+		case int32:
+			return strconv.FormatInt(int64(v), 10), nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		}
+	case EventFieldUint:
+		switch v := value.(type) {
+		case uint:
+			return strconv.FormatUint(uint64(v), 10), nil
+		case uint32:
+			return strconv.FormatUint(uint64(v), 10), nil
+		case uint64:
+			return strconv.FormatUint(v, 10), nil
+		}
+	case EventFieldUint256:
+		var s string
+		switch v := value.(type) {
+		case *big.Int:
+			s = v.String()
+		case string:
+			s = v
+		case fmt.Stringer:
+			s = v.String()
+		case interface{ ToString() string }:
+			// safemath.SafeUint256 of contract-utils
+			s = v.ToString()
+		default:
+			return "", fmt.Errorf("can not encode %T as %s", value, fieldType)
+		}
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok || n.Sign() < 0 {
+			return "", fmt.Errorf("[%s] is not a valid %s", s, fieldType)
+		}
+		return n.String(), nil
+	case EventFieldBytes:
+		if v, ok := value.([]byte); ok {
+			return hex.EncodeToString(v), nil
+		}
+	case EventFieldJSON:
+		v, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(v), nil
+	}
+
+	return "", fmt.Errorf("can not encode %T as %s", value, fieldType)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+type stringer string
+
+func (s stringer) String() string {
+	return string(s)
+}
+
+func TestEventSchema_Encode(t *testing.T) {
+	schema := NewEventSchema("Transfer",
+		NewEventField("from", EventFieldAddress, true),
+		NewEventField("ok", EventFieldBool, false),
+		NewEventField("delta", EventFieldInt, false),
+		NewEventField("nonce", EventFieldUint, false),
+		NewEventField("amount", EventFieldUint256, false),
+		NewEventField("data", EventFieldBytes, false),
+		NewEventField("extra", EventFieldJSON, false),
+	)
+
+	tests := []struct {
+		name    string
+		values  []interface{}
+		want    []string
+		wantErr string
+	}{
+		{name: "encode", values: []interface{}{"a1", true, int64(-3), uint32(7), big.NewInt(1000),
+			[]byte{0xab, 0x01}, map[string]int{"k": 1}},
+			want: []string{"a1", "true", "-3", "7", "1000", "ab01", `{"k":1}`}},
+		{name: "uint256 from string and stringer", values: []interface{}{"a1", false, 1, uint(2), "12",
+			[]byte{}, nil}, want: []string{"a1", "false", "1", "2", "12", "", "null"}},
+		{name: "uint256 from stringer", values: []interface{}{"a1", false, 1, uint(2), stringer("0012"),
+			[]byte{}, "x"}, want: []string{"a1", "false", "1", "2", "12", "", `"x"`}},
+		{name: "too few values", values: []interface{}{"a1"},
+			wantErr: "event [Transfer] expects 7 fields, got 1"},
+		{name: "wrong type", values: []interface{}{"a1", "true", 1, uint(2), "12", []byte{}, nil},
+			wantErr: "event [Transfer] field [ok]: can not encode string as bool"},
+		{name: "negative uint256", values: []interface{}{"a1", true, 1, uint(2), "-12", []byte{}, nil},
+			wantErr: "event [Transfer] field [amount]: [-12] is not a valid uint256"},
+		{name: "uint from int", values: []interface{}{"a1", true, 1, 2, "12", []byte{}, nil},
+			wantErr: "event [Transfer] field [nonce]: can not encode int as uint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schema.Encode(tt.values...)
+			if len(tt.wantErr) > 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Encode() error = %v, wantErr %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Encode() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterEventSchemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  *EventSchema
+		wantErr bool
+	}{
+		{name: "register", schema: NewEventSchema("Approval", NewEventField("owner", EventFieldAddress, true))},
+		{name: "empty name", schema: NewEventSchema(""), wantErr: true},
+		{name: "unknown type", schema: NewEventSchema("Bad", NewEventField("f", "float", false)), wantErr: true},
+		{name: "too many fields", schema: NewEventSchema("Big", make([]*EventField, 17)...), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterEventSchemas(tt.schema); (err != nil) != tt.wantErr {
+				t.Errorf("RegisterEventSchemas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	registered := make(map[string]bool)
+	for _, schema := range RegisteredEventSchemas() {
+		registered[schema.Name] = true
+	}
+	if !registered["Approval"] || registered["Bad"] || registered["Big"] {
+		t.Errorf("RegisteredEventSchemas() = %v", registered)
+	}
+}
+
+func TestEmitTypedEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := NewMockSDKInterface(ctrl)
+	mockInstance.EXPECT().EmitEvent("Minted", []string{"a1", "100"}).Times(1)
+	Instance = mockInstance
+	defer func() { Instance = nil }()
+
+	if err := RegisterEventSchemas(NewEventSchema("Minted", NewEventField("to", EventFieldAddress, true),
+		NewEventField("amount", EventFieldUint256, false))); err != nil {
+		t.Fatal(err)
+	}
+	if err := EmitTypedEvent("Minted", "a1", big.NewInt(100)); err != nil {
+		t.Errorf("EmitTypedEvent() error = %v", err)
+	}
+	if err := EmitTypedEvent("Minted", "a1", big.NewInt(-1)); err == nil {
+		t.Errorf("EmitTypedEvent() with invalid value should fail")
+	}
+	if err := EmitTypedEvent("Burned", "a1"); err == nil {
+		t.Errorf("EmitTypedEvent() of unregistered schema should fail")
+	}
+}
+
+func TestPutEventSchemas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := NewMockSDKInterface(ctrl)
+	state := make(map[string][]byte)
+	mockInstance.EXPECT().PutStateFromKeyByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key string, value []byte) error {
+			state[key] = value
+			return nil
+		})
+	mockInstance.EXPECT().GetStateFromKeyByte(gomock.Any()).AnyTimes().DoAndReturn(
+		func(key string) ([]byte, error) {
+			return state[key], nil
+		})
+	Instance = mockInstance
+	defer func() { Instance = nil }()
+
+	if schemas, err := GetEventSchemas(); err != nil || len(schemas) != 0 {
+		t.Errorf("GetEventSchemas() = %v, %v, want none", schemas, err)
+	}
+	want := []*EventSchema{NewEventSchema("Minted", NewEventField("to", EventFieldAddress, true))}
+	if err := PutEventSchemas(want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := GetEventSchemas(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetEventSchemas() = %v, %v, want %v", got, err, want)
+	}
+}
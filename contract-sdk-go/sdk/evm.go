@@ -0,0 +1,124 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"chainmaker.org/chainmaker/common/v2/evmutils/abi"
+)
+
+// EvmParamData is the arg the abi encoded calldata is passed to evm contract in
+const EvmParamData = "data"
+
+// revertSelector is the selector of Error(string), which solidity reverts with
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+var (
+	abiCacheLock sync.RWMutex
+	abiCache     = make(map[string]*abi.ABI)
+)
+
+// CallEVMContract call method of evm contract, params are abi encoded with abiJSON and the result
+// returned by the evm contract is abi decoded into the outputs of method.
+// The call goes through CallContract, so its gas and read write set are accounted as native calls.
+// @param1 contractName: evm合约名称
+// @param2 abiJSON: evm合约的abi
+// @param3 method: evm合约方法名
+// @param4 params: 方法参数，类型与abi定义一致，例如uint256对应*big.Int
+// @return1: 方法返回值
+// @return2: 获取错误信息，evm合约revert时包含revert原因
+func CallEVMContract(contractName, abiJSON, method string, params ...interface{}) ([]interface{}, error) {
+	contractABI, err := parseABI(abiJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse abi of evm contract %s, %s", contractName, err)
+	}
+
+	calldata, err := contractABI.Pack(method, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to abi encode params of %s.%s, %s", contractName, method, err)
+	}
+
+	// evm contracts take hex calldata, the method of the call is the hex selector
+	data := hex.EncodeToString(calldata)
+	response := Instance.CallContract(contractName, data[:8], map[string][]byte{
+		EvmParamData: []byte(data),
+	})
+	if response.Status != OK {
+		return nil, evmCallError(contractName, method, response.Message, response.Payload)
+	}
+
+	result, err := contractABI.Unpack(method, response.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to abi decode result of %s.%s, %s", contractName, method, err)
+	}
+	return result, nil
+}
+
+func parseABI(abiJSON string) (*abi.ABI, error) {
+	abiCacheLock.RLock()
+	contractABI, ok := abiCache[abiJSON]
+	abiCacheLock.RUnlock()
+	if ok {
+		return contractABI, nil
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	abiCacheLock.Lock()
+	abiCache[abiJSON] = &parsed
+	abiCacheLock.Unlock()
+	return &parsed, nil
+}
+
+func evmCallError(contractName, method, message string, payload []byte) error {
+	if reason, ok := unpackRevertReason(payload); ok {
+		return fmt.Errorf("evm contract %s.%s reverted: %s", contractName, method, reason)
+	}
+	if len(message) == 0 {
+		message = "call evm contract failed"
+	}
+	return fmt.Errorf("evm contract %s.%s: %s", contractName, method, message)
+}
+
+// unpackRevertReason decodes the reason of Error(string) revert data
+func unpackRevertReason(data []byte) (string, bool) {
+	if len(data) < 4+64 || !bytes.Equal(data[:4], revertSelector) {
+		return "", false
+	}
+	data = data[4:]
+
+	offset, err := abiWordToInt(data[:32])
+	if err != nil || offset+32 > len(data) {
+		return "", false
+	}
+	size, err := abiWordToInt(data[offset : offset+32])
+	if err != nil || offset+32+size > len(data) {
+		return "", false
+	}
+	return string(data[offset+32 : offset+32+size]), true
+}
+
+func abiWordToInt(word []byte) (int, error) {
+	// reasons are short, anything not fitting in the low 4 bytes is malformed
+	for _, b := range word[:28] {
+		if b != 0 {
+			return 0, errors.New("abi word overflows int")
+		}
+	}
+	return int(binary.BigEndian.Uint32(word[28:])), nil
+}
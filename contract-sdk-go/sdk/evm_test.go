@@ -0,0 +1,131 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"github.com/golang/mock/gomock"
+)
+
+const testEvmABI = `[{"inputs":[{"name":"id","type":"uint256"}],"name":"get",
+"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// abiWord returns the 32 bytes abi encoding of a small unsigned integer
+func abiWord(n byte) []byte {
+	word := make([]byte, 32)
+	word[31] = n
+	return word
+}
+
+// revertData returns the abi encoded Error(reason) revert data
+func revertData(reason string) []byte {
+	data := append([]byte{}, revertSelector...)
+	data = append(data, abiWord(32)...)
+	data = append(data, abiWord(byte(len(reason)))...)
+	padded := make([]byte, (len(reason)+31)/32*32)
+	copy(padded, reason)
+	return append(data, padded...)
+}
+
+func TestCallEVMContract(t *testing.T) {
+	tests := []struct {
+		name     string
+		abiJSON  string
+		method   string
+		params   []interface{}
+		response protogo.Response
+		want     []interface{}
+		wantErr  string
+	}{
+		{name: "success", abiJSON: testEvmABI, method: "get", params: []interface{}{big.NewInt(7)},
+			response: Success(abiWord(100)), want: []interface{}{big.NewInt(100)}},
+		{name: "reverted", abiJSON: testEvmABI, method: "get", params: []interface{}{big.NewInt(7)},
+			response: protogo.Response{Status: ERROR, Message: "revert", Payload: revertData("not found")},
+			wantErr:  "evm contract evm1.get reverted: not found"},
+		{name: "failed", abiJSON: testEvmABI, method: "get", params: []interface{}{big.NewInt(7)},
+			response: Error("out of gas"), wantErr: "evm contract evm1.get: out of gas"},
+		{name: "failed without message", abiJSON: testEvmABI, method: "get", params: []interface{}{big.NewInt(7)},
+			response: protogo.Response{Status: ERROR}, wantErr: "evm contract evm1.get: call evm contract failed"},
+		{name: "malformed result", abiJSON: testEvmABI, method: "get", params: []interface{}{big.NewInt(7)},
+			response: Success([]byte{1}), wantErr: "failed to abi decode result of evm1.get"},
+		{name: "invalid abi", abiJSON: "{", method: "get", wantErr: "failed to parse abi of evm contract evm1"},
+		{name: "unknown method", abiJSON: testEvmABI, method: "set", wantErr: "failed to abi encode params of evm1.set"},
+		{name: "wrong param type", abiJSON: testEvmABI, method: "get", params: []interface{}{"7"},
+			wantErr: "failed to abi encode params of evm1.get"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+			stub := NewMockSDKInterface(c)
+			stub.EXPECT().CallContract("evm1", gomock.Any(), gomock.Any()).DoAndReturn(
+				func(contractName, method string, args map[string][]byte) protogo.Response {
+					data := string(args[EvmParamData])
+					if len(args) != 1 || len(data) != 8+64 || method != data[:8] {
+						t.Errorf("CallContract() method = %s, args = %v", method, args)
+					}
+					if data[8:] != hex.EncodeToString(abiWord(7)) {
+						t.Errorf("CallContract() params = %s, want 7", data[8:])
+					}
+					return tt.response
+				}).MaxTimes(1)
+			Instance = stub
+			defer func() { Instance = nil }()
+
+			got, err := CallEVMContract("evm1", tt.abiJSON, tt.method, tt.params...)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CallEVMContract() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CallEVMContract() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CallEVMContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnpackRevertReason(t *testing.T) {
+	overflow := revertData("not found")
+	overflow[4] = 1
+	tooLong := revertData("not found")
+	tooLong[4+63] = 200
+
+	tests := []struct {
+		name   string
+		data   []byte
+		want   string
+		wantOk bool
+	}{
+		{name: "reason", data: revertData("not found"), want: "not found", wantOk: true},
+		{name: "empty reason", data: revertData(""), want: "", wantOk: true},
+		{name: "long reason", data: revertData(strings.Repeat("x", 40)), want: strings.Repeat("x", 40), wantOk: true},
+		{name: "empty", data: nil},
+		{name: "not Error(string)", data: append([]byte{1, 2, 3, 4}, revertData("not found")[4:]...)},
+		{name: "truncated", data: revertData("not found")[:4+63]},
+		{name: "offset overflows", data: overflow},
+		{name: "size out of data", data: tooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := unpackRevertReason(tt.data)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("unpackRevertReason() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrOutOfComputeGas is the panic value raised by Meter when a metered contract
+// consumes more compute units than the tx gas limit allows
+var ErrOutOfComputeGas = errors.New("out of gas: compute units exceed the limit")
+
+var (
+	computeUnits uint64
+	computeLimit uint64
+)
+
+// Meter charges compute units for the basic block about to run. Calls are inserted
+// by the gasmeter tool at build time, contracts should not call it by hand.
+func Meter(units uint64) {
+	used := atomic.AddUint64(&computeUnits, units)
+	limit := atomic.LoadUint64(&computeLimit)
+	if limit > 0 && used > limit {
+		panic(ErrOutOfComputeGas)
+	}
+}
+
+// ResetComputeMeter clears the consumed compute units and sets the limit for next tx, 0 means unlimited
+func ResetComputeMeter(limit uint64) {
+	atomic.StoreUint64(&computeUnits, 0)
+	atomic.StoreUint64(&computeLimit, limit)
+}
+
+// ComputeUnits returns compute units consumed by current tx
+func ComputeUnits() uint64 {
+	return atomic.LoadUint64(&computeUnits)
+}
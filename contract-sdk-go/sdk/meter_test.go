@@ -0,0 +1,48 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"testing"
+)
+
+func TestMeter(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     uint64
+		units     []uint64
+		wantUnits uint64
+		wantPanic bool
+	}{
+		{name: "unlimited", limit: 0, units: []uint64{10, 20, 30}, wantUnits: 60},
+		{name: "within limit", limit: 60, units: []uint64{10, 20, 30}, wantUnits: 60},
+		{name: "out of gas", limit: 50, units: []uint64{10, 20, 30}, wantUnits: 60, wantPanic: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetComputeMeter(tt.limit)
+			defer func() {
+				r := recover()
+				if (r != nil) != tt.wantPanic || (r != nil && r != ErrOutOfComputeGas) {
+					t.Errorf("Meter() panic = %v, wantPanic %v", r, tt.wantPanic)
+				}
+				if got := ComputeUnits(); got != tt.wantUnits {
+					t.Errorf("ComputeUnits() = %v, want %v", got, tt.wantUnits)
+				}
+			}()
+			for _, units := range tt.units {
+				Meter(units)
+			}
+		})
+	}
+
+	// the next tx starts from zero
+	ResetComputeMeter(0)
+	if got := ComputeUnits(); got != 0 {
+		t.Errorf("ComputeUnits() after reset = %v, want 0", got)
+	}
+}
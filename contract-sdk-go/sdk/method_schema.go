@@ -0,0 +1,134 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ContractSchemaMethod is the query method answered by sandbox with the method and event schemas of contract,
+// client code generators read it to build typed clients
+const ContractSchemaMethod = "__contract_schema__"
+
+// MethodKind tells whether a method changes state
+type MethodKind string
+
+const (
+	// MethodInvoke method changing state, called by InvokeContract
+	MethodInvoke MethodKind = "invoke"
+	// MethodQuery read only method, called by QueryContract
+	MethodQuery MethodKind = "query"
+)
+
+// MethodParam one parameter of method, the argument value is encoded with the canonical codec
+// of the type except bytes, which are passed as is
+type MethodParam struct {
+	Name string         `json:"name"`
+	Type EventFieldType `json:"type"`
+}
+
+// MethodSchema describes a method of contract, Returns is the type of the response payload,
+// empty if the payload is not meant to be decoded
+type MethodSchema struct {
+	Name    string         `json:"name"`
+	Kind    MethodKind     `json:"kind"`
+	Params  []*MethodParam `json:"params"`
+	Returns EventFieldType `json:"returns,omitempty"`
+}
+
+// MethodSchemaDeclarer is implemented by contracts which declare their method schemas
+type MethodSchemaDeclarer interface {
+	MethodSchemas() []*MethodSchema
+}
+
+// ContractSchema the method and event schemas of contract
+type ContractSchema struct {
+	Methods []*MethodSchema `json:"methods"`
+	Events  []*EventSchema  `json:"events"`
+}
+
+// NewMethodSchema create method schema
+func NewMethodSchema(name string, kind MethodKind, returns EventFieldType, params ...*MethodParam) *MethodSchema {
+	return &MethodSchema{
+		Name:    name,
+		Kind:    kind,
+		Params:  params,
+		Returns: returns,
+	}
+}
+
+// NewMethodParam create method param
+func NewMethodParam(name string, paramType EventFieldType) *MethodParam {
+	return &MethodParam{
+		Name: name,
+		Type: paramType,
+	}
+}
+
+// NewContractSchema collect the schemas declared by contract, methods and events are sorted by name
+func NewContractSchema(contract Contract) (*ContractSchema, error) {
+	schema := &ContractSchema{
+		Methods: []*MethodSchema{},
+		Events:  []*EventSchema{},
+	}
+	if declarer, ok := contract.(MethodSchemaDeclarer); ok {
+		schema.Methods = append(schema.Methods, declarer.MethodSchemas()...)
+	}
+	if declarer, ok := contract.(EventSchemaDeclarer); ok {
+		schema.Events = append(schema.Events, declarer.EventSchemas()...)
+	}
+
+	for _, method := range schema.Methods {
+		if err := method.check(); err != nil {
+			return nil, err
+		}
+	}
+	for _, event := range schema.Events {
+		if err := event.check(); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(schema.Methods, func(i, j int) bool { return schema.Methods[i].Name < schema.Methods[j].Name })
+	sort.Slice(schema.Events, func(i, j int) bool { return schema.Events[i].Name < schema.Events[j].Name })
+	return schema, nil
+}
+
+func (m *MethodSchema) check() error {
+	if m == nil || len(m.Name) == 0 {
+		return errors.New("method schema name is empty")
+	}
+	if m.Kind != MethodInvoke && m.Kind != MethodQuery {
+		return fmt.Errorf("method [%s] has unknown kind [%s]", m.Name, m.Kind)
+	}
+	if m.Returns != "" && !isEventFieldType(m.Returns) {
+		return fmt.Errorf("method [%s] returns unknown type [%s]", m.Name, m.Returns)
+	}
+	names := make(map[string]struct{}, len(m.Params))
+	for _, param := range m.Params {
+		if !isEventFieldType(param.Type) {
+			return fmt.Errorf("method [%s] param [%s] has unknown type [%s]", m.Name, param.Name, param.Type)
+		}
+		if _, ok := names[param.Name]; ok || len(param.Name) == 0 {
+			return fmt.Errorf("method [%s] has empty or duplicated param [%s]", m.Name, param.Name)
+		}
+		names[param.Name] = struct{}{}
+	}
+	return nil
+}
+
+func isEventFieldType(fieldType EventFieldType) bool {
+	switch fieldType {
+	case EventFieldString, EventFieldAddress, EventFieldBool, EventFieldInt, EventFieldUint,
+		EventFieldUint256, EventFieldBytes, EventFieldJSON:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// ContractVersionKey is the state key the deployed contract version is saved under
+	ContractVersionKey = "__contract_version__"
+	// StateSchemaVersionKey is the state key the state schema version of contract is saved under
+	StateSchemaVersionKey = "__state_schema_version__"
+	// StateVersionMethod is the query method answered by sandbox with the saved versions
+	StateVersionMethod = "__state_version__"
+
+	// DefaultMigrationBatchSize number of states RewriteStatePrefix reads before writing them back
+	DefaultMigrationBatchSize = 100
+)
+
+// Migration is a step migrating contract state from schema version Version-1 to Version
+type Migration struct {
+	Version     uint32
+	Description string
+	Migrate     func() error
+}
+
+// MigrationDeclarer is implemented by contracts which migrate their state on upgrade,
+// the sandbox runs the pending migrations in version order before UpgradeContract
+type MigrationDeclarer interface {
+	Migrations() []*Migration
+}
+
+// MigrationResult the state schema versions before and after running migrations
+type MigrationResult struct {
+	FromVersion uint32   `json:"fromVersion"`
+	ToVersion   uint32   `json:"toVersion"`
+	Applied     []uint32 `json:"applied"`
+}
+
+// StateVersion versions saved by the sandbox when contract is installed or upgraded
+type StateVersion struct {
+	ContractVersion    string `json:"contractVersion"`
+	StateSchemaVersion uint32 `json:"stateSchemaVersion"`
+}
+
+// NewMigration create migration step to state schema version
+func NewMigration(version uint32, description string, migrate func() error) *Migration {
+	return &Migration{
+		Version:     version,
+		Description: description,
+		Migrate:     migrate,
+	}
+}
+
+// LatestStateSchemaVersion returns the state schema version after running all migrations
+func LatestStateSchemaVersion(migrations []*Migration) uint32 {
+	return uint32(len(migrations))
+}
+
+// GetStateSchemaVersion get the state schema version saved on chain, 0 if the contract never saved one
+func GetStateSchemaVersion() (uint32, error) {
+	versionBytes, err := Instance.GetStateFromKeyByte(StateSchemaVersionKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(versionBytes) == 0 {
+		return 0, nil
+	}
+	version, err := strconv.ParseUint(string(versionBytes), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid state schema version [%s], %s", versionBytes, err)
+	}
+	return uint32(version), nil
+}
+
+// PutStateSchemaVersion save the state schema version on chain
+func PutStateSchemaVersion(version uint32) error {
+	return Instance.PutStateFromKeyByte(StateSchemaVersionKey, []byte(strconv.FormatUint(uint64(version), 10)))
+}
+
+// GetContractVersion get the contract version saved on chain when contract was installed or upgraded
+func GetContractVersion() (string, error) {
+	return Instance.GetStateFromKey(ContractVersionKey)
+}
+
+// PutContractVersion save the contract version on chain
+func PutContractVersion(version string) error {
+	return Instance.PutStateFromKey(ContractVersionKey, version)
+}
+
+// GetStateVersion get the contract version and state schema version saved on chain
+func GetStateVersion() (*StateVersion, error) {
+	contractVersion, err := GetContractVersion()
+	if err != nil {
+		return nil, err
+	}
+	schemaVersion, err := GetStateSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	return &StateVersion{
+		ContractVersion:    contractVersion,
+		StateSchemaVersion: schemaVersion,
+	}, nil
+}
+
+// RunMigrations runs the migrations newer than the saved state schema version in version order,
+// the state schema version is saved after every step
+func RunMigrations(migrations []*Migration) (*MigrationResult, error) {
+	if err := checkMigrations(migrations); err != nil {
+		return nil, err
+	}
+
+	current, err := GetStateSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	latest := LatestStateSchemaVersion(migrations)
+	if current > latest {
+		return nil, fmt.Errorf("state schema version %d is newer than the latest migration %d", current, latest)
+	}
+
+	result := &MigrationResult{
+		FromVersion: current,
+		ToVersion:   current,
+		Applied:     make([]uint32, 0, latest-current),
+	}
+	for _, m := range migrations[current:] {
+		if err = m.Migrate(); err != nil {
+			return result, fmt.Errorf("migration %d [%s] failed, %s", m.Version, m.Description, err)
+		}
+		if err = PutStateSchemaVersion(m.Version); err != nil {
+			return result, err
+		}
+		result.ToVersion = m.Version
+		result.Applied = append(result.Applied, m.Version)
+	}
+	return result, nil
+}
+
+// checkMigrations migrations must be ordered and numbered from 1 without gaps
+func checkMigrations(migrations []*Migration) error {
+	for i, m := range migrations {
+		if m == nil || m.Migrate == nil {
+			return fmt.Errorf("migration %d is empty", i+1)
+		}
+		if m.Version != uint32(i+1) {
+			return fmt.Errorf("migration %d has version %d, migrations must be ordered from version 1",
+				i+1, m.Version)
+		}
+	}
+	return nil
+}
+
+// RewriteStatePrefix rewrites the states whose key starts with prefix, batchSize states are read
+// through NewIteratorPrefixWithKey before they are written back with PutStateByte.
+// rewrite returns the new value of state, a nil value deletes the state.
+// @param1 prefix: 需要迁移的状态key前缀
+// @param2 batchSize: 每批读取的状态数量，小于等于0时使用DefaultMigrationBatchSize
+// @param3 rewrite: 返回状态的新值，返回nil时删除状态
+// @return1: 被修改的状态数量
+// @return2: 获取错误信息
+func RewriteStatePrefix(prefix string, batchSize int,
+	rewrite func(key, field string, value []byte) ([]byte, error)) (int, error) {
+	if rewrite == nil {
+		return 0, errors.New("rewrite func is nil")
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultMigrationBatchSize
+	}
+
+	iter, err := Instance.NewIteratorPrefixWithKey(prefix)
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Close()
+
+	type kv struct {
+		key, field string
+		value      []byte
+	}
+
+	rewritten := 0
+	batch := make([]kv, 0, batchSize)
+	flush := func() error {
+		for _, s := range batch {
+			value, err := rewrite(s.key, s.field, s.value)
+			if err != nil {
+				return fmt.Errorf("rewrite [%s#%s] failed, %s", s.key, s.field, err)
+			}
+			if value != nil && bytes.Equal(value, s.value) {
+				continue
+			}
+			if value == nil {
+				err = Instance.DelState(s.key, s.field)
+			} else {
+				err = Instance.PutStateByte(s.key, s.field, value)
+			}
+			if err != nil {
+				return err
+			}
+			rewritten++
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for iter.HasNext() {
+		key, field, value, err := iter.Next()
+		if err != nil {
+			return rewritten, err
+		}
+		batch = append(batch, kv{key: key, field: field, value: value})
+		if len(batch) == batchSize {
+			if err = flush(); err != nil {
+				return rewritten, err
+			}
+		}
+	}
+	return rewritten, flush()
+}
@@ -0,0 +1,247 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"chainmaker.org/chainmaker/common/v2/serialize"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+)
+
+// DryRunResult result of running the upgrade of contract against a copy of state
+type DryRunResult struct {
+	Migration *MigrationResult
+	Response  protogo.Response
+	// Writes states written by the upgrade, keyed by key or key#field, a nil value means deleted
+	Writes map[string][]byte
+}
+
+// DryRunMigrations runs the pending migrations and UpgradeContract of contract against a copy of state,
+// so a migration can be tested against production state before the upgrade tx is sent.
+// Only state, iterator, args and log methods of Instance are available during the dry run, see NewMemorySDK.
+// @param1 contract: 升级后的合约
+// @param2 state: 合约状态的副本，key为 key 或 key#field
+// @param3 args: 升级合约的参数
+func DryRunMigrations(contract Contract, state map[string][]byte, args map[string][]byte) (
+	result *DryRunResult, err error) {
+
+	mem := newMemoryState(state, args)
+	origin := Instance
+	Instance = mem
+	defer func() {
+		Instance = origin
+		if r := recover(); r != nil {
+			err = fmt.Errorf("dry run failed, %v", r)
+		}
+	}()
+
+	result = &DryRunResult{}
+	if declarer, ok := contract.(MigrationDeclarer); ok {
+		result.Migration, err = RunMigrations(declarer.Migrations())
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Response = contract.UpgradeContract()
+	result.Writes = mem.writes
+	if result.Response.Status != OK {
+		return result, fmt.Errorf("upgrade contract failed, %s", result.Response.Message)
+	}
+	return result, nil
+}
+
+// NewMemorySDK returns an SDKInterface over an in memory copy of state, keyed by key or key#field,
+// the one DryRunMigrations runs against. Only state, iterator, args and log methods are available,
+// so it serves as Instance in unit tests of code working on state only.
+func NewMemorySDK(state map[string][]byte, args map[string][]byte) SDKInterface {
+	return newMemoryState(state, args)
+}
+
+// memoryState SDKInterface over an in memory copy of contract state, methods not overridden
+// panic on the nil embedded interface and fail the dry run
+type memoryState struct {
+	SDKInterface
+
+	args   map[string][]byte
+	state  map[string][]byte
+	writes map[string][]byte
+}
+
+func newMemoryState(state map[string][]byte, args map[string][]byte) *memoryState {
+	m := &memoryState{
+		args:   args,
+		state:  make(map[string][]byte, len(state)),
+		writes: make(map[string][]byte),
+	}
+	for k, v := range state {
+		m.state[k] = v
+	}
+	return m
+}
+
+func memoryKey(key, field string) string {
+	if len(field) == 0 {
+		return key
+	}
+	return key + sandboxKVStoreSeparator + field
+}
+
+func (m *memoryState) GetArgs() map[string][]byte {
+	return m.args
+}
+
+func (m *memoryState) GetStateByte(key, field string) ([]byte, error) {
+	return m.state[memoryKey(key, field)], nil
+}
+
+func (m *memoryState) GetState(key, field string) (string, error) {
+	return string(m.state[memoryKey(key, field)]), nil
+}
+
+func (m *memoryState) GetStateWithExists(key, field string) (string, bool, error) {
+	value, ok := m.state[memoryKey(key, field)]
+	return string(value), ok, nil
+}
+
+func (m *memoryState) GetStateFromKey(key string) (string, error) {
+	return m.GetState(key, "")
+}
+
+func (m *memoryState) GetStateFromKeyWithExists(key string) (string, bool, error) {
+	return m.GetStateWithExists(key, "")
+}
+
+func (m *memoryState) GetStateFromKeyByte(key string) ([]byte, error) {
+	return m.GetStateByte(key, "")
+}
+
+func (m *memoryState) PutStateByte(key, field string, value []byte) error {
+	k := memoryKey(key, field)
+	m.state[k] = value
+	m.writes[k] = value
+	return nil
+}
+
+func (m *memoryState) PutState(key, field string, value string) error {
+	return m.PutStateByte(key, field, []byte(value))
+}
+
+func (m *memoryState) PutStateFromKey(key string, value string) error {
+	return m.PutStateByte(key, "", []byte(value))
+}
+
+func (m *memoryState) PutStateFromKeyByte(key string, value []byte) error {
+	return m.PutStateByte(key, "", value)
+}
+
+func (m *memoryState) DelState(key, field string) error {
+	k := memoryKey(key, field)
+	delete(m.state, k)
+	m.writes[k] = nil
+	return nil
+}
+
+func (m *memoryState) DelStateFromKey(key string) error {
+	return m.DelState(key, "")
+}
+
+func (m *memoryState) NewIteratorPrefixWithKey(key string) (ResultSetKV, error) {
+	return m.newIterator(func(k string) bool {
+		return strings.HasPrefix(k, key)
+	}), nil
+}
+
+func (m *memoryState) NewIteratorPrefixWithKeyField(key string, field string) (ResultSetKV, error) {
+	return m.newIterator(func(k string) bool {
+		return strings.HasPrefix(k, key+sandboxKVStoreSeparator+field)
+	}), nil
+}
+
+func (m *memoryState) NewIteratorWithField(key string, startField string, limitField string) (ResultSetKV, error) {
+	return m.newIterator(func(k string) bool {
+		return k >= key+sandboxKVStoreSeparator+startField && k < key+sandboxKVStoreSeparator+limitField
+	}), nil
+}
+
+// newIterator iterates over a snapshot of the states matching, in key order
+func (m *memoryState) newIterator(match func(k string) bool) *memoryIterator {
+	iter := &memoryIterator{}
+	for k, v := range m.state {
+		if match(k) {
+			iter.keys = append(iter.keys, k)
+			iter.values = append(iter.values, v)
+		}
+	}
+	sort.Sort(iter)
+	return iter
+}
+
+func (m *memoryState) EmitEvent(topic string, data []string) {}
+
+func (m *memoryState) Log(message string) {}
+
+func (m *memoryState) Debugf(format string, a ...interface{}) {}
+
+func (m *memoryState) Infof(format string, a ...interface{}) {}
+
+func (m *memoryState) Warnf(format string, a ...interface{}) {}
+
+func (m *memoryState) Errorf(format string, a ...interface{}) {}
+
+type memoryIterator struct {
+	keys   []string
+	values [][]byte
+	index  int
+}
+
+func (it *memoryIterator) Len() int           { return len(it.keys) }
+func (it *memoryIterator) Less(i, j int) bool { return it.keys[i] < it.keys[j] }
+func (it *memoryIterator) Swap(i, j int) {
+	it.keys[i], it.keys[j] = it.keys[j], it.keys[i]
+	it.values[i], it.values[j] = it.values[j], it.values[i]
+}
+
+func (it *memoryIterator) HasNext() bool {
+	return it.index < len(it.keys)
+}
+
+func (it *memoryIterator) Close() (bool, error) {
+	it.index = len(it.keys)
+	return true, nil
+}
+
+func (it *memoryIterator) NextRow() (*serialize.EasyCodec, error) {
+	key, field, value, err := it.Next()
+	if err != nil {
+		return nil, err
+	}
+	ec := serialize.NewEasyCodec()
+	ec.AddString(EC_KEY_TYPE_KEY, key)
+	ec.AddString(EC_KEY_TYPE_FIELD, field)
+	ec.AddBytes(EC_KEY_TYPE_VALUE, value)
+	return ec, nil
+}
+
+func (it *memoryIterator) Next() (string, string, []byte, error) {
+	if !it.HasNext() {
+		return "", "", nil, errors.New("iterator has no next row")
+	}
+	k, v := it.keys[it.index], it.values[it.index]
+	it.index++
+
+	key, field := k, ""
+	if i := strings.Index(k, sandboxKVStoreSeparator); i >= 0 {
+		key, field = k[:i], k[i+1:]
+	}
+	return key, field, v, nil
+}
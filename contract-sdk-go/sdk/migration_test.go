@@ -0,0 +1,310 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+)
+
+// recordMigrations returns count migrations appending their version to run, the one with version failAt fails
+func recordMigrations(count int, failAt uint32, run *[]uint32) []*Migration {
+	migrations := make([]*Migration, 0, count)
+	for i := 1; i <= count; i++ {
+		version := uint32(i)
+		migrations = append(migrations, NewMigration(version, fmt.Sprintf("step %d", version), func() error {
+			*run = append(*run, version)
+			if version == failAt {
+				return errors.New("broken")
+			}
+			return Instance.PutStateFromKey(fmt.Sprintf("step%d", version), "done")
+		}))
+	}
+	return migrations
+}
+
+func TestRunMigrations(t *testing.T) {
+	tests := []struct {
+		name        string
+		saved       string
+		count       int
+		failAt      uint32
+		wantRun     []uint32
+		wantResult  *MigrationResult
+		wantVersion uint32
+		wantErr     string
+	}{
+		{name: "from scratch", count: 3, wantRun: []uint32{1, 2, 3},
+			wantResult:  &MigrationResult{FromVersion: 0, ToVersion: 3, Applied: []uint32{1, 2, 3}},
+			wantVersion: 3},
+		{name: "applied steps are skipped", saved: "2", count: 4, wantRun: []uint32{3, 4},
+			wantResult:  &MigrationResult{FromVersion: 2, ToVersion: 4, Applied: []uint32{3, 4}},
+			wantVersion: 4},
+		{name: "up to date", saved: "3", count: 3,
+			wantResult:  &MigrationResult{FromVersion: 3, ToVersion: 3, Applied: []uint32{}},
+			wantVersion: 3},
+		{name: "failed step stops migration", saved: "1", count: 4, failAt: 3, wantRun: []uint32{2, 3},
+			wantResult:  &MigrationResult{FromVersion: 1, ToVersion: 2, Applied: []uint32{2}},
+			wantVersion: 2, wantErr: "migration 3 [step 3] failed, broken"},
+		{name: "state newer than migrations", saved: "5", count: 3, wantVersion: 5,
+			wantErr: "state schema version 5 is newer than the latest migration 3"},
+		{name: "invalid saved version", saved: "v1", count: 3,
+			wantErr: "invalid state schema version [v1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := map[string][]byte{}
+			if len(tt.saved) > 0 {
+				state[StateSchemaVersionKey] = []byte(tt.saved)
+			}
+			mem := newMemoryState(state, nil)
+			Instance = mem
+			defer func() { Instance = nil }()
+
+			var run []uint32
+			result, err := RunMigrations(recordMigrations(tt.count, tt.failAt, &run))
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RunMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("RunMigrations() error = %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.wantResult) || !reflect.DeepEqual(run, tt.wantRun) {
+				t.Errorf("RunMigrations() = %+v, run %v, want %+v, run %v", result, run, tt.wantResult, tt.wantRun)
+			}
+			if tt.wantVersion > 0 {
+				version, err := GetStateSchemaVersion()
+				if err != nil || version != tt.wantVersion {
+					t.Errorf("GetStateSchemaVersion() = %d, %v, want %d", version, err, tt.wantVersion)
+				}
+			}
+			for _, version := range run {
+				step, _ := mem.GetStateFromKey(fmt.Sprintf("step%d", version))
+				if (step == "done") != (version != tt.failAt) {
+					t.Errorf("step %d state = %q", version, step)
+				}
+			}
+		})
+	}
+}
+
+func TestRunMigrations_invalid(t *testing.T) {
+	noop := func() error { return nil }
+	tests := []struct {
+		name       string
+		migrations []*Migration
+		wantErr    string
+	}{
+		{name: "not from 1", migrations: []*Migration{NewMigration(2, "", noop)},
+			wantErr: "migration 1 has version 2"},
+		{name: "gap", migrations: []*Migration{NewMigration(1, "", noop), NewMigration(3, "", noop)},
+			wantErr: "migration 2 has version 3"},
+		{name: "out of order", migrations: []*Migration{NewMigration(2, "", noop), NewMigration(1, "", noop)},
+			wantErr: "migration 1 has version 2"},
+		{name: "nil step", migrations: []*Migration{NewMigration(1, "", noop), nil},
+			wantErr: "migration 2 is empty"},
+		{name: "nil migrate", migrations: []*Migration{NewMigration(1, "", nil)},
+			wantErr: "migration 1 is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := newMemoryState(nil, nil)
+			Instance = mem
+			defer func() { Instance = nil }()
+
+			_, err := RunMigrations(tt.migrations)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RunMigrations() error = %v, want %q", err, tt.wantErr)
+			}
+			if len(mem.writes) != 0 {
+				t.Errorf("RunMigrations() wrote %v", mem.writes)
+			}
+		})
+	}
+}
+
+func TestRewriteStatePrefix(t *testing.T) {
+	state := map[string][]byte{
+		"user#a":  []byte("1"),
+		"user#b":  []byte("2"),
+		"user#c":  []byte("3"),
+		"user#d":  []byte("keep"),
+		"user#e":  []byte("drop"),
+		"users#f": []byte("4"),
+		"other#g": []byte("5"),
+	}
+	rewrite := func(key, field string, value []byte) ([]byte, error) {
+		switch string(value) {
+		case "keep":
+			return value, nil
+		case "drop":
+			return nil, nil
+		case "bad":
+			return nil, errors.New("bad value")
+		}
+		return append([]byte("v"), value...), nil
+	}
+
+	tests := []struct {
+		name      string
+		batchSize int
+		state     map[string][]byte
+		want      int
+		wantState map[string]string
+		wantErr   string
+	}{
+		{name: "batched", batchSize: 2, state: state, want: 5, wantState: map[string]string{
+			"user#a": "v1", "user#b": "v2", "user#c": "v3", "user#d": "keep", "users#f": "v4", "other#g": "5"}},
+		{name: "default batch size", state: state, want: 5, wantState: map[string]string{
+			"user#a": "v1", "user#b": "v2", "user#c": "v3", "user#d": "keep", "users#f": "v4", "other#g": "5"}},
+		{name: "failed rewrite", batchSize: 2,
+			state: map[string][]byte{"user#a": []byte("1"), "user#b": []byte("2"), "user#c": []byte("bad")},
+			want:  2, wantState: map[string]string{"user#a": "v1", "user#b": "v2", "user#c": "bad"},
+			wantErr: "rewrite [user#c] failed, bad value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := newMemoryState(tt.state, nil)
+			Instance = mem
+			defer func() { Instance = nil }()
+
+			got, err := RewriteStatePrefix("user", tt.batchSize, rewrite)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RewriteStatePrefix() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("RewriteStatePrefix() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RewriteStatePrefix() = %d, want %d", got, tt.want)
+			}
+			gotState := make(map[string]string, len(mem.state))
+			for k, v := range mem.state {
+				gotState[k] = string(v)
+			}
+			if !reflect.DeepEqual(gotState, tt.wantState) {
+				t.Errorf("state = %v, want %v", gotState, tt.wantState)
+			}
+		})
+	}
+}
+
+// upgradeContract renames the balance state in its migration and records the upgrade args
+type upgradeContract struct {
+	migrations []*Migration
+	callOther  bool
+}
+
+func (c *upgradeContract) InitContract() protogo.Response {
+	return Success(nil)
+}
+
+func (c *upgradeContract) UpgradeContract() protogo.Response {
+	if c.callOther {
+		return Instance.CallContract("other", "method", nil)
+	}
+	version, ok := Instance.GetArgs()["version"]
+	if !ok {
+		return Error("missing version")
+	}
+	if err := Instance.PutStateFromKeyByte("version", version); err != nil {
+		return Error(err.Error())
+	}
+	return Success(nil)
+}
+
+func (c *upgradeContract) InvokeContract(method string) protogo.Response {
+	return Error("unknown method")
+}
+
+func (c *upgradeContract) Migrations() []*Migration {
+	return c.migrations
+}
+
+func TestDryRunMigrations(t *testing.T) {
+	renameBalances := NewMigration(1, "rename balances", func() error {
+		_, err := RewriteStatePrefix("balance", 0, func(key, field string, value []byte) ([]byte, error) {
+			if err := Instance.PutStateByte("account", field, value); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		})
+		return err
+	})
+	failing := NewMigration(2, "failing", func() error {
+		if err := Instance.PutStateFromKey("half", "done"); err != nil {
+			return err
+		}
+		return errors.New("broken")
+	})
+
+	tests := []struct {
+		name          string
+		contract      *upgradeContract
+		args          map[string][]byte
+		wantMigration *MigrationResult
+		wantWrites    map[string][]byte
+		wantErr       string
+	}{
+		{name: "migrated and upgraded", contract: &upgradeContract{migrations: []*Migration{renameBalances}},
+			args:          map[string][]byte{"version": []byte("2.0")},
+			wantMigration: &MigrationResult{FromVersion: 0, ToVersion: 1, Applied: []uint32{1}},
+			wantWrites: map[string][]byte{
+				"balance#alice": nil, "balance#bob": nil,
+				"account#alice": []byte("10"), "account#bob": []byte("20"),
+				StateSchemaVersionKey: []byte("1"), "version": []byte("2.0"),
+			}},
+		{name: "failed migration", contract: &upgradeContract{migrations: []*Migration{renameBalances, failing}},
+			args:          map[string][]byte{"version": []byte("2.0")},
+			wantMigration: &MigrationResult{FromVersion: 0, ToVersion: 1, Applied: []uint32{1}},
+			wantErr:       "migration 2 [failing] failed, broken"},
+		{name: "failed upgrade", contract: &upgradeContract{},
+			wantMigration: &MigrationResult{Applied: []uint32{}},
+			wantWrites:    map[string][]byte{}, wantErr: "upgrade contract failed, missing version"},
+		{name: "unavailable method", contract: &upgradeContract{callOther: true},
+			wantMigration: &MigrationResult{Applied: []uint32{}}, wantErr: "dry run failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := map[string][]byte{"balance#alice": []byte("10"), "balance#bob": []byte("20")}
+			origin := newMemoryState(nil, nil)
+			Instance = origin
+			defer func() { Instance = nil }()
+
+			result, err := DryRunMigrations(tt.contract, state, tt.args)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DryRunMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("DryRunMigrations() error = %v", err)
+			}
+			if Instance != origin {
+				t.Errorf("DryRunMigrations() did not restore Instance")
+			}
+			if len(state) != 2 || string(state["balance#alice"]) != "10" || string(state["balance#bob"]) != "20" {
+				t.Errorf("DryRunMigrations() changed the given state, %v", state)
+			}
+			if result == nil {
+				return
+			}
+			if !reflect.DeepEqual(result.Migration, tt.wantMigration) {
+				t.Errorf("DryRunMigrations() migration = %+v, want %+v", result.Migration, tt.wantMigration)
+			}
+			if tt.wantWrites != nil && !reflect.DeepEqual(result.Writes, tt.wantWrites) {
+				t.Errorf("DryRunMigrations() writes = %v, want %v", result.Writes, tt.wantWrites)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// CallContextParam is the reserved arg key the call stack of a cross contract call is passed with.
+// The vm-engine replaces whatever a tx or a contract puts under it from version 2.3.6 on and flags the tx request,
+// the call stack is only trusted then.
+const CallContextParam = "__call_context__"
+
+// ErrReentrantCall is returned by NonReentrant when the lock is held by current contract
+// or by a contract in the call stack
+var ErrReentrantCall = errors.New("reentrant call")
+
+// ErrUnknownCallStack is returned by NonReentrant when a contract in the call stack is not a go contract,
+// the locks it may hold are unknown, so the guard fails closed
+var ErrUnknownCallStack = errors.New("call stack is unknown, a caller is not a go contract")
+
+// CallContext is the call stack of current contract given by the vm-engine
+type CallContext struct {
+	// Callers the names of the contracts calling current contract, the tx entry contract first
+	Callers []string `json:"callers"`
+	// Locks the reentrancy locks held by the callers, as contractName/lockName
+	Locks []string `json:"locks"`
+}
+
+var (
+	callMutex    sync.Mutex
+	callStack    CallContext
+	callDepth    uint32
+	callContract string
+	// locks held by current contract in current tx
+	heldLocks = make(map[string]bool)
+)
+
+// ResetCallContext takes the call stack of next tx out of its args and releases the locks of the last tx.
+// The call stack is dropped unless trusted, i.e. the vm-engine flagged it as set by itself.
+func ResetCallContext(args map[string][]byte, trusted bool, depth uint32, contractName string) {
+	callMutex.Lock()
+	defer callMutex.Unlock()
+
+	callStack = CallContext{}
+	if value, ok := args[CallContextParam]; ok {
+		delete(args, CallContextParam)
+		if !trusted {
+			value = nil
+		}
+		if err := json.Unmarshal(value, &callStack); err != nil {
+			callStack = CallContext{}
+		}
+	}
+	callDepth = depth
+	callContract = contractName
+	heldLocks = make(map[string]bool)
+}
+
+// CallDepth returns the depth of current contract in the call stack, 0 for the contract called by the tx
+func CallDepth() uint32 {
+	callMutex.Lock()
+	defer callMutex.Unlock()
+	return callDepth
+}
+
+// Callers returns the names of the contracts calling current contract, the tx entry contract first.
+// Callers which are not go contracts are missing, len(Callers()) < CallDepth() in that case.
+func Callers() []string {
+	callMutex.Lock()
+	defer callMutex.Unlock()
+	return append([]string(nil), callStack.Callers...)
+}
+
+// Caller returns the name of the contract calling current contract, empty if called by the tx
+// or by a contract which is not a go contract
+func Caller() string {
+	callMutex.Lock()
+	defer callMutex.Unlock()
+	if int(callDepth) != len(callStack.Callers) || len(callStack.Callers) == 0 {
+		return ""
+	}
+	return callStack.Callers[len(callStack.Callers)-1]
+}
+
+// NonReentrant takes the lock name of current contract till release is called, the lock is passed down
+// to every contract called meanwhile, so the call fails with ErrReentrantCall if it comes back to a method
+// guarded by the same lock, directly or through other contracts. Locks are released at the end of the tx.
+//
+//	release, err := sdk.NonReentrant("transfer")
+//	if err != nil {
+//		return sdk.Error(err.Error())
+//	}
+//	defer release()
+func NonReentrant(name string) (release func(), err error) {
+	callMutex.Lock()
+	defer callMutex.Unlock()
+
+	if int(callDepth) > len(callStack.Callers) {
+		return nil, ErrUnknownCallStack
+	}
+	lock := callContract + "/" + name
+	if heldLocks[lock] {
+		return nil, ErrReentrantCall
+	}
+	for _, held := range callStack.Locks {
+		if held == lock {
+			return nil, ErrReentrantCall
+		}
+	}
+	heldLocks[lock] = true
+
+	return func() {
+		callMutex.Lock()
+		defer callMutex.Unlock()
+		delete(heldLocks, lock)
+	}, nil
+}
+
+// heldLockNames returns the names of the locks held by current contract, nil if none. CallContract passes
+// them to the engine, which prefixes them with the contract name
+func heldLockNames() []byte {
+	callMutex.Lock()
+	defer callMutex.Unlock()
+	if len(heldLocks) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(heldLocks))
+	for lock := range heldLocks {
+		names = append(names, lock[len(callContract)+1:])
+	}
+	sort.Strings(names)
+	data, _ := json.Marshal(names)
+	return data
+}
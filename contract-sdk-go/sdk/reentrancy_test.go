@@ -0,0 +1,71 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNonReentrant(t *testing.T) {
+	tests := []struct {
+		name        string
+		callContext string
+		untrusted   bool
+		depth       uint32
+		wantCallers []string
+		wantCaller  string
+		wantErr     error
+	}{
+		{name: "called by tx"},
+		{name: "called by go contract", callContext: `{"callers":["exchange"],"locks":["exchange/trade"]}`,
+			depth: 1, wantCallers: []string{"exchange"}, wantCaller: "exchange"},
+		{name: "reentered", callContext: `{"callers":["erc721","exchange"],"locks":["erc721/transfer"]}`,
+			depth: 2, wantCallers: []string{"erc721", "exchange"}, wantCaller: "exchange", wantErr: ErrReentrantCall},
+		{name: "called by evm contract", callContext: `{"callers":["erc721"]}`, depth: 2,
+			wantCallers: []string{"erc721"}, wantErr: ErrUnknownCallStack},
+		{name: "invalid call context", callContext: "erc721", depth: 1, wantErr: ErrUnknownCallStack},
+		{name: "forged by tx", callContext: `{"callers":["exchange"]}`, untrusted: true},
+		{name: "not set by engine", callContext: `{"callers":["exchange"],"locks":["exchange/trade"]}`,
+			untrusted: true, depth: 1, wantErr: ErrUnknownCallStack},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string][]byte{"tokenId": []byte("1")}
+			if len(tt.callContext) > 0 {
+				args[CallContextParam] = []byte(tt.callContext)
+			}
+			ResetCallContext(args, !tt.untrusted, tt.depth, "erc721")
+			if _, ok := args[CallContextParam]; ok || len(args) != 1 {
+				t.Errorf("args = %v, want call context removed", args)
+			}
+			if CallDepth() != tt.depth || !reflect.DeepEqual(Callers(), tt.wantCallers) || Caller() != tt.wantCaller {
+				t.Errorf("call stack = %d %v %q, want %d %v %q", CallDepth(), Callers(), Caller(),
+					tt.depth, tt.wantCallers, tt.wantCaller)
+			}
+			release, err := NonReentrant("transfer")
+			if err != tt.wantErr {
+				t.Fatalf("NonReentrant() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if _, err = NonReentrant("transfer"); err != ErrReentrantCall {
+				t.Errorf("NonReentrant() held error = %v, want %v", err, ErrReentrantCall)
+			}
+			if string(heldLockNames()) != `["transfer"]` {
+				t.Errorf("heldLockNames() = %s", heldLockNames())
+			}
+			release()
+			if release, err = NonReentrant("transfer"); err != nil {
+				t.Errorf("NonReentrant() released error = %v", err)
+			}
+			release()
+		})
+	}
+}
@@ -617,6 +617,12 @@ func (s *SDK) CallContract(contractName, method string, args map[string][]byte)
 	for key, value := range args {
 		initialArgs[key] = value
 	}
+	// locks held by current contract, the engine passes them down with the call stack
+	if locks := heldLockNames(); locks != nil {
+		initialArgs[CallContextParam] = locks
+	} else {
+		delete(initialArgs, CallContextParam)
+	}
 
 	callContractPayloadStruct := &protogo.CallContractRequest{
 		ContractName:   contractName,
@@ -0,0 +1,697 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+)
+
+const (
+	paramCommitBlocks = "commitBlocks"
+	paramRevealBlocks = "revealBlocks"
+	paramMinReveals   = "minReveals"
+	paramRound        = "round"
+	paramCommitment   = "commitment"
+	paramSecret       = "secret"
+	paramAddress      = "address"
+
+	keyConfig       = "config"
+	keyAdmin        = "admin"
+	keyCurrentRound = "currentRound"
+	keyRound        = "round"
+	keyCommit       = "commit"
+	keyReveal       = "reveal"
+	keyParticipant  = "participant"
+
+	eventRoundStarted   = "RoundStarted"
+	eventCommitted      = "Committed"
+	eventRevealed       = "Revealed"
+	eventRoundFinalized = "RoundFinalized"
+	eventRoundFailed    = "RoundFailed"
+	eventPenalized      = "Penalized"
+
+	defaultCommitBlocks = 10
+	defaultRevealBlocks = 10
+	defaultMinReveals   = 1
+
+	// secretSize secrets are 32 random bytes
+	secretSize = 32
+)
+
+// BeaconContract commit-reveal randomness beacon. Participants registered by the admin commit
+// sha256(secret || address) in the commit phase of a round and reveal the secret in the reveal phase.
+// After the reveal phase anyone finalizes the round, the seed of the round is the sha256 of the revealed
+// secrets ordered by address and the hash of the block at the reveal deadline, which is unknown when the
+// secrets are committed.
+// A participant who commits but withholds the reveal could choose between the seeds with and without
+// its secret, so a round with any withheld reveal fails without a seed, and the withholders are banned
+// until the admin pardons them. Withholding only makes the consumers wait for a later round, at the cost
+// of the ban.
+// Other contracts get the seed of a finalized round by calling GetRandomness with CallContract.
+type BeaconContract struct {
+}
+
+// Config beacon config, set when the contract is installed
+type Config struct {
+	CommitBlocks uint64 `json:"commitBlocks"`
+	RevealBlocks uint64 `json:"revealBlocks"`
+	MinReveals   uint64 `json:"minReveals"`
+}
+
+// Participant beacon participant, Missed counts the reveals it withheld
+type Participant struct {
+	Address    string `json:"address"`
+	Registered bool   `json:"registered"`
+	Missed     uint64 `json:"missed"`
+	Banned     bool   `json:"banned"`
+}
+
+// Round one round of the beacon. The commits and reveals are saved per participant under
+// keyCommit and keyReveal, so participants do not write the same state, Revealed and Withheld
+// are set when the round is finalized
+type Round struct {
+	Round          uint64   `json:"round"`
+	StartHeight    uint64   `json:"startHeight"`
+	CommitDeadline uint64   `json:"commitDeadline"`
+	RevealDeadline uint64   `json:"revealDeadline"`
+	Revealed       uint64   `json:"revealed"`
+	Withheld       []string `json:"withheld,omitempty"`
+	Seed           string   `json:"seed,omitempty"`
+	Finalized      bool     `json:"finalized"`
+	Failed         bool     `json:"failed"`
+}
+
+// InitContract install contract func
+func (b *BeaconContract) InitContract() protogo.Response {
+	params := sdk.Instance.GetArgs()
+	config := &Config{
+		CommitBlocks: defaultCommitBlocks,
+		RevealBlocks: defaultRevealBlocks,
+		MinReveals:   defaultMinReveals,
+	}
+	for key, value := range map[string]*uint64{
+		paramCommitBlocks: &config.CommitBlocks,
+		paramRevealBlocks: &config.RevealBlocks,
+		paramMinReveals:   &config.MinReveals,
+	} {
+		if len(params[key]) == 0 {
+			continue
+		}
+		num, err := strconv.ParseUint(string(params[key]), 10, 64)
+		if err != nil || num == 0 {
+			return sdk.Error(fmt.Sprintf("%s should be a positive integer", key))
+		}
+		*value = num
+	}
+	if err := putJSON(keyConfig, "", config); err != nil {
+		return sdk.Error(err.Error())
+	}
+
+	admin, err := sdk.Instance.Origin()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("get origin failed, %s", err))
+	}
+	if err = sdk.Instance.PutState(keyAdmin, "", admin); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("Init contract success"))
+}
+
+// UpgradeContract upgrade contract func
+func (b *BeaconContract) UpgradeContract() protogo.Response {
+	return sdk.Success([]byte("Upgrade contract success"))
+}
+
+// InvokeContract the entry func of invoke contract func
+func (b *BeaconContract) InvokeContract(method string) protogo.Response {
+	switch method {
+	case "register":
+		return b.register()
+	case "unregister":
+		return b.unregister()
+	case "pardon":
+		return b.pardon()
+	case "startRound":
+		return b.startRound()
+	case "commit":
+		return b.commit()
+	case "reveal":
+		return b.reveal()
+	case "finalize":
+		return b.finalize()
+	case "getRandomness":
+		return b.getRandomness()
+	case "getRound":
+		return b.getRound()
+	case "currentRound":
+		return b.currentRound()
+	case "getParticipant":
+		return b.getParticipant()
+	default:
+		return sdk.Error("invalid method")
+	}
+}
+
+// register a participant, admin only, so that nobody registers addresses of its own to withhold reveals
+// and dodge the bans
+func (b *BeaconContract) register() protogo.Response {
+	address, err := requireAdminAddress("register")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	participant, err := getParticipant(address)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if participant.Banned {
+		return sdk.Error(fmt.Sprintf("participant %s is banned for withholding %d reveals", address,
+			participant.Missed))
+	}
+	participant.Registered = true
+	if err = putJSON(keyParticipant, address, participant); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("ok"))
+}
+
+func (b *BeaconContract) unregister() protogo.Response {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("get sender failed, %s", err))
+	}
+	participant, err := getParticipant(sender)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	// the commits made are still revealed or penalized
+	participant.Registered = false
+	if err = putJSON(keyParticipant, sender, participant); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("ok"))
+}
+
+// pardon clear the withheld reveals of a participant and lift the ban, admin only
+func (b *BeaconContract) pardon() protogo.Response {
+	address, err := requireAdminAddress("pardon")
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	participant, err := getParticipant(address)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	participant.Missed = 0
+	participant.Banned = false
+	if err = putJSON(keyParticipant, address, participant); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("ok"))
+}
+
+// startRound start the next round, the current round is finalized first if its reveal phase is over
+func (b *BeaconContract) startRound() protogo.Response {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("get sender failed, %s", err))
+	}
+	if err = requireRegistered(sender); err != nil {
+		return sdk.Error(err.Error())
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	config, err := getConfig()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+
+	current, err := getCurrentRound()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if current > 0 {
+		round, err1 := getRound(current)
+		if err1 != nil {
+			return sdk.Error(err1.Error())
+		}
+		if !round.Finalized {
+			if height <= round.RevealDeadline {
+				return sdk.Error(fmt.Sprintf("round %d is in progress until block %d", current,
+					round.RevealDeadline))
+			}
+			if err1 = finalizeRound(round, config, height); err1 != nil {
+				return sdk.Error(err1.Error())
+			}
+		}
+	}
+
+	round := &Round{
+		Round:          current + 1,
+		StartHeight:    height,
+		CommitDeadline: height + config.CommitBlocks,
+		RevealDeadline: height + config.CommitBlocks + config.RevealBlocks,
+	}
+	if err = putJSON(keyRound, roundField(round.Round), round); err != nil {
+		return sdk.Error(err.Error())
+	}
+	if err = sdk.Instance.PutState(keyCurrentRound, "", roundField(round.Round)); err != nil {
+		return sdk.Error(err.Error())
+	}
+	sdk.Instance.EmitEvent(eventRoundStarted, []string{roundField(round.Round),
+		strconv.FormatUint(round.CommitDeadline, 10), strconv.FormatUint(round.RevealDeadline, 10)})
+	return sdk.Success([]byte(roundField(round.Round)))
+}
+
+func (b *BeaconContract) commit() protogo.Response {
+	params := sdk.Instance.GetArgs()
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("get sender failed, %s", err))
+	}
+	if err = requireRegistered(sender); err != nil {
+		return sdk.Error(err.Error())
+	}
+	round, err := requireRound(params)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	commitment := string(params[paramCommitment])
+	if c, err1 := hex.DecodeString(commitment); err1 != nil || len(c) != sha256.Size {
+		return sdk.Error("commitment should be the hex of sha256(secret || address)")
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if height > round.CommitDeadline {
+		return sdk.Error(fmt.Sprintf("commit phase of round %d ended at block %d", round.Round,
+			round.CommitDeadline))
+	}
+	field := participantField(round.Round, sender)
+	committed, err := sdk.Instance.GetState(keyCommit, field)
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("get commit failed, %s", err))
+	}
+	if len(committed) > 0 {
+		return sdk.Error(fmt.Sprintf("%s already committed in round %d", sender, round.Round))
+	}
+
+	if err = sdk.Instance.PutState(keyCommit, field, commitment); err != nil {
+		return sdk.Error(fmt.Sprintf("put commit failed, %s", err))
+	}
+	sdk.Instance.EmitEvent(eventCommitted, []string{roundField(round.Round), sender, commitment})
+	return sdk.Success([]byte("ok"))
+}
+
+func (b *BeaconContract) reveal() protogo.Response {
+	params := sdk.Instance.GetArgs()
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("get sender failed, %s", err))
+	}
+	round, err := requireRound(params)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	secret, err := hex.DecodeString(string(params[paramSecret]))
+	if err != nil || len(secret) != secretSize {
+		return sdk.Error(fmt.Sprintf("secret should be the hex of %d bytes", secretSize))
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if height <= round.CommitDeadline || height > round.RevealDeadline {
+		return sdk.Error(fmt.Sprintf("reveal phase of round %d is from block %d to %d", round.Round,
+			round.CommitDeadline+1, round.RevealDeadline))
+	}
+	field := participantField(round.Round, sender)
+	commitment, err := sdk.Instance.GetState(keyCommit, field)
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("get commit failed, %s", err))
+	}
+	if len(commitment) == 0 {
+		return sdk.Error(fmt.Sprintf("%s did not commit in round %d", sender, round.Round))
+	}
+	revealed, err := sdk.Instance.GetState(keyReveal, field)
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("get reveal failed, %s", err))
+	}
+	if len(revealed) > 0 {
+		return sdk.Error(fmt.Sprintf("%s already revealed in round %d", sender, round.Round))
+	}
+	if Commitment(secret, sender) != commitment {
+		return sdk.Error("secret does not match the commitment")
+	}
+
+	if err = sdk.Instance.PutState(keyReveal, field, hex.EncodeToString(secret)); err != nil {
+		return sdk.Error(fmt.Sprintf("put reveal failed, %s", err))
+	}
+	sdk.Instance.EmitEvent(eventRevealed, []string{roundField(round.Round), sender})
+	return sdk.Success([]byte("ok"))
+}
+
+func (b *BeaconContract) finalize() protogo.Response {
+	round, err := requireRound(sdk.Instance.GetArgs())
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if round.Finalized {
+		return sdk.Error(fmt.Sprintf("round %d is finalized", round.Round))
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if height <= round.RevealDeadline {
+		return sdk.Error(fmt.Sprintf("reveal phase of round %d ends at block %d", round.Round,
+			round.RevealDeadline))
+	}
+	config, err := getConfig()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if err = finalizeRound(round, config, height); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(round.Seed))
+}
+
+// getRandomness return the hex seed of a finalized round
+func (b *BeaconContract) getRandomness() protogo.Response {
+	round, err := requireRound(sdk.Instance.GetArgs())
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if !round.Finalized {
+		return sdk.Error(fmt.Sprintf("round %d is not finalized", round.Round))
+	}
+	if round.Failed {
+		return sdk.Error(fmt.Sprintf("round %d failed, %d reveals with %d withheld", round.Round,
+			round.Revealed, len(round.Withheld)))
+	}
+	return sdk.Success([]byte(round.Seed))
+}
+
+func (b *BeaconContract) getRound() protogo.Response {
+	round, err := requireRound(sdk.Instance.GetArgs())
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	roundBytes, err := json.Marshal(round)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(roundBytes)
+}
+
+func (b *BeaconContract) currentRound() protogo.Response {
+	current, err := getCurrentRound()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(roundField(current)))
+}
+
+func (b *BeaconContract) getParticipant() protogo.Response {
+	address := string(sdk.Instance.GetArgs()[paramAddress])
+	if len(address) == 0 {
+		return sdk.Error("address should not be empty")
+	}
+	participant, err := getParticipant(address)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	participantBytes, err := json.Marshal(participant)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(participantBytes)
+}
+
+// Commitment the commitment of secret by address, hex of sha256(secret || address),
+// binding the address keeps others from replaying the commitment
+func Commitment(secret []byte, address string) string {
+	h := sha256.New()
+	h.Write(secret)
+	h.Write([]byte(address))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// finalizeRound compute the seed of the round, the round fails if any participant withheld its reveal
+// or the reveals are less than MinReveals, and the withholders are banned
+func finalizeRound(round *Round, config *Config, height uint64) error {
+	reveals, withheld, err := getRoundReveals(round.Round)
+	if err != nil {
+		return err
+	}
+	for _, address := range withheld {
+		participant, err1 := getParticipant(address)
+		if err1 != nil {
+			return err1
+		}
+		participant.Missed++
+		participant.Banned = true
+		participant.Registered = false
+		if err1 = putJSON(keyParticipant, address, participant); err1 != nil {
+			return err1
+		}
+		sdk.Instance.EmitEvent(eventPenalized, []string{roundField(round.Round), address,
+			strconv.FormatUint(participant.Missed, 10)})
+	}
+
+	round.Finalized = true
+	round.Revealed = uint64(len(reveals))
+	round.Withheld = withheld
+	if len(withheld) > 0 || round.Revealed < config.MinReveals {
+		round.Failed = true
+		sdk.Instance.EmitEvent(eventRoundFailed, []string{roundField(round.Round),
+			strconv.FormatUint(round.Revealed, 10), strconv.Itoa(len(withheld))})
+		return putJSON(keyRound, roundField(round.Round), round)
+	}
+
+	hash, err := getBlockHash(round.RevealDeadline)
+	if err != nil {
+		return err
+	}
+	revealers := make([]string, 0, len(reveals))
+	for address := range reveals {
+		revealers = append(revealers, address)
+	}
+	sort.Strings(revealers)
+	h := sha256.New()
+	for _, address := range revealers {
+		h.Write(reveals[address])
+	}
+	h.Write(hash)
+	round.Seed = hex.EncodeToString(h.Sum(nil))
+
+	sdk.Instance.Infof("round %d finalized at block %d with %d reveals", round.Round, height, len(revealers))
+	sdk.Instance.EmitEvent(eventRoundFinalized, []string{roundField(round.Round), round.Seed})
+	return putJSON(keyRound, roundField(round.Round), round)
+}
+
+// getRoundReveals get the revealed secrets by address and the sorted addresses which committed
+// but did not reveal in the round
+func getRoundReveals(num uint64) (map[string][]byte, []string, error) {
+	prefix := roundField(num) + "_"
+	iter, err := sdk.Instance.NewIteratorPrefixWithKeyField(keyCommit, prefix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("iterate commits of round %d failed, %s", num, err)
+	}
+	defer iter.Close()
+
+	reveals := map[string][]byte{}
+	var withheld []string
+	for iter.HasNext() {
+		_, field, _, err1 := iter.Next()
+		if err1 != nil {
+			return nil, nil, fmt.Errorf("iterate commits of round %d failed, %s", num, err1)
+		}
+		address := strings.TrimPrefix(field, prefix)
+		revealed, err1 := sdk.Instance.GetState(keyReveal, field)
+		if err1 != nil {
+			return nil, nil, fmt.Errorf("get reveal of %s failed, %s", address, err1)
+		}
+		if len(revealed) == 0 {
+			withheld = append(withheld, address)
+			continue
+		}
+		if reveals[address], err1 = hex.DecodeString(revealed); err1 != nil {
+			return nil, nil, fmt.Errorf("decode reveal of %s failed, %s", address, err1)
+		}
+	}
+	sort.Strings(withheld)
+	return reveals, withheld, nil
+}
+
+// getBlockHash get the hash of the committed block at height from the chain query contract
+func getBlockHash(height uint64) ([]byte, error) {
+	resp := sdk.Instance.CallContract(syscontract.SystemContract_CHAIN_QUERY.String(),
+		syscontract.ChainQueryFunction_GET_BLOCK_HEADER_BY_HEIGHT.String(), map[string][]byte{
+			"blockHeight": []byte(strconv.FormatUint(height, 10)),
+		})
+	if resp.Status != sdk.OK {
+		return nil, fmt.Errorf("get block header of %d failed, %s", height, resp.Message)
+	}
+	var header common.BlockHeader
+	if err := header.Unmarshal(resp.Payload); err != nil {
+		return nil, fmt.Errorf("unmarshal block header of %d failed, %s", height, err)
+	}
+	if len(header.BlockHash) == 0 || bytes.Equal(header.BlockHash, make([]byte, len(header.BlockHash))) {
+		return nil, fmt.Errorf("block hash of %d is empty", height)
+	}
+	return header.BlockHash, nil
+}
+
+func blockHeight() (uint64, error) {
+	height, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return 0, fmt.Errorf("get block height failed, %s", err)
+	}
+	return uint64(height), nil
+}
+
+func roundField(round uint64) string {
+	return strconv.FormatUint(round, 10)
+}
+
+// participantField the field of the commit and reveal of address in round
+func participantField(round uint64, address string) string {
+	return roundField(round) + "_" + address
+}
+
+// requireAdminAddress check the sender is the admin and return the address param
+func requireAdminAddress(method string) (string, error) {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return "", fmt.Errorf("get sender failed, %s", err)
+	}
+	admin, err := sdk.Instance.GetState(keyAdmin, "")
+	if err != nil {
+		return "", err
+	}
+	if sender != admin {
+		return "", fmt.Errorf("only admin can %s participants", method)
+	}
+	address := string(sdk.Instance.GetArgs()[paramAddress])
+	if len(address) == 0 {
+		return "", errors.New("address should not be empty")
+	}
+	return address, nil
+}
+
+func requireRegistered(address string) error {
+	participant, err := getParticipant(address)
+	if err != nil {
+		return err
+	}
+	if !participant.Registered {
+		return fmt.Errorf("%s is not a registered participant", address)
+	}
+	return nil
+}
+
+func requireRound(params map[string][]byte) (*Round, error) {
+	num, err := strconv.ParseUint(string(params[paramRound]), 10, 64)
+	if err != nil || num == 0 {
+		return nil, errors.New("round should be a positive integer")
+	}
+	return getRound(num)
+}
+
+func getRound(num uint64) (*Round, error) {
+	var round Round
+	ok, err := getJSON(keyRound, roundField(num), &round)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("round %d not found", num)
+	}
+	return &round, nil
+}
+
+func getCurrentRound() (uint64, error) {
+	current, err := sdk.Instance.GetState(keyCurrentRound, "")
+	if err != nil {
+		return 0, err
+	}
+	if len(current) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseUint(current, 10, 64)
+}
+
+func getConfig() (*Config, error) {
+	var config Config
+	ok, err := getJSON(keyConfig, "", &config)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("config not found")
+	}
+	return &config, nil
+}
+
+func getParticipant(address string) (*Participant, error) {
+	participant := &Participant{Address: address}
+	if _, err := getJSON(keyParticipant, address, participant); err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
+func getJSON(key, field string, v interface{}) (bool, error) {
+	data, err := sdk.Instance.GetStateByte(key, field)
+	if err != nil {
+		return false, fmt.Errorf("get %s %s failed, %s", key, field, err)
+	}
+	if len(data) == 0 {
+		return false, nil
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("unmarshal %s %s failed, %s", key, field, err)
+	}
+	return true, nil
+}
+
+func putJSON(key, field string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err = sdk.Instance.PutStateByte(key, field, data); err != nil {
+		return fmt.Errorf("put %s %s failed, %s", key, field, err)
+	}
+	return nil
+}
+
+func main() {
+	err := sandbox.Start(new(BeaconContract))
+	if err != nil {
+		sdk.Instance.Errorf(err.Error())
+	}
+}
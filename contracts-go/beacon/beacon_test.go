@@ -0,0 +1,311 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	admin = "admin"
+	alice = "alice"
+	bob   = "bob"
+)
+
+var blockHash = bytes.Repeat([]byte{0x11}, 32)
+
+// mockGetPutState keeps the state of a test in the returned map by key#field
+func mockGetPutState(mockInstance *sdk.MockSDKInterface) map[string][]byte {
+	stateMap := make(map[string][]byte)
+	mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) (string, error) {
+			return string(stateMap[key+"#"+field]), nil
+		})
+	mockInstance.EXPECT().PutState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field, value string) error {
+			stateMap[key+"#"+field] = []byte(value)
+			return nil
+		})
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) ([]byte, error) {
+			return stateMap[key+"#"+field], nil
+		})
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string, value []byte) error {
+			stateMap[key+"#"+field] = value
+			return nil
+		})
+	mockInstance.EXPECT().NewIteratorPrefixWithKeyField(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) (sdk.ResultSetKV, error) {
+			return newKVIterator(stateMap, key+"#"+field), nil
+		})
+	return stateMap
+}
+
+// mockBlockHeader the chain query contract returns a header with blockHash
+func mockBlockHeader(mockInstance *sdk.MockSDKInterface) {
+	mockInstance.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(contractName, method string, args map[string][]byte) protogo.Response {
+			header := &common.BlockHeader{BlockHash: blockHash}
+			headerBytes, err := header.Marshal()
+			if err != nil {
+				return sdk.Error(err.Error())
+			}
+			return sdk.Success(headerBytes)
+		})
+}
+
+// kvIterator iterates the mocked state with a prefix in key order, NextRow is not used by the contract
+type kvIterator struct {
+	sdk.ResultSetKV
+	state map[string][]byte
+	keys  []string
+}
+
+func newKVIterator(state map[string][]byte, prefix string) *kvIterator {
+	iter := &kvIterator{state: state}
+	for k := range state {
+		if strings.HasPrefix(k, prefix) {
+			iter.keys = append(iter.keys, k)
+		}
+	}
+	sort.Strings(iter.keys)
+	return iter
+}
+
+func (i *kvIterator) HasNext() bool {
+	return len(i.keys) > 0
+}
+
+func (i *kvIterator) Next() (string, string, []byte, error) {
+	k := i.keys[0]
+	i.keys = i.keys[1:]
+	keyField := strings.SplitN(k, "#", 2)
+	return keyField[0], keyField[1], i.state[k], nil
+}
+
+func (i *kvIterator) Close() (bool, error) {
+	return true, nil
+}
+
+func secretOf(b byte) []byte {
+	return bytes.Repeat([]byte{b}, secretSize)
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// step one invocation of a test, at height
+type step struct {
+	name   string
+	height int
+	sender string
+	method string
+	args   map[string][]byte
+	want   protogo.Response
+}
+
+func TestBeaconContract_register(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, args := admin, map[string][]byte{}
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return args })
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().Origin().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().Return(1, nil)
+	mockGetPutState(mockInstance)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	sdk.Instance = mockInstance
+
+	if got := new(BeaconContract).InitContract(); got.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", got)
+	}
+	tests := []step{
+		{name: "not admin", sender: alice, method: "register",
+			args: map[string][]byte{paramAddress: []byte(alice)},
+			want: sdk.Error("only admin can register participants")},
+		{name: "no address", sender: admin, method: "register", want: sdk.Error("address should not be empty")},
+		{name: "not registered", sender: alice, method: "startRound",
+			want: sdk.Error("alice is not a registered participant")},
+		{name: "register", sender: admin, method: "register", args: map[string][]byte{paramAddress: []byte(alice)},
+			want: sdk.Success([]byte("ok"))},
+		{name: "registered", sender: alice, method: "startRound", want: sdk.Success([]byte("1"))},
+		{name: "unregister", sender: alice, method: "unregister", want: sdk.Success([]byte("ok"))},
+		{name: "unregistered", sender: alice, method: "getParticipant",
+			args: map[string][]byte{paramAddress: []byte(alice)},
+			want: sdk.Success(mustJSON(t, &Participant{Address: alice}))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, args = tt.sender, tt.args
+			if got := new(BeaconContract).InvokeContract(tt.method); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s() = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBeaconContract_commitReveal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, args, height := admin, map[string][]byte{}, 1
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return args })
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().Origin().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return height, nil })
+	mockGetPutState(mockInstance)
+	mockBlockHeader(mockInstance)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockInstance.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+	sdk.Instance = mockInstance
+
+	if got := new(BeaconContract).InitContract(); got.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", got)
+	}
+	h := sha256.New()
+	h.Write(secretOf(1))
+	h.Write(secretOf(2))
+	h.Write(blockHash)
+	seed := []byte(hex.EncodeToString(h.Sum(nil)))
+	round := []byte("1")
+	commit := func(secret []byte, address string) map[string][]byte {
+		return map[string][]byte{paramRound: round, paramCommitment: []byte(Commitment(secret, address))}
+	}
+	reveal := func(secret []byte) map[string][]byte {
+		return map[string][]byte{paramRound: round, paramSecret: []byte(hex.EncodeToString(secret))}
+	}
+
+	tests := []step{
+		{name: "register alice", height: 1, sender: admin, method: "register",
+			args: map[string][]byte{paramAddress: []byte(alice)}, want: sdk.Success([]byte("ok"))},
+		{name: "register bob", height: 1, sender: admin, method: "register",
+			args: map[string][]byte{paramAddress: []byte(bob)}, want: sdk.Success([]byte("ok"))},
+		{name: "start round", height: 1, sender: alice, method: "startRound", want: sdk.Success(round)},
+		{name: "commit alice", height: 1, sender: alice, method: "commit", args: commit(secretOf(1), alice),
+			want: sdk.Success([]byte("ok"))},
+		{name: "commit bob", height: 1, sender: bob, method: "commit", args: commit(secretOf(2), bob),
+			want: sdk.Success([]byte("ok"))},
+		{name: "commit twice", height: 1, sender: alice, method: "commit", args: commit(secretOf(3), alice),
+			want: sdk.Error("alice already committed in round 1")},
+		{name: "reveal too early", height: 1, sender: alice, method: "reveal", args: reveal(secretOf(1)),
+			want: sdk.Error("reveal phase of round 1 is from block 12 to 21")},
+		{name: "register admin", height: 12, sender: admin, method: "register",
+			args: map[string][]byte{paramAddress: []byte(admin)}, want: sdk.Success([]byte("ok"))},
+		{name: "commit too late", height: 12, sender: admin, method: "commit", args: commit(secretOf(3), admin),
+			want: sdk.Error("commit phase of round 1 ended at block 11")},
+		{name: "reveal not committed", height: 12, sender: alice, method: "reveal", args: reveal(secretOf(2)),
+			want: sdk.Error("secret does not match the commitment")},
+		{name: "reveal alice", height: 12, sender: alice, method: "reveal", args: reveal(secretOf(1)),
+			want: sdk.Success([]byte("ok"))},
+		{name: "reveal bob", height: 12, sender: bob, method: "reveal", args: reveal(secretOf(2)),
+			want: sdk.Success([]byte("ok"))},
+		{name: "not finalized", height: 12, sender: bob, method: "getRandomness",
+			args: map[string][]byte{paramRound: round}, want: sdk.Error("round 1 is not finalized")},
+		{name: "finalize too early", height: 12, sender: bob, method: "finalize",
+			args: map[string][]byte{paramRound: round}, want: sdk.Error("reveal phase of round 1 ends at block 21")},
+		{name: "finalize", height: 22, sender: bob, method: "finalize",
+			args: map[string][]byte{paramRound: round}, want: sdk.Success(seed)},
+		{name: "randomness", height: 22, sender: bob, method: "getRandomness",
+			args: map[string][]byte{paramRound: round}, want: sdk.Success(seed)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, args, height = tt.sender, tt.args, tt.height
+			if got := new(BeaconContract).InvokeContract(tt.method); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s() = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBeaconContract_withheld(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, height := admin, 1
+	args := map[string][]byte{paramCommitBlocks: []byte("2"), paramRevealBlocks: []byte("2")}
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return args })
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().Origin().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return height, nil })
+	mockGetPutState(mockInstance)
+	mockBlockHeader(mockInstance)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	sdk.Instance = mockInstance
+
+	if got := new(BeaconContract).InitContract(); got.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", got)
+	}
+	round := []byte("1")
+	registerBob := map[string][]byte{paramAddress: []byte(bob)}
+	// alice reveals, bob withholds after seeing it, the round is finalized when the next round starts.
+	// the reveal of alice is enough for minReveals, but no seed is made without the one of bob
+	tests := []step{
+		{name: "register alice", height: 1, sender: admin, method: "register",
+			args: map[string][]byte{paramAddress: []byte(alice)}, want: sdk.Success([]byte("ok"))},
+		{name: "register bob", height: 1, sender: admin, method: "register", args: registerBob,
+			want: sdk.Success([]byte("ok"))},
+		{name: "start round", height: 1, sender: alice, method: "startRound", want: sdk.Success(round)},
+		{name: "commit alice", height: 1, sender: alice, method: "commit",
+			args: map[string][]byte{paramRound: round, paramCommitment: []byte(Commitment(secretOf(1), alice))},
+			want: sdk.Success([]byte("ok"))},
+		{name: "commit bob", height: 1, sender: bob, method: "commit",
+			args: map[string][]byte{paramRound: round, paramCommitment: []byte(Commitment(secretOf(2), bob))},
+			want: sdk.Success([]byte("ok"))},
+		{name: "reveal alice", height: 4, sender: alice, method: "reveal",
+			args: map[string][]byte{paramRound: round, paramSecret: []byte(hex.EncodeToString(secretOf(1)))},
+			want: sdk.Success([]byte("ok"))},
+		{name: "next round", height: 6, sender: alice, method: "startRound", want: sdk.Success([]byte("2"))},
+		{name: "failed", height: 6, sender: alice, method: "getRandomness", args: map[string][]byte{paramRound: round},
+			want: sdk.Error("round 1 failed, 1 reveals with 1 withheld")},
+		{name: "failed round", height: 6, sender: alice, method: "getRound",
+			args: map[string][]byte{paramRound: round},
+			want: sdk.Success(mustJSON(t, &Round{Round: 1, StartHeight: 1, CommitDeadline: 3, RevealDeadline: 5,
+				Revealed: 1, Withheld: []string{bob}, Finalized: true, Failed: true}))},
+		{name: "banned", height: 6, sender: alice, method: "getParticipant", args: registerBob,
+			want: sdk.Success(mustJSON(t, &Participant{Address: bob, Missed: 1, Banned: true}))},
+		{name: "register banned", height: 6, sender: admin, method: "register", args: registerBob,
+			want: sdk.Error("participant bob is banned for withholding 1 reveals")},
+		{name: "pardon not admin", height: 6, sender: alice, method: "pardon", args: registerBob,
+			want: sdk.Error("only admin can pardon participants")},
+		{name: "pardon", height: 6, sender: admin, method: "pardon", args: registerBob,
+			want: sdk.Success([]byte("ok"))},
+		{name: "register pardoned", height: 6, sender: admin, method: "register", args: registerBob,
+			want: sdk.Success([]byte("ok"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, args, height = tt.sender, tt.args, tt.height
+			if got := new(BeaconContract).InvokeContract(tt.method); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s() = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+module beacon
+
+go 1.17
+
+require (
+	chainmaker.org/chainmaker/contract-sdk-go/v2 v2.3.3
+	chainmaker.org/chainmaker/pb-go/v2 v2.3.0
+	github.com/golang/mock v1.6.0
+)
+
+require (
+	chainmaker.org/chainmaker/common/v2 v2.3.0 // indirect
+	chainmaker.org/chainmaker/protocol/v2 v2.3.0 // indirect
+	github.com/dgryski/go-metro v0.0.0-20200812162917-85c65e2d0165 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/google/uuid v1.1.2 // indirect
+	github.com/linvon/cuckoo-filter v0.4.0 // indirect
+	github.com/tidwall/gjson v1.10.2 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
+	github.com/tidwall/tinylru v1.1.0 // indirect
+	github.com/tjfoc/gmsm v1.4.1 // indirect
+	go.uber.org/atomic v1.7.0 // indirect
+	go.uber.org/multierr v1.6.0 // indirect
+	go.uber.org/zap v1.18.1 // indirect
+	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519 // indirect
+	golang.org/x/net v0.0.0-20211015210444-4f30a5c0130f // indirect
+	golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a // indirect
+	golang.org/x/text v0.3.7 // indirect
+	google.golang.org/genproto v0.0.0-20210828152312-66f60bf46e71 // indirect
+	google.golang.org/grpc v1.41.1 // indirect
+	google.golang.org/protobuf v1.27.1 // indirect
+)
@@ -0,0 +1,585 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Verifiable credential registry contract. The credentials are kept off chain by the holders, the registry
+records the hashes of the credentials issued by the registered issuers, so anyone can verify that a
+credential was issued by the issuer and is neither revoked nor expired.
+
+The admin registers the issuers, when an identity contract is configured the issuer should have at least
+minIssuerLevel in it. An issuer issues a credential by its hash, the schema id, the expiry in tx timestamp
+seconds and the salted hashes of the claims, and revokes its credentials with a reason, the revoked hashes
+of an issuer form its revocation list.
+
+Selective disclosure: a claim hash is hex(sha256(json(["salt", "name", "value"]))), the holder discloses
+the salt, name and value of a single claim and VerifyClaim checks it against the claim hashes of the
+credential without revealing the other claims.
+
+Every address may publish a DID document with the id did:cm:<address>.
+*/
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/contract-utils/address"
+)
+
+const (
+	//db key
+	configKey      = "config"
+	issuerKey      = "issuer"
+	credentialKey  = "credential"
+	holderKey      = "holder"
+	revocationsKey = "revocations"
+	// revocationCountField field of the count of the revocation list of an issuer
+	revocationCountField = "n"
+
+	// maxClaims bounds the claim hashes of a credential
+	maxClaims = 100
+	// maxCredentialsPerHolder bounds the credentials checked by HasCredential, the revoked and expired
+	// ones are pruned from a full list when issuing
+	maxCredentialsPerHolder = 200
+	// maxRevocationPage bounds the revocations returned by one query
+	maxRevocationPage = 1000
+)
+
+// credential statuses returned by VerifyCredential
+const (
+	StatusValid         = "valid"
+	StatusNotFound      = "notFound"
+	StatusRevoked       = "revoked"
+	StatusExpired       = "expired"
+	StatusIssuerRemoved = "issuerRemoved"
+)
+
+// CredentialContract verifiable credential registry contract
+type CredentialContract struct {
+}
+
+// Config registry config, Identity is the optional standard-identity contract checked for the issuers
+type Config struct {
+	Admin          string `json:"admin"`
+	Identity       string `json:"identity,omitempty"`
+	MinIssuerLevel int    `json:"minIssuerLevel,omitempty"`
+}
+
+// Issuer registered issuer, Active is false after it is removed
+type Issuer struct {
+	Address          string `json:"address"`
+	Name             string `json:"name"`
+	Active           bool   `json:"active"`
+	RegisteredHeight uint64 `json:"registeredHeight"`
+}
+
+// Credential record of an issued credential, ExpireAt is in tx timestamp seconds and 0 never expires
+type Credential struct {
+	Hash         string   `json:"hash"`
+	Issuer       string   `json:"issuer"`
+	Holder       string   `json:"holder"`
+	SchemaId     string   `json:"schemaId"`
+	IssuedHeight uint64   `json:"issuedHeight"`
+	ExpireAt     uint64   `json:"expireAt"`
+	ClaimHashes  []string `json:"claimHashes,omitempty"`
+	Revoked      bool     `json:"revoked"`
+	RevokeReason string   `json:"revokeReason,omitempty"`
+}
+
+// Verification status of a credential at the current tx timestamp
+type Verification struct {
+	Valid      bool        `json:"valid"`
+	Status     string      `json:"status"`
+	Credential *Credential `json:"credential,omitempty"`
+}
+
+// RegisterIssuer admin注册发证方，配置了身份合约时发证方等级需不低于minIssuerLevel
+// @param issuer
+// @param name
+// @return error
+func (c *CredentialContract) RegisterIssuer(issuer, name string) error {
+	config, err := c.requireAdmin()
+	if err != nil {
+		return err
+	}
+	if !address.IsValidAddress(issuer) || address.IsZeroAddress(issuer) {
+		return errors.New("VC: invalid issuer")
+	}
+	if len(config.Identity) > 0 {
+		resp := sdk.Instance.CallContract(config.Identity, "LevelOf", map[string][]byte{
+			"address": []byte(issuer),
+		})
+		if resp.Status != sdk.OK {
+			return fmt.Errorf("VC: get level of issuer failed, %s", resp.Message)
+		}
+		level, err1 := strconv.Atoi(string(resp.Payload))
+		if err1 != nil || level < config.MinIssuerLevel {
+			return fmt.Errorf("VC: issuer level should be at least %d", config.MinIssuerLevel)
+		}
+	}
+	height, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return fmt.Errorf("get block height failed, err:%s", err)
+	}
+	if err = c.setIssuer(&Issuer{Address: issuer, Name: name, Active: true,
+		RegisteredHeight: uint64(height)}); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventIssuerRegistered, issuer, name)
+}
+
+// RemoveIssuer admin移除发证方，其签发的凭证不再有效
+// @param issuer
+// @return error
+func (c *CredentialContract) RemoveIssuer(issuer string) error {
+	if _, err := c.requireAdmin(); err != nil {
+		return err
+	}
+	record, err := c.GetIssuer(issuer)
+	if err != nil {
+		return err
+	}
+	if !record.Active {
+		return fmt.Errorf("VC: issuer %s is already removed", issuer)
+	}
+	record.Active = false
+	if err = c.setIssuer(record); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventIssuerRemoved, issuer)
+}
+
+// IssueCredential 发证方登记凭证哈希
+// @param credential Hash、Holder、SchemaId、ExpireAt、ClaimHashes，其余字段由合约设置
+// @return error
+func (c *CredentialContract) IssueCredential(credential *Credential) error {
+	issuer, err := c.requireIssuer()
+	if err != nil {
+		return err
+	}
+	if !isHash(credential.Hash) {
+		return errors.New("VC: credential hash should be a hex sha256")
+	}
+	if !address.IsValidAddress(credential.Holder) {
+		return errors.New("VC: invalid holder")
+	}
+	if len(credential.SchemaId) == 0 {
+		return errors.New("VC: schemaId should not be empty")
+	}
+	if len(credential.ClaimHashes) > maxClaims {
+		return fmt.Errorf("VC: credential should have at most %d claims", maxClaims)
+	}
+	for _, claimHash := range credential.ClaimHashes {
+		if !isHash(claimHash) {
+			return errors.New("VC: claim hash should be a hex sha256")
+		}
+	}
+	existing, err := c.getCredential(credential.Hash)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("VC: credential %s already exists", credential.Hash)
+	}
+	credentials, err := c.GetCredentialsOf(credential.Holder)
+	if err != nil {
+		return err
+	}
+	if len(credentials) >= maxCredentialsPerHolder {
+		if credentials, err = c.pruneCredentials(credentials); err != nil {
+			return err
+		}
+	}
+	if len(credentials) >= maxCredentialsPerHolder {
+		return fmt.Errorf("VC: holder has %d credentials", maxCredentialsPerHolder)
+	}
+	height, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return fmt.Errorf("get block height failed, err:%s", err)
+	}
+
+	credential.Issuer = issuer
+	credential.IssuedHeight = uint64(height)
+	credential.Revoked = false
+	credential.RevokeReason = ""
+	if err = c.setCredential(credential); err != nil {
+		return err
+	}
+	if err = c.setCredentialsOf(credential.Holder, append(credentials, credential.Hash)); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventCredentialIssued, credential.Hash, issuer, credential.Holder,
+		credential.SchemaId, credential.ExpireAt)
+}
+
+// RevokeCredential 发证方撤销凭证，凭证哈希加入发证方的撤销列表
+// @param hash
+// @param reason
+// @return error
+func (c *CredentialContract) RevokeCredential(hash, reason string) error {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("get sender failed, err:%s", err)
+	}
+	credential, err := c.GetCredential(hash)
+	if err != nil {
+		return err
+	}
+	if credential.Issuer != sender {
+		return errors.New("VC: only the issuer can revoke the credential")
+	}
+	if credential.Revoked {
+		return fmt.Errorf("VC: credential %s is already revoked", hash)
+	}
+	credential.Revoked = true
+	credential.RevokeReason = reason
+	if err = c.setCredential(credential); err != nil {
+		return err
+	}
+	count, err := c.revocationCount(sender)
+	if err != nil {
+		return err
+	}
+	if err = sdk.Instance.PutState(revocationsKey+sender, strconv.FormatUint(count, 10), hash); err != nil {
+		return err
+	}
+	if err = sdk.Instance.PutState(revocationsKey+sender, revocationCountField,
+		strconv.FormatUint(count+1, 10)); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventCredentialRevoked, hash, sender, reason)
+}
+
+// VerifyCredential 验证凭证是否存在、未撤销、未过期且发证方有效
+// @param hash
+// @return *Verification
+// @return error
+func (c *CredentialContract) VerifyCredential(hash string) (*Verification, error) {
+	credential, err := c.getCredential(hash)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return &Verification{Status: StatusNotFound}, nil
+	}
+	status, err := c.status(credential)
+	if err != nil {
+		return nil, err
+	}
+	return &Verification{Valid: status == StatusValid, Status: status, Credential: credential}, nil
+}
+
+// VerifyClaim 选择性披露验证，凭证有效且包含hex(sha256(json([salt, name, value])))
+// @param hash 凭证哈希
+// @param salt
+// @param name
+// @param value
+// @return bool
+// @return error
+func (c *CredentialContract) VerifyClaim(hash, salt, name, value string) (bool, error) {
+	verification, err := c.VerifyCredential(hash)
+	if err != nil || !verification.Valid {
+		return false, err
+	}
+	claimHash, err := ClaimHash(salt, name, value)
+	if err != nil {
+		return false, err
+	}
+	for _, h := range verification.Credential.ClaimHashes {
+		if h == claimHash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HasCredential 持有人是否有发证方签发的指定schema的有效凭证，schemaId为空时不限schema
+// @param holder
+// @param issuer
+// @param schemaId
+// @return bool
+// @return error
+func (c *CredentialContract) HasCredential(holder, issuer, schemaId string) (bool, error) {
+	hashes, err := c.GetCredentialsOf(holder)
+	if err != nil {
+		return false, err
+	}
+	for _, hash := range hashes {
+		credential, err1 := c.GetCredential(hash)
+		if err1 != nil {
+			return false, err1
+		}
+		if credential.Issuer != issuer || (len(schemaId) > 0 && credential.SchemaId != schemaId) {
+			continue
+		}
+		status, err1 := c.status(credential)
+		if err1 != nil {
+			return false, err1
+		}
+		if status == StatusValid {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetCredential 查询凭证
+// @param hash
+// @return *Credential
+// @return error
+func (c *CredentialContract) GetCredential(hash string) (*Credential, error) {
+	credential, err := c.getCredential(hash)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return nil, fmt.Errorf("VC: credential %s not found", hash)
+	}
+	return credential, nil
+}
+
+// GetCredentialsOf 查询持有人的凭证哈希
+// @param holder
+// @return []string
+// @return error
+func (c *CredentialContract) GetCredentialsOf(holder string) ([]string, error) {
+	data, err := sdk.Instance.GetStateByte(holderKey, holder)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0)
+	if len(data) == 0 {
+		return hashes, nil
+	}
+	if err = json.Unmarshal(data, &hashes); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// GetRevocationList 分页查询发证方撤销的凭证哈希，按撤销顺序
+// @param issuer
+// @param offset
+// @param limit 不超过1000
+// @return []string
+// @return error
+func (c *CredentialContract) GetRevocationList(issuer string, offset, limit uint64) ([]string, error) {
+	if limit == 0 || limit > maxRevocationPage {
+		limit = maxRevocationPage
+	}
+	count, err := c.revocationCount(issuer)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0)
+	for i := offset; i < count && i < offset+limit; i++ {
+		hash, err1 := sdk.Instance.GetState(revocationsKey+issuer, strconv.FormatUint(i, 10))
+		if err1 != nil {
+			return nil, err1
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+// GetIssuer 查询发证方
+// @param issuer
+// @return *Issuer
+// @return error
+func (c *CredentialContract) GetIssuer(issuer string) (*Issuer, error) {
+	data, err := sdk.Instance.GetStateByte(issuerKey, issuer)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("VC: issuer %s not found", issuer)
+	}
+	var record Issuer
+	if err = json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GetConfig 获取配置
+// @return *Config
+// @return error
+func (c *CredentialContract) GetConfig() (*Config, error) {
+	data, err := sdk.Instance.GetStateByte(configKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("VC: config not found")
+	}
+	var config Config
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SetConfig 设置配置
+// @param config
+// @return error
+func (c *CredentialContract) SetConfig(config *Config) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(configKey, "", data)
+}
+
+// ClaimHash 计算加盐的声明哈希 hex(sha256(json([salt, name, value])))
+// @param salt
+// @param name
+// @param value
+// @return string
+// @return error
+func ClaimHash(salt, name, value string) (string, error) {
+	data, err := json.Marshal([]string{salt, name, value})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// status the status of the credential at the current tx timestamp
+func (c *CredentialContract) status(credential *Credential) (string, error) {
+	if credential.Revoked {
+		return StatusRevoked, nil
+	}
+	if credential.ExpireAt > 0 {
+		timestamp, err := sdk.Instance.GetTxTimeStamp()
+		if err != nil {
+			return "", fmt.Errorf("get tx timestamp failed, err:%s", err)
+		}
+		now, err := strconv.ParseUint(timestamp, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid tx timestamp %s", timestamp)
+		}
+		if now >= credential.ExpireAt {
+			return StatusExpired, nil
+		}
+	}
+	issuer, err := c.GetIssuer(credential.Issuer)
+	if err != nil {
+		return "", err
+	}
+	if !issuer.Active {
+		return StatusIssuerRemoved, nil
+	}
+	return StatusValid, nil
+}
+
+// getCredential the credential of the hash, nil if not found
+func (c *CredentialContract) getCredential(hash string) (*Credential, error) {
+	data, err := sdk.Instance.GetStateByte(credentialKey, hash)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	var credential Credential
+	if err = json.Unmarshal(data, &credential); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// pruneCredentials drop the revoked and expired credentials from the hashes of a holder, they never
+// become valid again
+func (c *CredentialContract) pruneCredentials(hashes []string) ([]string, error) {
+	kept := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		credential, err := c.GetCredential(hash)
+		if err != nil {
+			return nil, err
+		}
+		status, err := c.status(credential)
+		if err != nil {
+			return nil, err
+		}
+		if status != StatusRevoked && status != StatusExpired {
+			kept = append(kept, hash)
+		}
+	}
+	return kept, nil
+}
+
+func (c *CredentialContract) requireAdmin() (*Config, error) {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return nil, fmt.Errorf("get sender failed, err:%s", err)
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if sender != config.Admin {
+		return nil, errors.New("VC: only the admin can manage the issuers")
+	}
+	return config, nil
+}
+
+// requireIssuer the sender should be an active issuer
+func (c *CredentialContract) requireIssuer() (string, error) {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return "", fmt.Errorf("get sender failed, err:%s", err)
+	}
+	issuer, err := c.GetIssuer(sender)
+	if err != nil || !issuer.Active {
+		return "", fmt.Errorf("VC: %s is not an active issuer", sender)
+	}
+	return sender, nil
+}
+
+func (c *CredentialContract) revocationCount(issuer string) (uint64, error) {
+	countStr, err := sdk.Instance.GetState(revocationsKey+issuer, revocationCountField)
+	if err != nil || len(countStr) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(countStr, 10, 64)
+}
+
+func (c *CredentialContract) setIssuer(issuer *Issuer) error {
+	data, err := json.Marshal(issuer)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(issuerKey, issuer.Address, data)
+}
+
+func (c *CredentialContract) setCredential(credential *Credential) error {
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(credentialKey, credential.Hash, data)
+}
+
+func (c *CredentialContract) setCredentialsOf(holder string, hashes []string) error {
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(holderKey, holder, data)
+}
+
+// isHash s is a lower case hex sha256
+func isHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	b, err := hex.DecodeString(s)
+	return err == nil && hex.EncodeToString(b) == s
+}
@@ -0,0 +1,330 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	admin    = "a04f7895de24f61807a729be230f03da8c0eef42"
+	issuer   = "b04f7895de24f61807a729be230f03da8c0eef42"
+	holder   = "c04f7895de24f61807a729be230f03da8c0eef42"
+	identity = "identity"
+)
+
+// mockGetPutState keeps the state of a test in the returned map by key#field
+func mockGetPutState(mockInstance *sdk.MockSDKInterface) map[string][]byte {
+	stateMap := make(map[string][]byte)
+	mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) (string, error) {
+			return string(stateMap[key+"#"+field]), nil
+		})
+	mockInstance.EXPECT().PutState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field, value string) error {
+			stateMap[key+"#"+field] = []byte(value)
+			return nil
+		})
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) ([]byte, error) {
+			return stateMap[key+"#"+field], nil
+		})
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string, value []byte) error {
+			stateMap[key+"#"+field] = value
+			return nil
+		})
+	return stateMap
+}
+
+// mockIdentity the identity contract answers LevelOf with the levels
+func mockIdentity(mockInstance *sdk.MockSDKInterface, levels map[string]string) {
+	mockInstance.EXPECT().CallContract(identity, "LevelOf", gomock.Any()).AnyTimes().DoAndReturn(
+		func(contractName, method string, callArgs map[string][]byte) protogo.Response {
+			level, ok := levels[string(callArgs["address"])]
+			if !ok {
+				return sdk.Error("not found")
+			}
+			return sdk.Success([]byte(level))
+		})
+}
+
+func hashOf(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// step one call of a test by sender at the tx timestamp, do returns an error when the result is not the
+// wanted one
+type step struct {
+	name      string
+	timestamp int64
+	sender    string
+	do        func(c *CredentialContract) error
+	wantErr   bool
+}
+
+func issue(credential *Credential) func(c *CredentialContract) error {
+	return func(c *CredentialContract) error { return c.IssueCredential(credential) }
+}
+
+func wantStatus(hash, status string) func(c *CredentialContract) error {
+	return func(c *CredentialContract) error {
+		v, err := c.VerifyCredential(hash)
+		if err == nil && (v.Valid || v.Status != status) {
+			err = fmt.Errorf("VerifyCredential() = %+v, want %s", v, status)
+		}
+		return err
+	}
+}
+
+func TestCredentialContract_InvokeContract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	var args map[string][]byte
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return args })
+	mockInstance.EXPECT().Sender().AnyTimes().Return(admin, nil)
+	mockInstance.EXPECT().Origin().AnyTimes().Return(admin, nil)
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().Return(10, nil)
+	mockInstance.EXPECT().GetTxTimeStamp().AnyTimes().Return("1000", nil)
+	mockGetPutState(mockInstance)
+	sdk.Instance = mockInstance
+
+	claimHash, _ := ClaimHash("s1", "age", "30")
+	if claimHash != hashOf(`["s1","age","30"]`) {
+		t.Errorf("ClaimHash() = %s", claimHash)
+	}
+	tests := []struct {
+		name   string
+		method string
+		args   map[string][]byte
+		want   protogo.Response
+	}{
+		{name: "register", method: "RegisterIssuer", want: sdk.Error("VC: require parameter:'issuer'")},
+		{name: "issue", method: "IssueCredential", want: sdk.Error("VC: require parameter:'hash'")},
+		{name: "verify not found", method: "VerifyCredential", args: map[string][]byte{pHash: []byte("h")},
+			want: sdk.Success([]byte(`{"valid":false,"status":"notFound"}`))},
+		{name: "has credential", method: "HasCredential",
+			args: map[string][]byte{pHolder: []byte(holder), pIssuer: []byte(issuer)},
+			want: sdk.Success([]byte("false"))},
+		{name: "claim hash", method: "ClaimHash",
+			args: map[string][]byte{pSalt: []byte("s1"), pName: []byte("age"), pValue: []byte("30")},
+			want: sdk.Success([]byte(claimHash))},
+		{name: "did document", method: "SetDIDDocument", args: map[string][]byte{pDocument: []byte("[]")},
+			want: sdk.Error("VC: DID document should be a json object")},
+		{name: "invalid", method: "invalid", want: sdk.Error("Invalid method")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args = tt.args
+			c := &CredentialContract{}
+			if got := c.InvokeContract(tt.method); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvokeContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialContract_lifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, timestamp := admin, int64(1000)
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(
+		map[string][]byte{pIdentity: []byte(identity), pMinIssuerLevel: []byte("4")})
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().Origin().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().Return(10, nil)
+	mockInstance.EXPECT().GetTxTimeStamp().AnyTimes().DoAndReturn(func() (string, error) {
+		return strconv.FormatInt(timestamp, 10), nil
+	})
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockIdentity(mockInstance, map[string]string{issuer: "4", holder: "3"})
+	sdk.Instance = mockInstance
+
+	c := &CredentialContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	claimHash, _ := ClaimHash("s1", "degree", "master")
+	degree := &Credential{Hash: hashOf("degree"), Holder: holder, SchemaId: "degree/v1",
+		ClaimHashes: []string{claimHash}}
+	card := &Credential{Hash: hashOf("card"), Holder: holder, SchemaId: "card/v1", ExpireAt: 2000}
+	verifyClaim := func(salt string, want bool) func(c *CredentialContract) error {
+		return func(c *CredentialContract) error {
+			ok, err := c.VerifyClaim(degree.Hash, salt, "degree", "master")
+			if err == nil && ok != want {
+				err = fmt.Errorf("VerifyClaim() = %v, want %v", ok, want)
+			}
+			return err
+		}
+	}
+	hasCredential := func(schemaId string, want bool) func(c *CredentialContract) error {
+		return func(c *CredentialContract) error {
+			ok, err := c.HasCredential(holder, issuer, schemaId)
+			if err == nil && ok != want {
+				err = fmt.Errorf("HasCredential() = %v, want %v", ok, want)
+			}
+			return err
+		}
+	}
+	steps := []step{
+		{name: "register low level", timestamp: 1000, sender: admin, wantErr: true,
+			do: func(c *CredentialContract) error { return c.RegisterIssuer(holder, "person") }},
+		{name: "register", timestamp: 1000, sender: admin,
+			do: func(c *CredentialContract) error { return c.RegisterIssuer(issuer, "university") }},
+		{name: "issue by others", timestamp: 1000, sender: admin, do: issue(degree), wantErr: true},
+		{name: "issue degree", timestamp: 1000, sender: issuer, do: issue(degree)},
+		{name: "issue twice", timestamp: 1000, sender: issuer, do: issue(degree), wantErr: true},
+		{name: "issue card", timestamp: 1000, sender: issuer, do: issue(card)},
+		{name: "has credential", timestamp: 1000, sender: issuer, do: hasCredential("degree/v1", true)},
+		{name: "verify claim", timestamp: 1000, sender: issuer, do: verifyClaim("s1", true)},
+		{name: "verify claim with wrong salt", timestamp: 1000, sender: issuer, do: verifyClaim("s2", false)},
+		// expiry and revocation
+		{name: "expired", timestamp: 2000, sender: issuer, do: wantStatus(card.Hash, StatusExpired)},
+		{name: "revoke by others", timestamp: 2000, sender: admin, wantErr: true,
+			do: func(c *CredentialContract) error { return c.RevokeCredential(degree.Hash, "fraud") }},
+		{name: "revoke", timestamp: 2000, sender: issuer,
+			do: func(c *CredentialContract) error { return c.RevokeCredential(degree.Hash, "fraud") }},
+		{name: "revoked", timestamp: 2000, sender: issuer, do: func(c *CredentialContract) error {
+			v, err := c.VerifyCredential(degree.Hash)
+			if err == nil && (v.Valid || v.Status != StatusRevoked || v.Credential.RevokeReason != "fraud") {
+				err = fmt.Errorf("VerifyCredential() = %+v", v)
+			}
+			return err
+		}},
+		{name: "has no credential", timestamp: 2000, sender: issuer, do: hasCredential("", false)},
+		{name: "revocation list", timestamp: 2000, sender: issuer, do: func(c *CredentialContract) error {
+			list, err := c.GetRevocationList(issuer, 0, 0)
+			if err == nil && !reflect.DeepEqual(list, []string{degree.Hash}) {
+				err = fmt.Errorf("GetRevocationList() = %v", list)
+			}
+			return err
+		}},
+		// the credentials of a removed issuer are not valid
+		{name: "remove issuer", timestamp: 1000, sender: admin,
+			do: func(c *CredentialContract) error { return c.RemoveIssuer(issuer) }},
+		{name: "issuer removed", timestamp: 1000, sender: admin, do: wantStatus(card.Hash, StatusIssuerRemoved)},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender, timestamp = s.sender, s.timestamp
+			if err := s.do(c); (err != nil) != s.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, s.wantErr)
+			}
+		})
+	}
+}
+
+func TestCredentialContract_DIDDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	mockInstance.EXPECT().Sender().AnyTimes().Return(holder, nil)
+	mockInstance.EXPECT().Origin().AnyTimes().Return(holder, nil)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	sdk.Instance = mockInstance
+
+	c := &CredentialContract{}
+	if err := c.SetDIDDocument([]byte(`{"id":"did:cm:other","service":[{"type":"hub"}]}`)); err != nil {
+		t.Fatalf("SetDIDDocument() = %v", err)
+	}
+	tests := []struct {
+		name    string
+		account string
+		want    string
+		wantErr bool
+	}{
+		{name: "id of the holder", account: holder, want: `{"id":"did:cm:` + holder + `","service":[{"type":"hub"}]}`},
+		{name: "not set", account: issuer, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := c.GetDIDDocument(tt.account)
+			if (err != nil) != tt.wantErr || string(document) != tt.want {
+				t.Errorf("GetDIDDocument() = %s, %v, want %s", document, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialContract_pruneHolder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, timestamp := admin, int64(1000)
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(map[string][]byte{})
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().Origin().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().Return(10, nil)
+	mockInstance.EXPECT().GetTxTimeStamp().AnyTimes().DoAndReturn(func() (string, error) {
+		return strconv.FormatInt(timestamp, 10), nil
+	})
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	sdk.Instance = mockInstance
+
+	c := &CredentialContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	if err := c.RegisterIssuer(issuer, "bank"); err != nil {
+		t.Fatalf("RegisterIssuer() = %v", err)
+	}
+	sender = issuer
+	for i := 0; i < maxCredentialsPerHolder; i++ {
+		credential := &Credential{Hash: hashOf("card" + strconv.Itoa(i)), Holder: holder, SchemaId: "card/v1",
+			ExpireAt: 2000}
+		if i == 0 {
+			credential.ExpireAt = 0
+		}
+		if err := c.IssueCredential(credential); err != nil {
+			t.Fatalf("IssueCredential() = %v", err)
+		}
+	}
+
+	credential := &Credential{Hash: hashOf("card"), Holder: holder, SchemaId: "card/v1"}
+	steps := []step{
+		{name: "full holder", timestamp: 1000, sender: issuer, do: issue(credential), wantErr: true},
+		// the revoked and expired ones are pruned
+		{name: "revoke", timestamp: 1000, sender: issuer,
+			do: func(c *CredentialContract) error { return c.RevokeCredential(hashOf("card0"), "lost") }},
+		{name: "issue after expiry", timestamp: 2000, sender: issuer, do: issue(credential)},
+		{name: "pruned", timestamp: 2000, sender: issuer, do: func(c *CredentialContract) error {
+			hashes, err := c.GetCredentialsOf(holder)
+			if err == nil && !reflect.DeepEqual(hashes, []string{credential.Hash}) {
+				err = fmt.Errorf("GetCredentialsOf() = %v", hashes)
+			}
+			return err
+		}},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender, timestamp = s.sender, s.timestamp
+			if err := s.do(c); (err != nil) != s.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, s.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	didKey    = "did"
+	didPrefix = "did:cm:"
+	// maxDIDDocumentSize bounds the size of a DID document
+	maxDIDDocumentSize = 16 * 1024
+)
+
+// DIDOf the DID of an address
+func DIDOf(account string) string {
+	return didPrefix + account
+}
+
+// SetDIDDocument 发送者设置自己的DID文档，文档须为json对象，id字段设置为did:cm:<address>
+// @param document
+// @return error
+func (c *CredentialContract) SetDIDDocument(document []byte) error {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("get sender failed, err:%s", err)
+	}
+	if len(document) > maxDIDDocumentSize {
+		return fmt.Errorf("VC: DID document should not be larger than %d bytes", maxDIDDocumentSize)
+	}
+	var doc map[string]interface{}
+	if err = json.Unmarshal(document, &doc); err != nil || doc == nil {
+		return errors.New("VC: DID document should be a json object")
+	}
+	did := DIDOf(sender)
+	doc["id"] = did
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err = sdk.Instance.PutStateByte(didKey, sender, data); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventDIDDocumentUpdated, sender, did)
+}
+
+// GetDIDDocument 查询地址的DID文档
+// @param account
+// @return []byte
+// @return error
+func (c *CredentialContract) GetDIDDocument(account string) ([]byte, error) {
+	data, err := sdk.Instance.GetStateByte(didKey, account)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("VC: DID document of %s not found", account)
+	}
+	return data, nil
+}
@@ -0,0 +1,59 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	eventIssuerRegistered   = "IssuerRegistered"
+	eventIssuerRemoved      = "IssuerRemoved"
+	eventCredentialIssued   = "CredentialIssued"
+	eventCredentialRevoked  = "CredentialRevoked"
+	eventDIDDocumentUpdated = "DIDDocumentUpdated"
+)
+
+// credentialEventSchemas schemas of the events emitted by credential, saved on chain when the contract is
+// installed
+var credentialEventSchemas = []*sdk.EventSchema{
+	sdk.NewEventSchema(eventIssuerRegistered,
+		sdk.NewEventField("issuer", sdk.EventFieldAddress, true),
+		sdk.NewEventField("name", sdk.EventFieldString, false),
+	),
+	sdk.NewEventSchema(eventIssuerRemoved,
+		sdk.NewEventField("issuer", sdk.EventFieldAddress, true),
+	),
+	sdk.NewEventSchema(eventCredentialIssued,
+		sdk.NewEventField("hash", sdk.EventFieldString, true),
+		sdk.NewEventField("issuer", sdk.EventFieldAddress, true),
+		sdk.NewEventField("holder", sdk.EventFieldAddress, true),
+		sdk.NewEventField("schemaId", sdk.EventFieldString, false),
+		sdk.NewEventField("expireAt", sdk.EventFieldUint, false),
+	),
+	sdk.NewEventSchema(eventCredentialRevoked,
+		sdk.NewEventField("hash", sdk.EventFieldString, true),
+		sdk.NewEventField("issuer", sdk.EventFieldAddress, true),
+		sdk.NewEventField("reason", sdk.EventFieldString, false),
+	),
+	sdk.NewEventSchema(eventDIDDocumentUpdated,
+		sdk.NewEventField("account", sdk.EventFieldAddress, true),
+		sdk.NewEventField("did", sdk.EventFieldString, false),
+	),
+}
+
+func init() {
+	if err := sdk.RegisterEventSchemas(credentialEventSchemas...); err != nil {
+		panic(err)
+	}
+}
+
+// EventSchemas returns the event schemas of credential
+func (c *CredentialContract) EventSchemas() []*sdk.EventSchema {
+	return credentialEventSchemas
+}
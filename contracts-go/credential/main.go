@@ -0,0 +1,308 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	pIdentity       = "identity"
+	pMinIssuerLevel = "minIssuerLevel"
+	pIssuer         = "issuer"
+	pName           = "name"
+	pHolder         = "holder"
+	pHash           = "hash"
+	pSchemaId       = "schemaId"
+	pExpireAt       = "expireAt"
+	pClaimHashes    = "claimHashes"
+	pReason         = "reason"
+	pSalt           = "salt"
+	pValue          = "value"
+	pOffset         = "offset"
+	pLimit          = "limit"
+	pDocument       = "document"
+	pAccount        = "account"
+)
+
+func main() {
+	err := sandbox.Start(new(CredentialContract))
+	if err != nil {
+		sdk.Instance.Errorf(err.Error())
+	}
+}
+
+// InitContract install contract func, the creator is the admin
+func (c *CredentialContract) InitContract() protogo.Response {
+	admin, err := sdk.Instance.Origin()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	args := sdk.Instance.GetArgs()
+	config := &Config{Admin: admin, Identity: string(args[pIdentity])}
+	if level := args[pMinIssuerLevel]; len(level) > 0 {
+		if config.MinIssuerLevel, err = strconv.Atoi(string(level)); err != nil {
+			return sdk.Error(fmt.Sprintf("VC: parameter:'%s' not a valid int", pMinIssuerLevel))
+		}
+	}
+	if err = c.SetConfig(config); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("Init contract success"))
+}
+
+// UpgradeContract upgrade contract func, the config is kept
+func (c *CredentialContract) UpgradeContract() protogo.Response {
+	return sdk.Success([]byte("Upgrade contract success"))
+}
+
+// InvokeContract the entry func of invoke contract func
+func (c *CredentialContract) InvokeContract(method string) protogo.Response { // nolint:gocyclo
+	if len(method) == 0 {
+		return sdk.Error("method of param should not be empty")
+	}
+	args := sdk.Instance.GetArgs()
+	switch method {
+	case "RegisterIssuer":
+		issuer, err := RequireString(pIssuer)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.RegisterIssuer(issuer, string(args[pName])))
+	case "RemoveIssuer":
+		issuer, err := RequireString(pIssuer)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.RemoveIssuer(issuer))
+	case "IssueCredential":
+		credential, err := requireCredential()
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.IssueCredential(credential))
+	case "RevokeCredential":
+		hash, err := RequireString(pHash)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.RevokeCredential(hash, string(args[pReason])))
+	case "VerifyCredential":
+		hash, err := RequireString(pHash)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		verification, err := c.VerifyCredential(hash)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(verification)
+	case "VerifyClaim":
+		hash, err := RequireString(pHash)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		name, err := RequireString(pName)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnBool(c.VerifyClaim(hash, string(args[pSalt]), name, string(args[pValue])))
+	case "HasCredential":
+		holder, err := RequireString(pHolder)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		issuer, err := RequireString(pIssuer)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnBool(c.HasCredential(holder, issuer, string(args[pSchemaId])))
+	case "ClaimHash":
+		name, err := RequireString(pName)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		claimHash, err := ClaimHash(string(args[pSalt]), name, string(args[pValue]))
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return sdk.Success([]byte(claimHash))
+	case "GetCredential":
+		hash, err := RequireString(pHash)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		credential, err := c.GetCredential(hash)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(credential)
+	case "GetCredentialsOf":
+		holder, err := RequireString(pHolder)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		hashes, err := c.GetCredentialsOf(holder)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(hashes)
+	case "GetRevocationList":
+		issuer, err := RequireString(pIssuer)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		var offset, limit uint64
+		if len(args[pOffset]) > 0 {
+			if offset, err = RequireUint64(pOffset); err != nil {
+				return sdk.Error(err.Error())
+			}
+		}
+		if len(args[pLimit]) > 0 {
+			if limit, err = RequireUint64(pLimit); err != nil {
+				return sdk.Error(err.Error())
+			}
+		}
+		hashes, err := c.GetRevocationList(issuer, offset, limit)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(hashes)
+	case "GetIssuer":
+		issuer, err := RequireString(pIssuer)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		record, err := c.GetIssuer(issuer)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(record)
+	case "SetDIDDocument":
+		document, err := RequireString(pDocument)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.SetDIDDocument([]byte(document)))
+	case "GetDIDDocument":
+		account, err := RequireString(pAccount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		document, err := c.GetDIDDocument(account)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return sdk.Success(document)
+	case "GetConfig":
+		config, err := c.GetConfig()
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(config)
+	default:
+		return sdk.Error("Invalid method")
+	}
+}
+
+// requireCredential read the credential to issue from the args
+func requireCredential() (*Credential, error) {
+	args := sdk.Instance.GetArgs()
+	credential := &Credential{}
+	var err error
+	if credential.Hash, err = RequireString(pHash); err != nil {
+		return nil, err
+	}
+	if credential.Holder, err = RequireString(pHolder); err != nil {
+		return nil, err
+	}
+	if credential.SchemaId, err = RequireString(pSchemaId); err != nil {
+		return nil, err
+	}
+	if len(args[pExpireAt]) > 0 {
+		if credential.ExpireAt, err = RequireUint64(pExpireAt); err != nil {
+			return nil, err
+		}
+	}
+	if len(args[pClaimHashes]) > 0 {
+		if err = json.Unmarshal(args[pClaimHashes], &credential.ClaimHashes); err != nil {
+			return nil, fmt.Errorf("VC: parameter:'%s' should be a json array of hashes", pClaimHashes)
+		}
+	}
+	return credential, nil
+}
+
+////////////////////////////////Helper//////////////////////////////////
+
+// ReturnBool 封装返回bool类型为Response，如果有error则忽略bool，封装error
+// @param b
+// @param err
+// @return Response
+func ReturnBool(b bool, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatBool(b)))
+}
+
+// ReturnJson 封装返回interface类型为json string Response
+// @param data
+// @return Response
+func ReturnJson(data interface{}) protogo.Response {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(bz)
+}
+
+// Return 封装返回Bool类型为Response，如果有error则忽略bool，封装error
+// @param err
+// @return Response
+func Return(err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.SuccessResponse
+}
+
+// RequireString 必须要有参数 string类型
+// @param key
+// @return string
+// @return error
+func RequireString(key string) (string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return "", fmt.Errorf("VC: require parameter:'%s'", key)
+	}
+	return string(b), nil
+}
+
+// RequireUint64 必须要有参数 uint64类型
+// @param key
+// @return uint64
+// @return error
+func RequireUint64(key string) (uint64, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok {
+		return 0, fmt.Errorf("VC: require parameter:'%s'", key)
+	}
+	num, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("VC: parameter:'%s' not a valid uint64", key)
+	}
+	return num, nil
+}
@@ -709,6 +709,24 @@ func (e *EncDataContract) InvokeContract(method string) protogo.Response {
 		return e.get_enc_auth()
 	case enc_update_auth_func_name:
 		return e.update_enc_auth()
+	case pre_enc_data_func_name:
+		return e.pre_enc_data()
+	case pre_get_data_func_name:
+		return e.pre_get_data()
+	case pre_grant_func_name:
+		return e.pre_grant()
+	case pre_revoke_func_name:
+		return e.pre_revoke()
+	case pre_get_grant_func_name:
+		return e.pre_get_grant()
+	case pre_request_func_name:
+		return e.pre_request_access()
+	case pre_fulfill_func_name:
+		return e.pre_fulfill_access()
+	case pre_get_request_func_name:
+		return e.pre_get_access()
+	case pre_get_audit_func_name:
+		return e.pre_get_audit()
 	default:
 		return sdk.Error("invalid method")
 	}
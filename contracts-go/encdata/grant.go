@@ -0,0 +1,638 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	pre_enc_data_func_name     = "pre_enc_data"
+	pre_get_data_func_name     = "pre_get_data"
+	pre_grant_func_name        = "pre_grant"
+	pre_revoke_func_name       = "pre_revoke"
+	pre_get_grant_func_name    = "pre_get_grant"
+	pre_request_func_name      = "pre_request_access"
+	pre_fulfill_func_name      = "pre_fulfill_access"
+	pre_get_request_func_name  = "pre_get_access"
+	pre_get_audit_func_name    = "pre_get_audit"
+	pre_data_filed             = "pre_data_contract_filed"
+	pre_value_filed            = "pre_value_contract_filed"
+	pre_grant_key_prefix       = "pre_grant_"
+	pre_request_key            = "pre_request"
+	pre_audit_key_prefix       = "pre_audit_"
+	pre_audit_count_filed      = "n"
+	pre_max_inline_payload     = 4 * 1024
+	pre_max_audit_query_limit  = 100
+	pre_request_status_pending = "pending"
+	pre_request_status_done    = "fulfilled"
+)
+
+const (
+	// CAPSULE parameter of the data encryption contract -- capsule
+	CAPSULE = "capsule"
+
+	// OWNER_PK parameter of the data encryption contract -- owner_pk
+	OWNER_PK = "owner_pk"
+
+	// PROXY parameter of the data encryption contract -- proxy
+	PROXY = "proxy"
+
+	// READER parameter of the data encryption contract -- reader
+	READER = "reader"
+
+	// READER_PK parameter of the data encryption contract -- reader_pk
+	READER_PK = "reader_pk"
+
+	// RE_KEY parameter of the data encryption contract -- re_key
+	RE_KEY = "re_key"
+
+	// PUBLIC_RE_KEY parameter of the data encryption contract -- public_re_key
+	PUBLIC_RE_KEY = "public_re_key"
+
+	// EXPIRE_AT parameter of the data encryption contract -- expire_at
+	EXPIRE_AT = "expire_at"
+
+	// REQUEST_ID parameter of the data encryption contract -- request_id
+	REQUEST_ID = "request_id"
+
+	// CFRAG parameter of the data encryption contract -- cfrag
+	CFRAG = "cfrag"
+
+	// OFFSET parameter of the data encryption contract -- offset
+	OFFSET = "offset"
+
+	// LIMIT parameter of the data encryption contract -- limit
+	LIMIT = "limit"
+)
+
+// PreData 代理重加密的数据
+type PreData struct {
+	// DataKey 加密数据的key
+	DataKey string `json:"dataKey"`
+
+	// Owner 数据所有者地址
+	Owner string `json:"owner"`
+
+	// OwnerPk 数据公钥，对称密钥封装在该公钥下
+	OwnerPk string `json:"ownerPk"`
+
+	// Capsule 封装对称密钥的胶囊
+	Capsule *Capsule `json:"capsule"`
+
+	// Proxy 指定的重加密服务地址，为空且数据不超过4KB时由合约重加密
+	Proxy string `json:"proxy"`
+
+	// Size 密文大小
+	Size int `json:"size"`
+}
+
+// PreGrant 读者的授权
+type PreGrant struct {
+	// DataKey 加密数据的key
+	DataKey string `json:"dataKey"`
+
+	// Reader 读者地址
+	Reader string `json:"reader"`
+
+	// ReaderPk 读者公钥
+	ReaderPk string `json:"readerPk"`
+
+	// ReKey 重加密密钥，仅由合约重加密时发布，读者可由其恢复数据私钥，授权不能过期也不能撤销
+	ReKey *ReKey `json:"reKey,omitempty"`
+
+	// PublicReKey 公开重加密密钥，用于校验重加密服务提交的 CFrag，撤销后清空
+	PublicReKey *PublicReKey `json:"publicReKey,omitempty"`
+
+	// ExpireAt 过期时间戳，0为不过期
+	ExpireAt int64 `json:"expireAt"`
+
+	// Revoked 是否已撤销
+	Revoked bool `json:"revoked"`
+}
+
+// PreRequest 读者的访问请求
+type PreRequest struct {
+	// RequestId 请求id，即发起请求的交易id
+	RequestId string `json:"requestId"`
+
+	// DataKey 加密数据的key
+	DataKey string `json:"dataKey"`
+
+	// Reader 读者地址
+	Reader string `json:"reader"`
+
+	// Status 请求状态 pending/fulfilled
+	Status string `json:"status"`
+
+	// CFrag 重加密后的胶囊
+	CFrag *CFrag `json:"cfrag,omitempty"`
+
+	// Ciphertext 由合约重加密时附带的密文
+	Ciphertext []byte `json:"ciphertext,omitempty"`
+
+	// Fulfiller 完成重加密的地址，合约重加密时为空
+	Fulfiller string `json:"fulfiller,omitempty"`
+}
+
+// PreAudit 审计记录
+type PreAudit struct {
+	// Action 操作 grant/revoke/request/fulfill
+	Action string `json:"action"`
+
+	// Operator 操作人地址
+	Operator string `json:"operator"`
+
+	// Reader 读者地址
+	Reader string `json:"reader"`
+
+	// RequestId 访问请求id
+	RequestId string `json:"requestId,omitempty"`
+
+	// TxId 交易id
+	TxId string `json:"txId"`
+
+	// Timestamp 交易时间戳
+	Timestamp string `json:"timestamp"`
+}
+
+func preError(action string, err error) protogo.Response {
+	err = fmt.Errorf("fail to %s, err: %s", action, err.Error())
+	sdk.Instance.Log(err.Error())
+	return sdk.Error(err.Error())
+}
+
+func preArgError(action, key string) protogo.Response {
+	return preError(action, fmt.Errorf("the parameter [%s] does not exist", key))
+}
+
+// pre_enc_data 存储代理重加密的数据，发送者为数据所有者
+func (e *EncDataContract) pre_enc_data() protogo.Response {
+	const action = "store pre encrypted data"
+	args := sdk.Instance.GetArgs()
+	dataKey := string(args[DATA_KEY])
+	if len(dataKey) == 0 {
+		return preArgError(action, DATA_KEY)
+	}
+	dataValue, ok := args[DATA_VALUE]
+	if !ok {
+		return preArgError(action, DATA_VALUE)
+	}
+	ownerPk := string(args[OWNER_PK])
+	if len(ownerPk) == 0 {
+		return preArgError(action, OWNER_PK)
+	}
+	if _, err := parsePoint(ownerPk); err != nil {
+		return preError(action, err)
+	}
+	capsule := &Capsule{}
+	if err := json.Unmarshal(args[CAPSULE], capsule); err != nil {
+		return preArgError(action, CAPSULE)
+	}
+	if err := capsule.Verify(); err != nil {
+		return preError(action, err)
+	}
+	data, err := getPreData(dataKey)
+	if err != nil {
+		return preError(action, err)
+	}
+	if data != nil {
+		return preError(action, fmt.Errorf("the data key already exist"))
+	}
+	owner, err := sdk.Instance.Sender()
+	if err != nil {
+		return preError(action, err)
+	}
+
+	data = &PreData{DataKey: dataKey, Owner: owner, OwnerPk: ownerPk, Capsule: capsule,
+		Proxy: string(args[PROXY]), Size: len(dataValue)}
+	if err = putJson(dataKey, pre_data_filed, data); err != nil {
+		return preError(action, err)
+	}
+	if err = sdk.Instance.PutStateByte(dataKey, pre_value_filed, dataValue); err != nil {
+		return preError(action, err)
+	}
+	return sdk.Success([]byte("store pre encrypted data successfully"))
+}
+
+// pre_get_data 获取代理重加密数据的元数据
+func (e *EncDataContract) pre_get_data() protogo.Response {
+	const action = "get pre encrypted data"
+	data, err := requirePreData(string(sdk.Instance.GetArgs()[DATA_KEY]))
+	if err != nil {
+		return preError(action, err)
+	}
+	return returnJson(action, data)
+}
+
+// pre_grant 数据所有者为读者授权，重复授权将覆盖之前的授权。由合约重加密时发布重加密密钥，
+// 读者由链上的重加密密钥即可恢复数据私钥，无需请求访问，授权即永久披露，因此不能设置过期时间；
+// 指定了重加密服务时只发布公开重加密密钥，重加密密钥在链下交给重加密服务
+func (e *EncDataContract) pre_grant() protogo.Response {
+	const action = "grant"
+	args := sdk.Instance.GetArgs()
+	reader := string(args[READER])
+	if len(reader) == 0 {
+		return preArgError(action, READER)
+	}
+	readerPk := string(args[READER_PK])
+	if len(readerPk) == 0 {
+		return preArgError(action, READER_PK)
+	}
+	if _, err := parsePoint(readerPk); err != nil {
+		return preError(action, err)
+	}
+	var expireAt int64
+	if len(args[EXPIRE_AT]) > 0 {
+		var err error
+		if expireAt, err = strconv.ParseInt(string(args[EXPIRE_AT]), 10, 64); err != nil || expireAt < 0 {
+			return preError(action, fmt.Errorf("invalid %s", EXPIRE_AT))
+		}
+	}
+	data, owner, err := requirePreOwner(string(args[DATA_KEY]))
+	if err != nil {
+		return preError(action, err)
+	}
+	if reader == owner {
+		return preError(action, fmt.Errorf("can not authorize for self"))
+	}
+
+	grant := &PreGrant{DataKey: data.DataKey, Reader: reader, ReaderPk: readerPk, ExpireAt: expireAt}
+	if len(data.Proxy) == 0 {
+		if expireAt > 0 {
+			return preError(action, fmt.Errorf("the published re-encryption key discloses the data key "+
+				"permanently, %s needs a designated re-encryption service", EXPIRE_AT))
+		}
+		grant.ReKey = &ReKey{}
+		if err = json.Unmarshal(args[RE_KEY], grant.ReKey); err != nil {
+			return preArgError(action, RE_KEY)
+		}
+		if grant.PublicReKey, err = grant.ReKey.Public(); err != nil {
+			return preError(action, err)
+		}
+	} else {
+		if len(args[RE_KEY]) > 0 {
+			return preError(action, fmt.Errorf("the re-encryption key should be sent to the re-encryption "+
+				"service off chain when it is designated"))
+		}
+		grant.PublicReKey = &PublicReKey{}
+		if err = json.Unmarshal(args[PUBLIC_RE_KEY], grant.PublicReKey); err != nil {
+			return preArgError(action, PUBLIC_RE_KEY)
+		}
+		if err = grant.PublicReKey.Verify(); err != nil {
+			return preError(action, err)
+		}
+	}
+	if err = putJson(pre_grant_key_prefix+data.DataKey, reader, grant); err != nil {
+		return preError(action, err)
+	}
+	if err = appendAudit(data.DataKey, action, owner, reader, ""); err != nil {
+		return preError(action, err)
+	}
+	sdk.Instance.EmitEvent(pre_grant_func_name, []string{data.DataKey, reader, strconv.FormatInt(expireAt, 10)})
+	return sdk.Success([]byte("grant successfully"))
+}
+
+// pre_revoke 数据所有者撤销读者的授权，清空公开重加密密钥。由合约重加密的授权已在链上发布重加密密钥，
+// 不能撤销
+func (e *EncDataContract) pre_revoke() protogo.Response {
+	const action = "revoke"
+	args := sdk.Instance.GetArgs()
+	reader := string(args[READER])
+	if len(reader) == 0 {
+		return preArgError(action, READER)
+	}
+	data, owner, err := requirePreOwner(string(args[DATA_KEY]))
+	if err != nil {
+		return preError(action, err)
+	}
+	grant, err := getPreGrant(data.DataKey, reader)
+	if err != nil {
+		return preError(action, err)
+	}
+	if grant == nil || grant.Revoked {
+		return preError(action, fmt.Errorf("the grant does not exist"))
+	}
+	if grant.ReKey != nil {
+		return preError(action, fmt.Errorf("the published re-encryption key discloses the data key "+
+			"permanently, the grant can not be revoked"))
+	}
+
+	grant.Revoked, grant.PublicReKey = true, nil
+	if err = putJson(pre_grant_key_prefix+data.DataKey, reader, grant); err != nil {
+		return preError(action, err)
+	}
+	if err = appendAudit(data.DataKey, action, owner, reader, ""); err != nil {
+		return preError(action, err)
+	}
+	sdk.Instance.EmitEvent(pre_revoke_func_name, []string{data.DataKey, reader})
+	return sdk.Success([]byte("revoke successfully"))
+}
+
+// pre_get_grant 获取读者的授权
+func (e *EncDataContract) pre_get_grant() protogo.Response {
+	const action = "get grant"
+	args := sdk.Instance.GetArgs()
+	grant, err := getPreGrant(string(args[DATA_KEY]), string(args[READER]))
+	if err != nil {
+		return preError(action, err)
+	}
+	if grant == nil {
+		return preError(action, fmt.Errorf("the grant does not exist"))
+	}
+	return returnJson(action, grant)
+}
+
+// pre_request_access 读者请求访问，未指定重加密服务且数据较小时由合约直接重加密并返回密文
+func (e *EncDataContract) pre_request_access() protogo.Response {
+	const action = "request access"
+	data, err := requirePreData(string(sdk.Instance.GetArgs()[DATA_KEY]))
+	if err != nil {
+		return preError(action, err)
+	}
+	reader, err := sdk.Instance.Sender()
+	if err != nil {
+		return preError(action, err)
+	}
+	grant, err := requireActiveGrant(data.DataKey, reader)
+	if err != nil {
+		return preError(action, err)
+	}
+	requestId, err := sdk.Instance.GetTxId()
+	if err != nil {
+		return preError(action, err)
+	}
+
+	request := &PreRequest{RequestId: requestId, DataKey: data.DataKey, Reader: reader,
+		Status: pre_request_status_pending}
+	if len(data.Proxy) == 0 {
+		if data.Size > pre_max_inline_payload {
+			return preError(action, fmt.Errorf("the data is larger than %d bytes and no re-encryption service "+
+				"is designated", pre_max_inline_payload))
+		}
+		if request.CFrag, err = ReEncrypt(data.Capsule, grant.ReKey); err != nil {
+			return preError(action, err)
+		}
+		if request.Ciphertext, err = sdk.Instance.GetStateByte(data.DataKey, pre_value_filed); err != nil {
+			return preError(action, err)
+		}
+		request.Status = pre_request_status_done
+	}
+	if err = putJson(pre_request_key, requestId, request); err != nil {
+		return preError(action, err)
+	}
+	if err = appendAudit(data.DataKey, "request", reader, reader, requestId); err != nil {
+		return preError(action, err)
+	}
+	sdk.Instance.EmitEvent(pre_request_func_name, []string{data.DataKey, reader, requestId, request.Status})
+	return returnJson(action, request)
+}
+
+// pre_fulfill_access 指定的重加密服务提交重加密结果，合约用公开重加密密钥校验 CFrag 的证明
+func (e *EncDataContract) pre_fulfill_access() protogo.Response {
+	const action = "fulfill access"
+	args := sdk.Instance.GetArgs()
+	requestId := string(args[REQUEST_ID])
+	if len(requestId) == 0 {
+		return preArgError(action, REQUEST_ID)
+	}
+	cFrag := &CFrag{}
+	if err := json.Unmarshal(args[CFRAG], cFrag); err != nil {
+		return preArgError(action, CFRAG)
+	}
+	request, err := requirePreRequest(requestId)
+	if err != nil {
+		return preError(action, err)
+	}
+	if request.Status != pre_request_status_pending {
+		return preError(action, fmt.Errorf("the request is %s", request.Status))
+	}
+	data, err := requirePreData(request.DataKey)
+	if err != nil {
+		return preError(action, err)
+	}
+	proxy, err := sdk.Instance.Sender()
+	if err != nil {
+		return preError(action, err)
+	}
+	if proxy != data.Proxy {
+		return preError(action, fmt.Errorf("only the designated re-encryption service can fulfill the request"))
+	}
+	grant, err := requireActiveGrant(data.DataKey, request.Reader)
+	if err != nil {
+		return preError(action, err)
+	}
+	if err = cFrag.Verify(data.Capsule, grant.PublicReKey); err != nil {
+		return preError(action, fmt.Errorf("invalid cfrag"))
+	}
+
+	request.CFrag, request.Fulfiller, request.Status = cFrag, proxy, pre_request_status_done
+	if err = putJson(pre_request_key, requestId, request); err != nil {
+		return preError(action, err)
+	}
+	if err = appendAudit(data.DataKey, "fulfill", proxy, request.Reader, requestId); err != nil {
+		return preError(action, err)
+	}
+	sdk.Instance.EmitEvent(pre_fulfill_func_name, []string{data.DataKey, request.Reader, requestId})
+	return sdk.Success([]byte("fulfill access successfully"))
+}
+
+// pre_get_access 获取访问请求及重加密结果
+func (e *EncDataContract) pre_get_access() protogo.Response {
+	const action = "get access"
+	request, err := requirePreRequest(string(sdk.Instance.GetArgs()[REQUEST_ID]))
+	if err != nil {
+		return preError(action, err)
+	}
+	return returnJson(action, request)
+}
+
+// pre_get_audit 分页获取数据的审计记录
+func (e *EncDataContract) pre_get_audit() protogo.Response {
+	const action = "get audit"
+	args := sdk.Instance.GetArgs()
+	data, err := requirePreData(string(args[DATA_KEY]))
+	if err != nil {
+		return preError(action, err)
+	}
+	offset, limit := 0, pre_max_audit_query_limit
+	if len(args[OFFSET]) > 0 {
+		if offset, err = strconv.Atoi(string(args[OFFSET])); err != nil || offset < 0 {
+			return preError(action, fmt.Errorf("invalid %s", OFFSET))
+		}
+	}
+	if len(args[LIMIT]) > 0 {
+		if limit, err = strconv.Atoi(string(args[LIMIT])); err != nil || limit <= 0 ||
+			limit > pre_max_audit_query_limit {
+			return preError(action, fmt.Errorf("%s should be between 1 and %d", LIMIT, pre_max_audit_query_limit))
+		}
+	}
+	count, err := getAuditCount(data.DataKey)
+	if err != nil {
+		return preError(action, err)
+	}
+	audits := make([]*PreAudit, 0)
+	for i := offset; i < count && i < offset+limit; i++ {
+		audit := &PreAudit{}
+		if err = getJson(pre_audit_key_prefix+data.DataKey, strconv.Itoa(i), audit); err != nil {
+			return preError(action, err)
+		}
+		audits = append(audits, audit)
+	}
+	return returnJson(action, audits)
+}
+
+func requirePreData(dataKey string) (*PreData, error) {
+	if len(dataKey) == 0 {
+		return nil, fmt.Errorf("the parameter [%s] does not exist", DATA_KEY)
+	}
+	data, err := getPreData(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("the data does not exist")
+	}
+	return data, nil
+}
+
+// requirePreOwner 获取发送者所有的数据
+func requirePreOwner(dataKey string) (*PreData, string, error) {
+	data, err := requirePreData(dataKey)
+	if err != nil {
+		return nil, "", err
+	}
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return nil, "", err
+	}
+	if sender != data.Owner {
+		return nil, "", fmt.Errorf("only the owner of the data can authorize")
+	}
+	return data, sender, nil
+}
+
+// requireActiveGrant 获取未撤销且未过期的授权
+func requireActiveGrant(dataKey, reader string) (*PreGrant, error) {
+	grant, err := getPreGrant(dataKey, reader)
+	if err != nil {
+		return nil, err
+	}
+	if grant == nil || grant.Revoked {
+		return nil, fmt.Errorf("the reader is not authorized")
+	}
+	if grant.ExpireAt > 0 {
+		txTime, err := sdk.Instance.GetTxTimeStamp()
+		if err != nil {
+			return nil, err
+		}
+		now, err := strconv.ParseInt(txTime, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if now >= grant.ExpireAt {
+			return nil, fmt.Errorf("the grant is expired")
+		}
+	}
+	return grant, nil
+}
+
+func requirePreRequest(requestId string) (*PreRequest, error) {
+	if len(requestId) == 0 {
+		return nil, fmt.Errorf("the parameter [%s] does not exist", REQUEST_ID)
+	}
+	request := &PreRequest{}
+	if err := getJson(pre_request_key, requestId, request); err != nil {
+		return nil, err
+	}
+	if len(request.RequestId) == 0 {
+		return nil, fmt.Errorf("the request does not exist")
+	}
+	return request, nil
+}
+
+func getPreData(dataKey string) (*PreData, error) {
+	data := &PreData{}
+	if err := getJson(dataKey, pre_data_filed, data); err != nil {
+		return nil, err
+	}
+	if len(data.DataKey) == 0 {
+		return nil, nil
+	}
+	return data, nil
+}
+
+func getPreGrant(dataKey, reader string) (*PreGrant, error) {
+	grant := &PreGrant{}
+	if err := getJson(pre_grant_key_prefix+dataKey, reader, grant); err != nil {
+		return nil, err
+	}
+	if len(grant.Reader) == 0 {
+		return nil, nil
+	}
+	return grant, nil
+}
+
+// appendAudit 追加审计记录
+func appendAudit(dataKey, action, operator, reader, requestId string) error {
+	txId, err := sdk.Instance.GetTxId()
+	if err != nil {
+		return err
+	}
+	timestamp, err := sdk.Instance.GetTxTimeStamp()
+	if err != nil {
+		return err
+	}
+	count, err := getAuditCount(dataKey)
+	if err != nil {
+		return err
+	}
+	audit := &PreAudit{Action: action, Operator: operator, Reader: reader, RequestId: requestId, TxId: txId,
+		Timestamp: timestamp}
+	if err = putJson(pre_audit_key_prefix+dataKey, strconv.Itoa(count), audit); err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(pre_audit_key_prefix+dataKey, pre_audit_count_filed,
+		[]byte(strconv.Itoa(count+1)))
+}
+
+func getAuditCount(dataKey string) (int, error) {
+	countBytes, err := sdk.Instance.GetStateByte(pre_audit_key_prefix+dataKey, pre_audit_count_filed)
+	if err != nil || len(countBytes) == 0 {
+		return 0, err
+	}
+	return strconv.Atoi(string(countBytes))
+}
+
+func putJson(key, field string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(key, field, data)
+}
+
+// getJson 读取json，不存在时v保持不变
+func getJson(key, field string, v interface{}) error {
+	data, err := sdk.Instance.GetStateByte(key, field)
+	if err != nil || len(data) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func returnJson(action string, v interface{}) protogo.Response {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return preError(action, err)
+	}
+	return sdk.Success(data)
+}
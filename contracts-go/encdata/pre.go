@@ -0,0 +1,374 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+)
+
+// 代理重加密采用基于 P-256 的 KEM 方案(Umbral 的单代理形式)：
+// 数据用对称密钥加密，对称密钥由胶囊(Capsule)封装在数据公钥下；
+// 数据所有者为读者生成重加密密钥(ReKey)，代理(合约或指定的重加密服务)用它把胶囊转换为读者可解封的 CFrag，
+// 代理无法得到对称密钥。rk·d = a，得到重加密密钥的读者即可恢复数据私钥，因此每份数据应使用独立的数据密钥对。
+// 指定重加密服务时链上只发布公开重加密密钥(PublicReKey)，CFrag 附带 DLEQ 证明，合约不需要重加密密钥即可校验。
+
+var curve = elliptic.P256()
+
+// Capsule 封装对称密钥的胶囊
+type Capsule struct {
+	// E 点 r·G
+	E string `json:"e"`
+	// V 点 u·G
+	V string `json:"v"`
+	// S 标量 u + r·H(E,V)
+	S string `json:"s"`
+}
+
+// ReKey 数据所有者为读者生成的重加密密钥
+type ReKey struct {
+	// RK 标量 a·d^-1
+	RK string `json:"rk"`
+	// X 临时公钥 x·G，读者用它计算 d
+	X string `json:"x"`
+}
+
+// PublicReKey 公开重加密密钥，用于校验重加密服务提交的 CFrag
+type PublicReKey struct {
+	// U1 点 rk·G
+	U1 string `json:"u1"`
+	// X 重加密密钥的临时公钥
+	X string `json:"x"`
+}
+
+// CFrag 重加密后的胶囊
+type CFrag struct {
+	// E1 点 rk·E
+	E1 string `json:"e1"`
+	// V1 点 rk·V
+	V1 string `json:"v1"`
+	// X 重加密密钥的临时公钥
+	X string `json:"x"`
+	// Proof E1、V1 与公开重加密密钥使用同一 rk 的证明
+	Proof *CFragProof `json:"proof"`
+}
+
+// CFragProof Chaum-Pedersen 证明 log_E(E1) == log_V(V1) == log_G(U1)
+type CFragProof struct {
+	// E2 点 t·E
+	E2 string `json:"e2"`
+	// V2 点 t·V
+	V2 string `json:"v2"`
+	// U2 点 t·G
+	U2 string `json:"u2"`
+	// Z 标量 t + rk·H(E,E1,E2,V,V1,V2,U1,U2)
+	Z string `json:"z"`
+}
+
+type point struct {
+	x, y *big.Int
+}
+
+func (p *point) hex() string {
+	return hex.EncodeToString(elliptic.Marshal(curve, p.x, p.y))
+}
+
+func (p *point) mul(k *big.Int) *point {
+	x, y := curve.ScalarMult(p.x, p.y, scalarBytes(k))
+	return &point{x, y}
+}
+
+func (p *point) add(q *point) *point {
+	x, y := curve.Add(p.x, p.y, q.x, q.y)
+	return &point{x, y}
+}
+
+func (p *point) equal(q *point) bool {
+	return p.x.Cmp(q.x) == 0 && p.y.Cmp(q.y) == 0
+}
+
+func baseMul(k *big.Int) *point {
+	x, y := curve.ScalarBaseMult(scalarBytes(k))
+	return &point{x, y}
+}
+
+func scalarBytes(k *big.Int) []byte {
+	return new(big.Int).Mod(k, curve.Params().N).FillBytes(make([]byte, 32))
+}
+
+func parsePoint(s string) (*point, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid point encoding")
+	}
+	x, y := elliptic.Unmarshal(curve, data)
+	if x == nil {
+		return nil, errors.New("invalid point")
+	}
+	return &point{x, y}, nil
+}
+
+func parseScalar(s string) (*big.Int, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil || len(data) != 32 {
+		return nil, errors.New("invalid scalar")
+	}
+	k := new(big.Int).SetBytes(data)
+	if k.Sign() == 0 || k.Cmp(curve.Params().N) >= 0 {
+		return nil, errors.New("invalid scalar")
+	}
+	return k, nil
+}
+
+func scalarHex(k *big.Int) string {
+	return hex.EncodeToString(scalarBytes(k))
+}
+
+// hashToScalar 将若干点哈希为标量
+func hashToScalar(points ...*point) *big.Int {
+	h := sha256.New()
+	for _, p := range points {
+		h.Write(elliptic.Marshal(curve, p.x, p.y))
+	}
+	k := new(big.Int).SetBytes(h.Sum(nil))
+	return k.Mod(k, curve.Params().N)
+}
+
+func randomScalar() (*big.Int, error) {
+	n := new(big.Int).Sub(curve.Params().N, big.NewInt(1))
+	k, err := rand.Int(rand.Reader, n)
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// kdf 由共享点派生对称密钥
+func kdf(p *point) []byte {
+	sum := sha256.Sum256(elliptic.Marshal(curve, p.x, p.y))
+	return sum[:]
+}
+
+// parse 解析胶囊并校验 S·G == V + H(E,V)·E
+func (c *Capsule) parse() (e, v *point, err error) {
+	if e, err = parsePoint(c.E); err != nil {
+		return nil, nil, err
+	}
+	if v, err = parsePoint(c.V); err != nil {
+		return nil, nil, err
+	}
+	s, err := parseScalar(c.S)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !baseMul(s).equal(v.add(e.mul(hashToScalar(e, v)))) {
+		return nil, nil, errors.New("invalid capsule")
+	}
+	return e, v, nil
+}
+
+// Verify 校验胶囊
+func (c *Capsule) Verify() error {
+	_, _, err := c.parse()
+	return err
+}
+
+// Verify 校验重加密密钥
+func (k *ReKey) Verify() error {
+	if _, err := parseScalar(k.RK); err != nil {
+		return err
+	}
+	_, err := parsePoint(k.X)
+	return err
+}
+
+// Public 公开重加密密钥
+func (k *ReKey) Public() (*PublicReKey, error) {
+	rk, err := parseScalar(k.RK)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = parsePoint(k.X); err != nil {
+		return nil, err
+	}
+	return &PublicReKey{U1: baseMul(rk).hex(), X: k.X}, nil
+}
+
+// Verify 校验公开重加密密钥
+func (k *PublicReKey) Verify() error {
+	if _, err := parsePoint(k.U1); err != nil {
+		return err
+	}
+	_, err := parsePoint(k.X)
+	return err
+}
+
+// ReEncrypt 用重加密密钥转换胶囊，并证明使用的是与公开重加密密钥对应的 rk
+// @param capsule
+// @param reKey
+// @return *CFrag
+// @return error
+func ReEncrypt(capsule *Capsule, reKey *ReKey) (*CFrag, error) {
+	e, v, err := capsule.parse()
+	if err != nil {
+		return nil, err
+	}
+	rk, err := parseScalar(reKey.RK)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = parsePoint(reKey.X); err != nil {
+		return nil, err
+	}
+	t, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	e1, v1, u1 := e.mul(rk), v.mul(rk), baseMul(rk)
+	e2, v2, u2 := e.mul(t), v.mul(t), baseMul(t)
+	z := new(big.Int).Mul(rk, hashToScalar(e, e1, e2, v, v1, v2, u1, u2))
+	z.Add(z, t)
+	return &CFrag{E1: e1.hex(), V1: v1.hex(), X: reKey.X,
+		Proof: &CFragProof{E2: e2.hex(), V2: v2.hex(), U2: u2.hex(), Z: scalarHex(z)}}, nil
+}
+
+// Verify 校验 CFrag 由胶囊和公开重加密密钥对应的 rk 转换得到，校验不需要重加密密钥
+// @param capsule
+// @param publicReKey
+// @return error
+func (f *CFrag) Verify(capsule *Capsule, publicReKey *PublicReKey) error {
+	e, v, err := capsule.parse()
+	if err != nil {
+		return err
+	}
+	if f.X != publicReKey.X || f.Proof == nil {
+		return errors.New("invalid cfrag")
+	}
+	points := make([]*point, 0, 6)
+	for _, s := range []string{f.E1, f.V1, publicReKey.U1, f.Proof.E2, f.Proof.V2, f.Proof.U2} {
+		p, err1 := parsePoint(s)
+		if err1 != nil {
+			return err1
+		}
+		points = append(points, p)
+	}
+	e1, v1, u1, e2, v2, u2 := points[0], points[1], points[2], points[3], points[4], points[5]
+	z, err := parseScalar(f.Proof.Z)
+	if err != nil {
+		return err
+	}
+	h := hashToScalar(e, e1, e2, v, v1, v2, u1, u2)
+	if !e.mul(z).equal(e2.add(e1.mul(h))) || !v.mul(z).equal(v2.add(v1.mul(h))) ||
+		!baseMul(z).equal(u2.add(u1.mul(h))) {
+		return errors.New("invalid cfrag")
+	}
+	return nil
+}
+
+// GenerateKey 生成密钥对，返回私钥标量和公钥点的hex
+func GenerateKey() (sk, pk string, err error) {
+	k, err := randomScalar()
+	if err != nil {
+		return "", "", err
+	}
+	return scalarHex(k), baseMul(k).hex(), nil
+}
+
+// Encapsulate 在公钥下封装新的对称密钥，由数据所有者在链下调用
+// @param pk
+// @return key 对称密钥
+// @return capsule
+// @return err
+func Encapsulate(pk string) (key []byte, capsule *Capsule, err error) {
+	pkPoint, err := parsePoint(pk)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := randomScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := randomScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	e, v := baseMul(r), baseMul(u)
+	s := new(big.Int).Mul(r, hashToScalar(e, v))
+	s.Add(s, u)
+	capsule = &Capsule{E: e.hex(), V: v.hex(), S: scalarHex(s)}
+	return kdf(pkPoint.mul(new(big.Int).Add(r, u))), capsule, nil
+}
+
+// Decapsulate 数据所有者用私钥解封胶囊
+func Decapsulate(sk string, capsule *Capsule) ([]byte, error) {
+	a, err := parseScalar(sk)
+	if err != nil {
+		return nil, err
+	}
+	e, v, err := capsule.parse()
+	if err != nil {
+		return nil, err
+	}
+	return kdf(e.add(v).mul(a)), nil
+}
+
+// GenerateReKey 数据所有者为读者公钥生成重加密密钥，由数据所有者在链下调用
+// @param sk 数据私钥
+// @param readerPk 读者公钥
+// @return *ReKey
+// @return error
+func GenerateReKey(sk, readerPk string) (*ReKey, error) {
+	a, err := parseScalar(sk)
+	if err != nil {
+		return nil, err
+	}
+	b, err := parsePoint(readerPk)
+	if err != nil {
+		return nil, err
+	}
+	x, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	xPoint := baseMul(x)
+	d := hashToScalar(xPoint, b, b.mul(x))
+	if d.Sign() == 0 {
+		return nil, errors.New("invalid re-encryption key")
+	}
+	rk := new(big.Int).ModInverse(d, curve.Params().N)
+	rk.Mul(rk, a)
+	return &ReKey{RK: scalarHex(rk), X: xPoint.hex()}, nil
+}
+
+// DecapsulateReEncrypted 读者用私钥解封重加密后的胶囊
+// @param sk 读者私钥
+// @param cFrag
+// @return []byte 对称密钥
+// @return error
+func DecapsulateReEncrypted(sk string, cFrag *CFrag) ([]byte, error) {
+	b, err := parseScalar(sk)
+	if err != nil {
+		return nil, err
+	}
+	e1, err := parsePoint(cFrag.E1)
+	if err != nil {
+		return nil, err
+	}
+	v1, err := parsePoint(cFrag.V1)
+	if err != nil {
+		return nil, err
+	}
+	x, err := parsePoint(cFrag.X)
+	if err != nil {
+		return nil, err
+	}
+	d := hashToScalar(x, baseMul(b), x.mul(b))
+	return kdf(e1.add(v1).mul(d)), nil
+}
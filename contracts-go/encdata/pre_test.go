@@ -0,0 +1,313 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	owner  = "a04f7895de24f61807a729be230f03da8c0eef42"
+	reader = "b04f7895de24f61807a729be230f03da8c0eef42"
+	proxy  = "c04f7895de24f61807a729be230f03da8c0eef42"
+)
+
+// mockGetPutState keeps the state of a test in the returned map by key#field
+func mockGetPutState(mockInstance *sdk.MockSDKInterface) map[string][]byte {
+	stateMap := make(map[string][]byte)
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) ([]byte, error) {
+			return stateMap[key+"#"+field], nil
+		})
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string, value []byte) error {
+			stateMap[key+"#"+field] = value
+			return nil
+		})
+	return stateMap
+}
+
+// step one invocation of a test by sender at the tx timestamp, want is the suffix of the error message
+type step struct {
+	name      string
+	sender    string
+	timestamp int64
+	method    string
+	args      map[string]string
+	want      string
+}
+
+// toArgs the string args as the args of the contract
+func toArgs(callArgs map[string]string) map[string][]byte {
+	args := make(map[string][]byte, len(callArgs))
+	for k, v := range callArgs {
+		args[k] = []byte(v)
+	}
+	return args
+}
+
+func toJson(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestReEncrypt(t *testing.T) {
+	ownerSk, ownerPk, _ := GenerateKey()
+	readerSk, readerPk, _ := GenerateKey()
+	otherSk, _, _ := GenerateKey()
+
+	key, capsule, err := Encapsulate(ownerPk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ownerKey, _ := Decapsulate(ownerSk, capsule); !bytes.Equal(ownerKey, key) {
+		t.Errorf("Decapsulate() = %x, want %x", ownerKey, key)
+	}
+	reKey, err := GenerateReKey(ownerSk, readerPk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cFrag, err := ReEncrypt(capsule, reKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readerKey, _ := DecapsulateReEncrypted(readerSk, cFrag); !bytes.Equal(readerKey, key) {
+		t.Errorf("DecapsulateReEncrypted() = %x, want %x", readerKey, key)
+	}
+	if otherKey, _ := DecapsulateReEncrypted(otherSk, cFrag); bytes.Equal(otherKey, key) {
+		t.Errorf("DecapsulateReEncrypted() with other key should fail")
+	}
+	publicReKey, _ := reKey.Public()
+	if err = cFrag.Verify(capsule, publicReKey); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+	otherReKey, _ := GenerateReKey(otherSk, readerPk)
+	otherPublicReKey, _ := otherReKey.Public()
+	otherPublicReKey.X = publicReKey.X
+	if err = cFrag.Verify(capsule, otherPublicReKey); err == nil {
+		t.Errorf("Verify() with other public re-encryption key should fail")
+	}
+
+	tampered := *capsule
+	tampered.S = capsule.E[2:66]
+	if _, err = ReEncrypt(&tampered, reKey); err == nil {
+		t.Errorf("ReEncrypt() of tampered capsule should fail")
+	}
+}
+
+func TestEncDataContract_pre(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	// every invocation is a new transaction
+	sender, timestamp, txCount := owner, int64(1000), 0
+	var args map[string][]byte
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte {
+		txCount++
+		return args
+	})
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetTxId().AnyTimes().DoAndReturn(func() (string, error) {
+		return "tx" + strconv.Itoa(txCount), nil
+	})
+	mockInstance.EXPECT().GetTxTimeStamp().AnyTimes().DoAndReturn(func() (string, error) {
+		return strconv.FormatInt(timestamp, 10), nil
+	})
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockInstance.EXPECT().Log(gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	sdk.Instance = mockInstance
+
+	ownerSk, ownerPk, _ := GenerateKey()
+	readerSk, readerPk, _ := GenerateKey()
+	key, capsule, _ := Encapsulate(ownerPk)
+	reKey, _ := GenerateReKey(ownerSk, readerPk)
+
+	// the published re-encryption key is a permanent disclosure, it can not expire or be revoked
+	steps := []step{
+		{name: "store", sender: owner, timestamp: 1000, method: pre_enc_data_func_name, args: map[string]string{
+			DATA_KEY: "d1", DATA_VALUE: "ciphertext", OWNER_PK: ownerPk, CAPSULE: toJson(t, capsule)}},
+		{name: "store twice", sender: owner, timestamp: 1000, method: pre_enc_data_func_name,
+			args: map[string]string{DATA_KEY: "d1", DATA_VALUE: "ciphertext", OWNER_PK: ownerPk,
+				CAPSULE: toJson(t, capsule)},
+			want: "the data key already exist"},
+		{name: "request without grant", sender: reader, timestamp: 1000, method: pre_request_func_name,
+			args: map[string]string{DATA_KEY: "d1"}, want: "the reader is not authorized"},
+		{name: "grant by others", sender: reader, timestamp: 1000, method: pre_grant_func_name,
+			args: map[string]string{DATA_KEY: "d1", READER: reader, READER_PK: readerPk, RE_KEY: toJson(t, reKey)},
+			want: "only the owner of the data can authorize"},
+		{name: "grant with expiry", sender: owner, timestamp: 1000, method: pre_grant_func_name,
+			args: map[string]string{DATA_KEY: "d1", READER: reader, READER_PK: readerPk, RE_KEY: toJson(t, reKey),
+				EXPIRE_AT: "2000"},
+			want: "expire_at needs a designated re-encryption service"},
+		{name: "grant", sender: owner, timestamp: 1000, method: pre_grant_func_name,
+			args: map[string]string{DATA_KEY: "d1", READER: reader, READER_PK: readerPk, RE_KEY: toJson(t, reKey)}},
+		{name: "request", sender: reader, timestamp: 1000, method: pre_request_func_name,
+			args: map[string]string{DATA_KEY: "d1"}},
+		{name: "revoke", sender: owner, timestamp: 1000, method: pre_revoke_func_name,
+			args: map[string]string{DATA_KEY: "d1", READER: reader}, want: "the grant can not be revoked"},
+	}
+	var request PreRequest
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender, timestamp, args = s.sender, s.timestamp, toArgs(s.args)
+			resp := (&EncDataContract{}).InvokeContract(s.method)
+			if (resp.Status == sdk.OK) != (len(s.want) == 0) || !strings.HasSuffix(resp.Message, s.want) {
+				t.Fatalf("%s() = %v, want %s", s.method, resp, s.want)
+			}
+			if s.method == pre_request_func_name && resp.Status == sdk.OK {
+				_ = json.Unmarshal(resp.Payload, &request)
+			}
+		})
+	}
+
+	// the contract re-encrypts small data itself
+	if request.Status != pre_request_status_done || string(request.Ciphertext) != "ciphertext" {
+		t.Fatalf("request = %+v", request)
+	}
+	if readerKey, _ := DecapsulateReEncrypted(readerSk, request.CFrag); !bytes.Equal(readerKey, key) {
+		t.Errorf("DecapsulateReEncrypted() = %x, want %x", readerKey, key)
+	}
+}
+
+func TestEncDataContract_preProxy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	// every invocation is a new transaction
+	sender, timestamp, txCount := owner, int64(1000), 0
+	var args map[string][]byte
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte {
+		txCount++
+		return args
+	})
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetTxId().AnyTimes().DoAndReturn(func() (string, error) {
+		return "tx" + strconv.Itoa(txCount), nil
+	})
+	mockInstance.EXPECT().GetTxTimeStamp().AnyTimes().DoAndReturn(func() (string, error) {
+		return strconv.FormatInt(timestamp, 10), nil
+	})
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockInstance.EXPECT().Log(gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	sdk.Instance = mockInstance
+	invoke := func(s step) protogo.Response {
+		sender, timestamp, args = s.sender, s.timestamp, toArgs(s.args)
+		return (&EncDataContract{}).InvokeContract(s.method)
+	}
+
+	ownerSk, ownerPk, _ := GenerateKey()
+	_, readerPk, _ := GenerateKey()
+	_, capsule, _ := Encapsulate(ownerPk)
+	reKey, _ := GenerateReKey(ownerSk, readerPk)
+	publicReKey, _ := reKey.Public()
+	otherKey, _ := GenerateReKey(ownerSk, ownerPk)
+
+	steps := []step{
+		{name: "store", sender: owner, timestamp: 1000, method: pre_enc_data_func_name, args: map[string]string{
+			DATA_KEY: "d1", DATA_VALUE: "ipfs://cid", OWNER_PK: ownerPk, CAPSULE: toJson(t, capsule), PROXY: proxy}},
+		// the re-encryption key is only sent to the re-encryption service
+		{name: "grant with re-encryption key", sender: owner, timestamp: 1000, method: pre_grant_func_name,
+			args: map[string]string{DATA_KEY: "d1", READER: reader, READER_PK: readerPk, RE_KEY: toJson(t, reKey)},
+			want: "the re-encryption key should be sent to the re-encryption service off chain when it is designated"},
+		{name: "grant", sender: owner, timestamp: 1000, method: pre_grant_func_name,
+			args: map[string]string{DATA_KEY: "d1", READER: reader, READER_PK: readerPk,
+				PUBLIC_RE_KEY: toJson(t, publicReKey), EXPIRE_AT: "2000"}},
+	}
+	for _, s := range steps {
+		if resp := invoke(s); (resp.Status == sdk.OK) != (len(s.want) == 0) ||
+			!strings.HasSuffix(resp.Message, s.want) {
+			t.Fatalf("%s: %s() = %v, want %s", s.name, s.method, resp, s.want)
+		}
+	}
+	resp := invoke(step{sender: reader, timestamp: 1000, method: pre_request_func_name,
+		args: map[string]string{DATA_KEY: "d1"}})
+	var request PreRequest
+	if err := json.Unmarshal(resp.Payload, &request); err != nil || request.Status != pre_request_status_pending {
+		t.Fatalf("request = %v", resp)
+	}
+
+	cFrag, _ := ReEncrypt(capsule, reKey)
+	wrongFrag, _ := ReEncrypt(capsule, otherKey)
+	forgedFrag := *cFrag
+	forgedFrag.E1 = wrongFrag.E1
+	tests := []struct {
+		name   string
+		sender string
+		cFrag  *CFrag
+		want   string
+	}{
+		{name: "by others", sender: reader, cFrag: cFrag,
+			want: "only the designated re-encryption service can fulfill the request"},
+		{name: "wrong cfrag", sender: proxy, cFrag: wrongFrag, want: "invalid cfrag"},
+		{name: "forged cfrag", sender: proxy, cFrag: &forgedFrag, want: "invalid cfrag"},
+		{name: "fulfill", sender: proxy, cFrag: cFrag},
+		{name: "fulfill twice", sender: proxy, cFrag: cFrag, want: "the request is fulfilled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := invoke(step{sender: tt.sender, timestamp: 1000, method: pre_fulfill_func_name,
+				args: map[string]string{REQUEST_ID: request.RequestId, CFRAG: toJson(t, tt.cFrag)}})
+			if (resp.Status == sdk.OK) != (len(tt.want) == 0) || !strings.HasSuffix(resp.Message, tt.want) {
+				t.Errorf("fulfill = %v, want %s", resp, tt.want)
+			}
+		})
+	}
+	resp = invoke(step{sender: reader, timestamp: 1000, method: pre_get_request_func_name,
+		args: map[string]string{REQUEST_ID: request.RequestId}})
+	if err := json.Unmarshal(resp.Payload, &request); err != nil || request.Fulfiller != proxy ||
+		request.CFrag.E1 != cFrag.E1 || *request.CFrag.Proof != *cFrag.Proof {
+		t.Errorf("get access = %v", resp)
+	}
+
+	// expiry and revocation of the grant
+	steps = []step{
+		{name: "request expired", sender: reader, timestamp: 2000, method: pre_request_func_name,
+			args: map[string]string{DATA_KEY: "d1"}, want: "the grant is expired"},
+		{name: "revoke", sender: owner, timestamp: 1000, method: pre_revoke_func_name,
+			args: map[string]string{DATA_KEY: "d1", READER: reader}},
+		{name: "request revoked", sender: reader, timestamp: 1000, method: pre_request_func_name,
+			args: map[string]string{DATA_KEY: "d1"}, want: "the reader is not authorized"},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			if resp := invoke(s); (resp.Status == sdk.OK) != (len(s.want) == 0) ||
+				!strings.HasSuffix(resp.Message, s.want) {
+				t.Errorf("%s() = %v, want %s", s.method, resp, s.want)
+			}
+		})
+	}
+
+	// audit trail
+	resp = invoke(step{sender: owner, timestamp: 1000, method: pre_get_audit_func_name,
+		args: map[string]string{DATA_KEY: "d1", OFFSET: "1"}})
+	var audits []*PreAudit
+	if err := json.Unmarshal(resp.Payload, &audits); err != nil {
+		t.Fatalf("audit = %v", resp)
+	}
+	var actions []string
+	for _, audit := range audits {
+		actions = append(actions, audit.Action+":"+audit.Operator)
+	}
+	want := "request:" + reader + ",fulfill:" + proxy + ",revoke:" + owner
+	if strings.Join(actions, ",") != want {
+		t.Errorf("audit = %v, want %s", actions, want)
+	}
+}
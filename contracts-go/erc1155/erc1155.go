@@ -57,6 +57,7 @@ const (
 )
 
 var _ IERC1155 = (*ERC1155Contract)(nil)
+var _ sdk.EventSchemaDeclarer = (*ERC1155Contract)(nil)
 
 // ERC1155Contract erc1155的一种实现
 type ERC1155Contract struct {
@@ -97,7 +98,7 @@ func (e *ERC1155Contract) initContract(adminAddresses []string, uri string) prot
 	if err != nil {
 		return sdk.Error("set user count of contractInfo failed")
 	}
-	sdk.Instance.EmitEvent("AlterAdminAddress", adminAddresses)
+	sdk.Instance.EmitEvent(eventAlterAdminAddress, adminAddresses)
 	return sdk.Success([]byte("Init contract success"))
 }
 
@@ -335,7 +336,7 @@ func (e *ERC1155Contract) AlterAdminAddress(adminAddress []string) protogo.Respo
 	if err != nil {
 		return sdk.Error("alter admin address of identityInfo failed." + err.Error())
 	}
-	sdk.Instance.EmitEvent("AlterAdminAddress", adminAddress)
+	sdk.Instance.EmitEvent(eventAlterAdminAddress, adminAddress)
 	return successNormal()
 }
 
@@ -350,7 +351,10 @@ func (e *ERC1155Contract) SafeTransferFrom(from, to Address, id *safemath.SafeUi
 	}
 
 	// emit event
-	sdk.Instance.EmitEvent("SafeTransferFrom", []string{string(from), string(to), id.ToString(), value.ToString()})
+	err = sdk.EmitTypedEvent(eventSafeTransferFrom, string(from), string(to), id.ToString(), value.ToString())
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
 	return successNormal()
 }
 
@@ -372,7 +376,10 @@ func (e *ERC1155Contract) SafeBatchTransferFrom(from, to Address, ids, values []
 	// emit event
 	idsStr, _ := json.Marshal(ids)
 	valuesStr, _ := json.Marshal(values)
-	sdk.Instance.EmitEvent("SafeTransferFrom", []string{string(from), string(to), string(idsStr), string(valuesStr)})
+	err := sdk.EmitTypedEvent(eventSafeTransferFrom, string(from), string(to), string(idsStr), string(valuesStr))
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
 
 	return successNormal()
 }
@@ -491,7 +498,10 @@ func (e *ERC1155Contract) SetApprovalForAll(sender, operator Address, approved b
 		return sdk.Error("set ApprovalForAll error, " + err.Error())
 	}
 
-	sdk.Instance.EmitEvent("ApprovalForAll", []string{string(sender), string(operator), approvedStr})
+	err = sdk.EmitTypedEvent(eventApprovalForAll, string(sender), string(operator), approvedStr)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
 
 	return successNormal()
 }
@@ -605,7 +615,10 @@ func (e *ERC1155Contract) Mint(to Address, id, amount *safemath.SafeUint256, dat
 	// set owner only nft valid
 	_ = sdk.Instance.PutState("owner", id.ToString(), string(to))
 	// emit event
-	sdk.Instance.EmitEvent("Mint", []string{string(to), id.ToString(), amount.ToString()})
+	err = sdk.EmitTypedEvent(eventMint, string(to), id, amount)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
 	// set the number of token transactions
 	tokenInfo := maps.getTokenMap()
 	_ = tokenInfo.Set([]string{id.ToString()}, []byte("0"))
@@ -641,9 +654,10 @@ func (e *ERC1155Contract) MintBatch(to Address, ids, amounts []*safemath.SafeUin
 		amountStrArr = append(amountStrArr, amount.ToString())
 	}
 	// emit event
-	i, _ := json.Marshal(idStrArr)
-	a, _ := json.Marshal(amountStrArr)
-	sdk.Instance.EmitEvent("MintBatch", []string{string(to), string(i), string(a)})
+	err := sdk.EmitTypedEvent(eventMintBatch, string(to), idStrArr, amountStrArr)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
 
 	return successNormal()
 }
@@ -0,0 +1,60 @@
+/**
+  Copyright (C) BABEC. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	// eventAlterAdminAddress has the admin addresses as data, one item each, it has no schema
+	// as the number of items varies
+	eventAlterAdminAddress = "AlterAdminAddress"
+	eventSafeTransferFrom  = "SafeTransferFrom"
+	eventApprovalForAll    = "ApprovalForAll"
+	eventMint              = "Mint"
+	eventMintBatch         = "MintBatch"
+)
+
+// erc1155EventSchemas schemas of the events emitted by erc1155, saved on chain when the contract is installed.
+// The data of the events are the same as before the schemas were declared, so existing subscribers keep working.
+var erc1155EventSchemas = []*sdk.EventSchema{
+	// SafeBatchTransferFrom emits the topic as well, with id and value the json arrays of the ids and values
+	sdk.NewEventSchema(eventSafeTransferFrom,
+		sdk.NewEventField("from", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("id", sdk.EventFieldString, true),
+		sdk.NewEventField("value", sdk.EventFieldString, false),
+	),
+	// approved is "1" or "0"
+	sdk.NewEventSchema(eventApprovalForAll,
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+		sdk.NewEventField("operator", sdk.EventFieldAddress, true),
+		sdk.NewEventField("approved", sdk.EventFieldString, false),
+	),
+	sdk.NewEventSchema(eventMint,
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("id", sdk.EventFieldUint256, true),
+		sdk.NewEventField("amount", sdk.EventFieldUint256, false),
+	),
+	sdk.NewEventSchema(eventMintBatch,
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("ids", sdk.EventFieldJSON, false),
+		sdk.NewEventField("amounts", sdk.EventFieldJSON, false),
+	),
+}
+
+func init() {
+	if err := sdk.RegisterEventSchemas(erc1155EventSchemas...); err != nil {
+		panic(err)
+	}
+}
+
+// EventSchemas returns the event schemas of erc1155
+func (e *ERC1155Contract) EventSchemas() []*sdk.EventSchema {
+	return erc1155EventSchemas
+}
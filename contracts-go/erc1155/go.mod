@@ -3,14 +3,14 @@ module chainmaker.org/chainmaker/contracts/contracts-go/erc721
 go 1.17
 
 require (
-	chainmaker.org/chainmaker/common/v2 v2.3.0
+	chainmaker.org/chainmaker/common/v2 v2.3.5
 	chainmaker.org/chainmaker/contract-sdk-go/v2 v2.3.3
 	chainmaker.org/chainmaker/contract-utils v1.0.2
 )
 
 require (
-	chainmaker.org/chainmaker/pb-go/v2 v2.3.0 // indirect
-	chainmaker.org/chainmaker/protocol/v2 v2.3.0 // indirect
+	chainmaker.org/chainmaker/pb-go/v2 v2.3.6 // indirect
+	chainmaker.org/chainmaker/protocol/v2 v2.3.6 // indirect
 	github.com/dgryski/go-metro v0.0.0-20200812162917-85c65e2d0165 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/golang/mock v1.6.0 // indirect
@@ -33,3 +33,5 @@ require (
 	google.golang.org/grpc v1.41.1 // indirect
 	google.golang.org/protobuf v1.27.1 // indirect
 )
+
+replace chainmaker.org/chainmaker/contract-sdk-go/v2 => ../../contract-sdk-go
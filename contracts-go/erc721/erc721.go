@@ -161,6 +161,7 @@ const (
 )
 
 var _ IERC721 = (*ERC721Contract)(nil)
+var _ sdk.EventSchemaDeclarer = (*ERC721Contract)(nil)
 
 // ERC721Contract contract for erc721
 type ERC721Contract struct {
@@ -412,7 +413,10 @@ func (c *ERC721Contract) approve(to string, tokenId *safemath.SafeUint256) proto
 		return sdk.Error(fmt.Sprintf("set owner failed, err:%s", err))
 	}
 
-	sdk.Instance.EmitEvent("approve", []string{owner, to, tokenId.ToString()})
+	err = sdk.EmitTypedEvent(eventApprove, owner, to, tokenId)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
 
 	return sdk.Success([]byte("approve success"))
 }
@@ -452,7 +456,10 @@ func (c *ERC721Contract) setApprovalForAll(operator string, approved bool) proto
 	if err != nil {
 		return sdk.Error(fmt.Sprintf("set operator approve failed, err:%s", err))
 	}
-	sdk.Instance.EmitEvent("ApprovalForAll", []string{sender, operator, approvedStr})
+	err = sdk.EmitTypedEvent(eventApprovalForAll, sender, operator, approvedStr)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
 
 	return sdk.Success([]byte("setApprovalForAll success"))
 }
@@ -809,7 +816,10 @@ func (c *ERC721Contract) transfer(from, to string, tokenId *safemath.SafeUint256
 		return sdk.Error(err.Error())
 	}
 
-	sdk.Instance.EmitEvent("transfer", []string{from, to, tokenId.ToString()})
+	err = sdk.EmitTypedEvent(eventTransfer, from, to, tokenId)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
 
 	return sdk.Success([]byte("transfer success"))
 }
@@ -0,0 +1,49 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	eventApprove        = "approve"
+	eventApprovalForAll = "ApprovalForAll"
+	eventTransfer       = "transfer"
+)
+
+// erc721EventSchemas schemas of the events emitted by erc721, saved on chain when the contract is installed
+var erc721EventSchemas = []*sdk.EventSchema{
+	sdk.NewEventSchema(eventApprove,
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("tokenId", sdk.EventFieldUint256, true),
+	),
+	// approved is "1" or "0", as before the schemas were declared
+	sdk.NewEventSchema(eventApprovalForAll,
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+		sdk.NewEventField("operator", sdk.EventFieldAddress, true),
+		sdk.NewEventField("approved", sdk.EventFieldString, false),
+	),
+	sdk.NewEventSchema(eventTransfer,
+		sdk.NewEventField("from", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("tokenId", sdk.EventFieldUint256, true),
+	),
+}
+
+func init() {
+	if err := sdk.RegisterEventSchemas(erc721EventSchemas...); err != nil {
+		panic(err)
+	}
+}
+
+// EventSchemas returns the event schemas of erc721
+func (c *ERC721Contract) EventSchemas() []*sdk.EventSchema {
+	return erc721EventSchemas
+}
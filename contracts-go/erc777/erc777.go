@@ -0,0 +1,603 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+ERC777 Token Standard
+https://eips.ethereum.org/EIPS/eip-777
+
+The ERC1820 registry of the standard is replaced by a registry kept by the contract itself,
+an account sets the contracts called as its tokensToSend and tokensReceived hooks by
+SetTokensSender and SetTokensRecipient, the hooks are called by CallContract.
+*/
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/contract-utils/address"
+	"chainmaker.org/chainmaker/contract-utils/safemath"
+)
+
+const (
+	//db key
+	balanceKey          = "b"
+	operatorKey         = "o"
+	revokedDefaultKey   = "r"
+	senderHookKey       = "hs"
+	recipientHookKey    = "hr"
+	totalSupplyKey      = "totalSupplyKey"
+	nameKey             = "name"
+	symbolKey           = "symbol"
+	granularityKey      = "granularity"
+	defaultOperatorsKey = "defaultOperators"
+	adminKey            = "admin"
+
+	trueString = "1"
+
+	// hook methods called on the hook contracts
+	tokensToSendMethod   = "tokensToSend"
+	tokensReceivedMethod = "tokensReceived"
+)
+
+var (
+	defaultName        = "TestToken"
+	defaultSymbol      = "TT"
+	defaultGranularity = uint64(1)
+	defaultTotalSupply = safemath.SafeUintZero
+)
+
+// ERC777Contract erc777 contract
+type ERC777Contract struct {
+}
+
+// Name Token的名字
+// @return string
+// @return error
+func (c *ERC777Contract) Name() (string, error) {
+	return sdk.Instance.GetState(nameKey, "")
+}
+
+// Symbol Token的符号
+// @return string
+// @return error
+func (c *ERC777Contract) Symbol() (string, error) {
+	return sdk.Instance.GetState(symbolKey, "")
+}
+
+// Granularity 最小不可分割单位，铸造、发送、销毁的数量必须是它的整数倍
+// @return uint64
+// @return error
+func (c *ERC777Contract) Granularity() (uint64, error) {
+	val, err := sdk.Instance.GetState(granularityKey, "")
+	if err != nil {
+		return 0, err
+	}
+	if len(val) == 0 {
+		return defaultGranularity, nil
+	}
+	return strconv.ParseUint(val, 10, 64)
+}
+
+// TotalSupply 发行总量
+// @return *safemath.SafeUint256
+// @return error
+func (c *ERC777Contract) TotalSupply() (*safemath.SafeUint256, error) {
+	return c.GetUint256(totalSupplyKey, "")
+}
+
+// BalanceOf 账户余额查询
+// @param account
+// @return *safemath.SafeUint256
+// @return error
+func (c *ERC777Contract) BalanceOf(account string) (*safemath.SafeUint256, error) {
+	return c.GetBalance(account)
+}
+
+// DefaultOperators 默认操作员，可以操作所有账户的Token，除非账户撤销了授权
+// @return []string
+// @return error
+func (c *ERC777Contract) DefaultOperators() ([]string, error) {
+	val, err := sdk.Instance.GetState(defaultOperatorsKey, "")
+	if err != nil {
+		return nil, err
+	}
+	operators := []string{}
+	if len(val) == 0 {
+		return operators, nil
+	}
+	if err = json.Unmarshal([]byte(val), &operators); err != nil {
+		return nil, fmt.Errorf("unmarshal default operators failed, err:%s", err)
+	}
+	return operators, nil
+}
+
+// IsOperatorFor 查询operator是否为holder的操作员，账户总是自己的操作员
+// @param operator
+// @param holder
+// @return bool
+// @return error
+func (c *ERC777Contract) IsOperatorFor(operator, holder string) (bool, error) {
+	if operator == holder {
+		return true, nil
+	}
+	isDefault, err := c.isDefaultOperator(operator)
+	if err != nil {
+		return false, err
+	}
+	if isDefault {
+		revoked, err1 := sdk.Instance.GetState(revokedDefaultKey, createCompositeKey(holder, operator))
+		if err1 != nil {
+			return false, err1
+		}
+		return revoked != trueString, nil
+	}
+	authorized, err := sdk.Instance.GetState(operatorKey, createCompositeKey(holder, operator))
+	if err != nil {
+		return false, err
+	}
+	return authorized == trueString, nil
+}
+
+// AuthorizeOperator 授权operator为调用者的操作员
+// @param operator
+// @return error
+func (c *ERC777Contract) AuthorizeOperator(operator string) error {
+	holder, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	if operator == holder {
+		return errors.New("ERC777: authorizing self as operator")
+	}
+	isDefault, err := c.isDefaultOperator(operator)
+	if err != nil {
+		return err
+	}
+	if isDefault {
+		err = sdk.Instance.DelState(revokedDefaultKey, createCompositeKey(holder, operator))
+	} else {
+		err = sdk.Instance.PutState(operatorKey, createCompositeKey(holder, operator), trueString)
+	}
+	if err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventAuthorizedOperator, operator, holder)
+}
+
+// RevokeOperator 撤销operator作为调用者操作员的授权，包括默认操作员
+// @param operator
+// @return error
+func (c *ERC777Contract) RevokeOperator(operator string) error {
+	holder, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	if operator == holder {
+		return errors.New("ERC777: revoking self as operator")
+	}
+	isDefault, err := c.isDefaultOperator(operator)
+	if err != nil {
+		return err
+	}
+	if isDefault {
+		err = sdk.Instance.PutState(revokedDefaultKey, createCompositeKey(holder, operator), trueString)
+	} else {
+		err = sdk.Instance.DelState(operatorKey, createCompositeKey(holder, operator))
+	}
+	if err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventRevokedOperator, operator, holder)
+}
+
+// Send 调用者发送Token给to，data为持有者附加数据
+// @param to
+// @param amount
+// @param data
+// @return error
+func (c *ERC777Contract) Send(to string, amount *safemath.SafeUint256, data []byte) error {
+	from, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	return c.baseSend(from, from, to, amount, data, nil)
+}
+
+// OperatorSend 操作员代from发送Token给to，operatorData为操作员附加数据
+// @param from
+// @param to
+// @param amount
+// @param data
+// @param operatorData
+// @return error
+func (c *ERC777Contract) OperatorSend(from, to string, amount *safemath.SafeUint256,
+	data, operatorData []byte) error {
+	operator, err := c.requireOperatorFor(from)
+	if err != nil {
+		return err
+	}
+	return c.baseSend(operator, from, to, amount, data, operatorData)
+}
+
+// Burn 调用者销毁Token
+// @param amount
+// @param data
+// @return error
+func (c *ERC777Contract) Burn(amount *safemath.SafeUint256, data []byte) error {
+	from, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	return c.baseBurn(from, from, amount, data, nil)
+}
+
+// OperatorBurn 操作员代from销毁Token
+// @param from
+// @param amount
+// @param data
+// @param operatorData
+// @return error
+func (c *ERC777Contract) OperatorBurn(from string, amount *safemath.SafeUint256, data, operatorData []byte) error {
+	operator, err := c.requireOperatorFor(from)
+	if err != nil {
+		return err
+	}
+	return c.baseBurn(operator, from, amount, data, operatorData)
+}
+
+// Mint 管理员铸造Token给to
+// @param to
+// @param amount
+// @param data
+// @param operatorData
+// @return error
+func (c *ERC777Contract) Mint(to string, amount *safemath.SafeUint256, data, operatorData []byte) error {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	admin, err := c.GetAdmin()
+	if err != nil {
+		return err
+	}
+	if sender != admin {
+		return errors.New("only admin can mint tokens")
+	}
+	return c.baseMint(sender, to, amount, data, operatorData)
+}
+
+// SetTokensSender 设置调用者账户发出Token前调用的hook合约，hook为空则取消
+// @param hook
+// @return error
+func (c *ERC777Contract) SetTokensSender(hook string) error {
+	return c.setHook(senderHookKey, hook)
+}
+
+// SetTokensRecipient 设置调用者账户收到Token后调用的hook合约，hook为空则取消
+// @param hook
+// @return error
+func (c *ERC777Contract) SetTokensRecipient(hook string) error {
+	return c.setHook(recipientHookKey, hook)
+}
+
+// GetTokensSender 查询账户发出Token前调用的hook合约
+// @param account
+// @return string
+// @return error
+func (c *ERC777Contract) GetTokensSender(account string) (string, error) {
+	return sdk.Instance.GetState(senderHookKey, account)
+}
+
+// GetTokensRecipient 查询账户收到Token后调用的hook合约
+// @param account
+// @return string
+// @return error
+func (c *ERC777Contract) GetTokensRecipient(account string) (string, error) {
+	return sdk.Instance.GetState(recipientHookKey, account)
+}
+
+/////////////////////////Data Access Layer/////////////////////////////////
+
+func createCompositeKey(data ...string) string {
+	return strings.Join(data, "_")
+}
+
+// GetUint256 获得DB中的SafeUint256
+// @param key
+// @param field
+// @return *safemath.SafeUint256
+// @return error
+func (c *ERC777Contract) GetUint256(key, field string) (*safemath.SafeUint256, error) {
+	val, err := sdk.Instance.GetState(key, field)
+	if err != nil {
+		return nil, err
+	}
+	if len(val) == 0 {
+		return safemath.SafeUintZero, nil
+	}
+	num, ok := safemath.ParseSafeUint256(val)
+	if !ok {
+		return nil, errors.New("invalid uint256 data")
+	}
+	return num, nil
+}
+
+// GetBalance 获得DB中的账户余额
+// @param account
+// @return *safemath.SafeUint256
+// @return error
+func (c *ERC777Contract) GetBalance(account string) (*safemath.SafeUint256, error) {
+	return c.GetUint256(balanceKey, account)
+}
+
+// SetBalance 设置DB中的账户余额
+// @param account
+// @param amount
+// @return error
+func (c *ERC777Contract) SetBalance(account string, amount *safemath.SafeUint256) error {
+	return sdk.Instance.PutState(balanceKey, account, amount.ToString())
+}
+
+// SetTotalSupply 设置发行总量
+// @param amount
+// @return error
+func (c *ERC777Contract) SetTotalSupply(amount *safemath.SafeUint256) error {
+	return sdk.Instance.PutState(totalSupplyKey, "", amount.ToString())
+}
+
+// GetAdmin 获得DB中的Admin
+// @return string
+// @return error
+func (c *ERC777Contract) GetAdmin() (string, error) {
+	return sdk.Instance.GetState(adminKey, "")
+}
+
+func (c *ERC777Contract) isDefaultOperator(operator string) (bool, error) {
+	operators, err := c.DefaultOperators()
+	if err != nil {
+		return false, err
+	}
+	for _, o := range operators {
+		if o == operator {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *ERC777Contract) setHook(key, hook string) error {
+	account, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	if len(hook) == 0 {
+		return sdk.Instance.DelState(key, account)
+	}
+	return sdk.Instance.PutState(key, account, hook)
+}
+
+////////////////////////////ERC777 Core/////////////////////////////
+
+func (c *ERC777Contract) requireOperatorFor(holder string) (string, error) {
+	operator, err := sdk.Instance.Sender()
+	if err != nil {
+		return "", fmt.Errorf("Get sender address failed, err:%s", err)
+	}
+	isOperator, err := c.IsOperatorFor(operator, holder)
+	if err != nil {
+		return "", err
+	}
+	if !isOperator {
+		return "", errors.New("ERC777: caller is not an operator for holder")
+	}
+	return operator, nil
+}
+
+func (c *ERC777Contract) checkGranularity(amount *safemath.SafeUint256) error {
+	granularity, err := c.Granularity()
+	if err != nil {
+		return err
+	}
+	if granularity <= 1 {
+		return nil
+	}
+	num, ok := new(big.Int).SetString(amount.ToString(), 10)
+	if !ok {
+		return errors.New("invalid amount")
+	}
+	if new(big.Int).Mod(num, new(big.Int).SetUint64(granularity)).Sign() != 0 {
+		return fmt.Errorf("ERC777: amount is not a multiple of granularity %d", granularity)
+	}
+	return nil
+}
+
+// callTokensToSend 调用from的tokensToSend hook，在余额变化之前调用，hook返回错误则交易失败
+func (c *ERC777Contract) callTokensToSend(operator, from, to string, amount *safemath.SafeUint256,
+	data, operatorData []byte) error {
+	hook, err := c.GetTokensSender(from)
+	if err != nil {
+		return err
+	}
+	if len(hook) == 0 {
+		return nil
+	}
+	return c.callHook(hook, tokensToSendMethod, operator, from, to, amount, data, operatorData)
+}
+
+// callTokensReceived 调用to的tokensReceived hook，在余额变化之后调用，hook返回错误则交易失败
+func (c *ERC777Contract) callTokensReceived(operator, from, to string, amount *safemath.SafeUint256,
+	data, operatorData []byte) error {
+	hook, err := c.GetTokensRecipient(to)
+	if err != nil {
+		return err
+	}
+	if len(hook) == 0 {
+		return nil
+	}
+	return c.callHook(hook, tokensReceivedMethod, operator, from, to, amount, data, operatorData)
+}
+
+func (c *ERC777Contract) callHook(hook, method, operator, from, to string, amount *safemath.SafeUint256,
+	data, operatorData []byte) error {
+	resp := sdk.Instance.CallContract(hook, method, map[string][]byte{
+		pOperator:     []byte(operator),
+		pFrom:         []byte(from),
+		pTo:           []byte(to),
+		pAmount:       []byte(amount.ToString()),
+		pData:         data,
+		pOperatorData: operatorData,
+	})
+	if resp.Status != sdk.OK {
+		return fmt.Errorf("ERC777: %s hook %s failed, %s", method, hook, resp.Message)
+	}
+	return nil
+}
+
+func (c *ERC777Contract) checkAccount(account, action string) error {
+	if !address.IsValidAddress(account) {
+		return fmt.Errorf("ERC777: %s the invalid address", action)
+	}
+	if address.IsZeroAddress(account) {
+		return fmt.Errorf("ERC777: %s the zero address", action)
+	}
+	return nil
+}
+
+func (c *ERC777Contract) baseSend(operator, from, to string, amount *safemath.SafeUint256,
+	data, operatorData []byte) error {
+	if err := c.checkAccount(from, "send from"); err != nil {
+		return err
+	}
+	if err := c.checkAccount(to, "send to"); err != nil {
+		return err
+	}
+	if err := c.checkGranularity(amount); err != nil {
+		return err
+	}
+	if err := c.callTokensToSend(operator, from, to, amount, data, operatorData); err != nil {
+		return err
+	}
+
+	//检查from余额充足
+	fromBalance, err := c.GetBalance(from)
+	if err != nil {
+		return err
+	}
+	if !fromBalance.GTE(amount) {
+		return errors.New("ERC777: transfer amount exceeds balance")
+	}
+	//更新from和to的余额
+	fromNewBalance, _ := safemath.SafeSub(fromBalance, amount)
+	if err = c.SetBalance(from, fromNewBalance); err != nil {
+		return err
+	}
+	toBalance, err := c.GetBalance(to)
+	if err != nil {
+		return err
+	}
+	toNewBalance, ok := safemath.SafeAdd(toBalance, amount)
+	if !ok {
+		return errors.New("calculate new to balance error")
+	}
+	if err = c.SetBalance(to, toNewBalance); err != nil {
+		return err
+	}
+	//触发事件
+	if err = sdk.EmitTypedEvent(eventSent, operator, from, to, amount, data, operatorData); err != nil {
+		return err
+	}
+
+	return c.callTokensReceived(operator, from, to, amount, data, operatorData)
+}
+
+func (c *ERC777Contract) baseMint(operator, to string, amount *safemath.SafeUint256,
+	data, operatorData []byte) error {
+	if err := c.checkAccount(to, "mint to"); err != nil {
+		return err
+	}
+	if err := c.checkGranularity(amount); err != nil {
+		return err
+	}
+
+	//更新TotalSupply
+	totalSupply, err := c.TotalSupply()
+	if err != nil {
+		return err
+	}
+	newTotal, ok := safemath.SafeAdd(totalSupply, amount)
+	if !ok {
+		return errors.New("calculate totalSupply failed")
+	}
+	if err = c.SetTotalSupply(newTotal); err != nil {
+		return err
+	}
+	//更新余额
+	toBalance, err := c.GetBalance(to)
+	if err != nil {
+		return err
+	}
+	toNewBalance, ok := safemath.SafeAdd(toBalance, amount)
+	if !ok {
+		return errors.New("calculate new to balance error")
+	}
+	if err = c.SetBalance(to, toNewBalance); err != nil {
+		return err
+	}
+	//触发事件
+	if err = sdk.EmitTypedEvent(eventMinted, operator, to, amount, data, operatorData); err != nil {
+		return err
+	}
+
+	return c.callTokensReceived(operator, address.ZeroAddr, to, amount, data, operatorData)
+}
+
+func (c *ERC777Contract) baseBurn(operator, from string, amount *safemath.SafeUint256,
+	data, operatorData []byte) error {
+	if err := c.checkAccount(from, "burn from"); err != nil {
+		return err
+	}
+	if err := c.checkGranularity(amount); err != nil {
+		return err
+	}
+	if err := c.callTokensToSend(operator, from, address.ZeroAddr, amount, data, operatorData); err != nil {
+		return err
+	}
+
+	//检查用户余额充足
+	fromBalance, err := c.GetBalance(from)
+	if err != nil {
+		return err
+	}
+	if !fromBalance.GTE(amount) {
+		return errors.New("ERC777: burn amount exceeds balance")
+	}
+	//更新TotalSupply
+	totalSupply, err := c.TotalSupply()
+	if err != nil {
+		return err
+	}
+	newTotal, ok := safemath.SafeSub(totalSupply, amount)
+	if !ok {
+		return errors.New("calculate totalSupply failed")
+	}
+	if err = c.SetTotalSupply(newTotal); err != nil {
+		return err
+	}
+	//更新余额
+	fromNewBalance, _ := safemath.SafeSub(fromBalance, amount)
+	if err = c.SetBalance(from, fromNewBalance); err != nil {
+		return err
+	}
+	//触发事件
+	return sdk.EmitTypedEvent(eventBurned, operator, from, amount, data, operatorData)
+}
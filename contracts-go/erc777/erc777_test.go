@@ -0,0 +1,335 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	admin         = "ec47ae0f0d6a0e952c240383d70ab43b19997a9f"
+	alice         = "a1c2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0"
+	bob           = "b0b1b2b3b4b5b6b7b8b9b0b1b2b3b4b5b6b7b8b9"
+	defaultOp     = "d0d1d2d3d4d5d6d7d8d9d0d1d2d3d4d5d6d7d8d9"
+	hookContract  = "hook"
+	rejectingHook = "rejectingHook"
+)
+
+// mockGetPutState keeps the state of a test in the returned map
+func mockGetPutState(mockInstance *sdk.MockSDKInterface) map[string]string {
+	stateMap := make(map[string]string)
+	mockInstance.EXPECT().PutState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(k string, f string, val string) error {
+			stateMap[k+"#"+f] = val
+			return nil
+		})
+	mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(k string, f string) (string, error) {
+			return stateMap[k+"#"+f], nil
+		})
+	mockInstance.EXPECT().DelState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(k string, f string) error {
+			delete(stateMap, k+"#"+f)
+			return nil
+		})
+	return stateMap
+}
+
+// caller sender and args of the next invocation in a test
+type caller struct {
+	sender string
+	args   map[string][]byte
+}
+
+func mockCaller(mockInstance *sdk.MockSDKInterface) *caller {
+	cl := &caller{sender: admin, args: make(map[string][]byte)}
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return cl.args })
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return cl.sender, nil })
+	mockInstance.EXPECT().Origin().AnyTimes().DoAndReturn(func() (string, error) { return cl.sender, nil })
+	return cl
+}
+
+func (cl *caller) invoke(c *ERC777Contract, from, method string, args map[string]string) protogo.Response {
+	cl.sender = from
+	cl.args = make(map[string][]byte)
+	for k, v := range args {
+		cl.args[k] = []byte(v)
+	}
+	return c.InvokeContract(method)
+}
+
+func (cl *caller) initContract(t *testing.T, c *ERC777Contract) {
+	cl.sender = admin
+	cl.args = map[string][]byte{
+		pName:             []byte("Token777"),
+		pSymbol:           []byte("T7"),
+		pGranularity:      []byte("10"),
+		pDefaultOperators: []byte(`["` + defaultOp + `"]`),
+		pTotalSupply:      []byte("1000"),
+	}
+	if got := c.InitContract(); got.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", got)
+	}
+}
+
+func assertResponse(t *testing.T, got protogo.Response, status int32, payload string) {
+	t.Helper()
+	if got.Status != status || (status == sdk.OK && string(got.Payload) != payload) ||
+		(status != sdk.OK && got.Message != payload) {
+		t.Errorf("response = %v, want status %d and %s", got, status, payload)
+	}
+}
+
+func TestERC777Contract_InitContract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+	mockGetPutState(mockInstance)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
+	cl := mockCaller(mockInstance)
+	sdk.Instance = mockInstance
+
+	c := &ERC777Contract{}
+	cl.initContract(t, c)
+
+	tests := []struct {
+		name   string
+		method string
+		args   map[string]string
+		want   string
+	}{
+		{"name", "Name", nil, "Token777"},
+		{"symbol", "Symbol", nil, "T7"},
+		{"granularity", "Granularity", nil, "10"},
+		{"totalSupply", "TotalSupply", nil, "1000"},
+		{"balanceOf", "BalanceOf", map[string]string{pAccount: admin}, "1000"},
+		{"defaultOperators", "DefaultOperators", nil, `["` + defaultOp + `"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertResponse(t, cl.invoke(c, admin, tt.method, tt.args), sdk.OK, tt.want)
+		})
+	}
+
+	cl.sender = admin
+	cl.args = map[string][]byte{pGranularity: []byte("0")}
+	assertResponse(t, c.InitContract(), sdk.ERROR, "param granularity should be a positive integer")
+}
+
+func TestERC777Contract_InvokeContract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+	mockGetPutState(mockInstance)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
+	cl := mockCaller(mockInstance)
+	sdk.Instance = mockInstance
+
+	tests := []struct {
+		name   string
+		method string
+		want   protogo.Response
+	}{
+		{
+			name:   "balanceOf",
+			method: "BalanceOf",
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "ERC777: require parameter:'account'",
+			},
+		},
+		{
+			name:   "send",
+			method: "Send",
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "ERC777: require parameter:'to'",
+			},
+		},
+		{
+			name:   "operatorSend",
+			method: "OperatorSend",
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "ERC777: require parameter:'from'",
+			},
+		},
+		{
+			name:   "burn",
+			method: "Burn",
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "ERC777: require parameter:'amount'",
+			},
+		},
+		{
+			name:   "authorizeOperator",
+			method: "AuthorizeOperator",
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "ERC777: require parameter:'operator'",
+			},
+		},
+		{
+			name:   "isOperatorFor",
+			method: "IsOperatorFor",
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "ERC777: require parameter:'operator'",
+			},
+		},
+		{
+			name:   "invalid",
+			method: "invalid",
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "Invalid method",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ERC777Contract{}
+			cl.initContract(t, c)
+			if got := cl.invoke(c, admin, tt.method, nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvokeContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestERC777Contract_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+	mockGetPutState(mockInstance)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
+	cl := mockCaller(mockInstance)
+	sdk.Instance = mockInstance
+
+	c := &ERC777Contract{}
+	cl.initContract(t, c)
+
+	assertResponse(t, cl.invoke(c, admin, "Send", map[string]string{pTo: alice, pAmount: "100", pData: "hi"}),
+		sdk.OK, "")
+	assertResponse(t, cl.invoke(c, admin, "BalanceOf", map[string]string{pAccount: admin}), sdk.OK, "900")
+	assertResponse(t, cl.invoke(c, admin, "BalanceOf", map[string]string{pAccount: alice}), sdk.OK, "100")
+
+	// amount not a multiple of granularity
+	assertResponse(t, cl.invoke(c, alice, "Send", map[string]string{pTo: bob, pAmount: "15"}),
+		sdk.ERROR, "ERC777: amount is not a multiple of granularity 10")
+	assertResponse(t, cl.invoke(c, alice, "Send", map[string]string{pTo: bob, pAmount: "200"}),
+		sdk.ERROR, "ERC777: transfer amount exceeds balance")
+	assertResponse(t, cl.invoke(c, alice, "Send", map[string]string{pTo: "bob", pAmount: "10"}),
+		sdk.ERROR, "ERC777: send to the invalid address")
+
+	// mint and burn
+	assertResponse(t, cl.invoke(c, alice, "Mint", map[string]string{pTo: alice, pAmount: "10"}),
+		sdk.ERROR, "only admin can mint tokens")
+	assertResponse(t, cl.invoke(c, admin, "Mint", map[string]string{pTo: bob, pAmount: "50"}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, bob, "Burn", map[string]string{pAmount: "20"}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, bob, "BalanceOf", map[string]string{pAccount: bob}), sdk.OK, "30")
+	assertResponse(t, cl.invoke(c, admin, "TotalSupply", nil), sdk.OK, "1030")
+}
+
+func TestERC777Contract_Operator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+	mockGetPutState(mockInstance)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
+	cl := mockCaller(mockInstance)
+	sdk.Instance = mockInstance
+
+	c := &ERC777Contract{}
+	cl.initContract(t, c)
+	assertResponse(t, cl.invoke(c, admin, "Send", map[string]string{pTo: alice, pAmount: "100"}), sdk.OK, "")
+
+	// bob is not an operator of alice until authorized
+	operatorSend := map[string]string{pFrom: alice, pTo: bob, pAmount: "10", pOperatorData: "fee"}
+	assertResponse(t, cl.invoke(c, bob, "OperatorSend", operatorSend),
+		sdk.ERROR, "ERC777: caller is not an operator for holder")
+	assertResponse(t, cl.invoke(c, alice, "AuthorizeOperator", map[string]string{pOperator: bob}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, alice, "IsOperatorFor", map[string]string{pOperator: bob, pHolder: alice}),
+		sdk.OK, "true")
+	assertResponse(t, cl.invoke(c, bob, "OperatorSend", operatorSend), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, alice, "RevokeOperator", map[string]string{pOperator: bob}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, bob, "OperatorSend", operatorSend),
+		sdk.ERROR, "ERC777: caller is not an operator for holder")
+	assertResponse(t, cl.invoke(c, alice, "AuthorizeOperator", map[string]string{pOperator: alice}),
+		sdk.ERROR, "ERC777: authorizing self as operator")
+
+	// default operators operate all accounts until revoked
+	operatorBurn := map[string]string{pFrom: alice, pAmount: "10"}
+	assertResponse(t, cl.invoke(c, defaultOp, "OperatorBurn", operatorBurn), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, alice, "RevokeOperator", map[string]string{pOperator: defaultOp}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, alice, "IsOperatorFor", map[string]string{pOperator: defaultOp, pHolder: alice}),
+		sdk.OK, "false")
+	assertResponse(t, cl.invoke(c, defaultOp, "OperatorBurn", operatorBurn),
+		sdk.ERROR, "ERC777: caller is not an operator for holder")
+	assertResponse(t, cl.invoke(c, alice, "AuthorizeOperator", map[string]string{pOperator: defaultOp}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, defaultOp, "OperatorBurn", operatorBurn), sdk.OK, "")
+
+	assertResponse(t, cl.invoke(c, alice, "BalanceOf", map[string]string{pAccount: alice}), sdk.OK, "70")
+	assertResponse(t, cl.invoke(c, alice, "BalanceOf", map[string]string{pAccount: bob}), sdk.OK, "10")
+}
+
+func TestERC777Contract_Hooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+	mockGetPutState(mockInstance)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
+	cl := mockCaller(mockInstance)
+	var hookArgs []map[string][]byte
+	mockInstance.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(contractName, method string, args map[string][]byte) protogo.Response {
+			if contractName == rejectingHook {
+				return sdk.Error("rejected")
+			}
+			args["hookMethod"] = []byte(method)
+			hookArgs = append(hookArgs, args)
+			return sdk.SuccessResponse
+		})
+	sdk.Instance = mockInstance
+
+	c := &ERC777Contract{}
+	cl.initContract(t, c)
+
+	assertResponse(t, cl.invoke(c, admin, "SetTokensSender", map[string]string{pHook: hookContract}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, alice, "SetTokensRecipient", map[string]string{pHook: hookContract}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, bob, "GetTokensRecipient", map[string]string{pAccount: alice}),
+		sdk.OK, hookContract)
+
+	assertResponse(t, cl.invoke(c, admin, "Send", map[string]string{pTo: alice, pAmount: "100", pData: "hi"}),
+		sdk.OK, "")
+	if len(hookArgs) != 2 {
+		t.Fatalf("hook called %d times, want 2", len(hookArgs))
+	}
+	for i, method := range []string{tokensToSendMethod, tokensReceivedMethod} {
+		if string(hookArgs[i]["hookMethod"]) != method || string(hookArgs[i][pFrom]) != admin ||
+			string(hookArgs[i][pTo]) != alice || string(hookArgs[i][pAmount]) != "100" ||
+			string(hookArgs[i][pData]) != "hi" {
+			t.Errorf("hook %d called with %v", i, hookArgs[i])
+		}
+	}
+
+	// the recipient rejects the tokens
+	assertResponse(t, cl.invoke(c, bob, "SetTokensRecipient", map[string]string{pHook: rejectingHook}), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, admin, "Send", map[string]string{pTo: bob, pAmount: "100"}),
+		sdk.ERROR, "ERC777: tokensReceived hook rejectingHook failed, rejected")
+
+	// the hook is removed by an empty hook
+	assertResponse(t, cl.invoke(c, bob, "SetTokensRecipient", nil), sdk.OK, "")
+	assertResponse(t, cl.invoke(c, admin, "Send", map[string]string{pTo: bob, pAmount: "100"}), sdk.OK, "")
+}
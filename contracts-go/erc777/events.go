@@ -0,0 +1,65 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	eventSent               = "Sent"
+	eventMinted             = "Minted"
+	eventBurned             = "Burned"
+	eventAuthorizedOperator = "AuthorizedOperator"
+	eventRevokedOperator    = "RevokedOperator"
+)
+
+// erc777EventSchemas schemas of the events emitted by erc777, saved on chain when the contract is installed
+var erc777EventSchemas = []*sdk.EventSchema{
+	sdk.NewEventSchema(eventSent,
+		sdk.NewEventField("operator", sdk.EventFieldAddress, true),
+		sdk.NewEventField("from", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("amount", sdk.EventFieldUint256, false),
+		sdk.NewEventField("data", sdk.EventFieldBytes, false),
+		sdk.NewEventField("operatorData", sdk.EventFieldBytes, false),
+	),
+	sdk.NewEventSchema(eventMinted,
+		sdk.NewEventField("operator", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("amount", sdk.EventFieldUint256, false),
+		sdk.NewEventField("data", sdk.EventFieldBytes, false),
+		sdk.NewEventField("operatorData", sdk.EventFieldBytes, false),
+	),
+	sdk.NewEventSchema(eventBurned,
+		sdk.NewEventField("operator", sdk.EventFieldAddress, true),
+		sdk.NewEventField("from", sdk.EventFieldAddress, true),
+		sdk.NewEventField("amount", sdk.EventFieldUint256, false),
+		sdk.NewEventField("data", sdk.EventFieldBytes, false),
+		sdk.NewEventField("operatorData", sdk.EventFieldBytes, false),
+	),
+	sdk.NewEventSchema(eventAuthorizedOperator,
+		sdk.NewEventField("operator", sdk.EventFieldAddress, true),
+		sdk.NewEventField("holder", sdk.EventFieldAddress, true),
+	),
+	sdk.NewEventSchema(eventRevokedOperator,
+		sdk.NewEventField("operator", sdk.EventFieldAddress, true),
+		sdk.NewEventField("holder", sdk.EventFieldAddress, true),
+	),
+}
+
+func init() {
+	if err := sdk.RegisterEventSchemas(erc777EventSchemas...); err != nil {
+		panic(err)
+	}
+}
+
+// EventSchemas returns the event schemas of erc777
+func (c *ERC777Contract) EventSchemas() []*sdk.EventSchema {
+	return erc777EventSchemas
+}
@@ -0,0 +1,359 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/contract-utils/address"
+	"chainmaker.org/chainmaker/contract-utils/safemath"
+)
+
+const (
+	pName             = "name"
+	pSymbol           = "symbol"
+	pGranularity      = "granularity"
+	pDefaultOperators = "defaultOperators"
+	pTotalSupply      = "totalSupply"
+	pAccount          = "account"
+	pAmount           = "amount"
+	pFrom             = "from"
+	pTo               = "to"
+	pOperator         = "operator"
+	pHolder           = "holder"
+	pHook             = "hook"
+	pData             = "data"
+	pOperatorData     = "operatorData"
+)
+
+func main() {
+	err := sandbox.Start(new(ERC777Contract))
+	if err != nil {
+		sdk.Instance.Errorf(err.Error())
+	}
+}
+
+// InitContract install contract func
+func (c *ERC777Contract) InitContract() protogo.Response {
+	err := c.updateErc777Info(true)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("Init contract success"))
+}
+
+// UpgradeContract upgrade contract func
+func (c *ERC777Contract) UpgradeContract() protogo.Response {
+	err := c.updateErc777Info(false)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("Upgrade contract success"))
+}
+
+// updateErc777Info save token info, granularity and default operators can only be set when installed
+func (c *ERC777Contract) updateErc777Info(install bool) error {
+	args := sdk.Instance.GetArgs()
+	// all args are optional
+	name := args[pName]
+	symbol := args[pSymbol]
+	granularityStr := args[pGranularity]
+	defaultOperatorsStr := args[pDefaultOperators]
+	totalSupply := args[pTotalSupply]
+
+	if len(name) > 0 {
+		defaultName = string(name)
+	}
+	if len(name) > 0 || install {
+		if err := sdk.Instance.PutState(nameKey, "", defaultName); err != nil {
+			return err
+		}
+	}
+	if len(symbol) > 0 {
+		defaultSymbol = string(symbol)
+	}
+	if len(symbol) > 0 || install {
+		if err := sdk.Instance.PutState(symbolKey, "", defaultSymbol); err != nil {
+			return err
+		}
+	}
+	if !install {
+		return nil
+	}
+
+	//granularity default to 1
+	granularity := defaultGranularity
+	if len(granularityStr) > 0 {
+		num, err := strconv.ParseUint(string(granularityStr), 10, 64)
+		if err != nil || num == 0 {
+			return errors.New("param granularity should be a positive integer")
+		}
+		granularity = num
+	}
+	if err := sdk.Instance.PutState(granularityKey, "", strconv.FormatUint(granularity, 10)); err != nil {
+		return err
+	}
+	if len(defaultOperatorsStr) > 0 {
+		var operators []string
+		if err := json.Unmarshal(defaultOperatorsStr, &operators); err != nil {
+			return fmt.Errorf("param defaultOperators should be a json array of addresses, err:%s", err)
+		}
+		for _, operator := range operators {
+			if !address.IsValidAddress(operator) {
+				return fmt.Errorf("invalid default operator %s", operator)
+			}
+		}
+		if err := sdk.Instance.PutState(defaultOperatorsKey, "", string(defaultOperatorsStr)); err != nil {
+			return err
+		}
+	}
+	//set admin
+	admin, err := sdk.Instance.Origin()
+	if err != nil {
+		return fmt.Errorf("get sender failed, err:%s", err)
+	}
+	if err = sdk.Instance.PutState(adminKey, "", admin); err != nil {
+		return err
+	}
+	//set totalSupply
+	if len(totalSupply) > 0 {
+		totalSupplyNum, ok := safemath.ParseSafeUint256(string(totalSupply))
+		if !ok {
+			return errors.New("invalid totalSupply number")
+		}
+		defaultTotalSupply = totalSupplyNum
+	}
+	//Mint
+	if defaultTotalSupply.ToString() != "0" {
+		return c.baseMint(admin, admin, defaultTotalSupply, nil, nil)
+	}
+	return nil
+}
+
+// InvokeContract the entry func of invoke contract func
+func (c *ERC777Contract) InvokeContract(method string) protogo.Response { // nolint:gocyclo
+	if len(method) == 0 {
+		return sdk.Error("method of param should not be empty")
+	}
+	args := sdk.Instance.GetArgs()
+	switch method {
+	case "Name":
+		return ReturnString(c.Name())
+	case "Symbol":
+		return ReturnString(c.Symbol())
+	case "Granularity":
+		return ReturnUint64(c.Granularity())
+	case "TotalSupply":
+		return ReturnUint256(c.TotalSupply())
+	case "BalanceOf":
+		account, err := RequireString(pAccount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnUint256(c.BalanceOf(account))
+	case "DefaultOperators":
+		operators, err := c.DefaultOperators()
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(operators)
+	case "IsOperatorFor":
+		operator, err := RequireString(pOperator)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		holder, err := RequireString(pHolder)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnBool(c.IsOperatorFor(operator, holder))
+	case "AuthorizeOperator":
+		operator, err := RequireString(pOperator)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.AuthorizeOperator(operator))
+	case "RevokeOperator":
+		operator, err := RequireString(pOperator)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.RevokeOperator(operator))
+	case "Send":
+		to, err := RequireString(pTo)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		amount, err := RequireUint256(pAmount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.Send(to, amount, args[pData]))
+	case "OperatorSend":
+		from, err := RequireString(pFrom)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		to, err := RequireString(pTo)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		amount, err := RequireUint256(pAmount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.OperatorSend(from, to, amount, args[pData], args[pOperatorData]))
+	case "Burn":
+		amount, err := RequireUint256(pAmount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.Burn(amount, args[pData]))
+	case "OperatorBurn":
+		from, err := RequireString(pFrom)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		amount, err := RequireUint256(pAmount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.OperatorBurn(from, amount, args[pData], args[pOperatorData]))
+	case "Mint":
+		to, err := RequireString(pTo)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		amount, err := RequireUint256(pAmount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.Mint(to, amount, args[pData], args[pOperatorData]))
+	case "SetTokensSender":
+		return Return(c.SetTokensSender(string(args[pHook])))
+	case "SetTokensRecipient":
+		return Return(c.SetTokensRecipient(string(args[pHook])))
+	case "GetTokensSender":
+		account, err := RequireString(pAccount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnString(c.GetTokensSender(account))
+	case "GetTokensRecipient":
+		account, err := RequireString(pAccount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnString(c.GetTokensRecipient(account))
+	default:
+		return sdk.Error("Invalid method")
+	}
+}
+
+////////////////////////////////Helper//////////////////////////////////
+
+// ReturnUint256 封装返回SafeUint256类型为Response，如果有error则忽略num，封装error
+// @param num
+// @param err
+// @return Response
+func ReturnUint256(num *safemath.SafeUint256, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(num.ToString()))
+}
+
+// ReturnUint64 封装返回uint64类型为Response，如果有error则忽略num，封装error
+// @param num
+// @param err
+// @return Response
+func ReturnUint64(num uint64, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatUint(num, 10)))
+}
+
+// ReturnString 封装返回string类型为Response，如果有error则忽略str，封装error
+// @param str
+// @param err
+// @return Response
+func ReturnString(str string, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(str))
+}
+
+// ReturnBool 封装返回bool类型为Response，如果有error则忽略b，封装error
+// @param b
+// @param err
+// @return Response
+func ReturnBool(b bool, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatBool(b)))
+}
+
+// ReturnJson 封装返回interface类型为json string Response
+// @param data
+// @return Response
+func ReturnJson(data interface{}) protogo.Response {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(bz)
+}
+
+// Return 封装返回Bool类型为Response，如果有error则忽略bool，封装error
+// @param err
+// @return Response
+func Return(err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.SuccessResponse
+}
+
+// RequireString 必须要有参数 string类型
+// @param key
+// @return string
+// @return error
+func RequireString(key string) (string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return "", fmt.Errorf("ERC777: require parameter:'%s'", key)
+	}
+	return string(b), nil
+}
+
+// RequireUint256 必须要有参数 Uint256类型
+// @param key
+// @return *safemath.SafeUint256
+// @return error
+func RequireUint256(key string) (*safemath.SafeUint256, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok {
+		return nil, fmt.Errorf("ERC777: require parameter:'%s'", key)
+	}
+	num, ok := safemath.ParseSafeUint256(string(b))
+	if !ok {
+		return nil, fmt.Errorf("ERC777: parameter:'%s' not a valid uint256", key)
+	}
+	return num, nil
+}
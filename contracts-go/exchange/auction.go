@@ -0,0 +1,191 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+// createEnglishAuction escrow the nfa token and open an english auction ending at endHeight, bids should be
+// at least the reserve price and raise the highest bid by minIncrementBps
+func (e *ExchangeContract) createEnglishAuction(nfa, tokenId, payToken, reservePrice, minIncrementBpsStr,
+	endHeight string) protogo.Response {
+	if len(endHeight) == 0 {
+		return Error("endHeight should not be empty")
+	}
+	order, err := e.newOrder(KindEnglish, nfa, tokenId, payToken, reservePrice, endHeight)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if len(minIncrementBpsStr) > 0 {
+		if order.MinIncrementBps, err = strconv.ParseUint(minIncrementBpsStr, 10, 64); err != nil {
+			return Error("minIncrementBps should be an integer")
+		}
+	}
+	return e.openAuction(order)
+}
+
+// createDutchAuction escrow the nfa token and open a dutch auction, the price declines linearly from
+// startPrice at the current height to endPrice at endHeight, endPrice should be positive so the token is
+// never sold for nothing
+func (e *ExchangeContract) createDutchAuction(nfa, tokenId, payToken, startPrice, endPriceStr,
+	endHeight string) protogo.Response {
+	if len(endHeight) == 0 {
+		return Error("endHeight should not be empty")
+	}
+	order, err := e.newOrder(KindDutch, nfa, tokenId, payToken, startPrice, endHeight)
+	if err != nil {
+		return Error(err.Error())
+	}
+	endPrice, ok := new(big.Int).SetString(endPriceStr, 10)
+	if !ok || endPrice.Sign() <= 0 || endPrice.Cmp(toBig(order.Price)) > 0 {
+		return Error("endPrice should be a positive integer not greater than startPrice")
+	}
+	order.EndPrice = endPrice.String()
+	return e.openAuction(order)
+}
+
+func (e *ExchangeContract) openAuction(order *Order) protogo.Response {
+	if err := e.putOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	e.emitOrderCreated(order)
+	if err := e.escrowNfa(order.Nfa, order.Maker, order.TokenId); err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatUint(order.Id, 10)))
+}
+
+// bidAuction escrow the bid of an english auction and refund the outbid bidder
+func (e *ExchangeContract) bidAuction(orderIdStr, amountStr string) protogo.Response {
+	order, height, err := e.getOpenOrder(orderIdStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if order.Kind != KindEnglish {
+		return Error(fmt.Sprintf("order %d is not an english auction", order.Id))
+	}
+	if expired(order, height) {
+		return Error(fmt.Sprintf("auction %d ended at height %d", order.Id, order.ExpireHeight))
+	}
+	bidder, err := sdk.Instance.Sender()
+	if err != nil {
+		return Error(err.Error())
+	}
+	if bidder == order.Maker {
+		return Error("maker can not bid its own auction")
+	}
+	amount, ok := new(big.Int).SetString(amountStr, 10)
+	if !ok {
+		return Error("amount should be an integer")
+	}
+	if minBid := minAuctionBid(order); amount.Cmp(minBid) < 0 {
+		return Error(fmt.Sprintf("amount should be at least %s", minBid))
+	}
+
+	prevBidder, prevBid := order.HighestBidder, toBig(order.HighestBid)
+	order.HighestBidder = bidder
+	order.HighestBid = amount.String()
+	if err = e.putOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	sdk.Instance.EmitEvent("AuctionBid", []string{strconv.FormatUint(order.Id, 10), bidder, amount.String()})
+	if err = e.escrowPayment(order.PayToken, bidder, amount); err != nil {
+		return Error(err.Error())
+	}
+	if len(prevBidder) > 0 {
+		if err = e.pay(order.PayToken, prevBidder, prevBid); err != nil {
+			return Error(err.Error())
+		}
+	}
+	return sdk.Success([]byte(trueString))
+}
+
+// settleAuction anyone closes an ended english auction, the highest bidder gets the token, or the maker gets
+// it back if there is no bid
+func (e *ExchangeContract) settleAuction(orderIdStr string) protogo.Response {
+	order, height, err := e.getOpenOrder(orderIdStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if order.Kind != KindEnglish {
+		return Error(fmt.Sprintf("order %d is not an english auction", order.Id))
+	}
+	if !expired(order, height) {
+		return Error(fmt.Sprintf("auction %d ends at height %d", order.Id, order.ExpireHeight))
+	}
+
+	if len(order.HighestBidder) == 0 {
+		order.Status = StatusExpired
+		if err = e.closeOrder(order); err != nil {
+			return Error(err.Error())
+		}
+		sdk.Instance.EmitEvent("OrderExpired", []string{strconv.FormatUint(order.Id, 10)})
+		return sdk.Success([]byte(trueString))
+	}
+	fill(order, order.HighestBidder, order.HighestBid)
+	if err = e.putOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.settle(order, 0, order.Maker, order.Taker, toBig(order.FilledPrice), true); err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success([]byte(trueString))
+}
+
+// getCurrentPrice the price to buy an ask or a dutch auction at the current height
+func (e *ExchangeContract) getCurrentPrice(orderIdStr string) protogo.Response {
+	order, height, err := e.getOpenOrder(orderIdStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	switch order.Kind {
+	case KindAsk, KindDutch:
+		return sdk.Success([]byte(currentPrice(order, height).String()))
+	case KindEnglish:
+		return sdk.Success([]byte(minAuctionBid(order).String()))
+	default:
+		return Error("bid has no current price")
+	}
+}
+
+// currentPrice the price of a dutch auction declines linearly from Price at StartHeight to EndPrice at
+// ExpireHeight, other orders keep their price
+func currentPrice(order *Order, height uint64) *big.Int {
+	start := toBig(order.Price)
+	if order.Kind != KindDutch || height <= order.StartHeight {
+		return start
+	}
+	end := toBig(order.EndPrice)
+	if height >= order.ExpireHeight {
+		return end
+	}
+	drop := new(big.Int).Sub(start, end)
+	drop.Mul(drop, new(big.Int).SetUint64(height-order.StartHeight))
+	drop.Div(drop, new(big.Int).SetUint64(order.ExpireHeight-order.StartHeight))
+	return start.Sub(start, drop)
+}
+
+// minAuctionBid the reserve price before the first bid, then the highest bid raised by MinIncrementBps and at
+// least by 1
+func minAuctionBid(order *Order) *big.Int {
+	if len(order.HighestBidder) == 0 {
+		return toBig(order.Price)
+	}
+	highest := toBig(order.HighestBid)
+	increment := bps(highest, order.MinIncrementBps)
+	if increment.Sign() == 0 {
+		increment.SetInt64(1)
+	}
+	return increment.Add(increment, highest)
+}
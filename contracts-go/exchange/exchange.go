@@ -8,26 +8,70 @@
 package main
 
 import (
+	"encoding/json"
+	"strconv"
+
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
 )
 
 const (
-	paramToken  = "tokenId"
-	paramFrom   = "from"
-	paramTo     = "to"
-	paramAmount = "amount"
-	trueString  = "true"
+	paramToken           = "tokenId"
+	paramFrom            = "from"
+	paramTo              = "to"
+	paramAmount          = "amount"
+	paramNfa             = "nfa"
+	paramPayToken        = "payToken"
+	paramPrice           = "price"
+	paramEndPrice        = "endPrice"
+	paramExpireHeight    = "expireHeight"
+	paramEndHeight       = "endHeight"
+	paramMinIncrementBps = "minIncrementBps"
+	paramOrderId         = "orderId"
+	paramFeeBps          = "feeBps"
+	paramRoyaltyBps      = "royaltyBps"
+	paramFeeRecipient    = "feeRecipient"
+	trueString           = "true"
 )
 
+// readOnlyMethods do not transfer tokens, the others hold the trade lock while running
+var readOnlyMethods = map[string]bool{
+	"getOrder":     true,
+	"getBids":      true,
+	"currentPrice": true,
+	"getConfig":    true,
+}
+
 type exchange interface {
 
 	// 购买 event
 	// tokenId uintStr, amount uintStr
+	// Deprecated: buyNow moves the erc721 token before the erc20 payment and charges no fee, use placeAsk
+	// and buy, which escrow the token and the payment and settle them together
 	buyNow(tokenId, from, to, amount string) protogo.Response // return "true","false"
-	InitContract() protogo.Response                           // return "Init contract success"
-	UpgradeContract() protogo.Response                        // return "Upgrade contract success"
+
+	// 挂单，与价格不低于price的最高买单立即成交，否则托管nft等待成交
+	placeAsk(nfa, tokenId, payToken, price, expireHeight string) protogo.Response // return orderId
+	// 买单，与价格不高于price的卖单立即成交，否则托管payToken等待成交
+	placeBid(nfa, tokenId, payToken, price, expireHeight string) protogo.Response // return orderId
+	// 按当前价格购买卖单或荷兰拍
+	buy(orderId string) protogo.Response // return price
+	// nft持有者接受买单
+	acceptBid(orderId string) protogo.Response // return price
+	// 英式拍卖
+	createEnglishAuction(nfa, tokenId, payToken, reservePrice, minIncrementBps, endHeight string) protogo.Response
+	bidAuction(orderId, amount string) protogo.Response // return "true"
+	settleAuction(orderId string) protogo.Response      // return "true"
+	// 荷兰式拍卖
+	createDutchAuction(nfa, tokenId, payToken, startPrice, endPrice, endHeight string) protogo.Response
+	// 挂单人撤单，退回托管资产
+	cancelOrder(orderId string) protogo.Response // return "true"
+	// 任何人可将过期订单的托管资产退回挂单人
+	expireOrder(orderId string) protogo.Response // return "true"
+
+	InitContract() protogo.Response    // return "Init contract success"
+	UpgradeContract() protogo.Response // return "Upgrade contract success"
 }
 
 var _ exchange = (*ExchangeContract)(nil)
@@ -36,13 +80,27 @@ var _ exchange = (*ExchangeContract)(nil)
 type ExchangeContract struct {
 }
 
-// InitContract install contract func
+// InitContract install contract func, feeBps and royaltyBps default 0, feeRecipient defaults to the creator
 func (e *ExchangeContract) InitContract() protogo.Response {
+	admin, err := sdk.Instance.Origin()
+	if err != nil {
+		return Error(err.Error())
+	}
+	args := sdk.Instance.GetArgs()
+	config := &Config{Admin: admin, FeeRecipient: admin}
+	if resp := e.updateConfig(config, args); resp.Status != sdk.OK {
+		return resp
+	}
 	return sdk.Success([]byte("Init contract success"))
 }
 
-// UpgradeContract upgrade contract func
+// UpgradeContract upgrade contract func, the config is initialized when upgraded from the version without it
 func (e *ExchangeContract) UpgradeContract() protogo.Response {
+	if _, err := e.getConfig(); err != nil {
+		if resp := e.InitContract(); resp.Status != sdk.OK {
+			return resp
+		}
+	}
 	return sdk.Success([]byte("Upgrade contract success"))
 }
 
@@ -52,6 +110,14 @@ func (e *ExchangeContract) InvokeContract(method string) protogo.Response {
 	if len(method) == 0 {
 		return Error("method of param should not be empty")
 	}
+	// nft and token contracts called while trading must not call back into trading methods
+	if !readOnlyMethods[method] {
+		release, err := sdk.NonReentrant("trade")
+		if err != nil {
+			return Error(err.Error())
+		}
+		defer release()
+	}
 
 	switch method {
 	case "buyNow":
@@ -60,10 +126,59 @@ func (e *ExchangeContract) InvokeContract(method string) protogo.Response {
 		from := string(args[paramFrom])
 		to := string(args[paramTo])
 		return e.buyNow(token, from, to, amount)
+	case "placeAsk":
+		return e.placeAsk(string(args[paramNfa]), string(args[paramToken]), string(args[paramPayToken]),
+			string(args[paramPrice]), string(args[paramExpireHeight]))
+	case "placeBid":
+		return e.placeBid(string(args[paramNfa]), string(args[paramToken]), string(args[paramPayToken]),
+			string(args[paramPrice]), string(args[paramExpireHeight]))
+	case "buy":
+		return e.buy(string(args[paramOrderId]))
+	case "acceptBid":
+		return e.acceptBid(string(args[paramOrderId]))
+	case "createEnglishAuction":
+		return e.createEnglishAuction(string(args[paramNfa]), string(args[paramToken]),
+			string(args[paramPayToken]), string(args[paramPrice]), string(args[paramMinIncrementBps]),
+			string(args[paramEndHeight]))
+	case "bidAuction":
+		return e.bidAuction(string(args[paramOrderId]), string(args[paramAmount]))
+	case "settleAuction":
+		return e.settleAuction(string(args[paramOrderId]))
+	case "createDutchAuction":
+		return e.createDutchAuction(string(args[paramNfa]), string(args[paramToken]),
+			string(args[paramPayToken]), string(args[paramPrice]), string(args[paramEndPrice]),
+			string(args[paramEndHeight]))
+	case "cancelOrder":
+		return e.cancelOrder(string(args[paramOrderId]))
+	case "expireOrder":
+		return e.expireOrder(string(args[paramOrderId]))
+	case "getOrder":
+		return e.getOrder(string(args[paramOrderId]))
+	case "getBids":
+		return e.getBids(string(args[paramNfa]), string(args[paramToken]))
+	case "currentPrice":
+		return e.getCurrentPrice(string(args[paramOrderId]))
+	case "setFee":
+		return e.setFee(args)
+	case "getConfig":
+		config, err := e.getConfig()
+		if err != nil {
+			return Error(err.Error())
+		}
+		configBytes, err := json.Marshal(config)
+		if err != nil {
+			return Error(err.Error())
+		}
+		return sdk.Success(configBytes)
 	default:
 		return Error("Invalid method" + method)
 	}
 }
+
+// buyNow transfer the erc721 token from the seller and then the erc20 payment from the buyer, kept for the
+// old clients.
+//
+// Deprecated: use placeAsk and buy
 func (e *ExchangeContract) buyNow(tokenId, from, to, amount string) protogo.Response {
 	// 查看是否在白名单(注册)
 	args := make(map[string][]byte)
@@ -103,6 +218,43 @@ func (e *ExchangeContract) buyNow(tokenId, from, to, amount string) protogo.Resp
 	return sdk.Success([]byte("true"))
 }
 
+// setFee the admin updates the fee, the royalty or the fee recipient
+func (e *ExchangeContract) setFee(args map[string][]byte) protogo.Response {
+	config, err := e.getConfig()
+	if err != nil {
+		return Error(err.Error())
+	}
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return Error(err.Error())
+	}
+	if sender != config.Admin {
+		return Error("only admin can set fee")
+	}
+	return e.updateConfig(config, args)
+}
+
+// updateConfig save the config with the fee args which are set
+func (e *ExchangeContract) updateConfig(config *Config, args map[string][]byte) protogo.Response {
+	for key, value := range map[string]*uint64{paramFeeBps: &config.FeeBps, paramRoyaltyBps: &config.RoyaltyBps} {
+		if len(args[key]) == 0 {
+			continue
+		}
+		num, err := strconv.ParseUint(string(args[key]), 10, 64)
+		if err != nil {
+			return Error(key + " should be an integer")
+		}
+		*value = num
+	}
+	if recipient := args[paramFeeRecipient]; len(recipient) > 0 {
+		config.FeeRecipient = string(recipient)
+	}
+	if err := e.putConfig(config); err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success([]byte(trueString))
+}
+
 // Error return error response with message
 func Error(message string) protogo.Response {
 	return protogo.Response{
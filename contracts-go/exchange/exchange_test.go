@@ -0,0 +1,406 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	admin    = "admin"
+	alice    = "alice"
+	bob      = "bob"
+	carol    = "carol"
+	minter   = "minter"
+	self     = "exchange"
+	nfa      = "nfa"
+	payToken = "dfa"
+)
+
+// market the states of the exchange and the nfa owners and dfa balances behind the mocked cross contract calls
+type market struct {
+	state    map[string][]byte
+	sender   string
+	height   int
+	args     map[string][]byte
+	owners   map[string]string
+	balances map[string]int64
+}
+
+func mockExchange(t *testing.T) (*gomock.Controller, *market) {
+	ctrl := gomock.NewController(t)
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+	m := &market{
+		state:    map[string][]byte{},
+		sender:   admin,
+		height:   1,
+		args:     map[string][]byte{},
+		owners:   map[string]string{},
+		balances: map[string]int64{alice: 1000, bob: 1000, carol: 1000},
+	}
+
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return m.args })
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return m.sender, nil })
+	mockInstance.EXPECT().Origin().AnyTimes().DoAndReturn(func() (string, error) { return m.sender, nil })
+	mockInstance.EXPECT().GetContractAddr().AnyTimes().Return(self, nil)
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return m.height, nil })
+	mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(k string, f string) (string, error) {
+			return string(m.state[k+"#"+f]), nil
+		})
+	mockInstance.EXPECT().PutState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(k string, f string, val string) error {
+			m.state[k+"#"+f] = []byte(val)
+			return nil
+		})
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(k string, f string) ([]byte, error) {
+			return m.state[k+"#"+f], nil
+		})
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(k string, f string, val []byte) error {
+			m.state[k+"#"+f] = val
+			return nil
+		})
+	mockInstance.EXPECT().DelState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(k string, f string) error {
+			delete(m.state, k+"#"+f)
+			return nil
+		})
+	mockInstance.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		m.callContract)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	sdk.Instance = mockInstance
+	return ctrl, m
+}
+
+// callContract the exchange is the sender of the nested calls and is approved by everyone
+func (m *market) callContract(contractName, method string, args map[string][]byte) protogo.Response {
+	switch contractName + "." + method {
+	case nfa + ".TransferFrom":
+		tokenId := string(args["tokenId"])
+		if m.owners[tokenId] != string(args["from"]) {
+			return sdk.Error("not owner")
+		}
+		m.owners[tokenId] = string(args["to"])
+	case nfa + ".MinterOf":
+		return sdk.Success([]byte(minter))
+	case payToken + ".TransferFrom":
+		return m.transfer(string(args["from"]), string(args["to"]), string(args["amount"]))
+	case payToken + ".Transfer":
+		return m.transfer(self, string(args["to"]), string(args["amount"]))
+	default:
+		return sdk.Error("Invalid method")
+	}
+	return sdk.Success(nil)
+}
+
+func (m *market) transfer(from, to, amountStr string) protogo.Response {
+	amount, _ := new(big.Int).SetString(amountStr, 10)
+	if m.balances[from] < amount.Int64() {
+		return sdk.Error("insufficient balance")
+	}
+	m.balances[from] -= amount.Int64()
+	m.balances[to] += amount.Int64()
+	return sdk.Success(nil)
+}
+
+// invoke call the method as the sender, the states are rolled back if it fails like a failed tx
+func (m *market) invoke(e *ExchangeContract, sender, method string, args map[string][]byte) protogo.Response {
+	m.sender, m.args = sender, args
+	states := make(map[string][]byte, len(m.state))
+	for k, v := range m.state {
+		states[k] = v
+	}
+	owners := make(map[string]string, len(m.owners))
+	for k, v := range m.owners {
+		owners[k] = v
+	}
+	balances := make(map[string]int64, len(m.balances))
+	for k, v := range m.balances {
+		balances[k] = v
+	}
+	resp := e.InvokeContract(method)
+	if resp.Status != sdk.OK {
+		m.state, m.owners, m.balances = states, owners, balances
+	}
+	return resp
+}
+
+func orderArgs(tokenId, price, expireHeight string) map[string][]byte {
+	return map[string][]byte{paramNfa: []byte(nfa), paramToken: []byte(tokenId), paramPayToken: []byte(payToken),
+		paramPrice: []byte(price), paramExpireHeight: []byte(expireHeight), paramEndHeight: []byte(expireHeight)}
+}
+
+func idArgs(orderId string) map[string][]byte {
+	return map[string][]byte{paramOrderId: []byte(orderId)}
+}
+
+func initExchange(t *testing.T, m *market) *ExchangeContract {
+	e := &ExchangeContract{}
+	m.args = map[string][]byte{paramFeeBps: []byte("250"), paramRoyaltyBps: []byte("500"),
+		paramFeeRecipient: []byte("fee")}
+	if resp := e.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	m.owners["1"] = alice
+	m.owners["2"] = carol
+	return e
+}
+
+func TestExchangeContract_InvokeContract(t *testing.T) {
+	ctrl, m := mockExchange(t)
+	defer ctrl.Finish()
+	e := initExchange(t, m)
+
+	tests := []struct {
+		name   string
+		method string
+		args   map[string][]byte
+		want   protogo.Response
+	}{
+		{name: "empty method", method: "", want: Error("method of param should not be empty")},
+		{name: "invalid method", method: "invalid", want: Error("Invalid methodinvalid")},
+		{name: "ask without price", method: "placeAsk", args: map[string][]byte{paramNfa: []byte(nfa),
+			paramToken: []byte("1"), paramPayToken: []byte(payToken)},
+			want: Error("price should be a positive integer")},
+		{name: "order not found", method: "getOrder", args: map[string][]byte{paramOrderId: []byte("9")},
+			want: Error("order 9 not found")},
+		{name: "set fee by others", method: "setFee", want: Error("only admin can set fee")},
+		{name: "config", method: "getConfig",
+			want: sdk.Success([]byte(`{"admin":"admin","feeRecipient":"fee","feeBps":250,"royaltyBps":500}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.sender, m.args = bob, tt.args
+			if got := e.InvokeContract(tt.method); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvokeContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExchangeContract_reentrancy(t *testing.T) {
+	ctrl, m := mockExchange(t)
+	defer ctrl.Finish()
+	e := initExchange(t, m)
+	defer sdk.ResetCallContext(map[string][]byte{}, true, 0, self)
+
+	// exchange called back by the nfa contract while trading
+	sdk.ResetCallContext(map[string][]byte{
+		sdk.CallContextParam: []byte(`{"callers":["exchange","nfa"],"locks":["exchange/trade"]}`),
+	}, true, 2, self)
+	resp := m.invoke(e, alice, "placeAsk", orderArgs("1", "100", ""))
+	if want := Error(sdk.ErrReentrantCall.Error()); !reflect.DeepEqual(resp, want) {
+		t.Errorf("reentrant placeAsk = %v, want %v", resp, want)
+	}
+	if resp := m.invoke(e, alice, "getConfig", nil); resp.Status != sdk.OK {
+		t.Errorf("reentrant getConfig = %v", resp)
+	}
+
+	// exchange called by an evm contract, the locks it inherits are unknown
+	sdk.ResetCallContext(map[string][]byte{}, true, 1, self)
+	resp = m.invoke(e, alice, "placeAsk", orderArgs("1", "100", ""))
+	if want := Error(sdk.ErrUnknownCallStack.Error()); !reflect.DeepEqual(resp, want) {
+		t.Errorf("placeAsk called by evm = %v, want %v", resp, want)
+	}
+}
+
+func TestExchangeContract_orderBook(t *testing.T) {
+	ctrl, m := mockExchange(t)
+	defer ctrl.Finish()
+	e := initExchange(t, m)
+
+	if resp := m.invoke(e, bob, "placeBid", orderArgs("1", "90", "20")); string(resp.Payload) != "1" {
+		t.Fatalf("placeBid() = %v", resp)
+	}
+	if resp := m.invoke(e, carol, "placeBid", orderArgs("1", "100", "20")); string(resp.Payload) != "2" {
+		t.Fatalf("placeBid() = %v", resp)
+	}
+	// the ask is filled by the highest bid at the bid price
+	if resp := m.invoke(e, alice, "placeAsk", orderArgs("1", "95", "")); string(resp.Payload) != "3" {
+		t.Fatalf("placeAsk() = %v", resp)
+	}
+	want := map[string]int64{alice: 1093, bob: 910, carol: 900, self: 90, "fee": 2, minter: 5}
+	if m.owners["1"] != carol || !reflect.DeepEqual(m.balances, want) {
+		t.Errorf("owner = %s, balances = %v, want %v", m.owners["1"], m.balances, want)
+	}
+	if order, _ := e.loadOrder("3"); order.Status != StatusFilled || order.Taker != carol ||
+		order.FilledPrice != "100" {
+		t.Errorf("ask = %+v", order)
+	}
+	if ids, _ := e.getBidIds(nfa, "1"); !reflect.DeepEqual(ids, []uint64{1}) {
+		t.Errorf("bids = %v", ids)
+	}
+
+	// the bid of bob is accepted by the new owner
+	if resp := m.invoke(e, alice, "acceptBid", idArgs("1")); resp.Status == sdk.OK {
+		t.Errorf("acceptBid() by others should fail")
+	}
+	if resp := m.invoke(e, carol, "acceptBid", idArgs("1")); resp.Status != sdk.OK {
+		t.Fatalf("acceptBid() = %v", resp)
+	}
+	if m.owners["1"] != bob || m.balances[self] != 0 || m.balances[carol] != 900+84 {
+		t.Errorf("owner = %s, balances = %v", m.owners["1"], m.balances)
+	}
+	if resp := m.invoke(e, carol, "acceptBid", idArgs("1")); resp.Status == sdk.OK {
+		t.Errorf("acceptBid() twice should fail")
+	}
+
+	// the bid is filled by the ask in the book at the ask price
+	if resp := m.invoke(e, bob, "placeAsk", orderArgs("1", "50", "10")); string(resp.Payload) != "4" {
+		t.Fatalf("placeAsk() = %v", resp)
+	}
+	if m.owners["1"] != self {
+		t.Errorf("owner = %s, want escrowed", m.owners["1"])
+	}
+	if resp := m.invoke(e, alice, "placeBid", orderArgs("1", "60", "20")); string(resp.Payload) != "5" {
+		t.Fatalf("placeBid() = %v", resp)
+	}
+	if m.owners["1"] != alice || m.balances[alice] != 1093-50 || m.balances[bob] != 910+47 {
+		t.Errorf("owner = %s, balances = %v", m.owners["1"], m.balances)
+	}
+
+	// cancel and expire
+	if resp := m.invoke(e, alice, "placeAsk", orderArgs("1", "70", "10")); string(resp.Payload) != "6" {
+		t.Fatalf("placeAsk() = %v", resp)
+	}
+	if resp := m.invoke(e, bob, "cancelOrder", idArgs("6")); resp.Status == sdk.OK {
+		t.Errorf("cancelOrder() by others should fail")
+	}
+	if resp := m.invoke(e, bob, "expireOrder", idArgs("6")); resp.Status == sdk.OK {
+		t.Errorf("expireOrder() before the expire height should fail")
+	}
+	m.height = 11
+	if resp := m.invoke(e, bob, "buy", idArgs("6")); resp.Status == sdk.OK {
+		t.Errorf("buy() of expired ask should fail")
+	}
+	if resp := m.invoke(e, bob, "expireOrder", idArgs("6")); resp.Status != sdk.OK {
+		t.Fatalf("expireOrder() = %v", resp)
+	}
+	if order, _ := e.loadOrder("6"); m.owners["1"] != alice || order.Status != StatusExpired {
+		t.Errorf("owner = %s, order = %+v", m.owners["1"], order)
+	}
+	if resp := m.invoke(e, alice, "cancelOrder", idArgs("6")); resp.Status == sdk.OK {
+		t.Errorf("cancelOrder() of expired order should fail")
+	}
+}
+
+func TestExchangeContract_bidBook(t *testing.T) {
+	ctrl, m := mockExchange(t)
+	defer ctrl.Finish()
+	e := initExchange(t, m)
+
+	// bob fills the book of the token with bids expiring at 5
+	for i := 0; i < maxBidsPerToken; i++ {
+		if resp := m.invoke(e, bob, "placeBid", orderArgs("1", "1", "5")); resp.Status != sdk.OK {
+			t.Fatalf("placeBid() = %v", resp)
+		}
+	}
+	tests := []struct {
+		name   string
+		height int
+		args   map[string][]byte
+		want   protogo.Response
+	}{
+		{name: "never expire", height: 2, args: orderArgs("1", "50", ""),
+			want: Error("expireHeight of bid should be within 100000 blocks")},
+		{name: "expire too late", height: 2, args: orderArgs("1", "50", "100003"),
+			want: Error("expireHeight of bid should be within 100000 blocks")},
+		{name: "book is full", height: 2, args: orderArgs("1", "50", "100002"),
+			want: Error("token 1 has 100 open bids")},
+		{name: "expired bids purged", height: 6, args: orderArgs("1", "50", "20"),
+			want: sdk.Success([]byte("101"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.height = tt.height
+			if got := m.invoke(e, carol, "placeBid", tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("placeBid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if ids, _ := e.getBidIds(nfa, "1"); !reflect.DeepEqual(ids, []uint64{101}) {
+		t.Errorf("bids = %v", ids)
+	}
+	if order, _ := e.loadOrder("1"); order.Status != StatusExpired || m.balances[bob] != 1000 ||
+		m.balances[self] != 50 {
+		t.Errorf("order = %+v, balances = %v", order, m.balances)
+	}
+}
+
+func TestExchangeContract_auction(t *testing.T) {
+	ctrl, m := mockExchange(t)
+	defer ctrl.Finish()
+	e := initExchange(t, m)
+	m.owners["2"] = minter
+
+	args := orderArgs("2", "100", "20")
+	args[paramMinIncrementBps] = []byte("1000")
+	if resp := m.invoke(e, carol, "createEnglishAuction", args); resp.Status == sdk.OK {
+		t.Errorf("createEnglishAuction() of others token should fail")
+	}
+	if resp := m.invoke(e, minter, "createEnglishAuction", args); string(resp.Payload) != "1" {
+		t.Fatalf("createEnglishAuction() = %v", resp)
+	}
+	for _, bid := range []struct {
+		bidder string
+		amount string
+		ok     bool
+	}{{alice, "99", false}, {alice, "100", true}, {bob, "105", false}, {bob, "110", true}} {
+		args = map[string][]byte{paramOrderId: []byte("1"), paramAmount: []byte(bid.amount)}
+		if resp := m.invoke(e, bid.bidder, "bidAuction", args); (resp.Status == sdk.OK) != bid.ok {
+			t.Errorf("bidAuction(%s, %s) = %v", bid.bidder, bid.amount, resp)
+		}
+	}
+	if m.balances[alice] != 1000 || m.balances[bob] != 890 || m.balances[self] != 110 {
+		t.Errorf("balances = %v", m.balances)
+	}
+	if resp := m.invoke(e, minter, "cancelOrder", idArgs("1")); resp.Status == sdk.OK {
+		t.Errorf("cancelOrder() of auction with bids should fail")
+	}
+	if resp := m.invoke(e, carol, "settleAuction", idArgs("1")); resp.Status == sdk.OK {
+		t.Errorf("settleAuction() before the end should fail")
+	}
+	m.height = 21
+	if resp := m.invoke(e, carol, "settleAuction", idArgs("1")); resp.Status != sdk.OK {
+		t.Fatalf("settleAuction() = %v", resp)
+	}
+	// no royalty when the minter sells
+	if m.owners["2"] != bob || m.balances[minter] != 108 || m.balances["fee"] != 2 {
+		t.Errorf("owner = %s, balances = %v", m.owners["2"], m.balances)
+	}
+
+	// dutch auction declines from 100 to 10 in 10 blocks, it never ends at 0
+	args = orderArgs("2", "100", "31")
+	args[paramEndPrice] = []byte("0")
+	resp := m.invoke(e, bob, "createDutchAuction", args)
+	if !reflect.DeepEqual(resp, Error("endPrice should be a positive integer not greater than startPrice")) {
+		t.Errorf("createDutchAuction() = %v", resp)
+	}
+	args[paramEndPrice] = []byte("10")
+	if resp = m.invoke(e, bob, "createDutchAuction", args); string(resp.Payload) != "2" {
+		t.Fatalf("createDutchAuction() = %v", resp)
+	}
+	m.height = 26
+	if resp = m.invoke(e, carol, "currentPrice", idArgs("2")); string(resp.Payload) != "55" {
+		t.Errorf("currentPrice() = %v", resp)
+	}
+	if resp = m.invoke(e, carol, "buy", idArgs("2")); string(resp.Payload) != "55" {
+		t.Fatalf("buy() = %v", resp)
+	}
+	if m.owners["2"] != carol || m.balances[carol] != 945 || m.balances[minter] != 108+2 {
+		t.Errorf("owner = %s, balances = %v", m.owners["2"], m.balances)
+	}
+}
@@ -2,15 +2,17 @@ module exchange
 
 go 1.17
 
-require chainmaker.org/chainmaker/contract-sdk-go/v2 v2.3.3
+require (
+	chainmaker.org/chainmaker/contract-sdk-go/v2 v2.3.3
+	github.com/golang/mock v1.6.0
+)
 
 require (
-	chainmaker.org/chainmaker/common/v2 v2.3.0 // indirect
-	chainmaker.org/chainmaker/pb-go/v2 v2.3.0 // indirect
-	chainmaker.org/chainmaker/protocol/v2 v2.3.0 // indirect
+	chainmaker.org/chainmaker/common/v2 v2.3.5 // indirect
+	chainmaker.org/chainmaker/pb-go/v2 v2.3.6 // indirect
+	chainmaker.org/chainmaker/protocol/v2 v2.3.6 // indirect
 	github.com/dgryski/go-metro v0.0.0-20200812162917-85c65e2d0165 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
-	github.com/golang/mock v1.6.0 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/google/uuid v1.1.2 // indirect
 	github.com/linvon/cuckoo-filter v0.4.0 // indirect
@@ -30,3 +32,5 @@ require (
 	google.golang.org/grpc v1.41.1 // indirect
 	google.golang.org/protobuf v1.27.1 // indirect
 )
+
+replace chainmaker.org/chainmaker/contract-sdk-go/v2 => ../../contract-sdk-go
@@ -0,0 +1,738 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+// order kinds
+const (
+	KindAsk     = "ask"
+	KindBid     = "bid"
+	KindEnglish = "english"
+	KindDutch   = "dutch"
+)
+
+// order status
+const (
+	StatusOpen      = "open"
+	StatusFilled    = "filled"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+const (
+	configKey     = "config"
+	orderKey      = "order"
+	orderCountKey = "orderCount"
+	// askKey + nfa, field tokenId => id of the open ask
+	askKey = "ask_"
+	// bidsKey + nfa, field tokenId => ids of the open bids
+	bidsKey = "bids_"
+
+	bpsBase         = 10000
+	maxBidsPerToken = 100
+	// maxBidBlocks bids expire within the blocks, so the open bids of a token can not be filled up for good
+	maxBidBlocks = 100000
+)
+
+// Config exchange config, fee and royalty are in basis points of the price
+type Config struct {
+	Admin        string `json:"admin"`
+	FeeRecipient string `json:"feeRecipient"`
+	FeeBps       uint64 `json:"feeBps"`
+	RoyaltyBps   uint64 `json:"royaltyBps"`
+}
+
+// Order an order of the exchange. Price is the limit price of asks and bids, the reserve price of english
+// auctions and the start price of dutch auctions. The nfa token of asks and auctions and the payment of
+// bids and english auction bids are escrowed by the exchange until the order is filled, cancelled or expired
+type Order struct {
+	Id              uint64 `json:"id"`
+	Kind            string `json:"kind"`
+	Maker           string `json:"maker"`
+	Nfa             string `json:"nfa"`
+	TokenId         string `json:"tokenId"`
+	PayToken        string `json:"payToken"`
+	Price           string `json:"price"`
+	EndPrice        string `json:"endPrice,omitempty"`
+	MinIncrementBps uint64 `json:"minIncrementBps,omitempty"`
+	StartHeight     uint64 `json:"startHeight"`
+	ExpireHeight    uint64 `json:"expireHeight"`
+	HighestBidder   string `json:"highestBidder,omitempty"`
+	HighestBid      string `json:"highestBid,omitempty"`
+	Status          string `json:"status"`
+	Taker           string `json:"taker,omitempty"`
+	FilledPrice     string `json:"filledPrice,omitempty"`
+}
+
+// placeAsk sell the nfa token at price, filled at once by the highest crossing bid, otherwise the token is
+// escrowed and the ask waits in the book. expireHeight 0 means never expire
+func (e *ExchangeContract) placeAsk(nfa, tokenId, payToken, priceStr, expireHeightStr string) protogo.Response {
+	order, err := e.newOrder(KindAsk, nfa, tokenId, payToken, priceStr, expireHeightStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	askId, err := sdk.Instance.GetState(askKey+nfa, tokenId)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if len(askId) > 0 {
+		return Error(fmt.Sprintf("token %s already has open ask %s", tokenId, askId))
+	}
+
+	bid, err := e.bestBid(order)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if bid == nil {
+		if err = e.putOrder(order); err != nil {
+			return Error(err.Error())
+		}
+		if err = sdk.Instance.PutState(askKey+nfa, tokenId, strconv.FormatUint(order.Id, 10)); err != nil {
+			return Error(err.Error())
+		}
+		e.emitOrderCreated(order)
+		if err = e.escrowNfa(nfa, order.Maker, tokenId); err != nil {
+			return Error(err.Error())
+		}
+		return sdk.Success([]byte(strconv.FormatUint(order.Id, 10)))
+	}
+
+	// filled at the price of the bid which was in the book
+	fill(order, bid.Maker, bid.Price)
+	fill(bid, order.Maker, bid.Price)
+	if err = e.removeBid(bid); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.putOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.putOrder(bid); err != nil {
+		return Error(err.Error())
+	}
+	e.emitOrderCreated(order)
+	if err = e.settle(order, bid.Id, order.Maker, bid.Maker, toBig(bid.Price), false); err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatUint(order.Id, 10)))
+}
+
+// placeBid buy the nfa token at price, filled at once by the crossing ask, otherwise the payment is
+// escrowed and the bid waits in the book. expireHeight is required and at most maxBidBlocks ahead, the
+// expired bids are returned to their makers when the book of the token is full
+func (e *ExchangeContract) placeBid(nfa, tokenId, payToken, priceStr, expireHeightStr string) protogo.Response {
+	order, err := e.newOrder(KindBid, nfa, tokenId, payToken, priceStr, expireHeightStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if order.ExpireHeight == 0 || order.ExpireHeight > order.StartHeight+maxBidBlocks {
+		return Error(fmt.Sprintf("expireHeight of bid should be within %d blocks", maxBidBlocks))
+	}
+	ask, err := e.openAsk(nfa, tokenId)
+	if err != nil {
+		return Error(err.Error())
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return Error(err.Error())
+	}
+	if ask == nil || ask.Maker == order.Maker || ask.PayToken != payToken || expired(ask, height) ||
+		toBig(ask.Price).Cmp(toBig(order.Price)) > 0 {
+		bids, err1 := e.getBidIds(nfa, tokenId)
+		if err1 != nil {
+			return Error(err1.Error())
+		}
+		if len(bids) >= maxBidsPerToken {
+			if bids, err = e.purgeExpiredBids(nfa, tokenId, bids, height); err != nil {
+				return Error(err.Error())
+			}
+		}
+		if len(bids) >= maxBidsPerToken {
+			return Error(fmt.Sprintf("token %s has %d open bids", tokenId, maxBidsPerToken))
+		}
+		if err = e.putOrder(order); err != nil {
+			return Error(err.Error())
+		}
+		if err = e.putBidIds(nfa, tokenId, append(bids, order.Id)); err != nil {
+			return Error(err.Error())
+		}
+		e.emitOrderCreated(order)
+		if err = e.escrowPayment(payToken, order.Maker, toBig(order.Price)); err != nil {
+			return Error(err.Error())
+		}
+		return sdk.Success([]byte(strconv.FormatUint(order.Id, 10)))
+	}
+
+	// filled at the price of the ask which was in the book, only the ask price is paid
+	fill(order, ask.Maker, ask.Price)
+	fill(ask, order.Maker, ask.Price)
+	if err = sdk.Instance.DelState(askKey+nfa, tokenId); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.putOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.putOrder(ask); err != nil {
+		return Error(err.Error())
+	}
+	e.emitOrderCreated(order)
+	if err = e.escrowPayment(payToken, order.Maker, toBig(ask.Price)); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.settle(ask, order.Id, ask.Maker, order.Maker, toBig(ask.Price), true); err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatUint(order.Id, 10)))
+}
+
+// buy take an ask or a dutch auction at its current price
+func (e *ExchangeContract) buy(orderIdStr string) protogo.Response {
+	order, height, err := e.getOpenOrder(orderIdStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if order.Kind != KindAsk && order.Kind != KindDutch {
+		return Error(fmt.Sprintf("order %d is %s, only ask and dutch auction can be bought", order.Id, order.Kind))
+	}
+	if expired(order, height) {
+		return Error(fmt.Sprintf("order %d expired at height %d", order.Id, order.ExpireHeight))
+	}
+	buyer, err := sdk.Instance.Sender()
+	if err != nil {
+		return Error(err.Error())
+	}
+	if buyer == order.Maker {
+		return Error("maker can not take its own order")
+	}
+	price := currentPrice(order, height)
+
+	fill(order, buyer, price.String())
+	if order.Kind == KindAsk {
+		if err = sdk.Instance.DelState(askKey+order.Nfa, order.TokenId); err != nil {
+			return Error(err.Error())
+		}
+	}
+	if err = e.putOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.escrowPayment(order.PayToken, buyer, price); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.settle(order, 0, order.Maker, buyer, price, true); err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success([]byte(price.String()))
+}
+
+// acceptBid the owner of the nfa token fills a bid, the exchange should be approved to transfer the token
+func (e *ExchangeContract) acceptBid(orderIdStr string) protogo.Response {
+	order, height, err := e.getOpenOrder(orderIdStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if order.Kind != KindBid {
+		return Error(fmt.Sprintf("order %d is not a bid", order.Id))
+	}
+	if expired(order, height) {
+		return Error(fmt.Sprintf("order %d expired at height %d", order.Id, order.ExpireHeight))
+	}
+	seller, err := sdk.Instance.Sender()
+	if err != nil {
+		return Error(err.Error())
+	}
+	if seller == order.Maker {
+		return Error("maker can not take its own order")
+	}
+
+	fill(order, seller, order.Price)
+	if err = e.removeBid(order); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.putOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	if err = e.settle(order, 0, seller, order.Maker, toBig(order.Price), false); err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success([]byte(order.Price))
+}
+
+// cancelOrder the maker cancels an open order and gets the escrow back, an english auction with bids can not
+// be cancelled
+func (e *ExchangeContract) cancelOrder(orderIdStr string) protogo.Response {
+	order, _, err := e.getOpenOrder(orderIdStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return Error(err.Error())
+	}
+	if sender != order.Maker {
+		return Error("only maker can cancel the order")
+	}
+	if order.Kind == KindEnglish && len(order.HighestBidder) > 0 {
+		return Error(fmt.Sprintf("auction %d has bids", order.Id))
+	}
+	order.Status = StatusCancelled
+	if err = e.closeOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	sdk.Instance.EmitEvent("OrderCancelled", []string{strconv.FormatUint(order.Id, 10), order.Maker})
+	return sdk.Success([]byte(trueString))
+}
+
+// expireOrder anyone returns the escrow of an expired order to the maker, english auctions are closed by
+// settleAuction
+func (e *ExchangeContract) expireOrder(orderIdStr string) protogo.Response {
+	order, height, err := e.getOpenOrder(orderIdStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if order.Kind == KindEnglish {
+		return Error("english auction is closed by settleAuction")
+	}
+	if !expired(order, height) {
+		return Error(fmt.Sprintf("order %d is not expired", order.Id))
+	}
+	order.Status = StatusExpired
+	if err = e.closeOrder(order); err != nil {
+		return Error(err.Error())
+	}
+	sdk.Instance.EmitEvent("OrderExpired", []string{strconv.FormatUint(order.Id, 10)})
+	return sdk.Success([]byte(trueString))
+}
+
+func (e *ExchangeContract) getOrder(orderIdStr string) protogo.Response {
+	order, err := e.loadOrder(orderIdStr)
+	if err != nil {
+		return Error(err.Error())
+	}
+	orderBytes, err := json.Marshal(order)
+	if err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success(orderBytes)
+}
+
+// getBids the open bids of the nfa token
+func (e *ExchangeContract) getBids(nfa, tokenId string) protogo.Response {
+	ids, err := e.getBidIds(nfa, tokenId)
+	if err != nil {
+		return Error(err.Error())
+	}
+	bids := make([]*Order, 0, len(ids))
+	for _, id := range ids {
+		bid, err1 := e.loadOrder(strconv.FormatUint(id, 10))
+		if err1 != nil {
+			return Error(err1.Error())
+		}
+		bids = append(bids, bid)
+	}
+	bidsBytes, err := json.Marshal(bids)
+	if err != nil {
+		return Error(err.Error())
+	}
+	return sdk.Success(bidsBytes)
+}
+
+// closeOrder save the cancelled or expired order and return the escrow to the maker
+func (e *ExchangeContract) closeOrder(order *Order) error {
+	switch order.Kind {
+	case KindAsk:
+		if err := sdk.Instance.DelState(askKey+order.Nfa, order.TokenId); err != nil {
+			return err
+		}
+	case KindBid:
+		if err := e.removeBid(order); err != nil {
+			return err
+		}
+	}
+	if err := e.putOrder(order); err != nil {
+		return err
+	}
+	if order.Kind == KindBid {
+		return e.pay(order.PayToken, order.Maker, toBig(order.Price))
+	}
+	return e.releaseNfa(order.Nfa, order.Maker, order.TokenId)
+}
+
+// settle move the nfa token to the buyer, and pay the fee, the royalty of the minter and the rest to the
+// seller from the escrowed payment
+func (e *ExchangeContract) settle(order *Order, counterOrderId uint64, seller, buyer string, price *big.Int,
+	nfaEscrowed bool) error {
+	config, err := e.getConfig()
+	if err != nil {
+		return err
+	}
+	fee := bps(price, config.FeeBps)
+	royalty := new(big.Int)
+	minter := e.minterOf(order.Nfa, order.TokenId)
+	if len(minter) > 0 && minter != seller {
+		royalty = bps(price, config.RoyaltyBps)
+	}
+	proceeds := new(big.Int).Sub(price, fee)
+	proceeds.Sub(proceeds, royalty)
+
+	counter := ""
+	if counterOrderId > 0 {
+		counter = strconv.FormatUint(counterOrderId, 10)
+	}
+	sdk.Instance.EmitEvent("OrderMatched", []string{strconv.FormatUint(order.Id, 10), counter, seller, buyer,
+		order.Nfa, order.TokenId, order.PayToken, price.String(), fee.String(), royalty.String()})
+
+	if nfaEscrowed {
+		err = e.releaseNfa(order.Nfa, buyer, order.TokenId)
+	} else {
+		err = e.transferNfa(order.Nfa, seller, buyer, order.TokenId)
+	}
+	if err != nil {
+		return err
+	}
+	if err = e.pay(order.PayToken, config.FeeRecipient, fee); err != nil {
+		return err
+	}
+	if err = e.pay(order.PayToken, minter, royalty); err != nil {
+		return err
+	}
+	return e.pay(order.PayToken, seller, proceeds)
+}
+
+func (e *ExchangeContract) newOrder(kind, nfa, tokenId, payToken, priceStr, expireHeightStr string) (*Order, error) {
+	if len(nfa) == 0 || len(tokenId) == 0 || len(payToken) == 0 {
+		return nil, errors.New("nfa, tokenId and payToken should not be empty")
+	}
+	price, ok := new(big.Int).SetString(priceStr, 10)
+	if !ok || price.Sign() <= 0 {
+		return nil, errors.New("price should be a positive integer")
+	}
+	var expireHeight uint64
+	var err error
+	if len(expireHeightStr) > 0 {
+		if expireHeight, err = strconv.ParseUint(expireHeightStr, 10, 64); err != nil {
+			return nil, errors.New("expireHeight should be an integer")
+		}
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return nil, err
+	}
+	if expireHeight > 0 && expireHeight <= height {
+		return nil, fmt.Errorf("expireHeight should be greater than the current height %d", height)
+	}
+	maker, err := sdk.Instance.Sender()
+	if err != nil {
+		return nil, err
+	}
+
+	countStr, err := sdk.Instance.GetState(orderCountKey, "")
+	if err != nil {
+		return nil, err
+	}
+	var count uint64
+	if len(countStr) > 0 {
+		if count, err = strconv.ParseUint(countStr, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	if err = sdk.Instance.PutState(orderCountKey, "", strconv.FormatUint(count+1, 10)); err != nil {
+		return nil, err
+	}
+	return &Order{
+		Id:           count + 1,
+		Kind:         kind,
+		Maker:        maker,
+		Nfa:          nfa,
+		TokenId:      tokenId,
+		PayToken:     payToken,
+		Price:        price.String(),
+		StartHeight:  height,
+		ExpireHeight: expireHeight,
+		Status:       StatusOpen,
+	}, nil
+}
+
+// bestBid the open bid with the highest price crossing the ask, the earliest one of the same price
+func (e *ExchangeContract) bestBid(ask *Order) (*Order, error) {
+	ids, err := e.getBidIds(ask.Nfa, ask.TokenId)
+	if err != nil {
+		return nil, err
+	}
+	var best *Order
+	for _, id := range ids {
+		bid, err1 := e.loadOrder(strconv.FormatUint(id, 10))
+		if err1 != nil {
+			return nil, err1
+		}
+		if bid.Maker == ask.Maker || bid.PayToken != ask.PayToken || expired(bid, ask.StartHeight) ||
+			toBig(bid.Price).Cmp(toBig(ask.Price)) < 0 {
+			continue
+		}
+		if best == nil || toBig(bid.Price).Cmp(toBig(best.Price)) > 0 {
+			best = bid
+		}
+	}
+	return best, nil
+}
+
+func (e *ExchangeContract) openAsk(nfa, tokenId string) (*Order, error) {
+	askId, err := sdk.Instance.GetState(askKey+nfa, tokenId)
+	if err != nil || len(askId) == 0 {
+		return nil, err
+	}
+	return e.loadOrder(askId)
+}
+
+func (e *ExchangeContract) removeBid(bid *Order) error {
+	ids, err := e.getBidIds(bid.Nfa, bid.TokenId)
+	if err != nil {
+		return err
+	}
+	rest := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if id != bid.Id {
+			rest = append(rest, id)
+		}
+	}
+	return e.putBidIds(bid.Nfa, bid.TokenId, rest)
+}
+
+// purgeExpiredBids return the escrow of the expired bids to the makers, and the ids of the open bids left
+func (e *ExchangeContract) purgeExpiredBids(nfa, tokenId string, ids []uint64, height uint64) ([]uint64, error) {
+	rest := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		bid, err := e.loadOrder(strconv.FormatUint(id, 10))
+		if err != nil {
+			return nil, err
+		}
+		if !expired(bid, height) {
+			rest = append(rest, id)
+			continue
+		}
+		bid.Status = StatusExpired
+		if err = e.putOrder(bid); err != nil {
+			return nil, err
+		}
+		if err = e.pay(bid.PayToken, bid.Maker, toBig(bid.Price)); err != nil {
+			return nil, err
+		}
+		sdk.Instance.EmitEvent("OrderExpired", []string{strconv.FormatUint(bid.Id, 10)})
+	}
+	if len(rest) == len(ids) {
+		return ids, nil
+	}
+	return rest, e.putBidIds(nfa, tokenId, rest)
+}
+
+func (e *ExchangeContract) getBidIds(nfa, tokenId string) ([]uint64, error) {
+	data, err := sdk.Instance.GetStateByte(bidsKey+nfa, tokenId)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	var ids []uint64
+	if err = json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (e *ExchangeContract) putBidIds(nfa, tokenId string, ids []uint64) error {
+	if len(ids) == 0 {
+		return sdk.Instance.DelState(bidsKey+nfa, tokenId)
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(bidsKey+nfa, tokenId, data)
+}
+
+// getOpenOrder load the open order and the current block height
+func (e *ExchangeContract) getOpenOrder(orderIdStr string) (*Order, uint64, error) {
+	order, err := e.loadOrder(orderIdStr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if order.Status != StatusOpen {
+		return nil, 0, fmt.Errorf("order %d is %s", order.Id, order.Status)
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return nil, 0, err
+	}
+	return order, height, nil
+}
+
+func (e *ExchangeContract) loadOrder(orderIdStr string) (*Order, error) {
+	if len(orderIdStr) == 0 {
+		return nil, errors.New("orderId should not be empty")
+	}
+	data, err := sdk.Instance.GetStateByte(orderKey, orderIdStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("order %s not found", orderIdStr)
+	}
+	var order Order
+	if err = json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (e *ExchangeContract) putOrder(order *Order) error {
+	data, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(orderKey, strconv.FormatUint(order.Id, 10), data)
+}
+
+func (e *ExchangeContract) emitOrderCreated(order *Order) {
+	sdk.Instance.EmitEvent("OrderCreated", []string{strconv.FormatUint(order.Id, 10), order.Kind, order.Maker,
+		order.Nfa, order.TokenId, order.PayToken, order.Price, strconv.FormatUint(order.ExpireHeight, 10)})
+}
+
+// escrowNfa move the nfa token from the owner to the exchange, the exchange should be approved
+func (e *ExchangeContract) escrowNfa(nfa, from, tokenId string) error {
+	self, err := sdk.Instance.GetContractAddr()
+	if err != nil {
+		return err
+	}
+	return e.transferNfa(nfa, from, self, tokenId)
+}
+
+// releaseNfa move the escrowed nfa token to the account
+func (e *ExchangeContract) releaseNfa(nfa, to, tokenId string) error {
+	self, err := sdk.Instance.GetContractAddr()
+	if err != nil {
+		return err
+	}
+	return e.transferNfa(nfa, self, to, tokenId)
+}
+
+func (e *ExchangeContract) transferNfa(nfa, from, to, tokenId string) error {
+	resp := sdk.Instance.CallContract(nfa, "TransferFrom", map[string][]byte{
+		"from":    []byte(from),
+		"to":      []byte(to),
+		"tokenId": []byte(tokenId),
+	})
+	if resp.Status != sdk.OK {
+		return fmt.Errorf("nfa TransferFrom error. %s", resp.Message)
+	}
+	return nil
+}
+
+// escrowPayment move the payment from the account to the exchange, the exchange should be approved
+func (e *ExchangeContract) escrowPayment(payToken, from string, amount *big.Int) error {
+	self, err := sdk.Instance.GetContractAddr()
+	if err != nil {
+		return err
+	}
+	resp := sdk.Instance.CallContract(payToken, "TransferFrom", map[string][]byte{
+		"from":   []byte(from),
+		"to":     []byte(self),
+		"amount": []byte(amount.String()),
+	})
+	if resp.Status != sdk.OK {
+		return fmt.Errorf("dfa TransferFrom error. %s", resp.Message)
+	}
+	return nil
+}
+
+// pay move the escrowed payment to the account
+func (e *ExchangeContract) pay(payToken, to string, amount *big.Int) error {
+	if amount.Sign() <= 0 {
+		return nil
+	}
+	resp := sdk.Instance.CallContract(payToken, "Transfer", map[string][]byte{
+		"to":     []byte(to),
+		"amount": []byte(amount.String()),
+	})
+	if resp.Status != sdk.OK {
+		return fmt.Errorf("dfa Transfer error. %s", resp.Message)
+	}
+	return nil
+}
+
+// minterOf the minter of the nfa token, empty if the nfa contract does not record it
+func (e *ExchangeContract) minterOf(nfa, tokenId string) string {
+	resp := sdk.Instance.CallContract(nfa, "MinterOf", map[string][]byte{"tokenId": []byte(tokenId)})
+	if resp.Status != sdk.OK {
+		return ""
+	}
+	return string(resp.Payload)
+}
+
+func (e *ExchangeContract) getConfig() (*Config, error) {
+	data, err := sdk.Instance.GetStateByte(configKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("config not found")
+	}
+	var config Config
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (e *ExchangeContract) putConfig(config *Config) error {
+	if config.FeeBps+config.RoyaltyBps > bpsBase {
+		return errors.New("feeBps and royaltyBps should not be more than 10000 in total")
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(configKey, "", data)
+}
+
+func fill(order *Order, taker, price string) {
+	order.Status = StatusFilled
+	order.Taker = taker
+	order.FilledPrice = price
+}
+
+// expired orders with expireHeight 0 never expire
+func expired(order *Order, height uint64) bool {
+	return order.ExpireHeight > 0 && height > order.ExpireHeight
+}
+
+func bps(amount *big.Int, bps uint64) *big.Int {
+	result := new(big.Int).Mul(amount, new(big.Int).SetUint64(bps))
+	return result.Div(result, big.NewInt(bpsBase))
+}
+
+func toBig(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return n
+}
+
+func blockHeight() (uint64, error) {
+	height, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return 0, fmt.Errorf("get block height failed, %s", err)
+	}
+	return uint64(height), nil
+}
@@ -0,0 +1,65 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	eventProposalCreated   = "ProposalCreated"
+	eventProposalActivated = "ProposalActivated"
+	eventVoteCast          = "VoteCast"
+	eventDelegateChanged   = "DelegateChanged"
+	eventProposalExecuted  = "ProposalExecuted"
+)
+
+// governanceEventSchemas schemas of the events emitted by governance, saved on chain when the contract is installed
+var governanceEventSchemas = []*sdk.EventSchema{
+	sdk.NewEventSchema(eventProposalCreated,
+		sdk.NewEventField("proposalId", sdk.EventFieldUint, true),
+		sdk.NewEventField("proposer", sdk.EventFieldAddress, true),
+		sdk.NewEventField("target", sdk.EventFieldString, false),
+		sdk.NewEventField("method", sdk.EventFieldString, false),
+		sdk.NewEventField("title", sdk.EventFieldString, false),
+	),
+	sdk.NewEventSchema(eventProposalActivated,
+		sdk.NewEventField("proposalId", sdk.EventFieldUint, true),
+		sdk.NewEventField("snapshotHeight", sdk.EventFieldUint, false),
+		sdk.NewEventField("startHeight", sdk.EventFieldUint, false),
+		sdk.NewEventField("endHeight", sdk.EventFieldUint, false),
+	),
+	sdk.NewEventSchema(eventVoteCast,
+		sdk.NewEventField("proposalId", sdk.EventFieldUint, true),
+		sdk.NewEventField("voter", sdk.EventFieldAddress, true),
+		sdk.NewEventField("support", sdk.EventFieldString, false),
+		sdk.NewEventField("weight", sdk.EventFieldUint256, false),
+	),
+	sdk.NewEventSchema(eventDelegateChanged,
+		sdk.NewEventField("delegator", sdk.EventFieldAddress, true),
+		sdk.NewEventField("fromDelegate", sdk.EventFieldAddress, false),
+		sdk.NewEventField("toDelegate", sdk.EventFieldAddress, false),
+	),
+	sdk.NewEventSchema(eventProposalExecuted,
+		sdk.NewEventField("proposalId", sdk.EventFieldUint, true),
+		sdk.NewEventField("target", sdk.EventFieldString, false),
+		sdk.NewEventField("method", sdk.EventFieldString, false),
+		sdk.NewEventField("result", sdk.EventFieldBytes, false),
+	),
+}
+
+func init() {
+	if err := sdk.RegisterEventSchemas(governanceEventSchemas...); err != nil {
+		panic(err)
+	}
+}
+
+// EventSchemas returns the event schemas of governance
+func (c *GovernanceContract) EventSchemas() []*sdk.EventSchema {
+	return governanceEventSchemas
+}
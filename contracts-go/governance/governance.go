@@ -0,0 +1,921 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Governance contract, the voting weights are the balances of a standard-dfa token at the snapshot height
+of the proposal, read by CallContract of BalanceOfAt and TotalSupplyAt of the token.
+
+Lifecycle of a proposal: a proposal is created as draft by a token holder with at least proposalThreshold
+tokens, the proposer activates it and the voting window starts votingDelay blocks later and lasts
+votingPeriod blocks, with the snapshot at the block before the window. After the window the proposal is
+succeeded if the votes reach quorumPercent of the total supply at the snapshot and the for votes are more
+than thresholdPercent of the for and against votes, otherwise it is defeated. A succeeded proposal is
+executed once by anyone, which calls the method of the target contract with the args of the proposal.
+
+Delegation: an account delegates its weight to a delegatee, the delegatee votes with its own weight and
+the weights of its delegators who have not voted. A delegator can still vote itself, its weight is then
+taken from the vote of the delegatee. Delegation is one level and is snapshotted like the balances, every
+change is checkpointed with its block height and the delegatee votes with the accounts which delegated to
+it at the snapshot height of the proposal, so delegating after the snapshot does not move weight between
+votes. The delegators who left are kept for the proposals in the voting window, at most maxDelegators.
+*/
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/contract-utils/address"
+	"chainmaker.org/chainmaker/contract-utils/safemath"
+)
+
+const (
+	//db key
+	configKey             = "config"
+	proposalCountKey      = "proposalCount"
+	proposalKey           = "proposal"
+	receiptKey            = "receipt"
+	delegateKey           = "delegate"
+	delegatorsKey         = "delegators"
+	delegateCheckpointKey = "delegateCheckpoint"
+	formerDelegatorsKey   = "formerDelegators"
+
+	defaultVotingDelay      = 1
+	defaultVotingPeriod     = 100
+	defaultQuorumPercent    = 4
+	defaultThresholdPercent = 50
+
+	// maxDelegators bounds the weights read by one vote
+	maxDelegators = 200
+)
+
+// proposal states
+const (
+	StateDraft     = "draft"
+	StateActive    = "active"
+	StateSucceeded = "succeeded"
+	StateDefeated  = "defeated"
+	StateExecuted  = "executed"
+)
+
+// vote supports
+const (
+	SupportFor     = "for"
+	SupportAgainst = "against"
+	SupportAbstain = "abstain"
+)
+
+// GovernanceContract governance contract
+type GovernanceContract struct {
+}
+
+// Config governance config, set when the contract is installed
+type Config struct {
+	Token             string `json:"token"`
+	VotingDelay       uint64 `json:"votingDelay"`
+	VotingPeriod      uint64 `json:"votingPeriod"`
+	QuorumPercent     uint64 `json:"quorumPercent"`
+	ThresholdPercent  uint64 `json:"thresholdPercent"`
+	ProposalThreshold string `json:"proposalThreshold"`
+}
+
+// Proposal governance proposal, State is draft, active or executed in the store,
+// succeeded and defeated are decided after the voting window
+type Proposal struct {
+	Id             uint64            `json:"id"`
+	Proposer       string            `json:"proposer"`
+	Title          string            `json:"title"`
+	Description    string            `json:"description"`
+	Target         string            `json:"target"`
+	Method         string            `json:"method"`
+	Args           map[string]string `json:"args"`
+	State          string            `json:"state"`
+	CreatedHeight  uint64            `json:"createdHeight"`
+	SnapshotHeight uint64            `json:"snapshotHeight"`
+	StartHeight    uint64            `json:"startHeight"`
+	EndHeight      uint64            `json:"endHeight"`
+	ForVotes       string            `json:"forVotes"`
+	AgainstVotes   string            `json:"againstVotes"`
+	AbstainVotes   string            `json:"abstainVotes"`
+}
+
+// Tally votes of a proposal, Quorum is empty before the snapshot height
+type Tally struct {
+	ProposalId    uint64 `json:"proposalId"`
+	State         string `json:"state"`
+	ForVotes      string `json:"forVotes"`
+	AgainstVotes  string `json:"againstVotes"`
+	AbstainVotes  string `json:"abstainVotes"`
+	Quorum        string `json:"quorum,omitempty"`
+	QuorumReached bool   `json:"quorumReached"`
+	ThresholdMet  bool   `json:"thresholdMet"`
+}
+
+// Receipt vote of an account on a proposal. A direct vote counts the weights of the Delegators, a vote
+// counted by the delegatee has CountedBy set and is replaced if the account votes itself
+type Receipt struct {
+	Support    string   `json:"support"`
+	Weight     string   `json:"weight"`
+	Direct     bool     `json:"direct"`
+	CountedBy  string   `json:"countedBy,omitempty"`
+	Delegators []string `json:"delegators,omitempty"`
+}
+
+// DelegateCheckpoint the delegatee of an account from Height on, empty after undelegating
+type DelegateCheckpoint struct {
+	Height    uint64 `json:"height"`
+	Delegatee string `json:"delegatee"`
+}
+
+// formerDelegator a delegator who left the delegatee at Height, it still delegates to the delegatee at
+// the snapshots before Height
+type formerDelegator struct {
+	Delegator string `json:"delegator"`
+	Height    uint64 `json:"height"`
+}
+
+// CreateProposal 创建草案状态的提案，提案人在上一区块的余额需不少于proposalThreshold
+// @param title
+// @param description
+// @param target 通过后调用的合约
+// @param method 通过后调用的合约方法
+// @param args 调用参数
+// @return uint64 提案ID
+// @return error
+func (c *GovernanceContract) CreateProposal(title, description, target, method string,
+	args map[string]string) (uint64, error) {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return 0, fmt.Errorf("get sender failed, err:%s", err)
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return 0, err
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	if err = c.requireProposer(config, sender, height); err != nil {
+		return 0, err
+	}
+
+	countStr, err := sdk.Instance.GetState(proposalCountKey, "")
+	if err != nil {
+		return 0, err
+	}
+	var count uint64
+	if len(countStr) > 0 {
+		if count, err = strconv.ParseUint(countStr, 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	proposal := &Proposal{
+		Id:            count + 1,
+		Proposer:      sender,
+		Title:         title,
+		Description:   description,
+		Target:        target,
+		Method:        method,
+		Args:          args,
+		State:         StateDraft,
+		CreatedHeight: height,
+		ForVotes:      "0",
+		AgainstVotes:  "0",
+		AbstainVotes:  "0",
+	}
+	if err = c.setProposal(proposal); err != nil {
+		return 0, err
+	}
+	if err = sdk.Instance.PutState(proposalCountKey, "", strconv.FormatUint(proposal.Id, 10)); err != nil {
+		return 0, err
+	}
+	if err = sdk.EmitTypedEvent(eventProposalCreated, proposal.Id, sender, target, method, title); err != nil {
+		return 0, err
+	}
+	return proposal.Id, nil
+}
+
+// ActivateProposal 提案人激活草案，投票窗口在votingDelay个区块后开始
+// @param id
+// @return error
+func (c *GovernanceContract) ActivateProposal(id uint64) error {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("get sender failed, err:%s", err)
+	}
+	proposal, err := c.getProposal(id)
+	if err != nil {
+		return err
+	}
+	if proposal.Proposer != sender {
+		return errors.New("GOV: only the proposer can activate the proposal")
+	}
+	if proposal.State != StateDraft {
+		return fmt.Errorf("GOV: proposal %d is not a draft", id)
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return err
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return err
+	}
+	// the proposer may have moved the tokens since the draft
+	if err = c.requireProposer(config, sender, height); err != nil {
+		return err
+	}
+
+	proposal.State = StateActive
+	proposal.StartHeight = height + config.VotingDelay
+	proposal.EndHeight = proposal.StartHeight + config.VotingPeriod - 1
+	proposal.SnapshotHeight = proposal.StartHeight - 1
+	if err = c.setProposal(proposal); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventProposalActivated, id, proposal.SnapshotHeight, proposal.StartHeight,
+		proposal.EndHeight)
+}
+
+// CastVote 在投票窗口内投票，权重为快照高度的余额加上快照高度委托给投票人且未投票的委托人的余额
+// @param id
+// @param support for, against or abstain
+// @return error
+func (c *GovernanceContract) CastVote(id uint64, support string) error {
+	if support != SupportFor && support != SupportAgainst && support != SupportAbstain {
+		return fmt.Errorf("GOV: support should be %s, %s or %s", SupportFor, SupportAgainst, SupportAbstain)
+	}
+	voter, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("get sender failed, err:%s", err)
+	}
+	proposal, err := c.getProposal(id)
+	if err != nil {
+		return err
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return err
+	}
+	if proposal.State != StateActive || height < proposal.StartHeight || height > proposal.EndHeight {
+		return fmt.Errorf("GOV: proposal %d is not in the voting window", id)
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	receipt, err := c.GetReceipt(id, voter)
+	if err != nil {
+		return err
+	}
+	if receipt != nil && receipt.Direct {
+		return fmt.Errorf("GOV: %s already voted on proposal %d", voter, id)
+	}
+	if receipt != nil {
+		// take the weight back from the vote of the delegatee
+		if err = c.uncount(proposal, voter, receipt); err != nil {
+			return err
+		}
+	}
+
+	weight, err := c.balanceAt(config, voter, proposal.SnapshotHeight)
+	if err != nil {
+		return err
+	}
+	delegators, err := c.delegatorsAt(voter, proposal.SnapshotHeight)
+	if err != nil {
+		return err
+	}
+	var counted []string
+	for _, delegator := range delegators {
+		delegatorReceipt, err1 := c.GetReceipt(id, delegator)
+		if err1 != nil {
+			return err1
+		}
+		if delegatorReceipt != nil {
+			continue
+		}
+		balance, err1 := c.balanceAt(config, delegator, proposal.SnapshotHeight)
+		if err1 != nil {
+			return err1
+		}
+		if balance.ToString() == "0" {
+			continue
+		}
+		delegatorReceipt = &Receipt{Support: support, Weight: balance.ToString(), CountedBy: voter}
+		if err1 = c.setReceipt(id, delegator, delegatorReceipt); err1 != nil {
+			return err1
+		}
+		weight, _ = safemath.SafeAdd(weight, balance)
+		counted = append(counted, delegator)
+	}
+	if weight.ToString() == "0" {
+		return fmt.Errorf("GOV: %s has no voting weight at height %d", voter, proposal.SnapshotHeight)
+	}
+
+	if err = addVotes(proposal, support, weight); err != nil {
+		return err
+	}
+	if err = c.setProposal(proposal); err != nil {
+		return err
+	}
+	receipt = &Receipt{Support: support, Weight: weight.ToString(), Direct: true, Delegators: counted}
+	if err = c.setReceipt(id, voter, receipt); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventVoteCast, id, voter, support, weight)
+}
+
+// Execute 执行已通过的提案，调用目标合约的方法，调用失败时交易失败，提案可再次执行
+// @param id
+// @return []byte 目标合约的返回
+// @return error
+func (c *GovernanceContract) Execute(id uint64) ([]byte, error) {
+	proposal, err := c.getProposal(id)
+	if err != nil {
+		return nil, err
+	}
+	tally, err := c.tally(proposal)
+	if err != nil {
+		return nil, err
+	}
+	if tally.State != StateSucceeded {
+		return nil, fmt.Errorf("GOV: proposal %d is %s, not succeeded", id, tally.State)
+	}
+	// mark executed before calling the target
+	proposal.State = StateExecuted
+	if err = c.setProposal(proposal); err != nil {
+		return nil, err
+	}
+
+	args := make(map[string][]byte, len(proposal.Args))
+	for key, value := range proposal.Args {
+		args[key] = []byte(value)
+	}
+	resp := sdk.Instance.CallContract(proposal.Target, proposal.Method, args)
+	if resp.Status != sdk.OK {
+		return nil, fmt.Errorf("GOV: execute proposal %d failed, %s", id, resp.Message)
+	}
+	if err = sdk.EmitTypedEvent(eventProposalExecuted, id, proposal.Target, proposal.Method,
+		resp.Payload); err != nil {
+		return nil, err
+	}
+	return resp.Payload, nil
+}
+
+// Delegate 将调用者的投票权重委托给delegatee，对快照高度在当前区块之后的提案生效
+// @param delegatee
+// @return error
+func (c *GovernanceContract) Delegate(delegatee string) error {
+	delegator, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("get sender failed, err:%s", err)
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return err
+	}
+	if !address.IsValidAddress(delegatee) || address.IsZeroAddress(delegatee) {
+		return errors.New("GOV: delegate to the invalid address")
+	}
+	if delegatee == delegator {
+		return errors.New("GOV: delegate to self")
+	}
+	old, err := c.GetDelegate(delegator)
+	if err != nil {
+		return err
+	}
+	if old == delegatee {
+		return nil
+	}
+	if len(old) > 0 {
+		if err = c.removeDelegator(old, delegator, height); err != nil {
+			return err
+		}
+	}
+	delegators, err := c.GetDelegators(delegatee)
+	if err != nil {
+		return err
+	}
+	if len(delegators) >= maxDelegators {
+		return fmt.Errorf("GOV: %s has %d delegators already", delegatee, maxDelegators)
+	}
+	if err = c.setDelegators(delegatee, append(delegators, delegator)); err != nil {
+		return err
+	}
+	if err = sdk.Instance.PutState(delegateKey, delegator, delegatee); err != nil {
+		return err
+	}
+	if err = c.checkpointDelegate(delegator, old, delegatee, height); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventDelegateChanged, delegator, old, delegatee)
+}
+
+// Undelegate 取消调用者的委托，对快照高度在当前区块之后的提案生效
+// @return error
+func (c *GovernanceContract) Undelegate() error {
+	delegator, err := sdk.Instance.Sender()
+	if err != nil {
+		return fmt.Errorf("get sender failed, err:%s", err)
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return err
+	}
+	old, err := c.GetDelegate(delegator)
+	if err != nil {
+		return err
+	}
+	if len(old) == 0 {
+		return errors.New("GOV: no delegate")
+	}
+	if err = c.removeDelegator(old, delegator, height); err != nil {
+		return err
+	}
+	if err = sdk.Instance.DelState(delegateKey, delegator); err != nil {
+		return err
+	}
+	if err = c.checkpointDelegate(delegator, old, "", height); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventDelegateChanged, delegator, old, "")
+}
+
+// GetProposal 查询提案，state为当前状态
+// @param id
+// @return *Proposal
+// @return error
+func (c *GovernanceContract) GetProposal(id uint64) (*Proposal, error) {
+	proposal, err := c.getProposal(id)
+	if err != nil {
+		return nil, err
+	}
+	tally, err := c.tally(proposal)
+	if err != nil {
+		return nil, err
+	}
+	proposal.State = tally.State
+	return proposal, nil
+}
+
+// GetTally 查询提案的计票
+// @param id
+// @return *Tally
+// @return error
+func (c *GovernanceContract) GetTally(id uint64) (*Tally, error) {
+	proposal, err := c.getProposal(id)
+	if err != nil {
+		return nil, err
+	}
+	return c.tally(proposal)
+}
+
+// GetReceipt 查询账户在提案上的投票，未投票时为nil
+// @param id
+// @param voter
+// @return *Receipt
+// @return error
+func (c *GovernanceContract) GetReceipt(id uint64, voter string) (*Receipt, error) {
+	data, err := sdk.Instance.GetStateByte(receiptKey+strconv.FormatUint(id, 10), voter)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	var receipt Receipt
+	if err = json.Unmarshal(data, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// GetDelegate 查询账户当前的委托对象
+// @param account
+// @return string
+// @return error
+func (c *GovernanceContract) GetDelegate(account string) (string, error) {
+	return sdk.Instance.GetState(delegateKey, account)
+}
+
+// GetDelegators 查询当前委托给账户的委托人
+// @param account
+// @return []string
+// @return error
+func (c *GovernanceContract) GetDelegators(account string) ([]string, error) {
+	data, err := sdk.Instance.GetStateByte(delegatorsKey, account)
+	if err != nil || len(data) == 0 {
+		return []string{}, err
+	}
+	var delegators []string
+	if err = json.Unmarshal(data, &delegators); err != nil {
+		return nil, err
+	}
+	return delegators, nil
+}
+
+// GetConfig 查询配置
+// @return *Config
+// @return error
+func (c *GovernanceContract) GetConfig() (*Config, error) {
+	data, err := sdk.Instance.GetStateByte(configKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("GOV: config not found")
+	}
+	var config Config
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SetConfig 设置配置
+// @param config
+// @return error
+func (c *GovernanceContract) SetConfig(config *Config) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(configKey, "", data)
+}
+
+// tally count the votes, the quorum is read from the token after the snapshot height
+func (c *GovernanceContract) tally(proposal *Proposal) (*Tally, error) {
+	tally := &Tally{
+		ProposalId:   proposal.Id,
+		State:        proposal.State,
+		ForVotes:     proposal.ForVotes,
+		AgainstVotes: proposal.AgainstVotes,
+		AbstainVotes: proposal.AbstainVotes,
+	}
+	if proposal.State == StateDraft {
+		return tally, nil
+	}
+	height, err := blockHeight()
+	if err != nil {
+		return nil, err
+	}
+	if height <= proposal.SnapshotHeight {
+		return tally, nil
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	supply, err := c.callToken(config, "TotalSupplyAt", map[string][]byte{
+		"height": []byte(strconv.FormatUint(proposal.SnapshotHeight, 10)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	forVotes, againstVotes, abstainVotes := toBig(proposal.ForVotes), toBig(proposal.AgainstVotes),
+		toBig(proposal.AbstainVotes)
+	quorum := new(big.Int).Mul(toBig(supply.ToString()), new(big.Int).SetUint64(config.QuorumPercent))
+	quorum.Div(quorum, big.NewInt(100))
+	total := new(big.Int).Add(forVotes, againstVotes)
+	total.Add(total, abstainVotes)
+	tally.Quorum = quorum.String()
+	tally.QuorumReached = total.Sign() > 0 && total.Cmp(quorum) >= 0
+	// for * 100 > threshold * (for + against)
+	forWeighted := new(big.Int).Mul(forVotes, big.NewInt(100))
+	thresholdWeighted := new(big.Int).Mul(new(big.Int).Add(forVotes, againstVotes),
+		new(big.Int).SetUint64(config.ThresholdPercent))
+	tally.ThresholdMet = forWeighted.Cmp(thresholdWeighted) > 0
+
+	if proposal.State == StateActive && height > proposal.EndHeight {
+		if tally.QuorumReached && tally.ThresholdMet {
+			tally.State = StateSucceeded
+		} else {
+			tally.State = StateDefeated
+		}
+	}
+	return tally, nil
+}
+
+// uncount take the weight of the voter from the vote of its delegatee
+func (c *GovernanceContract) uncount(proposal *Proposal, voter string, receipt *Receipt) error {
+	weight, ok := safemath.ParseSafeUint256(receipt.Weight)
+	if !ok {
+		return errors.New("GOV: invalid receipt weight")
+	}
+	if err := subVotes(proposal, receipt.Support, weight); err != nil {
+		return err
+	}
+	delegateeReceipt, err := c.GetReceipt(proposal.Id, receipt.CountedBy)
+	if err != nil {
+		return err
+	}
+	if delegateeReceipt == nil {
+		return errors.New("GOV: receipt of the delegatee not found")
+	}
+	delegateeWeight, ok := safemath.ParseSafeUint256(delegateeReceipt.Weight)
+	if !ok {
+		return errors.New("GOV: invalid receipt weight")
+	}
+	delegateeWeight, ok = safemath.SafeSub(delegateeWeight, weight)
+	if !ok {
+		return errors.New("GOV: invalid receipt weight")
+	}
+	delegateeReceipt.Weight = delegateeWeight.ToString()
+	delegateeReceipt.Delegators = removeString(delegateeReceipt.Delegators, voter)
+	return c.setReceipt(proposal.Id, receipt.CountedBy, delegateeReceipt)
+}
+
+// requireProposer the proposer should hold proposalThreshold tokens at the last block
+func (c *GovernanceContract) requireProposer(config *Config, proposer string, height uint64) error {
+	threshold, ok := safemath.ParseSafeUint256(config.ProposalThreshold)
+	if !ok {
+		return errors.New("GOV: invalid proposalThreshold")
+	}
+	if threshold.ToString() == "0" {
+		return nil
+	}
+	if height == 0 {
+		return errors.New("GOV: no block to check the proposer balance")
+	}
+	balance, err := c.balanceAt(config, proposer, height-1)
+	if err != nil {
+		return err
+	}
+	if !balance.GTE(threshold) {
+		return fmt.Errorf("GOV: proposer should hold at least %s tokens", threshold.ToString())
+	}
+	return nil
+}
+
+func (c *GovernanceContract) balanceAt(config *Config, account string, height uint64) (*safemath.SafeUint256, error) {
+	return c.callToken(config, "BalanceOfAt", map[string][]byte{
+		"account": []byte(account),
+		"height":  []byte(strconv.FormatUint(height, 10)),
+	})
+}
+
+// callToken call the query method of the token which returns an uint256
+func (c *GovernanceContract) callToken(config *Config, method string,
+	args map[string][]byte) (*safemath.SafeUint256, error) {
+	resp := sdk.Instance.CallContract(config.Token, method, args)
+	if resp.Status != sdk.OK {
+		return nil, fmt.Errorf("GOV: call %s of token %s failed, %s", method, config.Token, resp.Message)
+	}
+	num, ok := safemath.ParseSafeUint256(string(resp.Payload))
+	if !ok {
+		return nil, fmt.Errorf("GOV: invalid %s of token %s", method, config.Token)
+	}
+	return num, nil
+}
+
+func (c *GovernanceContract) getProposal(id uint64) (*Proposal, error) {
+	data, err := sdk.Instance.GetStateByte(proposalKey, strconv.FormatUint(id, 10))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("GOV: proposal %d not found", id)
+	}
+	var proposal Proposal
+	if err = json.Unmarshal(data, &proposal); err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+func (c *GovernanceContract) setProposal(proposal *Proposal) error {
+	data, err := json.Marshal(proposal)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(proposalKey, strconv.FormatUint(proposal.Id, 10), data)
+}
+
+func (c *GovernanceContract) setReceipt(id uint64, voter string, receipt *Receipt) error {
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(receiptKey+strconv.FormatUint(id, 10), voter, data)
+}
+
+func (c *GovernanceContract) setDelegators(account string, delegators []string) error {
+	data, err := json.Marshal(delegators)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(delegatorsKey, account, data)
+}
+
+// removeDelegator remove the delegator from the delegatee and keep it as a former delegator, the former
+// delegators who left before the snapshots of the proposals in the voting window are dropped
+func (c *GovernanceContract) removeDelegator(delegatee, delegator string, height uint64) error {
+	delegators, err := c.GetDelegators(delegatee)
+	if err != nil {
+		return err
+	}
+	if err = c.setDelegators(delegatee, removeString(delegators, delegator)); err != nil {
+		return err
+	}
+
+	config, err := c.GetConfig()
+	if err != nil {
+		return err
+	}
+	formers, err := c.getFormerDelegators(delegatee)
+	if err != nil {
+		return err
+	}
+	kept := make([]*formerDelegator, 0, len(formers)+1)
+	for _, former := range formers {
+		// a proposal in the voting window has its snapshot after height - votingPeriod
+		if former.Delegator != delegator && former.Height+config.VotingPeriod > height {
+			kept = append(kept, former)
+		}
+	}
+	kept = append(kept, &formerDelegator{Delegator: delegator, Height: height})
+	if len(kept) > maxDelegators {
+		kept = kept[len(kept)-maxDelegators:]
+	}
+	data, err := json.Marshal(kept)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(formerDelegatorsKey, delegatee, data)
+}
+
+func (c *GovernanceContract) getFormerDelegators(delegatee string) ([]*formerDelegator, error) {
+	data, err := sdk.Instance.GetStateByte(formerDelegatorsKey, delegatee)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	var formers []*formerDelegator
+	if err = json.Unmarshal(data, &formers); err != nil {
+		return nil, err
+	}
+	return formers, nil
+}
+
+// delegatorsAt the accounts delegating to the delegatee at height, from its current delegators and
+// the delegators who left after height
+func (c *GovernanceContract) delegatorsAt(delegatee string, height uint64) ([]string, error) {
+	candidates, err := c.GetDelegators(delegatee)
+	if err != nil {
+		return nil, err
+	}
+	formers, err := c.getFormerDelegators(delegatee)
+	if err != nil {
+		return nil, err
+	}
+	for _, former := range formers {
+		if former.Height > height && !containsString(candidates, former.Delegator) {
+			candidates = append(candidates, former.Delegator)
+		}
+	}
+	delegators := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		at, err1 := c.delegateAt(candidate, height)
+		if err1 != nil {
+			return nil, err1
+		}
+		if at == delegatee {
+			delegators = append(delegators, candidate)
+		}
+	}
+	return delegators, nil
+}
+
+// delegateAt the delegatee of the account at height, an account delegating before the checkpoints
+// were kept delegates to its current delegatee at any height
+func (c *GovernanceContract) delegateAt(account string, height uint64) (string, error) {
+	checkpoints, err := c.getDelegateCheckpoints(account)
+	if err != nil {
+		return "", err
+	}
+	if len(checkpoints) == 0 {
+		return c.GetDelegate(account)
+	}
+	for i := len(checkpoints) - 1; i >= 0; i-- {
+		if checkpoints[i].Height <= height {
+			return checkpoints[i].Delegatee, nil
+		}
+	}
+	return "", nil
+}
+
+// checkpointDelegate record the delegatee of the account from height on, the first checkpoint of an
+// account delegating before the checkpoints were kept records its old delegatee from height 0
+func (c *GovernanceContract) checkpointDelegate(account, old, delegatee string, height uint64) error {
+	checkpoints, err := c.getDelegateCheckpoints(account)
+	if err != nil {
+		return err
+	}
+	if len(checkpoints) == 0 && len(old) > 0 {
+		checkpoints = append(checkpoints, &DelegateCheckpoint{Delegatee: old})
+	}
+	if n := len(checkpoints); n > 0 && checkpoints[n-1].Height == height {
+		checkpoints[n-1].Delegatee = delegatee
+	} else {
+		checkpoints = append(checkpoints, &DelegateCheckpoint{Height: height, Delegatee: delegatee})
+	}
+	data, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(delegateCheckpointKey, account, data)
+}
+
+func (c *GovernanceContract) getDelegateCheckpoints(account string) ([]*DelegateCheckpoint, error) {
+	data, err := sdk.Instance.GetStateByte(delegateCheckpointKey, account)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	var checkpoints []*DelegateCheckpoint
+	if err = json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+func addVotes(proposal *Proposal, support string, weight *safemath.SafeUint256) error {
+	votes := supportVotes(proposal, support)
+	current, ok := safemath.ParseSafeUint256(*votes)
+	if !ok {
+		return errors.New("GOV: invalid votes")
+	}
+	sum, ok := safemath.SafeAdd(current, weight)
+	if !ok {
+		return errors.New("GOV: votes overflow")
+	}
+	*votes = sum.ToString()
+	return nil
+}
+
+func subVotes(proposal *Proposal, support string, weight *safemath.SafeUint256) error {
+	votes := supportVotes(proposal, support)
+	current, ok := safemath.ParseSafeUint256(*votes)
+	if !ok {
+		return errors.New("GOV: invalid votes")
+	}
+	diff, ok := safemath.SafeSub(current, weight)
+	if !ok {
+		return errors.New("GOV: invalid votes")
+	}
+	*votes = diff.ToString()
+	return nil
+}
+
+func supportVotes(proposal *Proposal, support string) *string {
+	switch support {
+	case SupportFor:
+		return &proposal.ForVotes
+	case SupportAgainst:
+		return &proposal.AgainstVotes
+	default:
+		return &proposal.AbstainVotes
+	}
+}
+
+func removeString(list []string, s string) []string {
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func toBig(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return n
+}
+
+func blockHeight() (uint64, error) {
+	height, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return 0, fmt.Errorf("get block height failed, err:%s", err)
+	}
+	return uint64(height), nil
+}
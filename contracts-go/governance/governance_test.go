@@ -0,0 +1,390 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	alice = "a04f7895de24f61807a729be230f03da8c0eef42"
+	bob   = "b04f7895de24f61807a729be230f03da8c0eef42"
+	carol = "c04f7895de24f61807a729be230f03da8c0eef42"
+	dave  = "d04f7895de24f61807a729be230f03da8c0eef42"
+	token = "cmdfa"
+)
+
+// mockGetPutState keeps the state of a test in the returned map by key#field
+func mockGetPutState(mockInstance *sdk.MockSDKInterface) map[string][]byte {
+	stateMap := make(map[string][]byte)
+	mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) (string, error) {
+			return string(stateMap[key+"#"+field]), nil
+		})
+	mockInstance.EXPECT().PutState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field, value string) error {
+			stateMap[key+"#"+field] = []byte(value)
+			return nil
+		})
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) ([]byte, error) {
+			return stateMap[key+"#"+field], nil
+		})
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string, value []byte) error {
+			stateMap[key+"#"+field] = value
+			return nil
+		})
+	mockInstance.EXPECT().DelState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) error {
+			delete(stateMap, key+"#"+field)
+			return nil
+		})
+	return stateMap
+}
+
+// mockCallContract the token contract answers with the balances at the snapshot, the total supply under "",
+// the args of a call to any other contract are copied to executed
+func mockCallContract(mockInstance *sdk.MockSDKInterface, balances map[string]string, executed map[string][]byte) {
+	mockInstance.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(contractName, method string, callArgs map[string][]byte) protogo.Response {
+			if contractName != token {
+				for k, v := range callArgs {
+					executed[k] = v
+				}
+				return sdk.Success([]byte("done"))
+			}
+			switch method {
+			case "BalanceOfAt":
+				balance, ok := balances[string(callArgs["account"])]
+				if !ok {
+					balance = "0"
+				}
+				return sdk.Success([]byte(balance))
+			case "TotalSupplyAt":
+				return sdk.Success([]byte(balances[""]))
+			default:
+				return sdk.Error("Invalid method")
+			}
+		})
+}
+
+// step one call of a test by sender at height, check returns an error when the result is not the wanted one
+type step struct {
+	name    string
+	height  int
+	sender  string
+	check   func(c *GovernanceContract) error
+	wantErr bool
+}
+
+func wantState(id uint64, state string) func(c *GovernanceContract) error {
+	return func(c *GovernanceContract) error {
+		proposal, err := c.GetProposal(id)
+		if err != nil {
+			return err
+		}
+		if proposal.State != state {
+			return fmt.Errorf("state = %s, want %s", proposal.State, state)
+		}
+		return nil
+	}
+}
+
+func wantReceipt(id uint64, voter string, want *Receipt) func(c *GovernanceContract) error {
+	return func(c *GovernanceContract) error {
+		receipt, err := c.GetReceipt(id, voter)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(receipt, want) {
+			return fmt.Errorf("GetReceipt() = %+v, want %+v", receipt, want)
+		}
+		return nil
+	}
+}
+
+func wantDelegators(account string, want []string) func(c *GovernanceContract) error {
+	return func(c *GovernanceContract) error {
+		delegators, err := c.GetDelegators(account)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(delegators, want) {
+			return fmt.Errorf("GetDelegators() = %v, want %v", delegators, want)
+		}
+		return nil
+	}
+}
+
+func TestGovernanceContract_InvokeContract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(map[string][]byte{})
+	mockInstance.EXPECT().Sender().AnyTimes().Return(alice, nil)
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().Return(1, nil)
+	mockGetPutState(mockInstance)
+	sdk.Instance = mockInstance
+
+	tests := []struct {
+		name   string
+		method string
+		want   protogo.Response
+	}{
+		{name: "create", method: "CreateProposal", want: sdk.Error("GOV: require parameter:'title'")},
+		{name: "activate", method: "ActivateProposal", want: sdk.Error("GOV: require parameter:'proposalId'")},
+		{name: "vote", method: "CastVote", want: sdk.Error("GOV: require parameter:'proposalId'")},
+		{name: "delegate", method: "Delegate", want: sdk.Error("GOV: require parameter:'delegatee'")},
+		{name: "undelegate", method: "Undelegate", want: sdk.Error("GOV: no delegate")},
+		{name: "delegators", method: "GetDelegators", want: sdk.Error("GOV: require parameter:'account'")},
+		{name: "config", method: "GetConfig", want: sdk.Error("GOV: config not found")},
+		{name: "invalid", method: "invalid", want: sdk.Error("Invalid method")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &GovernanceContract{}
+			if got := c.InvokeContract(tt.method); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvokeContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGovernanceContract_lifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, height := alice, 1
+	args := map[string][]byte{
+		pToken:             []byte(token),
+		pVotingDelay:       []byte("2"),
+		pVotingPeriod:      []byte("10"),
+		pQuorumPercent:     []byte("50"),
+		pProposalThreshold: []byte("10"),
+	}
+	executed := map[string][]byte{}
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(args)
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return height, nil })
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockCallContract(mockInstance, map[string]string{alice: "40", bob: "30", carol: "20", "": "100"}, executed)
+	sdk.Instance = mockInstance
+
+	c := &GovernanceContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	const id = uint64(1)
+	steps := []step{
+		{name: "proposer without tokens", height: 5, sender: dave, wantErr: true,
+			check: func(c *GovernanceContract) error {
+				_, err := c.CreateProposal("t", "", "target", "Exec", nil)
+				return err
+			}},
+		{name: "create", height: 5, sender: alice, check: func(c *GovernanceContract) error {
+			got, err := c.CreateProposal("t", "desc", "target", "Exec", map[string]string{"k": "v"})
+			if err == nil && got != id {
+				err = fmt.Errorf("CreateProposal() = %d, want %d", got, id)
+			}
+			return err
+		}},
+		// bob and carol delegate to alice
+		{name: "bob delegates", height: 5, sender: bob,
+			check: func(c *GovernanceContract) error { return c.Delegate(alice) }},
+		{name: "carol delegates", height: 5, sender: carol,
+			check: func(c *GovernanceContract) error { return c.Delegate(alice) }},
+		{name: "delegators", height: 5, sender: alice, check: wantDelegators(alice, []string{bob, carol})},
+		{name: "activate by others", height: 5, sender: bob, wantErr: true,
+			check: func(c *GovernanceContract) error { return c.ActivateProposal(id) }},
+		{name: "activate", height: 5, sender: alice,
+			check: func(c *GovernanceContract) error { return c.ActivateProposal(id) }},
+		{name: "window", height: 5, sender: alice, check: func(c *GovernanceContract) error {
+			proposal, err := c.GetProposal(id)
+			if err == nil && (proposal.State != StateActive || proposal.SnapshotHeight != 6 ||
+				proposal.StartHeight != 7 || proposal.EndHeight != 16) {
+				err = fmt.Errorf("GetProposal() = %+v", proposal)
+			}
+			return err
+		}},
+		{name: "vote before the window", height: 5, sender: alice, wantErr: true,
+			check: func(c *GovernanceContract) error { return c.CastVote(id, SupportFor) }},
+		{name: "vote", height: 7, sender: alice,
+			check: func(c *GovernanceContract) error { return c.CastVote(id, SupportFor) }},
+		{name: "vote twice", height: 7, sender: alice, wantErr: true,
+			check: func(c *GovernanceContract) error { return c.CastVote(id, SupportFor) }},
+		// bob overrides the vote of alice
+		{name: "override", height: 7, sender: bob,
+			check: func(c *GovernanceContract) error { return c.CastVote(id, SupportAgainst) }},
+		{name: "receipt", height: 7, sender: alice, check: wantReceipt(id, alice,
+			&Receipt{Support: SupportFor, Weight: "60", Direct: true, Delegators: []string{carol}})},
+		{name: "tally", height: 7, sender: alice, check: func(c *GovernanceContract) error {
+			want := &Tally{ProposalId: id, State: StateActive, ForVotes: "60", AgainstVotes: "30",
+				AbstainVotes: "0", Quorum: "50", QuorumReached: true, ThresholdMet: true}
+			tally, err := c.GetTally(id)
+			if err == nil && !reflect.DeepEqual(tally, want) {
+				err = fmt.Errorf("GetTally() = %+v, want %+v", tally, want)
+			}
+			return err
+		}},
+		{name: "execute in the window", height: 7, sender: alice, wantErr: true,
+			check: func(c *GovernanceContract) error {
+				_, err := c.Execute(id)
+				return err
+			}},
+		{name: "succeeded", height: 17, sender: alice, check: wantState(id, StateSucceeded)},
+		{name: "execute", height: 17, sender: alice, check: func(c *GovernanceContract) error {
+			result, err := c.Execute(id)
+			if err == nil && (string(result) != "done" ||
+				!reflect.DeepEqual(executed, map[string][]byte{"k": []byte("v")})) {
+				err = fmt.Errorf("Execute() = %s, executed %v", result, executed)
+			}
+			return err
+		}},
+		{name: "execute twice", height: 17, sender: alice, wantErr: true,
+			check: func(c *GovernanceContract) error {
+				_, err := c.Execute(id)
+				return err
+			}},
+		{name: "executed", height: 17, sender: alice, check: wantState(id, StateExecuted)},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender, height = s.sender, s.height
+			if err := s.check(c); (err != nil) != s.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, s.wantErr)
+			}
+		})
+	}
+}
+
+func TestGovernanceContract_defeated(t *testing.T) {
+	tests := []struct {
+		name  string
+		votes map[string]string
+	}{
+		{name: "quorum not reached", votes: map[string]string{alice: SupportFor}},
+		{name: "threshold not met", votes: map[string]string{alice: SupportAgainst, bob: SupportFor}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+			sender, height := alice, 1
+			args := map[string][]byte{
+				pToken:         []byte(token),
+				pQuorumPercent: []byte("50"),
+			}
+			mockInstance.EXPECT().GetArgs().AnyTimes().Return(args)
+			mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+			mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return height, nil })
+			mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+			mockGetPutState(mockInstance)
+			mockCallContract(mockInstance, map[string]string{alice: "40", bob: "30", "": "100"}, map[string][]byte{})
+			sdk.Instance = mockInstance
+
+			c := &GovernanceContract{}
+			if resp := c.InitContract(); resp.Status != sdk.OK {
+				t.Fatalf("InitContract() = %v", resp)
+			}
+			id, err := c.CreateProposal("t", "", "target", "Exec", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = c.ActivateProposal(id); err != nil {
+				t.Fatal(err)
+			}
+			proposal, _ := c.GetProposal(id)
+			height = int(proposal.StartHeight)
+			for voter, support := range tt.votes {
+				sender = voter
+				if err = c.CastVote(id, support); err != nil {
+					t.Fatal(err)
+				}
+			}
+			height = int(proposal.EndHeight) + 1
+			if proposal, _ = c.GetProposal(id); proposal.State != StateDefeated {
+				t.Errorf("state = %s, want %s", proposal.State, StateDefeated)
+			}
+			if _, err = c.Execute(id); err == nil {
+				t.Errorf("Execute() of defeated proposal should fail")
+			}
+		})
+	}
+}
+
+func TestGovernanceContract_delegationSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, height := alice, 1
+	args := map[string][]byte{
+		pToken:        []byte(token),
+		pVotingDelay:  []byte("2"),
+		pVotingPeriod: []byte("10"),
+	}
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(args)
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return height, nil })
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockCallContract(mockInstance, map[string]string{alice: "40", bob: "30", carol: "20", "": "100"},
+		map[string][]byte{})
+	sdk.Instance = mockInstance
+
+	c := &GovernanceContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	const id = uint64(1)
+	steps := []step{
+		{name: "bob delegates", height: 5, sender: bob,
+			check: func(c *GovernanceContract) error { return c.Delegate(alice) }},
+		{name: "create", height: 5, sender: alice, check: func(c *GovernanceContract) error {
+			_, err := c.CreateProposal("t", "", "target", "Exec", nil)
+			return err
+		}},
+		{name: "activate", height: 5, sender: alice,
+			check: func(c *GovernanceContract) error { return c.ActivateProposal(id) }},
+		// after the snapshot at 6, carol delegates to alice and bob moves to carol
+		{name: "carol delegates", height: 7, sender: carol,
+			check: func(c *GovernanceContract) error { return c.Delegate(alice) }},
+		{name: "bob moves", height: 7, sender: bob,
+			check: func(c *GovernanceContract) error { return c.Delegate(carol) }},
+		{name: "delegators", height: 7, sender: alice, check: wantDelegators(alice, []string{carol})},
+		// alice votes with the weight of bob, who delegated to her at the snapshot
+		{name: "alice votes", height: 7, sender: alice,
+			check: func(c *GovernanceContract) error { return c.CastVote(id, SupportFor) }},
+		{name: "alice receipt", height: 7, sender: alice, check: wantReceipt(id, alice,
+			&Receipt{Support: SupportFor, Weight: "70", Direct: true, Delegators: []string{bob}})},
+		{name: "carol votes", height: 7, sender: carol,
+			check: func(c *GovernanceContract) error { return c.CastVote(id, SupportAgainst) }},
+		{name: "carol receipt", height: 7, sender: carol, check: wantReceipt(id, carol,
+			&Receipt{Support: SupportAgainst, Weight: "20", Direct: true})},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender, height = s.sender, s.height
+			if err := s.check(c); (err != nil) != s.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, s.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/contract-utils/safemath"
+)
+
+const (
+	pToken             = "token"
+	pVotingDelay       = "votingDelay"
+	pVotingPeriod      = "votingPeriod"
+	pQuorumPercent     = "quorumPercent"
+	pThresholdPercent  = "thresholdPercent"
+	pProposalThreshold = "proposalThreshold"
+	pProposalId        = "proposalId"
+	pTitle             = "title"
+	pDescription       = "description"
+	pTarget            = "target"
+	pMethod            = "method"
+	pArgs              = "args"
+	pSupport           = "support"
+	pVoter             = "voter"
+	pAccount           = "account"
+	pDelegatee         = "delegatee"
+)
+
+func main() {
+	err := sandbox.Start(new(GovernanceContract))
+	if err != nil {
+		sdk.Instance.Errorf(err.Error())
+	}
+}
+
+// InitContract install contract func
+func (c *GovernanceContract) InitContract() protogo.Response {
+	err := c.initConfig()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("Init contract success"))
+}
+
+// UpgradeContract upgrade contract func, the config is kept
+func (c *GovernanceContract) UpgradeContract() protogo.Response {
+	return sdk.Success([]byte("Upgrade contract success"))
+}
+
+// initConfig save the config, token is required and the others are optional
+func (c *GovernanceContract) initConfig() error {
+	args := sdk.Instance.GetArgs()
+	config := &Config{
+		Token:             string(args[pToken]),
+		VotingDelay:       defaultVotingDelay,
+		VotingPeriod:      defaultVotingPeriod,
+		QuorumPercent:     defaultQuorumPercent,
+		ThresholdPercent:  defaultThresholdPercent,
+		ProposalThreshold: "0",
+	}
+	if len(config.Token) == 0 {
+		return errors.New("GOV: require parameter:'token'")
+	}
+	for key, value := range map[string]*uint64{
+		pVotingDelay:      &config.VotingDelay,
+		pVotingPeriod:     &config.VotingPeriod,
+		pQuorumPercent:    &config.QuorumPercent,
+		pThresholdPercent: &config.ThresholdPercent,
+	} {
+		if len(args[key]) == 0 {
+			continue
+		}
+		num, err := strconv.ParseUint(string(args[key]), 10, 64)
+		if err != nil {
+			return fmt.Errorf("GOV: parameter:'%s' not a valid uint64", key)
+		}
+		*value = num
+	}
+	if config.VotingDelay == 0 || config.VotingPeriod == 0 {
+		return errors.New("GOV: votingDelay and votingPeriod should be positive")
+	}
+	if config.QuorumPercent > 100 || config.ThresholdPercent > 100 {
+		return errors.New("GOV: quorumPercent and thresholdPercent should not be greater than 100")
+	}
+	if threshold := args[pProposalThreshold]; len(threshold) > 0 {
+		num, ok := safemath.ParseSafeUint256(string(threshold))
+		if !ok {
+			return fmt.Errorf("GOV: parameter:'%s' not a valid uint256", pProposalThreshold)
+		}
+		config.ProposalThreshold = num.ToString()
+	}
+	return c.SetConfig(config)
+}
+
+// InvokeContract the entry func of invoke contract func
+func (c *GovernanceContract) InvokeContract(method string) protogo.Response { // nolint:gocyclo
+	if len(method) == 0 {
+		return sdk.Error("method of param should not be empty")
+	}
+	args := sdk.Instance.GetArgs()
+	switch method {
+	case "CreateProposal":
+		title, err := RequireString(pTitle)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		target, err := RequireString(pTarget)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		targetMethod, err := RequireString(pMethod)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		var targetArgs map[string]string
+		if len(args[pArgs]) > 0 {
+			if err = json.Unmarshal(args[pArgs], &targetArgs); err != nil {
+				return sdk.Error(fmt.Sprintf("GOV: parameter:'%s' should be a json object of strings", pArgs))
+			}
+		}
+		return ReturnUint64(c.CreateProposal(title, string(args[pDescription]), target, targetMethod, targetArgs))
+	case "ActivateProposal":
+		id, err := RequireUint64(pProposalId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.ActivateProposal(id))
+	case "CastVote":
+		id, err := RequireUint64(pProposalId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		support, err := RequireString(pSupport)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.CastVote(id, support))
+	case "Execute":
+		id, err := RequireUint64(pProposalId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		result, err := c.Execute(id)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return sdk.Success(result)
+	case "Delegate":
+		delegatee, err := RequireString(pDelegatee)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.Delegate(delegatee))
+	case "Undelegate":
+		return Return(c.Undelegate())
+	case "GetProposal":
+		id, err := RequireUint64(pProposalId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		proposal, err := c.GetProposal(id)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(proposal)
+	case "GetTally":
+		id, err := RequireUint64(pProposalId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		tally, err := c.GetTally(id)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(tally)
+	case "GetReceipt":
+		id, err := RequireUint64(pProposalId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		voter, err := RequireString(pVoter)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		receipt, err := c.GetReceipt(id, voter)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(receipt)
+	case "GetDelegate":
+		account, err := RequireString(pAccount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnString(c.GetDelegate(account))
+	case "GetDelegators":
+		account, err := RequireString(pAccount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		delegators, err := c.GetDelegators(account)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(delegators)
+	case "GetConfig":
+		config, err := c.GetConfig()
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(config)
+	default:
+		return sdk.Error("Invalid method")
+	}
+}
+
+////////////////////////////////Helper//////////////////////////////////
+
+// ReturnUint64 封装返回uint64类型为Response，如果有error则忽略num，封装error
+// @param num
+// @param err
+// @return Response
+func ReturnUint64(num uint64, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatUint(num, 10)))
+}
+
+// ReturnString 封装返回string类型为Response，如果有error则忽略str，封装error
+// @param str
+// @param err
+// @return Response
+func ReturnString(str string, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(str))
+}
+
+// ReturnJson 封装返回interface类型为json string Response
+// @param data
+// @return Response
+func ReturnJson(data interface{}) protogo.Response {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(bz)
+}
+
+// Return 封装返回Bool类型为Response，如果有error则忽略bool，封装error
+// @param err
+// @return Response
+func Return(err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.SuccessResponse
+}
+
+// RequireString 必须要有参数 string类型
+// @param key
+// @return string
+// @return error
+func RequireString(key string) (string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return "", fmt.Errorf("GOV: require parameter:'%s'", key)
+	}
+	return string(b), nil
+}
+
+// RequireUint64 必须要有参数 uint64类型
+// @param key
+// @return uint64
+// @return error
+func RequireUint64(key string) (uint64, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok {
+		return 0, fmt.Errorf("GOV: require parameter:'%s'", key)
+	}
+	num, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("GOV: parameter:'%s' not a valid uint64", key)
+	}
+	return num, nil
+}
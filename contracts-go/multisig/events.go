@@ -0,0 +1,70 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	eventSubmission        = "Submission"
+	eventConfirmation      = "Confirmation"
+	eventRevocation        = "Revocation"
+	eventExecution         = "Execution"
+	eventOwnerAddition     = "OwnerAddition"
+	eventOwnerRemoval      = "OwnerRemoval"
+	eventRequirementChange = "RequirementChange"
+	eventDailyLimitChange  = "DailyLimitChange"
+)
+
+// multisigEventSchemas schemas of the events emitted by multisig, saved on chain when the contract is installed
+var multisigEventSchemas = []*sdk.EventSchema{
+	sdk.NewEventSchema(eventSubmission,
+		sdk.NewEventField("txId", sdk.EventFieldUint, true),
+		sdk.NewEventField("submitter", sdk.EventFieldAddress, true),
+		sdk.NewEventField("target", sdk.EventFieldString, false),
+		sdk.NewEventField("method", sdk.EventFieldString, false),
+	),
+	sdk.NewEventSchema(eventConfirmation,
+		sdk.NewEventField("txId", sdk.EventFieldUint, true),
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+	),
+	sdk.NewEventSchema(eventRevocation,
+		sdk.NewEventField("txId", sdk.EventFieldUint, true),
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+	),
+	sdk.NewEventSchema(eventExecution,
+		sdk.NewEventField("txId", sdk.EventFieldUint, true),
+		sdk.NewEventField("executor", sdk.EventFieldAddress, true),
+		sdk.NewEventField("result", sdk.EventFieldBytes, false),
+	),
+	sdk.NewEventSchema(eventOwnerAddition,
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+	),
+	sdk.NewEventSchema(eventOwnerRemoval,
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+	),
+	sdk.NewEventSchema(eventRequirementChange,
+		sdk.NewEventField("required", sdk.EventFieldUint, false),
+	),
+	sdk.NewEventSchema(eventDailyLimitChange,
+		sdk.NewEventField("token", sdk.EventFieldString, true),
+		sdk.NewEventField("limit", sdk.EventFieldUint256, false),
+	),
+}
+
+func init() {
+	if err := sdk.RegisterEventSchemas(multisigEventSchemas...); err != nil {
+		panic(err)
+	}
+}
+
+// EventSchemas returns the event schemas of multisig
+func (c *MultisigContract) EventSchemas() []*sdk.EventSchema {
+	return multisigEventSchemas
+}
@@ -0,0 +1,197 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	pOwners   = "owners"
+	pRequired = "required"
+	pTxId     = "txId"
+	pTarget   = "target"
+	pMethod   = "method"
+	pArgs     = "args"
+	pToken    = "token"
+)
+
+func main() {
+	err := sandbox.Start(new(MultisigContract))
+	if err != nil {
+		sdk.Instance.Errorf(err.Error())
+	}
+}
+
+// InitContract install contract func
+func (c *MultisigContract) InitContract() protogo.Response {
+	config := &Config{}
+	owners, err := RequireString(pOwners)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if err = json.Unmarshal([]byte(owners), &config.Owners); err != nil {
+		return sdk.Error(fmt.Sprintf("MSIG: parameter:'%s' should be a json array of addresses", pOwners))
+	}
+	if config.Required, err = RequireUint64(pRequired); err != nil {
+		return sdk.Error(err.Error())
+	}
+	if err = c.SetConfig(config); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("Init contract success"))
+}
+
+// UpgradeContract upgrade contract func, the config is kept
+func (c *MultisigContract) UpgradeContract() protogo.Response {
+	return sdk.Success([]byte("Upgrade contract success"))
+}
+
+// InvokeContract the entry func of invoke contract func
+func (c *MultisigContract) InvokeContract(method string) protogo.Response {
+	if len(method) == 0 {
+		return sdk.Error("method of param should not be empty")
+	}
+	args := sdk.Instance.GetArgs()
+	switch method {
+	case "SubmitTransaction":
+		targetMethod, err := RequireString(pMethod)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		var targetArgs map[string]string
+		if len(args[pArgs]) > 0 {
+			if err = json.Unmarshal(args[pArgs], &targetArgs); err != nil {
+				return sdk.Error(fmt.Sprintf("MSIG: parameter:'%s' should be a json object of strings", pArgs))
+			}
+		}
+		return ReturnUint64(c.SubmitTransaction(string(args[pTarget]), targetMethod, targetArgs))
+	case "ConfirmTransaction":
+		id, err := RequireUint64(pTxId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.ConfirmTransaction(id))
+	case "RevokeConfirmation":
+		id, err := RequireUint64(pTxId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return Return(c.RevokeConfirmation(id))
+	case "ExecuteTransaction":
+		id, err := RequireUint64(pTxId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		result, err := c.ExecuteTransaction(id)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return sdk.Success(result)
+	case "GetTransaction":
+		id, err := RequireUint64(pTxId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		tx, err := c.GetTransaction(id)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(tx)
+	case "GetTransactionCount":
+		return ReturnUint64(c.GetTransactionCount())
+	case "GetDailyLimit":
+		token, err := RequireString(pToken)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		limit, err := c.GetDailyLimit(token)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(limit)
+	case "GetConfig":
+		config, err := c.GetConfig()
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(config)
+	default:
+		return sdk.Error("Invalid method")
+	}
+}
+
+////////////////////////////////Helper//////////////////////////////////
+
+// ReturnUint64 封装返回uint64类型为Response，如果有error则忽略num，封装error
+// @param num
+// @param err
+// @return Response
+func ReturnUint64(num uint64, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatUint(num, 10)))
+}
+
+// ReturnJson 封装返回interface类型为json string Response
+// @param data
+// @return Response
+func ReturnJson(data interface{}) protogo.Response {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(bz)
+}
+
+// Return 封装返回Bool类型为Response，如果有error则忽略bool，封装error
+// @param err
+// @return Response
+func Return(err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.SuccessResponse
+}
+
+// RequireString 必须要有参数 string类型
+// @param key
+// @return string
+// @return error
+func RequireString(key string) (string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return "", fmt.Errorf("MSIG: require parameter:'%s'", key)
+	}
+	return string(b), nil
+}
+
+// RequireUint64 必须要有参数 uint64类型
+// @param key
+// @return uint64
+// @return error
+func RequireUint64(key string) (uint64, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok {
+		return 0, fmt.Errorf("MSIG: require parameter:'%s'", key)
+	}
+	num, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("MSIG: parameter:'%s' not a valid uint64", key)
+	}
+	return num, nil
+}
@@ -0,0 +1,601 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Multi-signature wallet contract, holds standard-nfa and standard-dfa tokens at the contract address and
+acts on them only when M of the N owners agree.
+
+An owner submits a transaction which calls a method of a contract with the args by CallContract, the
+submitter confirms it at once. Other owners confirm it, and an owner may revoke its confirmation before
+the transaction is executed. Any owner executes the transaction once it has the required confirmations of
+the current owners.
+
+A transaction with an empty target manages the wallet itself: AddOwner, RemoveOwner, ReplaceOwner,
+ChangeRequirement and SetDailyLimit, so the owners are rotated by M of N as well.
+
+A daily limit can be set for a standard-dfa token, a Transfer of the token is executed by a single owner
+without the required confirmations while the amount transferred in the day stays within the limit. The day
+is counted by the tx timestamp in UTC.
+*/
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/contract-utils/address"
+	"chainmaker.org/chainmaker/contract-utils/safemath"
+)
+
+const (
+	//db key
+	configKey     = "config"
+	txCountKey    = "txCount"
+	txKey         = "tx"
+	dailyLimitKey = "dailyLimit"
+
+	// maxOwners bounds the owners checked by each confirmation count
+	maxOwners = 50
+
+	// defaultDayBlocks the blocks of a day of the daily limit, a day of 3 second blocks
+	defaultDayBlocks = 28800
+)
+
+// wallet methods called by the transactions with an empty target
+const (
+	MethodAddOwner          = "AddOwner"
+	MethodRemoveOwner       = "RemoveOwner"
+	MethodReplaceOwner      = "ReplaceOwner"
+	MethodChangeRequirement = "ChangeRequirement"
+	MethodSetDailyLimit     = "SetDailyLimit"
+)
+
+// MultisigContract multi-signature wallet contract
+type MultisigContract struct {
+}
+
+// Config owners of the wallet and the confirmations required to execute a transaction
+type Config struct {
+	Owners   []string `json:"owners"`
+	Required uint64   `json:"required"`
+}
+
+// Transaction a call submitted by an owner, Confirmations are the owners who confirmed it
+type Transaction struct {
+	Id            uint64            `json:"id"`
+	Submitter     string            `json:"submitter"`
+	Target        string            `json:"target"`
+	Method        string            `json:"method"`
+	Args          map[string]string `json:"args"`
+	Confirmations []string          `json:"confirmations"`
+	Executed      bool              `json:"executed"`
+	Executor      string            `json:"executor,omitempty"`
+}
+
+// DailyLimit limit of the Transfer of a dfa token in a day, Spent is the amount transferred in Day. The
+// days are counted by the block height, each day lasts Blocks blocks
+type DailyLimit struct {
+	Limit  string `json:"limit"`
+	Blocks uint64 `json:"blocks"`
+	Day    uint64 `json:"day"`
+	Spent  string `json:"spent"`
+}
+
+// SubmitTransaction 提交交易并确认，target为空时调用钱包自身的管理方法
+// @param target 调用的合约
+// @param method 调用的合约方法
+// @param args 调用参数
+// @return uint64 交易ID
+// @return error
+func (c *MultisigContract) SubmitTransaction(target, method string, args map[string]string) (uint64, error) {
+	owner, config, err := c.requireOwner()
+	if err != nil {
+		return 0, err
+	}
+	if len(method) == 0 {
+		return 0, errors.New("MSIG: method should not be empty")
+	}
+	if len(target) == 0 {
+		if err = checkWalletMethod(config, method, args); err != nil {
+			return 0, err
+		}
+	}
+
+	countStr, err := sdk.Instance.GetState(txCountKey, "")
+	if err != nil {
+		return 0, err
+	}
+	var count uint64
+	if len(countStr) > 0 {
+		if count, err = strconv.ParseUint(countStr, 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	tx := &Transaction{
+		Id:            count + 1,
+		Submitter:     owner,
+		Target:        target,
+		Method:        method,
+		Args:          args,
+		Confirmations: []string{owner},
+	}
+	if err = c.setTransaction(tx); err != nil {
+		return 0, err
+	}
+	if err = sdk.Instance.PutState(txCountKey, "", strconv.FormatUint(tx.Id, 10)); err != nil {
+		return 0, err
+	}
+	if err = sdk.EmitTypedEvent(eventSubmission, tx.Id, owner, target, method); err != nil {
+		return 0, err
+	}
+	if err = sdk.EmitTypedEvent(eventConfirmation, tx.Id, owner); err != nil {
+		return 0, err
+	}
+	return tx.Id, nil
+}
+
+// ConfirmTransaction owner确认交易
+// @param id
+// @return error
+func (c *MultisigContract) ConfirmTransaction(id uint64) error {
+	owner, _, err := c.requireOwner()
+	if err != nil {
+		return err
+	}
+	tx, err := c.getPendingTransaction(id)
+	if err != nil {
+		return err
+	}
+	if contains(tx.Confirmations, owner) {
+		return fmt.Errorf("MSIG: transaction %d is already confirmed by %s", id, owner)
+	}
+	tx.Confirmations = append(tx.Confirmations, owner)
+	if err = c.setTransaction(tx); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventConfirmation, id, owner)
+}
+
+// RevokeConfirmation owner在交易执行前撤销确认
+// @param id
+// @return error
+func (c *MultisigContract) RevokeConfirmation(id uint64) error {
+	owner, _, err := c.requireOwner()
+	if err != nil {
+		return err
+	}
+	tx, err := c.getPendingTransaction(id)
+	if err != nil {
+		return err
+	}
+	if !contains(tx.Confirmations, owner) {
+		return fmt.Errorf("MSIG: transaction %d is not confirmed by %s", id, owner)
+	}
+	tx.Confirmations = removeString(tx.Confirmations, owner)
+	if err = c.setTransaction(tx); err != nil {
+		return err
+	}
+	return sdk.EmitTypedEvent(eventRevocation, id, owner)
+}
+
+// ExecuteTransaction owner执行已获得足够确认的交易，或在每日限额内的dfa Transfer，调用失败时交易失败，可再次执行
+// @param id
+// @return []byte 目标合约的返回
+// @return error
+func (c *MultisigContract) ExecuteTransaction(id uint64) ([]byte, error) {
+	owner, config, err := c.requireOwner()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.getPendingTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+	if !isConfirmed(config, tx) {
+		withinLimit, err1 := c.spendDailyLimit(tx)
+		if err1 != nil {
+			return nil, err1
+		}
+		if !withinLimit {
+			return nil, fmt.Errorf("MSIG: transaction %d has %d of %d confirmations", id,
+				confirmationCount(config, tx), config.Required)
+		}
+	}
+	// mark executed before calling the target
+	tx.Executed = true
+	tx.Executor = owner
+	if err = c.setTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	var result []byte
+	if len(tx.Target) == 0 {
+		err = c.executeWalletMethod(config, tx.Method, tx.Args)
+	} else {
+		result, err = callContract(tx.Target, tx.Method, tx.Args)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("MSIG: execute transaction %d failed, %s", id, err)
+	}
+	if err = sdk.EmitTypedEvent(eventExecution, id, owner, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetTransaction 查询交易
+// @param id
+// @return *Transaction
+// @return error
+func (c *MultisigContract) GetTransaction(id uint64) (*Transaction, error) {
+	data, err := sdk.Instance.GetStateByte(txKey, strconv.FormatUint(id, 10))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("MSIG: transaction %d not found", id)
+	}
+	var tx Transaction
+	if err = json.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// GetTransactionCount 查询交易总数
+// @return uint64
+// @return error
+func (c *MultisigContract) GetTransactionCount() (uint64, error) {
+	countStr, err := sdk.Instance.GetState(txCountKey, "")
+	if err != nil || len(countStr) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(countStr, 10, 64)
+}
+
+// GetDailyLimit 查询dfa token的每日限额，Spent为当天已用额度
+// @param token
+// @return *DailyLimit
+// @return error
+func (c *MultisigContract) GetDailyLimit(token string) (*DailyLimit, error) {
+	limit, err := c.getDailyLimit(token)
+	if err != nil {
+		return nil, err
+	}
+	// the days of a limit set before Blocks were counted by the tx timestamp, they restart by the height
+	restart := limit.Blocks == 0
+	if restart {
+		limit.Blocks = defaultDayBlocks
+	}
+	today, err := currentDay(limit.Blocks)
+	if err != nil {
+		return nil, err
+	}
+	if restart || today > limit.Day {
+		limit.Day, limit.Spent = today, "0"
+	}
+	return limit, nil
+}
+
+// GetConfig 获取配置
+// @return *Config
+// @return error
+func (c *MultisigContract) GetConfig() (*Config, error) {
+	data, err := sdk.Instance.GetStateByte(configKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("MSIG: config not found")
+	}
+	var config Config
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SetConfig 检查并设置配置
+// @param config
+// @return error
+func (c *MultisigContract) SetConfig(config *Config) error {
+	if err := checkConfig(config); err != nil {
+		return err
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(configKey, "", data)
+}
+
+// requireOwner the sender should be an owner
+func (c *MultisigContract) requireOwner() (string, *Config, error) {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return "", nil, fmt.Errorf("get sender failed, err:%s", err)
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	if !contains(config.Owners, sender) {
+		return "", nil, fmt.Errorf("MSIG: %s is not an owner", sender)
+	}
+	return sender, config, nil
+}
+
+func (c *MultisigContract) getPendingTransaction(id uint64) (*Transaction, error) {
+	tx, err := c.GetTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Executed {
+		return nil, fmt.Errorf("MSIG: transaction %d is already executed", id)
+	}
+	return tx, nil
+}
+
+func (c *MultisigContract) setTransaction(tx *Transaction) error {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(txKey, strconv.FormatUint(tx.Id, 10), data)
+}
+
+// spendDailyLimit add the amount of a Transfer of a dfa token to the spent of the day, false if the
+// transaction is not a Transfer of a token with a daily limit or the amount is over the limit
+func (c *MultisigContract) spendDailyLimit(tx *Transaction) (bool, error) {
+	if len(tx.Target) == 0 || tx.Method != "Transfer" {
+		return false, nil
+	}
+	limit, err := c.GetDailyLimit(tx.Target)
+	if err != nil || len(limit.Limit) == 0 {
+		return false, err
+	}
+	amount, ok := safemath.ParseSafeUint256(tx.Args["amount"])
+	if !ok {
+		return false, nil
+	}
+	spent := new(big.Int).Add(toBig(limit.Spent), toBig(amount.ToString()))
+	if spent.Cmp(toBig(limit.Limit)) > 0 {
+		return false, nil
+	}
+	limit.Spent = spent.String()
+	return true, c.setDailyLimit(tx.Target, limit)
+}
+
+func (c *MultisigContract) getDailyLimit(token string) (*DailyLimit, error) {
+	data, err := sdk.Instance.GetStateByte(dailyLimitKey, token)
+	if err != nil {
+		return nil, err
+	}
+	var limit DailyLimit
+	if len(data) == 0 {
+		return &limit, nil
+	}
+	if err = json.Unmarshal(data, &limit); err != nil {
+		return nil, err
+	}
+	return &limit, nil
+}
+
+func (c *MultisigContract) setDailyLimit(token string, limit *DailyLimit) error {
+	data, err := json.Marshal(limit)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(dailyLimitKey, token, data)
+}
+
+// executeWalletMethod run the wallet method of a confirmed transaction, the owners are checked again as
+// they may have changed since the submission
+func (c *MultisigContract) executeWalletMethod(config *Config, method string, args map[string]string) error {
+	if err := checkWalletMethod(config, method, args); err != nil {
+		return err
+	}
+	switch method {
+	case MethodAddOwner:
+		config.Owners = append(config.Owners, args["owner"])
+		if err := c.SetConfig(config); err != nil {
+			return err
+		}
+		return sdk.EmitTypedEvent(eventOwnerAddition, args["owner"])
+	case MethodRemoveOwner:
+		config.Owners = removeString(config.Owners, args["owner"])
+		if config.Required > uint64(len(config.Owners)) {
+			config.Required = uint64(len(config.Owners))
+		}
+		if err := c.SetConfig(config); err != nil {
+			return err
+		}
+		return sdk.EmitTypedEvent(eventOwnerRemoval, args["owner"])
+	case MethodReplaceOwner:
+		for i, owner := range config.Owners {
+			if owner == args["owner"] {
+				config.Owners[i] = args["newOwner"]
+			}
+		}
+		if err := c.SetConfig(config); err != nil {
+			return err
+		}
+		if err := sdk.EmitTypedEvent(eventOwnerRemoval, args["owner"]); err != nil {
+			return err
+		}
+		return sdk.EmitTypedEvent(eventOwnerAddition, args["newOwner"])
+	case MethodChangeRequirement:
+		config.Required, _ = strconv.ParseUint(args["required"], 10, 64)
+		if err := c.SetConfig(config); err != nil {
+			return err
+		}
+		return sdk.EmitTypedEvent(eventRequirementChange, config.Required)
+	default:
+		limit, err := c.GetDailyLimit(args["token"])
+		if err != nil {
+			return err
+		}
+		limit.Limit = args["limit"]
+		if limit.Limit == "0" {
+			limit.Limit = ""
+		}
+		// the spent of the day is kept in the day of the new length
+		if blocks, _ := strconv.ParseUint(args["blocks"], 10, 64); blocks > 0 && blocks != limit.Blocks {
+			if limit.Day, err = currentDay(blocks); err != nil {
+				return err
+			}
+			limit.Blocks = blocks
+		}
+		if err = c.setDailyLimit(args["token"], limit); err != nil {
+			return err
+		}
+		return sdk.EmitTypedEvent(eventDailyLimitChange, args["token"], args["limit"])
+	}
+}
+
+// checkWalletMethod check the args of the wallet method against the owners
+func checkWalletMethod(config *Config, method string, args map[string]string) error {
+	owner, newOwner := args["owner"], args["newOwner"]
+	switch method {
+	case MethodAddOwner:
+		if contains(config.Owners, owner) {
+			return fmt.Errorf("MSIG: %s is already an owner", owner)
+		}
+		return checkConfig(&Config{Owners: append(append([]string{}, config.Owners...), owner),
+			Required: config.Required})
+	case MethodRemoveOwner:
+		if !contains(config.Owners, owner) {
+			return fmt.Errorf("MSIG: %s is not an owner", owner)
+		}
+		if len(config.Owners) == 1 {
+			return errors.New("MSIG: can not remove the last owner")
+		}
+		return nil
+	case MethodReplaceOwner:
+		if !contains(config.Owners, owner) {
+			return fmt.Errorf("MSIG: %s is not an owner", owner)
+		}
+		if contains(config.Owners, newOwner) {
+			return fmt.Errorf("MSIG: %s is already an owner", newOwner)
+		}
+		if !address.IsValidAddress(newOwner) || address.IsZeroAddress(newOwner) {
+			return fmt.Errorf("MSIG: invalid owner %s", newOwner)
+		}
+		return nil
+	case MethodChangeRequirement:
+		required, err := strconv.ParseUint(args["required"], 10, 64)
+		if err != nil {
+			return errors.New("MSIG: required should be an uint64")
+		}
+		return checkConfig(&Config{Owners: config.Owners, Required: required})
+	case MethodSetDailyLimit:
+		if len(args["token"]) == 0 {
+			return errors.New("MSIG: token should not be empty")
+		}
+		limit, ok := safemath.ParseSafeUint256(args["limit"])
+		if !ok || limit.ToString() != args["limit"] {
+			return errors.New("MSIG: limit should be an uint256")
+		}
+		if blocks, ok := args["blocks"]; ok {
+			if n, err := strconv.ParseUint(blocks, 10, 64); err != nil || n == 0 {
+				return errors.New("MSIG: blocks should be a positive uint64")
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("MSIG: unknown wallet method %s", method)
+	}
+}
+
+// checkConfig the owners are valid and distinct addresses, and 1 <= required <= len(owners)
+func checkConfig(config *Config) error {
+	if len(config.Owners) == 0 || len(config.Owners) > maxOwners {
+		return fmt.Errorf("MSIG: the wallet should have 1 to %d owners", maxOwners)
+	}
+	seen := make(map[string]bool, len(config.Owners))
+	for _, owner := range config.Owners {
+		if !address.IsValidAddress(owner) || address.IsZeroAddress(owner) {
+			return fmt.Errorf("MSIG: invalid owner %s", owner)
+		}
+		if seen[owner] {
+			return fmt.Errorf("MSIG: duplicate owner %s", owner)
+		}
+		seen[owner] = true
+	}
+	if config.Required == 0 || config.Required > uint64(len(config.Owners)) {
+		return fmt.Errorf("MSIG: required should be between 1 and %d", len(config.Owners))
+	}
+	return nil
+}
+
+// confirmationCount the confirmations of the current owners
+func confirmationCount(config *Config, tx *Transaction) uint64 {
+	var count uint64
+	for _, owner := range tx.Confirmations {
+		if contains(config.Owners, owner) {
+			count++
+		}
+	}
+	return count
+}
+
+func isConfirmed(config *Config, tx *Transaction) bool {
+	return confirmationCount(config, tx) >= config.Required
+}
+
+func callContract(target, method string, args map[string]string) ([]byte, error) {
+	callArgs := make(map[string][]byte, len(args))
+	for key, value := range args {
+		callArgs[key] = []byte(value)
+	}
+	resp := sdk.Instance.CallContract(target, method, callArgs)
+	if resp.Status != sdk.OK {
+		return nil, errors.New(resp.Message)
+	}
+	return resp.Payload, nil
+}
+
+// currentDay the days of blocks since the genesis block, the tx timestamp is chosen by the submitter and is
+// not used
+func currentDay(blocks uint64) (uint64, error) {
+	height, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return 0, fmt.Errorf("get block height failed, err:%s", err)
+	}
+	return uint64(height) / blocks, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func toBig(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return n
+}
@@ -0,0 +1,335 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	alice = "a04f7895de24f61807a729be230f03da8c0eef42"
+	bob   = "b04f7895de24f61807a729be230f03da8c0eef42"
+	carol = "c04f7895de24f61807a729be230f03da8c0eef42"
+	dave  = "d04f7895de24f61807a729be230f03da8c0eef42"
+	token = "cmdfa"
+)
+
+// mockGetPutState keeps the state of a test in the returned map by key#field
+func mockGetPutState(mockInstance *sdk.MockSDKInterface) map[string][]byte {
+	stateMap := make(map[string][]byte)
+	mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) (string, error) {
+			return string(stateMap[key+"#"+field]), nil
+		})
+	mockInstance.EXPECT().PutState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field, value string) error {
+			stateMap[key+"#"+field] = []byte(value)
+			return nil
+		})
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) ([]byte, error) {
+			return stateMap[key+"#"+field], nil
+		})
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string, value []byte) error {
+			stateMap[key+"#"+field] = value
+			return nil
+		})
+	return stateMap
+}
+
+// mockCallContract the calls to other contracts succeed and their args are appended to calls
+func mockCallContract(mockInstance *sdk.MockSDKInterface, calls *[]map[string][]byte) {
+	mockInstance.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(contractName, method string, callArgs map[string][]byte) protogo.Response {
+			*calls = append(*calls, callArgs)
+			return sdk.Success([]byte("done"))
+		})
+}
+
+// initArgs the wallet of alice, bob and carol requiring 2 confirmations
+func initArgs() map[string][]byte {
+	return map[string][]byte{
+		pOwners:   []byte(`["` + alice + `","` + bob + `","` + carol + `"]`),
+		pRequired: []byte("2"),
+	}
+}
+
+// step one call of a test by sender at height, do returns an error when the result is not the wanted one
+type step struct {
+	name    string
+	height  int
+	sender  string
+	do      func(c *MultisigContract) error
+	wantErr bool
+}
+
+func submit(wantId uint64, target, method string, args map[string]string) func(c *MultisigContract) error {
+	return func(c *MultisigContract) error {
+		id, err := c.SubmitTransaction(target, method, args)
+		if err == nil && id != wantId {
+			err = fmt.Errorf("SubmitTransaction() = %d, want %d", id, wantId)
+		}
+		return err
+	}
+}
+
+func confirm(id uint64) func(c *MultisigContract) error {
+	return func(c *MultisigContract) error { return c.ConfirmTransaction(id) }
+}
+
+func execute(id uint64) func(c *MultisigContract) error {
+	return func(c *MultisigContract) error {
+		_, err := c.ExecuteTransaction(id)
+		return err
+	}
+}
+
+func TestMultisigContract_InvokeContract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	var args map[string][]byte
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return args })
+	mockInstance.EXPECT().Sender().AnyTimes().Return(alice, nil)
+	mockGetPutState(mockInstance)
+	sdk.Instance = mockInstance
+
+	tests := []struct {
+		name   string
+		method string
+		args   map[string][]byte
+		want   protogo.Response
+	}{
+		{name: "init without owners", method: "", want: sdk.Error("MSIG: require parameter:'owners'")},
+		{name: "init with duplicate owner", method: "", args: map[string][]byte{
+			pOwners: []byte(`["` + alice + `","` + alice + `"]`), pRequired: []byte("1")},
+			want: sdk.Error("MSIG: duplicate owner " + alice)},
+		{name: "init with too many required", method: "", args: map[string][]byte{
+			pOwners: []byte(`["` + alice + `"]`), pRequired: []byte("2")},
+			want: sdk.Error("MSIG: required should be between 1 and 1")},
+		{name: "submit", method: "SubmitTransaction", want: sdk.Error("MSIG: require parameter:'method'")},
+		{name: "confirm", method: "ConfirmTransaction", want: sdk.Error("MSIG: require parameter:'txId'")},
+		{name: "count", method: "GetTransactionCount", want: sdk.Success([]byte("0"))},
+		{name: "config", method: "GetConfig", want: sdk.Error("MSIG: config not found")},
+		{name: "invalid", method: "invalid", want: sdk.Error("Invalid method")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args = tt.args
+			c := &MultisigContract{}
+			var got protogo.Response
+			if len(tt.method) == 0 {
+				got = c.InitContract()
+			} else {
+				got = c.InvokeContract(tt.method)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvokeContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultisigContract_transaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender := alice
+	var calls []map[string][]byte
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(initArgs())
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().Return(1, nil)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockCallContract(mockInstance, &calls)
+	sdk.Instance = mockInstance
+
+	c := &MultisigContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	transfer := map[string]string{"to": dave, "amount": "10"}
+	steps := []step{
+		{name: "submit by others", sender: dave, do: submit(1, token, "Transfer", nil), wantErr: true},
+		{name: "submit", sender: alice, do: submit(1, token, "Transfer", transfer)},
+		{name: "execute with 1 confirmation", sender: alice, do: execute(1), wantErr: true},
+		{name: "confirm", sender: bob, do: confirm(1)},
+		{name: "confirm twice", sender: bob, do: confirm(1), wantErr: true},
+		{name: "revoke", sender: bob, do: func(c *MultisigContract) error { return c.RevokeConfirmation(1) }},
+		{name: "execute after the revocation", sender: bob, do: execute(1), wantErr: true},
+		{name: "confirm by carol", sender: carol, do: confirm(1)},
+		{name: "execute", sender: carol, do: func(c *MultisigContract) error {
+			result, err := c.ExecuteTransaction(1)
+			if err == nil && string(result) != "done" {
+				err = fmt.Errorf("ExecuteTransaction() = %s, want done", result)
+			}
+			return err
+		}},
+		{name: "execute twice", sender: carol, do: execute(1), wantErr: true},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender = s.sender
+			if err := s.do(c); (err != nil) != s.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, s.wantErr)
+			}
+		})
+	}
+	want := []map[string][]byte{{"to": []byte(dave), "amount": []byte("10")}}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	tx, _ := c.GetTransaction(1)
+	if !tx.Executed || tx.Executor != carol || !reflect.DeepEqual(tx.Confirmations, []string{alice, carol}) {
+		t.Errorf("GetTransaction() = %+v", tx)
+	}
+}
+
+func TestMultisigContract_owners(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender := alice
+	var calls []map[string][]byte
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(initArgs())
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().Return(1, nil)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockCallContract(mockInstance, &calls)
+	sdk.Instance = mockInstance
+
+	c := &MultisigContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	wantOwners := func(owners ...string) func(c *MultisigContract) error {
+		return func(c *MultisigContract) error {
+			config, err := c.GetConfig()
+			if err == nil && !reflect.DeepEqual(config.Owners, owners) {
+				err = fmt.Errorf("owners = %v, want %v", config.Owners, owners)
+			}
+			return err
+		}
+	}
+	steps := []step{
+		// carol confirms a transfer, then is replaced by dave
+		{name: "transfer by carol", sender: carol,
+			do: submit(1, token, "Transfer", map[string]string{"to": carol, "amount": "10"})},
+		{name: "replace others", sender: alice, wantErr: true,
+			do: submit(2, "", MethodReplaceOwner, map[string]string{"owner": dave})},
+		{name: "replace carol", sender: alice,
+			do: submit(2, "", MethodReplaceOwner, map[string]string{"owner": carol, "newOwner": dave})},
+		{name: "confirm replace", sender: bob, do: confirm(2)},
+		{name: "execute replace", sender: bob, do: execute(2)},
+		{name: "owners", sender: bob, do: wantOwners(alice, bob, dave)},
+		// the confirmation of carol is not counted anymore
+		{name: "confirm transfer", sender: dave, do: confirm(1)},
+		{name: "execute with removed owner", sender: dave, do: execute(1), wantErr: true},
+		// the requirement is changed by M of N as well
+		{name: "change requirement", sender: dave,
+			do: submit(3, "", MethodChangeRequirement, map[string]string{"required": "3"})},
+		{name: "confirm requirement", sender: alice, do: confirm(3)},
+		{name: "execute requirement", sender: alice, do: execute(3)},
+		{name: "required", sender: alice, do: func(c *MultisigContract) error {
+			config, err := c.GetConfig()
+			if err == nil && config.Required != 3 {
+				err = fmt.Errorf("required = %d, want 3", config.Required)
+			}
+			return err
+		}},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender = s.sender
+			if err := s.do(c); (err != nil) != s.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, s.wantErr)
+			}
+		})
+	}
+	if len(calls) != 0 {
+		t.Errorf("calls = %v, want none", calls)
+	}
+}
+
+// the days of the daily limit are counted by the block height, GetTxTimeStamp is not mocked
+func TestMultisigContract_dailyLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, height := alice, 1
+	var calls []map[string][]byte
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(initArgs())
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return height, nil })
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockCallContract(mockInstance, &calls)
+	sdk.Instance = mockInstance
+
+	c := &MultisigContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	transfer := func(id uint64, amount string) func(c *MultisigContract) error {
+		return func(c *MultisigContract) error {
+			if err := submit(id, token, "Transfer", map[string]string{"to": dave, "amount": amount})(c); err != nil {
+				return err
+			}
+			return execute(id)(c)
+		}
+	}
+	wantLimit := func(want *DailyLimit) func(c *MultisigContract) error {
+		return func(c *MultisigContract) error {
+			limit, err := c.GetDailyLimit(token)
+			if err == nil && !reflect.DeepEqual(limit, want) {
+				err = fmt.Errorf("GetDailyLimit() = %+v, want %+v", limit, want)
+			}
+			return err
+		}
+	}
+	steps := []step{
+		{name: "zero blocks", height: 1, sender: alice, wantErr: true,
+			do: submit(1, "", MethodSetDailyLimit, map[string]string{"token": token, "limit": "100", "blocks": "0"})},
+		{name: "set limit", height: 1, sender: alice,
+			do: submit(1, "", MethodSetDailyLimit, map[string]string{"token": token, "limit": "100", "blocks": "10"})},
+		{name: "confirm limit", height: 1, sender: bob, do: confirm(1)},
+		{name: "execute limit", height: 1, sender: bob, do: execute(1)},
+		{name: "within limit", height: 2, sender: alice, do: transfer(2, "60")},
+		{name: "over limit", height: 3, sender: alice, do: transfer(3, "50"), wantErr: true},
+		{name: "within the rest", height: 9, sender: alice, do: transfer(4, "40")},
+		{name: "spent", height: 9, sender: alice, do: wantLimit(&DailyLimit{Limit: "100", Blocks: 10, Spent: "100"})},
+		{name: "next day", height: 10, sender: alice, do: transfer(5, "100")},
+		{name: "spent next day", height: 10, sender: alice,
+			do: wantLimit(&DailyLimit{Limit: "100", Blocks: 10, Day: 1, Spent: "100"})},
+		{name: "new day", height: 25, sender: alice,
+			do: wantLimit(&DailyLimit{Limit: "100", Blocks: 10, Day: 2, Spent: "0"})},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender, height = s.sender, s.height
+			if err := s.do(c); (err != nil) != s.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, s.wantErr)
+			}
+		})
+	}
+	if len(calls) != 3 {
+		t.Errorf("calls = %v, want 3", calls)
+	}
+}
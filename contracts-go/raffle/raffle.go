@@ -8,17 +8,36 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
 
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
 )
 
-const queryErrorMsg = "get peoples data from store failed"
+const (
+	queryErrorMsg = "get peoples data from store failed"
 
-// RaffleContract demo contract for raffle
+	keyBeacon       = "beacon"
+	keyPending      = "pending"
+	keyPendingCount = "pendingCount"
+	keyAdmin        = "admin"
+
+	defaultBeacon = "beacon"
+)
+
+// RaffleContract demo contract for raffle. The winner of a level is picked by the seed of a round of
+// the beacon contract which starts after the raffle of the level is requested, so that nobody knows
+// the winner when requesting. All the levels ready are drawn at once in a fixed order. Only the admin
+// registers the peoples, and not while a raffle is pending, so the list can not be changed once the
+// seed is known
 type RaffleContract struct {
 }
 
@@ -36,14 +55,66 @@ type Peoples struct {
 
 // InitContract install contract func
 func (f *RaffleContract) InitContract() protogo.Response {
+	if err := f.saveBeacon(true); err != nil {
+		return sdk.Error(err.Error())
+	}
+	if err := f.saveAdmin(true); err != nil {
+		return sdk.Error(err.Error())
+	}
 	return sdk.Success([]byte("Init contract success"))
 }
 
 // UpgradeContract upgrade contract func
 func (f *RaffleContract) UpgradeContract() protogo.Response {
+	if err := f.saveBeacon(false); err != nil {
+		return sdk.Error(err.Error())
+	}
+	if err := f.saveAdmin(false); err != nil {
+		return sdk.Error(err.Error())
+	}
 	return sdk.Success([]byte("Upgrade contract success"))
 }
 
+// saveBeacon save the name of the beacon contract, param beacon is optional
+func (f *RaffleContract) saveBeacon(install bool) error {
+	beacon := string(sdk.Instance.GetArgs()[keyBeacon])
+	if len(beacon) == 0 {
+		if !install {
+			return nil
+		}
+		beacon = defaultBeacon
+	}
+	return sdk.Instance.PutState(keyBeacon, "", beacon)
+}
+
+// saveAdmin save the origin as the admin who registers the peoples, an upgrade keeps the saved admin
+func (f *RaffleContract) saveAdmin(install bool) error {
+	if !install {
+		admin, err := sdk.Instance.GetState(keyAdmin, "")
+		if err != nil || len(admin) > 0 {
+			return err
+		}
+	}
+	admin, err := sdk.Instance.Origin()
+	if err != nil {
+		return fmt.Errorf("get origin failed, %s", err)
+	}
+	return sdk.Instance.PutState(keyAdmin, "", admin)
+}
+
+// getPendingCount get the number of levels waiting for a beacon round
+func (f *RaffleContract) getPendingCount() (int, error) {
+	count, err := sdk.Instance.GetState(keyPendingCount, "")
+	if err != nil || len(count) == 0 {
+		return 0, err
+	}
+	return strconv.Atoi(count)
+}
+
+func (f *RaffleContract) putPendingCount(count int) error {
+	return sdk.Instance.PutState(keyPendingCount, "", strconv.Itoa(count))
+}
+
 // InvokeContract the entry func of invoke contract func
 func (f *RaffleContract) InvokeContract(method string) protogo.Response {
 	switch method {
@@ -51,6 +122,8 @@ func (f *RaffleContract) InvokeContract(method string) protogo.Response {
 		return f.registerAll()
 	case "query":
 		return f.query()
+	case "requestRaffle":
+		return f.requestRaffle()
 	case "raffle":
 		return f.raffle()
 	default:
@@ -61,9 +134,33 @@ func (f *RaffleContract) InvokeContract(method string) protogo.Response {
 func (f *RaffleContract) registerAll() protogo.Response {
 	params := sdk.Instance.GetArgs()
 
+	var errMsg string
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		errMsg = fmt.Sprintf("[registerAll] get sender failed, %s", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	admin, err := sdk.Instance.GetState(keyAdmin, "")
+	if err != nil || len(admin) == 0 || sender != admin {
+		errMsg = "[registerAll] only admin can register peoples"
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	pendingCount, err := f.getPendingCount()
+	if err != nil {
+		errMsg = fmt.Sprintf("[registerAll] get pending raffles failed, %s", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	if pendingCount > 0 {
+		errMsg = fmt.Sprintf("[registerAll] %d raffles are pending, peoples can not be changed", pendingCount)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+
 	// 获取参数
 	value := params["peoples"]
-	var errMsg string
 	if len(value) == 0 {
 		errMsg = "value should not be empty!"
 		sdk.Instance.Errorf(errMsg)
@@ -71,7 +168,7 @@ func (f *RaffleContract) registerAll() protogo.Response {
 	}
 	sdk.Instance.Debugf("registerAll receive peoples value:%s", string(value))
 	var peoples Peoples
-	err := json.Unmarshal(value, &peoples)
+	err = json.Unmarshal(value, &peoples)
 	if err != nil {
 		return sdk.Error(err.Error())
 	}
@@ -92,7 +189,8 @@ func (f *RaffleContract) registerAll() protogo.Response {
 	return sdk.Success([]byte("ok"))
 }
 
-func (f *RaffleContract) raffle() protogo.Response {
+// requestRaffle request the raffle of a level, the winner is picked by the next round of the beacon
+func (f *RaffleContract) requestRaffle() protogo.Response {
 	params := sdk.Instance.GetArgs()
 
 	var errMsg string
@@ -102,9 +200,90 @@ func (f *RaffleContract) raffle() protogo.Response {
 		sdk.Instance.Errorf(errMsg)
 		return sdk.Error(errMsg)
 	}
-	argTimestamp := string(params["timestamp"])
-	if len(argTimestamp) == 0 {
-		errMsg = "argTimestamp should not be empty!"
+	beacon, err := sdk.Instance.GetState(keyBeacon, "")
+	if err != nil || len(beacon) == 0 {
+		errMsg = fmt.Sprintf("get beacon contract failed, %v", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+
+	pending, err := sdk.Instance.GetState(keyPending, level)
+	if err != nil {
+		errMsg = fmt.Sprintf("get pending raffle failed, %s", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	if len(pending) > 0 {
+		// only the raffle waiting for a failed round can be requested again
+		resp := sdk.Instance.CallContract(beacon, "getRound", map[string][]byte{"round": []byte(pending)})
+		if resp.Status != sdk.OK {
+			errMsg = fmt.Sprintf("get beacon round %s failed, %s", pending, resp.Message)
+			sdk.Instance.Errorf(errMsg)
+			return sdk.Error(errMsg)
+		}
+		var round struct {
+			Failed bool `json:"failed"`
+		}
+		if err = json.Unmarshal(resp.Payload, &round); err != nil || !round.Failed {
+			errMsg = fmt.Sprintf("raffle of level %s is waiting for beacon round %s", level, pending)
+			sdk.Instance.Errorf(errMsg)
+			return sdk.Error(errMsg)
+		}
+	}
+
+	resp := sdk.Instance.CallContract(beacon, "currentRound", nil)
+	if resp.Status != sdk.OK {
+		errMsg = fmt.Sprintf("get beacon current round failed, %s", resp.Message)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	current, err := strconv.ParseUint(string(resp.Payload), 10, 64)
+	if err != nil {
+		errMsg = fmt.Sprintf("parse beacon current round failed, %s", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	round := strconv.FormatUint(current+1, 10)
+	err = sdk.Instance.PutState(keyPending, level, round)
+	if err != nil {
+		errMsg = fmt.Sprintf("put pending raffle failed, %s", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	if len(pending) == 0 {
+		pendingCount, err1 := f.getPendingCount()
+		if err1 == nil {
+			err1 = f.putPendingCount(pendingCount + 1)
+		}
+		if err1 != nil {
+			errMsg = fmt.Sprintf("count pending raffle failed, %s", err1)
+			sdk.Instance.Errorf(errMsg)
+			return sdk.Error(errMsg)
+		}
+	}
+
+	return sdk.Success([]byte(round))
+}
+
+// raffle draw every requested level whose beacon round is finalized, in the order of their rounds and
+// levels, so the caller can not choose which level picks first after the seeds are known. The levels
+// of a failed round are skipped, they are requested again
+func (f *RaffleContract) raffle() protogo.Response {
+	var errMsg string
+	beacon, err := sdk.Instance.GetState(keyBeacon, "")
+	if err != nil || len(beacon) == 0 {
+		errMsg = fmt.Sprintf("get beacon contract failed, %v", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	pending, err := f.getPendingRaffles()
+	if err != nil {
+		errMsg = fmt.Sprintf("get pending raffles failed, %s", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+	if len(pending) == 0 {
+		errMsg = "no raffle is requested"
 		sdk.Instance.Errorf(errMsg)
 		return sdk.Error(errMsg)
 	}
@@ -114,7 +293,6 @@ func (f *RaffleContract) raffle() protogo.Response {
 		sdk.Instance.Errorf(queryErrorMsg)
 		return sdk.Error(queryErrorMsg)
 	}
-
 	var peoples Peoples
 	err = json.Unmarshal(peoplesData, &peoples)
 	if err != nil {
@@ -122,20 +300,60 @@ func (f *RaffleContract) raffle() protogo.Response {
 		sdk.Instance.Errorf(errMsg)
 		return sdk.Error(errMsg)
 	}
-	num := f.bkdrHash(argTimestamp)
-	num = num % len(peoples.Peoples)
 
-	sdk.Instance.Infof(fmt.Sprintf("raffle pos: %d", num))
+	var results []string
+	seeds := make(map[uint64][]byte)
+	for _, raffle := range pending {
+		seed, ok := seeds[raffle.round]
+		if !ok {
+			var finalized bool
+			seed, finalized, err = f.getRoundSeed(beacon, raffle.round)
+			if err != nil {
+				errMsg = fmt.Sprintf("get randomness of beacon round %d failed, %s", raffle.round, err)
+				sdk.Instance.Errorf(errMsg)
+				return sdk.Error(errMsg)
+			}
+			// the later rounds are not finalized either
+			if !finalized {
+				break
+			}
+			seeds[raffle.round] = seed
+		}
+		if seed == nil {
+			continue
+		}
+
+		if len(peoples.Peoples) == 0 {
+			errMsg = "no people left to raffle"
+			sdk.Instance.Errorf(errMsg)
+			return sdk.Error(errMsg)
+		}
+		num := f.pick(seed, raffle.level, len(peoples.Peoples))
+		sdk.Instance.Infof(fmt.Sprintf("raffle pos: %d", num))
 
-	resultPeople := peoples.Peoples[num]
-	result := fmt.Sprintf("num: %d, name: %s, level: %s", resultPeople.Num, resultPeople.Name, level)
-	var newPeoples Peoples
-	newPeoples.Peoples = append(newPeoples.Peoples, peoples.Peoples[0:num]...)
-	if num+1 < len(peoples.Peoples) {
-		newPeoples.Peoples = append(newPeoples.Peoples, peoples.Peoples[num+1:]...)
+		resultPeople := peoples.Peoples[num]
+		results = append(results, fmt.Sprintf("num: %d, name: %s, level: %s", resultPeople.Num,
+			resultPeople.Name, raffle.level))
+		var newPeoples Peoples
+		newPeoples.Peoples = append(newPeoples.Peoples, peoples.Peoples[0:num]...)
+		if num+1 < len(peoples.Peoples) {
+			newPeoples.Peoples = append(newPeoples.Peoples, peoples.Peoples[num+1:]...)
+		}
+		peoples = newPeoples
+		err = sdk.Instance.DelState(keyPending, raffle.level)
+		if err != nil {
+			errMsg = fmt.Sprintf("delete pending raffle failed, %s", err)
+			sdk.Instance.Errorf(errMsg)
+			return sdk.Error(errMsg)
+		}
+	}
+	if len(results) == 0 {
+		errMsg = "no requested raffle has a finalized beacon round"
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
 	}
-	//delete(peoples.Peoples, num)
-	newPeoplesData, err := json.Marshal(newPeoples)
+
+	newPeoplesData, err := json.Marshal(peoples)
 	if err != nil {
 		errMsg = fmt.Sprintf("marshal new peoples data failed, %s", err)
 		sdk.Instance.Errorf(errMsg)
@@ -147,17 +365,83 @@ func (f *RaffleContract) raffle() protogo.Response {
 		sdk.Instance.Errorf(errMsg)
 		return sdk.Error(errMsg)
 	}
+	err = f.putPendingCount(len(pending) - len(results))
+	if err != nil {
+		errMsg = fmt.Sprintf("count pending raffle failed, %s", err)
+		sdk.Instance.Errorf(errMsg)
+		return sdk.Error(errMsg)
+	}
+
+	return sdk.Success([]byte(strings.Join(results, "\n")))
+}
 
-	return sdk.Success([]byte(result))
+// pendingRaffle a requested level and the beacon round it waits for
+type pendingRaffle struct {
+	level string
+	round uint64
 }
 
-func (f *RaffleContract) bkdrHash(timestamp string) int {
-	hash := 0
-	seed := 131
-	for x := range timestamp {
-		hash = hash*seed + x
+// getPendingRaffles get the requested levels ordered by their rounds and levels
+func (f *RaffleContract) getPendingRaffles() ([]pendingRaffle, error) {
+	iter, err := sdk.Instance.NewIteratorPrefixWithKeyField(keyPending, "")
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var pending []pendingRaffle
+	for iter.HasNext() {
+		_, level, value, err1 := iter.Next()
+		if err1 != nil {
+			return nil, err1
+		}
+		round, err1 := strconv.ParseUint(string(value), 10, 64)
+		if err1 != nil {
+			return nil, fmt.Errorf("parse round of level %s failed, %s", level, err1)
+		}
+		pending = append(pending, pendingRaffle{level: level, round: round})
 	}
-	return hash & 0x7FFFFFFF
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].round != pending[j].round {
+			return pending[i].round < pending[j].round
+		}
+		return pending[i].level < pending[j].level
+	})
+	return pending, nil
+}
+
+// getRoundSeed get the seed of a beacon round and whether the round is finalized,
+// the seed of a failed round is nil
+func (f *RaffleContract) getRoundSeed(beacon string, round uint64) ([]byte, bool, error) {
+	resp := sdk.Instance.CallContract(beacon, "getRound",
+		map[string][]byte{"round": []byte(strconv.FormatUint(round, 10))})
+	if resp.Status != sdk.OK {
+		// a round not started yet is not finalized
+		return nil, false, nil
+	}
+	var info struct {
+		Seed      string `json:"seed"`
+		Finalized bool   `json:"finalized"`
+		Failed    bool   `json:"failed"`
+	}
+	if err := json.Unmarshal(resp.Payload, &info); err != nil {
+		return nil, false, err
+	}
+	if !info.Finalized || info.Failed {
+		return nil, info.Finalized, nil
+	}
+	seed, err := hex.DecodeString(info.Seed)
+	if err != nil || len(seed) == 0 {
+		return nil, false, fmt.Errorf("invalid randomness %s", info.Seed)
+	}
+	return seed, true, nil
+}
+
+// pick the position of the winner, levels sharing a beacon round pick differently
+func (f *RaffleContract) pick(seed []byte, level string, n int) int {
+	h := sha256.Sum256(append(append([]byte{}, seed...), level...))
+	pos := new(big.Int).Mod(new(big.Int).SetBytes(h[:]), big.NewInt(int64(n)))
+	return int(pos.Int64())
 }
 
 func (f *RaffleContract) query() protogo.Response {
@@ -7,9 +7,16 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 
 	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
@@ -73,27 +80,49 @@ func TestRaffleContract_UpgradeContract(t *testing.T) {
 	}
 }
 
-func TestRaffleContract_bkdrHash(t *testing.T) {
+func TestRaffleContract_pick(t *testing.T) {
+	seed := bytes.Repeat([]byte{0xab}, 32)
 	type args struct {
-		timestamp string
+		seed  []byte
+		level string
+		n     int
 	}
 	tests := []struct {
 		name string
 		args args
 		want int
 	}{
-		// TODO: Add test cases.
+		{
+			name: "one people",
+			args: args{seed: seed, level: "1", n: 1},
+			want: 0,
+		},
+		{
+			name: "level 1",
+			args: args{seed: seed, level: "1", n: 10},
+			want: pickWant(seed, "1", 10),
+		},
+		{
+			name: "level 2",
+			args: args{seed: seed, level: "2", n: 10},
+			want: pickWant(seed, "2", 10),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			f := &RaffleContract{}
-			if got := f.bkdrHash(tt.args.timestamp); got != tt.want {
-				t.Errorf("bkdrHash() = %v, want %v", got, tt.want)
+			if got := f.pick(tt.args.seed, tt.args.level, tt.args.n); got != tt.want {
+				t.Errorf("pick() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func pickWant(seed []byte, level string, n int) int {
+	h := sha256.Sum256(append(append([]byte{}, seed...), level...))
+	return int(new(big.Int).Mod(new(big.Int).SetBytes(h[:]), big.NewInt(int64(n))).Int64())
+}
+
 func TestRaffleContract_query(t *testing.T) {
 	tests := []struct {
 		name string
@@ -111,55 +140,202 @@ func TestRaffleContract_query(t *testing.T) {
 	}
 }
 
+// mockGetPutState keeps the state of a test in stateMap by key#field
+func mockGetPutState(mockInstance *sdk.MockSDKInterface, stateMap map[string][]byte) {
+	mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) (string, error) { return string(stateMap[key+"#"+field]), nil })
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) ([]byte, error) { return stateMap[key+"#"+field], nil })
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string, value []byte) error { stateMap[key+"#"+field] = value; return nil })
+	mockInstance.EXPECT().PutState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field, value string) error { stateMap[key+"#"+field] = []byte(value); return nil })
+	mockInstance.EXPECT().DelState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) error { delete(stateMap, key+"#"+field); return nil })
+}
+
+// mockPendingIterator iterates the pending levels of stateMap in key order
+func mockPendingIterator(ctrl *gomock.Controller, mockInstance *sdk.MockSDKInterface, stateMap map[string][]byte) {
+	mockInstance.EXPECT().NewIteratorPrefixWithKeyField(keyPending, "").AnyTimes().DoAndReturn(
+		func(key, field string) (sdk.ResultSetKV, error) {
+			var fields []string
+			for k := range stateMap {
+				if strings.HasPrefix(k, keyPending+"#") {
+					fields = append(fields, strings.TrimPrefix(k, keyPending+"#"))
+				}
+			}
+			sort.Strings(fields)
+			iter := sdk.NewMockResultSetKV(ctrl)
+			iter.EXPECT().HasNext().AnyTimes().DoAndReturn(func() bool { return len(fields) > 0 })
+			iter.EXPECT().Next().AnyTimes().DoAndReturn(func() (string, string, []byte, error) {
+				field := fields[0]
+				fields = fields[1:]
+				return keyPending, field, stateMap[keyPending+"#"+field], nil
+			})
+			iter.EXPECT().Close().AnyTimes().Return(true, nil)
+			return iter, nil
+		})
+}
+
 func TestRaffleContract_raffle(t *testing.T) {
+	seed := bytes.Repeat([]byte{0xab}, 32)
+	// round 2 failed, round 3 is finalized and round 4 is not
+	rounds := map[string]protogo.Response{
+		"2": sdk.Success([]byte(`{"round":2,"finalized":true,"failed":true}`)),
+		"3": sdk.Success([]byte(`{"round":3,"seed":"` + hex.EncodeToString(seed) + `","finalized":true}`)),
+		"4": sdk.Success([]byte(`{"round":4}`)),
+	}
+	peoples := []string{"Chris", "Linus", "Ada"}
+
+	// the levels of round 3 are drawn in order from the peoples left
+	var winners []string
+	left := append([]string{}, peoples...)
+	for _, level := range []string{"1", "2"} {
+		pos := pickWant(seed, level, len(left))
+		num := 0
+		for i, name := range peoples {
+			if name == left[pos] {
+				num = i + 1
+			}
+		}
+		winners = append(winners, fmt.Sprintf("num: %d, name: %s, level: %s", num, left[pos], level))
+		left = append(left[:pos:pos], left[pos+1:]...)
+	}
+
 	tests := []struct {
-		name string
-		want protogo.Response
+		name        string
+		pending     map[string]string
+		want        protogo.Response
+		wantPending []string
 	}{
-		// TODO: Add test cases.
+		{
+			name:        "draw ready levels",
+			pending:     map[string]string{"1": "3", "2": "3", "3": "4", "4": "2"},
+			want:        sdk.Success([]byte(strings.Join(winners, "\n"))),
+			wantPending: []string{"3", "4"},
+		},
+		{
+			name:    "not requested",
+			pending: map[string]string{},
+			want:    sdk.Error("no raffle is requested"),
+		},
+		{
+			name:        "round not finalized",
+			pending:     map[string]string{"3": "4"},
+			want:        sdk.Error("no requested raffle has a finalized beacon round"),
+			wantPending: []string{"3"},
+		},
+		{
+			name:        "round failed",
+			pending:     map[string]string{"4": "2"},
+			want:        sdk.Error("no requested raffle has a finalized beacon round"),
+			wantPending: []string{"4"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+			stateMap := map[string][]byte{
+				"beacon#":       []byte("beacon"),
+				"pendingCount#": []byte(strconv.Itoa(len(tt.pending))),
+				"peoples#": []byte(`{"peoples":[{"num":1,"name":"Chris"},{"num":2,"name":"Linus"},` +
+					`{"num":3,"name":"Ada"}]}`),
+			}
+			for level, round := range tt.pending {
+				stateMap["pending#"+level] = []byte(round)
+			}
+			mockGetPutState(mockInstance, stateMap)
+			mockPendingIterator(ctrl, mockInstance, stateMap)
+			mockInstance.EXPECT().CallContract("beacon", "getRound", gomock.Any()).AnyTimes().DoAndReturn(
+				func(contractName, method string, args map[string][]byte) protogo.Response {
+					if resp, ok := rounds[string(args["round"])]; ok {
+						return resp
+					}
+					return sdk.Error("round not found")
+				})
+			mockInstance.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+			mockInstance.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			sdk.Instance = mockInstance
+
 			f := &RaffleContract{}
 			if got := f.raffle(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("raffle() = %v, want %v", got, tt.want)
 			}
+			var pending []string
+			for level := range tt.pending {
+				if _, ok := stateMap["pending#"+level]; ok {
+					pending = append(pending, level)
+				}
+			}
+			sort.Strings(pending)
+			if !reflect.DeepEqual(pending, tt.wantPending) {
+				t.Errorf("raffle() pending = %v, want %v", pending, tt.wantPending)
+			}
+			if tt.want.Status == sdk.OK {
+				if count := string(stateMap["pendingCount#"]); count != strconv.Itoa(len(tt.wantPending)) {
+					t.Errorf("raffle() pending count = %s, want %d", count, len(tt.wantPending))
+				}
+			}
 		})
 	}
 }
 
 func TestRaffleContract_registerAll(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	mockInstance := sdk.NewMockSDKInterface(ctrl)
-
-	args := make(map[string][]byte)
-	args["peoples"] = []byte("{\"peoples\":[{\"num\":1,\"name\":\"Chris\"},{\"num\":2,\"name\":\"Linus\"}]}")
-	mockInstance.EXPECT().GetArgs().AnyTimes().Return(args)
-	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
-	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
-	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
-	sdk.Instance = mockInstance
+	peoples := []byte("{\"peoples\":[{\"num\":1,\"name\":\"Chris\"},{\"num\":2,\"name\":\"Linus\"}]}")
 	tests := []struct {
-		name string
-		want protogo.Response
+		name   string
+		sender string
+		state  map[string][]byte
+		want   protogo.Response
 	}{
-		// TODO: Add test cases.
 		{
-			name: "right",
+			name:   "right",
+			sender: "admin",
+			state:  map[string][]byte{"admin#": []byte("admin")},
 			want: protogo.Response{
 				Status:  0,
 				Message: "",
 				Payload: []byte("ok"),
 			},
 		},
+		{
+			name:   "not admin",
+			sender: "alice",
+			state:  map[string][]byte{"admin#": []byte("admin")},
+			want:   sdk.Error("[registerAll] only admin can register peoples"),
+		},
+		{
+			name:   "raffle pending",
+			sender: "admin",
+			state:  map[string][]byte{"admin#": []byte("admin"), "pendingCount#": []byte("1")},
+			want:   sdk.Error("[registerAll] 1 raffles are pending, peoples can not be changed"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockInstance := sdk.NewMockSDKInterface(ctrl)
+			mockInstance.EXPECT().GetArgs().AnyTimes().Return(map[string][]byte{"peoples": peoples})
+			mockInstance.EXPECT().Sender().AnyTimes().Return(tt.sender, nil)
+			mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+				func(key, field string) (string, error) { return string(tt.state[key+"#"+field]), nil })
+			mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+				func(key, field string, value []byte) error { tt.state[key+"#"+field] = value; return nil })
+			mockInstance.EXPECT().Debugf(gomock.Any(), gomock.Any()).AnyTimes()
+			mockInstance.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+			sdk.Instance = mockInstance
+
 			f := &RaffleContract{}
 			if got := f.registerAll(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("registerAll() = %v, want %v", got, tt.want)
 			}
+			if registered := tt.want.Status == sdk.OK; registered != bytes.Equal(tt.state["peoples#"], peoples) {
+				t.Errorf("registerAll() peoples = %s", tt.state["peoples#"])
+			}
 		})
 	}
 }
@@ -33,6 +33,9 @@ const (
 	symbolKey      = "symbol"
 	decimalKey     = "decimal"
 	adminKey       = "admin"
+	checkpointKey  = "cp"
+	// checkpointCountField the field of the checkpoint count, checkpoints are at fields "0", "1", ...
+	checkpointCountField = "n"
 )
 
 var (
@@ -78,6 +81,29 @@ func (c *CmdfaContract) BalanceOf(account string) (*safemath.SafeUint256, error)
 	return c.GetBalance(account)
 }
 
+// BalanceOfAt 账户在某区块高度的余额快照，即该区块执行完成后的余额
+// @param account
+// @param height 必须小于当前区块高度
+// @return *safemath.SafeUint256
+// @return error
+func (c *CmdfaContract) BalanceOfAt(account string, height uint64) (*safemath.SafeUint256, error) {
+	if err := c.requirePastHeight(height); err != nil {
+		return nil, err
+	}
+	return c.getCheckpoint(createCompositeKey(checkpointKey, balanceKey, account), height, balanceKey, account)
+}
+
+// TotalSupplyAt 某区块高度的发行总量快照
+// @param height 必须小于当前区块高度
+// @return *safemath.SafeUint256
+// @return error
+func (c *CmdfaContract) TotalSupplyAt(height uint64) (*safemath.SafeUint256, error) {
+	if err := c.requirePastHeight(height); err != nil {
+		return nil, err
+	}
+	return c.getCheckpoint(createCompositeKey(checkpointKey, totalSupplyKey), height, totalSupplyKey, "")
+}
+
 // Transfer 转账操作
 // @param to
 // @param amount
@@ -292,6 +318,10 @@ func (c *CmdfaContract) GetBalance(account string) (*safemath.SafeUint256, error
 // @param amount
 // @return error
 func (c *CmdfaContract) SetBalance(account string, amount *safemath.SafeUint256) error {
+	err := c.setCheckpoint(createCompositeKey(checkpointKey, balanceKey, account), amount, balanceKey, account)
+	if err != nil {
+		return err
+	}
 	return sdk.Instance.PutState(balanceKey, account, amount.ToString())
 }
 
@@ -326,9 +356,133 @@ func (c *CmdfaContract) GetTotalSupply() (*safemath.SafeUint256, error) {
 // @param amount
 // @return error
 func (c *CmdfaContract) SetTotalSupply(amount *safemath.SafeUint256) error {
+	err := c.setCheckpoint(createCompositeKey(checkpointKey, totalSupplyKey), amount, totalSupplyKey, "")
+	if err != nil {
+		return err
+	}
 	return sdk.Instance.PutState(totalSupplyKey, "", amount.ToString())
 }
 
+// setCheckpoint 记录当前区块高度的值，一个区块内多次修改只保留最后的值。
+// 第一个检查点记录高度0时的原值(valueKey, valueField)，兼容升级前已有的余额
+// @param key 检查点的key
+// @param amount 新的值
+// @param valueKey
+// @param valueField
+// @return error
+func (c *CmdfaContract) setCheckpoint(key string, amount *safemath.SafeUint256,
+	valueKey, valueField string) error {
+	height, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return err
+	}
+	count, err := c.getCheckpointCount(key)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		old, err1 := c.GetUint256(valueKey, valueField)
+		if err1 != nil {
+			return err1
+		}
+		if err1 = c.putCheckpoint(key, 0, 0, old); err1 != nil {
+			return err1
+		}
+		count = 1
+	}
+	lastHeight, _, err := c.getCheckpointAt(key, count-1)
+	if err != nil {
+		return err
+	}
+	if lastHeight == uint64(height) {
+		return c.putCheckpoint(key, count-1, lastHeight, amount)
+	}
+	if err = c.putCheckpoint(key, count, uint64(height), amount); err != nil {
+		return err
+	}
+	return sdk.Instance.PutState(key, checkpointCountField, strconv.FormatUint(count+1, 10))
+}
+
+// getCheckpoint 二分查找不高于height的最后一个检查点，没有检查点时值未修改过，返回当前值
+// @param key 检查点的key
+// @param height
+// @param valueKey
+// @param valueField
+// @return *safemath.SafeUint256
+// @return error
+func (c *CmdfaContract) getCheckpoint(key string, height uint64,
+	valueKey, valueField string) (*safemath.SafeUint256, error) {
+	count, err := c.getCheckpointCount(key)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return c.GetUint256(valueKey, valueField)
+	}
+	// the first checkpoint is at height 0
+	low, high := uint64(0), count-1
+	for low < high {
+		mid := (low + high + 1) / 2
+		midHeight, _, err1 := c.getCheckpointAt(key, mid)
+		if err1 != nil {
+			return nil, err1
+		}
+		if midHeight <= height {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	_, amount, err := c.getCheckpointAt(key, low)
+	return amount, err
+}
+
+func (c *CmdfaContract) getCheckpointCount(key string) (uint64, error) {
+	countStr, err := sdk.Instance.GetState(key, checkpointCountField)
+	if err != nil || len(countStr) == 0 {
+		return 0, err
+	}
+	return strconv.ParseUint(countStr, 10, 64)
+}
+
+// getCheckpointAt 第index个检查点，值的格式为 height,amount
+func (c *CmdfaContract) getCheckpointAt(key string, index uint64) (uint64, *safemath.SafeUint256, error) {
+	value, err := sdk.Instance.GetState(key, strconv.FormatUint(index, 10))
+	if err != nil {
+		return 0, nil, err
+	}
+	parts := strings.SplitN(value, ",", 2)
+	if len(parts) != 2 {
+		return 0, nil, errors.New("invalid checkpoint data")
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, nil, errors.New("invalid checkpoint data")
+	}
+	amount, ok := safemath.ParseSafeUint256(parts[1])
+	if !ok {
+		return 0, nil, errors.New("invalid checkpoint data")
+	}
+	return height, amount, nil
+}
+
+func (c *CmdfaContract) putCheckpoint(key string, index, height uint64, amount *safemath.SafeUint256) error {
+	return sdk.Instance.PutState(key, strconv.FormatUint(index, 10),
+		strconv.FormatUint(height, 10)+","+amount.ToString())
+}
+
+// requirePastHeight 快照高度必须小于当前区块高度，当前区块的值还可能被修改
+func (c *CmdfaContract) requirePastHeight(height uint64) error {
+	current, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return err
+	}
+	if height >= uint64(current) {
+		return errors.New("CMDFA: snapshot height should be less than the current block height")
+	}
+	return nil
+}
+
 // GetName 获得DB中的Name
 // @return string
 // @return error
@@ -27,6 +27,7 @@ func TestERC20Contract_InitContract(t *testing.T) {
 	mockGetPutState(mockInstance)
 	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
 	mockInstance.EXPECT().Sender().AnyTimes().Return("studyzy", nil)
+	mockInstance.EXPECT().Origin().AnyTimes().Return("studyzy", nil)
 
 	sdk.Instance = mockInstance
 
@@ -82,6 +83,7 @@ func TestERC20Contract_InvokeContract(t *testing.T) {
 	mockGetPutState(mockInstance)
 	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
 	mockInstance.EXPECT().Sender().AnyTimes().Return("B250CEF819", nil)
+	mockInstance.EXPECT().Origin().AnyTimes().Return("B250CEF819", nil)
 	sdk.Instance = mockInstance
 
 	type args struct {
@@ -113,6 +115,26 @@ func TestERC20Contract_InvokeContract(t *testing.T) {
 				Message: "CMDFA: require parameter:'account'",
 			},
 		},
+		{
+			name: "balanceOfAt",
+			args: args{
+				method: "BalanceOfAt",
+			},
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "CMDFA: require parameter:'account'",
+			},
+		},
+		{
+			name: "totalSupplyAt",
+			args: args{
+				method: "TotalSupplyAt",
+			},
+			want: protogo.Response{
+				Status:  sdk.ERROR,
+				Message: "CMDFA: require parameter:'height'",
+			},
+		},
 		{
 			name: "transfer",
 			args: args{
@@ -561,3 +583,76 @@ func TestERC20Contract_transferFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestERC20Contract_BalanceOfAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	height := 10
+	mockGetPutState(mockInstance)
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) {
+		return height, nil
+	})
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes().Return()
+	sdk.Instance = mockInstance
+
+	c := &CmdfaContract{}
+	from := "a04f7895de24f61807a729be230f03da8c0eef42"
+	to := "b04f7895de24f61807a729be230f03da8c0eef42"
+	// balance before the checkpoints
+	stateMap[balanceKey+"#"+from] = "100"
+	stateMap[totalSupplyKey+"#"] = "100"
+	for _, amount := range []string{"10", "20"} {
+		num, _ := safemath.ParseSafeUint256(amount)
+		if err := c.baseTransfer(from, to, num); err != nil {
+			t.Fatal(err)
+		}
+	}
+	height = 20
+	num, _ := safemath.ParseSafeUint256("50")
+	if err := c.baseMint(to, num); err != nil {
+		t.Fatal(err)
+	}
+	height = 30
+
+	type args struct {
+		account string
+		height  uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{name: "from before", args: args{account: from, height: 9}, want: "100"},
+		{name: "from at", args: args{account: from, height: 10}, want: "70"},
+		{name: "from after", args: args{account: from, height: 29}, want: "70"},
+		{name: "to before", args: args{account: to, height: 9}, want: "0"},
+		{name: "to at", args: args{account: to, height: 10}, want: "30"},
+		{name: "to minted", args: args{account: to, height: 20}, want: "80"},
+		{name: "not checkpointed", args: args{account: "c04f7895de24f61807a729be230f03da8c0eef42",
+			height: 20}, want: "0"},
+		{name: "current height", args: args{account: from, height: 30}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.BalanceOfAt(tt.args.account, tt.args.height)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BalanceOfAt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.ToString() != tt.want {
+				t.Errorf("BalanceOfAt() = %v, want %v", got.ToString(), tt.want)
+			}
+		})
+	}
+
+	for h, want := range map[uint64]string{9: "100", 10: "100", 20: "150"} {
+		got, err := c.TotalSupplyAt(h)
+		if err != nil || got.ToString() != want {
+			t.Errorf("TotalSupplyAt(%d) = %v, %v, want %v", h, got, err, want)
+		}
+	}
+}
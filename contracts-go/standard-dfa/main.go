@@ -23,6 +23,7 @@ const (
 	pTo          = "to"
 	pSpender     = "spender"
 	pOwner       = "owner"
+	pHeight      = "height"
 )
 
 func main() {
@@ -131,6 +132,22 @@ func (c *CmdfaContract) InvokeContract(method string) protogo.Response { // noli
 			return sdk.Error(err.Error())
 		}
 		return ReturnUint256(c.BalanceOf(account))
+	case "BalanceOfAt":
+		account, err := RequireString(pAccount)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		height, err := RequireUint64(pHeight)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnUint256(c.BalanceOfAt(account, height))
+	case "TotalSupplyAt":
+		height, err := RequireUint64(pHeight)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnUint256(c.TotalSupplyAt(height))
 	case "Transfer":
 		to, err := RequireString(pTo)
 		if err != nil {
@@ -291,3 +308,20 @@ func RequireUint256(key string) (*safemath.SafeUint256, error) {
 	}
 	return num, nil
 }
+
+// RequireUint64 必须要有参数 uint64类型
+// @param key
+// @return uint64
+// @return error
+func RequireUint64(key string) (uint64, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok {
+		return 0, fmt.Errorf("CMDFA: require parameter:'%s'", key)
+	}
+	num, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("CMDFA: parameter:'%s' not a valid uint64", key)
+	}
+	return num, nil
+}
@@ -0,0 +1,86 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	eventMint                  = "Mint"
+	eventSetApproval           = "SetApproval"
+	eventSetApprovalForAll     = "SetApprovalForAll"
+	eventTransferFrom          = "TransferFrom"
+	eventBurn                  = "Burn"
+	eventSetApprovalByCategory = "SetApprovalByCategory"
+	eventCreateOrSetCategory   = "CreateOrSetCategory"
+)
+
+// nfaEventSchemas schemas of the events emitted by CMNFA, saved on chain when the contract is installed
+var nfaEventSchemas = []*sdk.EventSchema{
+	sdk.NewEventSchema(eventMint,
+		sdk.NewEventField("from", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("tokenId", sdk.EventFieldString, true),
+		sdk.NewEventField("categoryName", sdk.EventFieldString, false),
+		sdk.NewEventField("metadata", sdk.EventFieldString, false),
+	),
+	sdk.NewEventSchema(eventSetApproval,
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("tokenId", sdk.EventFieldString, true),
+		sdk.NewEventField("isApproval", sdk.EventFieldBool, false),
+	),
+	sdk.NewEventSchema(eventSetApprovalForAll,
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("isApproval", sdk.EventFieldBool, false),
+	),
+	sdk.NewEventSchema(eventTransferFrom,
+		sdk.NewEventField("from", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("tokenId", sdk.EventFieldString, true),
+	),
+	sdk.NewEventSchema(eventBurn,
+		sdk.NewEventField("tokenId", sdk.EventFieldString, true),
+	),
+	sdk.NewEventSchema(eventSetApprovalByCategory,
+		sdk.NewEventField("owner", sdk.EventFieldAddress, true),
+		sdk.NewEventField("to", sdk.EventFieldAddress, true),
+		sdk.NewEventField("categoryName", sdk.EventFieldString, true),
+		sdk.NewEventField("isApproval", sdk.EventFieldBool, false),
+	),
+	sdk.NewEventSchema(eventCreateOrSetCategory,
+		sdk.NewEventField("categoryName", sdk.EventFieldString, true),
+		sdk.NewEventField("categoryURI", sdk.EventFieldString, false),
+	),
+}
+
+func init() {
+	if err := sdk.RegisterEventSchemas(nfaEventSchemas...); err != nil {
+		panic(err)
+	}
+}
+
+// EventSchemas returns the event schemas of CMNFA
+func (c *CMNFAContract) EventSchemas() []*sdk.EventSchema {
+	return nfaEventSchemas
+}
+
+// emitEvent emits event described by nfaEventSchemas
+func emitEvent(name string, values ...interface{}) error {
+	return sdk.EmitTypedEvent(name, values...)
+}
+
+// logEventError logs the error of an event emitted by the Emit*Event methods of CMNFA,
+// they are part of the standard interface and have no error result
+func logEventError(name string, err error) {
+	if err != nil {
+		sdk.Instance.Errorf("emit event [%s] failed, %s", name, err)
+	}
+}
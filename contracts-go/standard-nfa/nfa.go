@@ -53,6 +53,7 @@ const (
 
 	adminStoreKey        = "admin"
 	metadataStoreKey     = "metadata"
+	minterStoreKey       = "minter"
 	categoryNameStoreKey = "categoryName"
 	totalSupplyStoreKey  = "TotalSupply"
 
@@ -61,6 +62,7 @@ const (
 
 var _ standard.CMNFA = (*CMNFAContract)(nil)
 var _ standard.CMBC = (*CMNFAContract)(nil)
+var _ sdk.EventSchemaDeclarer = (*CMNFAContract)(nil)
 var _ standard.CMNFAOption = (*CMNFAContract)(nil)
 
 // CMNFAContract contract for NFA
@@ -171,6 +173,8 @@ func (c *CMNFAContract) InvokeContract(method string) protogo.Response {
 		return c.accountTokensCore()
 	case "TokenMetadata":
 		return c.tokenMetadataCore()
+	case "MinterOf":
+		return c.minterOfCore()
 	default:
 		return sdk.Error("Invalid method")
 	}
@@ -265,13 +269,12 @@ func (c *CMNFAContract) Mint(to, tokenId, categoryName string, metadata []byte)
 		return err
 	}
 
-	err = c.setTokenInfo(tokenId, categoryName, metadata)
+	err = c.setTokenInfo(tokenId, categoryName, to, metadata)
 	if err != nil {
 		return err
 	}
 
-	c.EmitMintEvent(to, tokenId, categoryName, string(metadata))
-	return nil
+	return emitEvent(eventMint, address.ZeroAddr, to, tokenId, categoryName, string(metadata))
 }
 
 func (c *CMNFAContract) mintBatchCore() protogo.Response {
@@ -414,9 +417,7 @@ func (c *CMNFAContract) SetApproval(owner, to, tokenId string, isApproval bool)
 		return fmt.Errorf("set approve failed, err:%s", err)
 	}
 
-	c.EmitSetApprovalEvent(owner, to, tokenId, isApproval)
-
-	return nil
+	return emitEvent(eventSetApproval, owner, to, tokenId, isApproval)
 }
 
 func (c *CMNFAContract) setApprovalForAllCore() protogo.Response {
@@ -460,9 +461,7 @@ func (c *CMNFAContract) SetApprovalForAll(owner, to string, isApproval bool) err
 		return fmt.Errorf("set operator approve failed, err:%s", err)
 	}
 
-	c.EmitSetApprovalForAllEvent(owner, to, isApproval)
-
-	return nil
+	return emitEvent(eventSetApprovalForAll, owner, to, isApproval)
 }
 
 func (c *CMNFAContract) getApproved(tokenId string) (bool, error) {
@@ -574,9 +573,7 @@ func (c *CMNFAContract) TransferFrom(from, to, tokenId string) error {
 		return err
 	}
 
-	c.EmitTransferFromEvent(from, to, tokenId)
-
-	return nil
+	return emitEvent(eventTransferFrom, from, to, tokenId)
 }
 
 func (c *CMNFAContract) transferFromBatchCore() protogo.Response {
@@ -716,30 +713,22 @@ func (c *CMNFAContract) TokenURI(tokenId string) (uri string, err error) {
 
 // EmitMintEvent emits Mint event
 func (c *CMNFAContract) EmitMintEvent(to, tokenId, categoryName, metadata string) {
-	sdk.Instance.EmitEvent("Mint", []string{address.ZeroAddr, to, tokenId, categoryName, metadata})
+	logEventError(eventMint, emitEvent(eventMint, address.ZeroAddr, to, tokenId, categoryName, metadata))
 }
 
 // EmitSetApprovalEvent emits SetApproval event
 func (c *CMNFAContract) EmitSetApprovalEvent(owner, to, tokenId string, isApproval bool) {
-	if isApproval {
-		sdk.Instance.EmitEvent("SetApproval", []string{owner, to, tokenId, standard.TrueString})
-	} else {
-		sdk.Instance.EmitEvent("SetApproval", []string{owner, to, tokenId, standard.FalseString})
-	}
+	logEventError(eventSetApproval, emitEvent(eventSetApproval, owner, to, tokenId, isApproval))
 }
 
 // EmitSetApprovalForAllEvent emits SetApprovalForAll event
 func (c *CMNFAContract) EmitSetApprovalForAllEvent(owner, to string, isApproval bool) {
-	if isApproval {
-		sdk.Instance.EmitEvent("SetApprovalForAll", []string{owner, to, standard.TrueString})
-	} else {
-		sdk.Instance.EmitEvent("SetApprovalForAll", []string{owner, to, standard.FalseString})
-	}
+	logEventError(eventSetApprovalForAll, emitEvent(eventSetApprovalForAll, owner, to, isApproval))
 }
 
 // EmitTransferFromEvent emits TransferFrom event
 func (c *CMNFAContract) EmitTransferFromEvent(from, to, tokenId string) {
-	sdk.Instance.EmitEvent("TransferFrom", []string{from, to, tokenId})
+	logEventError(eventTransferFrom, emitEvent(eventTransferFrom, from, to, tokenId))
 }
 
 func (c *CMNFAContract) setApprovalByCategoryCore() protogo.Response {
@@ -792,9 +781,7 @@ func (c *CMNFAContract) SetApprovalByCategory(owner, to, categoryName string, is
 		return err
 	}
 
-	c.EmitSetApprovalByCategoryEvent(owner, to, categoryName, isApproval)
-
-	return nil
+	return emitEvent(eventSetApprovalByCategory, owner, to, categoryName, isApproval)
 }
 
 func (c *CMNFAContract) createOrSetCategoryCore() protogo.Response {
@@ -829,7 +816,10 @@ func (c *CMNFAContract) CreateOrSetCategory(category *standard.Category) error {
 		return err
 	}
 
-	c.EmitCreateOrSetCategoryEvent(category.CategoryName, category.CategoryURI)
+	err = emitEvent(eventCreateOrSetCategory, category.CategoryName, category.CategoryURI)
+	if err != nil {
+		return err
+	}
 
 	return cm.Set([]string{category.CategoryName}, []byte(category.CategoryURI))
 }
@@ -896,9 +886,7 @@ func (c *CMNFAContract) Burn(tokenId string) error {
 		return err
 	}
 
-	c.EmitBurnEvent(tokenId)
-
-	return nil
+	return emitEvent(eventBurn, tokenId)
 }
 
 func (c *CMNFAContract) getCategoryByNameCore() protogo.Response {
@@ -1190,23 +1178,48 @@ func (c *CMNFAContract) TokenMetadata(tokenId string) ([]byte, error) {
 	return metadata, nil
 }
 
+func (c *CMNFAContract) minterOfCore() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	tokenId := string(args[paramTokenId])
+	if len(tokenId) == 0 {
+		return sdk.Error("invalid tokenId")
+	}
+	minter, err := c.MinterOf(tokenId)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(minter))
+}
+
+// MinterOf get the minter of a token, the first owner when the token was minted. Optional.
+// @param tokenId, tokenId which will be queried.
+// @return minter, empty for the tokens minted before the minter was recorded.
+// @return err, the error msg if some error occur.
+func (c *CMNFAContract) MinterOf(tokenId string) (string, error) {
+	ti, err := c.getTokenInfoStoreMap()
+	if err != nil {
+		return "", fmt.Errorf("new store map of token info failed, err:%s", err)
+	}
+	minter, err := ti.Get([]string{tokenId, minterStoreKey})
+	if err != nil {
+		return "", fmt.Errorf("get minter of token info failed, err:%s", err)
+	}
+	return string(minter), nil
+}
+
 // EmitBurnEvent emits Burn event
 func (c *CMNFAContract) EmitBurnEvent(tokenId string) {
-	sdk.Instance.EmitEvent("Burn", []string{tokenId})
+	logEventError(eventBurn, emitEvent(eventBurn, tokenId))
 }
 
 // EmitSetApprovalByCategoryEvent emits SetApprovalByCategory event
 func (c *CMNFAContract) EmitSetApprovalByCategoryEvent(owner, to, categoryName string, isApproval bool) {
-	if isApproval {
-		sdk.Instance.EmitEvent("SetApprovalByCategory", []string{owner, to, categoryName, standard.TrueString})
-	} else {
-		sdk.Instance.EmitEvent("SetApprovalByCategory", []string{owner, to, categoryName, standard.FalseString})
-	}
+	logEventError(eventSetApprovalByCategory, emitEvent(eventSetApprovalByCategory, owner, to, categoryName, isApproval))
 }
 
 // EmitCreateOrSetCategoryEvent emits CreateOrSetCategory event
 func (c *CMNFAContract) EmitCreateOrSetCategoryEvent(categoryName, categoryURI string) {
-	sdk.Instance.EmitEvent("CreateOrSetCategory", []string{categoryName, categoryURI})
+	logEventError(eventCreateOrSetCategory, emitEvent(eventCreateOrSetCategory, categoryName, categoryURI))
 }
 
 func (c *CMNFAContract) setAccountToken(from, to string, tokenId string) error {
@@ -1229,7 +1242,7 @@ func (c *CMNFAContract) setAccountToken(from, to string, tokenId string) error {
 	return nil
 }
 
-func (c *CMNFAContract) setTokenInfo(tokenId, categoryName string, metadata []byte) error {
+func (c *CMNFAContract) setTokenInfo(tokenId, categoryName, minter string, metadata []byte) error {
 	ti, err := c.getTokenInfoStoreMap()
 	if err != nil {
 		return fmt.Errorf("new store map of token info failed, err:%s", err)
@@ -1238,6 +1251,10 @@ func (c *CMNFAContract) setTokenInfo(tokenId, categoryName string, metadata []by
 	if err != nil {
 		return fmt.Errorf("set category name of token info failed, err:%s", err)
 	}
+	err = ti.Set([]string{tokenId, minterStoreKey}, []byte(minter))
+	if err != nil {
+		return fmt.Errorf("set minter of token info failed, err:%s", err)
+	}
 	if len(metadata) > 0 {
 		err = ti.Set([]string{tokenId, metadataStoreKey}, metadata)
 		if err != nil {
@@ -0,0 +1,685 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	batchIdArgKey     = "batchId"
+	batchIdsArgKey    = "batchIds"
+	quantityArgKey    = "quantity"
+	splitsArgKey      = "splits"
+	roleArgKey        = "role"
+	docHashArgKey     = "docHash"
+	evidenceIdArgKey  = "evidenceId"
+	reasonArgKey      = "reason"
+	offsetArgKey      = "offset"
+	limitArgKey       = "limit"
+	identityArgKey    = "identity"
+	evidenceArgKey    = "evidence"
+	roleLevelsArgKey  = "roleLevels"
+	batchStoreMapKey  = "batchList"
+	configStoreMapKey = "traceConfig"
+	configField       = "config"
+	// maxGraphBatches 派生图遍历(召回、溯源)的批次数上限
+	maxGraphBatches = 1000
+	// maxPageLimit 溯源分页的最大条数
+	maxPageLimit = 100
+	// maxSplits 一次拆分的子批次数上限
+	maxSplits = 100
+)
+
+const (
+	batchStatusActive = iota
+	batchStatusSplit
+	batchStatusMerged
+)
+
+const (
+	relationSelf       = "self"
+	relationAncestor   = "ancestor"
+	relationDescendant = "descendant"
+)
+
+// BatchTrace 批次溯源
+type BatchTrace interface {
+
+	//创建批次，发送者为保管人
+	newBatch() protogo.Response
+
+	//拆分批次为若干子批次
+	splitBatch() protogo.Response
+
+	//合并若干批次为新批次
+	mergeBatches() protogo.Response
+
+	//按角色交接批次保管权
+	handoffBatch() protogo.Response
+
+	//附加链下文档哈希
+	attachDocument() protogo.Response
+
+	//召回批次及其派生批次
+	recallBatch() protogo.Response
+
+	//获取批次信息
+	getBatch() protogo.Response
+
+	//分页获取批次派生图的溯源记录
+	getProvenance() protogo.Response
+}
+
+var _ BatchTrace = (*TraceContract)(nil)
+
+// Config 溯源配置
+type Config struct {
+	//管理员
+	Admin string
+	//身份合约名，为空则不校验角色
+	Identity string
+	//存证合约名，为空则不校验存证
+	Evidence string
+	//角色 -> 身份合约要求的最低等级
+	RoleLevels map[string]int
+}
+
+// Batch 批次信息
+type Batch struct {
+	//批次id
+	BatchId string
+	//商品名称
+	Name string
+	//数量，拆分、合并后为0
+	Quantity uint64
+	//{0 :有效  1： 已拆分 2：已合并}
+	Status uint8
+	//当前保管人
+	Custodian string
+	//当前保管人角色
+	Role string
+	//是否召回
+	Recalled bool
+	//召回原因
+	RecallReason string
+	//父批次
+	Parents []string
+	//子批次
+	Children []string
+	//保管链
+	Custody []*Handoff
+	//附件
+	Attachments []*Attachment
+}
+
+// Handoff 保管交接
+type Handoff struct {
+	//交出人，创建时为空
+	From string
+	//接收人
+	To string
+	//接收人角色
+	Role string
+	//交接时间
+	OperatorTime string
+}
+
+// Attachment 链下文档
+type Attachment struct {
+	//文档哈希
+	DocHash string
+	//存证合约中的存证id
+	EvidenceId string
+	//操作人
+	Operator string
+	//操作时间
+	OperatorTime string
+}
+
+// Split 拆分出的子批次
+type Split struct {
+	BatchId  string `json:"batchId"`
+	Quantity uint64 `json:"quantity"`
+}
+
+// BatchRecord 批次的一次历史记录
+type BatchRecord struct {
+	TxId        string
+	BlockHeight int
+	Timestamp   string
+	Batch       *Batch
+}
+
+// ProvenanceNode 派生图中的批次及其历史记录
+type ProvenanceNode struct {
+	BatchId string
+	//相对查询批次的关系 {self, ancestor, descendant}
+	Relation string
+	//相对查询批次的距离
+	Depth   int
+	History []*BatchRecord
+}
+
+// Provenance 溯源分页结果
+type Provenance struct {
+	Total int
+	Nodes []*ProvenanceNode
+}
+
+// evidence 存证合约返回的存证信息
+type evidence struct {
+	Id   string `json:"id"`
+	Hash string `json:"hash"`
+}
+
+func (f *TraceContract) newBatch() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	batchId := string(args[batchIdArgKey])
+	if len(batchId) == 0 {
+		return sdk.Error("invalid batchId")
+	}
+	name := string(args[nameArgKey])
+	if len(name) == 0 {
+		return sdk.Error("invalid name")
+	}
+	quantity, err := strconv.ParseUint(string(args[quantityArgKey]), 10, 64)
+	if err != nil || quantity == 0 {
+		return sdk.Error("invalid quantity")
+	}
+
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("newBatch Sender failed, err: %s", err))
+	}
+	role := string(args[roleArgKey])
+	if err = checkRole(sender, role); err != nil {
+		return sdk.Error(err.Error())
+	}
+	if err = createBatch(&Batch{BatchId: batchId, Name: name, Quantity: quantity, Custodian: sender,
+		Role: role}); err != nil {
+		return sdk.Error(err.Error())
+	}
+	sdk.Instance.EmitEvent("BatchCreated", []string{batchId, name, strconv.FormatUint(quantity, 10), sender})
+	return sdk.Success([]byte("newBatch success"))
+}
+
+func (f *TraceContract) splitBatch() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	var splits []*Split
+	if err := json.Unmarshal(args[splitsArgKey], &splits); err != nil || len(splits) == 0 {
+		return sdk.Error("splits should be a json array of {batchId, quantity}")
+	}
+	if len(splits) > maxSplits {
+		return sdk.Error(fmt.Sprintf("splits should not be more than %d", maxSplits))
+	}
+	batch, err := requireCustody(string(args[batchIdArgKey]))
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+
+	var total uint64
+	for _, split := range splits {
+		if len(split.BatchId) == 0 || split.Quantity == 0 {
+			return sdk.Error("invalid split of batch " + batch.BatchId)
+		}
+		total += split.Quantity
+		if total < split.Quantity || total > batch.Quantity {
+			return sdk.Error(fmt.Sprintf("splits exceed the quantity %d of batch %s", batch.Quantity, batch.BatchId))
+		}
+	}
+	for _, split := range splits {
+		if err = createBatch(&Batch{BatchId: split.BatchId, Name: batch.Name, Quantity: split.Quantity,
+			Custodian: batch.Custodian, Role: batch.Role, Parents: []string{batch.BatchId}}); err != nil {
+			return sdk.Error(err.Error())
+		}
+		batch.Children = append(batch.Children, split.BatchId)
+		sdk.Instance.EmitEvent("BatchSplit", []string{batch.BatchId, split.BatchId,
+			strconv.FormatUint(split.Quantity, 10)})
+	}
+	batch.Quantity -= total
+	if batch.Quantity == 0 {
+		batch.Status = batchStatusSplit
+	}
+	if err = putBatch(batch); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("splitBatch success"))
+}
+
+func (f *TraceContract) mergeBatches() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	batchId := string(args[batchIdArgKey])
+	if len(batchId) == 0 {
+		return sdk.Error("invalid batchId")
+	}
+	var batchIds []string
+	if err := json.Unmarshal(args[batchIdsArgKey], &batchIds); err != nil || len(batchIds) < 2 {
+		return sdk.Error("batchIds should be a json array of at least 2 batch ids")
+	}
+	if len(batchIds) > maxSplits {
+		return sdk.Error(fmt.Sprintf("batchIds should not be more than %d", maxSplits))
+	}
+
+	merged := &Batch{BatchId: batchId}
+	parents := make([]*Batch, 0, len(batchIds))
+	for _, id := range batchIds {
+		for _, parent := range parents {
+			if parent.BatchId == id {
+				return sdk.Error("duplicate batch " + id)
+			}
+		}
+		batch, err := requireCustody(id)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		if len(merged.Name) == 0 {
+			merged.Name, merged.Custodian, merged.Role = batch.Name, batch.Custodian, batch.Role
+		} else if batch.Name != merged.Name {
+			return sdk.Error(fmt.Sprintf("batch %s is not of %s", id, merged.Name))
+		}
+		merged.Quantity += batch.Quantity
+		merged.Parents = append(merged.Parents, id)
+		parents = append(parents, batch)
+	}
+	if err := createBatch(merged); err != nil {
+		return sdk.Error(err.Error())
+	}
+	for _, batch := range parents {
+		batch.Children = append(batch.Children, batchId)
+		batch.Quantity = 0
+		batch.Status = batchStatusMerged
+		if err := putBatch(batch); err != nil {
+			return sdk.Error(err.Error())
+		}
+	}
+	sdk.Instance.EmitEvent("BatchMerged", []string{batchId, string(args[batchIdsArgKey]),
+		strconv.FormatUint(merged.Quantity, 10)})
+	return sdk.Success([]byte("mergeBatches success"))
+}
+
+func (f *TraceContract) handoffBatch() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	to := string(args[toArgKey])
+	if len(to) == 0 {
+		return sdk.Error("invalid to")
+	}
+	role := string(args[roleArgKey])
+	batch, err := requireCustody(string(args[batchIdArgKey]))
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if err = checkRole(to, role); err != nil {
+		return sdk.Error(err.Error())
+	}
+	time, err := sdk.Instance.GetTxTimeStamp()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("handoffBatch GetTxTimeStamp failed, err: %s", err))
+	}
+	batch.Custody = append(batch.Custody, &Handoff{From: batch.Custodian, To: to, Role: role, OperatorTime: time})
+	from := batch.Custodian
+	batch.Custodian, batch.Role = to, role
+	if err = putBatch(batch); err != nil {
+		return sdk.Error(err.Error())
+	}
+	sdk.Instance.EmitEvent("BatchHandoff", []string{batch.BatchId, from, to, role})
+	return sdk.Success([]byte("handoffBatch success"))
+}
+
+func (f *TraceContract) attachDocument() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	docHash := string(args[docHashArgKey])
+	if len(docHash) == 0 {
+		return sdk.Error("invalid docHash")
+	}
+	evidenceId := string(args[evidenceIdArgKey])
+	batchId := string(args[batchIdArgKey])
+	batch, err := getBatchById(batchId)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("attachDocument Sender failed, err: %s", err))
+	}
+	if sender != batch.Custodian {
+		return sdk.Error("only the custodian can attach documents to batch " + batchId)
+	}
+	if len(evidenceId) > 0 {
+		if err = checkEvidence(evidenceId, docHash); err != nil {
+			return sdk.Error(err.Error())
+		}
+	}
+	time, err := sdk.Instance.GetTxTimeStamp()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("attachDocument GetTxTimeStamp failed, err: %s", err))
+	}
+	batch.Attachments = append(batch.Attachments, &Attachment{DocHash: docHash, EvidenceId: evidenceId,
+		Operator: sender, OperatorTime: time})
+	if err = putBatch(batch); err != nil {
+		return sdk.Error(err.Error())
+	}
+	sdk.Instance.EmitEvent("DocumentAttached", []string{batchId, docHash, evidenceId})
+	return sdk.Success([]byte("attachDocument success"))
+}
+
+// recallBatch 由管理员或批次的创建人召回，召回标记传递到所有派生批次
+func (f *TraceContract) recallBatch() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	reason := string(args[reasonArgKey])
+	batch, err := getBatchById(string(args[batchIdArgKey]))
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("recallBatch Sender failed, err: %s", err))
+	}
+	config, err := getConfig()
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	if sender != config.Admin && sender != batch.Custody[0].To {
+		return sdk.Error("only the admin or the creator can recall batch " + batch.BatchId)
+	}
+	if batch.Recalled {
+		return sdk.Error("batch " + batch.BatchId + " is recalled")
+	}
+
+	nodes, err := walkBatches(batch.BatchId, false)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	for _, node := range nodes {
+		derived, err := getBatchById(node.BatchId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		if derived.Recalled {
+			continue
+		}
+		derived.Recalled, derived.RecallReason = true, reason
+		if err = putBatch(derived); err != nil {
+			return sdk.Error(err.Error())
+		}
+		sdk.Instance.EmitEvent("BatchRecalled", []string{derived.BatchId, batch.BatchId, reason})
+	}
+	return sdk.Success([]byte("recallBatch success"))
+}
+
+func (f *TraceContract) getBatch() protogo.Response {
+	batch, err := getBatchById(string(sdk.Instance.GetArgs()[batchIdArgKey]))
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	batchBytes, err := json.Marshal(batch)
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("getBatch Marshal batch failed, err: %s", err))
+	}
+	return sdk.Success(batchBytes)
+}
+
+// getProvenance 遍历批次的所有祖先和派生批次，按遍历顺序分页返回各批次的历史记录，需要开启 history db
+func (f *TraceContract) getProvenance() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	batchId := string(args[batchIdArgKey])
+	if _, err := getBatchById(batchId); err != nil {
+		return sdk.Error(err.Error())
+	}
+	offset, limit := 0, maxPageLimit
+	var err error
+	if len(args[offsetArgKey]) > 0 {
+		if offset, err = strconv.Atoi(string(args[offsetArgKey])); err != nil || offset < 0 {
+			return sdk.Error("invalid offset")
+		}
+	}
+	if len(args[limitArgKey]) > 0 {
+		if limit, err = strconv.Atoi(string(args[limitArgKey])); err != nil || limit <= 0 || limit > maxPageLimit {
+			return sdk.Error(fmt.Sprintf("limit should be between 1 and %d", maxPageLimit))
+		}
+	}
+
+	ancestors, err := walkBatches(batchId, true)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	descendants, err := walkBatches(batchId, false)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	// 祖先由远及近，然后是批次自身和派生批次
+	nodes := make([]*ProvenanceNode, 0, len(ancestors)+len(descendants))
+	for i := len(ancestors) - 1; i > 0; i-- {
+		nodes = append(nodes, ancestors[i])
+	}
+	nodes = append(nodes, descendants...)
+
+	provenance := &Provenance{Total: len(nodes), Nodes: []*ProvenanceNode{}}
+	for i := offset; i < len(nodes) && i < offset+limit; i++ {
+		if nodes[i].History, err = getBatchHistory(nodes[i].BatchId); err != nil {
+			return sdk.Error(err.Error())
+		}
+		provenance.Nodes = append(provenance.Nodes, nodes[i])
+	}
+	provenanceBytes, err := json.Marshal(provenance)
+	if err != nil {
+		return sdk.Error(fmt.Sprintf("getProvenance Marshal failed, err: %s", err))
+	}
+	return sdk.Success(provenanceBytes)
+}
+
+// walkBatches 广度优先遍历派生图，up为true时沿父批次遍历，否则沿子批次遍历，第一个节点为批次自身
+func walkBatches(batchId string, up bool) ([]*ProvenanceNode, error) {
+	relation := relationDescendant
+	if up {
+		relation = relationAncestor
+	}
+	nodes := []*ProvenanceNode{{BatchId: batchId, Relation: relationSelf}}
+	visited := map[string]bool{batchId: true}
+	for i := 0; i < len(nodes); i++ {
+		batch, err := getBatchById(nodes[i].BatchId)
+		if err != nil {
+			return nil, err
+		}
+		next := batch.Children
+		if up {
+			next = batch.Parents
+		}
+		for _, id := range next {
+			if visited[id] {
+				continue
+			}
+			if len(nodes) >= maxGraphBatches {
+				return nil, fmt.Errorf("derivation graph of batch %s exceeds %d batches", batchId, maxGraphBatches)
+			}
+			visited[id] = true
+			nodes = append(nodes, &ProvenanceNode{BatchId: id, Relation: relation, Depth: nodes[i].Depth + 1})
+		}
+	}
+	return nodes, nil
+}
+
+// getBatchHistory 通过历史迭代器读取批次的所有历史记录
+func getBatchHistory(batchId string) ([]*BatchRecord, error) {
+	iter, err := sdk.Instance.NewHistoryKvIterForKey(batchStoreMapKey, batchId)
+	if err != nil {
+		return nil, fmt.Errorf("new HistoryKvIter for batch %s failed, err: %s", batchId, err)
+	}
+	defer iter.Close()
+
+	records := make([]*BatchRecord, 0)
+	for iter.HasNext() {
+		km, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("iterate history of batch %s failed, err: %s", batchId, err)
+		}
+		if km.IsDelete {
+			continue
+		}
+		var batch *Batch
+		if err = json.Unmarshal(km.Value, &batch); err != nil {
+			return nil, fmt.Errorf("unmarshal history of batch %s failed, err: %s", batchId, err)
+		}
+		records = append(records, &BatchRecord{TxId: km.TxId, BlockHeight: km.BlockHeight,
+			Timestamp: km.Timestamp, Batch: batch})
+	}
+	return records, nil
+}
+
+// requireCustody 获取发送者保管的、可操作的批次
+func requireCustody(batchId string) (*Batch, error) {
+	batch, err := getBatchById(batchId)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return nil, fmt.Errorf("get sender failed, err: %s", err)
+	}
+	if sender != batch.Custodian {
+		return nil, errors.New("only the custodian can operate batch " + batchId)
+	}
+	if batch.Recalled {
+		return nil, errors.New("batch " + batchId + " is recalled")
+	}
+	if batch.Status != batchStatusActive {
+		return nil, errors.New("batch " + batchId + " is split or merged")
+	}
+	return batch, nil
+}
+
+// checkRole 校验账户在身份合约中的等级满足角色要求，未配置的角色不校验
+func checkRole(account, role string) error {
+	config, err := getConfig()
+	if err != nil {
+		return err
+	}
+	minLevel, ok := config.RoleLevels[role]
+	if !ok {
+		if len(config.RoleLevels) > 0 {
+			return errors.New("invalid role " + role)
+		}
+		return nil
+	}
+	if len(config.Identity) == 0 {
+		return nil
+	}
+	resp := sdk.Instance.CallContract(config.Identity, "LevelOf", map[string][]byte{
+		"address": []byte(account),
+	})
+	if resp.Status != sdk.OK {
+		return fmt.Errorf("get level of %s failed, %s", account, resp.Message)
+	}
+	level, err := strconv.Atoi(string(resp.Payload))
+	if err != nil || level < minLevel {
+		return fmt.Errorf("level of %s should be at least %d for role %s", account, minLevel, role)
+	}
+	return nil
+}
+
+// checkEvidence 校验存证合约中的存证与文档哈希一致
+func checkEvidence(evidenceId, docHash string) error {
+	config, err := getConfig()
+	if err != nil {
+		return err
+	}
+	if len(config.Evidence) == 0 {
+		return errors.New("evidence contract is not configured")
+	}
+	resp := sdk.Instance.CallContract(config.Evidence, "FindById", map[string][]byte{
+		"id": []byte(evidenceId),
+	})
+	if resp.Status != sdk.OK {
+		return fmt.Errorf("find evidence %s failed, %s", evidenceId, resp.Message)
+	}
+	var e evidence
+	if err = json.Unmarshal(resp.Payload, &e); err != nil || e.Hash != docHash {
+		return fmt.Errorf("evidence %s does not match docHash %s", evidenceId, docHash)
+	}
+	return nil
+}
+
+// createBatch 保存新批次，记录创建人为保管链的第一个节点
+func createBatch(batch *Batch) error {
+	batchBytes, err := sdk.Instance.GetStateByte(batchStoreMapKey, batch.BatchId)
+	if err != nil {
+		return fmt.Errorf("createBatch GetStateByte error : %s", err)
+	}
+	if len(batchBytes) > 0 {
+		return errors.New("batchId " + batch.BatchId + " already exists")
+	}
+	time, err := sdk.Instance.GetTxTimeStamp()
+	if err != nil {
+		return fmt.Errorf("createBatch GetTxTimeStamp failed, err: %s", err)
+	}
+	batch.Custody = []*Handoff{{To: batch.Custodian, Role: batch.Role, OperatorTime: time}}
+	return putBatch(batch)
+}
+
+func putBatch(batch *Batch) error {
+	batchBytes, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("marshal batch failed, err: %s", err)
+	}
+	if err = sdk.Instance.PutStateByte(batchStoreMapKey, batch.BatchId, batchBytes); err != nil {
+		return fmt.Errorf("putBatch PutStateByte failed, err: %s", err)
+	}
+	return nil
+}
+
+func getBatchById(batchId string) (*Batch, error) {
+	if len(batchId) == 0 {
+		return nil, errors.New("invalid batchId")
+	}
+	batchBytes, err := sdk.Instance.GetStateByte(batchStoreMapKey, batchId)
+	if err != nil {
+		return nil, fmt.Errorf("getBatch GetStateByte error : %s", err)
+	}
+	if len(batchBytes) == 0 {
+		return nil, errors.New("invalid batchId, batch " + batchId + " not found")
+	}
+	var batch *Batch
+	if err = json.Unmarshal(batchBytes, &batch); err != nil {
+		return nil, fmt.Errorf("unmarshal batch failed, err: %s", err)
+	}
+	return batch, nil
+}
+
+// setConfig 根据参数保存配置，管理员为部署人
+func setConfig() error {
+	args := sdk.Instance.GetArgs()
+	admin, err := sdk.Instance.Origin()
+	if err != nil {
+		return fmt.Errorf("get origin failed, err: %s", err)
+	}
+	config := &Config{Admin: admin, Identity: string(args[identityArgKey]), Evidence: string(args[evidenceArgKey])}
+	if len(args[roleLevelsArgKey]) > 0 {
+		if err = json.Unmarshal(args[roleLevelsArgKey], &config.RoleLevels); err != nil {
+			return errors.New("roleLevels should be a json object of role to level")
+		}
+	}
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(configStoreMapKey, configField, configBytes)
+}
+
+func getConfig() (*Config, error) {
+	configBytes, err := sdk.Instance.GetStateByte(configStoreMapKey, configField)
+	if err != nil {
+		return nil, fmt.Errorf("get config failed, err: %s", err)
+	}
+	config := &Config{}
+	if len(configBytes) == 0 {
+		return config, nil
+	}
+	if err = json.Unmarshal(configBytes, config); err != nil {
+		return nil, fmt.Errorf("unmarshal config failed, err: %s", err)
+	}
+	return config, nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	admin            = "a04f7895de24f61807a729be230f03da8c0eef42"
+	farmer           = "b04f7895de24f61807a729be230f03da8c0eef42"
+	carrier          = "c04f7895de24f61807a729be230f03da8c0eef42"
+	retailer         = "d04f7895de24f61807a729be230f03da8c0eef42"
+	identityContract = "identity"
+	evidenceContract = "evidence"
+)
+
+// historyIter iterates the recorded history of a key
+type historyIter struct {
+	sdk.KeyHistoryKvIter
+	records []*sdk.KeyModification
+}
+
+func (i *historyIter) HasNext() bool {
+	return len(i.records) > 0
+}
+
+func (i *historyIter) Next() (*sdk.KeyModification, error) {
+	km := i.records[0]
+	i.records = i.records[1:]
+	return km, nil
+}
+
+func (i *historyIter) Close() (bool, error) {
+	return true, nil
+}
+
+// mockGetPutState keeps the state of a test in the returned map by key#field, every write is appended to the
+// history of the key
+func mockGetPutState(mockInstance *sdk.MockSDKInterface) map[string][]byte {
+	stateMap := make(map[string][]byte)
+	history := make(map[string][]*sdk.KeyModification)
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) ([]byte, error) {
+			return stateMap[key+"#"+field], nil
+		})
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string, value []byte) error {
+			stateMap[key+"#"+field] = value
+			history[key+"#"+field] = append(history[key+"#"+field], &sdk.KeyModification{Key: key, Field: field,
+				Value: value, TxId: strconv.Itoa(len(history[key+"#"+field]))})
+			return nil
+		})
+	mockInstance.EXPECT().NewHistoryKvIterForKey(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) (sdk.KeyHistoryKvIter, error) {
+			return &historyIter{records: history[key+"#"+field]}, nil
+		})
+	return stateMap
+}
+
+// mockContracts the identity contract answers the levels of the accounts, the evidence contract holds e1
+func mockContracts(mockInstance *sdk.MockSDKInterface) {
+	levels := map[string]string{admin: "5", farmer: "3", carrier: "2", retailer: "1"}
+	evidences := map[string]string{"e1": "h1"}
+	mockInstance.EXPECT().CallContract(identityContract, "LevelOf", gomock.Any()).AnyTimes().DoAndReturn(
+		func(contractName, method string, callArgs map[string][]byte) protogo.Response {
+			return sdk.Success([]byte(levels[string(callArgs["address"])]))
+		})
+	mockInstance.EXPECT().CallContract(evidenceContract, "FindById", gomock.Any()).AnyTimes().DoAndReturn(
+		func(contractName, method string, callArgs map[string][]byte) protogo.Response {
+			hash, ok := evidences[string(callArgs["id"])]
+			if !ok {
+				return sdk.Error("not found")
+			}
+			return sdk.Success([]byte(`{"id":"` + string(callArgs["id"]) + `","hash":"` + hash + `"}`))
+		})
+}
+
+// toArgs the string args as the args of the contract
+func toArgs(callArgs map[string]string) map[string][]byte {
+	args := make(map[string][]byte, len(callArgs))
+	for k, v := range callArgs {
+		args[k] = []byte(v)
+	}
+	return args
+}
+
+func TestTraceContract_batch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, args := admin, toArgs(map[string]string{identityArgKey: identityContract,
+		evidenceArgKey: evidenceContract, roleLevelsArgKey: `{"producer":3,"carrier":2,"retailer":1}`})
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return args })
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().Origin().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetTxTimeStamp().AnyTimes().Return("1700000000", nil)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockContracts(mockInstance)
+	sdk.Instance = mockInstance
+
+	c := &TraceContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+
+	tests := []struct {
+		name   string
+		sender string
+		method string
+		args   map[string]string
+		want   string
+	}{
+		{name: "new by low level", sender: carrier, method: "newBatch", args: map[string]string{
+			batchIdArgKey: "b1", nameArgKey: "apple", quantityArgKey: "100", roleArgKey: "producer"},
+			want: "level of " + carrier + " should be at least 3 for role producer"},
+		{name: "new with unknown role", sender: farmer, method: "newBatch", args: map[string]string{
+			batchIdArgKey: "b1", nameArgKey: "apple", quantityArgKey: "100", roleArgKey: "owner"},
+			want: "invalid role owner"},
+		{name: "new", sender: farmer, method: "newBatch", args: map[string]string{
+			batchIdArgKey: "b1", nameArgKey: "apple", quantityArgKey: "100", roleArgKey: "producer"}},
+		{name: "new twice", sender: farmer, method: "newBatch", args: map[string]string{
+			batchIdArgKey: "b1", nameArgKey: "apple", quantityArgKey: "100", roleArgKey: "producer"},
+			want: "batchId b1 already exists"},
+		{name: "split over quantity", sender: farmer, method: "splitBatch", args: map[string]string{
+			batchIdArgKey: "b1", splitsArgKey: `[{"batchId":"b1-1","quantity":60},{"batchId":"b1-2","quantity":50}]`},
+			want: "splits exceed the quantity 100 of batch b1"},
+		{name: "split by others", sender: carrier, method: "splitBatch", args: map[string]string{
+			batchIdArgKey: "b1", splitsArgKey: `[{"batchId":"b1-1","quantity":60}]`},
+			want: "only the custodian can operate batch b1"},
+		{name: "split", sender: farmer, method: "splitBatch", args: map[string]string{
+			batchIdArgKey: "b1", splitsArgKey: `[{"batchId":"b1-1","quantity":60},{"batchId":"b1-2","quantity":40}]`}},
+		{name: "handoff split batch", sender: farmer, method: "handoffBatch", args: map[string]string{
+			batchIdArgKey: "b1", toArgKey: carrier, roleArgKey: "carrier"},
+			want: "batch b1 is split or merged"},
+		{name: "handoff to low level", sender: farmer, method: "handoffBatch", args: map[string]string{
+			batchIdArgKey: "b1-1", toArgKey: retailer, roleArgKey: "carrier"},
+			want: "level of " + retailer + " should be at least 2 for role carrier"},
+		{name: "new other", sender: farmer, method: "newBatch", args: map[string]string{
+			batchIdArgKey: "b2", nameArgKey: "apple", quantityArgKey: "10", roleArgKey: "producer"}},
+		{name: "merge", sender: farmer, method: "mergeBatches", args: map[string]string{
+			batchIdArgKey: "m1", batchIdsArgKey: `["b1-2","b2"]`}},
+		{name: "merge merged batch", sender: farmer, method: "mergeBatches", args: map[string]string{
+			batchIdArgKey: "m2", batchIdsArgKey: `["b1-1","b2"]`},
+			want: "batch b2 is split or merged"},
+		{name: "handoff", sender: farmer, method: "handoffBatch", args: map[string]string{
+			batchIdArgKey: "m1", toArgKey: carrier, roleArgKey: "carrier"}},
+		{name: "attach with wrong evidence", sender: carrier, method: "attachDocument", args: map[string]string{
+			batchIdArgKey: "m1", docHashArgKey: "h2", evidenceIdArgKey: "e1"},
+			want: "evidence e1 does not match docHash h2"},
+		{name: "attach", sender: carrier, method: "attachDocument", args: map[string]string{
+			batchIdArgKey: "m1", docHashArgKey: "h1", evidenceIdArgKey: "e1"}},
+		{name: "recall by others", sender: carrier, method: "recallBatch", args: map[string]string{
+			batchIdArgKey: "b1", reasonArgKey: "pesticide"},
+			want: "only the admin or the creator can recall batch b1"},
+		{name: "recall", sender: farmer, method: "recallBatch", args: map[string]string{
+			batchIdArgKey: "b1", reasonArgKey: "pesticide"}},
+		{name: "handoff recalled batch", sender: carrier, method: "handoffBatch", args: map[string]string{
+			batchIdArgKey: "m1", toArgKey: retailer, roleArgKey: "retailer"},
+			want: "batch m1 is recalled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, args = tt.sender, toArgs(tt.args)
+			resp := c.InvokeContract(tt.method)
+			if (resp.Status == sdk.OK) != (len(tt.want) == 0) || resp.Message != tt.want {
+				t.Errorf("%s() = %v, want %s", tt.method, resp, tt.want)
+			}
+		})
+	}
+
+	// the recall is propagated to the derived batches only
+	recalled := map[string]bool{"b1": true, "b1-1": true, "b1-2": true, "m1": true, "b2": false}
+	for id, want := range recalled {
+		batch, err := getBatchById(id)
+		if err != nil || batch.Recalled != want {
+			t.Errorf("getBatch(%s) = %+v, %v, want recalled %v", id, batch, err, want)
+		}
+	}
+	batch, _ := getBatchById("m1")
+	if batch.Custodian != carrier || len(batch.Custody) != 2 || batch.Quantity != 50 ||
+		len(batch.Attachments) != 1 || !reflect.DeepEqual(batch.Parents, []string{"b1-2", "b2"}) {
+		t.Errorf("getBatch(m1) = %+v", batch)
+	}
+}
+
+func TestTraceContract_provenance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	args := map[string][]byte{}
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return args })
+	mockInstance.EXPECT().Sender().AnyTimes().Return(farmer, nil)
+	mockInstance.EXPECT().Origin().AnyTimes().Return(farmer, nil)
+	mockInstance.EXPECT().GetTxTimeStamp().AnyTimes().Return("1700000000", nil)
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockContracts(mockInstance)
+	sdk.Instance = mockInstance
+
+	c := &TraceContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	steps := []struct {
+		method string
+		args   map[string]string
+	}{
+		{method: "newBatch", args: map[string]string{batchIdArgKey: "b1", nameArgKey: "apple", quantityArgKey: "10"}},
+		{method: "newBatch", args: map[string]string{batchIdArgKey: "b2", nameArgKey: "apple", quantityArgKey: "10"}},
+		{method: "mergeBatches", args: map[string]string{batchIdArgKey: "m1", batchIdsArgKey: `["b1","b2"]`}},
+		{method: "splitBatch", args: map[string]string{batchIdArgKey: "m1",
+			splitsArgKey: `[{"batchId":"s1","quantity":5},{"batchId":"s2","quantity":15}]`}},
+	}
+	for _, step := range steps {
+		args = toArgs(step.args)
+		if resp := c.InvokeContract(step.method); resp.Status != sdk.OK {
+			t.Fatalf("%s() = %v", step.method, resp)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		offset    string
+		limit     string
+		total     int
+		batchIds  []string
+		relations []string
+		histories []int
+	}{
+		{name: "all", total: 5, batchIds: []string{"b2", "b1", "m1", "s1", "s2"},
+			relations: []string{relationAncestor, relationAncestor, relationSelf, relationDescendant,
+				relationDescendant},
+			histories: []int{2, 2, 2, 1, 1}},
+		{name: "page", offset: "1", limit: "2", total: 5, batchIds: []string{"b1", "m1"},
+			relations: []string{relationAncestor, relationSelf}, histories: []int{2, 2}},
+		{name: "out of range", offset: "5", total: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args = toArgs(map[string]string{batchIdArgKey: "m1", offsetArgKey: tt.offset, limitArgKey: tt.limit})
+			resp := c.InvokeContract("traceBatch")
+			if resp.Status != sdk.OK {
+				t.Fatalf("traceBatch() = %v", resp)
+			}
+			var provenance Provenance
+			if err := json.Unmarshal(resp.Payload, &provenance); err != nil {
+				t.Fatal(err)
+			}
+			var batchIds, relations []string
+			var histories []int
+			for _, node := range provenance.Nodes {
+				batchIds = append(batchIds, node.BatchId)
+				relations = append(relations, node.Relation)
+				histories = append(histories, len(node.History))
+			}
+			if provenance.Total != tt.total || !reflect.DeepEqual(batchIds, tt.batchIds) ||
+				!reflect.DeepEqual(relations, tt.relations) || !reflect.DeepEqual(histories, tt.histories) {
+				t.Errorf("traceBatch() = %d %v %v %v", provenance.Total, batchIds, relations, histories)
+			}
+		})
+	}
+}
@@ -319,11 +319,20 @@ type TraceData struct {
 
 // InitContract used to deploy and upgrade contract
 func (f *TraceContract) InitContract() protogo.Response {
+	if err := setConfig(); err != nil {
+		return sdk.Error(err.Error())
+	}
 	return sdk.Success([]byte("Init contract success"))
 }
 
-// UpgradeContract used to upgrade contract
+// UpgradeContract used to upgrade contract, the config is reset if any config arg is given
 func (f *TraceContract) UpgradeContract() protogo.Response {
+	args := sdk.Instance.GetArgs()
+	if len(args[identityArgKey]) > 0 || len(args[evidenceArgKey]) > 0 || len(args[roleLevelsArgKey]) > 0 {
+		if err := setConfig(); err != nil {
+			return sdk.Error(err.Error())
+		}
+	}
 	return sdk.Success([]byte("Upgrade contract success"))
 }
 
@@ -342,6 +351,22 @@ func (f *TraceContract) InvokeContract(method string) protogo.Response {
 		return f.getGoodsStatus()
 	case "traceGoods":
 		return f.getTraceInfo()
+	case "newBatch":
+		return f.newBatch()
+	case "splitBatch":
+		return f.splitBatch()
+	case "mergeBatches":
+		return f.mergeBatches()
+	case "handoffBatch":
+		return f.handoffBatch()
+	case "attachDocument":
+		return f.attachDocument()
+	case "recallBatch":
+		return f.recallBatch()
+	case "batchInfo":
+		return f.getBatch()
+	case "traceBatch":
+		return f.getProvenance()
 	default:
 		return sdk.Error("invalid method")
 	}
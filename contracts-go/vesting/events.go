@@ -0,0 +1,53 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	eventGrantCreated   = "GrantCreated"
+	eventTokensReleased = "TokensReleased"
+	eventGrantRevoked   = "GrantRevoked"
+)
+
+// vestingEventSchemas schemas of the events emitted by vesting, saved on chain when the contract is installed
+var vestingEventSchemas = []*sdk.EventSchema{
+	sdk.NewEventSchema(eventGrantCreated,
+		sdk.NewEventField("grantId", sdk.EventFieldUint, true),
+		sdk.NewEventField("grantor", sdk.EventFieldAddress, true),
+		sdk.NewEventField("beneficiary", sdk.EventFieldAddress, true),
+		sdk.NewEventField("kind", sdk.EventFieldString, false),
+		sdk.NewEventField("clock", sdk.EventFieldString, false),
+		sdk.NewEventField("amount", sdk.EventFieldUint256, false),
+		sdk.NewEventField("revocable", sdk.EventFieldBool, false),
+	),
+	sdk.NewEventSchema(eventTokensReleased,
+		sdk.NewEventField("grantId", sdk.EventFieldUint, true),
+		sdk.NewEventField("beneficiary", sdk.EventFieldAddress, true),
+		sdk.NewEventField("amount", sdk.EventFieldUint256, false),
+	),
+	sdk.NewEventSchema(eventGrantRevoked,
+		sdk.NewEventField("grantId", sdk.EventFieldUint, true),
+		sdk.NewEventField("grantor", sdk.EventFieldAddress, true),
+		sdk.NewEventField("vested", sdk.EventFieldUint256, false),
+		sdk.NewEventField("refund", sdk.EventFieldUint256, false),
+	),
+}
+
+func init() {
+	if err := sdk.RegisterEventSchemas(vestingEventSchemas...); err != nil {
+		panic(err)
+	}
+}
+
+// EventSchemas returns the event schemas of vesting
+func (c *VestingContract) EventSchemas() []*sdk.EventSchema {
+	return vestingEventSchemas
+}
@@ -0,0 +1,216 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sandbox"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+)
+
+const (
+	pToken       = "token"
+	pGrantId     = "grantId"
+	pBeneficiary = "beneficiary"
+	pKind        = "kind"
+	pClock       = "clock"
+	pAmount      = "amount"
+	pStart       = "start"
+	pCliff       = "cliff"
+	pEnd         = "end"
+	pMilestones  = "milestones"
+	pRevocable   = "revocable"
+)
+
+func main() {
+	err := sandbox.Start(new(VestingContract))
+	if err != nil {
+		sdk.Instance.Errorf(err.Error())
+	}
+}
+
+// InitContract install contract func
+func (c *VestingContract) InitContract() protogo.Response {
+	token := sdk.Instance.GetArgs()[pToken]
+	if len(token) == 0 {
+		return sdk.Error("VEST: require parameter:'token'")
+	}
+	if err := c.SetConfig(&Config{Token: string(token)}); err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte("Init contract success"))
+}
+
+// UpgradeContract upgrade contract func, the config is kept
+func (c *VestingContract) UpgradeContract() protogo.Response {
+	return sdk.Success([]byte("Upgrade contract success"))
+}
+
+// InvokeContract the entry func of invoke contract func
+func (c *VestingContract) InvokeContract(method string) protogo.Response {
+	if len(method) == 0 {
+		return sdk.Error("method of param should not be empty")
+	}
+	switch method {
+	case "CreateGrant":
+		grant, err := requireGrant()
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnUint64(c.CreateGrant(grant))
+	case "Release":
+		id, err := RequireUint64(pGrantId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnString(c.Release(id))
+	case "Revoke":
+		id, err := RequireUint64(pGrantId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnString(c.Revoke(id))
+	case "GetGrant":
+		id, err := RequireUint64(pGrantId)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		grant, err := c.GetGrant(id)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(grant)
+	case "GetGrants":
+		beneficiary, err := RequireString(pBeneficiary)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		grants, err := c.GetGrants(beneficiary)
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(grants)
+	case "GetConfig":
+		config, err := c.GetConfig()
+		if err != nil {
+			return sdk.Error(err.Error())
+		}
+		return ReturnJson(config)
+	default:
+		return sdk.Error("Invalid method")
+	}
+}
+
+// requireGrant read the grant from the args, the schedule fields are optional and checked by the kind
+func requireGrant() (*Grant, error) {
+	args := sdk.Instance.GetArgs()
+	grant := &Grant{}
+	var err error
+	if grant.Beneficiary, err = RequireString(pBeneficiary); err != nil {
+		return nil, err
+	}
+	if grant.Kind, err = RequireString(pKind); err != nil {
+		return nil, err
+	}
+	if grant.Amount, err = RequireString(pAmount); err != nil {
+		return nil, err
+	}
+	grant.Clock = ClockHeight
+	if len(args[pClock]) > 0 {
+		grant.Clock = string(args[pClock])
+	}
+	for key, value := range map[string]*uint64{pStart: &grant.Start, pCliff: &grant.Cliff, pEnd: &grant.End} {
+		if len(args[key]) == 0 {
+			continue
+		}
+		if *value, err = RequireUint64(key); err != nil {
+			return nil, err
+		}
+	}
+	if len(args[pMilestones]) > 0 {
+		if err = json.Unmarshal(args[pMilestones], &grant.Milestones); err != nil {
+			return nil, fmt.Errorf("VEST: parameter:'%s' should be a json array of {at, amount}", pMilestones)
+		}
+	}
+	if revocable := args[pRevocable]; len(revocable) > 0 {
+		if grant.Revocable, err = strconv.ParseBool(string(revocable)); err != nil {
+			return nil, errors.New("VEST: parameter:'revocable' should be true or false")
+		}
+	}
+	return grant, nil
+}
+
+////////////////////////////////Helper//////////////////////////////////
+
+// ReturnUint64 封装返回uint64类型为Response，如果有error则忽略num，封装error
+// @param num
+// @param err
+// @return Response
+func ReturnUint64(num uint64, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(strconv.FormatUint(num, 10)))
+}
+
+// ReturnString 封装返回string类型为Response，如果有error则忽略str，封装error
+// @param str
+// @param err
+// @return Response
+func ReturnString(str string, err error) protogo.Response {
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success([]byte(str))
+}
+
+// ReturnJson 封装返回interface类型为json string Response
+// @param data
+// @return Response
+func ReturnJson(data interface{}) protogo.Response {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return sdk.Error(err.Error())
+	}
+	return sdk.Success(bz)
+}
+
+// RequireString 必须要有参数 string类型
+// @param key
+// @return string
+// @return error
+func RequireString(key string) (string, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok || len(b) == 0 {
+		return "", fmt.Errorf("VEST: require parameter:'%s'", key)
+	}
+	return string(b), nil
+}
+
+// RequireUint64 必须要有参数 uint64类型
+// @param key
+// @return uint64
+// @return error
+func RequireUint64(key string) (uint64, error) {
+	args := sdk.Instance.GetArgs()
+	b, ok := args[key]
+	if !ok {
+		return 0, fmt.Errorf("VEST: require parameter:'%s'", key)
+	}
+	num, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("VEST: parameter:'%s' not a valid uint64", key)
+	}
+	return num, nil
+}
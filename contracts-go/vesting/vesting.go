@@ -0,0 +1,464 @@
+/*
+ Copyright (C) BABEC. All rights reserved.
+ Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Vesting contract, locks the balances of a standard-dfa token under release schedules. The grantor approves
+the vesting contract on the token and creates a grant for a beneficiary, the tokens are taken into the custody
+of the contract by TransferFrom of the token.
+
+Schedules are measured on the block height or the tx timestamp in seconds:
+linear vests the amount evenly from start to end, nothing is vested before the cliff;
+cliff vests the whole amount at the cliff;
+milestone vests the amount of each milestone when it is reached.
+
+The vested and unreleased tokens are released to the beneficiary at any time. A revocable grant can be
+revoked by the grantor, the unvested tokens are returned to the grantor and the vested tokens stay releasable.
+*/
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"chainmaker.org/chainmaker/contract-utils/address"
+	"chainmaker.org/chainmaker/contract-utils/safemath"
+)
+
+const (
+	//db key
+	configKey     = "config"
+	grantCountKey = "grantCount"
+	grantKey      = "grant"
+	// grantsKey, field beneficiary => ids of the grants of the beneficiary
+	grantsKey = "grants"
+
+	// maxMilestones bounds the milestones of a grant
+	maxMilestones = 100
+	// maxGrantsPerBeneficiary bounds the grant ids kept for a beneficiary
+	maxGrantsPerBeneficiary = 200
+)
+
+// schedule kinds
+const (
+	KindLinear    = "linear"
+	KindCliff     = "cliff"
+	KindMilestone = "milestone"
+)
+
+// schedule clocks
+const (
+	ClockHeight    = "height"
+	ClockTimestamp = "timestamp"
+)
+
+// VestingContract vesting contract
+type VestingContract struct {
+}
+
+// Config vesting config, set when the contract is installed
+type Config struct {
+	Token string `json:"token"`
+}
+
+// Milestone amount vested when the clock reaches At
+type Milestone struct {
+	At     uint64 `json:"at"`
+	Amount string `json:"amount"`
+}
+
+// Grant tokens of the grantor locked for the beneficiary. VestedAtRevoke is the vested amount when the
+// grant was revoked, the vested amount does not grow after that
+type Grant struct {
+	Id             uint64       `json:"id"`
+	Grantor        string       `json:"grantor"`
+	Beneficiary    string       `json:"beneficiary"`
+	Kind           string       `json:"kind"`
+	Clock          string       `json:"clock"`
+	Amount         string       `json:"amount"`
+	Start          uint64       `json:"start,omitempty"`
+	Cliff          uint64       `json:"cliff,omitempty"`
+	End            uint64       `json:"end,omitempty"`
+	Milestones     []*Milestone `json:"milestones,omitempty"`
+	Revocable      bool         `json:"revocable"`
+	Revoked        bool         `json:"revoked"`
+	VestedAtRevoke string       `json:"vestedAtRevoke,omitempty"`
+	Released       string       `json:"released"`
+}
+
+// GrantState grant with the vested and releasable amounts at the current clock
+type GrantState struct {
+	*Grant
+	Now        uint64 `json:"now"`
+	Vested     string `json:"vested"`
+	Releasable string `json:"releasable"`
+}
+
+// CreateGrant 创建锁仓，grantor需先在token合约Approve本合约，amount数量的token转入本合约托管
+// @param grant 锁仓计划，Id、Grantor、Revoked、Released由合约设置
+// @return uint64 锁仓ID
+// @return error
+func (c *VestingContract) CreateGrant(grant *Grant) (uint64, error) {
+	grantor, err := sdk.Instance.Sender()
+	if err != nil {
+		return 0, fmt.Errorf("get sender failed, err:%s", err)
+	}
+	if !address.IsValidAddress(grant.Beneficiary) || address.IsZeroAddress(grant.Beneficiary) {
+		return 0, errors.New("VEST: invalid beneficiary")
+	}
+	amount, ok := safemath.ParseSafeUint256(grant.Amount)
+	if !ok || amount.ToString() == "0" {
+		return 0, errors.New("VEST: amount should be a positive uint256")
+	}
+	grant.Amount = amount.ToString()
+	if err = checkSchedule(grant); err != nil {
+		return 0, err
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	grants, err := c.GetGrants(grant.Beneficiary)
+	if err != nil {
+		return 0, err
+	}
+	if len(grants) >= maxGrantsPerBeneficiary {
+		return 0, fmt.Errorf("VEST: beneficiary has %d grants", maxGrantsPerBeneficiary)
+	}
+
+	countStr, err := sdk.Instance.GetState(grantCountKey, "")
+	if err != nil {
+		return 0, err
+	}
+	var count uint64
+	if len(countStr) > 0 {
+		if count, err = strconv.ParseUint(countStr, 10, 64); err != nil {
+			return 0, err
+		}
+	}
+	grant.Id = count + 1
+	grant.Grantor = grantor
+	grant.Revoked = false
+	grant.VestedAtRevoke = ""
+	grant.Released = "0"
+	if err = c.setGrant(grant); err != nil {
+		return 0, err
+	}
+	if err = sdk.Instance.PutState(grantCountKey, "", strconv.FormatUint(grant.Id, 10)); err != nil {
+		return 0, err
+	}
+	if err = c.setGrants(grant.Beneficiary, append(grants, grant.Id)); err != nil {
+		return 0, err
+	}
+	if err = sdk.EmitTypedEvent(eventGrantCreated, grant.Id, grantor, grant.Beneficiary, grant.Kind, grant.Clock,
+		grant.Amount, grant.Revocable); err != nil {
+		return 0, err
+	}
+
+	self, err := sdk.Instance.GetContractAddr()
+	if err != nil {
+		return 0, fmt.Errorf("get contract address failed, err:%s", err)
+	}
+	if err = c.callToken(config, "TransferFrom", map[string][]byte{
+		"from":   []byte(grantor),
+		"to":     []byte(self),
+		"amount": []byte(grant.Amount),
+	}); err != nil {
+		return 0, err
+	}
+	return grant.Id, nil
+}
+
+// Release 将已解锁未领取的token转给受益人，任何人可调用
+// @param id
+// @return string 领取数量
+// @return error
+func (c *VestingContract) Release(id uint64) (string, error) {
+	state, err := c.GetGrant(id)
+	if err != nil {
+		return "", err
+	}
+	releasable := toBig(state.Releasable)
+	if releasable.Sign() == 0 {
+		return "", fmt.Errorf("VEST: grant %d has nothing to release", id)
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return "", err
+	}
+
+	state.Released = new(big.Int).Add(toBig(state.Released), releasable).String()
+	if err = c.setGrant(state.Grant); err != nil {
+		return "", err
+	}
+	if err = sdk.EmitTypedEvent(eventTokensReleased, id, state.Beneficiary, releasable); err != nil {
+		return "", err
+	}
+	if err = c.callToken(config, "Transfer", map[string][]byte{
+		"to":     []byte(state.Beneficiary),
+		"amount": []byte(releasable.String()),
+	}); err != nil {
+		return "", err
+	}
+	return releasable.String(), nil
+}
+
+// Revoke grantor撤销可撤销的锁仓，未解锁的token退回grantor，已解锁的仍可由受益人领取
+// @param id
+// @return string 退回数量
+// @return error
+func (c *VestingContract) Revoke(id uint64) (string, error) {
+	sender, err := sdk.Instance.Sender()
+	if err != nil {
+		return "", fmt.Errorf("get sender failed, err:%s", err)
+	}
+	state, err := c.GetGrant(id)
+	if err != nil {
+		return "", err
+	}
+	if state.Grantor != sender {
+		return "", errors.New("VEST: only the grantor can revoke the grant")
+	}
+	if !state.Revocable {
+		return "", fmt.Errorf("VEST: grant %d is not revocable", id)
+	}
+	if state.Revoked {
+		return "", fmt.Errorf("VEST: grant %d is already revoked", id)
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return "", err
+	}
+
+	refund := new(big.Int).Sub(toBig(state.Amount), toBig(state.Vested))
+	state.Revoked = true
+	state.VestedAtRevoke = state.Vested
+	if err = c.setGrant(state.Grant); err != nil {
+		return "", err
+	}
+	if err = sdk.EmitTypedEvent(eventGrantRevoked, id, sender, state.Vested, refund); err != nil {
+		return "", err
+	}
+	if refund.Sign() > 0 {
+		if err = c.callToken(config, "Transfer", map[string][]byte{
+			"to":     []byte(sender),
+			"amount": []byte(refund.String()),
+		}); err != nil {
+			return "", err
+		}
+	}
+	return refund.String(), nil
+}
+
+// GetGrant 查询锁仓及当前已解锁和可领取数量
+// @param id
+// @return *GrantState
+// @return error
+func (c *VestingContract) GetGrant(id uint64) (*GrantState, error) {
+	data, err := sdk.Instance.GetStateByte(grantKey, strconv.FormatUint(id, 10))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("VEST: grant %d not found", id)
+	}
+	var grant Grant
+	if err = json.Unmarshal(data, &grant); err != nil {
+		return nil, err
+	}
+	now, err := clockNow(grant.Clock)
+	if err != nil {
+		return nil, err
+	}
+	vested := vestedAmount(&grant, now)
+	releasable := new(big.Int).Sub(vested, toBig(grant.Released))
+	return &GrantState{Grant: &grant, Now: now, Vested: vested.String(), Releasable: releasable.String()}, nil
+}
+
+// GetGrants 查询受益人的锁仓ID
+// @param beneficiary
+// @return []uint64
+// @return error
+func (c *VestingContract) GetGrants(beneficiary string) ([]uint64, error) {
+	data, err := sdk.Instance.GetStateByte(grantsKey, beneficiary)
+	if err != nil {
+		return nil, err
+	}
+	grants := make([]uint64, 0)
+	if len(data) == 0 {
+		return grants, nil
+	}
+	if err = json.Unmarshal(data, &grants); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// GetConfig 获取配置
+// @return *Config
+// @return error
+func (c *VestingContract) GetConfig() (*Config, error) {
+	data, err := sdk.Instance.GetStateByte(configKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("VEST: config not found")
+	}
+	var config Config
+	if err = json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SetConfig 设置配置
+// @param config
+// @return error
+func (c *VestingContract) SetConfig(config *Config) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(configKey, "", data)
+}
+
+// callToken call the token and fail the tx if the call fails
+func (c *VestingContract) callToken(config *Config, method string, args map[string][]byte) error {
+	resp := sdk.Instance.CallContract(config.Token, method, args)
+	if resp.Status != sdk.OK {
+		return fmt.Errorf("VEST: call %s of token %s failed, %s", method, config.Token, resp.Message)
+	}
+	return nil
+}
+
+func (c *VestingContract) setGrant(grant *Grant) error {
+	data, err := json.Marshal(grant)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(grantKey, strconv.FormatUint(grant.Id, 10), data)
+}
+
+func (c *VestingContract) setGrants(beneficiary string, grants []uint64) error {
+	data, err := json.Marshal(grants)
+	if err != nil {
+		return err
+	}
+	return sdk.Instance.PutStateByte(grantsKey, beneficiary, data)
+}
+
+// checkSchedule check the schedule fields of the kind, and clear the fields not used by the kind
+func checkSchedule(grant *Grant) error {
+	if grant.Clock != ClockHeight && grant.Clock != ClockTimestamp {
+		return fmt.Errorf("VEST: clock should be %s or %s", ClockHeight, ClockTimestamp)
+	}
+	switch grant.Kind {
+	case KindLinear:
+		if grant.End <= grant.Start {
+			return errors.New("VEST: end should be greater than start")
+		}
+		if grant.Cliff < grant.Start || grant.Cliff > grant.End {
+			return errors.New("VEST: cliff should be between start and end")
+		}
+		grant.Milestones = nil
+	case KindCliff:
+		if grant.Cliff == 0 {
+			return errors.New("VEST: cliff should be positive")
+		}
+		grant.Start, grant.End, grant.Milestones = 0, 0, nil
+	case KindMilestone:
+		if len(grant.Milestones) == 0 || len(grant.Milestones) > maxMilestones {
+			return fmt.Errorf("VEST: milestone grant should have 1 to %d milestones", maxMilestones)
+		}
+		sum := new(big.Int)
+		for i, milestone := range grant.Milestones {
+			if i > 0 && milestone.At <= grant.Milestones[i-1].At {
+				return errors.New("VEST: milestones should be in ascending order")
+			}
+			amount, ok := safemath.ParseSafeUint256(milestone.Amount)
+			if !ok {
+				return fmt.Errorf("VEST: invalid amount of milestone %d", i)
+			}
+			milestone.Amount = amount.ToString()
+			sum.Add(sum, toBig(milestone.Amount))
+		}
+		if sum.Cmp(toBig(grant.Amount)) != 0 {
+			return errors.New("VEST: the milestone amounts should sum to the amount")
+		}
+		grant.Start, grant.Cliff, grant.End = 0, 0, 0
+	default:
+		return fmt.Errorf("VEST: kind should be %s, %s or %s", KindLinear, KindCliff, KindMilestone)
+	}
+	return nil
+}
+
+// vestedAmount the amount vested at now, fixed when the grant was revoked
+func vestedAmount(grant *Grant, now uint64) *big.Int {
+	if grant.Revoked {
+		return toBig(grant.VestedAtRevoke)
+	}
+	amount := toBig(grant.Amount)
+	switch grant.Kind {
+	case KindLinear:
+		if now < grant.Cliff || now <= grant.Start {
+			return new(big.Int)
+		}
+		if now >= grant.End {
+			return amount
+		}
+		vested := amount.Mul(amount, new(big.Int).SetUint64(now-grant.Start))
+		return vested.Div(vested, new(big.Int).SetUint64(grant.End-grant.Start))
+	case KindCliff:
+		if now < grant.Cliff {
+			return new(big.Int)
+		}
+		return amount
+	case KindMilestone:
+		vested := new(big.Int)
+		for _, milestone := range grant.Milestones {
+			if now < milestone.At {
+				break
+			}
+			vested.Add(vested, toBig(milestone.Amount))
+		}
+		return vested
+	default:
+		return new(big.Int)
+	}
+}
+
+// clockNow the current block height or tx timestamp in seconds
+func clockNow(clock string) (uint64, error) {
+	if clock == ClockTimestamp {
+		timestamp, err := sdk.Instance.GetTxTimeStamp()
+		if err != nil {
+			return 0, fmt.Errorf("get tx timestamp failed, err:%s", err)
+		}
+		now, err := strconv.ParseUint(timestamp, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid tx timestamp %s", timestamp)
+		}
+		return now, nil
+	}
+	height, err := sdk.Instance.GetBlockHeight()
+	if err != nil {
+		return 0, fmt.Errorf("get block height failed, err:%s", err)
+	}
+	return uint64(height), nil
+}
+
+func toBig(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return n
+}
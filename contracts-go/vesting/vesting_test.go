@@ -0,0 +1,247 @@
+/*
+  Copyright (C) BABEC. All rights reserved.
+  Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+  SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/pb/protogo"
+	"chainmaker.org/chainmaker/contract-sdk-go/v2/sdk"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	employer = "a04f7895de24f61807a729be230f03da8c0eef42"
+	employee = "b04f7895de24f61807a729be230f03da8c0eef42"
+	self     = "c04f7895de24f61807a729be230f03da8c0eef42"
+	token    = "cmdfa"
+)
+
+// mockGetPutState keeps the state of a test in the returned map by key#field
+func mockGetPutState(mockInstance *sdk.MockSDKInterface) map[string][]byte {
+	stateMap := make(map[string][]byte)
+	mockInstance.EXPECT().GetState(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) (string, error) {
+			return string(stateMap[key+"#"+field]), nil
+		})
+	mockInstance.EXPECT().PutState(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field, value string) error {
+			stateMap[key+"#"+field] = []byte(value)
+			return nil
+		})
+	mockInstance.EXPECT().GetStateByte(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string) ([]byte, error) {
+			return stateMap[key+"#"+field], nil
+		})
+	mockInstance.EXPECT().PutStateByte(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(key, field string, value []byte) error {
+			stateMap[key+"#"+field] = value
+			return nil
+		})
+	return stateMap
+}
+
+// mockToken the token contract moves the balances, Transfer is called by the vesting contract
+func mockToken(mockInstance *sdk.MockSDKInterface, balances map[string]int64) {
+	mockInstance.EXPECT().CallContract(token, gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(contractName, method string, callArgs map[string][]byte) protogo.Response {
+			from := self
+			if method == "TransferFrom" {
+				from = string(callArgs["from"])
+			}
+			amount, _ := strconv.ParseInt(string(callArgs["amount"]), 10, 64)
+			if balances[from] < amount {
+				return sdk.Error("insufficient balance")
+			}
+			balances[from] -= amount
+			balances[string(callArgs["to"])] += amount
+			return sdk.SuccessResponse
+		})
+}
+
+func TestVestingContract_InvokeContract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	var args map[string][]byte
+	mockInstance.EXPECT().GetArgs().AnyTimes().DoAndReturn(func() map[string][]byte { return args })
+	mockInstance.EXPECT().Sender().AnyTimes().Return(employer, nil)
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().Return(1, nil)
+	mockGetPutState(mockInstance)
+	sdk.Instance = mockInstance
+
+	tests := []struct {
+		name   string
+		method string
+		args   map[string][]byte
+		want   protogo.Response
+	}{
+		{name: "create", method: "CreateGrant", want: sdk.Error("VEST: require parameter:'beneficiary'")},
+		{name: "invalid kind", method: "CreateGrant", args: map[string][]byte{pBeneficiary: []byte(employee),
+			pKind: []byte("weekly"), pAmount: []byte("10")},
+			want: sdk.Error("VEST: kind should be linear, cliff or milestone")},
+		{name: "invalid clock", method: "CreateGrant", args: map[string][]byte{pBeneficiary: []byte(employee),
+			pKind: []byte(KindCliff), pAmount: []byte("10"), pClock: []byte("epoch")},
+			want: sdk.Error("VEST: clock should be height or timestamp")},
+		{name: "invalid milestones", method: "CreateGrant", args: map[string][]byte{pBeneficiary: []byte(employee),
+			pKind: []byte(KindMilestone), pAmount: []byte("10"),
+			pMilestones: []byte(`[{"at":1,"amount":"4"},{"at":2,"amount":"5"}]`)},
+			want: sdk.Error("VEST: the milestone amounts should sum to the amount")},
+		{name: "release", method: "Release", want: sdk.Error("VEST: require parameter:'grantId'")},
+		{name: "grant not found", method: "GetGrant", args: map[string][]byte{pGrantId: []byte("1")},
+			want: sdk.Error("VEST: grant 1 not found")},
+		{name: "grants", method: "GetGrants", args: map[string][]byte{pBeneficiary: []byte(employee)},
+			want: sdk.Success([]byte("[]"))},
+		{name: "invalid", method: "invalid", want: sdk.Error("Invalid method")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args = tt.args
+			c := &VestingContract{}
+			if got := c.InvokeContract(tt.method); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvokeContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVestingContract_linear(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	sender, height := employer, 1
+	balances := map[string]int64{employer: 1000}
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(map[string][]byte{pToken: []byte(token)})
+	mockInstance.EXPECT().Sender().AnyTimes().DoAndReturn(func() (string, error) { return sender, nil })
+	mockInstance.EXPECT().GetContractAddr().AnyTimes().Return(self, nil)
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return height, nil })
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockToken(mockInstance, balances)
+	sdk.Instance = mockInstance
+
+	c := &VestingContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	const id = uint64(1)
+	release := func(c *VestingContract) (string, error) { return c.Release(id) }
+	revoke := func(c *VestingContract) (string, error) { return c.Revoke(id) }
+	steps := []struct {
+		name    string
+		height  int
+		sender  string
+		call    func(c *VestingContract) (string, error)
+		want    string
+		wantErr bool
+	}{
+		{name: "create", height: 1, sender: employer, want: "1", call: func(c *VestingContract) (string, error) {
+			id, err := c.CreateGrant(&Grant{Beneficiary: employee, Kind: KindLinear, Clock: ClockHeight,
+				Amount: "1000", Start: 10, Cliff: 20, End: 110, Revocable: true})
+			return strconv.FormatUint(id, 10), err
+		}},
+		{name: "escrowed", height: 1, sender: employer, want: "0/1000", call: func(*VestingContract) (string, error) {
+			return strconv.FormatInt(balances[employer], 10) + "/" + strconv.FormatInt(balances[self], 10), nil
+		}},
+		{name: "release before the cliff", height: 15, sender: employee, call: release, wantErr: true},
+		{name: "release at the cliff", height: 20, sender: employee, call: release, want: "100"},
+		{name: "vested", height: 60, sender: employee, want: "500/400/100",
+			call: func(c *VestingContract) (string, error) {
+				state, err := c.GetGrant(id)
+				if err != nil {
+					return "", err
+				}
+				return state.Vested + "/" + state.Releasable + "/" + state.Released, nil
+			}},
+		{name: "revoke by the beneficiary", height: 60, sender: employee, call: revoke, wantErr: true},
+		{name: "revoke", height: 60, sender: employer, call: revoke, want: "500"},
+		{name: "revoke twice", height: 60, sender: employer, call: revoke, wantErr: true},
+		// the vested tokens stay releasable after the grant is revoked
+		{name: "release after revoke", height: 110, sender: employee, call: release, want: "400"},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			sender, height = s.sender, s.height
+			got, err := s.call(c)
+			if (err != nil) != s.wantErr || (err == nil && got != s.want) {
+				t.Errorf("got %v, %v, want %v, wantErr %v", got, err, s.want, s.wantErr)
+			}
+		})
+	}
+	want := map[string]int64{employer: 500, employee: 500, self: 0}
+	if !reflect.DeepEqual(balances, want) {
+		t.Errorf("balances = %v, want %v", balances, want)
+	}
+	if grants, _ := c.GetGrants(employee); !reflect.DeepEqual(grants, []uint64{1}) {
+		t.Errorf("GetGrants() = %v", grants)
+	}
+}
+
+func TestVestingContract_schedules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockInstance := sdk.NewMockSDKInterface(ctrl)
+
+	height, timestamp := 1, int64(1)
+	mockInstance.EXPECT().GetArgs().AnyTimes().Return(map[string][]byte{pToken: []byte(token)})
+	mockInstance.EXPECT().Sender().AnyTimes().Return(employer, nil)
+	mockInstance.EXPECT().GetContractAddr().AnyTimes().Return(self, nil)
+	mockInstance.EXPECT().GetBlockHeight().AnyTimes().DoAndReturn(func() (int, error) { return height, nil })
+	mockInstance.EXPECT().GetTxTimeStamp().AnyTimes().DoAndReturn(func() (string, error) {
+		return strconv.FormatInt(timestamp, 10), nil
+	})
+	mockInstance.EXPECT().EmitEvent(gomock.Any(), gomock.Any()).AnyTimes()
+	mockGetPutState(mockInstance)
+	mockToken(mockInstance, map[string]int64{employer: 2000})
+	sdk.Instance = mockInstance
+
+	c := &VestingContract{}
+	if resp := c.InitContract(); resp.Status != sdk.OK {
+		t.Fatalf("InitContract() = %v", resp)
+	}
+	cliff, err := c.CreateGrant(&Grant{Beneficiary: employee, Kind: KindCliff, Clock: ClockTimestamp,
+		Amount: "1000", Cliff: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	milestone, err := c.CreateGrant(&Grant{Beneficiary: employee, Kind: KindMilestone, Clock: ClockHeight,
+		Amount: "1000", Milestones: []*Milestone{{At: 10, Amount: "300"}, {At: 20, Amount: "700"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Revoke(cliff); err == nil {
+		t.Errorf("Revoke() of irrevocable grant should fail")
+	}
+
+	tests := []struct {
+		height    int
+		timestamp int64
+		id        uint64
+		want      string
+	}{
+		{height: 9, timestamp: 999, id: cliff, want: "0"},
+		{height: 9, timestamp: 999, id: milestone, want: "0"},
+		{height: 15, timestamp: 1000, id: cliff, want: "1000"},
+		{height: 15, timestamp: 1000, id: milestone, want: "300"},
+		{height: 20, timestamp: 2000, id: milestone, want: "1000"},
+	}
+	for _, tt := range tests {
+		height, timestamp = tt.height, tt.timestamp
+		if state, _ := c.GetGrant(tt.id); state.Vested != tt.want {
+			t.Errorf("vested of grant %d at %d/%d = %s, want %s", tt.id, tt.height, tt.timestamp, state.Vested,
+				tt.want)
+		}
+	}
+	if released, err := c.Release(milestone); err != nil || released != "1000" {
+		t.Errorf("Release() = %v, %v", released, err)
+	}
+}
@@ -0,0 +1,651 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/config"
+	"chainmaker.org/chainmaker/pb-go/v2/store"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+)
+
+const (
+	// ArchiveTypeFile 归档到本地目录
+	ArchiveTypeFile = "file"
+	// ArchiveTypeS3 归档到S3兼容的对象存储
+	ArchiveTypeS3 = "s3"
+
+	// DefaultArchiveSegmentSize 每个segment文件包含的区块数
+	DefaultArchiveSegmentSize = 64
+
+	archiveManifestVersion = "1"
+	archiveManifestKey     = "manifest.json"
+	archiveSegmentDir      = "segments"
+	archiveIndexDir        = "indexes"
+)
+
+// ArchiveService 归档服务
+var _ ArchiveService = (*ArchiveFileClient)(nil)
+
+// ArchiveFileClient 归档服务的文件实现，区块按高度顺序写入不可变的segment文件，
+// segment和它的索引都以内容的sha256为文件名，manifest记录已归档的segment，是唯一会被覆盖的文件。
+// 不需要数据库和归档中心，适合小规模部署归档冷数据
+type ArchiveFileClient struct {
+	// chainId is the chain archived
+	chainId string
+	// store holds the manifest, segments and indexes
+	store ArchiveObjectStore
+	// secretKey is the key of manifest hmac, manifest is not authenticated if empty
+	secretKey string
+	// segmentSize is the max number of blocks in a segment
+	segmentSize int
+	log         utils.Logger
+
+	lock     sync.Mutex
+	manifest *archiveManifest
+	// indexes caches the loaded segment indexes by segment position
+	indexes map[int]*archiveSegmentIndex
+	// txHeights and hashHeights are built when the first tx or hash lookup happens
+	txHeights   map[string]uint64
+	hashHeights map[string]uint64
+	// lastSegment caches the last read segment
+	lastSegmentHash string
+	lastSegment     []byte
+}
+
+// archiveManifest 归档清单
+type archiveManifest struct {
+	Version        string                 `json:"version"`
+	ChainId        string                 `json:"chain_id"`
+	GenesisHash    string                 `json:"genesis_hash"`
+	ArchivedHeight uint64                 `json:"archived_height"`
+	Segments       []*archiveSegmentEntry `json:"segments"`
+	// Sum is hmac of the fields above when secret key is set
+	Sum string `json:"sum,omitempty"`
+}
+
+// archiveSegmentEntry segment在清单中的记录
+type archiveSegmentEntry struct {
+	StartHeight uint64 `json:"start_height"`
+	EndHeight   uint64 `json:"end_height"`
+	Index       string `json:"index"`
+}
+
+// archiveSegmentIndex segment的索引
+type archiveSegmentIndex struct {
+	Segment string               `json:"segment"`
+	Blocks  []*archiveBlockEntry `json:"blocks"`
+}
+
+// archiveBlockEntry 区块在segment中的位置
+type archiveBlockEntry struct {
+	Height uint64   `json:"height"`
+	Hash   string   `json:"hash"`
+	Offset uint64   `json:"offset"`
+	Length uint64   `json:"length"`
+	TxIds  []string `json:"tx_ids"`
+}
+
+// NewArchiveFileClient 根据归档配置创建ArchiveFileClient，
+// file类型的dest为本地目录，s3类型的dest格式为 http(s)://access_key:secret_key@host:port/bucket/prefix?region=
+// @param chainId
+// @param config
+// @param log
+// @return *ArchiveFileClient
+// @return error
+func NewArchiveFileClient(chainId string, config *ArchiveConfig, log utils.Logger) (*ArchiveFileClient, error) {
+	var (
+		objectStore ArchiveObjectStore
+		err         error
+	)
+	switch config.archiveType {
+	case ArchiveTypeFile:
+		objectStore, err = NewLocalObjectStore(config.Dest)
+	case ArchiveTypeS3:
+		objectStore, err = NewS3ObjectStore(config.Dest)
+	default:
+		err = fmt.Errorf("archive type %s is not a file archive type", config.archiveType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewArchiveFileClient2(chainId, objectStore, config.secretKey, DefaultArchiveSegmentSize, log)
+}
+
+// isFileArchive 归档配置是否为文件或S3归档
+func isFileArchive(config *ArchiveConfig) bool {
+	return config != nil && (config.archiveType == ArchiveTypeFile || config.archiveType == ArchiveTypeS3)
+}
+
+// NewArchiveFileClient2 根据对象存储创建ArchiveFileClient
+// @param chainId
+// @param objectStore
+// @param secretKey
+// @param segmentSize
+// @param log
+// @return *ArchiveFileClient
+// @return error
+func NewArchiveFileClient2(chainId string, objectStore ArchiveObjectStore, secretKey string, segmentSize int,
+	log utils.Logger) (*ArchiveFileClient, error) {
+	if segmentSize <= 0 {
+		segmentSize = DefaultArchiveSegmentSize
+	}
+	a := &ArchiveFileClient{
+		chainId:     chainId,
+		store:       objectStore,
+		secretKey:   secretKey,
+		segmentSize: segmentSize,
+		log:         log,
+		indexes:     make(map[int]*archiveSegmentIndex),
+	}
+	manifest, err := a.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+	a.manifest = manifest
+	return a, nil
+}
+
+// loadManifest returns nil if the chain is not registered
+func (a *ArchiveFileClient) loadManifest() (*archiveManifest, error) {
+	data, err := a.store.Get(a.key(archiveManifestKey))
+	if err == ErrArchiveObjectNotExist {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("load archive manifest failed, %s", err)
+	}
+	manifest := &archiveManifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("unmarshal archive manifest failed, %s", err)
+	}
+	if manifest.Version != archiveManifestVersion {
+		return nil, fmt.Errorf("unsupported archive manifest version %s", manifest.Version)
+	}
+	if manifest.ChainId != a.chainId {
+		return nil, fmt.Errorf("archive manifest of chain %s, want %s", manifest.ChainId, a.chainId)
+	}
+	sum, err := a.manifestSum(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if sum != manifest.Sum {
+		return nil, errors.New("archive manifest sum mismatch, the manifest was modified or secret key is wrong")
+	}
+	return manifest, nil
+}
+
+func (a *ArchiveFileClient) manifestSum(manifest *archiveManifest) (string, error) {
+	if a.secretKey == "" {
+		return "", nil
+	}
+	unsigned := *manifest
+	unsigned.Sum = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+	return hmac(manifest.ChainId, manifest.ArchivedHeight, data, a.secretKey)
+}
+
+func (a *ArchiveFileClient) saveManifest(manifest *archiveManifest) error {
+	sum, err := a.manifestSum(manifest)
+	if err != nil {
+		return err
+	}
+	manifest.Sum = sum
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = a.store.Put(a.key(archiveManifestKey), data); err != nil {
+		return fmt.Errorf("save archive manifest failed, %s", err)
+	}
+	return nil
+}
+
+func (a *ArchiveFileClient) key(name string) string {
+	return a.chainId + "/" + name
+}
+
+// putContent puts data with the sha256 of data as name
+func (a *ArchiveFileClient) putContent(dir string, data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:])
+	if err := a.store.Put(a.key(dir+"/"+name), data); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// getContent gets data of name and checks the sha256
+func (a *ArchiveFileClient) getContent(dir, name string) ([]byte, error) {
+	data, err := a.store.Get(a.key(dir + "/" + name))
+	if err != nil {
+		return nil, fmt.Errorf("get %s/%s failed, %s", dir, name, err)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != name {
+		return nil, fmt.Errorf("%s/%s is corrupted", dir, name)
+	}
+	return data, nil
+}
+
+// GetTxByTxId get transaction by txId
+// @param txId
+// @return *common.TransactionInfo
+// @return error
+func (a *ArchiveFileClient) GetTxByTxId(txId string) (*common.TransactionInfo, error) {
+	txInfo, err := a.GetTxWithRWSetByTxId(txId)
+	if err != nil {
+		return nil, err
+	}
+	return &common.TransactionInfo{
+		Transaction:    txInfo.Transaction,
+		BlockHeight:    txInfo.BlockHeight,
+		BlockHash:      txInfo.BlockHash,
+		TxIndex:        txInfo.TxIndex,
+		BlockTimestamp: txInfo.BlockTimestamp,
+	}, nil
+}
+
+// GetTxWithRWSetByTxId get transaction with rwset by txId
+// @param txId
+// @return *common.TransactionInfoWithRWSet
+// @return error
+func (a *ArchiveFileClient) GetTxWithRWSetByTxId(txId string) (*common.TransactionInfoWithRWSet, error) {
+	bInfo, err := a.GetBlockByTxId(txId, true)
+	if err != nil {
+		return nil, err
+	}
+	for idx, tx := range bInfo.Block.Txs {
+		if tx.Payload.TxId == txId {
+			txInfo := &common.TransactionInfoWithRWSet{
+				Transaction:    tx,
+				BlockHeight:    bInfo.Block.Header.BlockHeight,
+				BlockHash:      bInfo.Block.Header.BlockHash,
+				TxIndex:        uint32(idx),
+				BlockTimestamp: bInfo.Block.Header.BlockTimestamp,
+			}
+			if idx < len(bInfo.RwsetList) {
+				txInfo.RwSet = bInfo.RwsetList[idx]
+			}
+			return txInfo, nil
+		}
+	}
+	return nil, errors.New("tx not found")
+}
+
+// GetBlockByHeight get block by height
+// @param height
+// @param withRWSet
+// @return *common.BlockInfo
+// @return error
+func (a *ArchiveFileClient) GetBlockByHeight(height uint64, withRWSet bool) (*common.BlockInfo, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.getBlockByHeight(height, withRWSet)
+}
+
+func (a *ArchiveFileClient) getBlockByHeight(height uint64, withRWSet bool) (*common.BlockInfo, error) {
+	if a.manifest == nil || height > a.manifest.ArchivedHeight {
+		return nil, fmt.Errorf("block %d is not archived", height)
+	}
+	position := sort.Search(len(a.manifest.Segments), func(i int) bool {
+		return a.manifest.Segments[i].EndHeight >= height
+	})
+	if position == len(a.manifest.Segments) || a.manifest.Segments[position].StartHeight > height {
+		return nil, fmt.Errorf("block %d is not archived", height)
+	}
+	index, err := a.segmentIndex(position)
+	if err != nil {
+		return nil, err
+	}
+	entry := index.Blocks[height-a.manifest.Segments[position].StartHeight]
+
+	if a.lastSegmentHash != index.Segment {
+		segment, err := a.getContent(archiveSegmentDir, index.Segment)
+		if err != nil {
+			return nil, err
+		}
+		a.lastSegmentHash, a.lastSegment = index.Segment, segment
+	}
+	if entry.Offset+entry.Length > uint64(len(a.lastSegment)) {
+		return nil, fmt.Errorf("block %d is out of segment %s", height, index.Segment)
+	}
+	var blkWithRWSet store.BlockWithRWSet
+	if err = blkWithRWSet.Unmarshal(a.lastSegment[entry.Offset : entry.Offset+entry.Length]); err != nil {
+		return nil, err
+	}
+	result := &common.BlockInfo{
+		Block: blkWithRWSet.Block,
+	}
+	if withRWSet {
+		result.RwsetList = blkWithRWSet.TxRWSets
+	}
+	return result, nil
+}
+
+func (a *ArchiveFileClient) segmentIndex(position int) (*archiveSegmentIndex, error) {
+	if index, ok := a.indexes[position]; ok {
+		return index, nil
+	}
+	segmentEntry := a.manifest.Segments[position]
+	data, err := a.getContent(archiveIndexDir, segmentEntry.Index)
+	if err != nil {
+		return nil, err
+	}
+	index := &archiveSegmentIndex{}
+	if err = json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("unmarshal segment index %s failed, %s", segmentEntry.Index, err)
+	}
+	if uint64(len(index.Blocks)) != segmentEntry.EndHeight-segmentEntry.StartHeight+1 {
+		return nil, fmt.Errorf("segment index %s does not match the manifest", segmentEntry.Index)
+	}
+	a.indexes[position] = index
+	return index, nil
+}
+
+// loadLookups builds the tx id and block hash lookups from all segment indexes
+func (a *ArchiveFileClient) loadLookups() error {
+	if a.txHeights != nil {
+		return nil
+	}
+	txHeights := make(map[string]uint64)
+	hashHeights := make(map[string]uint64)
+	if a.manifest != nil {
+		for position := range a.manifest.Segments {
+			index, err := a.segmentIndex(position)
+			if err != nil {
+				return err
+			}
+			for _, entry := range index.Blocks {
+				hashHeights[entry.Hash] = entry.Height
+				for _, txId := range entry.TxIds {
+					txHeights[txId] = entry.Height
+				}
+			}
+		}
+	}
+	a.txHeights, a.hashHeights = txHeights, hashHeights
+	return nil
+}
+
+// GetBlockByHash get block by hash
+// @param blockHash hex of block hash
+// @param withRWSet
+// @return *common.BlockInfo
+// @return error
+func (a *ArchiveFileClient) GetBlockByHash(blockHash string, withRWSet bool) (*common.BlockInfo, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if err := a.loadLookups(); err != nil {
+		return nil, err
+	}
+	height, ok := a.hashHeights[blockHash]
+	if !ok {
+		return nil, fmt.Errorf("block %s is not archived", blockHash)
+	}
+	return a.getBlockByHeight(height, withRWSet)
+}
+
+// GetBlockByTxId get block by txId
+// @param txId
+// @param withRWSet
+// @return *common.BlockInfo
+// @return error
+func (a *ArchiveFileClient) GetBlockByTxId(txId string, withRWSet bool) (*common.BlockInfo, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if err := a.loadLookups(); err != nil {
+		return nil, err
+	}
+	height, ok := a.txHeights[txId]
+	if !ok {
+		return nil, fmt.Errorf("tx %s is not archived", txId)
+	}
+	return a.getBlockByHeight(height, withRWSet)
+}
+
+// GetChainConfigByBlockHeight get chain config by block height
+// @param blockHeight
+// @return *config.ChainConfig
+// @return error
+func (a *ArchiveFileClient) GetChainConfigByBlockHeight(blockHeight uint64) (*config.ChainConfig, error) {
+	offChainHeight, _, _, err := a.GetArchivedStatus()
+	if err != nil {
+		return nil, err
+	}
+	if blockHeight <= offChainHeight {
+		blkHeader, blkErr := a.GetBlockByHeight(blockHeight, false)
+		if blkErr != nil {
+			return nil, blkErr
+		}
+		if blkHeader.Block.Header.BlockType == common.BlockType_CONFIG_BLOCK {
+			return getChainConfig(blkHeader.Block.Txs[0])
+		}
+		configBlk, configBlkErr := a.GetBlockByHeight(blkHeader.Block.Header.PreConfHeight, true)
+		if configBlkErr != nil {
+			return nil, configBlkErr
+		}
+		return getChainConfig(configBlk.Block.Txs[0])
+	}
+	return nil, nil
+}
+
+// Register 注册链，归档创世区块
+// @param genesis
+// @return error
+func (a *ArchiveFileClient) Register(genesis *common.BlockInfo) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.manifest != nil {
+		if a.manifest.GenesisHash != hex.EncodeToString(genesis.Block.Header.BlockHash) {
+			return errors.New("chain is registered with another genesis block")
+		}
+		return nil
+	}
+	if genesis.Block.Header.BlockHeight != 0 {
+		return fmt.Errorf("block %d is not genesis block", genesis.Block.Header.BlockHeight)
+	}
+	return a.writeSegment([]*common.BlockInfo{genesis})
+}
+
+// ArchiveBlock 归档一个区块
+// @param block
+// @return error
+func (a *ArchiveFileClient) ArchiveBlock(block *common.BlockInfo) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.writeSegment([]*common.BlockInfo{block})
+}
+
+// ArchiveBlocks 归档多个区块，每segmentSize个区块写一个segment
+// @param bi
+// @param heightNoticeCallback
+// @return error
+func (a *ArchiveFileClient) ArchiveBlocks(bi BlockIterator, heightNoticeCallback func(ProcessMessage) error) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	notice := func(h uint64, e error) {
+		if heightNoticeCallback != nil {
+			heightNoticeCallback(ProcessMessage{
+				CurrentHeight: h,
+				Total:         bi.Total(),
+				Error:         e,
+			})
+		}
+	}
+	sendCount := 0
+	var pending []*common.BlockInfo
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		if err := a.writeSegment(pending); err != nil {
+			notice(pending[0].Block.Header.BlockHeight, err)
+			return fmt.Errorf("archive segment from height %d got error %s",
+				pending[0].Block.Header.BlockHeight, err.Error())
+		}
+		for _, block := range pending {
+			sendCount++
+			notice(block.Block.Header.BlockHeight, nil)
+		}
+		pending = pending[:0]
+		return nil
+	}
+	for bi.Next() {
+		block, err := bi.Value()
+		if err != nil {
+			// keep the blocks fetched before the error
+			if flushErr := flush(); flushErr != nil {
+				return flushErr
+			}
+			notice(bi.Current(), err)
+			return err
+		}
+		pending = append(pending, block)
+		if len(pending) >= a.segmentSize {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if sendCount == 0 {
+		return errors.New("no block to archive")
+	}
+	return nil
+}
+
+// writeSegment writes blocks as a segment, the blocks already archived are skipped
+func (a *ArchiveFileClient) writeSegment(blocks []*common.BlockInfo) error {
+	nextHeight := uint64(0)
+	if a.manifest != nil {
+		nextHeight = a.manifest.ArchivedHeight + 1
+	}
+	var (
+		segment []byte
+		index   = &archiveSegmentIndex{}
+	)
+	for _, block := range blocks {
+		header := block.Block.Header
+		if header.ChainId != a.chainId {
+			return fmt.Errorf("block of chain %s, want %s", header.ChainId, a.chainId)
+		}
+		if a.manifest != nil && header.BlockHeight < nextHeight && len(index.Blocks) == 0 {
+			// already archived
+			continue
+		}
+		if header.BlockHeight != nextHeight {
+			return fmt.Errorf("block %d is not continuous with archived height %d", header.BlockHeight,
+				nextHeight-1)
+		}
+		blkWithRWSet := &store.BlockWithRWSet{
+			Block:    block.Block,
+			TxRWSets: block.RwsetList,
+		}
+		blkWithRWSetBytes, err := blkWithRWSet.Marshal()
+		if err != nil {
+			return err
+		}
+		entry := &archiveBlockEntry{
+			Height: header.BlockHeight,
+			Hash:   hex.EncodeToString(header.BlockHash),
+			Offset: uint64(len(segment)),
+			Length: uint64(len(blkWithRWSetBytes)),
+		}
+		for _, tx := range block.Block.Txs {
+			entry.TxIds = append(entry.TxIds, tx.Payload.TxId)
+		}
+		segment = append(segment, blkWithRWSetBytes...)
+		index.Blocks = append(index.Blocks, entry)
+		nextHeight++
+	}
+	if len(index.Blocks) == 0 {
+		return nil
+	}
+
+	// segment and index are written before manifest, a failed write leaves unreferenced files only
+	segmentName, err := a.putContent(archiveSegmentDir, segment)
+	if err != nil {
+		return fmt.Errorf("write segment failed, %s", err)
+	}
+	index.Segment = segmentName
+	indexBytes, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	indexName, err := a.putContent(archiveIndexDir, indexBytes)
+	if err != nil {
+		return fmt.Errorf("write segment index failed, %s", err)
+	}
+
+	manifest := &archiveManifest{
+		Version: archiveManifestVersion,
+		ChainId: a.chainId,
+	}
+	if a.manifest != nil {
+		*manifest = *a.manifest
+		manifest.Segments = append([]*archiveSegmentEntry{}, a.manifest.Segments...)
+	} else {
+		manifest.GenesisHash = index.Blocks[0].Hash
+	}
+	first, last := index.Blocks[0], index.Blocks[len(index.Blocks)-1]
+	manifest.Segments = append(manifest.Segments, &archiveSegmentEntry{
+		StartHeight: first.Height,
+		EndHeight:   last.Height,
+		Index:       indexName,
+	})
+	manifest.ArchivedHeight = last.Height
+	if err = a.saveManifest(manifest); err != nil {
+		return err
+	}
+
+	a.manifest = manifest
+	a.indexes[len(manifest.Segments)-1] = index
+	if a.txHeights != nil {
+		for _, entry := range index.Blocks {
+			a.hashHeights[entry.Hash] = entry.Height
+			for _, txId := range entry.TxIds {
+				a.txHeights[txId] = entry.Height
+			}
+		}
+	}
+	a.log.Debugf("[SDK] archived segment %s of blocks [%d, %d]", segmentName, first.Height, last.Height)
+	return nil
+}
+
+// GetArchivedStatus get archived status
+// @return archivedHeight
+// @return inArchive
+// @return code
+// @return err
+func (a *ArchiveFileClient) GetArchivedStatus() (archivedHeight uint64, inArchive bool, code uint32, err error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.manifest == nil {
+		return 0, false, 0, errors.New("chain genesis not exists")
+	}
+	return a.manifest.ArchivedHeight, false, 0, nil
+}
+
+// Close 关闭与归档数据源的链接
+// @return err
+func (a *ArchiveFileClient) Close() error {
+	return nil
+}
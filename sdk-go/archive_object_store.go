@@ -0,0 +1,291 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"bytes"
+	cryptohmac "crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrArchiveObjectNotExist 对象不存在
+var ErrArchiveObjectNotExist = errors.New("archive object not exist")
+
+// ArchiveObjectStore ArchiveFileClient使用的对象存储
+type ArchiveObjectStore interface {
+	// Put 写入对象，已存在时覆盖
+	// @param key
+	// @param data
+	// @return error
+	Put(key string, data []byte) error
+
+	// Get 读取对象，不存在时返回ErrArchiveObjectNotExist
+	// @param key
+	// @return []byte
+	// @return error
+	Get(key string) ([]byte, error)
+}
+
+var _ ArchiveObjectStore = (*LocalObjectStore)(nil)
+
+// LocalObjectStore 本地目录实现的对象存储，对象key为目录下的相对路径
+type LocalObjectStore struct {
+	dir string
+}
+
+// NewLocalObjectStore 创建LocalObjectStore
+// @param dir
+// @return *LocalObjectStore
+// @return error
+func NewLocalObjectStore(dir string) (*LocalObjectStore, error) {
+	if dir == "" {
+		return nil, errors.New("archive dir must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("create archive dir failed, %s", err)
+	}
+	return &LocalObjectStore{dir: dir}, nil
+}
+
+// Put 先写临时文件再重命名，避免读到写了一半的对象
+// @param key
+// @param data
+// @return error
+func (s *LocalObjectStore) Put(key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get 读取对象
+// @param key
+// @return []byte
+// @return error
+func (s *LocalObjectStore) Get(key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrArchiveObjectNotExist
+	}
+	return data, err
+}
+
+func (s *LocalObjectStore) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid archive object key %s", key)
+	}
+	return filepath.Join(s.dir, cleaned), nil
+}
+
+var _ ArchiveObjectStore = (*S3ObjectStore)(nil)
+
+const (
+	s3DefaultRegion   = "us-east-1"
+	s3Service         = "s3"
+	s3SignAlgorithm   = "AWS4-HMAC-SHA256"
+	s3AmzDateFormat   = "20060102T150405Z"
+	s3ShortDateFormat = "20060102"
+	s3RequestTimeout  = 60 * time.Second
+)
+
+// S3ObjectStore S3兼容对象存储的实现，使用path-style地址和AWS Signature V4签名，可用于MinIO等
+type S3ObjectStore struct {
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// NewS3ObjectStore 创建S3ObjectStore
+// @param dest 格式为 http(s)://access_key:secret_key@host:port/bucket/prefix?region=us-east-1
+// @return *S3ObjectStore
+// @return error
+func NewS3ObjectStore(dest string) (*S3ObjectStore, error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("parse s3 dest failed, %s", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("s3 dest must be http(s)://access_key:secret_key@host:port/bucket/prefix")
+	}
+	if u.User == nil {
+		return nil, errors.New("s3 dest must contain access key and secret key")
+	}
+	secretKey, _ := u.User.Password()
+	parts := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)
+	if parts[0] == "" {
+		return nil, errors.New("s3 dest must contain bucket")
+	}
+	s := &S3ObjectStore{
+		endpoint:  &url.URL{Scheme: u.Scheme, Host: u.Host},
+		bucket:    parts[0],
+		region:    u.Query().Get("region"),
+		accessKey: u.User.Username(),
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: s3RequestTimeout},
+	}
+	if len(parts) == 2 {
+		s.prefix = parts[1]
+	}
+	if s.region == "" {
+		s.region = s3DefaultRegion
+	}
+	return s, nil
+}
+
+// Put 写入对象
+// @param key
+// @param data
+// @return error
+func (s *S3ObjectStore) Put(key string, data []byte) error {
+	resp, err := s.do(http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("put s3 object %s failed, status: %s, body: %s", key, resp.Status, body)
+	}
+	return nil
+}
+
+// Get 读取对象
+// @param key
+// @return []byte
+// @return error
+func (s *S3ObjectStore) Get(key string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, ErrArchiveObjectNotExist
+	default:
+		return nil, fmt.Errorf("get s3 object %s failed, status: %s, body: %s", key, resp.Status, body)
+	}
+}
+
+func (s *S3ObjectStore) do(method, key string, data []byte) (*http.Response, error) {
+	objectPath := "/" + s.bucket + "/" + key
+	if s.prefix != "" {
+		objectPath = "/" + s.bucket + "/" + s.prefix + "/" + key
+	}
+	u := *s.endpoint
+	u.Path = objectPath
+	u.RawPath = s3EscapePath(objectPath)
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, data, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// sign signs the request with AWS Signature V4
+func (s *S3ObjectStore) sign(req *http.Request, data []byte, now time.Time) {
+	payloadSum := sha256.Sum256(data)
+	payloadHash := hex.EncodeToString(payloadSum[:])
+	amzDate := now.Format(s3AmzDateFormat)
+	shortDate := now.Format(s3ShortDateFormat)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalSum := sha256.Sum256([]byte(canonicalRequest))
+	scope := shortDate + "/" + s.region + "/" + s3Service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		s3SignAlgorithm, amzDate, scope, hex.EncodeToString(canonicalSum[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), shortDate)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, s3Service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SignAlgorithm, s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := cryptohmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath escapes every byte except unreserved characters and '/' as S3 requires
+func s3EscapePath(path string) string {
+	var builder strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			builder.WriteByte(c)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", c)
+		}
+	}
+	return builder.String()
+}
@@ -9,6 +9,8 @@ package chainmaker_sdk_go
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -39,6 +41,8 @@ type ConnectionPool interface {
 	getClient() (*networkClient, error)
 	getClientWithIgnoreAddrs(ignoreAddrs map[string]struct{}) (*networkClient, error)
 	getLogger() utils.Logger
+	// Stats 获取各节点的健康状态
+	Stats() []*NodeStats
 	Close() error
 }
 
@@ -80,6 +84,17 @@ type ClientConnectionPool struct {
 	userEncCrtBytes   []byte
 	rpcMaxRecvMsgSize int
 	rpcMaxSendMsgSize int
+
+	chainId        string
+	connPoolConfig *ConnPoolConfig
+	// account is the digest of user identity, used by sticky balance policy
+	account string
+	// nodeAddrs are the node addresses in the order of connections
+	nodeAddrs     []string
+	health        map[string]*nodeHealth
+	highestHeight uint64
+	closeC        chan struct{}
+	closeOnce     sync.Once
 }
 
 // NewConnPool 创建连接池
@@ -112,6 +127,8 @@ func NewConnPool(config *ChainClientConfig) (*ClientConnectionPool, error) {
 
 	// 打散，用作负载均衡
 	pool.connections = shuffle(pool.connections)
+	pool.initHealth(config)
+	pool.startHealthCheck()
 
 	return pool, nil
 }
@@ -142,11 +159,43 @@ func NewCanonicalTxFetcherPools(config *ChainClientConfig) (map[string]Connectio
 		}
 		// 打散，用作负载均衡
 		pool.connections = shuffle(pool.connections)
+		pool.initHealth(config)
 		pools[node.addr] = pool
 	}
 	return pools, nil
 }
 
+// initHealth initializes the health of nodes, must be called after connections are set
+func (pool *ClientConnectionPool) initHealth(config *ChainClientConfig) {
+	pool.chainId = config.chainId
+	pool.connPoolConfig = config.connPoolConfig
+	if pool.connPoolConfig == nil {
+		pool.connPoolConfig = NewConnPoolConfig()
+	}
+	pool.account = userAccountDigest(config)
+	pool.health = make(map[string]*nodeHealth)
+	pool.closeC = make(chan struct{})
+	for _, cli := range pool.connections {
+		if _, ok := pool.health[cli.nodeAddr]; !ok {
+			pool.health[cli.nodeAddr] = newNodeHealth(cli.nodeAddr)
+			pool.nodeAddrs = append(pool.nodeAddrs, cli.nodeAddr)
+		}
+	}
+}
+
+// userAccountDigest digest of the user identity of config
+func userAccountDigest(config *ChainClientConfig) string {
+	identity := config.userSignCrtBytes
+	if len(identity) == 0 && config.userPk != nil {
+		identity, _ = config.userPk.Bytes()
+	}
+	if len(identity) == 0 {
+		identity = config.userCrtBytes
+	}
+	sum := sha256.Sum256(identity)
+	return hex.EncodeToString(sum[:])
+}
+
 // 初始化GPRC客户端连接
 func (pool *ClientConnectionPool) initGRPCConnect(nodeAddr string, useTLS bool, caPaths, caCerts []string,
 	tlsHostName string) (*grpc.ClientConn, error) {
@@ -191,6 +240,8 @@ func (pool *ClientConnectionPool) initGRPCConnect(nodeAddr string, useTLS bool,
 				grpc.MaxCallSendMsgSize(pool.rpcMaxSendMsgSize),
 			),
 			grpc.WithKeepaliveParams(kacp),
+			grpc.WithChainUnaryInterceptor(pool.healthInterceptor(nodeAddr)),
+			grpc.WithChainStreamInterceptor(pool.healthStreamInterceptor(nodeAddr)),
 		)
 	}
 	return grpc.Dial(
@@ -201,6 +252,8 @@ func (pool *ClientConnectionPool) initGRPCConnect(nodeAddr string, useTLS bool,
 			grpc.MaxCallSendMsgSize(pool.rpcMaxSendMsgSize),
 		),
 		grpc.WithKeepaliveParams(kacp),
+		grpc.WithChainUnaryInterceptor(pool.healthInterceptor(nodeAddr)),
+		grpc.WithChainStreamInterceptor(pool.healthStreamInterceptor(nodeAddr)),
 	)
 }
 
@@ -224,20 +277,73 @@ func (pool *ClientConnectionPool) getClientWithIgnoreAddrs(ignoreAddrs map[strin
 	return nc, nil
 }
 
+// getClientOnce picks a node by balance policy among the nodes whose circuit breaker allows requests,
+// all nodes not ignored are tried if every breaker is open
 func (pool *ClientConnectionPool) getClientOnce(ignoreAddrs map[string]struct{}) (*networkClient, error) {
 	pool.mut.Lock()
 	defer pool.mut.Unlock()
-	var err error
-	for _, cli := range pool.connections {
-		if ignoreAddrs != nil {
-			if _, ok := ignoreAddrs[cli.ID]; ok {
-				continue
+
+	now := time.Now()
+	var candidates, fallback []*NodeStats
+	for _, nodeStats := range pool.statsLocked() {
+		if !pool.hasConnectionLocked(nodeStats.NodeAddr, ignoreAddrs) {
+			continue
+		}
+		fallback = append(fallback, nodeStats)
+		if pool.health[nodeStats.NodeAddr].available(now, pool.connPoolConfig.openTimeout) {
+			candidates = append(candidates, nodeStats)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = fallback
+	}
+
+	for len(candidates) > 0 {
+		picked := pool.connPoolConfig.balancePolicy.Pick(pool.account, candidates)
+		if picked == nil {
+			picked = candidates[0]
+		}
+		if cli := pool.usableConnectionLocked(picked.NodeAddr, ignoreAddrs); cli != nil {
+			// the half open probe is taken only by a node which really gets the request
+			pool.health[picked.NodeAddr].acquire(now, pool.connPoolConfig.openTimeout)
+			return cli, nil
+		}
+		for i, candidate := range candidates {
+			if candidate == picked {
+				candidates = append(candidates[:i:i], candidates[i+1:]...)
+				break
 			}
 		}
+	}
+	return nil, errors.New("grpc connections unavailable, see sdk log file for more details")
+}
+
+// hasConnectionLocked whether node has connections not ignored
+func (pool *ClientConnectionPool) hasConnectionLocked(nodeAddr string, ignoreAddrs map[string]struct{}) bool {
+	for _, cli := range pool.connections {
+		if cli.nodeAddr != nodeAddr {
+			continue
+		}
+		if _, ok := ignoreAddrs[cli.ID]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// usableConnectionLocked returns a usable connection of node, connects it if necessary
+func (pool *ClientConnectionPool) usableConnectionLocked(nodeAddr string,
+	ignoreAddrs map[string]struct{}) *networkClient {
+	for _, cli := range pool.connections {
+		if cli.nodeAddr != nodeAddr {
+			continue
+		}
+		if _, ok := ignoreAddrs[cli.ID]; ok {
+			continue
+		}
 
 		if cli.conn == nil || cli.conn.GetState() == connectivity.Shutdown {
-			var conn *grpc.ClientConn
-			conn, err = pool.initGRPCConnect(cli.nodeAddr, cli.useTLS, cli.caPaths, cli.caCerts, cli.tlsHostName)
+			conn, err := pool.initGRPCConnect(cli.nodeAddr, cli.useTLS, cli.caPaths, cli.caCerts, cli.tlsHostName)
 			if err != nil {
 				pool.logger.Errorf("init grpc connection [nodeAddr:%s] failed, %s", cli.ID, err.Error())
 				continue
@@ -245,15 +351,15 @@ func (pool *ClientConnectionPool) getClientOnce(ignoreAddrs map[string]struct{})
 
 			cli.conn = conn
 			cli.rpcNode = api.NewRpcNodeClient(conn)
-			return cli, nil
+			return cli
 		}
 
 		s := cli.conn.GetState()
 		if s == connectivity.Idle || s == connectivity.Ready || s == connectivity.Connecting {
-			return cli, nil
+			return cli
 		}
 	}
-	return nil, errors.New("grpc connections unavailable, see sdk log file for more details")
+	return nil
 }
 
 func (pool *ClientConnectionPool) getLogger() utils.Logger {
@@ -262,6 +368,11 @@ func (pool *ClientConnectionPool) getLogger() utils.Logger {
 
 // Close 关闭连接池
 func (pool *ClientConnectionPool) Close() error {
+	pool.closeOnce.Do(func() {
+		if pool.closeC != nil {
+			close(pool.closeC)
+		}
+	})
 	pool.mut.Lock()
 	defer pool.mut.Unlock()
 	for _, c := range pool.connections {
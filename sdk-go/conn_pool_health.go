@@ -0,0 +1,515 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	syncpb "chainmaker.org/chainmaker/pb-go/v2/sync"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultCircuitFailureThreshold 连续失败多少次后熔断节点
+	DefaultCircuitFailureThreshold = 5
+	// DefaultCircuitOpenTimeout 熔断后多久进入半开状态，放行一个探测请求
+	DefaultCircuitOpenTimeout = 10 * time.Second
+	// DefaultHealthCheckInterval 通过GetSyncState探测节点高度的间隔
+	DefaultHealthCheckInterval = 10 * time.Second
+
+	healthCheckTimeout = 3 * time.Second
+	// healthEWMAWeight is the weight of the newest sample in latency and error rate
+	healthEWMAWeight = 0.2
+	// syncLagScoreWeight is the score of a block behind, in ms of latency
+	syncLagScoreWeight = 10.0
+	// errorRateScoreWeight multiplies latency score by 1 + errorRate*errorRateScoreWeight
+	errorRateScoreWeight = 10.0
+)
+
+// CircuitState 节点熔断器状态
+type CircuitState string
+
+const (
+	// CircuitClosed 正常状态，节点可用
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen 熔断状态，节点不参与负载均衡
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen 半开状态，放行一个探测请求，成功后恢复，失败后重新熔断
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// NodeStats 节点健康状态
+type NodeStats struct {
+	NodeAddr    string       `json:"node_addr"`
+	State       CircuitState `json:"state"`
+	Connections int          `json:"connections"`
+	// Requests and Failures count the unary rpc calls of the node
+	Requests            uint64 `json:"requests"`
+	Failures            uint64 `json:"failures"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	// Latency and ErrorRate are exponentially weighted moving averages
+	Latency   time.Duration `json:"latency"`
+	ErrorRate float64       `json:"error_rate"`
+	// BlockHeight is the height of node reported by GetSyncState
+	BlockHeight uint64 `json:"block_height"`
+	// SyncLag is how many blocks the node is behind the highest known height
+	SyncLag       uint64    `json:"sync_lag"`
+	LastError     string    `json:"last_error,omitempty"`
+	LastCheckTime time.Time `json:"last_check_time"`
+	// Score is lower for healthier node, combined by latency, error rate and sync lag
+	Score float64 `json:"score"`
+}
+
+// BalancePolicy 连接池负载均衡策略
+type BalancePolicy interface {
+	// Name 策略名
+	Name() string
+	// Pick 从可用节点中选择一个，nodes按连接池中的顺序排列且不为空
+	// @param account 客户端用户身份的摘要
+	// @param nodes
+	// @return *NodeStats
+	Pick(account string, nodes []*NodeStats) *NodeStats
+}
+
+// FirstAvailableBalancePolicy 选择连接池中第一个可用节点，连接池创建时已打散节点顺序
+type FirstAvailableBalancePolicy struct{}
+
+// Name 策略名
+func (FirstAvailableBalancePolicy) Name() string {
+	return "first_available"
+}
+
+// Pick 选择第一个可用节点
+func (FirstAvailableBalancePolicy) Pick(_ string, nodes []*NodeStats) *NodeStats {
+	return nodes[0]
+}
+
+// LeastLatencyBalancePolicy 选择延迟最低的节点，未测量过延迟的节点优先
+type LeastLatencyBalancePolicy struct{}
+
+// Name 策略名
+func (LeastLatencyBalancePolicy) Name() string {
+	return "least_latency"
+}
+
+// Pick 选择延迟最低的节点
+func (LeastLatencyBalancePolicy) Pick(_ string, nodes []*NodeStats) *NodeStats {
+	picked := nodes[0]
+	for _, node := range nodes[1:] {
+		if node.Latency < picked.Latency {
+			picked = node
+		}
+	}
+	return picked
+}
+
+// HighestHeightBalancePolicy 选择区块高度最高的节点，高度相同时选择延迟最低的节点
+type HighestHeightBalancePolicy struct{}
+
+// Name 策略名
+func (HighestHeightBalancePolicy) Name() string {
+	return "highest_height"
+}
+
+// Pick 选择区块高度最高的节点
+func (HighestHeightBalancePolicy) Pick(_ string, nodes []*NodeStats) *NodeStats {
+	picked := nodes[0]
+	for _, node := range nodes[1:] {
+		if node.BlockHeight > picked.BlockHeight ||
+			node.BlockHeight == picked.BlockHeight && node.Latency < picked.Latency {
+			picked = node
+		}
+	}
+	return picked
+}
+
+// StickyBalancePolicy 同一个用户身份固定使用同一个节点，保证交易按发送顺序到达节点，
+// 节点不可用时才切换，使用rendezvous hash使各用户均匀分布在节点上
+type StickyBalancePolicy struct{}
+
+// Name 策略名
+func (StickyBalancePolicy) Name() string {
+	return "sticky"
+}
+
+// Pick 选择用户身份和节点地址hash最大的节点
+func (StickyBalancePolicy) Pick(account string, nodes []*NodeStats) *NodeStats {
+	var (
+		picked  *NodeStats
+		highest uint64
+	)
+	for _, node := range nodes {
+		sum := sha256.Sum256([]byte(account + "/" + node.NodeAddr))
+		weight := binary.BigEndian.Uint64(sum[:8])
+		if picked == nil || weight > highest {
+			picked, highest = node, weight
+		}
+	}
+	return picked
+}
+
+// balancePolicies built-in policies by name
+var balancePolicies = map[string]BalancePolicy{
+	FirstAvailableBalancePolicy{}.Name(): FirstAvailableBalancePolicy{},
+	LeastLatencyBalancePolicy{}.Name():   LeastLatencyBalancePolicy{},
+	HighestHeightBalancePolicy{}.Name():  HighestHeightBalancePolicy{},
+	StickyBalancePolicy{}.Name():         StickyBalancePolicy{},
+}
+
+// GetBalancePolicy 根据策略名获取内置负载均衡策略
+// @param name first_available, least_latency, highest_height or sticky
+// @return BalancePolicy
+// @return error
+func GetBalancePolicy(name string) (BalancePolicy, error) {
+	policy, ok := balancePolicies[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown balance policy %s", name)
+	}
+	return policy, nil
+}
+
+// ConnPoolConfig 连接池负载均衡和熔断配置
+type ConnPoolConfig struct {
+	balancePolicy BalancePolicy
+	// 连续失败多少次后熔断节点
+	failureThreshold int
+	// 熔断后多久进入半开状态
+	openTimeout time.Duration
+	// 探测节点高度的间隔，为负数时不探测
+	healthCheckInterval time.Duration
+}
+
+// ConnPoolOption define conn pool option func
+type ConnPoolOption func(config *ConnPoolConfig)
+
+// NewConnPoolConfig create conn pool config, 未设置的参数使用默认值
+func NewConnPoolConfig(opts ...ConnPoolOption) *ConnPoolConfig {
+	config := &ConnPoolConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.balancePolicy == nil {
+		config.balancePolicy = FirstAvailableBalancePolicy{}
+	}
+	if config.failureThreshold <= 0 {
+		config.failureThreshold = DefaultCircuitFailureThreshold
+	}
+	if config.openTimeout <= 0 {
+		config.openTimeout = DefaultCircuitOpenTimeout
+	}
+	if config.healthCheckInterval == 0 {
+		config.healthCheckInterval = DefaultHealthCheckInterval
+	}
+	return config
+}
+
+// WithConnPoolBalancePolicy 设置负载均衡策略，默认为first_available
+func WithConnPoolBalancePolicy(policy BalancePolicy) ConnPoolOption {
+	return func(config *ConnPoolConfig) {
+		config.balancePolicy = policy
+	}
+}
+
+// WithConnPoolFailureThreshold 设置连续失败多少次后熔断节点
+func WithConnPoolFailureThreshold(threshold int) ConnPoolOption {
+	return func(config *ConnPoolConfig) {
+		config.failureThreshold = threshold
+	}
+}
+
+// WithConnPoolOpenTimeout 设置熔断后多久进入半开状态
+func WithConnPoolOpenTimeout(timeout time.Duration) ConnPoolOption {
+	return func(config *ConnPoolConfig) {
+		config.openTimeout = timeout
+	}
+}
+
+// WithConnPoolHealthCheckInterval 设置探测节点高度的间隔，为负数时不探测
+func WithConnPoolHealthCheckInterval(interval time.Duration) ConnPoolOption {
+	return func(config *ConnPoolConfig) {
+		config.healthCheckInterval = interval
+	}
+}
+
+// nodeHealth health and circuit breaker of a node
+type nodeHealth struct {
+	mut  sync.Mutex
+	addr string
+
+	state    CircuitState
+	openedAt time.Time
+	// probing is set when the half open probe is in flight
+	probing      bool
+	probeStarted time.Time
+
+	requests            uint64
+	failures            uint64
+	consecutiveFailures int
+	latency             float64
+	errorRate           float64
+	height              uint64
+	lastError           string
+	lastCheckTime       time.Time
+}
+
+func newNodeHealth(addr string) *nodeHealth {
+	return &nodeHealth{addr: addr, state: CircuitClosed}
+}
+
+// available whether the node can serve requests now, it does not change the state
+func (h *nodeHealth) available(now time.Time, openTimeout time.Duration) bool {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	switch h.state {
+	case CircuitOpen:
+		return now.Sub(h.openedAt) >= openTimeout
+	case CircuitHalfOpen:
+		return !h.probing || now.Sub(h.probeStarted) >= openTimeout
+	default:
+		return true
+	}
+}
+
+// acquire marks the request to the node as the half open probe if the breaker is not closed
+func (h *nodeHealth) acquire(now time.Time, openTimeout time.Duration) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	if h.state == CircuitOpen && now.Sub(h.openedAt) >= openTimeout {
+		h.state = CircuitHalfOpen
+	}
+	if h.state == CircuitHalfOpen {
+		h.probing = true
+		h.probeStarted = now
+	}
+}
+
+// record records the result of a request
+func (h *nodeHealth) record(latency time.Duration, err error, failureThreshold int, now time.Time) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	h.requests++
+	if isNodeFailure(err) {
+		h.failures++
+		h.consecutiveFailures++
+		h.errorRate = ewma(h.errorRate, 1, h.requests == 1)
+		h.lastError = err.Error()
+		if h.state == CircuitHalfOpen || h.consecutiveFailures >= failureThreshold {
+			h.state = CircuitOpen
+			h.openedAt = now
+			h.probing = false
+		}
+		return
+	}
+	h.consecutiveFailures = 0
+	h.errorRate = ewma(h.errorRate, 0, h.requests == 1)
+	h.latency = ewma(h.latency, float64(latency), h.latency == 0)
+	if h.state == CircuitHalfOpen {
+		h.state = CircuitClosed
+		h.probing = false
+	}
+}
+
+func (h *nodeHealth) setHeight(height uint64, now time.Time) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	h.height = height
+	h.lastCheckTime = now
+}
+
+func (h *nodeHealth) stats(highestHeight uint64) *NodeStats {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	stats := &NodeStats{
+		NodeAddr:            h.addr,
+		State:               h.state,
+		Requests:            h.requests,
+		Failures:            h.failures,
+		ConsecutiveFailures: h.consecutiveFailures,
+		Latency:             time.Duration(h.latency),
+		ErrorRate:           h.errorRate,
+		BlockHeight:         h.height,
+		LastError:           h.lastError,
+		LastCheckTime:       h.lastCheckTime,
+	}
+	if !h.lastCheckTime.IsZero() && highestHeight > h.height {
+		stats.SyncLag = highestHeight - h.height
+	}
+	stats.Score = float64(stats.Latency)/float64(time.Millisecond)*(1+stats.ErrorRate*errorRateScoreWeight) +
+		float64(stats.SyncLag)*syncLagScoreWeight
+	return stats
+}
+
+func ewma(avg, sample float64, first bool) float64 {
+	if first {
+		return sample
+	}
+	return avg*(1-healthEWMAWeight) + sample*healthEWMAWeight
+}
+
+// isNodeFailure whether the error means the node is unhealthy, errors of request itself are not counted
+func isNodeFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	statusErr, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+	switch statusErr.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// healthInterceptor records latency and errors of the unary rpc calls to node
+func (pool *ClientConnectionPool) healthInterceptor(nodeAddr string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if h := pool.nodeHealth(nodeAddr); h != nil {
+			h.record(time.Since(start), err, pool.connPoolConfig.failureThreshold, time.Now())
+		}
+		return err
+	}
+}
+
+// healthStreamInterceptor records the result of opening streams to node, like subscriptions,
+// and the errors breaking an opened stream, the end of stream is not an error
+func (pool *ClientConnectionPool) healthStreamInterceptor(nodeAddr string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		h := pool.nodeHealth(nodeAddr)
+		if h == nil {
+			return stream, err
+		}
+		h.record(time.Since(start), err, pool.connPoolConfig.failureThreshold, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return &healthClientStream{ClientStream: stream, pool: pool, health: h}, nil
+	}
+}
+
+// healthClientStream records the errors of RecvMsg to the health of node
+type healthClientStream struct {
+	grpc.ClientStream
+	pool   *ClientConnectionPool
+	health *nodeHealth
+}
+
+// RecvMsg receive message from stream
+func (s *healthClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil && err != io.EOF && isNodeFailure(err) {
+		s.health.record(0, err, s.pool.connPoolConfig.failureThreshold, time.Now())
+	}
+	return err
+}
+
+// Stats 获取连接池中各节点的健康状态
+// @return []*NodeStats
+func (pool *ClientConnectionPool) Stats() []*NodeStats {
+	pool.mut.Lock()
+	defer pool.mut.Unlock()
+	return pool.statsLocked()
+}
+
+func (pool *ClientConnectionPool) statsLocked() []*NodeStats {
+	highestHeight := pool.highestHeight
+	var stats []*NodeStats
+	for _, addr := range pool.nodeAddrs {
+		nodeStats := pool.health[addr].stats(highestHeight)
+		for _, cli := range pool.connections {
+			if cli.nodeAddr == addr {
+				nodeStats.Connections++
+			}
+		}
+		stats = append(stats, nodeStats)
+	}
+	return stats
+}
+
+// nodeHealth the health map is not changed after initHealth, so it is read without lock
+func (pool *ClientConnectionPool) nodeHealth(nodeAddr string) *nodeHealth {
+	return pool.health[nodeAddr]
+}
+
+// startHealthCheck probes the height of nodes by GetSyncState periodically until pool is closed
+func (pool *ClientConnectionPool) startHealthCheck() {
+	if pool.connPoolConfig.healthCheckInterval <= 0 || pool.chainId == "" {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(pool.connPoolConfig.healthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pool.closeC:
+				return
+			case <-ticker.C:
+				pool.checkHealth()
+			}
+		}
+	}()
+}
+
+func (pool *ClientConnectionPool) checkHealth() {
+	highestHeight := uint64(0)
+	for _, addr := range pool.nodeAddrs {
+		h := pool.nodeHealth(addr)
+		now := time.Now()
+		if !h.available(now, pool.connPoolConfig.openTimeout) {
+			continue
+		}
+		h.acquire(now, pool.connPoolConfig.openTimeout)
+
+		pool.mut.Lock()
+		cli := pool.usableConnectionLocked(addr, nil)
+		pool.mut.Unlock()
+		if cli == nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		state, err := cli.rpcNode.GetSyncState(ctx, &syncpb.GetSyncStateRequest{
+			ChainId:   pool.chainId,
+			WithPeers: true,
+		})
+		cancel()
+		if err != nil {
+			pool.logger.Debugf("[SDK] health check of node [%s] failed, %s", addr, err)
+			continue
+		}
+		h.setHeight(state.Height, time.Now())
+		if state.Height > highestHeight {
+			highestHeight = state.Height
+		}
+		for _, peer := range state.Others {
+			if peer.Height > highestHeight {
+				highestHeight = peer.Height
+			}
+		}
+	}
+
+	pool.mut.Lock()
+	pool.highestHeight = highestHeight
+	pool.mut.Unlock()
+}
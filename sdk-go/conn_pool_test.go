@@ -8,9 +8,15 @@ SPDX-License-Identifier: Apache-2.0
 package chainmaker_sdk_go
 
 import (
+	"context"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestNewConnPool(t *testing.T) {
@@ -26,3 +32,162 @@ func TestNewCanonicalTxFetcherPools(t *testing.T) {
 	_, err = NewCanonicalTxFetcherPools(conf)
 	require.Nil(t, err)
 }
+
+func TestBalancePolicies(t *testing.T) {
+	nodes := []*NodeStats{
+		{NodeAddr: "node1", Latency: 30 * time.Millisecond, BlockHeight: 10},
+		{NodeAddr: "node2", Latency: 10 * time.Millisecond, BlockHeight: 9},
+		{NodeAddr: "node3", Latency: 20 * time.Millisecond, BlockHeight: 10},
+	}
+	require.Equal(t, "node1", FirstAvailableBalancePolicy{}.Pick("", nodes).NodeAddr)
+	require.Equal(t, "node2", LeastLatencyBalancePolicy{}.Pick("", nodes).NodeAddr)
+	require.Equal(t, "node3", HighestHeightBalancePolicy{}.Pick("", nodes).NodeAddr)
+
+	// the same account sticks to the same node until it is unavailable
+	sticky := StickyBalancePolicy{}.Pick("account1", nodes)
+	require.Equal(t, sticky, StickyBalancePolicy{}.Pick("account1", nodes))
+	var others []*NodeStats
+	for _, node := range nodes {
+		if node != sticky {
+			others = append(others, node)
+		}
+	}
+	require.NotEqual(t, sticky, StickyBalancePolicy{}.Pick("account1", others))
+
+	policy, err := GetBalancePolicy("LEAST_LATENCY")
+	require.Nil(t, err)
+	require.Equal(t, LeastLatencyBalancePolicy{}, policy)
+	_, err = GetBalancePolicy("round_robin")
+	require.NotNil(t, err)
+}
+
+func TestNodeHealthCircuitBreaker(t *testing.T) {
+	openTimeout := time.Second
+	now := time.Now()
+	h := newNodeHealth("node1")
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	h.record(10*time.Millisecond, nil, 2, now)
+	// errors of request itself do not open the breaker
+	h.record(10*time.Millisecond, status.Error(codes.InvalidArgument, "bad request"), 2, now)
+	h.record(0, unavailable, 2, now)
+	require.True(t, h.available(now, openTimeout))
+	h.record(0, unavailable, 2, now)
+	require.Equal(t, CircuitOpen, h.stats(0).State)
+	require.False(t, h.available(now, openTimeout))
+
+	// half open lets one probe through, a failed probe opens the breaker again
+	now = now.Add(openTimeout)
+	require.True(t, h.available(now, openTimeout))
+	h.acquire(now, openTimeout)
+	require.Equal(t, CircuitHalfOpen, h.stats(0).State)
+	require.False(t, h.available(now, openTimeout))
+	h.record(0, unavailable, 2, now)
+	require.Equal(t, CircuitOpen, h.stats(0).State)
+
+	now = now.Add(openTimeout)
+	h.acquire(now, openTimeout)
+	h.record(20*time.Millisecond, nil, 2, now)
+	h.setHeight(8, now)
+	stats := h.stats(10)
+	require.Equal(t, CircuitClosed, stats.State)
+	require.Equal(t, uint64(6), stats.Requests)
+	require.Equal(t, uint64(3), stats.Failures)
+	require.Equal(t, uint64(2), stats.SyncLag)
+	require.True(t, stats.Score > 0)
+}
+
+func TestConnPoolCircuitBreaker(t *testing.T) {
+	conf, err := generateConfig(
+		WithConfPath(sdkConfigPathForUT),
+		AddChainClientNodeConfig(NewNodeConfig(WithNodeAddr("127.0.0.1:12311"), WithNodeConnCnt(1))),
+		AddChainClientNodeConfig(NewNodeConfig(WithNodeAddr("127.0.0.1:12312"), WithNodeConnCnt(1))),
+		WithConnPoolConfig(NewConnPoolConfig(
+			WithConnPoolFailureThreshold(1),
+			WithConnPoolOpenTimeout(100*time.Millisecond),
+			WithConnPoolHealthCheckInterval(-1),
+		)),
+	)
+	require.Nil(t, err)
+	pool, err := NewConnPool(conf)
+	require.Nil(t, err)
+	defer pool.Close()
+
+	// only the two nodes without tls are used
+	ignoreAddrs := make(map[string]struct{})
+	for _, cli := range pool.connections {
+		if cli.nodeAddr != "127.0.0.1:12311" && cli.nodeAddr != "127.0.0.1:12312" {
+			ignoreAddrs[cli.ID] = struct{}{}
+		}
+	}
+	cli, err := pool.getClientOnce(ignoreAddrs)
+	require.Nil(t, err)
+	first := cli.nodeAddr
+
+	pool.health[first].record(0, status.Error(codes.Unavailable, "unavailable"), 1, time.Now())
+	cli, err = pool.getClientOnce(ignoreAddrs)
+	require.Nil(t, err)
+	require.NotEqual(t, first, cli.nodeAddr)
+
+	time.Sleep(100 * time.Millisecond)
+	cli, err = pool.getClientOnce(ignoreAddrs)
+	require.Nil(t, err)
+	require.Equal(t, first, cli.nodeAddr)
+
+	for _, stats := range pool.Stats() {
+		if stats.NodeAddr == first {
+			require.Equal(t, CircuitHalfOpen, stats.State)
+			require.Equal(t, uint64(1), stats.Failures)
+		}
+	}
+}
+
+// recvStream is a grpc.ClientStream whose RecvMsg returns the errors in order
+type recvStream struct {
+	grpc.ClientStream
+	errs []error
+}
+
+func (s *recvStream) RecvMsg(m interface{}) error {
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func TestHealthStreamInterceptor(t *testing.T) {
+	pool := &ClientConnectionPool{
+		health:         map[string]*nodeHealth{"node1": newNodeHealth("node1")},
+		connPoolConfig: NewConnPoolConfig(WithConnPoolFailureThreshold(2)),
+	}
+	interceptor := pool.healthStreamInterceptor("node1")
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+
+	// failing to open a stream is a node failure
+	_, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/api.RpcNode/Subscribe",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream,
+			error) {
+			return nil, unavailable
+		})
+	require.Equal(t, unavailable, err)
+	stats := pool.health["node1"].stats(0)
+	require.Equal(t, uint64(1), stats.Failures)
+
+	// the end of stream and canceled streams are not, a broken stream is
+	stream, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/api.RpcNode/Subscribe",
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream,
+			error) {
+			return &recvStream{errs: []error{nil, io.EOF, status.Error(codes.Canceled, "canceled"), unavailable,
+				unavailable}}, nil
+		})
+	require.Nil(t, err)
+	require.Nil(t, stream.RecvMsg(nil))
+	require.Equal(t, io.EOF, stream.RecvMsg(nil))
+	require.NotNil(t, stream.RecvMsg(nil))
+	require.Equal(t, unavailable, stream.RecvMsg(nil))
+	require.Equal(t, CircuitClosed, pool.health["node1"].stats(0).State)
+	require.Equal(t, unavailable, stream.RecvMsg(nil))
+	stats = pool.health["node1"].stats(0)
+	require.Equal(t, uint64(4), stats.Requests)
+	require.Equal(t, uint64(3), stats.Failures)
+	require.Equal(t, CircuitOpen, stats.State)
+}
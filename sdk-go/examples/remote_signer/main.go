@@ -0,0 +1,83 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"time"
+
+	"chainmaker.org/chainmaker/common/v2/random/uuid"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	sdk "chainmaker.org/chainmaker/sdk-go/v2"
+)
+
+const (
+	sdkConfigOrg1Client1Path = "../sdk_configs/sdk_config_org1_client1.yml"
+
+	// start signerd first: cd signerd && go run main.go -conf signerd.yml
+	remoteSignerAddr  = "127.0.0.1:12401"
+	remoteSignerKeyId = "org1client1"
+	// signerd authenticates clients by tls certs issued by the org ca
+	remoteSignerCaPath      = "../../testdata/crypto-config/wx-org1.chainmaker.org/ca/ca.crt"
+	remoteSignerTLSHostName = "chainmaker.org"
+	remoteSignerTLSCertFile = "../../testdata/crypto-config/wx-org1.chainmaker.org/user/client1/client1.tls.crt"
+	remoteSignerTLSKeyFile  = "../../testdata/crypto-config/wx-org1.chainmaker.org/user/client1/client1.tls.key"
+
+	claimContractName = "claim001"
+)
+
+func main() {
+	caCert, err := ioutil.ReadFile(remoteSignerCaPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// the sign key in sdk config is not used, txs are signed by signerd
+	cc, err := sdk.NewChainClient(
+		sdk.WithConfPath(sdkConfigOrg1Client1Path),
+		sdk.WithRemoteSignerConfig(sdk.NewRemoteSignerConfig(
+			sdk.WithRemoteSignerAddr(remoteSignerAddr),
+			sdk.WithRemoteSignerKeyId(remoteSignerKeyId),
+			sdk.WithRemoteSignerTLS([]string{string(caCert)}, remoteSignerTLSHostName, remoteSignerTLSCertFile,
+				remoteSignerTLSKeyFile),
+		)),
+	)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer cc.Stop()
+
+	fmt.Println("====================== 使用远程签名服务调用合约 ======================")
+	curTime := strconv.FormatInt(time.Now().Unix(), 10)
+	kvs := []*common.KeyValuePair{
+		{
+			Key:   "time",
+			Value: []byte(curTime),
+		},
+		{
+			Key:   "file_hash",
+			Value: []byte(uuid.GetUUID()),
+		},
+		{
+			Key:   "file_name",
+			Value: []byte(fmt.Sprintf("file_%s", curTime)),
+		},
+	}
+	resp, err := cc.InvokeContract(claimContractName, "save", "", kvs, -1, true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("invoke contract resp: %+v\n", resp)
+
+	fmt.Println("====================== 签名服务拒绝未授权的方法 ======================")
+	_, err = cc.InvokeContract(claimContractName, "delete", "", kvs, -1, true)
+	fmt.Printf("invoke contract err: %v\n", err)
+}
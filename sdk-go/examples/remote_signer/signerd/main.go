@@ -0,0 +1,190 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// signerd is a reference signing daemon for sdk.RemoteSigner, holding keys in local key files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"chainmaker.org/chainmaker/common/v2/ca"
+	cmlog "chainmaker.org/chainmaker/common/v2/log"
+	"chainmaker.org/chainmaker/sdk-go/v2/remotesigner"
+	"chainmaker.org/chainmaker/sdk-go/v2/remotesigner/pb"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+)
+
+type signerdConfig struct {
+	Listen    string       `mapstructure:"listen"`
+	AuditFile string       `mapstructure:"audit_file"`
+	TLS       *tlsConfig   `mapstructure:"tls"`
+	Insecure  bool         `mapstructure:"insecure"`
+	Keys      []keyConfig  `mapstructure:"keys"`
+	Policy    policyConfig `mapstructure:"policy"`
+}
+
+type tlsConfig struct {
+	CertFile string   `mapstructure:"cert_file"`
+	KeyFile  string   `mapstructure:"key_file"`
+	CaPaths  []string `mapstructure:"ca_paths"`
+}
+
+type keyConfig struct {
+	Id       string `mapstructure:"id"`
+	OrgId    string `mapstructure:"org_id"`
+	KeyFile  string `mapstructure:"key_file"`
+	KeyPwd   string `mapstructure:"key_pwd"`
+	CertFile string `mapstructure:"cert_file"`
+	Hash     string `mapstructure:"hash"`
+}
+
+// contracts and value limits are lists, viper lowercases map keys
+type policyConfig struct {
+	Contracts   []contractConfig   `mapstructure:"contracts"`
+	ValueLimits []valueLimitConfig `mapstructure:"value_limits"`
+	AllowQuery  bool               `mapstructure:"allow_query"`
+	RateLimit   float64            `mapstructure:"rate_limit"`
+	RateBurst   int                `mapstructure:"rate_burst"`
+}
+
+type contractConfig struct {
+	Name    string   `mapstructure:"name"`
+	Methods []string `mapstructure:"methods"`
+}
+
+type valueLimitConfig struct {
+	Key string `mapstructure:"key"`
+	Max string `mapstructure:"max"`
+}
+
+func main() {
+	confPath := flag.String("conf", "./signerd.yml", "signerd config file path")
+	flag.Parse()
+
+	conf, err := loadConfig(*confPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// the daemon signs for anyone who can reach it, clients must authenticate by tls certs
+	if conf.TLS == nil && !conf.Insecure {
+		log.Fatalln("tls is not configured, set insecure to serve without tls")
+	}
+	if conf.TLS != nil && len(conf.TLS.CaPaths) == 0 {
+		log.Fatalln("tls ca_paths are required to authenticate clients")
+	}
+
+	var keys []*remotesigner.Key
+	for _, k := range conf.Keys {
+		key, err := remotesigner.LoadKey(k.Id, k.OrgId, k.KeyFile, k.KeyPwd, k.CertFile, k.Hash)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		keys = append(keys, key)
+	}
+
+	policy, err := newPolicy(&conf.Policy)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	auditor, err := remotesigner.NewFileAuditor(conf.AuditFile)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer auditor.Close()
+
+	logger, _ := cmlog.InitSugarLogger(&cmlog.LogConfig{
+		Module:       "[SIGNERD]",
+		LogPath:      "./signerd.log",
+		LogLevel:     cmlog.LEVEL_INFO,
+		MaxAge:       30,
+		ShowLine:     true,
+		LogInConsole: true,
+	})
+	server, err := remotesigner.NewServer(keys, auditor, logger, policy.Approve)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	var opts []grpc.ServerOption
+	if conf.TLS != nil {
+		tlsServer := ca.CAServer{
+			CaPaths:  conf.TLS.CaPaths,
+			CertFile: conf.TLS.CertFile,
+			KeyFile:  conf.TLS.KeyFile,
+		}
+		// clients must present certs issued by the cas
+		creds, err := tlsServer.GetCredentialsByCA(true, ca.CustomVerify{
+			VerifyPeerCertificate:   nil,
+			GMVerifyPeerCertificate: nil,
+		})
+		if err != nil {
+			log.Fatalln(err)
+		}
+		opts = append(opts, grpc.Creds(*creds))
+	}
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterRemoteSignerServer(grpcServer, server)
+
+	listener, err := net.Listen("tcp", conf.Listen)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	go func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+		<-ch
+		grpcServer.GracefulStop()
+	}()
+
+	log.Printf("signerd listening on %s with %d keys", conf.Listen, len(keys))
+	if err = grpcServer.Serve(listener); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func loadConfig(path string) (*signerdConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	conf := &signerdConfig{}
+	if err := v.Unmarshal(conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func newPolicy(conf *policyConfig) (*remotesigner.Policy, error) {
+	policy := &remotesigner.Policy{
+		Contracts:   make(map[string][]string, len(conf.Contracts)),
+		ValueLimits: make(map[string]*big.Int, len(conf.ValueLimits)),
+		AllowQuery:  conf.AllowQuery,
+		RateLimit:   conf.RateLimit,
+		RateBurst:   conf.RateBurst,
+	}
+	for _, contract := range conf.Contracts {
+		policy.Contracts[contract.Name] = contract.Methods
+	}
+	for _, limit := range conf.ValueLimits {
+		max, ok := new(big.Int).SetString(limit.Max, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid value limit of [%s]: %s", limit.Key, limit.Max)
+		}
+		policy.ValueLimits[limit.Key] = max
+	}
+	return policy, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditRecord audit log entry of a sign request
+type AuditRecord struct {
+	Time         time.Time `json:"time"`
+	KeyId        string    `json:"key_id"`
+	Peer         string    `json:"peer"`
+	ChainId      string    `json:"chain_id"`
+	TxId         string    `json:"tx_id"`
+	TxType       string    `json:"tx_type"`
+	ContractName string    `json:"contract_name"`
+	Method       string    `json:"method"`
+	Approved     bool      `json:"approved"`
+	// Reason why the request is rejected
+	Reason string `json:"reason,omitempty"`
+}
+
+// Auditor writes audit records, requests are not signed if their records can not be written
+type Auditor interface {
+	Audit(record *AuditRecord) error
+}
+
+// FileAuditor writes audit records to a file in json lines
+type FileAuditor struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditor create auditor appending to file of path
+func NewFileAuditor(path string) (*FileAuditor, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditor{file: file}, nil
+}
+
+// Audit write record and sync it to disk
+func (a *FileAuditor) Audit(record *AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err = a.file.Write(line); err != nil {
+		return err
+	}
+	return a.file.Sync()
+}
+
+// Close close the audit file
+func (a *FileAuditor) Close() error {
+	return a.file.Close()
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remotesigner implements a signing daemon for sdk-go RemoteSigner.
+//
+// The daemon holds the private keys, so application servers never do. Every sign request carries the
+// marshaled tx payload, which is checked by the approval hooks of the daemon, such as Policy, and written
+// to the audit log before it is signed.
+package remotesigner
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	"chainmaker.org/chainmaker/common/v2/crypto/asym"
+	bcx509 "chainmaker.org/chainmaker/common/v2/crypto/x509"
+	"chainmaker.org/chainmaker/sdk-go/v2/remotesigner/pb"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+)
+
+// Key signing key held by the daemon
+type Key struct {
+	// Id key id used by clients to select the key
+	Id    string
+	OrgId string
+
+	PrivateKey crypto.PrivateKey
+	// CertPEM cert of key in PermissionedWithCert mode, empty in PermissionedWithKey and Public mode
+	CertPEM []byte
+	// HashType hash algorithm name such as SHA256, the hash of cert is used when CertPEM is set
+	HashType string
+}
+
+// LoadKey load key from pem files, certFilePath is empty in PermissionedWithKey and Public mode
+func LoadKey(id, orgId, keyFilePath, keyPwd, certFilePath, hashType string) (*Key, error) {
+	keyPEM, err := ioutil.ReadFile(keyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read key file failed, %s", err)
+	}
+	var pwd []byte
+	if keyPwd != "" {
+		pwd = []byte(keyPwd)
+	}
+	privateKey, err := asym.PrivateKeyFromPEM(keyPEM, pwd)
+	if err != nil {
+		return nil, fmt.Errorf("parse key [%s] failed, %s", id, err)
+	}
+
+	key := &Key{
+		Id:         id,
+		OrgId:      orgId,
+		PrivateKey: privateKey,
+		HashType:   hashType,
+	}
+	if certFilePath != "" {
+		if key.CertPEM, err = ioutil.ReadFile(certFilePath); err != nil {
+			return nil, fmt.Errorf("read cert file failed, %s", err)
+		}
+	}
+	if err = key.init(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// init takes the hash type from cert and checks the key
+func (k *Key) init() error {
+	if k.Id == "" || k.PrivateKey == nil {
+		return fmt.Errorf("key id and private key must not be empty")
+	}
+	if len(k.CertPEM) == 0 {
+		if _, ok := crypto.HashAlgoMap[k.HashType]; !ok {
+			return fmt.Errorf("invalid hash type [%s] of key [%s]", k.HashType, k.Id)
+		}
+		return nil
+	}
+
+	cert, err := utils.ParseCert(k.CertPEM)
+	if err != nil {
+		return err
+	}
+	hashType, err := bcx509.GetHashFromSignatureAlgorithm(cert.SignatureAlgorithm)
+	if err != nil {
+		return fmt.Errorf("invalid algorithm: %v", err)
+	}
+	if k.HashType = HashTypeName(hashType); k.HashType == "" {
+		return fmt.Errorf("unknown hash type of cert of key [%s]", k.Id)
+	}
+	return nil
+}
+
+func (k *Key) identity() (*pb.Identity, error) {
+	publicKeyPEM, err := k.PrivateKey.PublicKey().String()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Identity{
+		KeyId:        k.Id,
+		OrgId:        k.OrgId,
+		CertPem:      k.CertPEM,
+		PublicKeyPem: []byte(publicKeyPEM),
+		HashType:     k.HashType,
+	}, nil
+}
+
+// HashTypeName returns the name of hashType in crypto.HashAlgoMap, empty if unknown
+func HashTypeName(hashType crypto.HashType) string {
+	for name, t := range crypto.HashAlgoMap {
+		if t == hashType {
+			return name
+		}
+	}
+	return ""
+}
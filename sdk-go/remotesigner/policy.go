@@ -0,0 +1,165 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+)
+
+// AnyName matches any contract or method in Policy.Contracts
+const AnyName = "*"
+
+// evmParamKey parameter key of the abi encoded calldata of evm txs, same as protocol.ContractEvmParamKey
+const evmParamKey = "data"
+
+// SignContext sign request passed to approval hooks
+type SignContext struct {
+	// KeyId id of the key asked to sign
+	KeyId string
+	// Peer address of the requesting client
+	Peer    string
+	Payload *common.Payload
+}
+
+// ApprovalHook approves a sign request before it is signed, a non nil error rejects the request
+type ApprovalHook func(ctx context.Context, req *SignContext) error
+
+// Policy approval policy of sign requests
+type Policy struct {
+	// Contracts allowed methods by contract name, AnyName matches any contract or method.
+	// All contracts are allowed when empty
+	Contracts map[string][]string
+	// ValueLimits max values of tx parameters by parameter key, the parameters are decimal integers.
+	// The values abi encoded in evm calldata can not be checked, evm txs are rejected when set
+	ValueLimits map[string]*big.Int
+	// AllowQuery approves query and subscribe payloads without checking contracts
+	AllowQuery bool
+	// RateLimit max tx signatures per second of each key, queries are not limited.
+	// Unlimited when RateLimit <= 0
+	RateLimit float64
+	// RateBurst max tx signatures of each key in a burst, RateLimit is used when RateBurst <= 0
+	RateBurst int
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// Approve approves req by policy, it is an ApprovalHook
+func (p *Policy) Approve(ctx context.Context, req *SignContext) error {
+	payload := req.Payload
+	if isQuery(payload.TxType) {
+		if p.AllowQuery {
+			return nil
+		}
+	}
+
+	if err := p.checkContract(payload.ContractName, payload.Method); err != nil {
+		return err
+	}
+	if err := p.checkValues(payload.Parameters); err != nil {
+		return err
+	}
+	if !isQuery(payload.TxType) && !p.allow(req.KeyId, time.Now()) {
+		return fmt.Errorf("rate limit of key [%s] exceeded", req.KeyId)
+	}
+	return nil
+}
+
+func (p *Policy) checkContract(contractName, method string) error {
+	if len(p.Contracts) == 0 {
+		return nil
+	}
+	methods, ok := p.Contracts[contractName]
+	if !ok {
+		methods, ok = p.Contracts[AnyName]
+	}
+	if !ok {
+		return fmt.Errorf("contract [%s] is not allowed", contractName)
+	}
+	for _, m := range methods {
+		if m == method || m == AnyName {
+			return nil
+		}
+	}
+	return fmt.Errorf("method [%s] of contract [%s] is not allowed", method, contractName)
+}
+
+func (p *Policy) checkValues(parameters []*common.KeyValuePair) error {
+	if len(p.ValueLimits) == 0 {
+		return nil
+	}
+	for _, kv := range parameters {
+		if kv.Key == evmParamKey {
+			return errors.New("evm calldata can not be checked against value limits")
+		}
+		limit, ok := p.ValueLimits[kv.Key]
+		if !ok {
+			continue
+		}
+		value, ok := new(big.Int).SetString(string(kv.Value), 10)
+		if !ok {
+			return fmt.Errorf("parameter [%s] is not a decimal integer", kv.Key)
+		}
+		if value.Cmp(limit) > 0 {
+			return fmt.Errorf("parameter [%s] value %s exceeds limit %s", kv.Key, value, limit)
+		}
+	}
+	return nil
+}
+
+// allow takes a token from the bucket of key
+func (p *Policy) allow(keyId string, now time.Time) bool {
+	if p.RateLimit <= 0 {
+		return true
+	}
+	burst := float64(p.RateBurst)
+	if burst <= 0 {
+		burst = p.RateLimit
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.buckets == nil {
+		p.buckets = make(map[string]*tokenBucket)
+	}
+	bucket, ok := p.buckets[keyId]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		p.buckets[keyId] = bucket
+	}
+	return bucket.take(now, p.RateLimit, burst)
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(now time.Time, rate, burst float64) bool {
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func isQuery(txType common.TxType) bool {
+	return txType == common.TxType_QUERY_CONTRACT || txType == common.TxType_SUBSCRIBE
+}
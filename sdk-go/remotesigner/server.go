@@ -0,0 +1,143 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/sdk-go/v2/remotesigner/pb"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Server signing daemon serving pb.RemoteSignerServer
+type Server struct {
+	keys    map[string]*Key
+	auditor Auditor
+	hooks   []ApprovalHook
+	logger  utils.Logger
+}
+
+var _ pb.RemoteSignerServer = (*Server)(nil)
+
+// NewServer create signing daemon holding keys, sign requests are approved by hooks in order
+// and written to auditor before signing
+func NewServer(keys []*Key, auditor Auditor, logger utils.Logger, hooks ...ApprovalHook) (*Server, error) {
+	if auditor == nil {
+		return nil, fmt.Errorf("auditor must not be nil")
+	}
+	s := &Server{
+		keys:    make(map[string]*Key, len(keys)),
+		auditor: auditor,
+		hooks:   hooks,
+		logger:  logger,
+	}
+	for _, key := range keys {
+		if err := key.init(); err != nil {
+			return nil, err
+		}
+		if _, ok := s.keys[key.Id]; ok {
+			return nil, fmt.Errorf("duplicate key id [%s]", key.Id)
+		}
+		s.keys[key.Id] = key
+	}
+	return s, nil
+}
+
+// GetIdentity returns the identity of key
+func (s *Server) GetIdentity(ctx context.Context, req *pb.GetIdentityRequest) (*pb.Identity, error) {
+	key, ok := s.keys[req.KeyId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "key [%s] not found", req.KeyId)
+	}
+	identity, err := key.identity()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return identity, nil
+}
+
+// Sign approve, audit and sign the payload of req
+func (s *Server) Sign(ctx context.Context, req *pb.SignRequest) (*pb.SignResponse, error) {
+	key, ok := s.keys[req.KeyId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "key [%s] not found", req.KeyId)
+	}
+	hashType := crypto.HashAlgoMap[key.HashType]
+	if req.HashType != "" && crypto.HashAlgoMap[req.HashType] != hashType {
+		return nil, status.Errorf(codes.InvalidArgument, "key [%s] signs with %s, got %s",
+			key.Id, key.HashType, req.HashType)
+	}
+
+	// the approved payload must be exactly the signed bytes
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(req.Payload, payload); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unmarshal payload failed, %s", err)
+	}
+	canonical, err := proto.Marshal(payload)
+	if err != nil || !bytes.Equal(canonical, req.Payload) {
+		return nil, status.Error(codes.InvalidArgument, "payload is not canonically marshaled")
+	}
+
+	signCtx := &SignContext{
+		KeyId:   key.Id,
+		Payload: payload,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		signCtx.Peer = p.Addr.String()
+	}
+	record := &AuditRecord{
+		Time:         time.Now(),
+		KeyId:        key.Id,
+		Peer:         signCtx.Peer,
+		ChainId:      payload.ChainId,
+		TxId:         payload.TxId,
+		TxType:       payload.TxType.String(),
+		ContractName: payload.ContractName,
+		Method:       payload.Method,
+	}
+
+	for _, hook := range s.hooks {
+		if err = hook(ctx, signCtx); err != nil {
+			record.Reason = err.Error()
+			if auditErr := s.auditor.Audit(record); auditErr != nil {
+				s.logger.Errorf("[RemoteSigner] write audit record of tx [%s] failed, %s", payload.TxId, auditErr)
+			}
+			s.logger.Warnf("[RemoteSigner] reject tx [%s] of [%s], %s", payload.TxId, signCtx.Peer, err)
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+
+	record.Approved = true
+	if err = s.auditor.Audit(record); err != nil {
+		s.logger.Errorf("[RemoteSigner] write audit record of tx [%s] failed, %s", payload.TxId, err)
+		return nil, status.Error(codes.Internal, "write audit record failed")
+	}
+
+	uid := req.Uid
+	if uid == "" {
+		uid = crypto.CRYPTO_DEFAULT_UID
+	}
+	signature, err := key.PrivateKey.SignWithOpts(req.Payload, &crypto.SignOpts{
+		Hash: hashType,
+		UID:  uid,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "sign failed, %s", err)
+	}
+	s.logger.Debugf("[RemoteSigner] signed tx [%s] of [%s] with key [%s]", payload.TxId, signCtx.Peer, key.Id)
+	return &pb.SignResponse{Signature: signature}, nil
+}
@@ -69,6 +69,9 @@ type ChainClient struct {
 	// kms config
 	kmsConfig *KMSConfig
 
+	// remote signer holding the private key
+	remoteSigner *RemoteSigner
+
 	hashType crypto.HashType
 	authType AuthType
 	// retry config
@@ -211,6 +214,9 @@ func NewChainClient(opts ...ChainClientOption) (*ChainClient, error) {
 
 	pool, err := NewConnPool(config)
 	if err != nil {
+		if config.remoteSigner != nil {
+			_ = config.remoteSigner.Close()
+		}
 		return nil, err
 	}
 
@@ -248,6 +254,7 @@ func NewChainClient(opts ...ChainClientOption) (*ChainClient, error) {
 		rpcClientConfig: config.rpcClientConfig,
 		pkcs11Config:    config.pkcs11Config,
 		kmsConfig:       config.kmsConfig,
+		remoteSigner:    config.remoteSigner,
 
 		publicKey: publicKey,
 		hashType:  hashType,
@@ -287,6 +294,15 @@ func NewChainClient(opts ...ChainClientOption) (*ChainClient, error) {
 		}
 	}
 
+	// 文件归档在创建时打开，以便返回目录或bucket的错误，而不是在 GetArchiveService 中 panic
+	if isFileArchive(config.archiveConfig) {
+		archiveService, err := NewArchiveFileClient(config.chainId, config.archiveConfig, cc.logger)
+		if err != nil {
+			return nil, fmt.Errorf("open %s archive failed, %s", config.archiveConfig.archiveType, err)
+		}
+		cc.archiveService = archiveService
+	}
+
 	return cc, nil
 }
 
@@ -316,6 +332,12 @@ func (cc *ChainClient) GetArchiveService() ArchiveService {
 	return cc.archiveService
 }
 
+// GetConnPoolStats 获取连接池中各节点的健康状态
+// @return []*NodeStats
+func (cc *ChainClient) GetConnPoolStats() []*NodeStats {
+	return cc.pool.Stats()
+}
+
 // IsEnableNormalKey whether to use normal key
 func (cc *ChainClient) IsEnableNormalKey() bool {
 	return cc.enableNormalKey
@@ -337,6 +359,11 @@ func (cc *ChainClient) Stop() error {
 	for _, pool := range cc.canonicalTxFetcherPools {
 		pool.Close()
 	}
+	if cc.remoteSigner != nil {
+		if err := cc.remoteSigner.Close(); err != nil {
+			cc.logger.Errorf("[SDK] close remote signer failed, %s", err)
+		}
+	}
 	return cc.pool.Close()
 }
 
@@ -14,6 +14,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"chainmaker.org/chainmaker/common/v2/kmsutils"
 
@@ -319,12 +320,19 @@ type ChainClientConfig struct {
 	// rpc客户端设置
 	rpcClientConfig *RPCClientConfig
 
+	// 连接池负载均衡和熔断设置
+	connPoolConfig *ConnPoolConfig
+
 	// pkcs11的配置
 	pkcs11Config *Pkcs11Config
 
 	// kms配置
 	kmsConfig *KMSConfig
 
+	// 远程签名服务配置，设置后私钥由签名服务持有
+	remoteSignerConfig *RemoteSignerConfig
+	remoteSigner       *RemoteSigner
+
 	// AuthType
 	authType AuthType
 
@@ -520,6 +528,13 @@ func WithRPCClientConfig(conf *RPCClientConfig) ChainClientOption {
 	}
 }
 
+// WithConnPoolConfig 设置连接池负载均衡和熔断配置
+func WithConnPoolConfig(conf *ConnPoolConfig) ChainClientOption {
+	return func(config *ChainClientConfig) {
+		config.connPoolConfig = conf
+	}
+}
+
 // WithPkcs11Config 设置pkcs11配置
 func WithPkcs11Config(conf *Pkcs11Config) ChainClientOption {
 	return func(config *ChainClientConfig) {
@@ -536,6 +551,13 @@ func WithKMSConfig(conf *KMSConfig) ChainClientOption {
 	}
 }
 
+// WithRemoteSignerConfig 设置远程签名服务配置，设置后交易由签名服务签名，无需配置用户签名私钥
+func WithRemoteSignerConfig(conf *RemoteSignerConfig) ChainClientOption {
+	return func(config *ChainClientConfig) {
+		config.remoteSignerConfig = conf
+	}
+}
+
 // WithCryptoConfig 设置crypto配置
 func WithCryptoConfig(conf *CryptoConfig) ChainClientOption {
 	return func(config *ChainClientConfig) {
@@ -771,6 +793,27 @@ func setRPCClientConfig(config *ChainClientConfig) {
 	}
 }
 
+func setConnPoolConfig(config *ChainClientConfig) error {
+	model := config.ConfigModel.ChainClientConfig.ConnPoolConfig
+	if model == nil || config.connPoolConfig != nil {
+		return nil
+	}
+	opts := []ConnPoolOption{
+		WithConnPoolFailureThreshold(model.FailureThreshold),
+		WithConnPoolOpenTimeout(time.Duration(model.OpenTimeout) * time.Second),
+		WithConnPoolHealthCheckInterval(time.Duration(model.HealthCheckInterval) * time.Second),
+	}
+	if model.BalancePolicy != "" {
+		policy, err := GetBalancePolicy(model.BalancePolicy)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, WithConnPoolBalancePolicy(policy))
+	}
+	config.connPoolConfig = NewConnPoolConfig(opts...)
+	return nil
+}
+
 func setPkcs11Config(config *ChainClientConfig) {
 	if config.authType == PermissionedWithCert {
 		if config.ConfigModel.ChainClientConfig.Pkcs11Config != nil && config.pkcs11Config == nil {
@@ -907,6 +950,10 @@ func readConfigFile(config *ChainClientConfig) error {
 
 	setRPCClientConfig(config)
 
+	if err = setConnPoolConfig(config); err != nil {
+		return err
+	}
+
 	setPkcs11Config(config)
 
 	setKMSConfig(config)
@@ -1000,6 +1047,11 @@ func checkNodeListConfig(config *ChainClientConfig) error {
 }
 
 func checkUserConfig(config *ChainClientConfig) error {
+	// 私钥由远程签名服务持有
+	if config.remoteSignerConfig != nil {
+		return nil
+	}
+
 	if config.authType == PermissionedWithCert {
 		// 用户私钥不可为空
 		if config.userKeyFilePath == "" && config.userKeyBytes == nil {
@@ -1021,8 +1073,8 @@ func checkUserConfig(config *ChainClientConfig) error {
 
 func checkChainConfig(config *ChainClientConfig) error {
 	if config.authType == PermissionedWithCert || config.authType == PermissionedWithKey {
-		// OrgId不可为空
-		if config.orgId == "" {
+		// OrgId不可为空，使用远程签名服务时默认为签名密钥的组织
+		if config.orgId == "" && config.remoteSignerConfig == nil {
 			return fmt.Errorf("orgId cannot be empty")
 		}
 	}
@@ -1153,6 +1205,10 @@ func dealConfig(config *ChainClientConfig) error {
 		return err
 	}
 
+	if config.remoteSignerConfig != nil {
+		return dealRemoteSignerConfig(config)
+	}
+
 	// PermissionedWithKey & Public
 	if config.authType == PermissionedWithKey || config.authType == Public {
 		return dealUserSignKeyConfig(config)
@@ -1168,6 +1224,42 @@ func dealConfig(config *ChainClientConfig) error {
 	return dealUserSignKeyConfig(config)
 }
 
+func dealRemoteSignerConfig(config *ChainClientConfig) (err error) {
+	if config.authType == PermissionedWithCert {
+		//gmtls enc key/cert set
+		_ = dealUserEncCrtKeyConfig(config)
+	}
+
+	if config.remoteSignerConfig.logger == nil {
+		config.remoteSignerConfig.logger = config.logger
+	}
+	signer, err := NewRemoteSigner(config.remoteSignerConfig)
+	if err != nil {
+		return fmt.Errorf("new remote signer failed, %s", err)
+	}
+
+	if config.authType == PermissionedWithCert {
+		if signer.Cert() == nil {
+			_ = signer.Close()
+			return fmt.Errorf("remote signer key [%s] has no cert", config.remoteSignerConfig.keyId)
+		}
+		config.userCrt = signer.Cert()
+		config.userSignCrtBytes = signer.identity.CertPem
+	} else if config.crypto == nil {
+		config.crypto = &CryptoConfig{hash: signer.identity.HashType}
+	} else if config.crypto.hash == "" {
+		config.crypto.hash = signer.identity.HashType
+	}
+	if config.orgId == "" {
+		config.orgId = signer.OrgId()
+	}
+
+	config.remoteSigner = signer
+	config.privateKey = signer.PrivateKey()
+	config.userPk = config.privateKey.PublicKey()
+	return nil
+}
+
 func dealUserCrtConfig(config *ChainClientConfig) (err error) {
 	if config.userCrtFilePath == "" {
 		return nil
@@ -0,0 +1,244 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+)
+
+// eventSchemasMethod query method answered by contract-sdk-go sandbox with the event schemas of contract
+const eventSchemasMethod = "__event_schemas__"
+
+// event field types, same with contract-sdk-go
+const (
+	EventFieldString  = "string"
+	EventFieldAddress = "address"
+	EventFieldBool    = "bool"
+	EventFieldInt     = "int"
+	EventFieldUint    = "uint"
+	EventFieldUint256 = "uint256"
+	EventFieldBytes   = "bytes"
+	EventFieldJSON    = "json"
+)
+
+// EventField one field of contract event
+type EventField struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed,omitempty"`
+}
+
+// EventSchema describes a contract event, the event topic is the schema name
+// and the event data are the fields encoded in declared order
+type EventSchema struct {
+	Name   string        `json:"name"`
+	Fields []*EventField `json:"fields"`
+}
+
+// EventSchemas event schemas of a contract, keyed by event name
+type EventSchemas map[string]*EventSchema
+
+// GetContractEventSchemas get the event schemas a contract saved on chain when it was installed or upgraded
+func (cc *ChainClient) GetContractEventSchemas(contractName string) (EventSchemas, error) {
+	cc.logger.Debugf("[SDK] begin to QUERY event schemas, [contractName:%s]", contractName)
+
+	resp, err := cc.QueryContract(contractName, eventSchemasMethod, nil, -1)
+	if err != nil {
+		return nil, err
+	}
+	if err = utils.CheckProposalRequestResp(resp, true); err != nil {
+		return nil, fmt.Errorf("query event schemas of contract %s failed, %s", contractName, err)
+	}
+
+	var schemas []*EventSchema
+	if err = json.Unmarshal(resp.ContractResult.Result, &schemas); err != nil {
+		return nil, fmt.Errorf("unmarshal event schemas failed, %s", err)
+	}
+
+	eventSchemas := make(EventSchemas, len(schemas))
+	for _, schema := range schemas {
+		eventSchemas[schema.Name] = schema
+	}
+	return eventSchemas, nil
+}
+
+// Decode decode the event data into a map of field name to typed value,
+// uint256 fields are decoded as *big.Int, bytes fields as []byte and json fields as interface{}
+func (s EventSchemas) Decode(event *common.ContractEventInfo) (map[string]interface{}, error) {
+	schema, err := s.schemaOf(event)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{}, len(schema.Fields))
+	for i, field := range schema.Fields {
+		value, err := decodeEventField(field.Type, event.EventData[i])
+		if err != nil {
+			return nil, fmt.Errorf("event [%s] field [%s]: %s", schema.Name, field.Name, err)
+		}
+		fields[field.Name] = value
+	}
+	return fields, nil
+}
+
+// Unmarshal decode the event data into the struct pointed by v, a struct field receives the event field
+// named by its `event` tag, or the event field with the same name regardless of case
+func (s EventSchemas) Unmarshal(event *common.ContractEventInfo, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("event can only be unmarshaled into a non-nil struct pointer")
+	}
+	schema, err := s.schemaOf(event)
+	if err != nil {
+		return err
+	}
+
+	rv = rv.Elem()
+	for i, field := range schema.Fields {
+		sf, ok := structFieldOf(rv, field.Name)
+		if !ok {
+			continue
+		}
+		if err = setEventField(sf, field.Type, event.EventData[i]); err != nil {
+			return fmt.Errorf("event [%s] field [%s]: %s", schema.Name, field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (s EventSchemas) schemaOf(event *common.ContractEventInfo) (*EventSchema, error) {
+	if event == nil {
+		return nil, errors.New("event is nil")
+	}
+	schema, ok := s[event.Topic]
+	if !ok {
+		return nil, fmt.Errorf("no schema for event [%s] of contract [%s]", event.Topic, event.ContractName)
+	}
+	if len(event.EventData) != len(schema.Fields) {
+		return nil, fmt.Errorf("event [%s] expects %d fields, got %d",
+			schema.Name, len(schema.Fields), len(event.EventData))
+	}
+	return schema, nil
+}
+
+func structFieldOf(rv reflect.Value, name string) (reflect.Value, bool) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := sf.Tag.Get("event")
+		if tag == "-" {
+			continue
+		}
+		if tag == name || (tag == "" && strings.EqualFold(sf.Name, name)) {
+			return rv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func decodeEventField(fieldType, data string) (interface{}, error) {
+	switch fieldType {
+	case EventFieldString, EventFieldAddress:
+		return data, nil
+	case EventFieldBool:
+		return strconv.ParseBool(data)
+	case EventFieldInt:
+		return strconv.ParseInt(data, 10, 64)
+	case EventFieldUint:
+		return strconv.ParseUint(data, 10, 64)
+	case EventFieldUint256:
+		n, ok := new(big.Int).SetString(data, 10)
+		if !ok {
+			return nil, fmt.Errorf("[%s] is not a valid %s", data, fieldType)
+		}
+		return n, nil
+	case EventFieldBytes:
+		return hex.DecodeString(data)
+	case EventFieldJSON:
+		var v interface{}
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unknown field type [%s]", fieldType)
+	}
+}
+
+// nolint: gocyclo
+func setEventField(sf reflect.Value, fieldType, data string) error {
+	// json fields and struct values are decoded by json directly
+	if fieldType == EventFieldJSON {
+		return json.Unmarshal([]byte(data), sf.Addr().Interface())
+	}
+
+	value, err := decodeEventField(fieldType, data)
+	if err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case *big.Int:
+		switch sf.Interface().(type) {
+		case *big.Int:
+			sf.Set(reflect.ValueOf(v))
+			return nil
+		case big.Int:
+			sf.Set(reflect.ValueOf(*v))
+			return nil
+		}
+	case []byte:
+		if sf.Kind() == reflect.Slice && sf.Type().Elem().Kind() == reflect.Uint8 {
+			sf.SetBytes(v)
+			return nil
+		}
+	case int64:
+		switch sf.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if sf.OverflowInt(v) {
+				return fmt.Errorf("%d overflows %s", v, sf.Type())
+			}
+			sf.SetInt(v)
+			return nil
+		}
+	case uint64:
+		switch sf.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if sf.OverflowUint(v) {
+				return fmt.Errorf("%d overflows %s", v, sf.Type())
+			}
+			sf.SetUint(v)
+			return nil
+		}
+	case bool:
+		if sf.Kind() == reflect.Bool {
+			sf.SetBool(v)
+			return nil
+		}
+	}
+
+	// any field can receive the raw data as string
+	if sf.Kind() == reflect.String {
+		sf.SetString(data)
+		return nil
+	}
+	return fmt.Errorf("can not decode %s into %s", fieldType, sf.Type())
+}
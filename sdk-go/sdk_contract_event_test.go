@@ -0,0 +1,64 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"math/big"
+	"testing"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSchemasDecode(t *testing.T) {
+	schemas := EventSchemas{
+		"Transfer": {
+			Name: "Transfer",
+			Fields: []*EventField{
+				{Name: "from", Type: EventFieldAddress, Indexed: true},
+				{Name: "to", Type: EventFieldAddress, Indexed: true},
+				{Name: "tokenId", Type: EventFieldUint256},
+				{Name: "approved", Type: EventFieldBool},
+				{Name: "data", Type: EventFieldBytes},
+				{Name: "ids", Type: EventFieldJSON},
+			},
+		},
+	}
+	event := &common.ContractEventInfo{
+		Topic:        "Transfer",
+		ContractName: "erc721",
+		EventData:    []string{"a1", "b2", "111111111111111111111112", "true", "0102", `["1","2"]`},
+	}
+
+	fields, err := schemas.Decode(event)
+	require.Nil(t, err)
+	require.Equal(t, "a1", fields["from"])
+	require.Equal(t, "111111111111111111111112", fields["tokenId"].(*big.Int).String())
+	require.Equal(t, true, fields["approved"])
+	require.Equal(t, []byte{1, 2}, fields["data"])
+
+	var transfer struct {
+		From     string
+		Receiver string `event:"to"`
+		TokenId  *big.Int
+		Approved bool
+		Data     []byte
+		Ids      []string
+	}
+	require.Nil(t, schemas.Unmarshal(event, &transfer))
+	require.Equal(t, "a1", transfer.From)
+	require.Equal(t, "b2", transfer.Receiver)
+	require.Equal(t, "111111111111111111111112", transfer.TokenId.String())
+	require.True(t, transfer.Approved)
+	require.Equal(t, []byte{1, 2}, transfer.Data)
+	require.Equal(t, []string{"1", "2"}, transfer.Ids)
+
+	_, err = schemas.Decode(&common.ContractEventInfo{Topic: "Unknown"})
+	require.NotNil(t, err)
+	_, err = schemas.Decode(&common.ContractEventInfo{Topic: "Transfer", EventData: []string{"a1"}})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,650 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	"chainmaker.org/chainmaker/common/v2/crypto/hash"
+	bcx509 "chainmaker.org/chainmaker/common/v2/crypto/x509"
+	"chainmaker.org/chainmaker/common/v2/helper"
+	"chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/config"
+	"chainmaker.org/chainmaker/pb-go/v2/consensus"
+	tbftpb "chainmaker.org/chainmaker/pb-go/v2/consensus/tbft"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+	cmutils "chainmaker.org/chainmaker/utils/v2"
+	"github.com/gogo/protobuf/proto"
+)
+
+const (
+	// tbftAdditionalDataKey key of the tbft precommit vote set in block additional data,
+	// same as protocol.TBFTAddtionalDataKey
+	tbftAdditionalDataKey = "TBFTAddtionalDataKey"
+	// lightClientTruncateModel blocks fetched for tx proofs only need the header and additional data
+	lightClientTruncateModel = "empty"
+)
+
+var (
+	// ErrBlockBodyUnverified the block is returned without all its txs, its tx root and rwsets are not verified
+	ErrBlockBodyUnverified = errors.New("block body is not verified")
+	// ErrConsensusUnsupported the chain does not run tbft, blocks can not be verified without a quorum
+	ErrConsensusUnsupported = errors.New("consensus type is not supported by light client")
+)
+
+// LightClient verifies the blocks and txs returned by nodes instead of trusting them.
+// It tracks the consensus nodes from config blocks, starting from a trusted chain config, and checks
+// block hashes, proposer signatures, tbft precommit quorums, tx merkle paths and rwset hashes.
+// Only cert members and tbft chains are supported, blocks signed by public key members or of other
+// consensus types are never verified, neither are blocks returned without all their txs.
+// Of the subscriptions only SubscribeBlock is verified, txs and contract events received from the
+// subscriptions of ChainClient are not, verify them with GetTxByTxId.
+type LightClient struct {
+	cc *ChainClient
+	// signers sign certs of the consensus nodes by node id, set by WithConsensusSigners
+	signers map[string]*bcx509.Certificate
+
+	mu sync.Mutex
+	// configHeight height of the latest verified config block
+	configHeight uint64
+	// configs verified chain configs by config block height
+	configs map[uint64]*config.ChainConfig
+}
+
+// LightClientOption define light client option func
+type LightClientOption func(*LightClient)
+
+// WithTrustedChainConfig trust chainConfig written in config block of height,
+// without it the light client trusts the last config block returned by node
+func WithTrustedChainConfig(height uint64, chainConfig *config.ChainConfig) LightClientOption {
+	return func(lc *LightClient) {
+		lc.configHeight = height
+		lc.configs[height] = chainConfig
+	}
+}
+
+// WithConsensusSigners binds the consensus nodes to the sign certs their tbft votes are signed with,
+// signers are PEM certs by node id. Without it a vote counts only if the node id of its voter is derived
+// from the public key of the signer cert, as node ids are derived from node tls certs, nodes signing
+// consensus messages with a separate sign cert need it.
+func WithConsensusSigners(signers map[string][]byte) LightClientOption {
+	return func(lc *LightClient) {
+		lc.signers = make(map[string]*bcx509.Certificate, len(signers))
+		for nodeId, certPEM := range signers {
+			// a cert failing to parse is kept as nil, the votes of the node never count
+			cert, _ := utils.ParseCert(certPEM)
+			lc.signers[nodeId] = cert
+		}
+	}
+}
+
+// VerifiedBlockInfo block info with the light client verification result
+type VerifiedBlockInfo struct {
+	*common.BlockInfo
+	// Verified whether block header, quorum and tx/rwset roots are verified
+	Verified bool
+	// VerifyErr reason of verification failure, ErrBlockBodyUnverified or ErrConsensusUnsupported
+	// if the block could not be verified
+	VerifyErr error
+}
+
+// VerifiedTxInfo tx info with the light client verification result
+type VerifiedTxInfo struct {
+	*common.TransactionInfoWithRWSet
+	// Verified whether the block header, tx inclusion and rwset hash are verified
+	Verified bool
+	// VerifyErr reason of verification failure
+	VerifyErr error
+}
+
+// NewLightClient new light client verifying the results of cc
+func NewLightClient(cc *ChainClient, opts ...LightClientOption) (*LightClient, error) {
+	lc := &LightClient{
+		cc:      cc,
+		configs: make(map[uint64]*config.ChainConfig),
+	}
+	for _, opt := range opts {
+		opt(lc)
+	}
+	if len(lc.configs) > 0 {
+		return lc, nil
+	}
+
+	blockInfo, err := cc.GetLastConfigBlock(false)
+	if err != nil {
+		return nil, fmt.Errorf("get last config block failed, %s", err)
+	}
+	height := blockInfo.Block.Header.BlockHeight
+	chainConfig, err := cc.GetChainConfigByBlockHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("get chain config at [%d] failed, %s", height, err)
+	}
+	cc.logger.Infof("[SDK] light client trusts chain config of config block [%d]", height)
+	lc.configHeight = height
+	lc.configs[height] = chainConfig
+	return lc, nil
+}
+
+// TrustedChainConfig returns the latest verified chain config and its config block height
+func (lc *LightClient) TrustedChainConfig() (uint64, *config.ChainConfig) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.configHeight, lc.configs[lc.configHeight]
+}
+
+// GetBlockByHeight get block by block height and verify it
+func (lc *LightClient) GetBlockByHeight(blockHeight uint64, withRWSet bool) (*VerifiedBlockInfo, error) {
+	blockInfo, err := lc.cc.GetBlockByHeight(blockHeight, withRWSet)
+	if err != nil {
+		return nil, err
+	}
+	return lc.verifiedBlockInfo(blockInfo), nil
+}
+
+// GetTxByTxId get tx with rwset by tx id, and verify the tx is included in a verified block
+func (lc *LightClient) GetTxByTxId(txId string) (*VerifiedTxInfo, error) {
+	txInfo, err := lc.cc.GetTxWithRWSetByTxId(txId)
+	if err != nil {
+		return nil, err
+	}
+	result := &VerifiedTxInfo{TransactionInfoWithRWSet: txInfo}
+	if result.VerifyErr = lc.VerifyTx(txInfo); result.VerifyErr != nil {
+		lc.cc.logger.Warnf("[SDK] light client verify tx [%s] failed, %s", txId, result.VerifyErr)
+	}
+	result.Verified = result.VerifyErr == nil
+	return result, nil
+}
+
+// SubscribeBlock block subscription, returns channel of verified blocks
+func (lc *LightClient) SubscribeBlock(ctx context.Context, startBlock, endBlock int64,
+	withRWSet bool) (<-chan *VerifiedBlockInfo, error) {
+
+	dataCh, err := lc.cc.SubscribeBlock(ctx, startBlock, endBlock, withRWSet, false)
+	if err != nil {
+		return nil, err
+	}
+
+	c := make(chan *VerifiedBlockInfo)
+	go func() {
+		defer close(c)
+		for data := range dataCh {
+			blockInfo, ok := data.(*common.BlockInfo)
+			if !ok {
+				lc.cc.logger.Errorf("[SDK] light client received unexpected subscription data %T", data)
+				return
+			}
+			select {
+			case c <- lc.verifiedBlockInfo(blockInfo):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return c, nil
+}
+
+// VerifyBlock verify block hash, proposer signature and quorum of block, and its tx root, rwset root
+// and rwset hashes, returns ErrBlockBodyUnverified if block does not contain all txs
+func (lc *LightClient) VerifyBlock(blockInfo *common.BlockInfo) error {
+	if blockInfo == nil || blockInfo.Block == nil || blockInfo.Block.Header == nil {
+		return errors.New("block is empty")
+	}
+	chainConfig, err := lc.chainConfigAt(blockInfo.Block.Header.PreConfHeight)
+	if err != nil {
+		return err
+	}
+	return lc.verifyBlock(blockInfo, chainConfig)
+}
+
+// VerifyTx verify tx is included in a verified block by its merkle path and its rwset matches the rwset hash
+func (lc *LightClient) VerifyTx(txInfo *common.TransactionInfoWithRWSet) error {
+	tx := txInfo.Transaction
+	if tx == nil || tx.Payload == nil || tx.Result == nil {
+		return errors.New("tx or tx result is empty")
+	}
+
+	blockInfo, err := lc.cc.GetBlockByHeightTruncate(txInfo.BlockHeight, false, 1, lightClientTruncateModel)
+	if err != nil {
+		return fmt.Errorf("get block [%d] failed, %s", txInfo.BlockHeight, err)
+	}
+	if blockInfo.Block == nil || blockInfo.Block.Header == nil {
+		return fmt.Errorf("block [%d] is empty", txInfo.BlockHeight)
+	}
+	header := blockInfo.Block.Header
+	chainConfig, err := lc.chainConfigAt(header.PreConfHeight)
+	if err != nil {
+		return err
+	}
+	if err = lc.verifyHeader(blockInfo.Block, chainConfig); err != nil {
+		return err
+	}
+	if !bytes.Equal(header.BlockHash, txInfo.BlockHash) {
+		return fmt.Errorf("tx block hash expect %x, got %x", header.BlockHash, txInfo.BlockHash)
+	}
+
+	hashType := chainConfig.Crypto.Hash
+	if txInfo.RwSet == nil {
+		return fmt.Errorf("tx [%s] is returned without rwset", tx.Payload.TxId)
+	}
+	if err = verifyRWSetHash(hashType, tx, txInfo.RwSet); err != nil {
+		return err
+	}
+
+	txHash, err := cmutils.CalcTxHashWithVersion(hashType, tx, int(header.BlockVersion))
+	if err != nil {
+		return fmt.Errorf("calc tx hash failed, %s", err)
+	}
+	pathBytes, err := lc.cc.GetMerklePathByTxId(tx.Payload.TxId)
+	if err != nil {
+		return fmt.Errorf("get merkle path failed, %s", err)
+	}
+	var path [][]byte
+	if err = json.Unmarshal(pathBytes, &path); err != nil {
+		return fmt.Errorf("unmarshal merkle path failed, %s", err)
+	}
+	return verifyMerklePath(hashType, txHash, txInfo.TxIndex, path, header.TxRoot)
+}
+
+func (lc *LightClient) verifiedBlockInfo(blockInfo *common.BlockInfo) *VerifiedBlockInfo {
+	result := &VerifiedBlockInfo{BlockInfo: blockInfo}
+	if result.VerifyErr = lc.VerifyBlock(blockInfo); result.VerifyErr != nil {
+		lc.cc.logger.Warnf("[SDK] light client verify block failed, %s", result.VerifyErr)
+	}
+	result.Verified = result.VerifyErr == nil
+	return result
+}
+
+// chainConfigAt returns the verified chain config of config block height, verifying the config blocks
+// between the latest verified one and height first
+func (lc *LightClient) chainConfigAt(height uint64) (*config.ChainConfig, error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if chainConfig, ok := lc.configs[height]; ok {
+		return chainConfig, nil
+	}
+	if height < lc.configHeight {
+		return nil, fmt.Errorf("config block [%d] is before trusted config block [%d]", height, lc.configHeight)
+	}
+
+	// walk back the config blocks until the latest verified one, then verify them in order
+	var configBlocks []*common.BlockInfo
+	for h := height; h != lc.configHeight; {
+		blockInfo, err := lc.cc.GetBlockByHeight(h, true)
+		if err != nil {
+			return nil, fmt.Errorf("get config block [%d] failed, %s", h, err)
+		}
+		if blockInfo.Block == nil || blockInfo.Block.Header == nil || blockInfo.Block.Header.BlockHeight != h {
+			return nil, fmt.Errorf("config block [%d] is invalid", h)
+		}
+		configBlocks = append(configBlocks, blockInfo)
+		preConfHeight := blockInfo.Block.Header.PreConfHeight
+		if preConfHeight >= h || preConfHeight < lc.configHeight {
+			return nil, fmt.Errorf("config block [%d] does not link to trusted config block [%d]",
+				height, lc.configHeight)
+		}
+		h = preConfHeight
+	}
+
+	for i := len(configBlocks) - 1; i >= 0; i-- {
+		blockInfo := configBlocks[i]
+		block := blockInfo.Block
+		if len(block.Txs) != int(block.Header.TxCount) || len(blockInfo.RwsetList) != len(block.Txs) {
+			return nil, fmt.Errorf("config block [%d] is returned without all txs and rwsets", block.Header.BlockHeight)
+		}
+		if err := lc.verifyBlock(blockInfo, lc.configs[lc.configHeight]); err != nil {
+			return nil, err
+		}
+		chainConfig, err := chainConfigFromBlock(blockInfo)
+		if err != nil {
+			return nil, err
+		}
+		lc.configHeight = blockInfo.Block.Header.BlockHeight
+		lc.configs[lc.configHeight] = chainConfig
+		lc.cc.logger.Debugf("[SDK] light client verified config block [%d]", lc.configHeight)
+	}
+	return lc.configs[height], nil
+}
+
+func (lc *LightClient) verifyBlock(blockInfo *common.BlockInfo, chainConfig *config.ChainConfig) error {
+	if err := lc.verifyHeader(blockInfo.Block, chainConfig); err != nil {
+		return err
+	}
+	return verifyBlockBody(blockInfo, chainConfig.Crypto.Hash)
+}
+
+// verifyHeader verify block hash, proposer signature and the consensus quorum of block
+func (lc *LightClient) verifyHeader(block *common.Block, chainConfig *config.ChainConfig) error {
+	header := block.Header
+	// a proposer signature alone does not prove the block is committed
+	if chainConfig.Consensus.Type != consensus.ConsensusType_TBFT {
+		return fmt.Errorf("block [%d] of %s consensus, %w", header.BlockHeight, chainConfig.Consensus.Type,
+			ErrConsensusUnsupported)
+	}
+	if header.ChainId != lc.cc.chainId {
+		return fmt.Errorf("block chain id expect %s, got %s", lc.cc.chainId, header.ChainId)
+	}
+
+	blockHash, err := cmutils.CalcBlockHash(chainConfig.Crypto.Hash, block)
+	if err != nil {
+		return fmt.Errorf("calc block hash failed, %s", err)
+	}
+	if !bytes.Equal(blockHash, header.BlockHash) {
+		return fmt.Errorf("block [%d] hash expect %x, got %x", header.BlockHeight, header.BlockHash, blockHash)
+	}
+
+	at := time.Unix(header.BlockTimestamp, 0)
+	proposerOrg, err := lc.verifySignature(header.Proposer, header.BlockHash, header.Signature, chainConfig, at)
+	if err != nil {
+		return fmt.Errorf("verify proposer signature of block [%d] failed, %s", header.BlockHeight, err)
+	}
+	if !isConsensusOrg(chainConfig, proposerOrg) {
+		return fmt.Errorf("proposer org [%s] of block [%d] is not a consensus org", proposerOrg, header.BlockHeight)
+	}
+
+	return lc.verifyTBFTQuorum(block, chainConfig, at)
+}
+
+// verifyTBFTQuorum verify more than 2/3 of the consensus nodes precommitted block, a vote counts
+// if it is signed by the cert of its voter and every consensus node and signer counts once
+func (lc *LightClient) verifyTBFTQuorum(block *common.Block, chainConfig *config.ChainConfig, at time.Time) error {
+	header := block.Header
+	if block.AdditionalData == nil || len(block.AdditionalData.ExtraData[tbftAdditionalDataKey]) == 0 {
+		return fmt.Errorf("block [%d] has no tbft vote set", header.BlockHeight)
+	}
+	voteSet := &tbftpb.VoteSet{}
+	if err := proto.Unmarshal(block.AdditionalData.ExtraData[tbftAdditionalDataKey], voteSet); err != nil {
+		return fmt.Errorf("unmarshal tbft vote set of block [%d] failed, %s", header.BlockHeight, err)
+	}
+
+	nodeOrgs := make(map[string]string)
+	for _, node := range chainConfig.Consensus.Nodes {
+		for _, nodeId := range node.NodeId {
+			nodeOrgs[nodeId] = node.OrgId
+		}
+	}
+
+	voted := 0
+	signers := make(map[string]bool)
+	for voter, vote := range voteSet.Votes {
+		orgId, ok := nodeOrgs[voter]
+		if !ok || vote == nil || vote.Voter != voter {
+			continue
+		}
+		if vote.Type != tbftpb.VoteType_VOTE_PRECOMMIT || vote.Height != header.BlockHeight ||
+			vote.Round != voteSet.Round || !bytes.Equal(vote.Hash, header.BlockHash) || vote.Endorsement == nil {
+			continue
+		}
+
+		voteCopy := proto.Clone(vote).(*tbftpb.Vote)
+		voteCopy.Endorsement = nil
+		message, err := proto.Marshal(voteCopy)
+		if err != nil {
+			return fmt.Errorf("marshal vote of [%s] failed, %s", voter, err)
+		}
+		cert, err := lc.cc.verifyCertMemberSignature(vote.Endorsement.Signer, message, vote.Endorsement.Signature,
+			chainConfig, at)
+		if err != nil {
+			lc.cc.logger.Debugf("[SDK] light client ignores vote of [%s], %s", voter, err)
+			continue
+		}
+		if vote.Endorsement.Signer.OrgId != orgId || !lc.isVoterCert(voter, cert) {
+			lc.cc.logger.Debugf("[SDK] light client ignores vote of [%s], not signed by the voter", voter)
+			continue
+		}
+		if signers[string(cert.Raw)] {
+			continue
+		}
+		signers[string(cert.Raw)] = true
+		voted++
+	}
+
+	if voted*3 <= len(nodeOrgs)*2 {
+		return fmt.Errorf("block [%d] has %d valid precommits of %d consensus nodes, no quorum",
+			header.BlockHeight, voted, len(nodeOrgs))
+	}
+	return nil
+}
+
+// isVoterCert reports whether cert is the cert of consensus node voter, the sign cert bound to voter by
+// WithConsensusSigners or a cert whose public key the node id of voter is derived from
+func (lc *LightClient) isVoterCert(voter string, cert *bcx509.Certificate) bool {
+	if lc.signers != nil {
+		signer := lc.signers[voter]
+		return signer != nil && bytes.Equal(signer.Raw, cert.Raw)
+	}
+	nodeId, err := helper.GetLibp2pPeerIdFromCertDer(cert.Raw)
+	return err == nil && nodeId == voter
+}
+
+// verifySignature verify signature of member over message, member cert must be issued by the trust roots
+// of its org, returns the org of member
+func (lc *LightClient) verifySignature(member *accesscontrol.Member, message, signature []byte,
+	chainConfig *config.ChainConfig, at time.Time) (string, error) {
+	if _, err := lc.cc.verifyCertMemberSignature(member, message, signature, chainConfig, at); err != nil {
+		return "", err
+	}
+	return member.OrgId, nil
+}
+
+// verifyCertMemberSignature verify signature of a cert or cert hash member over message, member cert must be
+// issued by the trust roots of its org, returns the cert of member
+func (cc *ChainClient) verifyCertMemberSignature(member *accesscontrol.Member, message, signature []byte,
+	chainConfig *config.ChainConfig, at time.Time) (*bcx509.Certificate, error) {
+	if member == nil {
+		return nil, errors.New("signer is empty")
+	}
+
+	var certPEM []byte
+	switch member.MemberType {
+	case accesscontrol.MemberType_CERT:
+		certPEM = member.MemberInfo
+	case accesscontrol.MemberType_CERT_HASH:
+		certInfos, err := cc.QueryCert([]string{hex.EncodeToString(member.MemberInfo)})
+		if err != nil {
+			return nil, fmt.Errorf("query cert failed, %s", err)
+		}
+		if len(certInfos.CertInfos) == 0 || len(certInfos.CertInfos[0].Cert) == 0 {
+			return nil, fmt.Errorf("cert [%x] not found", member.MemberInfo)
+		}
+		certPEM = certInfos.CertInfos[0].Cert
+	default:
+		return nil, fmt.Errorf("member type [%s] is not supported", member.MemberType)
+	}
+
+	cert, err := utils.ParseCert(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	if member.MemberType == accesscontrol.MemberType_CERT_HASH {
+		certHash, err := utils.GetCertificateIdFromDER(cert.Raw, chainConfig.Crypto.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(certHash, member.MemberInfo) {
+			return nil, fmt.Errorf("cert hash expect %x, got %x", member.MemberInfo, certHash)
+		}
+	}
+	if len(cert.Subject.Organization) == 0 || cert.Subject.Organization[0] != member.OrgId {
+		return nil, fmt.Errorf("cert org does not match member org [%s]", member.OrgId)
+	}
+	if err = verifyCertChain(cert, member.OrgId, chainConfig, at); err != nil {
+		return nil, err
+	}
+
+	hashAlgo, err := bcx509.GetHashFromSignatureAlgorithm(cert.SignatureAlgorithm)
+	if err != nil {
+		return nil, fmt.Errorf("invalid algorithm: %v", err)
+	}
+	ok, err := cert.PublicKey.VerifyWithOpts(message, signature, &crypto.SignOpts{
+		Hash: hashAlgo,
+		UID:  crypto.CRYPTO_DEFAULT_UID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid signature")
+	}
+	return cert, nil
+}
+
+// verifyCertChain verify cert is issued by the trust roots of org at time at
+func verifyCertChain(cert *bcx509.Certificate, orgId string, chainConfig *config.ChainConfig, at time.Time) error {
+	roots := bcx509.NewCertPool()
+	for _, trustRoot := range chainConfig.TrustRoots {
+		if trustRoot.OrgId != orgId {
+			continue
+		}
+		for _, root := range trustRoot.Root {
+			roots.AppendCertsFromPEM([]byte(root))
+		}
+	}
+
+	_, err := cert.Verify(bcx509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: roots,
+		CurrentTime:   at,
+		KeyUsages:     []bcx509.ExtKeyUsage{bcx509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("verify cert chain of org [%s] failed, %s", orgId, err)
+	}
+	return nil
+}
+
+func isConsensusOrg(chainConfig *config.ChainConfig, orgId string) bool {
+	for _, node := range chainConfig.Consensus.Nodes {
+		if node.OrgId == orgId {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyBlockBody verify tx root, rwset root and rwset hashes of block, returns ErrBlockBodyUnverified
+// for blocks without all txs
+func verifyBlockBody(blockInfo *common.BlockInfo, hashType string) error {
+	block := blockInfo.Block
+	header := block.Header
+	if len(block.Txs) != int(header.TxCount) {
+		return fmt.Errorf("block [%d] has %d of %d txs, %w", header.BlockHeight, len(block.Txs), header.TxCount,
+			ErrBlockBodyUnverified)
+	}
+
+	txHashes := make([][]byte, len(block.Txs))
+	for i, tx := range block.Txs {
+		txHash, err := cmutils.CalcTxHashWithVersion(hashType, tx, int(header.BlockVersion))
+		if err != nil {
+			return fmt.Errorf("calc tx hash failed, %s", err)
+		}
+		txHashes[i] = txHash
+	}
+	txRoot, err := hash.GetMerkleRoot(hashType, txHashes)
+	if err != nil {
+		return fmt.Errorf("calc tx root failed, %s", err)
+	}
+	if !bytes.Equal(txRoot, header.TxRoot) {
+		return fmt.Errorf("block [%d] tx root expect %x, got %x", header.BlockHeight, header.TxRoot, txRoot)
+	}
+
+	rwSetRoot, err := cmutils.CalcRWSetRoot(hashType, block.Txs)
+	if err != nil {
+		return fmt.Errorf("calc rwset root failed, %s", err)
+	}
+	if !bytes.Equal(rwSetRoot, header.RwSetRoot) {
+		return fmt.Errorf("block [%d] rwset root expect %x, got %x", header.BlockHeight, header.RwSetRoot, rwSetRoot)
+	}
+
+	if len(blockInfo.RwsetList) == 0 {
+		return nil
+	}
+	if len(blockInfo.RwsetList) != len(block.Txs) {
+		return fmt.Errorf("block [%d] has %d txs but %d rwsets", header.BlockHeight, len(block.Txs),
+			len(blockInfo.RwsetList))
+	}
+	for i, tx := range block.Txs {
+		if err = verifyRWSetHash(hashType, tx, blockInfo.RwsetList[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func verifyRWSetHash(hashType string, tx *common.Transaction, rwSet *common.TxRWSet) error {
+	if tx.Result == nil {
+		return fmt.Errorf("tx [%s] has no result", tx.Payload.TxId)
+	}
+	if rwSet.TxId != tx.Payload.TxId {
+		return fmt.Errorf("rwset of tx [%s] belongs to tx [%s]", tx.Payload.TxId, rwSet.TxId)
+	}
+	rwSetHash, err := cmutils.CalcRWSetHash(hashType, rwSet)
+	if err != nil {
+		return fmt.Errorf("calc rwset hash failed, %s", err)
+	}
+	if !bytes.Equal(rwSetHash, tx.Result.RwSetHash) {
+		return fmt.Errorf("tx [%s] rwset hash expect %x, got %x", tx.Payload.TxId, tx.Result.RwSetHash, rwSetHash)
+	}
+	return nil
+}
+
+// verifyMerklePath verify leaf at index is included in root, path holds the siblings from the leaf level up,
+// an empty sibling means the node is hashed with itself as the last node of an odd level
+func verifyMerklePath(hashType string, leaf []byte, index uint32, path [][]byte, root []byte) error {
+	node := leaf
+	for _, sibling := range path {
+		if len(sibling) == 0 {
+			sibling = node
+		}
+		var data []byte
+		if index%2 == 0 {
+			data = append(append(data, node...), sibling...)
+		} else {
+			data = append(append(data, sibling...), node...)
+		}
+		parent, err := hash.GetByStrType(hashType, data)
+		if err != nil {
+			return err
+		}
+		node = parent
+		index /= 2
+	}
+	if !bytes.Equal(node, root) {
+		return fmt.Errorf("merkle path leads to %x, tx root is %x", node, root)
+	}
+	return nil
+}
+
+// chainConfigFromBlock returns the chain config written by the config tx of a verified config block
+func chainConfigFromBlock(blockInfo *common.BlockInfo) (*config.ChainConfig, error) {
+	chainConfigName := syscontract.SystemContract_CHAIN_CONFIG.String()
+	for _, rwSet := range blockInfo.RwsetList {
+		for _, write := range rwSet.TxWrites {
+			if write.ContractName != chainConfigName || string(write.Key) != chainConfigName {
+				continue
+			}
+			chainConfig := &config.ChainConfig{}
+			if err := proto.Unmarshal(write.Value, chainConfig); err != nil {
+				return nil, fmt.Errorf("unmarshal chain config failed, %s", err)
+			}
+			return chainConfig, nil
+		}
+	}
+	return nil, fmt.Errorf("config block [%d] writes no chain config", blockInfo.Block.Header.BlockHeight)
+}
@@ -0,0 +1,246 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	"chainmaker.org/chainmaker/common/v2/crypto/asym"
+	"chainmaker.org/chainmaker/common/v2/crypto/hash"
+	"chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/config"
+	"chainmaker.org/chainmaker/pb-go/v2/consensus"
+	tbftpb "chainmaker.org/chainmaker/pb-go/v2/consensus/tbft"
+	"chainmaker.org/chainmaker/protocol/v2/test"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+// merklePath returns the siblings of leaf index from the leaf level up, nil for a node without sibling
+func merklePath(t *testing.T, hashType string, leaves [][]byte, index int) [][]byte {
+	var path [][]byte
+	level := leaves
+	for len(level) > 1 {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			path = append(path, level[sibling])
+		} else {
+			path = append(path, nil)
+		}
+
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			parent, err := hash.GetByStrType(hashType, append(append([]byte{}, level[i]...), right...))
+			require.Nil(t, err)
+			next = append(next, parent)
+		}
+		level = next
+		index /= 2
+	}
+	return path
+}
+
+func TestVerifyMerklePath(t *testing.T) {
+	hashType := crypto.CRYPTO_ALGO_SHA256
+	for _, count := range []int{1, 2, 3, 5, 8, 11} {
+		var leaves [][]byte
+		for i := 0; i < count; i++ {
+			leaf, err := hash.GetByStrType(hashType, []byte(fmt.Sprintf("tx%d", i)))
+			require.Nil(t, err)
+			leaves = append(leaves, leaf)
+		}
+		root, err := hash.GetMerkleRoot(hashType, leaves)
+		require.Nil(t, err)
+
+		for i := range leaves {
+			// paths are returned by node in json
+			bz, err := json.Marshal(merklePath(t, hashType, leaves, i))
+			require.Nil(t, err)
+			var path [][]byte
+			require.Nil(t, json.Unmarshal(bz, &path))
+
+			require.Nil(t, verifyMerklePath(hashType, leaves[i], uint32(i), path, root), "count %d index %d", count, i)
+			if count > 1 {
+				other := leaves[(i+1)%count]
+				require.NotNil(t, verifyMerklePath(hashType, other, uint32(i), path, root))
+			}
+		}
+	}
+}
+
+const testLightClientOrg = "wx-org.chainmaker.org"
+
+// testVoteSigner key and cert of a member of testLightClientOrg signing tbft votes
+type testVoteSigner struct {
+	key     crypto.PrivateKey
+	certPEM []byte
+}
+
+func newTestVoteSigner(t *testing.T, path string) *testVoteSigner {
+	dir := "./testdata/crypto-config/" + testLightClientOrg + "/"
+	keyPEM, err := ioutil.ReadFile(dir + path + ".key")
+	require.Nil(t, err)
+	key, err := asym.PrivateKeyFromPEM(keyPEM, nil)
+	require.Nil(t, err)
+	certPEM, err := ioutil.ReadFile(dir + path + ".crt")
+	require.Nil(t, err)
+	return &testVoteSigner{key: key, certPEM: certPEM}
+}
+
+// precommit returns the precommit of voter for block hash at height 5, signed by s
+func (s *testVoteSigner) precommit(t *testing.T, voter string) *tbftpb.Vote {
+	vote := &tbftpb.Vote{
+		Type:   tbftpb.VoteType_VOTE_PRECOMMIT,
+		Voter:  voter,
+		Height: 5,
+		Hash:   []byte("hash"),
+	}
+	message, err := proto.Marshal(vote)
+	require.Nil(t, err)
+	cert, err := utils.ParseCert(s.certPEM)
+	require.Nil(t, err)
+	signature, err := utils.SignPayloadBytes(s.key, cert, message)
+	require.Nil(t, err)
+	vote.Endorsement = &common.EndorsementEntry{
+		Signer: &accesscontrol.Member{
+			OrgId:      testLightClientOrg,
+			MemberType: accesscontrol.MemberType_CERT,
+			MemberInfo: s.certPEM,
+		},
+		Signature: signature,
+	}
+	return vote
+}
+
+func TestVerifyTBFTQuorum(t *testing.T) {
+	dir := "./testdata/crypto-config/" + testLightClientOrg + "/"
+	caPEM, err := ioutil.ReadFile(dir + "ca/ca.crt")
+	require.Nil(t, err)
+	nodeId := func(node string) string {
+		id, err := ioutil.ReadFile(dir + "node/" + node + "/" + node + ".nodeid")
+		require.Nil(t, err)
+		return strings.TrimSpace(string(id))
+	}
+	consensus1, common1 := nodeId("consensus1"), nodeId("common1")
+
+	consensus1Sign := newTestVoteSigner(t, "node/consensus1/consensus1.sign")
+	common1Sign := newTestVoteSigner(t, "node/common1/common1.sign")
+	consensus1TLS := newTestVoteSigner(t, "node/consensus1/consensus1.tls")
+	common1TLS := newTestVoteSigner(t, "node/common1/common1.tls")
+	client1 := newTestVoteSigner(t, "user/client1/client1.sign")
+
+	chainConfig := &config.ChainConfig{
+		Crypto:     &config.CryptoConfig{Hash: crypto.CRYPTO_ALGO_SHA256},
+		TrustRoots: []*config.TrustRootConfig{{OrgId: testLightClientOrg, Root: []string{string(caPEM)}}},
+		Consensus: &config.ConsensusConfig{
+			Type:  consensus.ConsensusType_TBFT,
+			Nodes: []*config.OrgConfig{{OrgId: testLightClientOrg, NodeId: []string{consensus1, common1}}},
+		},
+	}
+	signCerts := map[string][]byte{consensus1: consensus1Sign.certPEM, common1: common1Sign.certPEM}
+
+	// two consensus nodes, both precommits are needed for a quorum
+	tests := []struct {
+		name    string
+		signers map[string][]byte
+		votes   []*tbftpb.Vote
+		wantErr bool
+	}{
+		{name: "bound sign certs", signers: signCerts,
+			votes: []*tbftpb.Vote{consensus1Sign.precommit(t, consensus1), common1Sign.precommit(t, common1)}},
+		{name: "node ids derived from certs",
+			votes: []*tbftpb.Vote{consensus1TLS.precommit(t, consensus1), common1TLS.precommit(t, common1)}},
+		{name: "voter forged by another node", signers: signCerts, wantErr: true,
+			votes: []*tbftpb.Vote{consensus1Sign.precommit(t, consensus1), consensus1Sign.precommit(t, common1)}},
+		{name: "voter forged by a user of consensus org", signers: signCerts, wantErr: true,
+			votes: []*tbftpb.Vote{consensus1Sign.precommit(t, consensus1), client1.precommit(t, common1)}},
+		{name: "sign certs not bound", wantErr: true,
+			votes: []*tbftpb.Vote{consensus1Sign.precommit(t, consensus1), common1Sign.precommit(t, common1)}},
+		{name: "duplicate signer", wantErr: true,
+			signers: map[string][]byte{consensus1: consensus1Sign.certPEM, common1: consensus1Sign.certPEM},
+			votes:   []*tbftpb.Vote{consensus1Sign.precommit(t, consensus1), consensus1Sign.precommit(t, common1)}},
+		{name: "voter not a consensus node", signers: signCerts, wantErr: true,
+			votes: []*tbftpb.Vote{consensus1Sign.precommit(t, consensus1), client1.precommit(t, "client1")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := &LightClient{cc: &ChainClient{logger: test.NewTestLogger(t)}}
+			if tt.signers != nil {
+				WithConsensusSigners(tt.signers)(lc)
+			}
+
+			voteSet := &tbftpb.VoteSet{Type: tbftpb.VoteType_VOTE_PRECOMMIT, Height: 5, Votes: map[string]*tbftpb.Vote{}}
+			for _, vote := range tt.votes {
+				voteSet.Votes[vote.Voter] = vote
+			}
+			voteSetBytes, err := proto.Marshal(voteSet)
+			require.Nil(t, err)
+			block := &common.Block{
+				Header: &common.BlockHeader{BlockHeight: 5, BlockHash: []byte("hash")},
+				AdditionalData: &common.AdditionalData{
+					ExtraData: map[string][]byte{tbftAdditionalDataKey: voteSetBytes},
+				},
+			}
+
+			err = lc.verifyTBFTQuorum(block, chainConfig, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+			if tt.wantErr {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), "no quorum")
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyBlockUnverified(t *testing.T) {
+	header := &common.BlockHeader{ChainId: "chain1", BlockHeight: 5, TxCount: 2}
+	tests := []struct {
+		name      string
+		consensus consensus.ConsensusType
+		txs       []*common.Transaction
+		wantErr   error
+	}{
+		{name: "solo", consensus: consensus.ConsensusType_SOLO, wantErr: ErrConsensusUnsupported},
+		{name: "raft", consensus: consensus.ConsensusType_RAFT, wantErr: ErrConsensusUnsupported},
+		{name: "header only", consensus: consensus.ConsensusType_TBFT, wantErr: ErrBlockBodyUnverified},
+		{name: "txs missing", consensus: consensus.ConsensusType_TBFT, txs: []*common.Transaction{{}},
+			wantErr: ErrBlockBodyUnverified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chainConfig := &config.ChainConfig{
+				Crypto:    &config.CryptoConfig{Hash: crypto.CRYPTO_ALGO_SHA256},
+				Consensus: &config.ConsensusConfig{Type: tt.consensus},
+			}
+			blockInfo := &common.BlockInfo{Block: &common.Block{Header: header, Txs: tt.txs}}
+
+			var err error
+			if tt.consensus == consensus.ConsensusType_TBFT {
+				err = verifyBlockBody(blockInfo, chainConfig.Crypto.Hash)
+			} else {
+				lc := &LightClient{cc: &ChainClient{chainId: "chain1", logger: test.NewTestLogger(t)}}
+				err = lc.verifyBlock(blockInfo, chainConfig)
+			}
+			require.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+		})
+	}
+}
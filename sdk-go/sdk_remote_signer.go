@@ -0,0 +1,304 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"errors"
+	"fmt"
+	"time"
+
+	"chainmaker.org/chainmaker/common/v2/ca"
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	"chainmaker.org/chainmaker/common/v2/crypto/asym"
+	bcx509 "chainmaker.org/chainmaker/common/v2/crypto/x509"
+	"chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/sdk-go/v2/remotesigner/pb"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"google.golang.org/grpc"
+)
+
+const (
+	// DefaultRemoteSignerTimeout default timeout of remote signer requests
+	DefaultRemoteSignerTimeout = 10 * time.Second
+)
+
+// RemoteSignerConfig remote signer config
+type RemoteSignerConfig struct {
+	// signing daemon address
+	addr string
+	// id of the key in signing daemon
+	keyId string
+	// tls config, the tls cert and key authenticate the client to the signing daemon
+	useTLS      bool
+	caCerts     []string
+	tlsHostName string
+	tlsCertFile string
+	tlsKeyFile  string
+	timeout     time.Duration
+	dialOptions []grpc.DialOption
+	logger      utils.Logger
+}
+
+// RemoteSignerOption define remote signer option func
+type RemoteSignerOption func(config *RemoteSignerConfig)
+
+// NewRemoteSignerConfig new remote signer config
+func NewRemoteSignerConfig(opts ...RemoteSignerOption) *RemoteSignerConfig {
+	config := &RemoteSignerConfig{timeout: DefaultRemoteSignerTimeout}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// WithRemoteSignerAddr 设置签名服务地址
+func WithRemoteSignerAddr(addr string) RemoteSignerOption {
+	return func(config *RemoteSignerConfig) {
+		config.addr = addr
+	}
+}
+
+// WithRemoteSignerKeyId 设置签名服务中的密钥ID
+func WithRemoteSignerKeyId(keyId string) RemoteSignerOption {
+	return func(config *RemoteSignerConfig) {
+		config.keyId = keyId
+	}
+}
+
+// WithRemoteSignerTLS 设置签名服务的TLS配置，caCerts为CA证书内容，tlsCertFile/tlsKeyFile为客户端TLS证书和私钥
+func WithRemoteSignerTLS(caCerts []string, tlsHostName, tlsCertFile, tlsKeyFile string) RemoteSignerOption {
+	return func(config *RemoteSignerConfig) {
+		config.useTLS = true
+		config.caCerts = caCerts
+		config.tlsHostName = tlsHostName
+		config.tlsCertFile = tlsCertFile
+		config.tlsKeyFile = tlsKeyFile
+	}
+}
+
+// WithRemoteSignerTimeout 设置签名请求超时时间
+func WithRemoteSignerTimeout(timeout time.Duration) RemoteSignerOption {
+	return func(config *RemoteSignerConfig) {
+		config.timeout = timeout
+	}
+}
+
+// WithRemoteSignerDialOptions 设置额外的gRPC连接参数
+func WithRemoteSignerDialOptions(opts ...grpc.DialOption) RemoteSignerOption {
+	return func(config *RemoteSignerConfig) {
+		config.dialOptions = append(config.dialOptions, opts...)
+	}
+}
+
+// WithRemoteSignerLogger 设置logger，未设置时使用ChainClient的logger
+func WithRemoteSignerLogger(logger utils.Logger) RemoteSignerOption {
+	return func(config *RemoteSignerConfig) {
+		config.logger = logger
+	}
+}
+
+// RemoteSigner signer delegating signing to a signing daemon over gRPC, the private key never leaves the daemon.
+// It implements Signer, and is used by ChainClient for all signatures when set by WithRemoteSignerConfig.
+type RemoteSigner struct {
+	config   *RemoteSignerConfig
+	conn     *grpc.ClientConn
+	client   pb.RemoteSignerClient
+	identity *pb.Identity
+
+	privateKey *remotePrivateKey
+	cert       *bcx509.Certificate
+	hashType   crypto.HashType
+}
+
+var _ Signer = (*RemoteSigner)(nil)
+
+// NewRemoteSigner connect to the signing daemon and get the identity of the key
+func NewRemoteSigner(config *RemoteSignerConfig) (*RemoteSigner, error) {
+	if config == nil || config.addr == "" || config.keyId == "" {
+		return nil, errors.New("remote signer addr and key id must not be empty")
+	}
+	if config.logger == nil {
+		config.logger = getDefaultLogger()
+	}
+
+	dialOptions := append([]grpc.DialOption{}, config.dialOptions...)
+	if config.useTLS {
+		tlsClient := ca.CAClient{
+			ServerName: config.tlsHostName,
+			CaCerts:    config.caCerts,
+			CertFile:   config.tlsCertFile,
+			KeyFile:    config.tlsKeyFile,
+			Logger:     config.logger,
+		}
+		creds, err := tlsClient.GetCredentialsByCA()
+		if err != nil {
+			return nil, fmt.Errorf("GetCredentialsByCA error %s", err.Error())
+		}
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(*creds))
+	} else {
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+	conn, err := grpc.Dial(config.addr, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("dial remote signer failed, %s", err)
+	}
+
+	signer := &RemoteSigner{
+		config: config,
+		conn:   conn,
+		client: pb.NewRemoteSignerClient(conn),
+	}
+	if err = signer.init(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return signer, nil
+}
+
+func (signer *RemoteSigner) init() error {
+	ctx, cancel := context.WithTimeout(context.Background(), signer.config.timeout)
+	defer cancel()
+
+	identity, err := signer.client.GetIdentity(ctx, &pb.GetIdentityRequest{KeyId: signer.config.keyId})
+	if err != nil {
+		return fmt.Errorf("get identity of key [%s] failed, %s", signer.config.keyId, err)
+	}
+	hashType, ok := crypto.HashAlgoMap[identity.HashType]
+	if !ok {
+		return fmt.Errorf("invalid hash type [%s] of key [%s]", identity.HashType, identity.KeyId)
+	}
+	publicKey, err := asym.PublicKeyFromPEM(identity.PublicKeyPem)
+	if err != nil {
+		return fmt.Errorf("parse public key failed, %s", err)
+	}
+	if len(identity.CertPem) != 0 {
+		if signer.cert, err = utils.ParseCert(identity.CertPem); err != nil {
+			return err
+		}
+	}
+
+	signer.identity = identity
+	signer.hashType = hashType
+	signer.privateKey = &remotePrivateKey{signer: signer, publicKey: publicKey}
+	return nil
+}
+
+// Sign sign payload
+func (signer *RemoteSigner) Sign(payload *common.Payload) ([]byte, error) {
+	return utils.SignPayloadWithHashType(signer.privateKey, signer.hashType, payload)
+}
+
+// NewMember new *accesscontrol.Member
+func (signer *RemoteSigner) NewMember() (*accesscontrol.Member, error) {
+	if signer.cert != nil {
+		return &accesscontrol.Member{
+			OrgId:      signer.identity.OrgId,
+			MemberInfo: signer.identity.CertPem,
+			MemberType: accesscontrol.MemberType_CERT,
+		}, nil
+	}
+	return &accesscontrol.Member{
+		OrgId:      signer.identity.OrgId,
+		MemberInfo: signer.identity.PublicKeyPem,
+		MemberType: accesscontrol.MemberType_PUBLIC_KEY,
+	}, nil
+}
+
+// PrivateKey returns the private key signing in the daemon, it can not be exported
+func (signer *RemoteSigner) PrivateKey() crypto.PrivateKey {
+	return signer.privateKey
+}
+
+// Cert returns the cert of key, nil in PermissionedWithKey and Public mode
+func (signer *RemoteSigner) Cert() *bcx509.Certificate {
+	return signer.cert
+}
+
+// OrgId returns the org id of key
+func (signer *RemoteSigner) OrgId() string {
+	return signer.identity.OrgId
+}
+
+// HashType returns the hash type of key
+func (signer *RemoteSigner) HashType() crypto.HashType {
+	return signer.hashType
+}
+
+// Close close the connection to the signing daemon
+func (signer *RemoteSigner) Close() error {
+	return signer.conn.Close()
+}
+
+func (signer *RemoteSigner) sign(data []byte, opts *crypto.SignOpts) ([]byte, error) {
+	req := &pb.SignRequest{
+		KeyId:   signer.identity.KeyId,
+		Payload: data,
+	}
+	if opts != nil {
+		req.HashType = signer.identity.HashType
+		if opts.Hash != signer.hashType {
+			return nil, fmt.Errorf("remote key signs with %s only", signer.identity.HashType)
+		}
+		req.Uid = opts.UID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), signer.config.timeout)
+	defer cancel()
+	resp, err := signer.client.Sign(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("remote sign failed, %s", err)
+	}
+	return resp.Signature, nil
+}
+
+// remotePrivateKey crypto.PrivateKey signing by the signing daemon,
+// the data to sign must be a marshaled common.Payload
+type remotePrivateKey struct {
+	signer    *RemoteSigner
+	publicKey crypto.PublicKey
+}
+
+var _ crypto.PrivateKey = (*remotePrivateKey)(nil)
+
+// Bytes private key can not be exported
+func (key *remotePrivateKey) Bytes() ([]byte, error) {
+	return nil, errors.New("private key is held by remote signer")
+}
+
+// Type returns the key type
+func (key *remotePrivateKey) Type() crypto.KeyType {
+	return key.publicKey.Type()
+}
+
+// String private key can not be exported
+func (key *remotePrivateKey) String() (string, error) {
+	return "", errors.New("private key is held by remote signer")
+}
+
+// Sign sign data with the hash type of key
+func (key *remotePrivateKey) Sign(data []byte) ([]byte, error) {
+	return key.signer.sign(data, nil)
+}
+
+// SignWithOpts sign data with opts
+func (key *remotePrivateKey) SignWithOpts(data []byte, opts *crypto.SignOpts) ([]byte, error) {
+	return key.signer.sign(data, opts)
+}
+
+// PublicKey returns the public key
+func (key *remotePrivateKey) PublicKey() crypto.PublicKey {
+	return key.publicKey
+}
+
+// ToStandardKey remote keys have no standard key
+func (key *remotePrivateKey) ToStandardKey() stdcrypto.PrivateKey {
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	"chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/sdk-go/v2/remotesigner"
+	"chainmaker.org/chainmaker/sdk-go/v2/remotesigner/pb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	remoteSignerKeyFile = "./testdata/crypto-config/wx-org.chainmaker.org/user/client1/client1.sign.key"
+	remoteSignerCrtFile = "./testdata/crypto-config/wx-org.chainmaker.org/user/client1/client1.sign.crt"
+	remoteSignerOrgId   = "wx-org.chainmaker.org"
+)
+
+func newTestRemoteSigner(t *testing.T, auditPath string) *RemoteSigner {
+	key, err := remotesigner.LoadKey("client1", remoteSignerOrgId, remoteSignerKeyFile, "", remoteSignerCrtFile, "")
+	require.Nil(t, err)
+	auditor, err := remotesigner.NewFileAuditor(auditPath)
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = auditor.Close() })
+
+	policy := &remotesigner.Policy{
+		Contracts:   map[string][]string{"claim001": {"save"}},
+		ValueLimits: map[string]*big.Int{"amount": big.NewInt(100)},
+		AllowQuery:  true,
+	}
+	server, err := remotesigner.NewServer([]*remotesigner.Key{key}, auditor, getDefaultLogger(), policy.Approve)
+	require.Nil(t, err)
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterRemoteSignerServer(grpcServer, server)
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	signer, err := NewRemoteSigner(NewRemoteSignerConfig(
+		WithRemoteSignerAddr("bufnet"),
+		WithRemoteSignerKeyId("client1"),
+		WithRemoteSignerDialOptions(grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		})),
+	))
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = signer.Close() })
+	return signer
+}
+
+func TestRemoteSigner(t *testing.T) {
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	signer := newTestRemoteSigner(t, auditPath)
+
+	require.NotNil(t, signer.Cert())
+	require.Equal(t, remoteSignerOrgId, signer.OrgId())
+	member, err := signer.NewMember()
+	require.Nil(t, err)
+	require.Equal(t, accesscontrol.MemberType_CERT, member.MemberType)
+	_, err = signer.PrivateKey().Bytes()
+	require.NotNil(t, err)
+
+	tests := []struct {
+		name    string
+		payload *common.Payload
+		wantErr bool
+	}{
+		{
+			name: "allowed method",
+			payload: &common.Payload{ChainId: "chain1", TxType: common.TxType_INVOKE_CONTRACT, TxId: "tx1",
+				ContractName: "claim001", Method: "save",
+				Parameters: []*common.KeyValuePair{{Key: "amount", Value: []byte("100")}}},
+		},
+		{
+			name: "query",
+			payload: &common.Payload{ChainId: "chain1", TxType: common.TxType_QUERY_CONTRACT, TxId: "tx2",
+				ContractName: "claim001", Method: "find_by_file_hash"},
+		},
+		{
+			name: "method not allowed",
+			payload: &common.Payload{ChainId: "chain1", TxType: common.TxType_INVOKE_CONTRACT, TxId: "tx3",
+				ContractName: "claim001", Method: "delete"},
+			wantErr: true,
+		},
+		{
+			name: "contract not allowed",
+			payload: &common.Payload{ChainId: "chain1", TxType: common.TxType_INVOKE_CONTRACT, TxId: "tx4",
+				ContractName: "asset", Method: "save"},
+			wantErr: true,
+		},
+		{
+			name: "value exceeds limit",
+			payload: &common.Payload{ChainId: "chain1", TxType: common.TxType_INVOKE_CONTRACT, TxId: "tx5",
+				ContractName: "claim001", Method: "save",
+				Parameters: []*common.KeyValuePair{{Key: "amount", Value: []byte("101")}}},
+			wantErr: true,
+		},
+		{
+			name: "evm calldata with value limits",
+			payload: &common.Payload{ChainId: "chain1", TxType: common.TxType_INVOKE_CONTRACT, TxId: "tx6",
+				ContractName: "claim001", Method: "save",
+				Parameters: []*common.KeyValuePair{{Key: "data", Value: []byte("a9059cbb")}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature, err := signer.Sign(tt.payload)
+			require.Equal(t, tt.wantErr, err != nil, err)
+			if tt.wantErr {
+				return
+			}
+
+			payloadBz, err := proto.Marshal(tt.payload)
+			require.Nil(t, err)
+			verified, err := signer.Cert().PublicKey.VerifyWithOpts(payloadBz, signature, &crypto.SignOpts{
+				Hash: signer.HashType(),
+				UID:  crypto.CRYPTO_DEFAULT_UID,
+			})
+			require.Nil(t, err)
+			require.True(t, verified)
+		})
+	}
+
+	// every request is audited
+	file, err := os.Open(auditPath)
+	require.Nil(t, err)
+	defer file.Close()
+	var records []*remotesigner.AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := &remotesigner.AuditRecord{}
+		require.Nil(t, json.Unmarshal(scanner.Bytes(), record))
+		records = append(records, record)
+	}
+	require.Len(t, records, len(tests))
+	for i, tt := range tests {
+		require.Equal(t, tt.payload.TxId, records[i].TxId)
+		require.Equal(t, !tt.wantErr, records[i].Approved)
+		require.Equal(t, tt.wantErr, records[i].Reason != "")
+	}
+}
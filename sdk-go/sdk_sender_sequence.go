@@ -0,0 +1,90 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+)
+
+const (
+	// QuerySenderSequenceMethod 查询账户下一个交易序号的方法，由节点rpc服务响应，需链版本>=v2.3.5
+	QuerySenderSequenceMethod = "GET_SENDER_SEQUENCE"
+	// KeySenderSequenceAddress 查询账户下一个交易序号的账户地址参数
+	KeySenderSequenceAddress = "address"
+)
+
+// GetSenderSequence 查询账户下一个应使用的交易序号，序号从1开始，交易执行成功后加1；
+// address为空时查询当前客户端用户的账户
+func (cc *ChainClient) GetSenderSequence(address string) (uint64, error) {
+	cc.logger.Debugf("[SDK] begin to QUERY system contract, [method:%s]/[address:%s]",
+		QuerySenderSequenceMethod, address)
+
+	var pairs []*common.KeyValuePair
+	if address != "" {
+		pairs = []*common.KeyValuePair{
+			{
+				Key:   KeySenderSequenceAddress,
+				Value: []byte(address),
+			},
+		}
+	}
+	payload := cc.CreatePayload("", common.TxType_QUERY_CONTRACT, syscontract.SystemContract_ACCOUNT_MANAGER.String(),
+		QuerySenderSequenceMethod, pairs, defaultSeq, nil)
+
+	resp, err := cc.proposalRequest(payload, nil, nil, -1, false)
+	if err != nil {
+		return 0, fmt.Errorf(errStringFormat, payload.TxType, err)
+	}
+
+	if err = utils.CheckProposalRequestResp(resp, true); err != nil {
+		return 0, fmt.Errorf(errStringFormat, payload.TxType, err)
+	}
+
+	sequence, err := strconv.ParseUint(string(resp.ContractResult.Result), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s, parse sender sequence failed, %s", payload.TxType, err)
+	}
+
+	return sequence, nil
+}
+
+// CreateSequencedContractPayload 构造带账户交易序号的合约调用payload，同一账户的交易按序号顺序执行，
+// 序号不是账户下一个序号的交易失败或等待前序交易；交易池中同账户同序号的交易，
+// 可用gas limit高出10%以上的新交易替换
+func (cc *ChainClient) CreateSequencedContractPayload(contractName, method, txId string, kvs []*common.KeyValuePair,
+	sequence uint64, limit *common.Limit) (*common.Payload, error) {
+	if sequence == 0 {
+		return nil, errors.New("sequence must > 0")
+	}
+	if contractName == syscontract.SystemContract_CHAIN_CONFIG.String() {
+		return nil, errors.New("chain config txs use the chain config sequence")
+	}
+	return cc.CreatePayload(txId, common.TxType_INVOKE_CONTRACT, contractName, method, kvs, sequence, limit), nil
+}
+
+// InvokeContractWithSequence 调用合约并指定账户交易序号，参数同InvokeContractWithLimit，
+// sequence可通过GetSenderSequence查询
+func (cc *ChainClient) InvokeContractWithSequence(contractName, method, txId string, kvs []*common.KeyValuePair,
+	timeout int64, withSyncResult bool, sequence uint64, limit *common.Limit) (*common.TxResponse, error) {
+
+	cc.logger.Debugf("[SDK] begin to INVOKE contract, [contractName:%s]/[method:%s]/[txId:%s]/[sequence:%d]",
+		contractName, method, txId, sequence)
+
+	payload, err := cc.CreateSequencedContractPayload(contractName, method, txId, kvs, sequence, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return cc.proposalRequest(payload, nil, nil, timeout, withSyncResult)
+}
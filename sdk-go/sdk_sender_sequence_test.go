@@ -0,0 +1,53 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"testing"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainClient_GetSenderSequence(t *testing.T) {
+	cc, err := newMockChainClient(&common.TxResponse{
+		ContractResult: &common.ContractResult{
+			Message: "OK",
+			Result:  []byte("3"),
+		},
+		Message: "SUCCESS",
+	}, nil, WithConfPath(sdkConfigPathForUT))
+	require.Nil(t, err)
+	defer cc.Stop()
+
+	sequence, err := cc.GetSenderSequence("ZXf9fc99018a7d5d09b8e909e5c7d34c8b4fce9429")
+	require.Nil(t, err)
+	require.Equal(t, uint64(3), sequence)
+
+	sequence, err = cc.GetSenderSequence("")
+	require.Nil(t, err)
+	require.Equal(t, uint64(3), sequence)
+}
+
+func TestChainClient_CreateSequencedContractPayload(t *testing.T) {
+	cc, err := newMockChainClient(nil, nil, WithConfPath(sdkConfigPathForUT))
+	require.Nil(t, err)
+	defer cc.Stop()
+
+	payload, err := cc.CreateSequencedContractPayload("contract1", "save", "", nil, 2, nil)
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), payload.Sequence)
+	require.Equal(t, common.TxType_INVOKE_CONTRACT, payload.TxType)
+
+	_, err = cc.CreateSequencedContractPayload("contract1", "save", "", nil, 0, nil)
+	require.NotNil(t, err)
+	_, err = cc.CreateSequencedContractPayload(syscontract.SystemContract_CHAIN_CONFIG.String(), "save", "", nil,
+		2, nil)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// SubscribeWSPath 节点网关的websocket订阅路径，需开启节点的rpc.gateway
+	SubscribeWSPath = "/v1/subscribe/ws"
+
+	subscribeMessageBlock         = "block"
+	subscribeMessageBlockHeader   = "block_header"
+	subscribeMessageTx            = "tx"
+	subscribeMessageContractEvent = "contract_event"
+	subscribeMessageEnd           = "end"
+	subscribeMessageError         = "error"
+)
+
+// subscribeWSMessage json message of the websocket subscribe endpoint
+type subscribeWSMessage struct {
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error *struct {
+		Code    uint32 `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// SubscribeByWebsocket 通过节点网关的websocket json订阅，返回的订阅内容与Subscribe相同，
+// 连接配置的第一个节点；节点启用TLS时dialer必填，未启用时为空则使用websocket.DefaultDialer。
+// 订阅失败时（如节点拒绝订阅请求或连接断开），通道在关闭前返回一个error，正常结束或ctx取消时直接关闭
+func (cc *ChainClient) SubscribeByWebsocket(ctx context.Context, payload *common.Payload,
+	dialer *websocket.Dialer) (<-chan interface{}, error) {
+
+	if _, ok := subscribeWSMethods[payload.Method]; !ok {
+		return nil, fmt.Errorf("subscribe method %s is not supported", payload.Method)
+	}
+	if len(cc.config.nodeList) == 0 {
+		return nil, errors.New("no node configured")
+	}
+	node := cc.config.nodeList[0]
+	scheme := "ws"
+	if node.useTLS {
+		if dialer == nil {
+			return nil, errors.New("dialer with tls config is required when node uses tls")
+		}
+		scheme = "wss"
+	}
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	req, err := cc.GenerateTxRequest(payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	rawTx, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal subscribe tx request failed, %s", err)
+	}
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     node.addr,
+		Path:     SubscribeWSPath,
+		RawQuery: url.Values{"rawTx": []string{base64.StdEncoding.EncodeToString(rawTx)}}.Encode(),
+	}
+
+	conn, _, err := dialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial subscribe websocket failed, %s", err)
+	}
+
+	c := make(chan interface{})
+	// unblock the read when the subscription is cancelled
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	go func() {
+		defer close(c)
+		defer close(stop)
+		defer conn.Close()
+		// fail passes the error to the subscriber, unless it has cancelled the subscription
+		fail := func(err error) {
+			if ctx.Err() != nil {
+				return
+			}
+			cc.logger.Errorf("[SDK] Subscriber by websocket receive failed, %s", err)
+			select {
+			case c <- err:
+			case <-ctx.Done():
+			}
+		}
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				fail(fmt.Errorf("receive subscribe message failed, %s", err))
+				return
+			}
+
+			rets, done, err := decodeSubscribeWSMessage(data)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if done {
+				cc.logger.Debugf("[SDK] Subscriber by websocket got end and stop recv msg")
+				return
+			}
+
+			for _, ret := range rets {
+				select {
+				case c <- ret:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return c, nil
+}
+
+// decodeSubscribeWSMessage decode the subscribed items of the message, done is true when the
+// subscription ends normally
+func decodeSubscribeWSMessage(data []byte) (rets []interface{}, done bool, err error) {
+	var msg subscribeWSMessage
+	if err = json.Unmarshal(data, &msg); err != nil {
+		return nil, false, fmt.Errorf("unmarshal message failed, %s", err)
+	}
+
+	var item proto.Message
+	switch msg.Type {
+	case subscribeMessageEnd:
+		return nil, true, nil
+	case subscribeMessageError:
+		if msg.Error == nil {
+			return nil, false, errors.New("subscribe failed")
+		}
+		return nil, false, fmt.Errorf("subscribe failed, [code:%d]/[msg:%s]", msg.Error.Code, msg.Error.Message)
+	case subscribeMessageBlock:
+		item = &common.BlockInfo{}
+	case subscribeMessageBlockHeader:
+		item = &common.BlockHeader{}
+	case subscribeMessageTx:
+		item = &common.Transaction{}
+	case subscribeMessageContractEvent:
+		item = &common.ContractEventInfoList{}
+	default:
+		return nil, false, fmt.Errorf("unknown message type %s", msg.Type)
+	}
+
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(bytes.NewReader(msg.Data), item); err != nil {
+		return nil, false, fmt.Errorf("unmarshal %s failed, %s", msg.Type, err)
+	}
+
+	// contract events are sent one by one, the same as Subscribe
+	if events, ok := item.(*common.ContractEventInfoList); ok {
+		for _, event := range events.ContractEvents {
+			rets = append(rets, event)
+		}
+		return rets, false, nil
+	}
+	return []interface{}{item}, false, nil
+}
+
+// subscribeWSMethods methods supported by SubscribeByWebsocket
+var subscribeWSMethods = map[string]struct{}{
+	syscontract.SubscribeFunction_SUBSCRIBE_BLOCK.String():          {},
+	syscontract.SubscribeFunction_SUBSCRIBE_TX.String():             {},
+	syscontract.SubscribeFunction_SUBSCRIBE_CONTRACT_EVENT.String(): {},
+}
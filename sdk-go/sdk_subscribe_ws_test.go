@@ -0,0 +1,113 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeSubscribeWSMessage(t *testing.T) {
+	rets, done, err := decodeSubscribeWSMessage(
+		[]byte(`{"type":"block_header","data":{"chain_id":"chain1","block_height":"3"}}`))
+	require.Nil(t, err)
+	require.False(t, done)
+	require.Equal(t, []interface{}{&common.BlockHeader{ChainId: "chain1", BlockHeight: 3}}, rets)
+
+	rets, done, err = decodeSubscribeWSMessage([]byte(`{"type":"contract_event","data":{"contract_events":[` +
+		`{"topic":"t1","block_height":"5"},{"topic":"t2","block_height":"5"}]}}`))
+	require.Nil(t, err)
+	require.False(t, done)
+	require.Len(t, rets, 2)
+	require.Equal(t, "t2", rets[1].(*common.ContractEventInfo).Topic)
+
+	_, done, err = decodeSubscribeWSMessage([]byte(`{"type":"end"}`))
+	require.Nil(t, err)
+	require.True(t, done)
+
+	_, _, err = decodeSubscribeWSMessage([]byte(`{"type":"error","error":{"code":7,"message":"denied"}}`))
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "denied")
+
+	_, _, err = decodeSubscribeWSMessage([]byte(`{"type":"unknown"}`))
+	require.NotNil(t, err)
+}
+
+func TestChainClient_SubscribeByWebsocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var messages atomic.Value
+	messages.Store([]string{`{"type":"tx","data":{"payload":{"tx_id":"tx1"}}}`, `{"type":"end"}`})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != SubscribeWSPath || r.URL.Query().Get("rawTx") == "" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, msg := range messages.Load().([]string) {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		}
+	}))
+	defer server.Close()
+
+	cc, err := newMockChainClient(nil, nil, WithConfPath(sdkConfigPathForUT))
+	require.Nil(t, err)
+	defer cc.Stop()
+	cc.config.nodeList[0].addr = strings.TrimPrefix(server.URL, "http://")
+	cc.config.nodeList[0].useTLS = false
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = cc.SubscribeByWebsocket(ctx, cc.CreatePayload("", common.TxType_SUBSCRIBE, "", "UNKNOWN", nil,
+		defaultSeq, nil), nil)
+	require.NotNil(t, err)
+
+	c, err := cc.SubscribeByWebsocket(ctx, cc.CreateSubscribeTxPayload(0, -1, "", nil), nil)
+	require.Nil(t, err)
+	tx, ok := (<-c).(*common.Transaction)
+	require.True(t, ok)
+	require.Equal(t, "tx1", tx.Payload.TxId)
+	_, ok = <-c
+	require.False(t, ok)
+
+	// the errors of node are returned on the channel before it is closed
+	messages.Store([]string{`{"type":"error","error":{"code":8,"message":"rejected by black list"}}`})
+	c, err = cc.SubscribeByWebsocket(ctx, cc.CreateSubscribeTxPayload(0, -1, "", nil), nil)
+	require.Nil(t, err)
+	err, ok = (<-c).(error)
+	require.True(t, ok)
+	require.Contains(t, err.Error(), "rejected by black list")
+	_, ok = <-c
+	require.False(t, ok)
+
+	// so is a broken connection
+	messages.Store([]string{})
+	c, err = cc.SubscribeByWebsocket(ctx, cc.CreateSubscribeTxPayload(0, -1, "", nil), nil)
+	require.Nil(t, err)
+	_, ok = (<-c).(error)
+	require.True(t, ok)
+	_, ok = <-c
+	require.False(t, ok)
+
+	cc.config.nodeList[0].useTLS = true
+	_, err = cc.SubscribeByWebsocket(ctx, cc.CreateSubscribeTxPayload(0, -1, "", nil), nil)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,657 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chainmaker.org/chainmaker/common/v2/crypto"
+	"chainmaker.org/chainmaker/common/v2/crypto/asym"
+	"chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/config"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"chainmaker.org/chainmaker/protocol/v2"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"github.com/gogo/protobuf/proto"
+)
+
+const (
+	// TxEnvelopeVersion version of the tx envelope format
+	TxEnvelopeVersion = "1"
+
+	// maxSummaryValueLen parameter values longer than it are summarized by size and hash, such as byte codes
+	maxSummaryValueLen = 256
+	// publicAdminOrgId org id of the admin trust roots in Public mode
+	publicAdminOrgId = "public"
+)
+
+var (
+	// policyMajorityAdmin is used for resources without a default endorsement policy
+	policyMajorityAdmin = &accesscontrol.Policy{
+		Rule:     string(protocol.RuleMajority),
+		RoleList: []string{string(protocol.RoleAdmin)},
+	}
+	policyAnyAdmin = &accesscontrol.Policy{
+		Rule:     string(protocol.RuleAny),
+		RoleList: []string{string(protocol.RoleAdmin)},
+	}
+	policySelfAdmin = &accesscontrol.Policy{
+		Rule:     string(protocol.RuleSelf),
+		RoleList: []string{string(protocol.RoleAdmin)},
+	}
+	policyAnyWriter = &accesscontrol.Policy{
+		Rule: string(protocol.RuleAny),
+		RoleList: []string{string(protocol.RoleClient), string(protocol.RoleAdmin),
+			string(protocol.RoleLight)},
+	}
+
+	// defaultEndorsementPolicies default policies of the node access control for resources
+	// which differ from policyMajorityAdmin, overridden by the resource policies of chain config
+	defaultEndorsementPolicies = map[string]*accesscontrol.Policy{
+		resourceName(syscontract.SystemContract_CHAIN_CONFIG.String(),
+			syscontract.ChainConfigFunction_NODE_ID_UPDATE.String()): policySelfAdmin,
+		resourceName(syscontract.SystemContract_CHAIN_CONFIG.String(),
+			syscontract.ChainConfigFunction_TRUST_ROOT_UPDATE.String()): policySelfAdmin,
+		resourceName(syscontract.SystemContract_CONTRACT_MANAGE.String(),
+			syscontract.ContractManageFunction_INIT_CONTRACT.String()): policyAnyAdmin,
+		resourceName(syscontract.SystemContract_CERT_MANAGE.String(),
+			syscontract.CertManageFunction_CERT_ADD.String()): policyAnyWriter,
+		resourceName(syscontract.SystemContract_CERT_MANAGE.String(),
+			syscontract.CertManageFunction_CERTS_FREEZE.String()): policyAnyAdmin,
+		resourceName(syscontract.SystemContract_CERT_MANAGE.String(),
+			syscontract.CertManageFunction_CERTS_UNFREEZE.String()): policyAnyAdmin,
+		resourceName(syscontract.SystemContract_CERT_MANAGE.String(),
+			syscontract.CertManageFunction_CERTS_DELETE.String()): policyAnyAdmin,
+		resourceName(syscontract.SystemContract_CERT_MANAGE.String(),
+			syscontract.CertManageFunction_CERTS_REVOKE.String()): policyAnyAdmin,
+		resourceName(syscontract.SystemContract_CERT_MANAGE.String(),
+			syscontract.CertManageFunction_CERT_ALIAS_ADD.String()): policyAnyWriter,
+		resourceName(syscontract.SystemContract_CERT_MANAGE.String(),
+			syscontract.CertManageFunction_CERT_ALIAS_UPDATE.String()): policyAnyAdmin,
+		resourceName(syscontract.SystemContract_CERT_MANAGE.String(),
+			syscontract.CertManageFunction_CERTS_ALIAS_DELETE.String()): policyAnyAdmin,
+	}
+)
+
+// TxEnvelope portable partially signed tx, used to collect the endorsements of several orgs offline.
+// Create it with NewTxEnvelope, pass the json of Marshal to the signers, who Sign it or sign it by
+// ChainClient.SignTxEnvelope, merge the signed envelopes with MergeTxEnvelopes, and submit it by
+// ChainClient.SubmitTxEnvelope once the endorsement policy is satisfied.
+type TxEnvelope struct {
+	Version string `json:"version"`
+	// Summary human readable description of Payload, it must match Payload
+	Summary *PayloadSummary `json:"summary"`
+	// Payload marshaled common.Payload, signed by the endorsements
+	Payload      []byte                 `json:"payload"`
+	Endorsements []*EnvelopeEndorsement `json:"endorsements"`
+}
+
+// PayloadSummary human readable description of a payload
+type PayloadSummary struct {
+	ChainId string `json:"chain_id"`
+	TxId    string `json:"tx_id"`
+	TxType  string `json:"tx_type"`
+	// Resource resource name of the endorsement policy
+	Resource       string              `json:"resource"`
+	ContractName   string              `json:"contract_name"`
+	Method         string              `json:"method"`
+	Sequence       uint64              `json:"sequence"`
+	Timestamp      string              `json:"timestamp"`
+	ExpirationTime string              `json:"expiration_time,omitempty"`
+	Parameters     []*SummaryParameter `json:"parameters"`
+}
+
+// SummaryParameter human readable description of a payload parameter
+type SummaryParameter struct {
+	Key string `json:"key"`
+	// Value utf8 value, or hex value with 0x prefix, empty if the value is longer than 256 bytes
+	Value string `json:"value,omitempty"`
+	Size  int    `json:"size"`
+	// Sha256 hash of values longer than 256 bytes
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+// EnvelopeEndorsement endorsement of a tx envelope
+type EnvelopeEndorsement struct {
+	OrgId      string `json:"org_id"`
+	MemberType string `json:"member_type"`
+	MemberInfo []byte `json:"member_info"`
+	Signature  []byte `json:"signature"`
+}
+
+// TxEnvelopeCheckResult result of checking the endorsements of a tx envelope against the resource policy
+type TxEnvelopeCheckResult struct {
+	Resource string                `json:"resource"`
+	Policy   *accesscontrol.Policy `json:"policy"`
+	// Endorsements check results in the order of TxEnvelope.Endorsements
+	Endorsements []*EndorsementCheck `json:"endorsements"`
+	Satisfied    bool                `json:"satisfied"`
+	// Reason why the policy is not satisfied
+	Reason string `json:"reason,omitempty"`
+}
+
+// EndorsementCheck check result of an endorsement
+type EndorsementCheck struct {
+	OrgId string `json:"org_id"`
+	// Role role of the signer, empty if unknown
+	Role  string `json:"role,omitempty"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+
+	// voter distinct voter of policy, org id or admin public key in Public mode
+	voter string
+}
+
+// NewTxEnvelope create tx envelope of payload, tx id and timestamp are set if they are empty
+func NewTxEnvelope(payload *common.Payload) (*TxEnvelope, error) {
+	if payload == nil {
+		return nil, errors.New("payload must not be nil")
+	}
+	if payload.TxId == "" {
+		payload.TxId = utils.GetTimestampTxId()
+	}
+	if payload.Timestamp == 0 {
+		payload.Timestamp = time.Now().Unix()
+	}
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload failed, %s", err)
+	}
+	return &TxEnvelope{
+		Version:      TxEnvelopeVersion,
+		Summary:      NewPayloadSummary(payload),
+		Payload:      payloadBytes,
+		Endorsements: []*EnvelopeEndorsement{},
+	}, nil
+}
+
+// UnmarshalTxEnvelope unmarshal tx envelope from json and check it
+func UnmarshalTxEnvelope(data []byte) (*TxEnvelope, error) {
+	envelope := &TxEnvelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return nil, fmt.Errorf("unmarshal tx envelope failed, %s", err)
+	}
+	if envelope.Version != TxEnvelopeVersion {
+		return nil, fmt.Errorf("tx envelope version %s is not supported, want %s", envelope.Version,
+			TxEnvelopeVersion)
+	}
+	if _, err := envelope.GetPayload(); err != nil {
+		return nil, err
+	}
+	if _, err := envelope.GetEndorsements(); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// Marshal marshal tx envelope to indented json
+func (e *TxEnvelope) Marshal() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// GetPayload unmarshal payload, the payload must be canonically marshaled and match the summary
+func (e *TxEnvelope) GetPayload() (*common.Payload, error) {
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(e.Payload, payload); err != nil {
+		return nil, fmt.Errorf("unmarshal payload failed, %s", err)
+	}
+	// signers sign the marshaled payload, which must be the same bytes
+	canonical, err := proto.Marshal(payload)
+	if err != nil || !bytes.Equal(canonical, e.Payload) {
+		return nil, errors.New("payload is not canonically marshaled")
+	}
+
+	expect, err := json.Marshal(NewPayloadSummary(payload))
+	if err != nil {
+		return nil, err
+	}
+	got, err := json.Marshal(e.Summary)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(expect, got) {
+		return nil, errors.New("summary does not match payload")
+	}
+	return payload, nil
+}
+
+// GetEndorsements returns the endorsements of envelope
+func (e *TxEnvelope) GetEndorsements() ([]*common.EndorsementEntry, error) {
+	entries := make([]*common.EndorsementEntry, 0, len(e.Endorsements))
+	for _, endorsement := range e.Endorsements {
+		memberType, ok := accesscontrol.MemberType_value[endorsement.MemberType]
+		if !ok {
+			return nil, fmt.Errorf("invalid member type [%s]", endorsement.MemberType)
+		}
+		entries = append(entries, &common.EndorsementEntry{
+			Signer: &accesscontrol.Member{
+				OrgId:      endorsement.OrgId,
+				MemberInfo: endorsement.MemberInfo,
+				MemberType: accesscontrol.MemberType(memberType),
+			},
+			Signature: endorsement.Signature,
+		})
+	}
+	return entries, nil
+}
+
+// AddEndorsement add endorsement to envelope, the endorsements of a signer already in envelope are ignored
+func (e *TxEnvelope) AddEndorsement(entry *common.EndorsementEntry) error {
+	if entry == nil || entry.Signer == nil || len(entry.Signature) == 0 {
+		return errors.New("endorsement signer and signature must not be empty")
+	}
+	for _, endorsement := range e.Endorsements {
+		if endorsement.OrgId == entry.Signer.OrgId &&
+			endorsement.MemberType == entry.Signer.MemberType.String() &&
+			bytes.Equal(endorsement.MemberInfo, entry.Signer.MemberInfo) {
+			return nil
+		}
+	}
+	e.Endorsements = append(e.Endorsements, &EnvelopeEndorsement{
+		OrgId:      entry.Signer.OrgId,
+		MemberType: entry.Signer.MemberType.String(),
+		MemberInfo: entry.Signer.MemberInfo,
+		Signature:  entry.Signature,
+	})
+	return nil
+}
+
+// Sign sign the payload of envelope by signer and add the endorsement
+func (e *TxEnvelope) Sign(signer Signer) error {
+	payload, err := e.GetPayload()
+	if err != nil {
+		return err
+	}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return fmt.Errorf("sign payload failed, %s", err)
+	}
+	member, err := signer.NewMember()
+	if err != nil {
+		return err
+	}
+	return e.AddEndorsement(&common.EndorsementEntry{
+		Signer:    member,
+		Signature: signature,
+	})
+}
+
+// MergeTxEnvelopes merge the endorsements of envelopes of the same payload
+func MergeTxEnvelopes(envelopes ...*TxEnvelope) (*TxEnvelope, error) {
+	if len(envelopes) == 0 {
+		return nil, errors.New("no envelope to merge")
+	}
+	first := envelopes[0]
+	if _, err := first.GetPayload(); err != nil {
+		return nil, err
+	}
+	merged := &TxEnvelope{
+		Version:      first.Version,
+		Summary:      first.Summary,
+		Payload:      first.Payload,
+		Endorsements: []*EnvelopeEndorsement{},
+	}
+	for i, envelope := range envelopes {
+		if !bytes.Equal(envelope.Payload, first.Payload) {
+			return nil, fmt.Errorf("payload of envelope %d differs from envelope 0", i)
+		}
+		entries, err := envelope.GetEndorsements()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if err = merged.AddEndorsement(entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return merged, nil
+}
+
+// NewPayloadSummary returns the human readable description of payload
+func NewPayloadSummary(payload *common.Payload) *PayloadSummary {
+	summary := &PayloadSummary{
+		ChainId:      payload.ChainId,
+		TxId:         payload.TxId,
+		TxType:       payload.TxType.String(),
+		Resource:     resourceName(payload.ContractName, payload.Method),
+		ContractName: payload.ContractName,
+		Method:       payload.Method,
+		Sequence:     payload.Sequence,
+		Timestamp:    time.Unix(payload.Timestamp, 0).UTC().Format(time.RFC3339),
+		Parameters:   make([]*SummaryParameter, 0, len(payload.Parameters)),
+	}
+	if payload.ExpirationTime > 0 {
+		summary.ExpirationTime = time.Unix(payload.ExpirationTime, 0).UTC().Format(time.RFC3339)
+	}
+	for _, kv := range payload.Parameters {
+		parameter := &SummaryParameter{
+			Key:  kv.Key,
+			Size: len(kv.Value),
+		}
+		switch {
+		case len(kv.Value) > maxSummaryValueLen:
+			sum := sha256.Sum256(kv.Value)
+			parameter.Sha256 = hex.EncodeToString(sum[:])
+		case utf8.Valid(kv.Value):
+			parameter.Value = string(kv.Value)
+		default:
+			parameter.Value = "0x" + hex.EncodeToString(kv.Value)
+		}
+		summary.Parameters = append(summary.Parameters, parameter)
+	}
+	return summary
+}
+
+// SignTxEnvelope sign the payload of envelope by the user of chain client and add the endorsement
+func (cc *ChainClient) SignTxEnvelope(envelope *TxEnvelope) error {
+	payload, err := envelope.GetPayload()
+	if err != nil {
+		return err
+	}
+	entry, err := cc.SignPayload(payload)
+	if err != nil {
+		return err
+	}
+	return envelope.AddEndorsement(entry)
+}
+
+// CheckTxEnvelope check the endorsements of envelope against the resource policy in current chain config,
+// the default policies of node are used for resources without policy in chain config.
+// The roles of public key members except admins are unknown locally.
+// The result is advisory, it mirrors the access control of node to tell the signers whether more endorsements
+// are needed, the node checks the policy again when the tx is submitted and its verdict is authoritative.
+func (cc *ChainClient) CheckTxEnvelope(envelope *TxEnvelope) (*TxEnvelopeCheckResult, error) {
+	payload, err := envelope.GetPayload()
+	if err != nil {
+		return nil, err
+	}
+	if payload.ChainId != cc.chainId {
+		return nil, fmt.Errorf("payload chain id expect %s, got %s", cc.chainId, payload.ChainId)
+	}
+	entries, err := envelope.GetEndorsements()
+	if err != nil {
+		return nil, err
+	}
+	chainConfig, err := cc.GetChainConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TxEnvelopeCheckResult{
+		Resource: resourceName(payload.ContractName, payload.Method),
+	}
+	result.Policy = endorsementPolicy(chainConfig, result.Resource)
+
+	now := time.Now()
+	for _, entry := range entries {
+		check := &EndorsementCheck{OrgId: entry.Signer.OrgId}
+		check.Role, check.voter, err = cc.verifyEndorsement(entry, envelope.Payload, chainConfig, now)
+		if err != nil {
+			check.Error = err.Error()
+		} else {
+			check.Valid = true
+		}
+		result.Endorsements = append(result.Endorsements, check)
+	}
+
+	if err = cc.evaluatePolicy(result.Policy, result.Endorsements, chainConfig, payload); err != nil {
+		result.Reason = err.Error()
+	} else {
+		result.Satisfied = true
+	}
+	return result, nil
+}
+
+// SubmitTxEnvelope send the tx of envelope to node after checking its endorsements satisfy the resource policy,
+// the local check only saves a round trip for envelopes that are bound to be rejected, node may still reject the tx
+func (cc *ChainClient) SubmitTxEnvelope(envelope *TxEnvelope, timeout int64,
+	withSyncResult bool) (*common.TxResponse, error) {
+	result, err := cc.CheckTxEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Satisfied {
+		return nil, fmt.Errorf("endorsements of tx [%s] do not satisfy policy of [%s], %s",
+			envelope.Summary.TxId, result.Resource, result.Reason)
+	}
+
+	payload, err := envelope.GetPayload()
+	if err != nil {
+		return nil, err
+	}
+	endorsers, err := envelope.GetEndorsements()
+	if err != nil {
+		return nil, err
+	}
+	return cc.proposalRequest(payload, endorsers, nil, timeout, withSyncResult)
+}
+
+// verifyEndorsement verify the signature of endorsement over message, returns the role and voter of signer
+func (cc *ChainClient) verifyEndorsement(entry *common.EndorsementEntry, message []byte,
+	chainConfig *config.ChainConfig, at time.Time) (string, string, error) {
+	member := entry.Signer
+	if member.MemberType != accesscontrol.MemberType_PUBLIC_KEY {
+		cert, err := cc.verifyCertMemberSignature(member, message, entry.Signature, chainConfig, at)
+		if err != nil {
+			return "", "", err
+		}
+		var role string
+		if len(cert.Subject.OrganizationalUnit) > 0 {
+			role = strings.ToUpper(cert.Subject.OrganizationalUnit[0])
+		}
+		return role, member.OrgId, nil
+	}
+
+	publicKey, err := asym.PublicKeyFromPEM(member.MemberInfo)
+	if err != nil {
+		return "", "", fmt.Errorf("parse public key failed, %s", err)
+	}
+	hashType, ok := crypto.HashAlgoMap[chainConfig.Crypto.Hash]
+	if !ok {
+		return "", "", fmt.Errorf("invalid hash type [%s]", chainConfig.Crypto.Hash)
+	}
+	verified, err := publicKey.VerifyWithOpts(message, entry.Signature, &crypto.SignOpts{
+		Hash: hashType,
+		UID:  crypto.CRYPTO_DEFAULT_UID,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if !verified {
+		return "", "", errors.New("invalid signature")
+	}
+
+	pkBytes, err := publicKey.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	adminOrgId := member.OrgId
+	voter := member.OrgId
+	if cc.authType == Public {
+		adminOrgId = publicAdminOrgId
+		voter = hex.EncodeToString(pkBytes)
+	}
+	// the trust roots of public key modes are admin public keys
+	for _, trustRoot := range chainConfig.TrustRoots {
+		if trustRoot.OrgId != adminOrgId {
+			continue
+		}
+		for _, root := range trustRoot.Root {
+			rootKey, err := asym.PublicKeyFromPEM([]byte(root))
+			if err != nil {
+				continue
+			}
+			rootBytes, err := rootKey.Bytes()
+			if err == nil && bytes.Equal(rootBytes, pkBytes) {
+				return string(protocol.RoleAdmin), voter, nil
+			}
+		}
+	}
+	return "", voter, nil
+}
+
+// evaluatePolicy evaluate policy with valid endorsements like the access control of node
+func (cc *ChainClient) evaluatePolicy(policy *accesscontrol.Policy, checks []*EndorsementCheck,
+	chainConfig *config.ChainConfig, payload *common.Payload) error {
+	rule := protocol.Rule(strings.ToUpper(policy.Rule))
+	total := cc.countPolicyVoters(chainConfig)
+
+	switch rule {
+	case protocol.RuleForbidden:
+		return errors.New("resource is forbidden")
+	case protocol.RuleMajority:
+		valid := countValidVoters(checks, nil, []string{string(protocol.RoleAdmin)})
+		if valid*2 > total {
+			return nil
+		}
+		return fmt.Errorf("%d valid admin endorsements required, %d received", total/2+1, valid)
+	case protocol.RuleSelf:
+		targetOrg := ""
+		for _, kv := range payload.Parameters {
+			if kv.Key == utils.KeyChainConfigContractOrgId {
+				targetOrg = string(kv.Value)
+			}
+		}
+		if targetOrg == "" {
+			return errors.New("SELF rule requires the org id of the target")
+		}
+		if countValidVoters(checks, []string{targetOrg}, []string{string(protocol.RoleAdmin)}) > 0 {
+			return nil
+		}
+		return fmt.Errorf("admin endorsement of target org [%s] required", targetOrg)
+	case protocol.RuleAny:
+		if countValidVoters(checks, policy.OrgList, policy.RoleList) > 0 {
+			return nil
+		}
+		return errors.New("no valid endorsement meets the policy")
+	case protocol.RuleAll:
+		required := total
+		if len(policy.OrgList) > 0 {
+			required = len(policy.OrgList)
+		}
+		valid := countValidVoters(checks, policy.OrgList, policy.RoleList)
+		if valid >= required {
+			return nil
+		}
+		return fmt.Errorf("%d valid endorsements required, %d received", required, valid)
+	}
+
+	valid := countValidVoters(checks, policy.OrgList, policy.RoleList)
+	nums := strings.Split(policy.Rule, "/")
+	switch len(nums) {
+	case 1:
+		threshold, err := strconv.Atoi(nums[0])
+		if err != nil {
+			return fmt.Errorf("unrecognized rule [%s]", policy.Rule)
+		}
+		if valid >= threshold {
+			return nil
+		}
+		return fmt.Errorf("%d valid endorsements required, %d received", threshold, valid)
+	case 2:
+		numerator, err := strconv.Atoi(nums[0])
+		denominator, err2 := strconv.Atoi(nums[1])
+		if err != nil || err2 != nil {
+			return fmt.Errorf("unrecognized rule [%s]", policy.Rule)
+		}
+		if denominator <= 0 {
+			denominator = total
+		}
+		base := total
+		if len(policy.OrgList) > 0 {
+			base = len(policy.OrgList)
+		}
+		required := float64(base) * float64(numerator) / float64(denominator)
+		if float64(valid) >= required {
+			return nil
+		}
+		return fmt.Errorf("%f valid endorsements required, %d received", required, valid)
+	default:
+		return fmt.Errorf("unrecognized rule [%s]", policy.Rule)
+	}
+}
+
+// countPolicyVoters returns the number of orgs, or the number of admins in Public mode
+func (cc *ChainClient) countPolicyVoters(chainConfig *config.ChainConfig) int {
+	if cc.authType != Public {
+		return len(chainConfig.TrustRoots)
+	}
+	count := 0
+	for _, trustRoot := range chainConfig.TrustRoots {
+		if trustRoot.OrgId == publicAdminOrgId {
+			count += len(trustRoot.Root)
+		}
+	}
+	return count
+}
+
+// countValidVoters count distinct voters of valid endorsements in orgList with roles in roleList,
+// empty lists match any org or role
+func countValidVoters(checks []*EndorsementCheck, orgList, roleList []string) int {
+	voters := make(map[string]struct{})
+	for _, check := range checks {
+		if !check.Valid {
+			continue
+		}
+		if len(orgList) > 0 && !containsString(orgList, check.OrgId) {
+			continue
+		}
+		if len(roleList) > 0 && !containsRole(roleList, check.Role) {
+			continue
+		}
+		voters[check.voter] = struct{}{}
+	}
+	return len(voters)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRole(roleList []string, role string) bool {
+	for _, item := range roleList {
+		if strings.EqualFold(item, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// endorsementPolicy returns the policy of resource in chain config, or the default policy of node
+func endorsementPolicy(chainConfig *config.ChainConfig, resource string) *accesscontrol.Policy {
+	for _, resourcePolicy := range chainConfig.ResourcePolicies {
+		if resourcePolicy.ResourceName == resource && resourcePolicy.Policy != nil {
+			return resourcePolicy.Policy
+		}
+	}
+	if policy, ok := defaultEndorsementPolicies[resource]; ok {
+		return policy
+	}
+	return policyMajorityAdmin
+}
+
+func resourceName(contractName, method string) string {
+	return contractName + "-" + method
+}
@@ -0,0 +1,144 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"chainmaker.org/chainmaker/common/v2/crypto/asym"
+	"chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/pb-go/v2/config"
+	"chainmaker.org/chainmaker/pb-go/v2/syscontract"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCertModeSigner(t *testing.T, user string) *CertModeSigner {
+	dir := "./testdata/crypto-config/wx-org.chainmaker.org/user/" + user + "/"
+	keyPem, err := ioutil.ReadFile(dir + user + ".sign.key")
+	require.Nil(t, err)
+	privateKey, err := asym.PrivateKeyFromPEM(keyPem, nil)
+	require.Nil(t, err)
+	certPem, err := ioutil.ReadFile(dir + user + ".sign.crt")
+	require.Nil(t, err)
+	cert, err := utils.ParseCert(certPem)
+	require.Nil(t, err)
+	return &CertModeSigner{PrivateKey: privateKey, Cert: cert, OrgId: "wx-org.chainmaker.org"}
+}
+
+func newTestEnvelope(t *testing.T) *TxEnvelope {
+	envelope, err := NewTxEnvelope(&common.Payload{
+		ChainId:      "chain1",
+		TxType:       common.TxType_INVOKE_CONTRACT,
+		ContractName: syscontract.SystemContract_CHAIN_CONFIG.String(),
+		Method:       syscontract.ChainConfigFunction_CORE_UPDATE.String(),
+		Parameters: []*common.KeyValuePair{
+			{Key: "tx_scheduler_timeout", Value: []byte("15")},
+			{Key: "bin", Value: []byte{0xff, 0x01}},
+			{Key: "byte_code", Value: make([]byte, 1024)},
+		},
+		Sequence: 2,
+	})
+	require.Nil(t, err)
+	return envelope
+}
+
+func TestTxEnvelope(t *testing.T) {
+	envelope := newTestEnvelope(t)
+	require.NotEmpty(t, envelope.Summary.TxId)
+	require.Equal(t, "CHAIN_CONFIG-CORE_UPDATE", envelope.Summary.Resource)
+	require.Equal(t, "15", envelope.Summary.Parameters[0].Value)
+	require.Equal(t, "0xff01", envelope.Summary.Parameters[1].Value)
+	require.Empty(t, envelope.Summary.Parameters[2].Value)
+	require.Equal(t, 1024, envelope.Summary.Parameters[2].Size)
+	require.NotEmpty(t, envelope.Summary.Parameters[2].Sha256)
+
+	admin := newTestCertModeSigner(t, "admin1")
+	client := newTestCertModeSigner(t, "client1")
+	signedByAdmin := newTestEnvelopeCopy(t, envelope)
+	require.Nil(t, signedByAdmin.Sign(admin))
+	// signing twice keeps one endorsement of the signer
+	require.Nil(t, signedByAdmin.Sign(admin))
+	require.Len(t, signedByAdmin.Endorsements, 1)
+	signedByClient := newTestEnvelopeCopy(t, envelope)
+	require.Nil(t, signedByClient.Sign(client))
+
+	merged, err := MergeTxEnvelopes(signedByAdmin, signedByClient)
+	require.Nil(t, err)
+	require.Len(t, merged.Endorsements, 2)
+
+	data, err := merged.Marshal()
+	require.Nil(t, err)
+	unmarshaled, err := UnmarshalTxEnvelope(data)
+	require.Nil(t, err)
+	entries, err := unmarshaled.GetEndorsements()
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, accesscontrol.MemberType_CERT, entries[0].Signer.MemberType)
+
+	// the summary must describe the payload
+	tampered := strings.Replace(string(data), `"value": "15"`, `"value": "16"`, 1)
+	_, err = UnmarshalTxEnvelope([]byte(tampered))
+	require.NotNil(t, err)
+
+	// envelopes of different payloads can not be merged
+	_, err = MergeTxEnvelopes(merged, newTestEnvelope(t))
+	require.NotNil(t, err)
+}
+
+func newTestEnvelopeCopy(t *testing.T, envelope *TxEnvelope) *TxEnvelope {
+	data, err := envelope.Marshal()
+	require.Nil(t, err)
+	envelopeCopy, err := UnmarshalTxEnvelope(data)
+	require.Nil(t, err)
+	return envelopeCopy
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	cc := &ChainClient{authType: PermissionedWithCert}
+	chainConfig := &config.ChainConfig{
+		TrustRoots: []*config.TrustRootConfig{
+			{OrgId: "org1"}, {OrgId: "org2"}, {OrgId: "org3"}, {OrgId: "org4"},
+		},
+	}
+	payload := &common.Payload{Parameters: []*common.KeyValuePair{{Key: "org_id", Value: []byte("org2")}}}
+	checks := []*EndorsementCheck{
+		{OrgId: "org1", Role: "ADMIN", Valid: true, voter: "org1"},
+		{OrgId: "org1", Role: "ADMIN", Valid: true, voter: "org1"},
+		{OrgId: "org2", Role: "ADMIN", Valid: true, voter: "org2"},
+		{OrgId: "org3", Role: "CLIENT", Valid: true, voter: "org3"},
+		{OrgId: "org4", Role: "ADMIN", Valid: false, voter: "org4"},
+	}
+
+	tests := []struct {
+		policy    *accesscontrol.Policy
+		satisfied bool
+	}{
+		{policyMajorityAdmin, false},
+		{policyAnyAdmin, true},
+		{policySelfAdmin, true},
+		{&accesscontrol.Policy{Rule: "ALL", RoleList: []string{"admin"}}, false},
+		{&accesscontrol.Policy{Rule: "ALL", OrgList: []string{"org1", "org2"}, RoleList: []string{"admin"}}, true},
+		{&accesscontrol.Policy{Rule: "2", RoleList: []string{"admin"}}, true},
+		{&accesscontrol.Policy{Rule: "3", RoleList: []string{"admin"}}, false},
+		{&accesscontrol.Policy{Rule: "3"}, true},
+		{&accesscontrol.Policy{Rule: "1/2", RoleList: []string{"admin"}}, true},
+		{&accesscontrol.Policy{Rule: "3/4", RoleList: []string{"admin"}}, false},
+		{&accesscontrol.Policy{Rule: "FORBIDDEN"}, false},
+	}
+	for _, tt := range tests {
+		err := cc.evaluatePolicy(tt.policy, checks, chainConfig, payload)
+		require.Equal(t, tt.satisfied, err == nil, "rule %s %v", tt.policy.Rule, err)
+	}
+
+	checks = append(checks, &EndorsementCheck{OrgId: "org3", Role: "ADMIN", Valid: true, voter: "org3"})
+	require.Nil(t, cc.evaluatePolicy(policyMajorityAdmin, checks, chainConfig, payload))
+}
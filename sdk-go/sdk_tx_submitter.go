@@ -0,0 +1,555 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultTxSubmitterWindow 每个节点默认的在途交易数
+	DefaultTxSubmitterWindow = 100
+	// DefaultTxSubmitterQueueSize 默认的待发送交易队列长度
+	DefaultTxSubmitterQueueSize = 1024
+	// DefaultTxSubmitterRetryLimit 默认的重试次数
+	DefaultTxSubmitterRetryLimit = 5
+	// DefaultTxSubmitterRetryBackoff 默认的首次重试等待时间，之后每次翻倍
+	DefaultTxSubmitterRetryBackoff = 200 * time.Millisecond
+
+	txSubmitterMaxBackoff = 5 * time.Second
+	// txPoolRejectedCode is the code of node when a tx can not be added to tx pool, the message tells why
+	txPoolRejectedCode = common.TxStatusCode_INTERNAL_ERROR
+	txPoolFullMessage  = "tx pool is full"
+)
+
+// ErrTxSubmitterClosed TxSubmitter已关闭
+var ErrTxSubmitterClosed = errors.New("tx submitter is closed")
+
+// txIdExistMessages the lower case messages of tx pool and ledger when the tx id exists
+var txIdExistMessages = []string{"txid exist", "tx id exist", "tx duplicate"}
+
+// isTxPoolFull whether the tx is rejected because tx pool of node is full
+func isTxPoolFull(resp *common.TxResponse) bool {
+	return resp.Code == txPoolRejectedCode && strings.Contains(resp.Message, txPoolFullMessage)
+}
+
+// isTxIdExist whether the tx is rejected because its tx id is in tx pool or ledger of node
+func isTxIdExist(resp *common.TxResponse) bool {
+	if resp.Code != txPoolRejectedCode {
+		return false
+	}
+	message := strings.ToLower(resp.Message)
+	for _, exist := range txIdExistMessages {
+		if strings.Contains(message, exist) {
+			return true
+		}
+	}
+	return false
+}
+
+// TxSubmitterConfig TxSubmitter配置
+type TxSubmitterConfig struct {
+	// 并行签名的协程数
+	signers int
+	// 每个节点的在途交易数，等待交易结果时，交易上链后才释放
+	window int
+	// 待发送交易队列长度，队列满时Submit阻塞
+	queueSize int
+	// 交易池满、交易ID已存在及节点不可用时的重试次数
+	retryLimit int
+	// 首次重试等待时间，之后每次翻倍
+	retryBackoff time.Duration
+	// 发送交易超时时间，单位：s
+	sendTimeout int64
+	// 等待交易结果超时时间，单位：s
+	resultTimeout int64
+	// 是否等待交易上链结果
+	waitResult bool
+}
+
+// TxSubmitterOption define tx submitter option func
+type TxSubmitterOption func(config *TxSubmitterConfig)
+
+// WithTxSubmitterSigners 设置并行签名的协程数，默认为cpu核数
+func WithTxSubmitterSigners(signers int) TxSubmitterOption {
+	return func(config *TxSubmitterConfig) {
+		config.signers = signers
+	}
+}
+
+// WithTxSubmitterWindow 设置每个节点的在途交易数
+func WithTxSubmitterWindow(window int) TxSubmitterOption {
+	return func(config *TxSubmitterConfig) {
+		config.window = window
+	}
+}
+
+// WithTxSubmitterQueueSize 设置待发送交易队列长度
+func WithTxSubmitterQueueSize(size int) TxSubmitterOption {
+	return func(config *TxSubmitterConfig) {
+		config.queueSize = size
+	}
+}
+
+// WithTxSubmitterRetry 设置重试次数和首次重试等待时间
+func WithTxSubmitterRetry(limit int, backoff time.Duration) TxSubmitterOption {
+	return func(config *TxSubmitterConfig) {
+		config.retryLimit = limit
+		config.retryBackoff = backoff
+	}
+}
+
+// WithTxSubmitterTimeout 设置发送交易和等待交易结果的超时时间，单位：s
+func WithTxSubmitterTimeout(sendTimeout, resultTimeout int64) TxSubmitterOption {
+	return func(config *TxSubmitterConfig) {
+		config.sendTimeout = sendTimeout
+		config.resultTimeout = resultTimeout
+	}
+}
+
+// WithTxSubmitterWaitResult 设置是否等待交易上链结果，默认等待
+func WithTxSubmitterWaitResult(waitResult bool) TxSubmitterOption {
+	return func(config *TxSubmitterConfig) {
+		config.waitResult = waitResult
+	}
+}
+
+// TxFuture 提交交易的结果
+type TxFuture struct {
+	payload   *common.Payload
+	endorsers []*common.EndorsementEntry
+
+	done       chan struct{}
+	resp       *common.TxResponse
+	err        error
+	attempts   int
+	submitTime time.Time
+	finishTime time.Time
+}
+
+// TxId 交易ID，交易ID冲突时会重新生成
+func (f *TxFuture) TxId() string {
+	return f.payload.TxId
+}
+
+// Done 交易完成时关闭
+func (f *TxFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait 等待交易完成，返回的resp与SendTxRequest同步模式的结果相同
+// @return *common.TxResponse
+// @return error
+func (f *TxFuture) Wait() (*common.TxResponse, error) {
+	<-f.done
+	return f.resp, f.err
+}
+
+// Attempts 发送次数
+func (f *TxFuture) Attempts() int {
+	<-f.done
+	return f.attempts
+}
+
+// Latency 从提交到完成的耗时
+func (f *TxFuture) Latency() time.Duration {
+	<-f.done
+	return f.finishTime.Sub(f.submitTime)
+}
+
+// TxSubmitterStats TxSubmitter统计
+type TxSubmitterStats struct {
+	Submitted uint64 `json:"submitted"`
+	Sent      uint64 `json:"sent"`
+	Retried   uint64 `json:"retried"`
+	Succeeded uint64 `json:"succeeded"`
+	Failed    uint64 `json:"failed"`
+	// InFlight 各节点的在途交易数
+	InFlight   map[string]int `json:"in_flight"`
+	AvgLatency time.Duration  `json:"avg_latency"`
+	MaxLatency time.Duration  `json:"max_latency"`
+	// TPS 成功交易数/运行时间
+	TPS float64 `json:"tps"`
+}
+
+// TxSubmitter 异步批量提交交易，并行签名，限制每个节点的在途交易数，
+// 交易池满、交易ID已存在及节点不可用时退避重试，通过一个区块订阅获取所有交易结果
+type TxSubmitter struct {
+	cc     *ChainClient
+	config *TxSubmitterConfig
+	logger utils.Logger
+
+	queue    chan *TxFuture
+	closeMut sync.RWMutex
+	closed   bool
+	stopC    chan struct{}
+	signWg   sync.WaitGroup
+	resultWg sync.WaitGroup
+
+	// dispatcher is cc.txResultDispatcher if enabled, otherwise owned by the submitter
+	dispatcher    *txResultDispatcher
+	ownDispatcher bool
+
+	windowMut sync.Mutex
+	windows   map[string]chan struct{}
+
+	startTime    time.Time
+	submitted    uint64
+	sent         uint64
+	retried      uint64
+	succeeded    uint64
+	failed       uint64
+	latencyMut   sync.Mutex
+	latencySum   time.Duration
+	latencyCount uint64
+	latencyMax   time.Duration
+}
+
+// NewTxSubmitter 创建TxSubmitter
+// @param cc
+// @param opts
+// @return *TxSubmitter
+// @return error
+func NewTxSubmitter(cc *ChainClient, opts ...TxSubmitterOption) (*TxSubmitter, error) {
+	config := &TxSubmitterConfig{
+		signers:      runtime.NumCPU(),
+		window:       DefaultTxSubmitterWindow,
+		queueSize:    DefaultTxSubmitterQueueSize,
+		retryLimit:   DefaultTxSubmitterRetryLimit,
+		retryBackoff: DefaultTxSubmitterRetryBackoff,
+		waitResult:   true,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.signers <= 0 || config.window <= 0 || config.queueSize <= 0 || config.retryLimit < 0 {
+		return nil, errors.New("signers, window and queue size of tx submitter must be positive, " +
+			"retry limit must not be negative")
+	}
+	if config.sendTimeout <= 0 {
+		config.sendTimeout = cc.rpcClientConfig.rpcClientSendTxTimeout
+	}
+	if config.resultTimeout <= 0 {
+		config.resultTimeout = cc.rpcClientConfig.rpcClientGetTxTimeout
+	}
+
+	s := &TxSubmitter{
+		cc:        cc,
+		config:    config,
+		logger:    cc.logger,
+		queue:     make(chan *TxFuture, config.queueSize),
+		stopC:     make(chan struct{}),
+		windows:   make(map[string]chan struct{}),
+		startTime: time.Now(),
+	}
+	if config.waitResult {
+		if cc.txResultDispatcher != nil {
+			s.dispatcher = cc.txResultDispatcher
+		} else {
+			dispatcher, err := newTxResultDispatcher(cc)
+			if err != nil {
+				return nil, fmt.Errorf("create tx result dispatcher failed, %s", err)
+			}
+			s.dispatcher = dispatcher
+			s.ownDispatcher = true
+			go dispatcher.start()
+		}
+	}
+
+	for i := 0; i < config.signers; i++ {
+		s.signWg.Add(1)
+		go s.signLoop()
+	}
+	return s, nil
+}
+
+// Submit 提交交易，队列满时阻塞
+// @param payload 由CreatePayload等方法构造的payload
+// @param endorsers 背书，系统合约交易需要
+// @return *TxFuture
+// @return error 已关闭时返回ErrTxSubmitterClosed
+func (s *TxSubmitter) Submit(payload *common.Payload, endorsers []*common.EndorsementEntry) (*TxFuture, error) {
+	s.closeMut.RLock()
+	defer s.closeMut.RUnlock()
+	if s.closed {
+		return nil, ErrTxSubmitterClosed
+	}
+	future := &TxFuture{
+		payload:    payload,
+		endorsers:  endorsers,
+		done:       make(chan struct{}),
+		submitTime: time.Now(),
+	}
+	atomic.AddUint64(&s.submitted, 1)
+	s.queue <- future
+	return future, nil
+}
+
+// SubmitChan 提交payloads中的所有交易，返回的channel按提交顺序输出TxFuture，payloads关闭后关闭
+// @param payloads
+// @return <-chan *TxFuture
+func (s *TxSubmitter) SubmitChan(payloads <-chan *common.Payload) <-chan *TxFuture {
+	futures := make(chan *TxFuture, s.config.queueSize)
+	go func() {
+		defer close(futures)
+		for payload := range payloads {
+			future, err := s.Submit(payload, nil)
+			if err != nil {
+				future = &TxFuture{payload: payload, done: make(chan struct{}), submitTime: time.Now()}
+				s.finish(future, nil, err)
+			}
+			futures <- future
+		}
+	}()
+	return futures
+}
+
+// Stats 获取统计
+// @return *TxSubmitterStats
+func (s *TxSubmitter) Stats() *TxSubmitterStats {
+	stats := &TxSubmitterStats{
+		Submitted: atomic.LoadUint64(&s.submitted),
+		Sent:      atomic.LoadUint64(&s.sent),
+		Retried:   atomic.LoadUint64(&s.retried),
+		Succeeded: atomic.LoadUint64(&s.succeeded),
+		Failed:    atomic.LoadUint64(&s.failed),
+		InFlight:  make(map[string]int),
+	}
+	s.windowMut.Lock()
+	for addr, window := range s.windows {
+		stats.InFlight[addr] = len(window)
+	}
+	s.windowMut.Unlock()
+
+	s.latencyMut.Lock()
+	if s.latencyCount > 0 {
+		stats.AvgLatency = s.latencySum / time.Duration(s.latencyCount)
+	}
+	stats.MaxLatency = s.latencyMax
+	s.latencyMut.Unlock()
+	if elapsed := time.Since(s.startTime).Seconds(); elapsed > 0 {
+		stats.TPS = float64(stats.Succeeded) / elapsed
+	}
+	return stats
+}
+
+// Close 停止接收交易，等待已提交的交易完成
+// @return error
+func (s *TxSubmitter) Close() error {
+	s.closeMut.Lock()
+	if s.closed {
+		s.closeMut.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.closeMut.Unlock()
+
+	s.signWg.Wait()
+	s.resultWg.Wait()
+	close(s.stopC)
+	if s.ownDispatcher {
+		s.dispatcher.stop()
+	}
+	return nil
+}
+
+func (s *TxSubmitter) signLoop() {
+	defer s.signWg.Done()
+	for future := range s.queue {
+		req, err := s.cc.GenerateTxRequest(future.payload, future.endorsers)
+		if err != nil {
+			s.finish(future, nil, fmt.Errorf("sign tx failed, %s", err))
+			continue
+		}
+		s.send(future, req)
+	}
+}
+
+// send sends the tx and retries with backoff, the window slot is held until the result is known
+func (s *TxSubmitter) send(future *TxFuture, req *common.TxRequest) {
+	// delivered is set when a former attempt may have reached the node though sending failed
+	var delivered bool
+	for {
+		future.attempts++
+		if future.attempts > 1 {
+			atomic.AddUint64(&s.retried, 1)
+			time.Sleep(s.backoff(future.attempts - 1))
+		}
+		canRetry := future.attempts <= s.config.retryLimit
+
+		client, err := s.cc.pool.getClient()
+		if err != nil {
+			if canRetry {
+				continue
+			}
+			s.finish(future, nil, err)
+			return
+		}
+		window := s.window(client.nodeAddr)
+		window <- struct{}{}
+		var resultC chan *txResult
+		if s.dispatcher != nil {
+			resultC = s.dispatcher.register(req.Payload.TxId)
+		}
+
+		atomic.AddUint64(&s.sent, 1)
+		resp, err := client.sendRequest(req, s.config.sendTimeout)
+		if err == nil && resp.Code == common.TxStatusCode_SUCCESS {
+			if s.dispatcher == nil {
+				<-window
+				s.finish(future, resp, nil)
+				return
+			}
+			s.resultWg.Add(1)
+			go s.awaitResult(future, resp, resultC, window)
+			return
+		}
+
+		if s.dispatcher != nil {
+			s.dispatcher.unregister(req.Payload.TxId)
+		}
+		<-window
+		switch {
+		case err != nil:
+			statusErr, ok := status.FromError(err)
+			if !ok || !canRetry ||
+				(statusErr.Code() != codes.Unavailable && statusErr.Code() != codes.DeadlineExceeded) {
+				s.finish(future, nil, fmt.Errorf("send tx failed, %s", err))
+				return
+			}
+			s.logger.Debugf("[SDK] tx submitter send tx [%s] to [%s] failed, %s, retry", req.Payload.TxId,
+				client.ID, err)
+			// the node may have received the tx before the deadline
+			delivered = delivered || statusErr.Code() == codes.DeadlineExceeded
+		case isTxPoolFull(resp) && canRetry:
+			s.logger.Debugf("[SDK] tx pool of [%s] is full, retry tx [%s]", client.ID, req.Payload.TxId)
+		case isTxIdExist(resp) && canRetry:
+			if delivered {
+				// a former attempt reached the node, wait for its result
+				if s.dispatcher == nil {
+					s.finish(future, &common.TxResponse{Code: common.TxStatusCode_SUCCESS, TxId: req.Payload.TxId}, nil)
+					return
+				}
+				window <- struct{}{}
+				s.resultWg.Add(1)
+				go s.awaitResult(future, resp, s.dispatcher.register(req.Payload.TxId), window)
+				return
+			}
+			// the tx id conflicts with another tx, sign again with new tx id
+			future.payload.TxId = s.newTxId()
+			if req, err = s.cc.GenerateTxRequest(future.payload, future.endorsers); err != nil {
+				s.finish(future, nil, fmt.Errorf("sign tx failed, %s", err))
+				return
+			}
+		default:
+			s.finish(future, resp, fmt.Errorf("send tx failed, code: %s, message: %s", resp.Code, resp.Message))
+			return
+		}
+	}
+}
+
+func (s *TxSubmitter) awaitResult(future *TxFuture, resp *common.TxResponse, resultC chan *txResult,
+	window chan struct{}) {
+	defer s.resultWg.Done()
+	defer func() { <-window }()
+	txId := future.payload.TxId
+	defer s.dispatcher.unregister(txId)
+
+	timer := time.NewTimer(time.Duration(s.config.resultTimeout) * time.Second)
+	defer timer.Stop()
+	var r *txResult
+	select {
+	case r = <-resultC:
+	case <-timer.C:
+		// the block may be missed while resubscribing, query it once
+		txInfo, err := s.cc.GetTxByTxId(txId)
+		if err != nil || txInfo.Transaction == nil || txInfo.Transaction.Result == nil {
+			s.finish(future, resp, fmt.Errorf("get tx [%s] result timed out", txId))
+			return
+		}
+		r = &txResult{
+			Result:        txInfo.Transaction.Result,
+			TxTimestamp:   txInfo.Transaction.Payload.Timestamp,
+			TxBlockHeight: txInfo.BlockHeight,
+		}
+	}
+	final := &common.TxResponse{
+		Code:           r.Result.Code,
+		Message:        r.Result.Message,
+		ContractResult: r.Result.ContractResult,
+		TxId:           txId,
+		TxTimestamp:    r.TxTimestamp,
+		TxBlockHeight:  r.TxBlockHeight,
+	}
+	if final.Code != common.TxStatusCode_SUCCESS {
+		s.finish(future, final, fmt.Errorf("tx [%s] failed, code: %s, message: %s", txId, final.Code,
+			final.Message))
+		return
+	}
+	s.finish(future, final, nil)
+}
+
+func (s *TxSubmitter) finish(future *TxFuture, resp *common.TxResponse, err error) {
+	future.resp, future.err = resp, err
+	future.finishTime = time.Now()
+	if err != nil {
+		atomic.AddUint64(&s.failed, 1)
+	} else {
+		atomic.AddUint64(&s.succeeded, 1)
+		latency := future.finishTime.Sub(future.submitTime)
+		s.latencyMut.Lock()
+		s.latencySum += latency
+		s.latencyCount++
+		if latency > s.latencyMax {
+			s.latencyMax = latency
+		}
+		s.latencyMut.Unlock()
+	}
+	close(future.done)
+}
+
+func (s *TxSubmitter) window(nodeAddr string) chan struct{} {
+	s.windowMut.Lock()
+	defer s.windowMut.Unlock()
+	window, ok := s.windows[nodeAddr]
+	if !ok {
+		window = make(chan struct{}, s.config.window)
+		s.windows[nodeAddr] = window
+	}
+	return window
+}
+
+func (s *TxSubmitter) backoff(retry int) time.Duration {
+	backoff := s.config.retryBackoff
+	for i := 1; i < retry && backoff < txSubmitterMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > txSubmitterMaxBackoff {
+		backoff = txSubmitterMaxBackoff
+	}
+	return backoff
+}
+
+func (s *TxSubmitter) newTxId() string {
+	if s.cc.enableNormalKey {
+		return utils.GetRandTxId()
+	}
+	return utils.GetTimestampTxId()
+}
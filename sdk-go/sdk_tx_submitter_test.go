@@ -0,0 +1,189 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chainmaker_sdk_go
+
+import (
+	"testing"
+	"time"
+
+	"chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/sdk-go/v2/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxSubmitter(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverTxResp  *common.TxResponse
+		wantErr       bool
+		wantAttempts  int
+		wantSucceeded uint64
+	}{
+		{
+			"good",
+			&common.TxResponse{Code: common.TxStatusCode_SUCCESS},
+			false,
+			1,
+			10,
+		},
+		{
+			"tx pool is full",
+			&common.TxResponse{Code: common.TxStatusCode_INTERNAL_ERROR, Message: "tx pool is full"},
+			true,
+			3,
+			0,
+		},
+		{
+			"tx id exist",
+			&common.TxResponse{Code: common.TxStatusCode_INTERNAL_ERROR, Message: "txId exist in ledger"},
+			true,
+			3,
+			0,
+		},
+		{
+			"contract fail",
+			&common.TxResponse{Code: common.TxStatusCode_CONTRACT_FAIL, Message: "bad"},
+			true,
+			1,
+			0,
+		},
+		{
+			"tx id exist in contract message",
+			&common.TxResponse{Code: common.TxStatusCode_CONTRACT_FAIL, Message: "txId exist in contract"},
+			true,
+			1,
+			0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc, err := newMockChainClient(tt.serverTxResp, nil, WithConfPath(sdkConfigPathForUT))
+			require.Nil(t, err)
+			defer cc.Stop()
+
+			submitter, err := NewTxSubmitter(cc, WithTxSubmitterWaitResult(false), WithTxSubmitterWindow(2),
+				WithTxSubmitterRetry(2, time.Millisecond))
+			require.Nil(t, err)
+
+			payloads := make(chan *common.Payload)
+			futures := submitter.SubmitChan(payloads)
+			go func() {
+				for i := 0; i < 10; i++ {
+					payloads <- cc.CreatePayload(utils.GetTimestampTxId(), common.TxType_INVOKE_CONTRACT,
+						"claim", "save", nil, defaultSeq, nil)
+				}
+				close(payloads)
+			}()
+
+			var count int
+			for future := range futures {
+				_, err = future.Wait()
+				require.Equal(t, tt.wantErr, err != nil)
+				require.Equal(t, tt.wantAttempts, future.Attempts())
+				count++
+			}
+			require.Equal(t, 10, count)
+			require.Nil(t, submitter.Close())
+
+			stats := submitter.Stats()
+			require.Equal(t, uint64(10), stats.Submitted)
+			require.Equal(t, uint64(10*tt.wantAttempts), stats.Sent)
+			require.Equal(t, tt.wantSucceeded, stats.Succeeded)
+			for _, inFlight := range stats.InFlight {
+				require.Zero(t, inFlight)
+			}
+
+			_, err = submitter.Submit(&common.Payload{}, nil)
+			require.Equal(t, ErrTxSubmitterClosed, err)
+		})
+	}
+}
+
+func TestTxSubmitterWaitResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *common.Result
+		wantErr  bool
+		wantCode common.TxStatusCode
+	}{
+		{
+			"success",
+			&common.Result{Code: common.TxStatusCode_SUCCESS,
+				ContractResult: &common.ContractResult{Result: []byte("ok")}},
+			false,
+			common.TxStatusCode_SUCCESS,
+		},
+		{
+			"contract fail",
+			&common.Result{Code: common.TxStatusCode_CONTRACT_FAIL, Message: "bad"},
+			true,
+			common.TxStatusCode_CONTRACT_FAIL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc, err := newMockChainClient(&common.TxResponse{Code: common.TxStatusCode_SUCCESS}, nil,
+				WithConfPath(sdkConfigPathForUT))
+			require.Nil(t, err)
+			defer cc.Stop()
+			// the results are dispatched by the test instead of a block subscription
+			dispatcher := &txResultDispatcher{
+				cc:              cc,
+				stopC:           make(chan struct{}),
+				txRegistrations: make(map[string]chan *txResult),
+			}
+			cc.txResultDispatcher = dispatcher
+
+			submitter, err := NewTxSubmitter(cc, WithTxSubmitterWaitResult(true), WithTxSubmitterWindow(2),
+				WithTxSubmitterTimeout(0, 10))
+			require.Nil(t, err)
+
+			var futures []*TxFuture
+			for i := 0; i < 5; i++ {
+				future, err := submitter.Submit(cc.CreatePayload(utils.GetTimestampTxId(),
+					common.TxType_INVOKE_CONTRACT, "claim", "save", nil, defaultSeq, nil), nil)
+				require.Nil(t, err)
+				futures = append(futures, future)
+			}
+			for i, future := range futures {
+				tx := &common.Transaction{Payload: future.payload, Result: tt.result}
+				require.Eventually(t, func() bool {
+					dispatcher.mux.Lock()
+					defer dispatcher.mux.Unlock()
+					_, ok := dispatcher.txRegistrations[tx.Payload.TxId]
+					return ok
+				}, 5*time.Second, time.Millisecond)
+				dispatcher.trySendTxResult(&transaction{Transaction: tx, BlockHeight: uint64(i + 1)})
+			}
+
+			for i, future := range futures {
+				resp, err := future.Wait()
+				require.Equal(t, tt.wantErr, err != nil)
+				require.Equal(t, tt.wantCode, resp.Code)
+				require.Equal(t, future.payload.TxId, resp.TxId)
+				require.Equal(t, uint64(i+1), resp.TxBlockHeight)
+				require.Equal(t, tt.result.ContractResult, resp.ContractResult)
+				require.Equal(t, 1, future.Attempts())
+			}
+			require.Nil(t, submitter.Close())
+
+			stats := submitter.Stats()
+			require.Equal(t, uint64(5), stats.Sent)
+			if tt.wantErr {
+				require.Equal(t, uint64(5), stats.Failed)
+			} else {
+				require.Equal(t, uint64(5), stats.Succeeded)
+			}
+			for _, inFlight := range stats.InFlight {
+				require.Zero(t, inFlight)
+			}
+		})
+	}
+}
@@ -279,6 +279,15 @@ func (pool *mockConnectionPool) getLogger() utils.Logger {
 	return pool.logger
 }
 
+// Stats 获取各节点的健康状态
+func (pool *mockConnectionPool) Stats() []*NodeStats {
+	var stats []*NodeStats
+	for _, cli := range pool.connections {
+		stats = append(stats, &NodeStats{NodeAddr: cli.nodeAddr, State: CircuitClosed, Connections: 1})
+	}
+	return stats
+}
+
 // Close 关闭连接池
 func (pool *mockConnectionPool) Close() error {
 	for _, c := range pool.connections {
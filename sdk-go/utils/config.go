@@ -47,6 +47,17 @@ type rpcClientConfigModel struct {
 	GetTxTimeout   int64 `mapstructure:"get_tx_timeout"`
 }
 
+type connPoolConfigModel struct {
+	// 负载均衡策略 first_available, least_latency, highest_height, sticky
+	BalancePolicy string `mapstructure:"balance_policy"`
+	// 连续失败多少次后熔断节点
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// 熔断后多久进入半开状态 单位：s
+	OpenTimeout int `mapstructure:"open_timeout"`
+	// 探测节点高度的间隔 单位：s，为负数时不探测
+	HealthCheckInterval int `mapstructure:"health_check_interval"`
+}
+
 type pkcs11ConfigModel struct {
 	// 是否开启pkcs11
 	Enabled bool `mapstructure:"enabled"`
@@ -137,6 +148,8 @@ type chainClientConfigModel struct {
 	ArchiveConfig *archiveConfigModel `mapstructure:"archive,omitempty"`
 	// 设置grpc客户端配置
 	RPCClientConfig *rpcClientConfigModel `mapstructure:"rpc_client"`
+	// 连接池负载均衡和熔断配置
+	ConnPoolConfig *connPoolConfigModel `mapstructure:"conn_pool"`
 	// pkcs11配置(若未设置，则不使用pkcs11)
 	Pkcs11Config *pkcs11ConfigModel `mapstructure:"pkcs11"`
 	// kms配置(若未设置，则不使用kms）
@@ -0,0 +1,75 @@
+/*
+Copyright (C) BABEC. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package docker_go
+
+import (
+	"encoding/json"
+	"strconv"
+
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	"chainmaker.org/chainmaker/vm-engine/v2/config"
+)
+
+// callContext is the call stack passed to go contracts, the same as sdk.CallContext
+type callContext struct {
+	// Callers the names of the contracts calling the contract, the tx entry contract first
+	Callers []string `json:"callers"`
+	// Locks the reentrancy locks held by the callers, as contractName/lockName
+	Locks []string `json:"locks"`
+}
+
+func callContextKey(txId string, depth uint32) string {
+	return txId + "#" + strconv.FormatUint(uint64(depth), 10)
+}
+
+// storeCallContext records the call stack of the go contract about to be called at depth by caller,
+// the locks declared by caller are added to the locks it inherits, the returned func drops the record
+func (m *InstancesManager) storeCallContext(txId string, depth uint32, current *callContext,
+	caller *commonPb.Contract, declared []byte) func() {
+
+	next := &callContext{}
+	if current != nil {
+		next.Callers = append(next.Callers, current.Callers...)
+		next.Locks = append(next.Locks, current.Locks...)
+	}
+	next.Callers = append(next.Callers, caller.Name)
+
+	var names []string
+	if err := json.Unmarshal(declared, &names); err == nil {
+		for _, name := range names {
+			next.Locks = append(next.Locks, caller.Name+"/"+name)
+		}
+	}
+
+	key := callContextKey(txId, depth)
+	m.callContexts.Store(key, next)
+	return func() {
+		m.callContexts.Delete(key)
+	}
+}
+
+// loadCallContext returns the call stack recorded for the contract at depth of tx, nil for contracts called
+// by the tx or by contracts which are not go contracts
+func (m *InstancesManager) loadCallContext(txId string, depth uint32) *callContext {
+	value, ok := m.callContexts.Load(callContextKey(txId, depth))
+	if !ok {
+		return nil
+	}
+	return value.(*callContext)
+}
+
+// setCallContextParam replaces the call stack in parameters by the one recorded by the engine,
+// the one given by the tx or by a contract is dropped
+func setCallContextParam(parameters map[string][]byte, callCtx *callContext) {
+	delete(parameters, config.CallContextParam)
+	if callCtx == nil {
+		return
+	}
+	if data, err := json.Marshal(callCtx); err == nil {
+		parameters[config.CallContextParam] = data
+	}
+}
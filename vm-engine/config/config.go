@@ -154,6 +154,8 @@ const (
 	KeyCallContractResp = "KEY_CALL_CONTRACT_RESPONSE"
 	// KeyCallContractReq is the key call contract req
 	KeyCallContractReq = "KEY_CALL_CONTRACT_REQUEST"
+	// CallContextParam is the reserved arg key the call stack of a cross contract call is passed with
+	CallContextParam = "__call_context__"
 
 	// KeyStateKey is the key state key
 	KeyStateKey = "KEY_STATE_KEY"
@@ -11,6 +11,8 @@ import (
 
 const (
 	blockVersion2312 = uint32(2030102)
+	// blockVersion2360 metered compute of go contracts is charged since
+	blockVersion2360 = uint32(2030600)
 )
 
 // GetSenderAddressGasUsed returns get sender address gas used
@@ -115,3 +117,23 @@ func CallContractGasUsed(blockVersion uint32, gasConfig *gasutils.GasConfig, gas
 
 	return CallContractGasUsed2312(gasConfig, gasUsed, contractName, contractMethod, parameters, txId, log)
 }
+
+// ComputeGasUsed returns gas used by the compute units a metered contract reported
+func ComputeGasUsed(blockVersion uint32, gasConfig *gasutils.GasConfig, gasUsed uint64,
+	computeUnits uint64, txId string, log protocol.Logger) (uint64, error) {
+	if blockVersion < blockVersion2360 || computeUnits == 0 {
+		return gasUsed, nil
+	}
+
+	return ComputeGasUsed2312(gasConfig, gasUsed, computeUnits, txId, log)
+}
+
+// ComputeLimit returns the compute units a metered contract may consume before running out of gas,
+// 0 means the contract is not limited
+func ComputeLimit(blockVersion uint32, gasConfig *gasutils.GasConfig, gasUsed, gasLimit uint64) uint64 {
+	if blockVersion < blockVersion2360 {
+		return 0
+	}
+
+	return ComputeLimit2312(gasConfig, gasUsed, gasLimit)
+}
@@ -2,6 +2,7 @@ package gas
 
 import (
 	"errors"
+	"math"
 	"strings"
 
 	"chainmaker.org/chainmaker/vm-engine/v2/config"
@@ -148,3 +149,49 @@ func CallContractGasUsed2312(gasConfig *gasutils.GasConfig, gasUsed uint64,
 	}
 	return gasUsed, nil
 }
+
+// ComputeGasUsed2312 calculate gas for compute units consumed inside a metered contract
+func ComputeGasUsed2312(gasConfig *gasutils.GasConfig, gasUsed uint64,
+	computeUnits uint64, txId string, log protocol.Logger) (uint64, error) {
+	gasPrice := float32(0)
+	if gasConfig != nil {
+		gasPrice = gasConfig.GetGasPriceForInvoke()
+	}
+	if computeUnits > math.MaxInt32 {
+		return 0, errors.New("over gas limited")
+	}
+
+	log.Debugf("【gas calc】%v, ComputeGasUsed2312, computeUnits = %v", txId, computeUnits)
+
+	gas, err := gasutils.MultiplyGasPrice(int(computeUnits), gasPrice)
+	if err != nil {
+		return 0, err
+	}
+
+	gasUsed += gas
+	if CheckGasLimit(gasUsed) {
+		return 0, errors.New("over gas limited")
+	}
+	return gasUsed, nil
+}
+
+// ComputeLimit2312 calculate how many compute units fit in the gas left of the tx gas limit
+func ComputeLimit2312(gasConfig *gasutils.GasConfig, gasUsed, gasLimit uint64) uint64 {
+	if gasConfig == nil || gasLimit == 0 {
+		return 0
+	}
+	gasPrice := gasConfig.GetGasPriceForInvoke()
+	if gasPrice <= 0 {
+		return 0
+	}
+	// gas is already exhausted, any metered block runs out of gas
+	if gasUsed >= gasLimit {
+		return 1
+	}
+
+	limit := uint64(float64(gasLimit-gasUsed) / float64(gasPrice))
+	if limit == 0 {
+		return 1
+	}
+	return limit
+}
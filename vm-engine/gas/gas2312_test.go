@@ -0,0 +1,141 @@
+/*
+Copyright (C) THL A29 Limited, a Tencent company. All rights reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gas
+
+import (
+	"math"
+	"testing"
+
+	"chainmaker.org/chainmaker/pb-go/v2/config"
+	"chainmaker.org/chainmaker/protocol/v2/test"
+	gasutils "chainmaker.org/chainmaker/utils/v2/gas"
+)
+
+func TestComputeLimit2312(t *testing.T) {
+	gasConfig := gasutils.NewGasConfig(&config.GasAccountConfig{
+		EnableGas:       true,
+		DefaultGas:      uint64(1000),
+		DefaultGasPrice: float32(2),
+	})
+
+	type args struct {
+		gasConfig *gasutils.GasConfig
+		gasUsed   uint64
+		gasLimit  uint64
+	}
+	tests := []struct {
+		name string
+		args args
+		want uint64
+	}{
+		{
+			name: "gasDisabled",
+			args: args{gasConfig: nil, gasUsed: 100, gasLimit: 1000},
+			want: 0,
+		},
+		{
+			name: "noGasLimit",
+			args: args{gasConfig: gasConfig, gasUsed: 100, gasLimit: 0},
+			want: 0,
+		},
+		{
+			name: "gasLeft",
+			args: args{gasConfig: gasConfig, gasUsed: 200, gasLimit: 1000},
+			want: 400,
+		},
+		{
+			name: "gasExhausted",
+			args: args{gasConfig: gasConfig, gasUsed: 1000, gasLimit: 1000},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeLimit2312(tt.args.gasConfig, tt.args.gasUsed, tt.args.gasLimit); got != tt.want {
+				t.Errorf("ComputeLimit2312() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeGasUsed2312(t *testing.T) {
+	gasConfig := gasutils.NewGasConfig(&config.GasAccountConfig{
+		EnableGas:       true,
+		DefaultGas:      uint64(1000),
+		DefaultGasPrice: float32(2),
+	})
+
+	type args struct {
+		gasConfig    *gasutils.GasConfig
+		gasUsed      uint64
+		computeUnits uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name: "gasDisabled",
+			args: args{gasConfig: nil, gasUsed: 100, computeUnits: 50},
+			want: 100,
+		},
+		{
+			name: "computeUnits",
+			args: args{gasConfig: gasConfig, gasUsed: 100, computeUnits: 50},
+			want: 200,
+		},
+		{
+			name:    "tooManyComputeUnits",
+			args:    args{gasConfig: gasConfig, gasUsed: 100, computeUnits: math.MaxInt32 + 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeGasUsed2312(tt.args.gasConfig, tt.args.gasUsed, tt.args.computeUnits, "tx1",
+				&test.GoLogger{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ComputeGasUsed2312() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ComputeGasUsed2312() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeGasBlockVersion(t *testing.T) {
+	gasConfig := gasutils.NewGasConfig(&config.GasAccountConfig{
+		EnableGas:       true,
+		DefaultGas:      uint64(1000),
+		DefaultGasPrice: float32(2),
+	})
+
+	tests := []struct {
+		name         string
+		blockVersion uint32
+		wantGasUsed  uint64
+		wantLimit    uint64
+	}{
+		{name: "v2350", blockVersion: 2030500, wantGasUsed: 100, wantLimit: 0},
+		{name: "v2360", blockVersion: 2030600, wantGasUsed: 200, wantLimit: 450},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeGasUsed(tt.blockVersion, gasConfig, 100, 50, "tx1", &test.GoLogger{})
+			if err != nil || got != tt.wantGasUsed {
+				t.Errorf("ComputeGasUsed() got = %v, %v, want %v", got, err, tt.wantGasUsed)
+			}
+			if limit := ComputeLimit(tt.blockVersion, gasConfig, 100, 1000); limit != tt.wantLimit {
+				t.Errorf("ComputeLimit() got = %v, want %v", limit, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
 	"chainmaker.org/chainmaker/protocol/v2"
@@ -33,6 +34,7 @@ type InstancesManager struct {
 	dockerVMConfig        *config.DockerVMConfig             // original config from local config
 	dockerContainerConfig *config.DockerContainerConfig      // container setting
 	BlockDurationMgr      *utils.BlockTxsDurationMgr
+	callContexts          sync.Map // call stacks of pending cross contract calls, tx id#depth -> *callContext
 }
 
 // NewInstancesManager return docker vm instance manager
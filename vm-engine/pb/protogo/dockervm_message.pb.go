@@ -561,6 +561,10 @@ type TxRequest struct {
 	ChainId       string     `protobuf:"bytes,6,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 	ContractAddr  string     `protobuf:"bytes,7,opt,name=contract_addr,json=contractAddr,proto3" json:"contract_addr,omitempty"`
 	ContractIndex uint32     `protobuf:"varint,8,opt,name=contract_index,json=contractIndex,proto3" json:"contract_index,omitempty"`
+	// compute units the metered contract may consume, 0 means unlimited
+	ComputeLimit uint64 `protobuf:"varint,9,opt,name=compute_limit,json=computeLimit,proto3" json:"compute_limit,omitempty"`
+	// the engine put the call stack under __call_context__ in parameters
+	HasCallContext bool `protobuf:"varint,10,opt,name=has_call_context,json=hasCallContext,proto3" json:"has_call_context,omitempty"`
 }
 
 func (m *TxRequest) Reset()         { *m = TxRequest{} }
@@ -652,6 +656,20 @@ func (m *TxRequest) GetContractIndex() uint32 {
 	return 0
 }
 
+func (m *TxRequest) GetComputeLimit() uint64 {
+	if m != nil {
+		return m.ComputeLimit
+	}
+	return 0
+}
+
+func (m *TxRequest) GetHasCallContext() bool {
+	if m != nil {
+		return m.HasCallContext
+	}
+	return false
+}
+
 type TxContext struct {
 	WriteMap map[string][]byte `protobuf:"bytes,1,rep,name=write_map,json=writeMap,proto3" json:"write_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	ReadMap  map[string][]byte `protobuf:"bytes,2,rep,name=read_map,json=readMap,proto3" json:"read_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -717,6 +735,8 @@ type TxResponse struct {
 	ContractVersion string                 `protobuf:"bytes,9,opt,name=contract_version,json=contractVersion,proto3" json:"contract_version,omitempty"`
 	ChainId         string                 `protobuf:"bytes,10,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 	ContractIndex   uint32                 `protobuf:"varint,11,opt,name=contract_index,json=contractIndex,proto3" json:"contract_index,omitempty"`
+	// compute units consumed by a metered contract
+	ComputeUnits uint64 `protobuf:"varint,12,opt,name=compute_units,json=computeUnits,proto3" json:"compute_units,omitempty"`
 }
 
 func (m *TxResponse) Reset()         { *m = TxResponse{} }
@@ -829,6 +849,13 @@ func (m *TxResponse) GetContractIndex() uint32 {
 	return 0
 }
 
+func (m *TxResponse) GetComputeUnits() uint64 {
+	if m != nil {
+		return m.ComputeUnits
+	}
+	return 0
+}
+
 type DockerContractEvent struct {
 	// Event topic
 	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
@@ -1185,113 +1212,115 @@ func init() {
 func init() { proto.RegisterFile("dockervm_message.proto", fileDescriptor_23619f598d968e93) }
 
 var fileDescriptor_23619f598d968e93 = []byte{
-	// 1683 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcd, 0x72, 0xdb, 0xc8,
-	0x11, 0x16, 0xf8, 0xcf, 0x16, 0x29, 0xc1, 0x23, 0x4b, 0x0b, 0xd3, 0x36, 0xcd, 0xa5, 0xed, 0xd8,
-	0x71, 0x6a, 0xa9, 0x94, 0x73, 0x88, 0xe3, 0x4d, 0x6a, 0x43, 0x83, 0x63, 0x0b, 0x65, 0x09, 0x64,
-	0x86, 0x90, 0x62, 0xed, 0x05, 0x85, 0x25, 0x66, 0x65, 0x96, 0x49, 0x80, 0x01, 0x40, 0xad, 0xf4,
-	0x0e, 0x39, 0xe4, 0x96, 0xd7, 0xc8, 0x21, 0x0f, 0x91, 0x4b, 0xaa, 0xf6, 0xb4, 0x95, 0x63, 0xca,
-	0x7a, 0x8c, 0xe4, 0x90, 0x9a, 0xc1, 0x00, 0x02, 0x40, 0xd0, 0x89, 0x0e, 0xa9, 0x3d, 0x11, 0xdd,
-	0xfd, 0x75, 0x4f, 0xcf, 0x37, 0xdd, 0x3d, 0x43, 0xd8, 0xb3, 0xdd, 0xc9, 0x07, 0xea, 0x9d, 0xcf,
-	0xcd, 0x39, 0xf5, 0x7d, 0xeb, 0x8c, 0xf6, 0x16, 0x9e, 0x1b, 0xb8, 0xa8, 0xcc, 0x7f, 0xba, 0xff,
-	0x2e, 0xc0, 0xf6, 0x80, 0x23, 0x4e, 0x8e, 0x8e, 0x42, 0x00, 0xda, 0x81, 0x72, 0x70, 0x61, 0x4e,
-	0x6d, 0x45, 0xea, 0x48, 0x4f, 0xeb, 0xa4, 0x14, 0x5c, 0x68, 0x36, 0x7a, 0x02, 0xa5, 0xe0, 0x72,
-	0x41, 0x95, 0x42, 0x47, 0x7a, 0xba, 0xf5, 0x7c, 0x27, 0x8c, 0xd2, 0x8b, 0x5c, 0x8d, 0xcb, 0x05,
-	0x25, 0x1c, 0x80, 0x5e, 0x40, 0x73, 0xe2, 0xb9, 0xbe, 0x6f, 0x4e, 0x5c, 0x27, 0xa0, 0x17, 0x81,
-	0x52, 0xec, 0x48, 0x4f, 0x37, 0x63, 0x0f, 0x95, 0xd9, 0xd4, 0xd0, 0x44, 0x1a, 0x93, 0x84, 0x84,
-	0xbe, 0x02, 0xd9, 0xbf, 0xf4, 0xcd, 0x89, 0x35, 0x9b, 0x45, 0xc9, 0x2a, 0x25, 0xee, 0xbc, 0x2b,
-	0x9c, 0xc7, 0x97, 0xbe, 0x6a, 0xcd, 0x66, 0x22, 0x51, 0xb2, 0xe5, 0xa7, 0x64, 0xf4, 0x0c, 0xaa,
-	0x1e, 0xfd, 0xc3, 0x92, 0xfa, 0x81, 0x52, 0xe6, 0x7e, 0xb2, 0xf0, 0x33, 0x2e, 0x48, 0xa8, 0x27,
-	0x11, 0x00, 0x7d, 0x01, 0x35, 0x8f, 0xfa, 0x0b, 0xd7, 0xf1, 0xa9, 0x52, 0xe1, 0xe0, 0x5b, 0x09,
-	0x70, 0x68, 0x20, 0x31, 0x04, 0xdd, 0x81, 0xda, 0xe4, 0xbd, 0x35, 0x75, 0x18, 0x2d, 0x55, 0x4e,
-	0x4b, 0x95, 0xcb, 0x9a, 0x8d, 0x5e, 0xc2, 0x96, 0x1f, 0xd0, 0x85, 0x69, 0x2f, 0x3d, 0x2b, 0x98,
-	0xba, 0x8e, 0xaf, 0xd4, 0x3a, 0xc5, 0xc4, 0x8e, 0xc7, 0x01, 0x5d, 0x0c, 0x84, 0x8d, 0x34, 0xfd,
-	0x84, 0xe4, 0x77, 0xff, 0x2e, 0xc1, 0x56, 0x7a, 0x53, 0x8c, 0xe8, 0x89, 0x6b, 0x53, 0x4e, 0xfe,
-	0x2a, 0xd1, 0xaa, 0x6b, 0x53, 0xc2, 0x01, 0x48, 0x81, 0x6a, 0xc4, 0x52, 0x21, 0xcc, 0x48, 0x88,
-	0xe8, 0xd7, 0x50, 0x5d, 0x58, 0x97, 0x33, 0xd7, 0xb2, 0x95, 0x22, 0x4f, 0xa5, 0x9b, 0xcb, 0x5f,
-	0x6f, 0x14, 0x82, 0xb0, 0x13, 0x78, 0x97, 0x24, 0x72, 0x69, 0xbd, 0x84, 0x46, 0xd2, 0x80, 0x64,
-	0x28, 0x7e, 0xa0, 0x97, 0xa2, 0x18, 0xd8, 0x27, 0xba, 0x0d, 0xe5, 0x73, 0x6b, 0xb6, 0x0c, 0xd7,
-	0x6d, 0x90, 0x50, 0x78, 0x59, 0x78, 0x21, 0x75, 0x97, 0xd0, 0x48, 0x1e, 0x30, 0x7a, 0x08, 0xcd,
-	0xc9, 0xd2, 0xf3, 0xa8, 0x13, 0x98, 0x36, 0x5d, 0x04, 0xef, 0x79, 0x94, 0x26, 0x69, 0x08, 0xe5,
-	0x80, 0xe9, 0xd0, 0xe7, 0xd0, 0x58, 0x78, 0xee, 0x84, 0xfa, 0xbe, 0xe9, 0x58, 0xf3, 0x68, 0x37,
-	0x9b, 0x42, 0xa7, 0x5b, 0x73, 0x8a, 0xee, 0x03, 0x84, 0x45, 0x35, 0x75, 0xbe, 0x75, 0x79, 0x45,
-	0x95, 0x48, 0x9d, 0x6b, 0x34, 0xe7, 0x5b, 0xb7, 0xfb, 0x17, 0x09, 0x1a, 0x49, 0x9a, 0xd1, 0x43,
-	0x51, 0xad, 0x21, 0x89, 0xdb, 0x89, 0x93, 0x48, 0x54, 0xea, 0x7d, 0x00, 0x3f, 0xb0, 0xbc, 0xc0,
-	0x0c, 0xa6, 0x62, 0xd5, 0x22, 0xa9, 0x73, 0x8d, 0x31, 0x9d, 0x53, 0x96, 0x7b, 0xea, 0x5c, 0xf9,
-	0xb2, 0x45, 0xd2, 0x48, 0x9e, 0x20, 0x7a, 0x0c, 0x5b, 0x4b, 0x27, 0x98, 0xce, 0xae, 0x51, 0x25,
-	0x8e, 0x6a, 0x72, 0x6d, 0x0c, 0x93, 0xa1, 0x38, 0xf7, 0xcf, 0x78, 0x55, 0xd6, 0x09, 0xfb, 0xec,
-	0xfe, 0xb1, 0x08, 0xf5, 0xb8, 0x2c, 0x39, 0x4f, 0xae, 0x13, 0x78, 0xd6, 0x24, 0x08, 0x39, 0x08,
-	0xd9, 0x6e, 0x44, 0x4a, 0x4e, 0xc2, 0x4f, 0x41, 0x8e, 0x41, 0xe7, 0xd4, 0xf3, 0xd9, 0x6a, 0x21,
-	0x57, 0xdb, 0x91, 0xfe, 0x24, 0x54, 0xa3, 0x3d, 0xa8, 0xcc, 0x69, 0xf0, 0xde, 0xb5, 0x79, 0xd2,
-	0x75, 0x22, 0x24, 0xf4, 0x5b, 0x80, 0x85, 0xe5, 0x59, 0x73, 0x1a, 0x50, 0xcf, 0x57, 0x4a, 0xbc,
-	0x38, 0x3a, 0xd9, 0x26, 0xe9, 0x8d, 0x62, 0x48, 0x58, 0x1a, 0x09, 0x1f, 0xb4, 0x0f, 0x10, 0x5c,
-	0xc4, 0xbd, 0x9d, 0x6d, 0xb3, 0xa8, 0xb1, 0xeb, 0x41, 0xf4, 0x99, 0xea, 0x9c, 0x4a, 0xba, 0x73,
-	0x92, 0xbb, 0xb6, 0x6c, 0xdb, 0x13, 0x9d, 0x15, 0xef, 0xba, 0x6f, 0xdb, 0x1e, 0x63, 0x38, 0x06,
-	0x4d, 0x1d, 0x9b, 0x5e, 0x28, 0x35, 0x5e, 0x43, 0xb1, 0xab, 0xc6, 0x94, 0xad, 0xdf, 0xc0, 0x76,
-	0x26, 0xed, 0x1b, 0x15, 0xee, 0xbf, 0x24, 0x76, 0x1c, 0x51, 0xce, 0x5f, 0x42, 0xfd, 0x3b, 0x6f,
-	0x1a, 0x50, 0x73, 0x6e, 0x2d, 0x14, 0x89, 0xb3, 0xd4, 0xce, 0xee, 0xb1, 0xf7, 0x7b, 0x86, 0x38,
-	0xb2, 0x16, 0x21, 0x47, 0xb5, 0xef, 0x84, 0x88, 0x5e, 0xb0, 0xc9, 0x62, 0xd9, 0xdc, 0xb7, 0xc0,
-	0x7d, 0xef, 0xaf, 0xf8, 0x12, 0x6a, 0xd9, 0xb1, 0x6b, 0xd5, 0x0b, 0xa5, 0xd6, 0x97, 0xd0, 0x4c,
-	0x05, 0xbd, 0xc9, 0x0e, 0x58, 0xdb, 0x26, 0xa3, 0xde, 0x68, 0xf7, 0x7f, 0x2d, 0x01, 0x5c, 0x8f,
-	0xbd, 0xb5, 0x17, 0x00, 0x9f, 0x4b, 0x85, 0xff, 0x36, 0x97, 0xf6, 0xa0, 0xe2, 0x51, 0x7f, 0x39,
-	0x0b, 0x27, 0x7f, 0x83, 0x08, 0x29, 0x39, 0xaf, 0x4a, 0xd9, 0x79, 0x95, 0xa0, 0xbb, 0xcc, 0x29,
-	0x7b, 0xb0, 0x32, 0x8c, 0xd7, 0xf2, 0xfd, 0xab, 0x04, 0xdf, 0x95, 0xcc, 0x59, 0xc5, 0xce, 0xb9,
-	0x84, 0xa3, 0xe7, 0x50, 0xa1, 0xe7, 0xd4, 0x09, 0x7c, 0xa5, 0xca, 0x1d, 0x5b, 0xa9, 0x5d, 0xa9,
-	0xa2, 0xc0, 0x30, 0x83, 0x10, 0x81, 0x5c, 0x6d, 0xd5, 0xda, 0xff, 0xd8, 0xaa, 0xf5, 0xfc, 0x56,
-	0x4d, 0xf6, 0x07, 0xa4, 0xfb, 0x63, 0xb5, 0xf4, 0x37, 0xf3, 0x4a, 0xff, 0x47, 0x2b, 0x1b, 0x1b,
-	0x76, 0x72, 0x98, 0x62, 0x0e, 0x81, 0xbb, 0x98, 0x4e, 0x44, 0x90, 0x50, 0x58, 0xe5, 0xad, 0x90,
-	0xc3, 0x1b, 0x82, 0x92, 0x6d, 0x05, 0x16, 0xbf, 0xb6, 0xea, 0x84, 0x7f, 0x77, 0x7f, 0x90, 0x60,
-	0x87, 0xdd, 0x5a, 0xd1, 0x22, 0x37, 0x9a, 0x99, 0x4f, 0x20, 0x26, 0xdc, 0x14, 0x13, 0x31, 0x5c,
-	0x37, 0x66, 0xf6, 0x28, 0x9c, 0x8c, 0x2f, 0xa0, 0x64, 0x79, 0x67, 0xbe, 0xb8, 0x30, 0x1f, 0x45,
-	0xaf, 0x95, 0xd5, 0x75, 0x7b, 0x7d, 0xef, 0x4c, 0xcc, 0x45, 0xee, 0xd1, 0xfa, 0x25, 0xd4, 0x63,
-	0xd5, 0x8d, 0xe8, 0x3b, 0x81, 0x5a, 0xdc, 0x72, 0x7b, 0x50, 0xf1, 0x03, 0x2b, 0x58, 0xfa, 0xdc,
-	0xb5, 0x4c, 0x84, 0xf4, 0x89, 0x4b, 0x5e, 0x49, 0x5e, 0xf2, 0x2c, 0x72, 0x24, 0x76, 0xaf, 0x0a,
-	0x20, 0x5f, 0x27, 0x2d, 0x16, 0xf8, 0x59, 0xe2, 0xbd, 0x23, 0xf1, 0xa9, 0x1d, 0xdd, 0x8a, 0x39,
-	0xaf, 0x9d, 0x57, 0xc9, 0x86, 0x0c, 0x67, 0xd8, 0xe3, 0x88, 0x91, 0x4c, 0xe0, 0xb5, 0x6d, 0xf9,
-	0x55, 0xa2, 0x2d, 0x33, 0xa4, 0x66, 0x43, 0xe4, 0x37, 0xe7, 0xa3, 0xb8, 0x39, 0xc3, 0x7b, 0xaa,
-	0x21, 0xdc, 0x53, 0xed, 0xf8, 0xe3, 0x15, 0xff, 0x09, 0x94, 0xff, 0x1f, 0xe5, 0xfe, 0xec, 0x87,
-	0x32, 0x34, 0x92, 0xcf, 0x6a, 0xd4, 0x84, 0xfa, 0xb1, 0x3e, 0xc0, 0xaf, 0x35, 0x1d, 0x0f, 0xe4,
-	0x0d, 0xd4, 0x80, 0x1a, 0xc1, 0x6f, 0xb4, 0xb1, 0x81, 0x89, 0x2c, 0xa1, 0x2d, 0x80, 0x48, 0xc2,
-	0x03, 0xb9, 0x80, 0x36, 0xa1, 0x3a, 0x22, 0x78, 0xd4, 0x27, 0x58, 0x2e, 0xa2, 0x3a, 0x94, 0x09,
-	0xee, 0x0f, 0x4e, 0xe5, 0x12, 0xaa, 0x41, 0x49, 0xd3, 0x35, 0x43, 0x2e, 0x23, 0x80, 0x8a, 0xa6,
-	0x9f, 0x0c, 0xdf, 0x62, 0xb9, 0xc2, 0xbc, 0x8d, 0x77, 0x26, 0xc1, 0xbf, 0x3b, 0xc6, 0x63, 0x43,
-	0xae, 0xa2, 0x6d, 0xd8, 0xe4, 0xf2, 0x78, 0x34, 0xd4, 0xc7, 0x58, 0xae, 0xa1, 0x5d, 0xb8, 0xf5,
-	0x06, 0x1b, 0xe6, 0xd8, 0xe8, 0x1b, 0x38, 0xc6, 0xd5, 0xd1, 0x1e, 0xa0, 0xa4, 0x5a, 0xc0, 0x01,
-	0x29, 0x70, 0x9b, 0xe9, 0x5f, 0x9d, 0x1a, 0x58, 0x1d, 0x0e, 0xae, 0x3d, 0x36, 0xd1, 0x1d, 0xd8,
-	0xcd, 0x58, 0x84, 0x53, 0x83, 0x99, 0xd4, 0xfe, 0xe1, 0xa1, 0xa9, 0x0e, 0x75, 0x83, 0xf4, 0x55,
-	0x23, 0xf6, 0x6a, 0xa2, 0x16, 0xec, 0x65, 0x4d, 0xc2, 0x6d, 0x8b, 0xd1, 0xa2, 0x0e, 0x8f, 0x46,
-	0x87, 0xd8, 0xc0, 0x03, 0x79, 0x9b, 0xed, 0x15, 0x13, 0x32, 0x24, 0xb2, 0x8c, 0xda, 0xd0, 0x52,
-	0x09, 0x66, 0xa9, 0xbd, 0x3d, 0x31, 0x35, 0x03, 0x93, 0xbe, 0x31, 0x24, 0x71, 0xd4, 0x5b, 0xe8,
-	0x01, 0xdc, 0xcd, 0xb5, 0x8b, 0xd0, 0x88, 0x03, 0x86, 0xfa, 0xf8, 0xf8, 0x28, 0x3f, 0xc2, 0x0e,
-	0xea, 0xc0, 0xbd, 0x7c, 0x80, 0x08, 0x71, 0x1b, 0x3d, 0x84, 0x07, 0xd1, 0x1a, 0xf8, 0xd4, 0x3c,
-	0xd0, 0xc6, 0xc6, 0x90, 0x9c, 0x72, 0x64, 0x1c, 0x66, 0x77, 0x0d, 0x28, 0xc4, 0x88, 0x48, 0x7b,
-	0xe8, 0x11, 0x74, 0xe2, 0xb5, 0xd6, 0x85, 0xfa, 0x0c, 0x3d, 0x86, 0xcf, 0x3f, 0x81, 0x12, 0xc1,
-	0x14, 0x46, 0x0d, 0x3f, 0x38, 0xac, 0x0f, 0x30, 0x31, 0xfb, 0x83, 0x01, 0xc1, 0xe3, 0x71, 0x1c,
-	0xe6, 0x0e, 0xdb, 0x79, 0xae, 0x5d, 0x04, 0x68, 0xa1, 0xbb, 0xf0, 0x19, 0x3f, 0xc7, 0xbe, 0xa1,
-	0x1e, 0x64, 0xca, 0xe2, 0x2e, 0xba, 0x07, 0xca, 0xaa, 0x51, 0xb8, 0xde, 0x7b, 0xf6, 0xe7, 0x0a,
-	0xd4, 0xa2, 0x17, 0x38, 0x4b, 0x84, 0x1c, 0xeb, 0x86, 0x76, 0x84, 0x4d, 0x51, 0xaf, 0x66, 0xa2,
-	0x12, 0x37, 0x18, 0xc3, 0x91, 0xfd, 0x0d, 0x19, 0xa9, 0x3c, 0xa3, 0x24, 0x42, 0x42, 0x5d, 0x68,
-	0x63, 0xfd, 0x8d, 0xa6, 0x0b, 0x00, 0xc1, 0x2a, 0xd6, 0x4e, 0x52, 0x51, 0x0a, 0xe8, 0x09, 0x3c,
-	0x14, 0x98, 0xb1, 0x7a, 0x80, 0x07, 0xc7, 0x87, 0x9c, 0x8d, 0x15, 0x60, 0x91, 0x91, 0xbc, 0x02,
-	0xcc, 0x2e, 0x59, 0x62, 0xe7, 0x15, 0x2f, 0x39, 0x3c, 0x1e, 0xe5, 0x85, 0x2a, 0xb3, 0xcc, 0x53,
-	0xa0, 0x6c, 0x98, 0x0a, 0x3b, 0x2b, 0x81, 0x18, 0x91, 0xa1, 0x1a, 0x12, 0xbc, 0x12, 0xa8, 0x9a,
-	0xd8, 0x60, 0x04, 0xcb, 0x86, 0xaa, 0xa1, 0x9f, 0x40, 0xf7, 0x53, 0xa1, 0x04, 0xf7, 0x75, 0x96,
-	0xf9, 0xb8, 0xaf, 0x0f, 0x5e, 0x0d, 0xdf, 0xad, 0x65, 0x0b, 0x58, 0xe6, 0x29, 0x50, 0x76, 0xb9,
-	0xcd, 0x24, 0x42, 0x3d, 0xe8, 0xeb, 0x2b, 0x88, 0x06, 0x4b, 0x28, 0x42, 0x1c, 0xf4, 0xf5, 0xc1,
-	0x1a, 0xc2, 0x9b, 0xac, 0x8e, 0xb2, 0x38, 0xfc, 0x0e, 0xab, 0xc7, 0x06, 0x6b, 0xed, 0x84, 0x91,
-	0xaf, 0xa0, 0x1e, 0xf4, 0x35, 0x9d, 0x6f, 0x47, 0xde, 0xce, 0xcf, 0x32, 0x81, 0x90, 0x59, 0x19,
-	0xa6, 0xdc, 0x05, 0x43, 0xdc, 0x7a, 0x8b, 0x1d, 0x75, 0xaa, 0xb2, 0xf2, 0x08, 0x43, 0x8c, 0xfc,
-	0x18, 0x85, 0x0d, 0x53, 0x1f, 0x1a, 0xda, 0xeb, 0xd3, 0x14, 0x66, 0x87, 0x55, 0x57, 0x84, 0xc9,
-	0xdd, 0x6b, 0x3c, 0x0c, 0x12, 0xc5, 0x1e, 0x02, 0x53, 0xf6, 0xdd, 0x67, 0x9d, 0xeb, 0x89, 0xcf,
-	0xde, 0xd1, 0xa8, 0x02, 0x85, 0xe1, 0x5b, 0x79, 0x83, 0x0d, 0xed, 0xd7, 0x7d, 0xed, 0x50, 0x96,
-	0x9e, 0x7f, 0x0d, 0x9b, 0x11, 0x82, 0x2c, 0x26, 0xe8, 0x6d, 0xf4, 0xf0, 0x62, 0x0e, 0xf3, 0xf9,
-	0xd2, 0x99, 0x4e, 0xac, 0x80, 0xa2, 0xbd, 0xcc, 0xa3, 0x5c, 0xfc, 0xcf, 0x6f, 0xad, 0xd1, 0x77,
-	0x37, 0x9e, 0x4a, 0x3f, 0x97, 0x5e, 0x1d, 0xfc, 0xed, 0x63, 0x5b, 0xfa, 0xfe, 0x63, 0x5b, 0xfa,
-	0xe7, 0xc7, 0xb6, 0xf4, 0xa7, 0xab, 0xf6, 0xc6, 0xf7, 0x57, 0xed, 0x8d, 0x7f, 0x5c, 0xb5, 0x37,
-	0xbe, 0xee, 0xf1, 0x87, 0xe8, 0xdc, 0xfa, 0x40, 0xbd, 0x9e, 0xeb, 0x9d, 0xed, 0x5f, 0x8b, 0xfb,
-	0xe7, 0xf3, 0x2f, 0xa8, 0x73, 0x36, 0x75, 0xe8, 0xfe, 0xe2, 0x9b, 0x7d, 0x1e, 0xfd, 0xcc, 0xfd,
-	0xa6, 0xc2, 0x3f, 0x7e, 0xf1, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4b, 0x7b, 0x74, 0xc4, 0x74,
-	0x12, 0x00, 0x00,
+	// 1727 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0xcd, 0x72, 0xdb, 0xc8,
+	0x11, 0x16, 0xf8, 0xcf, 0x16, 0x24, 0xc1, 0x23, 0x4b, 0x0b, 0xd3, 0x36, 0xcd, 0xa5, 0xed, 0x58,
+	0x71, 0x6a, 0xa5, 0x94, 0x73, 0x88, 0xe3, 0x4d, 0x6a, 0x43, 0x83, 0x63, 0x0b, 0x65, 0x09, 0x64,
+	0x86, 0x90, 0x62, 0xed, 0x05, 0x85, 0x25, 0x66, 0x25, 0x96, 0x49, 0x80, 0x01, 0x40, 0xad, 0xf4,
+	0x16, 0xb9, 0xe5, 0x35, 0xf2, 0x18, 0xb9, 0xa4, 0x6a, 0x4f, 0x5b, 0x49, 0xe5, 0x92, 0xb2, 0x1f,
+	0x23, 0x39, 0xa4, 0x66, 0x30, 0x80, 0x00, 0x10, 0xf2, 0x46, 0x87, 0x2d, 0x9f, 0xc4, 0xee, 0xfe,
+	0xba, 0xa7, 0xfb, 0x9b, 0xee, 0xc6, 0x94, 0x60, 0xdb, 0xf1, 0xc6, 0xef, 0xa8, 0x7f, 0x3e, 0xb3,
+	0x66, 0x34, 0x08, 0xec, 0x53, 0xba, 0x3b, 0xf7, 0xbd, 0xd0, 0x43, 0x55, 0xfe, 0xa7, 0xfb, 0xdf,
+	0x12, 0x6c, 0xf4, 0x39, 0xe2, 0xf8, 0xf0, 0x30, 0x02, 0xa0, 0x4d, 0xa8, 0x86, 0x17, 0xd6, 0xc4,
+	0x51, 0xa5, 0x8e, 0xb4, 0xd3, 0x24, 0x95, 0xf0, 0x42, 0x77, 0xd0, 0x13, 0xa8, 0x84, 0x97, 0x73,
+	0xaa, 0x96, 0x3a, 0xd2, 0xce, 0xfa, 0xb3, 0xcd, 0x28, 0xca, 0x6e, 0xec, 0x6a, 0x5e, 0xce, 0x29,
+	0xe1, 0x00, 0xf4, 0x1c, 0xd6, 0xc6, 0xbe, 0x17, 0x04, 0xd6, 0xd8, 0x73, 0x43, 0x7a, 0x11, 0xaa,
+	0xe5, 0x8e, 0xb4, 0xb3, 0x9a, 0x78, 0x68, 0xcc, 0xa6, 0x45, 0x26, 0x22, 0x8f, 0x53, 0x12, 0xfa,
+	0x0a, 0x94, 0xe0, 0x32, 0xb0, 0xc6, 0xf6, 0x74, 0x1a, 0x27, 0xab, 0x56, 0xb8, 0xf3, 0x96, 0x70,
+	0x1e, 0x5d, 0x06, 0x9a, 0x3d, 0x9d, 0x8a, 0x44, 0xc9, 0x7a, 0x90, 0x91, 0xd1, 0x53, 0xa8, 0xfb,
+	0xf4, 0x4f, 0x0b, 0x1a, 0x84, 0x6a, 0x95, 0xfb, 0x29, 0xc2, 0xcf, 0xbc, 0x20, 0x91, 0x9e, 0xc4,
+	0x00, 0xf4, 0x05, 0x34, 0x7c, 0x1a, 0xcc, 0x3d, 0x37, 0xa0, 0x6a, 0x8d, 0x83, 0x6f, 0xa5, 0xc0,
+	0x91, 0x81, 0x24, 0x10, 0x74, 0x07, 0x1a, 0xe3, 0x33, 0x7b, 0xe2, 0x32, 0x5a, 0xea, 0x9c, 0x96,
+	0x3a, 0x97, 0x75, 0x07, 0xbd, 0x80, 0xf5, 0x20, 0xa4, 0x73, 0xcb, 0x59, 0xf8, 0x76, 0x38, 0xf1,
+	0xdc, 0x40, 0x6d, 0x74, 0xca, 0xa9, 0x8a, 0x47, 0x21, 0x9d, 0xf7, 0x85, 0x8d, 0xac, 0x05, 0x29,
+	0x29, 0xe8, 0xfe, 0x5d, 0x82, 0xf5, 0x6c, 0x51, 0x8c, 0xe8, 0xb1, 0xe7, 0x50, 0x55, 0x2a, 0x24,
+	0x5a, 0xf3, 0x1c, 0x4a, 0x38, 0x00, 0xa9, 0x50, 0x8f, 0x59, 0x2a, 0x45, 0x19, 0x09, 0x11, 0xfd,
+	0x16, 0xea, 0x73, 0xfb, 0x72, 0xea, 0xd9, 0x8e, 0x5a, 0xe6, 0xa9, 0x74, 0x0b, 0xf9, 0xdb, 0x1d,
+	0x46, 0x20, 0xec, 0x86, 0xfe, 0x25, 0x89, 0x5d, 0x5a, 0x2f, 0x40, 0x4e, 0x1b, 0x90, 0x02, 0xe5,
+	0x77, 0xf4, 0x52, 0x34, 0x03, 0xfb, 0x89, 0x6e, 0x43, 0xf5, 0xdc, 0x9e, 0x2e, 0xa2, 0x73, 0x65,
+	0x12, 0x09, 0x2f, 0x4a, 0xcf, 0xa5, 0xee, 0x02, 0xe4, 0xf4, 0x05, 0xa3, 0x87, 0xb0, 0x36, 0x5e,
+	0xf8, 0x3e, 0x75, 0x43, 0xcb, 0xa1, 0xf3, 0xf0, 0x8c, 0x47, 0x59, 0x23, 0xb2, 0x50, 0xf6, 0x99,
+	0x0e, 0x7d, 0x0e, 0xf2, 0xdc, 0xf7, 0xc6, 0x34, 0x08, 0x2c, 0xd7, 0x9e, 0xc5, 0xd5, 0xac, 0x0a,
+	0x9d, 0x61, 0xcf, 0x28, 0xba, 0x0f, 0x10, 0x35, 0xd5, 0xc4, 0xfd, 0xd6, 0xe3, 0x1d, 0x55, 0x21,
+	0x4d, 0xae, 0xd1, 0xdd, 0x6f, 0xbd, 0xee, 0x5f, 0x25, 0x90, 0xd3, 0x34, 0xa3, 0x87, 0xa2, 0x5b,
+	0x23, 0x12, 0x37, 0x52, 0x37, 0x91, 0xea, 0xd4, 0xfb, 0x00, 0x41, 0x68, 0xfb, 0xa1, 0x15, 0x4e,
+	0xc4, 0xa9, 0x65, 0xd2, 0xe4, 0x1a, 0x73, 0x32, 0xa3, 0x2c, 0xf7, 0xcc, 0xbd, 0xf2, 0x63, 0xcb,
+	0x44, 0x4e, 0xdf, 0x20, 0x7a, 0x0c, 0xeb, 0x0b, 0x37, 0x9c, 0x4c, 0xaf, 0x50, 0x15, 0x8e, 0x5a,
+	0xe3, 0xda, 0x04, 0xa6, 0x40, 0x79, 0x16, 0x9c, 0xf2, 0xae, 0x6c, 0x12, 0xf6, 0xb3, 0xfb, 0xaf,
+	0x32, 0x34, 0x93, 0xb6, 0xe4, 0x3c, 0x79, 0x6e, 0xe8, 0xdb, 0xe3, 0x30, 0xe2, 0x20, 0x62, 0x5b,
+	0x8e, 0x95, 0x9c, 0x84, 0x9f, 0x83, 0x92, 0x80, 0xce, 0xa9, 0x1f, 0xb0, 0xd3, 0x22, 0xae, 0x36,
+	0x62, 0xfd, 0x71, 0xa4, 0x46, 0xdb, 0x50, 0x9b, 0xd1, 0xf0, 0xcc, 0x73, 0x78, 0xd2, 0x4d, 0x22,
+	0x24, 0xf4, 0x7b, 0x80, 0xb9, 0xed, 0xdb, 0x33, 0x1a, 0x52, 0x3f, 0x50, 0x2b, 0xbc, 0x39, 0x3a,
+	0xf9, 0x21, 0xd9, 0x1d, 0x26, 0x90, 0xa8, 0x35, 0x52, 0x3e, 0x68, 0x0f, 0x20, 0xbc, 0x48, 0x66,
+	0x3b, 0x3f, 0x66, 0xf1, 0x60, 0x37, 0xc3, 0xf8, 0x67, 0x66, 0x72, 0x6a, 0xd9, 0xc9, 0x49, 0x57,
+	0x6d, 0x3b, 0x8e, 0xaf, 0xd6, 0xb3, 0x55, 0xf7, 0x1c, 0xc7, 0x67, 0x0c, 0x27, 0xa0, 0x89, 0xeb,
+	0xd0, 0x0b, 0xb5, 0xc1, 0x7b, 0x28, 0x71, 0xd5, 0x99, 0x32, 0x8a, 0x35, 0x9b, 0x2f, 0x42, 0x6a,
+	0x4d, 0x27, 0xb3, 0x49, 0xa8, 0x36, 0x79, 0x93, 0xc8, 0x42, 0x79, 0xc0, 0x74, 0x68, 0x07, 0x94,
+	0x33, 0x5b, 0x6c, 0x98, 0xb8, 0x04, 0xe8, 0x48, 0x3b, 0x0d, 0xb2, 0x7e, 0x66, 0xf3, 0xd1, 0x10,
+	0x59, 0xb7, 0x7e, 0x07, 0x1b, 0x39, 0x16, 0x6e, 0x34, 0x07, 0xff, 0x91, 0xd8, 0xed, 0xc6, 0x14,
+	0x7c, 0x09, 0xcd, 0xef, 0xfc, 0x49, 0x48, 0xad, 0x99, 0x3d, 0x57, 0x25, 0x4e, 0x7a, 0x3b, 0x4f,
+	0xd9, 0xee, 0x1f, 0x19, 0xe2, 0xd0, 0x9e, 0x47, 0x94, 0x37, 0xbe, 0x13, 0x22, 0x7a, 0xce, 0x16,
+	0x95, 0xed, 0x70, 0xdf, 0x12, 0xf7, 0xbd, 0xbf, 0xe4, 0x4b, 0xa8, 0xed, 0x24, 0xae, 0x75, 0x3f,
+	0x92, 0x5a, 0x5f, 0xc2, 0x5a, 0x26, 0xe8, 0x4d, 0x2a, 0x60, 0x5b, 0x20, 0x1d, 0xf5, 0x46, 0xd5,
+	0xff, 0xb3, 0x02, 0x70, 0xb5, 0x45, 0xaf, 0xfd, 0x9e, 0xf0, 0x35, 0x57, 0xfa, 0xb1, 0x35, 0xb7,
+	0x0d, 0x35, 0x9f, 0x06, 0x8b, 0x69, 0xf4, 0x21, 0x91, 0x89, 0x90, 0xd2, 0xeb, 0xaf, 0x92, 0x5f,
+	0x7f, 0x29, 0xba, 0xab, 0x9c, 0xb2, 0x07, 0x4b, 0xbb, 0xfd, 0x5a, 0xbe, 0x7f, 0x93, 0xe2, 0xbb,
+	0x96, 0xbb, 0xab, 0xc4, 0xb9, 0x90, 0x70, 0xf4, 0x0c, 0x6a, 0xf4, 0x9c, 0xba, 0x61, 0xa0, 0xd6,
+	0xb9, 0x63, 0x2b, 0x53, 0x95, 0x26, 0xfa, 0x15, 0x33, 0x08, 0x11, 0xc8, 0xe5, 0xc9, 0x6f, 0xfc,
+	0x9f, 0x93, 0xdf, 0x2c, 0x9e, 0xfc, 0xf4, 0xb8, 0x41, 0x76, 0xdc, 0x96, 0x27, 0x69, 0xf5, 0x47,
+	0x26, 0x69, 0xe1, 0x4e, 0xc2, 0x40, 0x95, 0x33, 0x93, 0x74, 0xc4, 0x74, 0x9f, 0xae, 0xb7, 0x1c,
+	0xd8, 0x2c, 0xa0, 0x93, 0x39, 0x84, 0xde, 0x7c, 0x32, 0x16, 0x41, 0x22, 0x61, 0x99, 0xdc, 0x52,
+	0x01, 0xb9, 0x08, 0x2a, 0x8e, 0x1d, 0xda, 0xfc, 0x53, 0xd9, 0x24, 0xfc, 0x77, 0xf7, 0x07, 0x09,
+	0x36, 0xe3, 0x75, 0xc0, 0x80, 0x37, 0xda, 0xd3, 0x4f, 0x20, 0xb9, 0x15, 0x4b, 0x6c, 0xe1, 0xe8,
+	0xdc, 0x84, 0xfe, 0x43, 0xae, 0x45, 0xcf, 0xa1, 0x62, 0xfb, 0xa7, 0x81, 0xf8, 0x48, 0x3f, 0x8a,
+	0x5f, 0x48, 0xcb, 0xe7, 0xee, 0xf6, 0xfc, 0x53, 0xb1, 0x8b, 0xb9, 0x47, 0xeb, 0xd7, 0xd0, 0x4c,
+	0x54, 0x37, 0xa2, 0xef, 0x18, 0x1a, 0xc9, 0x5c, 0x6e, 0x43, 0x2d, 0x08, 0xed, 0x70, 0x11, 0x70,
+	0xd7, 0x2a, 0x11, 0xd2, 0x47, 0x1e, 0x16, 0x6a, 0xfa, 0x61, 0xc1, 0x22, 0xc7, 0x62, 0xf7, 0x43,
+	0x09, 0x94, 0xab, 0xa4, 0xc5, 0x01, 0xbf, 0x48, 0xbd, 0xb1, 0x24, 0xfe, 0xa5, 0x88, 0xbf, 0xc4,
+	0x05, 0x2f, 0xac, 0x97, 0xe9, 0xa9, 0x8d, 0x16, 0xdd, 0xe3, 0x98, 0x91, 0x5c, 0xe0, 0x6b, 0x67,
+	0xf7, 0xab, 0xd4, 0xec, 0xe6, 0x48, 0xcd, 0x87, 0x28, 0x9e, 0xe0, 0x47, 0xc9, 0x04, 0x47, 0xdf,
+	0x46, 0x59, 0xb8, 0x67, 0x66, 0xf6, 0xd3, 0x35, 0xff, 0x31, 0x54, 0x7f, 0x8a, 0x76, 0x7f, 0xfa,
+	0x43, 0x15, 0xe4, 0xf4, 0x53, 0x1e, 0xad, 0x41, 0xf3, 0xc8, 0xe8, 0xe3, 0x57, 0xba, 0x81, 0xfb,
+	0xca, 0x0a, 0x92, 0xa1, 0x41, 0xf0, 0x6b, 0x7d, 0x64, 0x62, 0xa2, 0x48, 0x68, 0x1d, 0x20, 0x96,
+	0x70, 0x5f, 0x29, 0xa1, 0x55, 0xa8, 0x0f, 0x09, 0x1e, 0xf6, 0x08, 0x56, 0xca, 0xa8, 0x09, 0x55,
+	0x82, 0x7b, 0xfd, 0x13, 0xa5, 0x82, 0x1a, 0x50, 0xd1, 0x0d, 0xdd, 0x54, 0xaa, 0x08, 0xa0, 0xa6,
+	0x1b, 0xc7, 0x83, 0x37, 0x58, 0xa9, 0x31, 0x6f, 0xf3, 0xad, 0x45, 0xf0, 0x1f, 0x8e, 0xf0, 0xc8,
+	0x54, 0xea, 0x68, 0x03, 0x56, 0xb9, 0x3c, 0x1a, 0x0e, 0x8c, 0x11, 0x56, 0x1a, 0x68, 0x0b, 0x6e,
+	0xbd, 0xc6, 0xa6, 0x35, 0x32, 0x7b, 0x26, 0x4e, 0x70, 0x4d, 0xb4, 0x0d, 0x28, 0xad, 0x16, 0x70,
+	0x40, 0x2a, 0xdc, 0x66, 0xfa, 0x97, 0x27, 0x26, 0xd6, 0x06, 0xfd, 0x2b, 0x8f, 0x55, 0x74, 0x07,
+	0xb6, 0x72, 0x16, 0xe1, 0x24, 0x33, 0x93, 0xd6, 0x3b, 0x38, 0xb0, 0xb4, 0x81, 0x61, 0x92, 0x9e,
+	0x66, 0x26, 0x5e, 0x6b, 0xa8, 0x05, 0xdb, 0x79, 0x93, 0x70, 0x5b, 0x67, 0xb4, 0x68, 0x83, 0xc3,
+	0xe1, 0x01, 0x36, 0x71, 0x5f, 0xd9, 0x60, 0xb5, 0x62, 0x42, 0x06, 0x44, 0x51, 0x50, 0x1b, 0x5a,
+	0x1a, 0xc1, 0x2c, 0xb5, 0x37, 0xc7, 0x96, 0x6e, 0x62, 0xd2, 0x33, 0x07, 0x24, 0x89, 0x7a, 0x0b,
+	0x3d, 0x80, 0xbb, 0x85, 0x76, 0x11, 0x1a, 0x71, 0xc0, 0xc0, 0x18, 0x1d, 0x1d, 0x16, 0x47, 0xd8,
+	0x44, 0x1d, 0xb8, 0x57, 0x0c, 0x10, 0x21, 0x6e, 0xa3, 0x87, 0xf0, 0x20, 0x3e, 0x03, 0x9f, 0x58,
+	0xfb, 0xfa, 0xc8, 0x1c, 0x90, 0x13, 0x8e, 0x4c, 0xc2, 0x6c, 0x5d, 0x03, 0x32, 0x71, 0x2a, 0xd2,
+	0x36, 0x7a, 0x04, 0x9d, 0xe4, 0xac, 0xeb, 0x42, 0x7d, 0x86, 0x1e, 0xc3, 0xe7, 0x1f, 0x41, 0x89,
+	0x60, 0x2a, 0xa3, 0x86, 0x5f, 0x1c, 0x36, 0xfa, 0x98, 0x58, 0xbd, 0x7e, 0x9f, 0xe0, 0xd1, 0x28,
+	0x09, 0x73, 0x87, 0x55, 0x5e, 0x68, 0x17, 0x01, 0x5a, 0xe8, 0x2e, 0x7c, 0xc6, 0xef, 0xb1, 0x67,
+	0x6a, 0xfb, 0xb9, 0xb6, 0xb8, 0x8b, 0xee, 0x81, 0xba, 0x6c, 0x14, 0xae, 0xf7, 0x9e, 0xfe, 0xa5,
+	0x06, 0x8d, 0xf8, 0xd5, 0xcf, 0x12, 0x21, 0x47, 0x86, 0xa9, 0x1f, 0x62, 0x4b, 0xf4, 0xab, 0x95,
+	0xea, 0xc4, 0x15, 0xc6, 0x70, 0x6c, 0x7f, 0x4d, 0x86, 0x1a, 0xcf, 0x28, 0x8d, 0x90, 0x50, 0x17,
+	0xda, 0xd8, 0x78, 0xad, 0x1b, 0x02, 0x40, 0xb0, 0x86, 0xf5, 0xe3, 0x4c, 0x94, 0x12, 0x7a, 0x02,
+	0x0f, 0x05, 0x66, 0xa4, 0xed, 0xe3, 0xfe, 0xd1, 0x01, 0x26, 0x45, 0xc0, 0x32, 0x23, 0x79, 0x09,
+	0x98, 0x3f, 0xb2, 0xc2, 0xee, 0x2b, 0x39, 0x72, 0x70, 0x34, 0x2c, 0x0a, 0x55, 0x65, 0x99, 0x67,
+	0x40, 0xf9, 0x30, 0x35, 0x76, 0x57, 0x02, 0x31, 0x24, 0x03, 0x2d, 0x22, 0x78, 0x29, 0x50, 0x3d,
+	0x55, 0x60, 0x0c, 0xcb, 0x87, 0x6a, 0xa0, 0x9f, 0x41, 0xf7, 0x63, 0xa1, 0x04, 0xf7, 0x4d, 0x96,
+	0xf9, 0xa8, 0x67, 0xf4, 0x5f, 0x0e, 0xde, 0x5e, 0xcb, 0x16, 0xb0, 0xcc, 0x33, 0xa0, 0xfc, 0x71,
+	0xab, 0x69, 0x84, 0xb6, 0xdf, 0x33, 0x96, 0x10, 0x32, 0x4b, 0x28, 0x46, 0xec, 0xf7, 0x8c, 0xfe,
+	0x35, 0x84, 0xaf, 0xb1, 0x3e, 0xca, 0xe3, 0xf0, 0x5b, 0xac, 0x1d, 0x99, 0x6c, 0xb4, 0x53, 0x46,
+	0x7e, 0x82, 0xb6, 0xdf, 0xd3, 0x0d, 0x5e, 0x8e, 0xb2, 0x51, 0x9c, 0x65, 0x0a, 0xa1, 0xb0, 0x36,
+	0xcc, 0xb8, 0x0b, 0x86, 0xb8, 0xf5, 0x16, 0xbb, 0xea, 0x4c, 0x67, 0x15, 0x11, 0x86, 0x18, 0xf9,
+	0x09, 0x0a, 0x9b, 0x96, 0x31, 0x30, 0xf5, 0x57, 0x27, 0x19, 0xcc, 0x26, 0xeb, 0xae, 0x18, 0x53,
+	0x58, 0x6b, 0xb2, 0x0c, 0x52, 0xcd, 0x1e, 0x01, 0x33, 0xf6, 0xad, 0xa7, 0x1d, 0x90, 0xd3, 0x8f,
+	0x6d, 0x54, 0x83, 0xd2, 0xe0, 0x8d, 0xb2, 0xc2, 0x96, 0xf6, 0xab, 0x9e, 0x7e, 0xa0, 0x48, 0xcf,
+	0xbe, 0x86, 0xd5, 0x18, 0x41, 0xe6, 0x63, 0xf4, 0x06, 0x36, 0xaf, 0x1c, 0x66, 0xb3, 0x85, 0x3b,
+	0x19, 0xdb, 0x21, 0x45, 0xdb, 0xb9, 0x97, 0xbb, 0xf8, 0xdf, 0x42, 0xeb, 0x1a, 0x7d, 0x77, 0x65,
+	0x47, 0xfa, 0xa5, 0xf4, 0x72, 0xff, 0x6f, 0xef, 0xdb, 0xd2, 0xf7, 0xef, 0xdb, 0xd2, 0xbf, 0xdf,
+	0xb7, 0xa5, 0x3f, 0x7f, 0x68, 0xaf, 0x7c, 0xff, 0xa1, 0xbd, 0xf2, 0x8f, 0x0f, 0xed, 0x95, 0xaf,
+	0x77, 0xf9, 0x6b, 0x75, 0x66, 0xbf, 0xa3, 0xfe, 0xae, 0xe7, 0x9f, 0xee, 0x5d, 0x89, 0x7b, 0xe7,
+	0xb3, 0x2f, 0xa8, 0x7b, 0x3a, 0x71, 0xe9, 0xde, 0xfc, 0x9b, 0x3d, 0x1e, 0xfd, 0xd4, 0xfb, 0xa6,
+	0xc6, 0x7f, 0xfc, 0xea, 0x7f, 0x03, 0x00, 0x98, 0x30, 0x4c, 0xcb, 0xe8, 0x12, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1676,6 +1705,21 @@ func (m *TxRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.HasCallContext {
+		i--
+		if m.HasCallContext {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.ComputeLimit != 0 {
+		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ComputeLimit))
+		i--
+		dAtA[i] = 0x48
+	}
 	if m.ContractIndex != 0 {
 		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ContractIndex))
 		i--
@@ -1837,6 +1881,11 @@ func (m *TxResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ComputeUnits != 0 {
+		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ComputeUnits))
+		i--
+		dAtA[i] = 0x60
+	}
 	if m.ContractIndex != 0 {
 		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ContractIndex))
 		i--
@@ -2401,6 +2450,12 @@ func (m *TxRequest) Size() (n int) {
 	if m.ContractIndex != 0 {
 		n += 1 + sovDockervmMessage(uint64(m.ContractIndex))
 	}
+	if m.ComputeLimit != 0 {
+		n += 1 + sovDockervmMessage(uint64(m.ComputeLimit))
+	}
+	if m.HasCallContext {
+		n += 2
+	}
 	return n
 }
 
@@ -2503,6 +2558,9 @@ func (m *TxResponse) Size() (n int) {
 	if m.ContractIndex != 0 {
 		n += 1 + sovDockervmMessage(uint64(m.ContractIndex))
 	}
+	if m.ComputeUnits != 0 {
+		n += 1 + sovDockervmMessage(uint64(m.ComputeUnits))
+	}
 	return n
 }
 
@@ -3840,6 +3898,45 @@ func (m *TxRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ComputeLimit", wireType)
+			}
+			m.ComputeLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ComputeLimit |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HasCallContext", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HasCallContext = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDockervmMessage(dAtA[iNdEx:])
@@ -4718,6 +4815,25 @@ func (m *TxResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ComputeUnits", wireType)
+			}
+			m.ComputeUnits = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ComputeUnits |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDockervmMessage(dAtA[iNdEx:])
@@ -17,6 +17,7 @@ import (
 
 	configPb "chainmaker.org/chainmaker/pb-go/v2/config"
 	"chainmaker.org/chainmaker/protocol/v2"
+	gasutils "chainmaker.org/chainmaker/utils/v2/gas"
 
 	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
 	"chainmaker.org/chainmaker/vm-engine/v2/config"
@@ -33,6 +34,7 @@ const (
 	version2312      uint32 = 2030102
 	version233       uint32 = 2030300
 	version235       uint32 = 2030500
+	version236       uint32 = 2030600
 	version300       uint32 = 3000000
 )
 
@@ -66,6 +68,7 @@ type RuntimeInstance struct {
 	DockerManager       *InstancesManager
 	//txDuration          *utils.TxDuration
 	currSysCall *utils.SysCallDuration
+	callContext *callContext // call stack of the contract, nil if not called by a go contract
 }
 
 func (r *RuntimeInstance) contractEngineMsgNotify(msg *protogo.DockerVMMessage) {
@@ -136,6 +139,10 @@ func (r *RuntimeInstance) Invoke(
 			delete(parameters, key)
 		}
 	}
+	if txSimContext.GetBlockVersion() >= version236 {
+		r.callContext = r.DockerManager.loadCallContext(originalTxId, uint32(txSimContext.GetDepth()))
+		setCallContextParam(parameters, r.callContext)
+	}
 
 	dockerVMMsg, ok := dockerVMMsgPool.Get().(*protogo.DockerVMMessage)
 	if !ok && txSimContext.GetBlockVersion() >= version235 {
@@ -151,6 +158,15 @@ func (r *RuntimeInstance) Invoke(
 	dockerVMMsg.Request.ContractIndex = contract.Index
 	dockerVMMsg.Request.Method = method
 	dockerVMMsg.Request.Parameters = parameters
+	// metered contracts stop with out of gas once the gas left in tx is consumed
+	dockerVMMsg.Request.ComputeLimit = gas.ComputeLimit(
+		txSimContext.GetBlockVersion(),
+		gasutils.NewGasConfig(txSimContext.GetLastChainConfig().AccountConfig),
+		gasUsed,
+		txSimContext.GetTx().Payload.GetLimit().GetGasLimit(),
+	)
+	// below version236 the parameter is not replaced and may come straight from the tx
+	dockerVMMsg.Request.HasCallContext = txSimContext.GetBlockVersion() >= version236
 	if txSimContext.GetBlockVersion() >= version233 && txSimContext.GetBlockVersion() < version300 {
 		// 如果是至信链的地址，需要添加前缀，CM和EVM地址不需要添加前缀
 		address := contract.Address
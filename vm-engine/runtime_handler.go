@@ -2,6 +2,7 @@ package docker_go
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -57,6 +58,20 @@ func (r *RuntimeInstance) handleTxResponse(txId string, recvMsg *protogo.DockerV
 	}()
 
 	contractResult = new(commonPb.ContractResult)
+
+	// compute units are charged whether tx succeeded or not, a metered contract
+	// which runs out of compute units fails with out of gas here
+	r.logger.Debugf("【gas calc】%v, before compute => gasUsed = %v, computeUnits = %v",
+		txSimContext.GetTx().Payload.TxId, gasUsed, txResponse.ComputeUnits)
+	gasUsed, err = gas.ComputeGasUsed(blockVersion, gasConfig, gasUsed, txResponse.ComputeUnits,
+		txSimContext.GetTx().Payload.TxId, r.logger)
+	r.logger.Debugf("【gas calc】%v, after compute => gasUsed = %v",
+		txSimContext.GetTx().Payload.TxId, gasUsed)
+	if err != nil {
+		contractResult.GasUsed = gasUsed
+		return r.errorResult(contractResult, err, err.Error())
+	}
+
 	// tx fail, just return without merge read write map and events
 	if txResponse.Code != 0 {
 		contractResult.Code = 1
@@ -216,6 +231,22 @@ func (r *RuntimeInstance) handlerCallContract(
 	}
 
 	parameters := callContractReq.Args
+	// locks declared by the caller are not contract args, they are passed down with the call stack
+	var declaredLocks []byte
+	if blockVersion >= version236 {
+		declaredLocks = parameters[config.CallContextParam]
+		delete(parameters, config.CallContextParam)
+	}
+	// evm contracts are called with abi encoded calldata, reject malformed calls before charging gas
+	if contract.RuntimeType == commonPb.RuntimeType_EVM && blockVersion >= version236 {
+		if err = checkEvmCallData(parameters); err != nil {
+			errMsg := fmt.Sprintf("[call contract] invalid calldata for evm contract [%s], err: %s",
+				contractName, err.Error())
+			r.setError(errMsg, response)
+			return response, gasUsed, specialTxType
+		}
+	}
+
 	gasUsed, err = gas.CallContractGasUsed(blockVersion, gasConfig, gasUsed,
 		contractName, contractMethod, parameters, txId, r.logger)
 	if err != nil {
@@ -228,6 +259,12 @@ func (r *RuntimeInstance) handlerCallContract(
 		return response, gasUsed, specialTxType
 	}
 
+	if contract.RuntimeType == commonPb.RuntimeType_GO && blockVersion >= version236 {
+		dropCallContext := r.DockerManager.storeCallContext(txSimContext.GetTx().Payload.TxId,
+			uint32(txSimContext.GetDepth())+1, r.callContext, caller, declaredLocks)
+		defer dropCallContext()
+	}
+
 	result, specialTxType, code = txSimContext.CallContract(caller, contract, contractMethod,
 		nil, parameters, gasUsed, txSimContext.GetTx().Payload.TxType)
 	r.logger.DebugDynamic(func() string {
@@ -289,6 +326,22 @@ func (r *RuntimeInstance) handlerCallContract(
 	return response, gasUsed, specialTxType
 }
 
+// checkEvmCallData checks that args hold hex calldata with at least a method selector
+func checkEvmCallData(args map[string][]byte) error {
+	data, ok := args[protocol.ContractEvmParamKey]
+	if !ok {
+		return fmt.Errorf("missing param [%s]", protocol.ContractEvmParamKey)
+	}
+	calldata, err := hex.DecodeString(strings.TrimPrefix(string(data), "0x"))
+	if err != nil {
+		return err
+	}
+	if len(calldata) < 4 {
+		return errors.New("calldata is shorter than method selector")
+	}
+	return nil
+}
+
 func constructCallContractResponse(
 	result *commonPb.ContractResult,
 	contractName string,
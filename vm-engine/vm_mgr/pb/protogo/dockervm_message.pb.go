@@ -561,6 +561,10 @@ type TxRequest struct {
 	ChainId       string     `protobuf:"bytes,6,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 	ContractAddr  string     `protobuf:"bytes,7,opt,name=contract_addr,json=contractAddr,proto3" json:"contract_addr,omitempty"`
 	ContractIndex uint32     `protobuf:"varint,8,opt,name=contract_index,json=contractIndex,proto3" json:"contract_index,omitempty"`
+	// compute units the metered contract may consume, 0 means unlimited
+	ComputeLimit uint64 `protobuf:"varint,9,opt,name=compute_limit,json=computeLimit,proto3" json:"compute_limit,omitempty"`
+	// the engine put the call stack under __call_context__ in parameters
+	HasCallContext bool `protobuf:"varint,10,opt,name=has_call_context,json=hasCallContext,proto3" json:"has_call_context,omitempty"`
 }
 
 func (m *TxRequest) Reset()         { *m = TxRequest{} }
@@ -652,6 +656,20 @@ func (m *TxRequest) GetContractIndex() uint32 {
 	return 0
 }
 
+func (m *TxRequest) GetComputeLimit() uint64 {
+	if m != nil {
+		return m.ComputeLimit
+	}
+	return 0
+}
+
+func (m *TxRequest) GetHasCallContext() bool {
+	if m != nil {
+		return m.HasCallContext
+	}
+	return false
+}
+
 type TxContext struct {
 	WriteMap map[string][]byte `protobuf:"bytes,1,rep,name=write_map,json=writeMap,proto3" json:"write_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	ReadMap  map[string][]byte `protobuf:"bytes,2,rep,name=read_map,json=readMap,proto3" json:"read_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -717,6 +735,8 @@ type TxResponse struct {
 	ContractVersion string                 `protobuf:"bytes,9,opt,name=contract_version,json=contractVersion,proto3" json:"contract_version,omitempty"`
 	ChainId         string                 `protobuf:"bytes,10,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 	ContractIndex   uint32                 `protobuf:"varint,11,opt,name=contract_index,json=contractIndex,proto3" json:"contract_index,omitempty"`
+	// compute units consumed by a metered contract
+	ComputeUnits uint64 `protobuf:"varint,12,opt,name=compute_units,json=computeUnits,proto3" json:"compute_units,omitempty"`
 }
 
 func (m *TxResponse) Reset()         { *m = TxResponse{} }
@@ -829,6 +849,13 @@ func (m *TxResponse) GetContractIndex() uint32 {
 	return 0
 }
 
+func (m *TxResponse) GetComputeUnits() uint64 {
+	if m != nil {
+		return m.ComputeUnits
+	}
+	return 0
+}
+
 type DockerContractEvent struct {
 	// Event topic
 	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
@@ -1185,113 +1212,116 @@ func init() {
 func init() { proto.RegisterFile("dockervm_message.proto", fileDescriptor_23619f598d968e93) }
 
 var fileDescriptor_23619f598d968e93 = []byte{
-	// 1689 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcd, 0x72, 0xdb, 0xc8,
-	0x11, 0x16, 0xf8, 0xcf, 0x16, 0x29, 0xc1, 0x23, 0x4b, 0x0b, 0xd3, 0x36, 0xcd, 0xa5, 0xed, 0xd8,
-	0x71, 0x6a, 0xa5, 0x94, 0x53, 0xa9, 0x38, 0xde, 0xa4, 0x36, 0x34, 0x38, 0xb6, 0x50, 0x96, 0x40,
-	0x66, 0x08, 0x29, 0xd6, 0x5e, 0x50, 0x58, 0x62, 0x96, 0x66, 0x99, 0x04, 0x18, 0x00, 0xd4, 0x4a,
-	0xef, 0x90, 0x43, 0x6e, 0x79, 0x8d, 0x1c, 0xf2, 0x10, 0xb9, 0xa4, 0x6a, 0x4f, 0x5b, 0x39, 0xa6,
-	0xec, 0xc7, 0x48, 0x0e, 0xa9, 0x19, 0x0c, 0x20, 0x00, 0x04, 0x9d, 0xe8, 0xb0, 0xe5, 0x93, 0xd8,
-	0xdd, 0x5f, 0xff, 0xcc, 0x37, 0xdd, 0x8d, 0x11, 0xec, 0xd9, 0xee, 0xf8, 0x1d, 0xf5, 0xce, 0xe7,
-	0xe6, 0x9c, 0xfa, 0xbe, 0x35, 0xa1, 0xfb, 0x0b, 0xcf, 0x0d, 0x5c, 0x54, 0xe6, 0x7f, 0xba, 0xff,
-	0x29, 0xc0, 0x76, 0x9f, 0x23, 0x4e, 0x8f, 0x8f, 0x43, 0x00, 0xda, 0x81, 0x72, 0x70, 0x61, 0x4e,
-	0x6d, 0x45, 0xea, 0x48, 0x8f, 0xeb, 0xa4, 0x14, 0x5c, 0x68, 0x36, 0x7a, 0x04, 0xa5, 0xe0, 0x72,
-	0x41, 0x95, 0x42, 0x47, 0x7a, 0xbc, 0xf5, 0x74, 0x27, 0x8c, 0xb2, 0x1f, 0xb9, 0x1a, 0x97, 0x0b,
-	0x4a, 0x38, 0x00, 0x3d, 0x83, 0xe6, 0xd8, 0x73, 0x7d, 0xdf, 0x1c, 0xbb, 0x4e, 0x40, 0x2f, 0x02,
-	0xa5, 0xd8, 0x91, 0x1e, 0x6f, 0xc6, 0x1e, 0x2a, 0xb3, 0xa9, 0xa1, 0x89, 0x34, 0xc6, 0x09, 0x09,
-	0x7d, 0x05, 0xb2, 0x7f, 0xe9, 0x9b, 0x63, 0x6b, 0x36, 0x8b, 0x8a, 0x55, 0x4a, 0xdc, 0x79, 0x57,
-	0x38, 0x8f, 0x2e, 0x7d, 0xd5, 0x9a, 0xcd, 0x44, 0xa1, 0x64, 0xcb, 0x4f, 0xc9, 0xe8, 0x09, 0x54,
-	0x3d, 0xfa, 0xc7, 0x25, 0xf5, 0x03, 0xa5, 0xcc, 0xfd, 0x64, 0xe1, 0x67, 0x5c, 0x90, 0x50, 0x4f,
-	0x22, 0x00, 0xfa, 0x02, 0x6a, 0x1e, 0xf5, 0x17, 0xae, 0xe3, 0x53, 0xa5, 0xc2, 0xc1, 0x37, 0x12,
-	0xe0, 0xd0, 0x40, 0x62, 0x08, 0xba, 0x05, 0xb5, 0xf1, 0x5b, 0x6b, 0xea, 0x30, 0x5a, 0xaa, 0x9c,
-	0x96, 0x2a, 0x97, 0x35, 0x1b, 0x3d, 0x87, 0x2d, 0x3f, 0xa0, 0x0b, 0xd3, 0x5e, 0x7a, 0x56, 0x30,
-	0x75, 0x1d, 0x5f, 0xa9, 0x75, 0x8a, 0x89, 0x13, 0x8f, 0x02, 0xba, 0xe8, 0x0b, 0x1b, 0x69, 0xfa,
-	0x09, 0xc9, 0xef, 0xfe, 0x43, 0x82, 0xad, 0xf4, 0xa1, 0x18, 0xd1, 0x63, 0xd7, 0xa6, 0x9c, 0xfc,
-	0x55, 0xa2, 0x55, 0xd7, 0xa6, 0x84, 0x03, 0x90, 0x02, 0xd5, 0x88, 0xa5, 0x42, 0x58, 0x91, 0x10,
-	0xd1, 0x6f, 0xa0, 0xba, 0xb0, 0x2e, 0x67, 0xae, 0x65, 0x2b, 0x45, 0x5e, 0x4a, 0x37, 0x97, 0xbf,
-	0xfd, 0x61, 0x08, 0xc2, 0x4e, 0xe0, 0x5d, 0x92, 0xc8, 0xa5, 0xf5, 0x1c, 0x1a, 0x49, 0x03, 0x92,
-	0xa1, 0xf8, 0x8e, 0x5e, 0x8a, 0x66, 0x60, 0x3f, 0xd1, 0x4d, 0x28, 0x9f, 0x5b, 0xb3, 0x65, 0x98,
-	0xb7, 0x41, 0x42, 0xe1, 0x79, 0xe1, 0x99, 0xd4, 0x5d, 0x42, 0x23, 0x79, 0xc1, 0xe8, 0x3e, 0x34,
-	0xc7, 0x4b, 0xcf, 0xa3, 0x4e, 0x60, 0xda, 0x74, 0x11, 0xbc, 0xe5, 0x51, 0x9a, 0xa4, 0x21, 0x94,
-	0x7d, 0xa6, 0x43, 0x9f, 0x43, 0x63, 0xe1, 0xb9, 0x63, 0xea, 0xfb, 0xa6, 0x63, 0xcd, 0xa3, 0xd3,
-	0x6c, 0x0a, 0x9d, 0x6e, 0xcd, 0x29, 0xba, 0x0b, 0x10, 0x36, 0xd5, 0xd4, 0xf9, 0xd6, 0xe5, 0x1d,
-	0x55, 0x22, 0x75, 0xae, 0xd1, 0x9c, 0x6f, 0xdd, 0xee, 0x5f, 0x25, 0x68, 0x24, 0x69, 0x46, 0xf7,
-	0x45, 0xb7, 0x86, 0x24, 0x6e, 0x27, 0x6e, 0x22, 0xd1, 0xa9, 0x77, 0x01, 0xfc, 0xc0, 0xf2, 0x02,
-	0x33, 0x98, 0x8a, 0xac, 0x45, 0x52, 0xe7, 0x1a, 0x63, 0x3a, 0xa7, 0xac, 0xf6, 0xd4, 0xbd, 0xf2,
-	0xb4, 0x45, 0xd2, 0x48, 0xde, 0x20, 0x7a, 0x08, 0x5b, 0x4b, 0x27, 0x98, 0xce, 0xae, 0x50, 0x25,
-	0x8e, 0x6a, 0x72, 0x6d, 0x0c, 0x93, 0xa1, 0x38, 0xf7, 0x27, 0xbc, 0x2b, 0xeb, 0x84, 0xfd, 0xec,
-	0xfe, 0xa9, 0x08, 0xf5, 0xb8, 0x2d, 0x39, 0x4f, 0xae, 0x13, 0x78, 0xd6, 0x38, 0x08, 0x39, 0x08,
-	0xd9, 0x6e, 0x44, 0x4a, 0x4e, 0xc2, 0x4f, 0x41, 0x8e, 0x41, 0xe7, 0xd4, 0xf3, 0x59, 0xb6, 0x90,
-	0xab, 0xed, 0x48, 0x7f, 0x1a, 0xaa, 0xd1, 0x1e, 0x54, 0xe6, 0x34, 0x78, 0xeb, 0xda, 0xbc, 0xe8,
-	0x3a, 0x11, 0x12, 0xfa, 0x1d, 0xc0, 0xc2, 0xf2, 0xac, 0x39, 0x0d, 0xa8, 0xe7, 0x2b, 0x25, 0xde,
-	0x1c, 0x9d, 0xec, 0x90, 0xec, 0x0f, 0x63, 0x48, 0xd8, 0x1a, 0x09, 0x1f, 0x74, 0x00, 0x10, 0x5c,
-	0xc4, 0xb3, 0x9d, 0x1d, 0xb3, 0x68, 0xb0, 0xeb, 0x41, 0xf4, 0x33, 0x35, 0x39, 0x95, 0xf4, 0xe4,
-	0x24, 0x4f, 0x6d, 0xd9, 0xb6, 0x27, 0x26, 0x2b, 0x3e, 0x75, 0xcf, 0xb6, 0x3d, 0xc6, 0x70, 0x0c,
-	0x9a, 0x3a, 0x36, 0xbd, 0x50, 0x6a, 0xbc, 0x87, 0x62, 0x57, 0x8d, 0x29, 0x5b, 0xbf, 0x85, 0xed,
-	0x4c, 0xd9, 0xd7, 0x6a, 0xdc, 0x7f, 0x4b, 0xec, 0x3a, 0xa2, 0x9a, 0xbf, 0x84, 0xfa, 0x77, 0xde,
-	0x34, 0xa0, 0xe6, 0xdc, 0x5a, 0x28, 0x12, 0x67, 0xa9, 0x9d, 0x3d, 0xe3, 0xfe, 0x1f, 0x18, 0xe2,
-	0xd8, 0x5a, 0x84, 0x1c, 0xd5, 0xbe, 0x13, 0x22, 0x7a, 0xc6, 0x36, 0x8b, 0x65, 0x73, 0xdf, 0x02,
-	0xf7, 0xbd, 0xbb, 0xe2, 0x4b, 0xa8, 0x65, 0xc7, 0xae, 0x55, 0x2f, 0x94, 0x5a, 0x5f, 0x42, 0x33,
-	0x15, 0xf4, 0x3a, 0x27, 0x60, 0x63, 0x9b, 0x8c, 0x7a, 0xad, 0xd3, 0xff, 0xad, 0x04, 0x70, 0xb5,
-	0xf6, 0xd6, 0x7e, 0x00, 0xf8, 0x5e, 0x2a, 0xfc, 0xaf, 0xbd, 0xb4, 0x07, 0x15, 0x8f, 0xfa, 0xcb,
-	0x59, 0xb8, 0xf9, 0x1b, 0x44, 0x48, 0xc9, 0x7d, 0x55, 0xca, 0xee, 0xab, 0x04, 0xdd, 0x65, 0x4e,
-	0xd9, 0xbd, 0x95, 0x65, 0xbc, 0x96, 0xef, 0x5f, 0x27, 0xf8, 0xae, 0x64, 0xee, 0x2a, 0x76, 0xce,
-	0x25, 0x1c, 0x3d, 0x85, 0x0a, 0x3d, 0xa7, 0x4e, 0xe0, 0x2b, 0x55, 0xee, 0xd8, 0x4a, 0x9d, 0x4a,
-	0x15, 0x0d, 0x86, 0x19, 0x84, 0x08, 0xe4, 0xea, 0xa8, 0xd6, 0xfe, 0xcf, 0x51, 0xad, 0xe7, 0x8f,
-	0x6a, 0x72, 0x3e, 0x20, 0x3d, 0x1f, 0xab, 0xad, 0xbf, 0x99, 0xd7, 0xfa, 0x9f, 0xac, 0x6d, 0x6c,
-	0xd8, 0xc9, 0x61, 0x8a, 0x39, 0x04, 0xee, 0x62, 0x3a, 0x16, 0x41, 0x42, 0x61, 0x95, 0xb7, 0x42,
-	0x0e, 0x6f, 0x08, 0x4a, 0xb6, 0x15, 0x58, 0xfc, 0xb3, 0x55, 0x27, 0xfc, 0x77, 0xf7, 0x07, 0x09,
-	0x76, 0xd8, 0x57, 0x2b, 0x4a, 0x72, 0xad, 0x9d, 0xf9, 0x08, 0x62, 0xc2, 0x4d, 0xb1, 0x11, 0xc3,
-	0xbc, 0x31, 0xb3, 0xc7, 0xe1, 0x66, 0x7c, 0x06, 0x25, 0xcb, 0x9b, 0xf8, 0xe2, 0x83, 0xf9, 0x20,
-	0x7a, 0xad, 0xac, 0xe6, 0xdd, 0xef, 0x79, 0x13, 0xb1, 0x17, 0xb9, 0x47, 0xeb, 0x57, 0x50, 0x8f,
-	0x55, 0xd7, 0xa2, 0xef, 0x14, 0x6a, 0xf1, 0xc8, 0xed, 0x41, 0xc5, 0x0f, 0xac, 0x60, 0xe9, 0x73,
-	0xd7, 0x32, 0x11, 0xd2, 0x47, 0x3e, 0xf2, 0x4a, 0xf2, 0x23, 0xcf, 0x22, 0x47, 0x62, 0xf7, 0x43,
-	0x01, 0xe4, 0xab, 0xa2, 0x45, 0x82, 0x9f, 0x25, 0xde, 0x3b, 0x12, 0xdf, 0xda, 0xd1, 0x57, 0x31,
-	0xe7, 0xb5, 0xf3, 0x22, 0x39, 0x90, 0xe1, 0x0e, 0x7b, 0x18, 0x31, 0x92, 0x09, 0xbc, 0x76, 0x2c,
-	0xbf, 0x4a, 0x8c, 0x65, 0x86, 0xd4, 0x6c, 0x88, 0xfc, 0xe1, 0x7c, 0x10, 0x0f, 0x67, 0xf8, 0x9d,
-	0x6a, 0x08, 0xf7, 0xd4, 0x38, 0x7e, 0xba, 0xe6, 0x3f, 0x85, 0xf2, 0x8f, 0xd1, 0xee, 0x4f, 0x7e,
-	0x28, 0x43, 0x23, 0xf9, 0xac, 0x46, 0x4d, 0xa8, 0x9f, 0xe8, 0x7d, 0xfc, 0x52, 0xd3, 0x71, 0x5f,
-	0xde, 0x40, 0x0d, 0xa8, 0x11, 0xfc, 0x4a, 0x1b, 0x19, 0x98, 0xc8, 0x12, 0xda, 0x02, 0x88, 0x24,
-	0xdc, 0x97, 0x0b, 0x68, 0x13, 0xaa, 0x43, 0x82, 0x87, 0x3d, 0x82, 0xe5, 0x22, 0xaa, 0x43, 0x99,
-	0xe0, 0x5e, 0xff, 0x4c, 0x2e, 0xa1, 0x1a, 0x94, 0x34, 0x5d, 0x33, 0xe4, 0x32, 0x02, 0xa8, 0x68,
-	0xfa, 0xe9, 0xe0, 0x35, 0x96, 0x2b, 0xcc, 0xdb, 0x78, 0x63, 0x12, 0xfc, 0xfb, 0x13, 0x3c, 0x32,
-	0xe4, 0x2a, 0xda, 0x86, 0x4d, 0x2e, 0x8f, 0x86, 0x03, 0x7d, 0x84, 0xe5, 0x1a, 0xda, 0x85, 0x1b,
-	0xaf, 0xb0, 0x61, 0x8e, 0x8c, 0x9e, 0x81, 0x63, 0x5c, 0x1d, 0xed, 0x01, 0x4a, 0xaa, 0x05, 0x1c,
-	0x90, 0x02, 0x37, 0x99, 0xfe, 0xc5, 0x99, 0x81, 0xd5, 0x41, 0xff, 0xca, 0x63, 0x13, 0xdd, 0x82,
-	0xdd, 0x8c, 0x45, 0x38, 0x35, 0x98, 0x49, 0xed, 0x1d, 0x1d, 0x99, 0xea, 0x40, 0x37, 0x48, 0x4f,
-	0x35, 0x62, 0xaf, 0x26, 0x6a, 0xc1, 0x5e, 0xd6, 0x24, 0xdc, 0xb6, 0x18, 0x2d, 0xea, 0xe0, 0x78,
-	0x78, 0x84, 0x0d, 0xdc, 0x97, 0xb7, 0xd9, 0x59, 0x31, 0x21, 0x03, 0x22, 0xcb, 0xa8, 0x0d, 0x2d,
-	0x95, 0x60, 0x56, 0xda, 0xeb, 0x53, 0x53, 0x33, 0x30, 0xe9, 0x19, 0x03, 0x12, 0x47, 0xbd, 0x81,
-	0xee, 0xc1, 0xed, 0x5c, 0xbb, 0x08, 0x8d, 0x38, 0x60, 0xa0, 0x8f, 0x4e, 0x8e, 0xf3, 0x23, 0xec,
-	0xa0, 0x0e, 0xdc, 0xc9, 0x07, 0x88, 0x10, 0x37, 0xd1, 0x7d, 0xb8, 0x17, 0xe5, 0xc0, 0x67, 0xe6,
-	0xa1, 0x36, 0x32, 0x06, 0xe4, 0x8c, 0x23, 0xe3, 0x30, 0xbb, 0x6b, 0x40, 0x21, 0x46, 0x44, 0xda,
-	0x43, 0x0f, 0xa0, 0x13, 0xe7, 0x5a, 0x17, 0xea, 0x33, 0xf4, 0x10, 0x3e, 0xff, 0x08, 0x4a, 0x04,
-	0x53, 0x18, 0x35, 0xfc, 0xe2, 0xb0, 0xde, 0xc7, 0xc4, 0xec, 0xf5, 0xfb, 0x04, 0x8f, 0x46, 0x71,
-	0x98, 0x5b, 0xec, 0xe4, 0xb9, 0x76, 0x11, 0xa0, 0x85, 0x6e, 0xc3, 0x67, 0xfc, 0x1e, 0x7b, 0x86,
-	0x7a, 0x98, 0x69, 0x8b, 0xdb, 0xe8, 0x0e, 0x28, 0xab, 0x46, 0xe1, 0x7a, 0xe7, 0xc9, 0x5f, 0x2a,
-	0x50, 0x8b, 0x5e, 0xe0, 0xac, 0x10, 0x72, 0xa2, 0x1b, 0xda, 0x31, 0x36, 0x45, 0xbf, 0x9a, 0x89,
-	0x4e, 0xdc, 0x60, 0x0c, 0x47, 0xf6, 0x57, 0x64, 0xa8, 0xf2, 0x8a, 0x92, 0x08, 0x09, 0x75, 0xa1,
-	0x8d, 0xf5, 0x57, 0x9a, 0x2e, 0x00, 0x04, 0xab, 0x58, 0x3b, 0x4d, 0x45, 0x29, 0xa0, 0x47, 0x70,
-	0x5f, 0x60, 0x46, 0xea, 0x21, 0xee, 0x9f, 0x1c, 0x71, 0x36, 0x56, 0x80, 0x45, 0x46, 0xf2, 0x0a,
-	0x30, 0x9b, 0xb2, 0xc4, 0xee, 0x2b, 0x4e, 0x39, 0x38, 0x19, 0xe6, 0x85, 0x2a, 0xb3, 0xca, 0x53,
-	0xa0, 0x6c, 0x98, 0x0a, 0xbb, 0x2b, 0x81, 0x18, 0x92, 0x81, 0x1a, 0x12, 0xbc, 0x12, 0xa8, 0x9a,
-	0x38, 0x60, 0x04, 0xcb, 0x86, 0xaa, 0xa1, 0x9f, 0x40, 0xf7, 0x63, 0xa1, 0x04, 0xf7, 0x75, 0x56,
-	0xf9, 0xa8, 0xa7, 0xf7, 0x5f, 0x0c, 0xde, 0xac, 0x65, 0x0b, 0x58, 0xe5, 0x29, 0x50, 0x36, 0xdd,
-	0x66, 0x12, 0xa1, 0x1e, 0xf6, 0xf4, 0x15, 0x44, 0x83, 0x15, 0x14, 0x21, 0x0e, 0x7b, 0x7a, 0x7f,
-	0x0d, 0xe1, 0x4d, 0xd6, 0x47, 0x59, 0x1c, 0x7e, 0x83, 0xd5, 0x13, 0x83, 0x8d, 0x76, 0xc2, 0xc8,
-	0x33, 0xa8, 0x87, 0x3d, 0x4d, 0xe7, 0xc7, 0x91, 0xb7, 0xf3, 0xab, 0x4c, 0x20, 0x64, 0xd6, 0x86,
-	0x29, 0x77, 0xc1, 0x10, 0xb7, 0xde, 0x60, 0x57, 0x9d, 0xea, 0xac, 0x3c, 0xc2, 0x10, 0x23, 0x3f,
-	0x46, 0x61, 0xc3, 0xd4, 0x07, 0x86, 0xf6, 0xf2, 0x2c, 0x85, 0xd9, 0x61, 0xdd, 0x15, 0x61, 0x72,
-	0xcf, 0x1a, 0x2f, 0x83, 0x44, 0xb3, 0x87, 0xc0, 0x94, 0x7d, 0xf7, 0x49, 0xe7, 0x6a, 0xe3, 0xb3,
-	0x77, 0x34, 0xaa, 0x40, 0x61, 0xf0, 0x5a, 0xde, 0x60, 0x4b, 0xfb, 0x65, 0x4f, 0x3b, 0x92, 0xa5,
-	0xa7, 0x5f, 0xc3, 0x66, 0x84, 0x20, 0x8b, 0x31, 0x7a, 0x1d, 0x3d, 0xbc, 0x98, 0xc3, 0x7c, 0xbe,
-	0x74, 0xa6, 0x63, 0x2b, 0xa0, 0x68, 0x2f, 0xf3, 0x28, 0x17, 0xff, 0xe7, 0xb7, 0xd6, 0xe8, 0xbb,
-	0x1b, 0x8f, 0xa5, 0x9f, 0x4b, 0x2f, 0x06, 0x7f, 0x7f, 0xdf, 0x96, 0xbe, 0x7f, 0xdf, 0x96, 0xfe,
-	0xf5, 0xbe, 0x2d, 0xfd, 0xf9, 0x43, 0x7b, 0xe3, 0xfb, 0x0f, 0xed, 0x8d, 0x7f, 0x7e, 0x68, 0x6f,
-	0x7c, 0xfd, 0x4b, 0xfe, 0x10, 0x9d, 0x5b, 0xef, 0xa8, 0xb7, 0xef, 0x7a, 0x93, 0x83, 0x2b, 0xf1,
-	0xe0, 0x7c, 0xfe, 0x05, 0x75, 0x26, 0x53, 0x87, 0x1e, 0x9c, 0xcf, 0xcd, 0xf9, 0xc4, 0x3b, 0x58,
-	0x7c, 0x73, 0xc0, 0x93, 0x4c, 0xdc, 0x6f, 0x2a, 0xfc, 0xc7, 0x2f, 0xfe, 0x1b, 0x00, 0x00, 0xff,
-	0xff, 0x7a, 0x9c, 0xb8, 0x3b, 0x7b, 0x12, 0x00, 0x00,
+	// 1733 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0x5f, 0x6f, 0xdb, 0xc8,
+	0x11, 0x37, 0xf5, 0x5f, 0x63, 0xda, 0x66, 0xd6, 0xb1, 0x8f, 0x51, 0x12, 0x45, 0xa7, 0x24, 0x8d,
+	0x9b, 0xe2, 0xec, 0x22, 0x45, 0xd1, 0x34, 0xd7, 0xe2, 0xaa, 0x50, 0x9b, 0x98, 0x88, 0x4d, 0xa9,
+	0x2b, 0xda, 0x8d, 0xef, 0x85, 0xe0, 0x89, 0x7b, 0xb6, 0x10, 0x89, 0x54, 0x49, 0xca, 0x67, 0x7f,
+	0x8b, 0xbe, 0xf5, 0x6b, 0xf4, 0x63, 0xf4, 0xa5, 0xc0, 0x3d, 0x1d, 0x5a, 0xf4, 0xa5, 0x48, 0x3e,
+	0x46, 0xfb, 0x50, 0xec, 0x72, 0x49, 0x93, 0x14, 0x9d, 0xab, 0x1f, 0x8a, 0x3c, 0x89, 0x33, 0xf3,
+	0x9b, 0xd9, 0xd9, 0xdf, 0xce, 0xcc, 0x2e, 0x04, 0xdb, 0x8e, 0x37, 0x7e, 0x47, 0xfd, 0xf3, 0x99,
+	0x35, 0xa3, 0x41, 0x60, 0x9f, 0xd2, 0xdd, 0xb9, 0xef, 0x85, 0x1e, 0xaa, 0xf2, 0x9f, 0xee, 0x7f,
+	0x4a, 0xb0, 0xd1, 0xe7, 0x88, 0xe3, 0xc3, 0xc3, 0x08, 0x80, 0x36, 0xa1, 0x1a, 0x5e, 0x58, 0x13,
+	0x47, 0x95, 0x3a, 0xd2, 0x4e, 0x93, 0x54, 0xc2, 0x0b, 0xdd, 0x41, 0x4f, 0xa0, 0x12, 0x5e, 0xce,
+	0xa9, 0x5a, 0xea, 0x48, 0x3b, 0xeb, 0xcf, 0x36, 0xa3, 0x28, 0xbb, 0xb1, 0xab, 0x79, 0x39, 0xa7,
+	0x84, 0x03, 0xd0, 0x73, 0x58, 0x1b, 0xfb, 0x5e, 0x10, 0x58, 0x63, 0xcf, 0x0d, 0xe9, 0x45, 0xa8,
+	0x96, 0x3b, 0xd2, 0xce, 0x6a, 0xe2, 0xa1, 0x31, 0x9b, 0x16, 0x99, 0x88, 0x3c, 0x4e, 0x49, 0xe8,
+	0x2b, 0x50, 0x82, 0xcb, 0xc0, 0x1a, 0xdb, 0xd3, 0x69, 0x9c, 0xac, 0x5a, 0xe1, 0xce, 0x5b, 0xc2,
+	0x79, 0x74, 0x19, 0x68, 0xf6, 0x74, 0x2a, 0x12, 0x25, 0xeb, 0x41, 0x46, 0x46, 0x4f, 0xa1, 0xee,
+	0xd3, 0x3f, 0x2e, 0x68, 0x10, 0xaa, 0x55, 0xee, 0xa7, 0x08, 0x3f, 0xf3, 0x82, 0x44, 0x7a, 0x12,
+	0x03, 0xd0, 0x17, 0xd0, 0xf0, 0x69, 0x30, 0xf7, 0xdc, 0x80, 0xaa, 0x35, 0x0e, 0xbe, 0x95, 0x02,
+	0x47, 0x06, 0x92, 0x40, 0xd0, 0x1d, 0x68, 0x8c, 0xcf, 0xec, 0x89, 0xcb, 0x68, 0xa9, 0x73, 0x5a,
+	0xea, 0x5c, 0xd6, 0x1d, 0xf4, 0x02, 0xd6, 0x83, 0x90, 0xce, 0x2d, 0x67, 0xe1, 0xdb, 0xe1, 0xc4,
+	0x73, 0x03, 0xb5, 0xd1, 0x29, 0xa7, 0x76, 0x3c, 0x0a, 0xe9, 0xbc, 0x2f, 0x6c, 0x64, 0x2d, 0x48,
+	0x49, 0x41, 0xf7, 0x6f, 0x12, 0xac, 0x67, 0x37, 0xc5, 0x88, 0x1e, 0x7b, 0x0e, 0x55, 0xa5, 0x42,
+	0xa2, 0x35, 0xcf, 0xa1, 0x84, 0x03, 0x90, 0x0a, 0xf5, 0x98, 0xa5, 0x52, 0x94, 0x91, 0x10, 0xd1,
+	0x6f, 0xa0, 0x3e, 0xb7, 0x2f, 0xa7, 0x9e, 0xed, 0xa8, 0x65, 0x9e, 0x4a, 0xb7, 0x90, 0xbf, 0xdd,
+	0x61, 0x04, 0xc2, 0x6e, 0xe8, 0x5f, 0x92, 0xd8, 0xa5, 0xf5, 0x02, 0xe4, 0xb4, 0x01, 0x29, 0x50,
+	0x7e, 0x47, 0x2f, 0x45, 0x31, 0xb0, 0x4f, 0x74, 0x1b, 0xaa, 0xe7, 0xf6, 0x74, 0x11, 0xad, 0x2b,
+	0x93, 0x48, 0x78, 0x51, 0x7a, 0x2e, 0x75, 0x17, 0x20, 0xa7, 0x0f, 0x18, 0x3d, 0x84, 0xb5, 0xf1,
+	0xc2, 0xf7, 0xa9, 0x1b, 0x5a, 0x0e, 0x9d, 0x87, 0x67, 0x3c, 0xca, 0x1a, 0x91, 0x85, 0xb2, 0xcf,
+	0x74, 0xe8, 0x73, 0x90, 0xe7, 0xbe, 0x37, 0xa6, 0x41, 0x60, 0xb9, 0xf6, 0x2c, 0xde, 0xcd, 0xaa,
+	0xd0, 0x19, 0xf6, 0x8c, 0xa2, 0xfb, 0x00, 0x51, 0x51, 0x4d, 0xdc, 0x6f, 0x3d, 0x5e, 0x51, 0x15,
+	0xd2, 0xe4, 0x1a, 0xdd, 0xfd, 0xd6, 0xeb, 0xfe, 0x45, 0x02, 0x39, 0x4d, 0x33, 0x7a, 0x28, 0xaa,
+	0x35, 0x22, 0x71, 0x23, 0x75, 0x12, 0xa9, 0x4a, 0xbd, 0x0f, 0x10, 0x84, 0xb6, 0x1f, 0x5a, 0xe1,
+	0x44, 0xac, 0x5a, 0x26, 0x4d, 0xae, 0x31, 0x27, 0x33, 0xca, 0x72, 0xcf, 0x9c, 0x2b, 0x5f, 0xb6,
+	0x4c, 0xe4, 0xf4, 0x09, 0xa2, 0xc7, 0xb0, 0xbe, 0x70, 0xc3, 0xc9, 0xf4, 0x0a, 0x55, 0xe1, 0xa8,
+	0x35, 0xae, 0x4d, 0x60, 0x0a, 0x94, 0x67, 0xc1, 0x29, 0xaf, 0xca, 0x26, 0x61, 0x9f, 0xdd, 0x7f,
+	0x96, 0xa1, 0x99, 0x94, 0x25, 0xe7, 0xc9, 0x73, 0x43, 0xdf, 0x1e, 0x87, 0x11, 0x07, 0x11, 0xdb,
+	0x72, 0xac, 0xe4, 0x24, 0xfc, 0x14, 0x94, 0x04, 0x74, 0x4e, 0xfd, 0x80, 0xad, 0x16, 0x71, 0xb5,
+	0x11, 0xeb, 0x8f, 0x23, 0x35, 0xda, 0x86, 0xda, 0x8c, 0x86, 0x67, 0x9e, 0xc3, 0x93, 0x6e, 0x12,
+	0x21, 0xa1, 0xdf, 0x01, 0xcc, 0x6d, 0xdf, 0x9e, 0xd1, 0x90, 0xfa, 0x81, 0x5a, 0xe1, 0xc5, 0xd1,
+	0xc9, 0x37, 0xc9, 0xee, 0x30, 0x81, 0x44, 0xa5, 0x91, 0xf2, 0x41, 0x7b, 0x00, 0xe1, 0x45, 0xd2,
+	0xdb, 0xf9, 0x36, 0x8b, 0x1b, 0xbb, 0x19, 0xc6, 0x9f, 0x99, 0xce, 0xa9, 0x65, 0x3b, 0x27, 0xbd,
+	0x6b, 0xdb, 0x71, 0x7c, 0xb5, 0x9e, 0xdd, 0x75, 0xcf, 0x71, 0x7c, 0xc6, 0x70, 0x02, 0x9a, 0xb8,
+	0x0e, 0xbd, 0x50, 0x1b, 0xbc, 0x86, 0x12, 0x57, 0x9d, 0x29, 0xa3, 0x58, 0xb3, 0xf9, 0x22, 0xa4,
+	0xd6, 0x74, 0x32, 0x9b, 0x84, 0x6a, 0x93, 0x17, 0x89, 0x2c, 0x94, 0x07, 0x4c, 0x87, 0x76, 0x40,
+	0x39, 0xb3, 0xc5, 0x84, 0x89, 0xb7, 0x00, 0x1d, 0x69, 0xa7, 0x41, 0xd6, 0xcf, 0x6c, 0xde, 0x1a,
+	0x22, 0xeb, 0xd6, 0x6f, 0x61, 0x23, 0xc7, 0xc2, 0x8d, 0xfa, 0xe0, 0xdf, 0x12, 0x3b, 0xdd, 0x98,
+	0x82, 0x2f, 0xa1, 0xf9, 0x9d, 0x3f, 0x09, 0xa9, 0x35, 0xb3, 0xe7, 0xaa, 0xc4, 0x49, 0x6f, 0xe7,
+	0x29, 0xdb, 0xfd, 0x03, 0x43, 0x1c, 0xda, 0xf3, 0x88, 0xf2, 0xc6, 0x77, 0x42, 0x44, 0xcf, 0xd9,
+	0xa0, 0xb2, 0x1d, 0xee, 0x5b, 0xe2, 0xbe, 0xf7, 0x97, 0x7c, 0x09, 0xb5, 0x9d, 0xc4, 0xb5, 0xee,
+	0x47, 0x52, 0xeb, 0x4b, 0x58, 0xcb, 0x04, 0xbd, 0xc9, 0x0e, 0xd8, 0x14, 0x48, 0x47, 0xbd, 0xd1,
+	0xee, 0xff, 0x51, 0x01, 0xb8, 0x9a, 0xa2, 0xd7, 0xde, 0x27, 0x7c, 0xcc, 0x95, 0x7e, 0x6c, 0xcc,
+	0x6d, 0x43, 0xcd, 0xa7, 0xc1, 0x62, 0x1a, 0x5d, 0x24, 0x32, 0x11, 0x52, 0x7a, 0xfc, 0x55, 0xf2,
+	0xe3, 0x2f, 0x45, 0x77, 0x95, 0x53, 0xf6, 0x60, 0x69, 0xb6, 0x5f, 0xcb, 0xf7, 0xaf, 0x53, 0x7c,
+	0xd7, 0x72, 0x67, 0x95, 0x38, 0x17, 0x12, 0x8e, 0x9e, 0x41, 0x8d, 0x9e, 0x53, 0x37, 0x0c, 0xd4,
+	0x3a, 0x77, 0x6c, 0x65, 0x76, 0xa5, 0x89, 0x7a, 0xc5, 0x0c, 0x42, 0x04, 0x72, 0xb9, 0xf3, 0x1b,
+	0xff, 0x63, 0xe7, 0x37, 0x8b, 0x3b, 0x3f, 0xdd, 0x6e, 0x90, 0x6d, 0xb7, 0xe5, 0x4e, 0x5a, 0xfd,
+	0x91, 0x4e, 0x5a, 0xb8, 0x93, 0x30, 0x50, 0xe5, 0x4c, 0x27, 0x1d, 0x31, 0xdd, 0xa7, 0xab, 0x2d,
+	0x07, 0x36, 0x0b, 0xe8, 0x64, 0x0e, 0xa1, 0x37, 0x9f, 0x8c, 0x45, 0x90, 0x48, 0x58, 0x26, 0xb7,
+	0x54, 0x40, 0x2e, 0x82, 0x8a, 0x63, 0x87, 0x36, 0xbf, 0x2a, 0x9b, 0x84, 0x7f, 0x77, 0x7f, 0x90,
+	0x60, 0x33, 0x1e, 0x07, 0x0c, 0x78, 0xa3, 0x39, 0xfd, 0x04, 0x92, 0x53, 0xb1, 0xc4, 0x14, 0x8e,
+	0xd6, 0x4d, 0xe8, 0x3f, 0xe4, 0x5a, 0xf4, 0x1c, 0x2a, 0xb6, 0x7f, 0x1a, 0x88, 0x4b, 0xfa, 0x51,
+	0xfc, 0x42, 0x5a, 0x5e, 0x77, 0xb7, 0xe7, 0x9f, 0x8a, 0x59, 0xcc, 0x3d, 0x5a, 0xbf, 0x82, 0x66,
+	0xa2, 0xba, 0x11, 0x7d, 0xc7, 0xd0, 0x48, 0xfa, 0x72, 0x1b, 0x6a, 0x41, 0x68, 0x87, 0x8b, 0x80,
+	0xbb, 0x56, 0x89, 0x90, 0x3e, 0xf2, 0xb0, 0x50, 0xd3, 0x0f, 0x0b, 0x16, 0x39, 0x16, 0xbb, 0x1f,
+	0x4a, 0xa0, 0x5c, 0x25, 0x2d, 0x16, 0xf8, 0x59, 0xea, 0x8d, 0x25, 0xf1, 0x9b, 0x22, 0xbe, 0x89,
+	0x0b, 0x5e, 0x58, 0x2f, 0xd3, 0x5d, 0x1b, 0x0d, 0xba, 0xc7, 0x31, 0x23, 0xb9, 0xc0, 0xd7, 0xf6,
+	0xee, 0x57, 0xa9, 0xde, 0xcd, 0x91, 0x9a, 0x0f, 0x51, 0xdc, 0xc1, 0x8f, 0x92, 0x0e, 0x8e, 0xee,
+	0x46, 0x59, 0xb8, 0x67, 0x7a, 0xf6, 0xd3, 0x15, 0xff, 0x31, 0x54, 0xff, 0x1f, 0xe5, 0xfe, 0xf4,
+	0x87, 0x2a, 0xc8, 0xe9, 0xa7, 0x3c, 0x5a, 0x83, 0xe6, 0x91, 0xd1, 0xc7, 0xaf, 0x74, 0x03, 0xf7,
+	0x95, 0x15, 0x24, 0x43, 0x83, 0xe0, 0xd7, 0xfa, 0xc8, 0xc4, 0x44, 0x91, 0xd0, 0x3a, 0x40, 0x2c,
+	0xe1, 0xbe, 0x52, 0x42, 0xab, 0x50, 0x1f, 0x12, 0x3c, 0xec, 0x11, 0xac, 0x94, 0x51, 0x13, 0xaa,
+	0x04, 0xf7, 0xfa, 0x27, 0x4a, 0x05, 0x35, 0xa0, 0xa2, 0x1b, 0xba, 0xa9, 0x54, 0x11, 0x40, 0x4d,
+	0x37, 0x8e, 0x07, 0x6f, 0xb0, 0x52, 0x63, 0xde, 0xe6, 0x5b, 0x8b, 0xe0, 0xdf, 0x1f, 0xe1, 0x91,
+	0xa9, 0xd4, 0xd1, 0x06, 0xac, 0x72, 0x79, 0x34, 0x1c, 0x18, 0x23, 0xac, 0x34, 0xd0, 0x16, 0xdc,
+	0x7a, 0x8d, 0x4d, 0x6b, 0x64, 0xf6, 0x4c, 0x9c, 0xe0, 0x9a, 0x68, 0x1b, 0x50, 0x5a, 0x2d, 0xe0,
+	0x80, 0x54, 0xb8, 0xcd, 0xf4, 0x2f, 0x4f, 0x4c, 0xac, 0x0d, 0xfa, 0x57, 0x1e, 0xab, 0xe8, 0x0e,
+	0x6c, 0xe5, 0x2c, 0xc2, 0x49, 0x66, 0x26, 0xad, 0x77, 0x70, 0x60, 0x69, 0x03, 0xc3, 0x24, 0x3d,
+	0xcd, 0x4c, 0xbc, 0xd6, 0x50, 0x0b, 0xb6, 0xf3, 0x26, 0xe1, 0xb6, 0xce, 0x68, 0xd1, 0x06, 0x87,
+	0xc3, 0x03, 0x6c, 0xe2, 0xbe, 0xb2, 0xc1, 0xf6, 0x8a, 0x09, 0x19, 0x10, 0x45, 0x41, 0x6d, 0x68,
+	0x69, 0x04, 0xb3, 0xd4, 0xde, 0x1c, 0x5b, 0xba, 0x89, 0x49, 0xcf, 0x1c, 0x90, 0x24, 0xea, 0x2d,
+	0xf4, 0x00, 0xee, 0x16, 0xda, 0x45, 0x68, 0xc4, 0x01, 0x03, 0x63, 0x74, 0x74, 0x58, 0x1c, 0x61,
+	0x13, 0x75, 0xe0, 0x5e, 0x31, 0x40, 0x84, 0xb8, 0x8d, 0x1e, 0xc2, 0x83, 0x78, 0x0d, 0x7c, 0x62,
+	0xed, 0xeb, 0x23, 0x73, 0x40, 0x4e, 0x38, 0x32, 0x09, 0xb3, 0x75, 0x0d, 0xc8, 0xc4, 0xa9, 0x48,
+	0xdb, 0xe8, 0x11, 0x74, 0x92, 0xb5, 0xae, 0x0b, 0xf5, 0x19, 0x7a, 0x0c, 0x9f, 0x7f, 0x04, 0x25,
+	0x82, 0xa9, 0x8c, 0x1a, 0x7e, 0x70, 0xd8, 0xe8, 0x63, 0x62, 0xf5, 0xfa, 0x7d, 0x82, 0x47, 0xa3,
+	0x24, 0xcc, 0x1d, 0xb6, 0xf3, 0x42, 0xbb, 0x08, 0xd0, 0x42, 0x77, 0xe1, 0x33, 0x7e, 0x8e, 0x3d,
+	0x53, 0xdb, 0xcf, 0x95, 0xc5, 0x5d, 0x74, 0x0f, 0xd4, 0x65, 0xa3, 0x70, 0xbd, 0xf7, 0xf4, 0xcf,
+	0x35, 0x68, 0xc4, 0xaf, 0x7e, 0x96, 0x08, 0x39, 0x32, 0x4c, 0xfd, 0x10, 0x5b, 0xa2, 0x5e, 0xad,
+	0x54, 0x25, 0xae, 0x30, 0x86, 0x63, 0xfb, 0x6b, 0x32, 0xd4, 0x78, 0x46, 0x69, 0x84, 0x84, 0xba,
+	0xd0, 0xc6, 0xc6, 0x6b, 0xdd, 0x10, 0x00, 0x82, 0x35, 0xac, 0x1f, 0x67, 0xa2, 0x94, 0xd0, 0x13,
+	0x78, 0x28, 0x30, 0x23, 0x6d, 0x1f, 0xf7, 0x8f, 0x0e, 0x30, 0x29, 0x02, 0x96, 0x19, 0xc9, 0x4b,
+	0xc0, 0xfc, 0x92, 0x15, 0x76, 0x5e, 0xc9, 0x92, 0x83, 0xa3, 0x61, 0x51, 0xa8, 0x2a, 0xcb, 0x3c,
+	0x03, 0xca, 0x87, 0xa9, 0xb1, 0xb3, 0x12, 0x88, 0x21, 0x19, 0x68, 0x11, 0xc1, 0x4b, 0x81, 0xea,
+	0xa9, 0x0d, 0xc6, 0xb0, 0x7c, 0xa8, 0x06, 0xfa, 0x09, 0x74, 0x3f, 0x16, 0x4a, 0x70, 0xdf, 0x64,
+	0x99, 0x8f, 0x7a, 0x46, 0xff, 0xe5, 0xe0, 0xed, 0xb5, 0x6c, 0x01, 0xcb, 0x3c, 0x03, 0xca, 0x2f,
+	0xb7, 0x9a, 0x46, 0x68, 0xfb, 0x3d, 0x63, 0x09, 0x21, 0xb3, 0x84, 0x62, 0xc4, 0x7e, 0xcf, 0xe8,
+	0x5f, 0x43, 0xf8, 0x1a, 0xab, 0xa3, 0x3c, 0x0e, 0xbf, 0xc5, 0xda, 0x91, 0xc9, 0x5a, 0x3b, 0x65,
+	0xe4, 0x2b, 0x68, 0xfb, 0x3d, 0xdd, 0xe0, 0xdb, 0x51, 0x36, 0x8a, 0xb3, 0x4c, 0x21, 0x14, 0x56,
+	0x86, 0x19, 0x77, 0xc1, 0x10, 0xb7, 0xde, 0x62, 0x47, 0x9d, 0xa9, 0xac, 0x22, 0xc2, 0x10, 0x23,
+	0x3f, 0x41, 0x61, 0xd3, 0x32, 0x06, 0xa6, 0xfe, 0xea, 0x24, 0x83, 0xd9, 0x64, 0xd5, 0x15, 0x63,
+	0x0a, 0xf7, 0x9a, 0x0c, 0x83, 0x54, 0xb1, 0x47, 0xc0, 0x8c, 0x7d, 0xeb, 0x69, 0x07, 0xe4, 0xf4,
+	0x63, 0x1b, 0xd5, 0xa0, 0x34, 0x78, 0xa3, 0xac, 0xb0, 0xa1, 0xfd, 0xaa, 0xa7, 0x1f, 0x28, 0xd2,
+	0xb3, 0xaf, 0x61, 0x35, 0x46, 0x90, 0xf9, 0x18, 0xbd, 0x81, 0xcd, 0x2b, 0x87, 0xd9, 0x6c, 0xe1,
+	0x4e, 0xc6, 0x76, 0x48, 0xd1, 0x76, 0xee, 0xe5, 0x2e, 0xfe, 0x5b, 0x68, 0x5d, 0xa3, 0xef, 0xae,
+	0xec, 0x48, 0x3f, 0x97, 0x5e, 0x0e, 0xfe, 0xfa, 0xbe, 0x2d, 0x7d, 0xff, 0xbe, 0x2d, 0xfd, 0xeb,
+	0x7d, 0x5b, 0xfa, 0xd3, 0x87, 0xf6, 0xca, 0xf7, 0x1f, 0xda, 0x2b, 0x7f, 0xff, 0xd0, 0x5e, 0xf9,
+	0xfa, 0x97, 0xfc, 0xb5, 0x3a, 0xb3, 0xdf, 0x51, 0x7f, 0xd7, 0xf3, 0x4f, 0xf7, 0xae, 0xc4, 0xbd,
+	0xf3, 0xd9, 0x17, 0xd4, 0x3d, 0x9d, 0xb8, 0x74, 0x8f, 0xfd, 0x9b, 0x75, 0xea, 0xef, 0xcd, 0xbf,
+	0xd9, 0xe3, 0x8b, 0x9c, 0x7a, 0xdf, 0xd4, 0xf8, 0xc7, 0x2f, 0xfe, 0x3b, 0x00, 0x1c, 0x9c, 0x3e,
+	0x0d, 0xef, 0x12, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1676,6 +1706,21 @@ func (m *TxRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.HasCallContext {
+		i--
+		if m.HasCallContext {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.ComputeLimit != 0 {
+		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ComputeLimit))
+		i--
+		dAtA[i] = 0x48
+	}
 	if m.ContractIndex != 0 {
 		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ContractIndex))
 		i--
@@ -1837,6 +1882,11 @@ func (m *TxResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ComputeUnits != 0 {
+		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ComputeUnits))
+		i--
+		dAtA[i] = 0x60
+	}
 	if m.ContractIndex != 0 {
 		i = encodeVarintDockervmMessage(dAtA, i, uint64(m.ContractIndex))
 		i--
@@ -2401,6 +2451,12 @@ func (m *TxRequest) Size() (n int) {
 	if m.ContractIndex != 0 {
 		n += 1 + sovDockervmMessage(uint64(m.ContractIndex))
 	}
+	if m.ComputeLimit != 0 {
+		n += 1 + sovDockervmMessage(uint64(m.ComputeLimit))
+	}
+	if m.HasCallContext {
+		n += 2
+	}
 	return n
 }
 
@@ -2503,6 +2559,9 @@ func (m *TxResponse) Size() (n int) {
 	if m.ContractIndex != 0 {
 		n += 1 + sovDockervmMessage(uint64(m.ContractIndex))
 	}
+	if m.ComputeUnits != 0 {
+		n += 1 + sovDockervmMessage(uint64(m.ComputeUnits))
+	}
 	return n
 }
 
@@ -3840,6 +3899,45 @@ func (m *TxRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ComputeLimit", wireType)
+			}
+			m.ComputeLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ComputeLimit |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HasCallContext", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HasCallContext = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDockervmMessage(dAtA[iNdEx:])
@@ -4718,6 +4816,25 @@ func (m *TxResponse) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ComputeUnits", wireType)
+			}
+			m.ComputeUnits = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDockervmMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ComputeUnits |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDockervmMessage(dAtA[iNdEx:])